@@ -97,7 +97,7 @@ var askCmd = &cobra.Command{
 		// Try to load config for local LLM
 		cfg, cfgErr := config.Load(burrowDir)
 		if cfgErr == nil {
-			config.ResolveEnvVars(cfg)
+			resolveEnvVars(cfg)
 			if err := config.Validate(cfg); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: config issue: %v\n", err)
 			}
@@ -113,7 +113,7 @@ var askCmd = &cobra.Command{
 		// Find local LLM provider (spec: zero network requests for gd ask)
 		provider := findLocalProvider(cfg)
 		if provider != nil {
-			return askWithLLM(cmd, provider, ledger, contactStore, prof, query)
+			return askWithLLM(cmd, provider, ledger, contactStore, prof, query, renderStyle(cfg))
 		}
 
 		// Fallback to text search
@@ -144,7 +144,7 @@ func findLocalProvider(cfg *config.Config) synthesis.Provider {
 }
 
 // askWithLLM gathers context and queries a local LLM for a reasoned answer.
-func askWithLLM(cmd *cobra.Command, provider synthesis.Provider, ledger *bcontext.Ledger, contactStore *contacts.Store, prof *profile.Profile, query string) error {
+func askWithLLM(cmd *cobra.Command, provider synthesis.Provider, ledger *bcontext.Ledger, contactStore *contacts.Store, prof *profile.Profile, query, style string) error {
 	contextData, err := ledger.GatherContext(100_000)
 	if err != nil {
 		return fmt.Errorf("gathering context: %w", err)
@@ -175,7 +175,7 @@ func askWithLLM(cmd *cobra.Command, provider synthesis.Provider, ledger *bcontex
 		return fmt.Errorf("LLM error: %w", err)
 	}
 
-	rendered, err := render.RenderMarkdown(response, 80)
+	rendered, err := render.RenderMarkdownStyle(response, 80, style)
 	if err != nil {
 		// Fallback to plain text
 		fmt.Println(response)
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jcadam/burrow/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	configCmd.AddCommand(configEditCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage config.yaml",
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open config.yaml in your editor, validating before saving",
+	Long: "Opens a copy of config.yaml in your editor. On exit the copy is validated with environment " +
+		"variables resolved; invalid edits are never saved, and you can reopen the editor to fix them. " +
+		"The previous config is kept as config.yaml.bak.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		burrowDir, err := config.BurrowDir()
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(burrowDir, "config.yaml")
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("no config found at %s (run gd init or gd configure first)", path)
+		}
+		return editValidated(burrowDir, path, validateConfigFile, os.Stdin, os.Stdout)
+	},
+}
+
+// validateConfigFile checks an edited config.yaml the way commands load it:
+// parsed, with env var references resolved, then validated.
+func validateConfigFile(path string) error {
+	cfg, err := config.Load(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	resolveEnvVars(cfg)
+	return config.Validate(cfg)
+}
+
+// runEditor opens path in editor and waits for it to exit. Tests replace it.
+var runEditor = func(editor, path string) error {
+	c := exec.Command(editor, path)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
+
+// editValidated opens a copy of path in the user's editor and replaces path
+// only once validate accepts the copy. The copy lives in a private temporary
+// directory under burrowDir with the original file name, so credentials stay
+// inside the Burrow directory and validators that derive names from the file
+// see the right one. Invalid edits are reported and the editor can be
+// reopened; declining leaves path untouched. The previous contents are kept
+// in path+".bak".
+func editValidated(burrowDir, path string, validate func(path string) error, in io.Reader, out io.Writer) error {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+
+	tmpDir, err := os.MkdirTemp(burrowDir, ".edit-")
+	if err != nil {
+		return fmt.Errorf("creating edit directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	draft := filepath.Join(tmpDir, filepath.Base(path))
+	if err := os.WriteFile(draft, original, 0o600); err != nil {
+		return fmt.Errorf("writing edit copy: %w", err)
+	}
+
+	editor := findEditor(burrowDir)
+	scanner := bufio.NewScanner(in)
+	for {
+		if err := runEditor(editor, draft); err != nil {
+			return fmt.Errorf("running editor: %w", err)
+		}
+
+		edited, err := os.ReadFile(draft)
+		if err != nil {
+			return fmt.Errorf("reading edit copy: %w", err)
+		}
+		if bytes.Equal(edited, original) {
+			fmt.Fprintln(out, "No changes.")
+			return nil
+		}
+
+		verr := validate(draft)
+		if verr == nil {
+			if err := os.WriteFile(path+".bak", original, 0o644); err != nil {
+				return fmt.Errorf("writing backup: %w", err)
+			}
+			if err := os.WriteFile(path, edited, 0o644); err != nil {
+				return fmt.Errorf("saving %s: %w", filepath.Base(path), err)
+			}
+			fmt.Fprintf(out, "Saved %s (previous version in %s.bak)\n", path, filepath.Base(path))
+			return nil
+		}
+
+		fmt.Fprintf(out, "Invalid %s: %v\n", filepath.Base(path), verr)
+		fmt.Fprint(out, "Reopen editor? [Y/n] ")
+		answer := "n" // no input to answer with: don't loop
+		if scanner.Scan() {
+			answer = strings.TrimSpace(strings.ToLower(scanner.Text()))
+		}
+		if answer != "" && answer != "y" && answer != "yes" {
+			fmt.Fprintln(out, "Discarded edits; no changes saved.")
+			return nil
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// scriptEditor replaces runEditor with one that writes each of edits in turn
+// to the file being edited.
+func scriptEditor(t *testing.T, edits ...string) *int {
+	t.Helper()
+	calls := 0
+	orig := runEditor
+	runEditor = func(_, path string) error {
+		if calls < len(edits) {
+			if err := os.WriteFile(path, []byte(edits[calls]), 0o600); err != nil {
+				return err
+			}
+		}
+		calls++
+		return nil
+	}
+	t.Cleanup(func() { runEditor = orig })
+	return &calls
+}
+
+const validRoutine = "report:\n  title: Brief\nsources:\n  - service: news\n    tool: headlines\n"
+
+func writeRoutineFile(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "brief.yaml")
+	if err := os.WriteFile(path, []byte(validRoutine), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEditValidatedSavesWithBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := writeRoutineFile(t, dir)
+	edited := strings.Replace(validRoutine, "Brief", "Morning Brief", 1)
+	scriptEditor(t, edited)
+
+	var out bytes.Buffer
+	if err := editValidated(dir, path, routineFileValidator(dir), strings.NewReader(""), &out); err != nil {
+		t.Fatalf("editValidated: %v", err)
+	}
+
+	if got, _ := os.ReadFile(path); string(got) != edited {
+		t.Errorf("routine not saved, got:\n%s", got)
+	}
+	if got, _ := os.ReadFile(path + ".bak"); string(got) != validRoutine {
+		t.Errorf("backup = %q, want original", got)
+	}
+	if entries, _ := filepath.Glob(filepath.Join(dir, ".edit-*")); len(entries) != 0 {
+		t.Errorf("edit directory not cleaned up: %v", entries)
+	}
+}
+
+func TestEditValidatedReopensOnInvalid(t *testing.T) {
+	dir := t.TempDir()
+	path := writeRoutineFile(t, dir)
+	fixed := strings.Replace(validRoutine, "Brief", "Fixed", 1)
+	calls := scriptEditor(t, "report:\n  title: Broken\n", fixed)
+
+	var out bytes.Buffer
+	if err := editValidated(dir, path, routineFileValidator(dir), strings.NewReader("\n"), &out); err != nil {
+		t.Fatalf("editValidated: %v", err)
+	}
+
+	if *calls != 2 {
+		t.Errorf("editor opened %d times, want 2", *calls)
+	}
+	if !strings.Contains(out.String(), "Invalid brief.yaml: ") {
+		t.Errorf("expected validation error in output, got:\n%s", out.String())
+	}
+	if got, _ := os.ReadFile(path); string(got) != fixed {
+		t.Errorf("fixed routine not saved, got:\n%s", got)
+	}
+}
+
+func TestEditValidatedDiscardsInvalid(t *testing.T) {
+	dir := t.TempDir()
+	path := writeRoutineFile(t, dir)
+	scriptEditor(t, "sources: [")
+
+	var out bytes.Buffer
+	if err := editValidated(dir, path, routineFileValidator(dir), strings.NewReader("n\n"), &out); err != nil {
+		t.Fatalf("editValidated: %v", err)
+	}
+
+	if got, _ := os.ReadFile(path); string(got) != validRoutine {
+		t.Errorf("invalid edit was saved:\n%s", got)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Error("backup written for a discarded edit")
+	}
+	if !strings.Contains(out.String(), "Discarded edits") {
+		t.Errorf("expected discard message, got:\n%s", out.String())
+	}
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+
+	os.WriteFile(path, []byte("services:\n  - name: news\n    type: rest\n"), 0o644)
+	if err := validateConfigFile(path); err == nil || !strings.Contains(err.Error(), "missing endpoint") {
+		t.Errorf("expected missing endpoint error, got %v", err)
+	}
+
+	os.WriteFile(path, []byte("services:\n  - name: news\n    type: rest\n    endpoint: https://example.com\n"), 0o644)
+	if err := validateConfigFile(path); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+}
@@ -11,13 +11,16 @@ import (
 )
 
 func init() {
+	configureCmd.Flags().Bool("profile", false, "Build your profile through a guided interview")
+	configureCmd.Flags().Bool("auto-apply", false, "Apply profile, routine, and tool-mapping changes without confirmation; other config changes still ask")
+	configureCmd.Flags().BoolP("yes", "y", false, "Alias for --auto-apply")
 	rootCmd.AddCommand(configureCmd)
 }
 
 var configureCmd = &cobra.Command{
 	Use:   "configure",
 	Short: "Modify Burrow configuration",
-	Long:  "Interactively modify your Burrow configuration. Uses conversational mode if an LLM is available, otherwise falls back to a structured wizard.",
+	Long:  "Interactively modify your Burrow configuration. Uses conversational mode if an LLM is available, otherwise falls back to a structured wizard.\n\nWith --profile, the assistant interviews you (name, industry, competitors, interests, locations) and builds your profile step by step.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		burrowDir, err := config.BurrowDir()
 		if err != nil {
@@ -40,7 +43,7 @@ var configureCmd = &cobra.Command{
 		// Resolve env vars on a copy so we can construct providers,
 		// but keep the original cfg with ${ENV_VAR} references intact for saving.
 		resolvedCfg := cfg.DeepCopy()
-		config.ResolveEnvVars(resolvedCfg)
+		resolveEnvVars(resolvedCfg)
 
 		provider := configure.DetectProvider(resolvedCfg)
 		if provider == nil {
@@ -50,9 +53,21 @@ var configureCmd = &cobra.Command{
 		if provider != nil {
 			// Session uses the unresolved config so YAML output preserves ${ENV_VAR} references.
 			session := configure.NewSession(burrowDir, cfg, provider)
+			if interview, _ := cmd.Flags().GetBool("profile"); interview {
+				session.StartInterview()
+			}
+			autoApply, _ := cmd.Flags().GetBool("auto-apply")
+			yes, _ := cmd.Flags().GetBool("yes")
+			session.SetAutoApply(autoApply || yes)
+			session.SetRenderStyle(renderStyle(cfg))
+			session.SetAccessible(accessibleMode(cfg))
 			return configure.RunTUI(cmd.Context(), session)
 		}
 
+		if interview, _ := cmd.Flags().GetBool("profile"); interview {
+			return fmt.Errorf("the profile interview requires an LLM provider")
+		}
+
 		// Fallback to wizard — operates on unresolved config to preserve ${ENV_VAR} references.
 		wiz := configure.NewWizard(os.Stdin, os.Stdout)
 		if err := wiz.RunModify(cfg); err != nil {
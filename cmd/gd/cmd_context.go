@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -21,14 +22,20 @@ func init() {
 	contextCmd.AddCommand(contextStatsCmd)
 	contextCmd.AddCommand(contextClearCmd)
 	contextCmd.AddCommand(contextPruneCmd)
+	contextCmd.AddCommand(contextExportCmd)
+	contextCmd.AddCommand(contextImportCmd)
 
 	contextShowCmd.Flags().IntVarP(&contextShowLimit, "limit", "n", 20, "number of entries to show")
 	contextShowCmd.Flags().StringVar(&contextShowType, "type", "", "filter by type: report, result, session, contact, or note")
+	contextSearchCmd.Flags().StringVar(&contextSearchType, "type", "", "filter by type: report, result, session, contact, or note")
+	contextExportCmd.Flags().BoolVar(&contextExportResults, "include-results", false, "also export raw source results (can be large)")
 }
 
 var (
-	contextShowLimit int
-	contextShowType  string
+	contextShowLimit     int
+	contextShowType      string
+	contextSearchType    string
+	contextExportResults bool
 )
 
 var contextCmd = &cobra.Command{
@@ -43,19 +50,32 @@ var contextCmd = &cobra.Command{
 var contextSearchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search context entries",
-	Args:  cobra.MinimumNArgs(1),
+	Long: "Case-insensitive full-text search of the context ledger: indexed reports, source results, " +
+		"sessions, contacts, and notes. Unlike 'gd reports search', which reads report files, this " +
+		"searches what the ledger has accumulated for gd ask and drafting. No LLM is used.",
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		query := strings.Join(args, " ")
+		types, err := contextTypes(contextSearchType)
+		if err != nil {
+			return err
+		}
 
 		ledger, err := openLedger()
 		if err != nil {
 			return err
 		}
 
-		entries, err := ledger.Search(query)
+		found, err := ledger.Search(query)
 		if err != nil {
 			return fmt.Errorf("searching context: %w", err)
 		}
+		var entries []bcontext.Entry
+		for _, e := range found {
+			if slices.Contains(types, e.Type) {
+				entries = append(entries, e)
+			}
+		}
 
 		if len(entries) == 0 {
 			fmt.Printf("No results for %q\n", query)
@@ -65,7 +85,10 @@ var contextSearchCmd = &cobra.Command{
 		fmt.Printf("Found %d result(s) for %q:\n\n", len(entries), query)
 		for _, e := range entries {
 			ts := e.Timestamp.Format("2006-01-02 15:04")
-			fmt.Printf("  %s  [%s]  %s\n", ts, e.Type, e.Label)
+			fmt.Printf("  %s  [%-7s]  %s\n", ts, e.Type, e.Label)
+			if snippet := extractSnippet(e.Content, query, 80); snippet != "" {
+				fmt.Printf("    ...%s...\n", snippet)
+			}
 		}
 		return nil
 	},
@@ -80,14 +103,9 @@ var contextShowCmd = &cobra.Command{
 			return err
 		}
 
-		types := []string{bcontext.TypeReport, bcontext.TypeResult, bcontext.TypeSession, bcontext.TypeContact, bcontext.TypeNote}
-		if contextShowType != "" {
-			switch contextShowType {
-			case bcontext.TypeReport, bcontext.TypeResult, bcontext.TypeSession, bcontext.TypeContact, bcontext.TypeNote:
-				types = []string{contextShowType}
-			default:
-				return fmt.Errorf("unknown type %q (use report, result, session, contact, or note)", contextShowType)
-			}
+		types, err := contextTypes(contextShowType)
+		if err != nil {
+			return err
 		}
 
 		var all []bcontext.Entry
@@ -243,6 +261,86 @@ var contextPruneCmd = &cobra.Command{
 	},
 }
 
+var contextExportCmd = &cobra.Command{
+	Use:   "export <file>",
+	Short: "Export the context ledger to a portable archive (.tar.gz)",
+	Long: "Bundle reports, sessions, contacts, and notes from the context ledger into a gzipped tar archive " +
+		"of the ledger's markdown files, for backup or moving to another machine. " +
+		"Raw source results are included only with --include-results.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ledger, err := openLedger()
+		if err != nil {
+			return err
+		}
+
+		types := bcontext.ArchiveTypes
+		if contextExportResults {
+			types = append([]string{bcontext.TypeResult}, types...)
+		}
+
+		f, err := os.OpenFile(args[0], os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return fmt.Errorf("creating archive: %w", err)
+		}
+		n, err := ledger.Export(f, types)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(args[0])
+			return fmt.Errorf("exporting context: %w", err)
+		}
+
+		fmt.Printf("Exported %d entry(ies) to %s\n", n, args[0])
+		return nil
+	},
+}
+
+var contextImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Merge entries from a context archive into the ledger",
+	Long: "Merge an archive created by 'gd context export' into the local context ledger. " +
+		"Entries already present are skipped; entries whose filename collides with different content are kept under a new name.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ledger, err := openLedger()
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("opening archive: %w", err)
+		}
+		defer f.Close()
+
+		stats, err := ledger.Import(f)
+		if err != nil {
+			return fmt.Errorf("importing context (%d entry(ies) imported before the error): %w", stats.Imported, err)
+		}
+
+		fmt.Printf("Imported %d entry(ies), skipped %d already present", stats.Imported, stats.Skipped)
+		if stats.Renamed > 0 {
+			fmt.Printf(", %d renamed to avoid conflicts", stats.Renamed)
+		}
+		fmt.Println(".")
+		return nil
+	},
+}
+
+// contextTypes returns the entry types a --type filter selects: all of
+// them when the filter is empty.
+func contextTypes(filter string) ([]string, error) {
+	switch filter {
+	case "":
+		return []string{bcontext.TypeReport, bcontext.TypeResult, bcontext.TypeSession, bcontext.TypeContact, bcontext.TypeNote}, nil
+	case bcontext.TypeReport, bcontext.TypeResult, bcontext.TypeSession, bcontext.TypeContact, bcontext.TypeNote:
+		return []string{filter}, nil
+	}
+	return nil, fmt.Errorf("unknown type %q (use report, result, session, contact, or note)", filter)
+}
+
 // openLedger is a helper to open the context ledger from the standard location.
 func openLedger() (*bcontext.Ledger, error) {
 	burrowDir, err := config.BurrowDir()
@@ -0,0 +1,17 @@
+package main
+
+import "testing"
+
+func TestContextTypes(t *testing.T) {
+	all, err := contextTypes("")
+	if err != nil || len(all) != 5 {
+		t.Errorf("contextTypes(\"\") = %v, %v; want all five types", all, err)
+	}
+	one, err := contextTypes("note")
+	if err != nil || len(one) != 1 || one[0] != "note" {
+		t.Errorf("contextTypes(note) = %v, %v", one, err)
+	}
+	if _, err := contextTypes("reports"); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+}
@@ -3,9 +3,11 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
@@ -14,13 +16,20 @@ import (
 	"github.com/jcadam/burrow/pkg/pipeline"
 	"github.com/jcadam/burrow/pkg/profile"
 	"github.com/jcadam/burrow/pkg/scheduler"
+	"github.com/jcadam/burrow/pkg/values"
 	"github.com/spf13/cobra"
 )
 
-var daemonOnce bool
+var (
+	daemonOnce       bool
+	daemonMaxBackoff time.Duration
+	daemonCatchUp    bool
+)
 
 func init() {
 	daemonCmd.Flags().BoolVar(&daemonOnce, "once", false, "Evaluate schedules once and exit (for cron integration)")
+	daemonCmd.Flags().DurationVar(&daemonMaxBackoff, "max-backoff", time.Hour, "Maximum retry delay after consecutive routine failures")
+	daemonCmd.Flags().BoolVar(&daemonCatchUp, "catch-up", false, "Run missed runs immediately, overriding each routine's missed_run policy")
 	rootCmd.AddCommand(daemonCmd)
 }
 
@@ -28,7 +37,11 @@ var daemonCmd = &cobra.Command{
 	Use:   "daemon",
 	Short: "Run the routine scheduler",
 	Long: `Runs the scheduler in the foreground. Evaluates routine schedules
-every minute and executes due routines. Use --once for cron integration.
+every minute and executes due routines. Failed routines are retried with
+exponential backoff (1m, 2m, 4m, ... up to --max-backoff). Use --once for
+cron integration.
+A run whose time passed while the daemon was stopped or the machine asleep
+follows the routine's missed_run policy; --catch-up runs it regardless.
 Send SIGINT or SIGTERM to stop gracefully.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		burrowDir, err := config.BurrowDir()
@@ -41,7 +54,11 @@ Send SIGINT or SIGTERM to stop gracefully.`,
 
 		store := scheduler.NewFileStateStore(statePath)
 		loader := func() ([]*pipeline.Routine, error) {
-			return pipeline.LoadAllRoutines(routinesDir, os.Stderr)
+			routines, err := pipeline.LoadAllRoutines(routinesDir, os.Stderr)
+			if err != nil {
+				return nil, err
+			}
+			return routinesWithValidLLM(burrowDir, routines, os.Stderr), nil
 		}
 		runner := func(ctx context.Context, routine *pipeline.Routine) error {
 			return runRoutine(ctx, burrowDir, routine)
@@ -53,13 +70,16 @@ Send SIGINT or SIGTERM to stop gracefully.`,
 			Runner: runner,
 			Logger: os.Stderr,
 			Once:   daemonOnce,
+
+			BackoffMax: daemonMaxBackoff,
+			CatchUp:    daemonCatchUp,
 		})
 
 		// Print startup banner.
 		routines, _ := loader()
 		scheduled := 0
 		for _, r := range routines {
-			if r.Schedule != "" {
+			if r.Schedule != "" || len(r.After) > 0 {
 				scheduled++
 			}
 		}
@@ -69,13 +89,18 @@ Send SIGINT or SIGTERM to stop gracefully.`,
 			fmt.Fprintf(os.Stderr, "Burrow scheduler: monitoring %d routine(s) with schedules\n", scheduled)
 		}
 		for _, r := range routines {
-			if r.Schedule != "" {
-				tz := r.Timezone
-				if tz == "" {
-					tz = "local"
-				}
-				fmt.Fprintf(os.Stderr, "  %s — %s (%s)\n", r.Name, r.Schedule, tz)
+			if r.Schedule == "" && len(r.After) == 0 {
+				continue
+			}
+			tz := r.Timezone
+			if tz == "" {
+				tz = "local"
 			}
+			when := r.Schedule
+			if len(r.After) > 0 {
+				when = strings.TrimSpace(when + " after " + strings.Join(r.After, ", "))
+			}
+			fmt.Fprintf(os.Stderr, "  %s — %s (%s)\n", r.Name, when, tz)
 		}
 
 		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -90,6 +115,27 @@ Send SIGINT or SIGTERM to stop gracefully.`,
 	},
 }
 
+// routinesWithValidLLM drops routines whose llm: doesn't match the configured
+// providers (see pipeline.ValidateRoutineLLM), warning to w, so a
+// misconfigured routine is reported when loaded rather than failing each
+// scheduled run. If config.yaml can't be loaded, routines are returned as is
+// and each run reports the config error.
+func routinesWithValidLLM(burrowDir string, routines []*pipeline.Routine, w io.Writer) []*pipeline.Routine {
+	cfg, err := config.Load(burrowDir)
+	if err != nil {
+		return routines
+	}
+	valid := routines[:0]
+	for _, r := range routines {
+		if err := pipeline.ValidateRoutineLLM(r, cfg); err != nil {
+			fmt.Fprintf(w, "warning: skipping routine %s: %v\n", r.Name, err)
+			continue
+		}
+		valid = append(valid, r)
+	}
+	return valid
+}
+
 // runRoutine executes a single routine with a fresh config load.
 // This replicates the gd routines run execution sequence, ensuring
 // credentials are not cached across routine boundaries.
@@ -98,10 +144,16 @@ func runRoutine(ctx context.Context, burrowDir string, routine *pipeline.Routine
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
-	config.ResolveEnvVars(cfg)
+	resolveEnvVars(cfg)
 	if err := config.Validate(cfg); err != nil {
 		return fmt.Errorf("invalid config: %w", err)
 	}
+	// The scheduler's routine is shared between runs, so work on a copy.
+	withDefaults := *routine
+	routine = &withDefaults
+	if err := pipeline.ApplySynthesisDefaults(routine, cfg.SynthesisDefaults); err != nil {
+		return err
+	}
 
 	// Load user profile (optional, re-read each run for fresh data) —
 	// needed before buildRegistry for template expansion in tool paths.
@@ -131,8 +183,15 @@ func runRoutine(ctx context.Context, burrowDir string, routine *pipeline.Routine
 	if prof != nil {
 		executor.SetProfile(prof)
 	}
+	executor.SetValueStore(values.NewStore(filepath.Join(burrowDir, "routine-values.json")))
+	executor.SetShapeStore(pipeline.NewShapeStore(filepath.Join(burrowDir, "source-shapes.json")))
+	executor.SetHashStore(pipeline.NewHashStore(filepath.Join(burrowDir, "source-hashes.json")))
 
 	report, err := executor.Run(ctx, routine)
+	if unchanged, ok := pipeline.Unchanged(err); ok {
+		fmt.Fprintf(os.Stderr, "%s: %v; no report written\n", routine.Name, unchanged)
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("running routine: %w", err)
 	}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jcadam/burrow/pkg/config"
+	"github.com/jcadam/burrow/pkg/profile"
+	"github.com/jcadam/burrow/pkg/services"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(exploreCmd)
+}
+
+var exploreCmd = &cobra.Command{
+	Use:   "explore <service>",
+	Short: "List a service's tools and call them interactively",
+	Long: "Lists the tools configured for a service, then prompts for a tool and its params and prints " +
+		"the raw result. Use it to check API mappings before writing a routine. Calls bypass the result " +
+		"cache so every call reaches the service.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		burrowDir, err := config.BurrowDir()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(burrowDir)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		resolveEnvVars(cfg)
+		if err := config.Validate(cfg); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+
+		var svcCfg *config.ServiceConfig
+		for i := range cfg.Services {
+			if cfg.Services[i].Name == args[0] {
+				svcCfg = &cfg.Services[i]
+				break
+			}
+		}
+		if svcCfg == nil {
+			return fmt.Errorf("service %q not found in config", args[0])
+		}
+		// A cached result would hide what the service returns now.
+		svcCfg.CacheTTL = 0
+
+		prof, _ := profile.Load(burrowDir)
+		registry, err := buildRegistry(cfg, burrowDir, prof, nil)
+		if err != nil {
+			return err
+		}
+		svc, err := registry.Get(svcCfg.Name)
+		if err != nil {
+			return err
+		}
+
+		return explore(cmd.Context(), svc, *svcCfg, os.Stdin, os.Stdout)
+	},
+}
+
+// explore lists a service's configured tools, then repeatedly prompts for a
+// tool and its params, executes it, and prints the raw result. A tool that
+// isn't in config (as with MCP services, whose tools live on the server) is
+// still called, with params entered as name=value lines. A blank tool name
+// or end of input ends the session.
+func explore(ctx context.Context, svc services.Service, svcCfg config.ServiceConfig, in io.Reader, out io.Writer) error {
+	tools := make(map[string]config.ToolConfig, len(svcCfg.Tools))
+	for _, tool := range svcCfg.Tools {
+		tools[tool.Name] = tool
+	}
+	printTools(out, svcCfg)
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "\nTool (blank to quit): ")
+		if !scanner.Scan() {
+			return nil
+		}
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" {
+			return nil
+		}
+
+		var params map[string]string
+		if tool, ok := tools[name]; ok {
+			params = promptParams(scanner, out, tool.Params)
+		} else {
+			fmt.Fprintf(out, "%q is not configured for %s; enter params as name=value, blank line to call.\n", name, svcCfg.Name)
+			params = promptFreeParams(scanner, out)
+		}
+
+		result, err := svc.Execute(ctx, name, params)
+		if err != nil {
+			fmt.Fprintf(out, "Error: %v\n", err)
+			continue
+		}
+		printResult(out, result)
+	}
+}
+
+func printTools(out io.Writer, svcCfg config.ServiceConfig) {
+	if len(svcCfg.Tools) == 0 {
+		fmt.Fprintf(out, "%s (%s) has no tools in config.\n", svcCfg.Name, svcCfg.Type)
+		return
+	}
+	fmt.Fprintf(out, "%s (%s) tools:\n", svcCfg.Name, svcCfg.Type)
+	for _, tool := range svcCfg.Tools {
+		fmt.Fprintf(out, "  %s", tool.Name)
+		if tool.Description != "" {
+			fmt.Fprintf(out, " — %s", tool.Description)
+		}
+		fmt.Fprintln(out)
+		for _, p := range tool.Params {
+			fmt.Fprintf(out, "      %s (%s)%s\n", p.Name, paramType(p), requiredNote(p))
+		}
+	}
+}
+
+// promptParams asks for each configured param in turn. Blank answers leave
+// the param unset.
+func promptParams(scanner *bufio.Scanner, out io.Writer, params []config.ParamConfig) map[string]string {
+	values := make(map[string]string)
+	for _, p := range params {
+		fmt.Fprintf(out, "  %s (%s)%s: ", p.Name, paramType(p), requiredNote(p))
+		if !scanner.Scan() {
+			break
+		}
+		if v := strings.TrimSpace(scanner.Text()); v != "" {
+			values[p.Name] = v
+		}
+	}
+	return values
+}
+
+// promptFreeParams reads name=value lines until a blank line.
+func promptFreeParams(scanner *bufio.Scanner, out io.Writer) map[string]string {
+	values := make(map[string]string)
+	for {
+		fmt.Fprint(out, "  param: ")
+		if !scanner.Scan() {
+			return values
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			return values
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			fmt.Fprintln(out, "  expected name=value")
+			continue
+		}
+		values[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+}
+
+func paramType(p config.ParamConfig) string {
+	if p.Type == "" {
+		return "string"
+	}
+	return p.Type
+}
+
+func requiredNote(p config.ParamConfig) string {
+	if p.In == "path" {
+		return ", required"
+	}
+	return ""
+}
+
+func printResult(out io.Writer, r *services.Result) {
+	fmt.Fprintln(out)
+	if r.URL != "" {
+		fmt.Fprintf(out, "URL: %s\n", r.URL)
+	}
+	if r.ContentType != "" {
+		fmt.Fprintf(out, "Content-Type: %s\n", r.ContentType)
+	}
+	names := make([]string, 0, len(r.Headers))
+	for name := range r.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "%s: %s\n", name, r.Headers[name])
+	}
+	if r.Error != "" {
+		fmt.Fprintf(out, "Error: %s\n", r.Error)
+	}
+	if r.Empty {
+		fmt.Fprintln(out, "(no results)")
+	}
+	if len(r.Data) > 0 {
+		fmt.Fprintf(out, "\n%s\n", r.Data)
+	}
+}
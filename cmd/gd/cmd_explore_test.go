@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/config"
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+type exploreService struct {
+	calls []map[string]string
+	tools []string
+}
+
+func (s *exploreService) Name() string { return "api" }
+
+func (s *exploreService) Execute(_ context.Context, tool string, params map[string]string) (*services.Result, error) {
+	s.tools = append(s.tools, tool)
+	s.calls = append(s.calls, params)
+	return &services.Result{
+		Service: "api",
+		Tool:    tool,
+		URL:     "https://api.example.com/" + tool,
+		Data:    []byte(`{"ok": true}`),
+	}, nil
+}
+
+func TestExploreCallsConfiguredTool(t *testing.T) {
+	svc := &exploreService{}
+	svcCfg := config.ServiceConfig{
+		Name: "api",
+		Type: "rest",
+		Tools: []config.ToolConfig{{
+			Name:        "search",
+			Description: "Full-text search",
+			Params: []config.ParamConfig{
+				{Name: "query", Type: "string", MapsTo: "q"},
+				{Name: "limit", Type: "int", MapsTo: "n"},
+			},
+		}},
+	}
+
+	// Call search with a query and no limit, then quit.
+	in := strings.NewReader("search\nearthquakes\n\n\n")
+	var out bytes.Buffer
+	if err := explore(context.Background(), svc, svcCfg, in, &out); err != nil {
+		t.Fatalf("explore: %v", err)
+	}
+
+	if len(svc.calls) != 1 || svc.tools[0] != "search" {
+		t.Fatalf("expected one search call, got %v", svc.tools)
+	}
+	if got := svc.calls[0]; got["query"] != "earthquakes" || len(got) != 1 {
+		t.Errorf("params = %v, want only query", got)
+	}
+	for _, want := range []string{"search — Full-text search", "limit (int)", "URL: https://api.example.com/search", `{"ok": true}`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestExploreUnconfiguredToolTakesFreeParams(t *testing.T) {
+	svc := &exploreService{}
+	svcCfg := config.ServiceConfig{Name: "api", Type: "mcp"}
+
+	in := strings.NewReader("lookup\nid = 42\nbogus\n\n")
+	var out bytes.Buffer
+	if err := explore(context.Background(), svc, svcCfg, in, &out); err != nil {
+		t.Fatalf("explore: %v", err)
+	}
+
+	if len(svc.calls) != 1 || svc.tools[0] != "lookup" {
+		t.Fatalf("expected one lookup call, got %v", svc.tools)
+	}
+	if got := svc.calls[0]; got["id"] != "42" || len(got) != 1 {
+		t.Errorf("params = %v, want id=42", got)
+	}
+	if !strings.Contains(out.String(), "expected name=value") {
+		t.Errorf("expected a hint for the malformed param:\n%s", out.String())
+	}
+}
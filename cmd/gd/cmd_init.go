@@ -39,6 +39,8 @@ var initCmd = &cobra.Command{
 		// Try to detect Ollama for conversational config
 		if provider := configure.DetectOllama(); provider != nil {
 			session := configure.NewSession(burrowDir, &config.Config{}, provider)
+			session.SetRenderStyle(renderStyle(nil))
+			session.SetAccessible(accessibleMode(nil))
 			cfg, err = configure.RunInitTUI(cmd.Context(), session)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "  Conversational config failed: %v\n", err)
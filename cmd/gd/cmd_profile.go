@@ -57,13 +57,10 @@ var profileEditCmd = &cobra.Command{
 
 		// Create a starter file if it doesn't exist
 		if _, err := os.Stat(profilePath); os.IsNotExist(err) {
-			starter := &profile.Profile{
-				Raw: map[string]interface{}{
-					"name":        "",
-					"description": "",
-					"interests":   []interface{}{},
-				},
-			}
+			// A malformed schema yields nil here; Save warns about it.
+			schema, _ := profile.LoadSchema(burrowDir)
+			// Declared fields with their declared types, so the starter satisfies the schema.
+			starter := &profile.Profile{Raw: schema.Starter(), Schema: schema}
 			if err := profile.Save(burrowDir, starter); err != nil {
 				return fmt.Errorf("creating profile: %w", err)
 			}
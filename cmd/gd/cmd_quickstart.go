@@ -424,7 +424,7 @@ func runQuickstart(ctx context.Context) error {
 
 	// Resolve env vars on a copy for runtime use (saved config keeps ${VAR} references).
 	runtimeCfg := cfg.DeepCopy()
-	config.ResolveEnvVars(runtimeCfg)
+	resolveEnvVars(runtimeCfg)
 
 	// Build registry and test connectivity
 	registry, err := buildRegistry(runtimeCfg, burrowDir, prof, nil)
@@ -4,54 +4,136 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jcadam/burrow/pkg/actions"
 	"github.com/jcadam/burrow/pkg/config"
 	"github.com/jcadam/burrow/pkg/profile"
 	"github.com/jcadam/burrow/pkg/render"
 	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/jcadam/burrow/pkg/slug"
 	"github.com/spf13/cobra"
 )
 
-var exportFormat string
+var (
+	exportFormat string
+	baselineName string
+)
 
 func init() {
 	rootCmd.AddCommand(reportsCmd)
+	reportsCmd.AddCommand(reportsListCmd)
 	reportsCmd.AddCommand(reportsViewCmd)
+	reportsCmd.AddCommand(reportsOpenCmd)
 	reportsCmd.AddCommand(reportsSearchCmd)
 	reportsCmd.AddCommand(reportsExportCmd)
 	reportsCmd.AddCommand(reportsCompareCmd)
+	reportsCmd.AddCommand(reportsBaselineCmd)
 
 	reportsExportCmd.Flags().StringVar(&exportFormat, "format", "md", "export format: md, html, or pdf")
+	for _, c := range []*cobra.Command{reportsViewCmd, reportsBaselineCmd} {
+		c.Flags().StringVar(&baselineName, "baseline", reports.DefaultBaseline, "name of the baseline to pin to and diff against")
+	}
+	for _, c := range []*cobra.Command{reportsCmd, reportsListCmd} {
+		c.Flags().String("since", "", "only reports newer than a duration (7d, 12h, 2w) or date (YYYY-MM-DD)")
+		c.Flags().String("routine", "", "only reports from this routine")
+	}
 }
 
 var reportsCmd = &cobra.Command{
 	Use:   "reports",
 	Short: "List and view generated reports",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		burrowDir, err := config.BurrowDir()
+	RunE:  runReportsList,
+}
+
+var reportsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List reports, optionally filtered by age and routine",
+	Args:  cobra.NoArgs,
+	RunE:  runReportsList,
+}
+
+func runReportsList(cmd *cobra.Command, args []string) error {
+	burrowDir, err := config.BurrowDir()
+	if err != nil {
+		return err
+	}
+	reportsDir := filepath.Join(burrowDir, "reports")
+
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	routineFlag, _ := cmd.Flags().GetString("routine")
+
+	var all []*reports.Report
+	if sinceFlag != "" {
+		since, err := parseSince(sinceFlag, time.Now())
 		if err != nil {
 			return err
 		}
-		reportsDir := filepath.Join(burrowDir, "reports")
-		all, err := reports.List(reportsDir)
+		all, err = reports.ListSince(reportsDir, since)
 		if err != nil {
 			return fmt.Errorf("listing reports: %w", err)
 		}
-		if len(all) == 0 {
-			fmt.Println("No reports found. Run a routine first: gd routines run <name>")
-			return nil
+	} else {
+		all, err = reports.List(reportsDir)
+		if err != nil {
+			return fmt.Errorf("listing reports: %w", err)
 		}
-		for _, r := range all {
-			title := r.Title
-			if title == "" {
-				title = r.Routine
-			}
-			fmt.Printf("  %s  %s  (%d sources)\n", r.Date, title, len(r.Sources))
+	}
+	if routineFlag != "" {
+		all = filterByRoutine(all, routineFlag)
+	}
+
+	if len(all) == 0 {
+		if sinceFlag != "" || routineFlag != "" {
+			fmt.Println("No reports match.")
+			return nil
 		}
+		fmt.Println("No reports found. Run a routine first: gd routines run <name>")
 		return nil
-	},
+	}
+	for _, r := range all {
+		title := r.Title
+		if title == "" {
+			title = r.Routine
+		}
+		fmt.Printf("  %s  %s  (%d sources)\n", r.Date, title, len(r.Sources))
+	}
+	return nil
+}
+
+// sincePattern matches relative ages like 7d, 12h, or 2w.
+var sincePattern = regexp.MustCompile(`^(\d+)([hdw])$`)
+
+// parseSince resolves a --since or --period value to an absolute time: a relative age
+// counted back from now, or a local date (YYYY-MM-DD) or date and time
+// (YYYY-MM-DDTHH:MM).
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if m := sincePattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		unit := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[m[2]]
+		return now.Add(-time.Duration(n) * unit), nil
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use a duration like 7d, 12h, 2w or a date like 2026-01-31)", s)
+}
+
+// filterByRoutine keeps reports produced by the named routine.
+func filterByRoutine(all []*reports.Report, routine string) []*reports.Report {
+	name := slug.Sanitize(routine)
+	var kept []*reports.Report
+	for _, r := range all {
+		if r.Routine == name {
+			kept = append(kept, r)
+		}
+	}
+	return kept
 }
 
 var reportsViewCmd = &cobra.Command{
@@ -88,8 +170,11 @@ var reportsViewCmd = &cobra.Command{
 
 		cfg, _ := loadConfigQuiet(burrowDir)
 		prof, _ := profile.Load(burrowDir)
-		opts := viewerOptions(cfg, prof)
-		opts = append(opts, render.WithReportDir(report.Dir))
+		opts := withReportAge(viewerOptions(cfg, prof), report)
+		opts = append(opts, render.WithReportDir(report.Dir), render.WithBaseline(baselineName))
+		if ledger, err := openLedger(); err == nil {
+			opts = append(opts, render.WithLedger(ledger))
+		}
 		if cfg != nil {
 			opts = append(opts, render.WithImageConfig(cfg.Rendering.Images))
 		}
@@ -137,6 +222,70 @@ var reportsSearchCmd = &cobra.Command{
 	},
 }
 
+var reportsBaselineCmd = &cobra.Command{
+	Use:   "baseline [routine|date]",
+	Short: "Pin a report as a named baseline, or list baselines",
+	Long: `Pins a report as a named baseline (--baseline, default "default") that the
+viewer can diff later reports against with D. The viewer pins the open
+report with b. Without an argument, lists the baselines.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		burrowDir, err := config.BurrowDir()
+		if err != nil {
+			return err
+		}
+		reportsDir := filepath.Join(burrowDir, "reports")
+
+		if len(args) == 0 {
+			baselines, err := reports.LoadBaselines(reportsDir)
+			if err != nil {
+				return err
+			}
+			if len(baselines) == 0 {
+				fmt.Println("No baselines. Pin one with: gd reports baseline <routine|date>")
+				return nil
+			}
+			for _, name := range reports.BaselineNames(baselines) {
+				fmt.Printf("  %-16s %s\n", name, baselines[name])
+			}
+			return nil
+		}
+
+		report, err := resolveReport(reportsDir, args[0])
+		if err != nil {
+			return err
+		}
+		if err := reports.SetBaseline(reportsDir, baselineName, report.Dir); err != nil {
+			return err
+		}
+		fmt.Printf("Pinned %s as baseline %q\n", filepath.Base(report.Dir), baselineName)
+		return nil
+	},
+}
+
+var reportsOpenCmd = &cobra.Command{
+	Use:   "open <routine|date>",
+	Short: "Open a report's markdown in the system's default app",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		burrowDir, err := config.BurrowDir()
+		if err != nil {
+			return err
+		}
+		report, err := resolveReport(filepath.Join(burrowDir, "reports"), args[0])
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(report.Dir, "report.md")
+		if err := actions.NewHandoff(config.AppsConfig{}).OpenDefault(path); err != nil {
+			return err
+		}
+		fmt.Printf("Opened: %s\n", path)
+		return nil
+	},
+}
+
 var reportsExportCmd = &cobra.Command{
 	Use:   "export <routine|date>",
 	Short: "Export a report to a file (md or html)",
@@ -276,7 +425,7 @@ func loadConfigQuiet(burrowDir string) (*config.Config, error) {
 	if err != nil {
 		return nil, err
 	}
-	config.ResolveEnvVars(cfg)
+	resolveEnvVars(cfg)
 	return cfg, nil
 }
 
@@ -349,7 +498,7 @@ Format your response as structured markdown with clear sections.`
 			return fmt.Errorf("LLM comparison: %w", err)
 		}
 
-		rendered, err := render.RenderMarkdown(response, 80)
+		rendered, err := render.RenderMarkdownStyle(response, 80, renderStyle(cfg))
 		if err != nil {
 			fmt.Println(response)
 			return nil
@@ -361,11 +510,14 @@ Format your response as structured markdown with clear sections.`
 
 // viewerOptions builds viewer options from config for the enhanced viewer.
 func viewerOptions(cfg *config.Config, prof *profile.Profile) []render.ViewerOption {
+	opts := []render.ViewerOption{
+		render.WithStyle(renderStyle(cfg)),
+		render.WithAccessible(accessibleMode(cfg)),
+	}
 	if cfg == nil {
-		return nil
+		return opts
 	}
 
-	var opts []render.ViewerOption
 	opts = append(opts, render.WithHandoff(actions.NewHandoff(cfg.Apps)))
 
 	if p := findLocalProvider(cfg); p != nil {
@@ -376,5 +528,18 @@ func viewerOptions(cfg *config.Config, prof *profile.Profile) []render.ViewerOpt
 		opts = append(opts, render.WithProfile(prof))
 	}
 
+	if days := cfg.Rendering.StaleDays; days != nil {
+		opts = append(opts, render.WithStaleAfter(time.Duration(*days)*24*time.Hour))
+	}
+
+	return opts
+}
+
+// withReportAge appends the report's creation time to opts so the viewer
+// can show its age and warn when it is stale.
+func withReportAge(opts []render.ViewerOption, report *reports.Report) []render.ViewerOption {
+	if t, ok := report.Generated(); ok {
+		opts = append(opts, render.WithGenerated(t))
+	}
 	return opts
 }
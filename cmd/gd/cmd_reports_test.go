@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/reports"
 )
 
 func TestExtractSnippet(t *testing.T) {
@@ -128,3 +131,40 @@ func TestResolveReport(t *testing.T) {
 		t.Error("expected error for no match")
 	}
 }
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"12h", now.Add(-12 * time.Hour)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"2w", now.AddDate(0, 0, -14)},
+		{"2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)},
+		{"2026-03-01T08:30", time.Date(2026, 3, 1, 8, 30, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.in, now)
+		if err != nil {
+			t.Errorf("parseSince(%q): %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"", "7", "d7", "3m", "yesterday", "2026-13-01"} {
+		if _, err := parseSince(bad, now); err == nil {
+			t.Errorf("parseSince(%q) should fail", bad)
+		}
+	}
+}
+
+func TestFilterByRoutine(t *testing.T) {
+	all := []*reports.Report{{Routine: "morning-intel"}, {Routine: "evening"}, {Routine: "morning-intel"}}
+	if got := filterByRoutine(all, "Morning Intel"); len(got) != 2 {
+		t.Errorf("filterByRoutine kept %d, want 2", len(got))
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jcadam/burrow/pkg/config"
+	"github.com/jcadam/burrow/pkg/pipeline"
+	"github.com/jcadam/burrow/pkg/profile"
+	"github.com/jcadam/burrow/pkg/services"
+	"github.com/jcadam/burrow/pkg/slug"
+	"github.com/jcadam/burrow/pkg/synthesis"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(resynthCmd)
+	resynthCmd.Flags().Bool("deterministic", false, "Reproducible synthesis: temperature 0, fixed seed; prompts saved to prompts.md")
+}
+
+var resynthCmd = &cobra.Command{
+	Use:   "resynth <report>",
+	Short: "Regenerate a report from its stored raw data without re-fetching",
+	Long: "Re-runs synthesis for an existing report using the raw results saved in its data/ directory. " +
+		"No service is queried. The routine's current synthesis settings are used, so edits to its " +
+		"system prompt take effect. <report> is a report directory name, routine name, or date prefix.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		burrowDir, err := config.BurrowDir()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(burrowDir)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		resolveEnvVars(cfg)
+
+		reportsDir := filepath.Join(burrowDir, "reports")
+		reportDir, err := resolveReportDir(reportsDir, args[0])
+		if err != nil {
+			return err
+		}
+
+		routinesDir := filepath.Join(burrowDir, "routines")
+		routine, err := findRoutineForReport(routinesDir, reportDir)
+		if err != nil {
+			return err
+		}
+		if err := pipeline.ApplySynthesisDefaults(routine, cfg.SynthesisDefaults); err != nil {
+			return err
+		}
+
+		deterministic, _ := cmd.Flags().GetBool("deterministic")
+		var synth synthesis.Synthesizer
+		var recorder *synthesis.PromptRecorder
+		if deterministic {
+			synth, recorder, err = buildDeterministicSynthesizer(routine, cfg)
+		} else {
+			synth, err = buildSynthesizer(routine, cfg)
+		}
+		if err != nil {
+			return fmt.Errorf("configuring synthesizer: %w", err)
+		}
+
+		// Resynthesis never queries services, so the registry stays empty.
+		executor := pipeline.NewExecutor(services.NewRegistry(), synth, reportsDir)
+		if prof, _ := profile.Load(burrowDir); prof != nil {
+			executor.SetProfile(prof)
+		}
+
+		report, err := executor.Resynthesize(cmd.Context(), routine, reportDir)
+		if err != nil {
+			return fmt.Errorf("resynthesizing: %w", err)
+		}
+		if err := savePrompts(report.Dir, recorder); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: saving prompts: %v\n", err)
+		}
+
+		fmt.Printf("Report regenerated: %s\n", report.Dir)
+		return nil
+	},
+}
+
+// resolveReportDir returns the report directory for ref. An exact directory
+// name is accepted even without a report.md, so reports whose synthesis
+// failed can be regenerated; otherwise resolveReport's matching applies.
+func resolveReportDir(reportsDir, ref string) (string, error) {
+	dir := filepath.Join(reportsDir, ref)
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir, nil
+	}
+	report, err := resolveReport(reportsDir, ref)
+	if err != nil {
+		return "", err
+	}
+	return report.Dir, nil
+}
+
+// findRoutineForReport loads the routine that produced the report in
+// reportDir, matching the routine name embedded in the directory name.
+func findRoutineForReport(routinesDir, reportDir string) (*pipeline.Routine, error) {
+	routines, err := pipeline.LoadAllRoutines(routinesDir, os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("loading routines: %w", err)
+	}
+	base := filepath.Base(reportDir)
+	var match *pipeline.Routine
+	for _, r := range routines {
+		name := slug.Sanitize(r.Name)
+		// Directory names end in "-<routine>"; prefer the longest match so
+		// "brief" does not claim a "morning-brief" report.
+		if strings.HasSuffix(base, "-"+name) {
+			if match == nil || len(name) > len(slug.Sanitize(match.Name)) {
+				match = r
+			}
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("no routine found for report %s", base)
+	}
+	return match, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/config"
+	"github.com/jcadam/burrow/pkg/pipeline"
+	"github.com/jcadam/burrow/pkg/profile"
+	"github.com/jcadam/burrow/pkg/services"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(rollupCmd)
+	rollupCmd.Flags().String("period", "7d", "how far back to roll up: a duration (7d, 2w) or date (YYYY-MM-DD)")
+}
+
+var rollupCmd = &cobra.Command{
+	Use:   "rollup <routine>",
+	Short: "Consolidate a routine's recent reports into one roll-up report",
+	Long: "Feeds the routine's reports from the period to its synthesizer as sources and saves one " +
+		"consolidated report under \"<routine>-rollup\". No service is queried.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		burrowDir, err := config.BurrowDir()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.Load(burrowDir)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		resolveEnvVars(cfg)
+
+		period, _ := cmd.Flags().GetString("period")
+		since, err := parseSince(period, time.Now())
+		if err != nil {
+			return err
+		}
+
+		routines, err := pipeline.LoadAllRoutines(filepath.Join(burrowDir, "routines"), os.Stderr)
+		if err != nil {
+			return fmt.Errorf("loading routines: %w", err)
+		}
+		var routine *pipeline.Routine
+		for _, r := range routines {
+			if r.Name == args[0] {
+				routine = r
+				break
+			}
+		}
+		if routine == nil {
+			return fmt.Errorf("routine %q not found", args[0])
+		}
+		if err := pipeline.ApplySynthesisDefaults(routine, cfg.SynthesisDefaults); err != nil {
+			return err
+		}
+
+		synth, err := buildSynthesizer(routine, cfg)
+		if err != nil {
+			return fmt.Errorf("configuring synthesizer: %w", err)
+		}
+
+		// Rollups read stored reports only, so the registry stays empty.
+		executor := pipeline.NewExecutor(services.NewRegistry(), synth, filepath.Join(burrowDir, "reports"))
+		if prof, _ := profile.Load(burrowDir); prof != nil {
+			executor.SetProfile(prof)
+		}
+
+		report, err := executor.Rollup(cmd.Context(), routine, since)
+		if err != nil {
+			return fmt.Errorf("rolling up: %w", err)
+		}
+
+		fmt.Printf("Rollup generated: %s\n", report.Dir)
+		return nil
+	},
+}
@@ -2,26 +2,37 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/jcadam/burrow/pkg/cache"
 	"github.com/jcadam/burrow/pkg/config"
 	bcontext "github.com/jcadam/burrow/pkg/context"
 	"github.com/jcadam/burrow/pkg/debug"
+	bfile "github.com/jcadam/burrow/pkg/file"
 	bhttp "github.com/jcadam/burrow/pkg/http"
 	"github.com/jcadam/burrow/pkg/mcp"
 	"github.com/jcadam/burrow/pkg/pipeline"
 	"github.com/jcadam/burrow/pkg/privacy"
 	"github.com/jcadam/burrow/pkg/profile"
+	"github.com/jcadam/burrow/pkg/ratelimit"
+	"github.com/jcadam/burrow/pkg/render"
 	"github.com/jcadam/burrow/pkg/reports"
 	brss "github.com/jcadam/burrow/pkg/rss"
+	"github.com/jcadam/burrow/pkg/scheduler"
 	"github.com/jcadam/burrow/pkg/services"
+	"github.com/jcadam/burrow/pkg/slug"
+	bstream "github.com/jcadam/burrow/pkg/stream"
 	"github.com/jcadam/burrow/pkg/synthesis"
+	"github.com/jcadam/burrow/pkg/values"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 func init() {
@@ -30,8 +41,20 @@ func init() {
 	routinesCmd.AddCommand(routinesRunCmd)
 	routinesCmd.AddCommand(routinesHistoryCmd)
 	routinesCmd.AddCommand(routinesTestCmd)
+	routinesCmd.AddCommand(routinesEditCmd)
+	routinesCmd.AddCommand(routinesSnoozeCmd)
+	routinesCmd.AddCommand(routinesEnableCmd)
 
 	routinesRunCmd.Flags().Bool("debug", false, "Print debug output (full requests, responses, timing)")
+	routinesRunCmd.Flags().Bool("events", false, "Stream progress events to stdout as NDJSON")
+	routinesRunCmd.Flags().Bool("deterministic", false, "Reproducible synthesis: temperature 0, fixed seed, no jitter; prompts saved to prompts.md")
+	routinesRunCmd.Flags().Bool("headlines", false, "Produce a headlines-only digest (same as report style: headlines)")
+	routinesRunCmd.Flags().Bool("print-prompt", false, "Fetch sources and print the synthesis prompts instead of calling the LLM; no report is written")
+	routinesRunCmd.Flags().Bool("watch", false, "Stream the report to the terminal as the model writes it, then open it in the viewer")
+	routinesRunCmd.Flags().String("output", "", "Also write the report to this path, in the format its extension names: .md, .html, .json, or .txt")
+	routinesRunCmd.Flags().String("retry-failed", "", "Re-fetch only the sources that failed in this earlier report of the routine and regenerate it in place")
+	routinesSnoozeCmd.Flags().String("until", "", "Date (YYYY-MM-DD) the routine resumes running on schedule")
+	_ = routinesSnoozeCmd.MarkFlagRequired("until")
 }
 
 var routinesCmd = &cobra.Command{
@@ -56,12 +79,26 @@ var routinesListCmd = &cobra.Command{
 			fmt.Println("No routines found. Add .yaml files to ~/.burrow/routines/")
 			return nil
 		}
+		state, err := scheduler.NewFileStateStore(filepath.Join(burrowDir, "scheduler-state.json")).Load()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+			state = &scheduler.State{}
+		}
 		for _, r := range routines {
 			fmt.Printf("  %s — %s\n", r.Name, r.Report.Title)
 			fmt.Printf("    Sources: %d", len(r.Sources))
 			if r.Schedule != "" {
 				fmt.Printf(" | Schedule: %s", r.Schedule)
 			}
+			if len(r.After) > 0 {
+				fmt.Printf(" | After: %s", strings.Join(r.After, ", "))
+			}
+			if slot := state.Missed[r.Name]; slot != "" && r.MissedRun == "notify" {
+				fmt.Printf(" | Missed %s run", slot)
+			}
+			if r.SnoozedOn(time.Now().Format("2006-01-02")) {
+				fmt.Printf(" | Snoozed until %s", r.SnoozeUntil)
+			}
 			fmt.Println()
 		}
 		return nil
@@ -75,6 +112,18 @@ var routinesRunCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		routineName := args[0]
 
+		// Check the output format before any source is queried.
+		output, _ := cmd.Flags().GetString("output")
+		if output != "" {
+			if _, err := outputFormat(output); err != nil {
+				return err
+			}
+		}
+		watch, _ := cmd.Flags().GetBool("watch")
+		if events, _ := cmd.Flags().GetBool("events"); watch && events {
+			return fmt.Errorf("--watch and --events both write to stdout (use one)")
+		}
+
 		burrowDir, err := config.BurrowDir()
 		if err != nil {
 			return err
@@ -85,7 +134,7 @@ var routinesRunCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("loading config: %w", err)
 		}
-		config.ResolveEnvVars(cfg)
+		resolveEnvVars(cfg)
 
 		if err := config.Validate(cfg); err != nil {
 			return fmt.Errorf("invalid config: %w", err)
@@ -106,6 +155,31 @@ var routinesRunCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("loading routine: %w", err)
 		}
+		if err := pipeline.ValidateRoutineLLM(routine, cfg); err != nil {
+			return err
+		}
+		if err := pipeline.ApplySynthesisDefaults(routine, cfg.SynthesisDefaults); err != nil {
+			return err
+		}
+		if headlines, _ := cmd.Flags().GetBool("headlines"); headlines {
+			routine.Report.Style = "headlines"
+		}
+
+		reportsDir := filepath.Join(burrowDir, "reports")
+		retryFailed, _ := cmd.Flags().GetString("retry-failed")
+		var retryDir string
+		if retryFailed != "" {
+			if printPrompt, _ := cmd.Flags().GetBool("print-prompt"); printPrompt {
+				return fmt.Errorf("--retry-failed and --print-prompt can't be combined")
+			}
+			retryDir, err = resolveReportDir(reportsDir, retryFailed)
+			if err != nil {
+				return err
+			}
+			if !strings.HasSuffix(filepath.Base(retryDir), "-"+slug.Sanitize(routine.Name)) {
+				return fmt.Errorf("report %s is not from routine %q", filepath.Base(retryDir), routine.Name)
+			}
+		}
 
 		// Load user profile (optional) — needed before buildRegistry for
 		// template expansion in tool paths.
@@ -125,8 +199,21 @@ var routinesRunCmd = &cobra.Command{
 			return err
 		}
 
+		valueStore := values.NewStore(filepath.Join(burrowDir, "routine-values.json"))
+
+		if printPrompt, _ := cmd.Flags().GetBool("print-prompt"); printPrompt {
+			return previewPrompts(cmd.Context(), routine, cfg, registry, reportsDir, prof, valueStore, os.Stdout)
+		}
+
 		// Select synthesizer based on routine's LLM field
-		synth, err := buildSynthesizer(routine, cfg)
+		deterministic, _ := cmd.Flags().GetBool("deterministic")
+		var synth synthesis.Synthesizer
+		var recorder *synthesis.PromptRecorder
+		if deterministic {
+			synth, recorder, err = buildDeterministicSynthesizer(routine, cfg)
+		} else {
+			synth, err = buildSynthesizer(routine, cfg)
+		}
 		if err != nil {
 			return fmt.Errorf("configuring synthesizer: %w", err)
 		}
@@ -144,7 +231,6 @@ var routinesRunCmd = &cobra.Command{
 		}
 
 		// Run pipeline
-		reportsDir := filepath.Join(burrowDir, "reports")
 		executor := pipeline.NewExecutor(registry, synth, reportsDir)
 		if ledger != nil {
 			executor.SetLedger(ledger)
@@ -152,20 +238,152 @@ var routinesRunCmd = &cobra.Command{
 		if prof != nil {
 			executor.SetProfile(prof)
 		}
+		executor.SetValueStore(valueStore)
+		executor.SetShapeStore(pipeline.NewShapeStore(filepath.Join(burrowDir, "source-shapes.json")))
+		executor.SetHashStore(pipeline.NewHashStore(filepath.Join(burrowDir, "source-hashes.json")))
 		if dbg != nil {
 			executor.SetDebug(dbg)
 		}
+		// With --events, stdout carries only NDJSON for the consuming program.
+		events, _ := cmd.Flags().GetBool("events")
+		if events {
+			executor.SetObserver(pipeline.NewJSONObserver(os.Stdout))
+		} else if term.IsTerminal(int(os.Stderr.Fd())) {
+			executor.SetObserver(summaryProgress{w: os.Stderr})
+		}
 
-		report, err := executor.Run(cmd.Context(), routine)
+		// Raw results are saved before synthesis starts, so stopping a
+		// watched run early with Ctrl-C loses nothing a resynth can't redo.
+		ctx := cmd.Context()
+		if watch {
+			ctx = synthesis.WithTokens(ctx, func(text string) { fmt.Print(text) })
+		}
+		var report *reports.Report
+		if retryDir != "" {
+			report, err = executor.RetryFailed(ctx, routine, retryDir)
+		} else {
+			report, err = executor.Run(ctx, routine)
+		}
+		if watch {
+			fmt.Println()
+		}
+		if unchanged, ok := pipeline.Unchanged(err); ok {
+			if !events {
+				fmt.Printf("No changes since %s; no report written.\n", unchanged.Since.Local().Format("2006-01-02 15:04"))
+			}
+			return nil
+		}
 		if err != nil {
 			return fmt.Errorf("running routine: %w", err)
 		}
+		if err := savePrompts(report.Dir, recorder); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: saving prompts: %v\n", err)
+		}
 
-		fmt.Printf("Report generated: %s\n", report.Dir)
+		if output != "" {
+			if err := writeReportOutput(report, output); err != nil {
+				return err
+			}
+		}
+
+		switch {
+		case events:
+			// stdout carries only events.
+		case watch && term.IsTerminal(int(os.Stdout.Fd())):
+			opts := withReportAge(viewerOptions(cfg, prof), report)
+			opts = append(opts, render.WithReportDir(report.Dir))
+			if ledger != nil {
+				opts = append(opts, render.WithLedger(ledger))
+			}
+			title := report.Title
+			if title == "" {
+				title = report.Routine + " — " + report.Date
+			}
+			return render.RunViewer(title, report.Markdown, opts...)
+		case output != "" && !term.IsTerminal(int(os.Stdout.Fd())):
+			// Piped: just the written path, for the consuming script.
+			fmt.Println(output)
+		case output != "":
+			fmt.Printf("Report generated: %s\nWritten: %s\n", report.Dir, output)
+		default:
+			fmt.Printf("Report generated: %s\n", report.Dir)
+		}
 		return nil
 	},
 }
 
+// outputFormat returns the format an --output path's extension names.
+func outputFormat(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".md", ".markdown":
+		return "md", nil
+	case ".html", ".htm":
+		return "html", nil
+	case ".json":
+		return "json", nil
+	case ".txt":
+		return "txt", nil
+	default:
+		return "", fmt.Errorf("unsupported --output extension %q (use .md, .html, .json, or .txt)", ext)
+	}
+}
+
+// writeReportOutput writes report to path in the format its extension
+// names: the markdown as-is, the HTML export, the JSON export, or text
+// rendered without color or escape codes.
+func writeReportOutput(report *reports.Report, path string) error {
+	format, err := outputFormat(path)
+	if err != nil {
+		return err
+	}
+	title := report.Title
+	if title == "" {
+		title = report.Routine + " — " + report.Date
+	}
+
+	var data []byte
+	switch format {
+	case "md":
+		data = []byte(report.Markdown)
+	case "html":
+		html, err := reports.ExportHTML(report.Markdown, title, report.Dir)
+		if err != nil {
+			return fmt.Errorf("exporting HTML: %w", err)
+		}
+		data = []byte(html)
+	case "json":
+		if data, err = reports.ExportJSON(report); err != nil {
+			return err
+		}
+	case "txt":
+		text, err := render.RenderPlain(report.Markdown, 80)
+		if err != nil {
+			return err
+		}
+		data = []byte(text)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// summaryProgress shows multi-stage synthesis progress on one terminal
+// line, so a slow local model doesn't look frozen.
+type summaryProgress struct {
+	w io.Writer
+}
+
+func (p summaryProgress) Observe(ev pipeline.Event) {
+	if ev.Type != pipeline.EventSynthesisProgress {
+		return
+	}
+	fmt.Fprintf(p.w, "\rSummarizing sources… %d/%d", ev.Done, ev.Total)
+	if ev.Done == ev.Total {
+		fmt.Fprintln(p.w)
+	}
+}
+
 var routinesHistoryCmd = &cobra.Command{
 	Use:   "history <name>",
 	Short: "Show report history for a routine",
@@ -224,7 +442,7 @@ var routinesTestCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("loading config: %w", err)
 		}
-		config.ResolveEnvVars(cfg)
+		resolveEnvVars(cfg)
 		if err := config.Validate(cfg); err != nil {
 			return fmt.Errorf("invalid config: %w", err)
 		}
@@ -243,6 +461,9 @@ var routinesTestCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("loading routine: %w", err)
 		}
+		if err := pipeline.ValidateRoutineLLM(routine, cfg); err != nil {
+			return err
+		}
 
 		// Load user profile (optional) — needed before buildRegistry for
 		// template expansion in tool paths.
@@ -253,6 +474,25 @@ var routinesTestCmd = &cobra.Command{
 			return err
 		}
 
+		// Check profile references against the schema before touching the network.
+		if prof != nil && len(prof.Schema) > 0 {
+			texts := []string{routine.Report.Title, routine.Synthesis.System}
+			for _, src := range routine.Sources {
+				for _, v := range src.Params {
+					texts = append(texts, v)
+				}
+			}
+			var schemaErrs []error
+			for _, text := range texts {
+				if err := prof.Schema.CheckTemplate(text, prof); err != nil {
+					schemaErrs = append(schemaErrs, err)
+				}
+			}
+			if err := errors.Join(schemaErrs...); err != nil {
+				fmt.Printf("Profile schema problems:\n%s\n\n", err)
+			}
+		}
+
 		fmt.Printf("Testing %d source(s) for routine %q...\n\n", len(routine.Sources), routineName)
 
 		synth := synthesis.NewPassthroughSynthesizer()
@@ -293,12 +533,23 @@ var routinesTestCmd = &cobra.Command{
 	},
 }
 
+// memoryCache is the process-wide backend for cache.backend: memory, so a
+// daemon reuses cached results across scheduled runs without writing them
+// to disk.
+var memoryCache = cache.NewMemoryBackend()
+
+// rateLimiters holds each service's rate_limit budget for the life of the
+// process, so a daemon's scheduled runs share it.
+var rateLimiters = ratelimit.NewSet()
+
 // buildRegistry creates a service registry from config, wiring privacy transport,
 // MCP clients, and result caching. burrowDir is used for cache storage.
 // prof is optional — when non-nil, REST services get a template expand function
 // for resolving {{profile.X}} references in tool paths.
 // dbg is optional — when non-nil, a debug transport is injected into each service's
-// HTTP client for request/response logging.
+// HTTP client for request/response logging. A service's rate_limit is waited
+// on before each request is sent, so the wait is neither logged as request
+// time nor counted against the client's timeout.
 func buildRegistry(cfg *config.Config, burrowDir string, prof *profile.Profile, dbg *debug.Logger) (*services.Registry, error) {
 	var privCfg *privacy.Config
 	if cfg.Privacy.StripReferrers || cfg.Privacy.RandomizeUserAgent || cfg.Privacy.MinimizeRequests {
@@ -315,12 +566,16 @@ func buildRegistry(cfg *config.Config, burrowDir string, prof *profile.Profile,
 		routes[i] = privacy.RouteEntry{Service: r.Service, Proxy: r.Proxy}
 	}
 
-	cacheDir := filepath.Join(burrowDir, "cache")
+	var cacheBackend cache.Backend = cache.NewDiskBackend(filepath.Join(burrowDir, "cache"))
+	if cfg.Cache.Backend == "memory" {
+		cacheBackend = memoryCache
+	}
 
 	registry := services.NewRegistry()
 	for _, svcCfg := range cfg.Services {
 		var svc services.Service
 		proxyURL := privacy.ResolveProxy(svcCfg.Name, cfg.Privacy.DefaultProxy, routes)
+		limit := serviceRateLimit(svcCfg)
 
 		switch svcCfg.Type {
 		case "rest":
@@ -336,13 +591,20 @@ func buildRegistry(cfg *config.Config, burrowDir string, prof *profile.Profile,
 					return debug.NewTransport(rt, dbg)
 				})
 			}
+			if limit != nil {
+				restSvc.SetLimiter(limit)
+			}
 			svc = restSvc
 		case "mcp":
-			httpClient := mcp.NewHTTPClient(svcCfg.Auth, privCfg, proxyURL)
+			httpClient := mcp.NewHTTPClient(svcCfg, privCfg, proxyURL)
 			if dbg != nil {
 				httpClient.Transport = debug.NewTransport(httpClient.Transport, dbg)
 			}
-			svc = mcp.NewMCPService(svcCfg.Name, svcCfg.Endpoint, httpClient)
+			mcpSvc := mcp.NewMCPService(svcCfg.Name, svcCfg.Endpoint, httpClient)
+			if limit != nil {
+				mcpSvc.SetLimiter(limit)
+			}
+			svc = mcpSvc
 		case "rss":
 			rssSvc := brss.NewRSSService(svcCfg, privCfg, proxyURL)
 			if dbg != nil {
@@ -350,16 +612,30 @@ func buildRegistry(cfg *config.Config, burrowDir string, prof *profile.Profile,
 					return debug.NewTransport(rt, dbg)
 				})
 			}
+			if limit != nil {
+				rssSvc.SetLimiter(limit)
+			}
 			svc = rssSvc
+		case "file":
+			svc = bfile.NewFileService(svcCfg)
+		case "stream":
+			// Not wrapped with the debug transport: it buffers whole
+			// response bodies, and a stream's body only ends with the window.
+			svc = bstream.NewStreamService(svcCfg, privCfg, proxyURL)
 		default:
 			fmt.Fprintf(os.Stderr, "warning: unknown service type %q for %q, skipping\n", svcCfg.Type, svcCfg.Name)
 			continue
 		}
 
-		// Wrap with cache if TTL > 0.
-		if svcCfg.CacheTTL > 0 {
-			svc = cache.NewCachedService(svc, cacheDir, svcCfg.CacheTTL)
+		// Every service is wrapped: with no cache_ttl it passes calls
+		// through, unless a routine source sets its own cache_ttl.
+		cached := cache.NewCachedService(svc, cacheBackend, svcCfg.CacheTTL)
+		for _, tool := range svcCfg.Tools {
+			if tool.CacheKey != nil {
+				cached.SetKeyParams(tool.Name, tool.CacheKey.Include, tool.CacheKey.Exclude)
+			}
 		}
+		svc = cached
 
 		if err := registry.Register(svc); err != nil {
 			return nil, fmt.Errorf("registering service: %w", err)
@@ -368,33 +644,121 @@ func buildRegistry(cfg *config.Config, burrowDir string, prof *profile.Profile,
 	return registry, nil
 }
 
+// serviceRateLimit returns the limiter enforcing the service's rate_limit,
+// or nil when it has none.
+func serviceRateLimit(svcCfg config.ServiceConfig) *ratelimit.Limiter {
+	if svcCfg.RateLimit == nil {
+		return nil
+	}
+	per, err := svcCfg.RateLimit.Window()
+	if err != nil {
+		return nil // rejected by config validation
+	}
+	return rateLimiters.Get(svcCfg.Name, svcCfg.RateLimit.Requests, per)
+}
+
+// privateServices returns the names of services marked private. When any
+// are, attribution stripping applies to those alone.
+func privateServices(cfg *config.Config) []string {
+	var names []string
+	for _, svc := range cfg.Services {
+		if svc.Private {
+			names = append(names, svc.Name)
+		}
+	}
+	return names
+}
+
 // buildSynthesizer creates the appropriate synthesizer based on the routine's
 // LLM config and the global provider configuration.
 func buildSynthesizer(routine *pipeline.Routine, cfg *config.Config) (synthesis.Synthesizer, error) {
+	return buildSynthesizerWith(routine, cfg, nil)
+}
+
+// buildSynthesizerWith is buildSynthesizer with an optional wrapper applied
+// to the LLM provider before any call budget.
+func buildSynthesizerWith(routine *pipeline.Routine, cfg *config.Config, wrap func(synthesis.Provider) synthesis.Provider) (synthesis.Synthesizer, error) {
+	if err := pipeline.ValidateRoutineLLM(routine, cfg); err != nil {
+		return nil, err
+	}
 	llmName := routine.LLM
 	if llmName == "" || llmName == "none" || llmName == "passthrough" {
+		if routine.Report.Headlines() {
+			return synthesis.NewHeadlinesSynthesizer(), nil
+		}
 		return synthesis.NewPassthroughSynthesizer(), nil
 	}
 
 	// Find matching provider in config
-	var provCfg *config.ProviderConfig
-	for i := range cfg.LLM.Providers {
-		if cfg.LLM.Providers[i].Name == llmName {
-			provCfg = &cfg.LLM.Providers[i]
-			break
-		}
-	}
+	provCfg := findProvider(cfg, llmName)
 	if provCfg == nil {
 		return nil, fmt.Errorf("LLM provider %q not found in config", llmName)
 	}
+	synth, err := providerSynthesizer(routine, cfg, provCfg, wrap)
+	if err != nil {
+		return nil, err
+	}
+
+	// Follow with the configured fallbacks, skipping the routine's own
+	// provider and, for a local-only routine, any that aren't local.
+	names := []string{llmName}
+	chain := []synthesis.Synthesizer{synth}
+	for _, name := range cfg.LLM.Fallbacks {
+		fb := findProvider(cfg, name)
+		if fb == nil || name == llmName || (routine.Privacy == "local" && fb.Privacy != "local") {
+			continue
+		}
+		s, err := providerSynthesizer(routine, cfg, fb, wrap)
+		if err != nil {
+			return nil, fmt.Errorf("llm.fallbacks: %w", err)
+		}
+		names = append(names, name)
+		chain = append(chain, s)
+	}
+	if len(chain) == 1 {
+		return synth, nil
+	}
+	return synthesis.NewFailoverSynthesizer(names, chain), nil
+}
+
+// providerPricing returns the provider's configured pricing, or none.
+func providerPricing(provCfg *config.ProviderConfig) synthesis.Pricing {
+	if provCfg.Pricing == nil {
+		return synthesis.Pricing{}
+	}
+	return synthesis.Pricing{Prompt: provCfg.Pricing.Prompt, Completion: provCfg.Pricing.Completion}
+}
+
+// findProvider returns the named LLM provider's config, or nil.
+func findProvider(cfg *config.Config, name string) *config.ProviderConfig {
+	for i := range cfg.LLM.Providers {
+		if cfg.LLM.Providers[i].Name == name {
+			return &cfg.LLM.Providers[i]
+		}
+	}
+	return nil
+}
 
+// providerSynthesizer builds the synthesizer for one LLM provider, with the
+// routine's synthesis settings and the provider's privacy defaults.
+func providerSynthesizer(routine *pipeline.Routine, cfg *config.Config, provCfg *config.ProviderConfig, wrap func(synthesis.Provider) synthesis.Provider) (synthesis.Synthesizer, error) {
 	provider, err := synthesis.NewProvider(*provCfg)
 	if err != nil {
 		return nil, err
 	}
 	if provider == nil {
+		if routine.Report.Headlines() {
+			return synthesis.NewHeadlinesSynthesizer(), nil
+		}
 		return synthesis.NewPassthroughSynthesizer(), nil
 	}
+	if wrap != nil {
+		provider = wrap(provider)
+	}
+	if max := routine.Budget.MaxLLMCalls; max > 0 {
+		provider = synthesis.LimitCalls(provider, max)
+	}
+	provider = synthesis.MeterUsage(provider, provCfg.Name, providerPricing(provCfg))
 
 	// Strip attribution for remote providers when configured
 	stripAttribution := provCfg.Privacy == "remote" && cfg.Privacy.StripAttributionForRemote
@@ -411,6 +775,7 @@ func buildSynthesizer(routine *pipeline.Routine, cfg *config.Config) (synthesis.
 	}
 
 	synth := synthesis.NewLLMSynthesizer(provider, stripAttribution)
+	synth.SetPrivateServices(privateServices(cfg))
 	synth.SetLocalModel(provCfg.Privacy == "local")
 
 	// Resolve preprocessing: explicit config wins, nil = auto (local models).
@@ -420,16 +785,113 @@ func buildSynthesizer(routine *pipeline.Routine, cfg *config.Config) (synthesis.
 	}
 	synth.SetPreprocess(preprocess)
 
+	if routine.Synthesis.Retries != nil {
+		synth.SetRetries(*routine.Synthesis.Retries)
+	}
+	synth.SetSourceOrder(routine.Synthesis.SourceOrder)
+
 	synth.SetMultiStage(synthesis.MultiStageConfig{
-		Strategy:        routine.Synthesis.Strategy,
-		SummaryMaxWords: routine.Synthesis.SummaryMaxWords,
-		MaxSourceWords:  routine.Synthesis.MaxSourceWords,
-		Concurrency:     routine.Synthesis.Concurrency,
-		ContextWindow:   contextWindow,
+		Strategy:         routine.Synthesis.Strategy,
+		SummaryMaxWords:  routine.Synthesis.SummaryMaxWords,
+		MaxSourceWords:   routine.Synthesis.MaxSourceWords,
+		Concurrency:      routine.Synthesis.Concurrency,
+		ContextWindow:    contextWindow,
+		TruncationMarker: routine.Synthesis.TruncationMarker,
 	})
 	return synth, nil
 }
 
+// deterministicSeed is the sampling seed used by --deterministic.
+const deterministicSeed = 42
+
+// makeDeterministic adjusts the in-memory routine and config so synthesis is
+// reproducible: temperature 0 and a fixed seed for the routine's provider,
+// no jitter, and sequential stage-1 calls so prompts are sent in a stable
+// order. Neither file on disk changes.
+func makeDeterministic(routine *pipeline.Routine, cfg *config.Config) {
+	routine.Jitter = pipeline.JitterConfig{}
+	routine.Synthesis.Concurrency = 1
+	for i := range cfg.LLM.Providers {
+		p := &cfg.LLM.Providers[i]
+		if p.Name != routine.LLM {
+			continue
+		}
+		zero := 0.0
+		p.Temperature = &zero
+		if p.Seed == nil {
+			seed := deterministicSeed
+			p.Seed = &seed
+		}
+	}
+}
+
+// buildDeterministicSynthesizer applies makeDeterministic and builds a
+// synthesizer whose prompts are recorded; save them with savePrompts.
+func buildDeterministicSynthesizer(routine *pipeline.Routine, cfg *config.Config) (synthesis.Synthesizer, *synthesis.PromptRecorder, error) {
+	makeDeterministic(routine, cfg)
+	var recorder *synthesis.PromptRecorder
+	synth, err := buildSynthesizerWith(routine, cfg, func(p synthesis.Provider) synthesis.Provider {
+		recorder = synthesis.RecordPrompts(p)
+		return recorder
+	})
+	return synth, recorder, err
+}
+
+// previewPrompts fetches a routine's sources and prints the prompts its
+// synthesizer would send, multi-stage prompts included, without calling the
+// LLM. Every model reply is a placeholder, so a stage 2 prompt shows where
+// stage 1 summaries go rather than real ones. Stage 1 runs sequentially so
+// prompts print in source order, and jitter is skipped. Nothing is saved.
+func previewPrompts(ctx context.Context, routine *pipeline.Routine, cfg *config.Config, registry *services.Registry, reportsDir string, prof *profile.Profile, valueStore *values.Store, out io.Writer) error {
+	routine.Jitter = pipeline.JitterConfig{}
+	routine.Synthesis.Concurrency = 1
+
+	var recorder *synthesis.PromptRecorder
+	synth, err := buildSynthesizerWith(routine, cfg, func(synthesis.Provider) synthesis.Provider {
+		recorder = synthesis.RecordPrompts(synthesis.PreviewProvider{})
+		return recorder
+	})
+	if err != nil {
+		return fmt.Errorf("configuring synthesizer: %w", err)
+	}
+	if recorder == nil {
+		fmt.Fprintf(out, "Routine %q uses passthrough synthesis; no prompt is sent to an LLM.\n", routine.Name)
+		return nil
+	}
+
+	executor := pipeline.NewExecutor(registry, synth, reportsDir)
+	if prof != nil {
+		executor.SetProfile(prof)
+	}
+	executor.SetValueStore(valueStore)
+
+	title, system, results, err := executor.SynthesisInput(ctx, routine)
+	if err != nil {
+		return fmt.Errorf("running routine: %w", err)
+	}
+	if _, err := synth.Synthesize(ctx, title, system, results); err != nil {
+		return fmt.Errorf("assembling prompts: %w", err)
+	}
+	return recorder.WritePrompts(out)
+}
+
+// savePrompts writes the recorded prompts to prompts.md in the report
+// directory. A nil recorder (passthrough synthesis) writes nothing.
+func savePrompts(reportDir string, recorder *synthesis.PromptRecorder) error {
+	if recorder == nil {
+		return nil
+	}
+	f, err := os.Create(filepath.Join(reportDir, "prompts.md"))
+	if err != nil {
+		return err
+	}
+	if err := recorder.WriteMarkdown(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 // debugSynthesizer wraps a Synthesizer to log timing and sizes when --debug is active.
 type debugSynthesizer struct {
 	inner synthesis.Synthesizer
@@ -457,3 +919,105 @@ func (d *debugSynthesizer) Synthesize(ctx context.Context, title string, systemP
 	d.dbg.Printf("synthesis complete (%s): %d chars markdown", elapsed.Round(time.Millisecond), len(md))
 	return md, nil
 }
+
+var routinesEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Open a routine in your editor, validating before saving",
+	Long: "Opens a copy of the routine's YAML in your editor. On exit the copy is validated; invalid " +
+		"edits are never saved, and you can reopen the editor to fix them. The previous routine is " +
+		"kept alongside it with a .bak suffix.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		burrowDir, err := config.BurrowDir()
+		if err != nil {
+			return err
+		}
+		routinesDir := filepath.Join(burrowDir, "routines")
+		path, err := routinePath(routinesDir, args[0])
+		if err != nil {
+			return err
+		}
+		return editValidated(burrowDir, path, routineFileValidator(burrowDir), os.Stdin, os.Stdout)
+	},
+}
+
+var routinesSnoozeCmd = &cobra.Command{
+	Use:   "snooze <name> --until <date>",
+	Short: "Pause a routine's schedule until a date",
+	Long: "Sets snooze_until in the routine's file. The scheduler skips the routine before that date " +
+		"and resumes it on the date. Running the routine by hand still works.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		until, _ := cmd.Flags().GetString("until")
+		date, err := time.ParseInLocation("2006-01-02", until, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --until %q (must be YYYY-MM-DD)", until)
+		}
+		if !date.After(time.Now()) {
+			return fmt.Errorf("--until %s is not in the future", until)
+		}
+
+		burrowDir, err := config.BurrowDir()
+		if err != nil {
+			return err
+		}
+		path, err := routinePath(filepath.Join(burrowDir, "routines"), args[0])
+		if err != nil {
+			return err
+		}
+		if err := pipeline.SetSnoozeUntil(path, until); err != nil {
+			return err
+		}
+		fmt.Printf("Snoozed %s until %s.\n", args[0], until)
+		return nil
+	},
+}
+
+var routinesEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "Resume a snoozed routine's schedule",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		burrowDir, err := config.BurrowDir()
+		if err != nil {
+			return err
+		}
+		path, err := routinePath(filepath.Join(burrowDir, "routines"), args[0])
+		if err != nil {
+			return err
+		}
+		if err := pipeline.SetSnoozeUntil(path, ""); err != nil {
+			return err
+		}
+		fmt.Printf("Enabled %s.\n", args[0])
+		return nil
+	},
+}
+
+// routinePath returns the file for the named routine, trying .yaml then .yml.
+func routinePath(routinesDir, name string) (string, error) {
+	for _, ext := range []string{".yaml", ".yml"} {
+		path := filepath.Join(routinesDir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("routine %q not found", name)
+}
+
+// routineFileValidator returns a validator for edited routines. LoadRoutine
+// validates the routine itself; when config.yaml in burrowDir loads, its
+// llm: is also checked against the configured providers.
+func routineFileValidator(burrowDir string) func(path string) error {
+	return func(path string) error {
+		routine, err := pipeline.LoadRoutine(path)
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(burrowDir)
+		if err != nil {
+			return nil
+		}
+		return pipeline.ValidateRoutineLLM(routine, cfg)
+	}
+}
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/jcadam/burrow/pkg/config"
 	"github.com/jcadam/burrow/pkg/pipeline"
+	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/jcadam/burrow/pkg/services"
 	"github.com/jcadam/burrow/pkg/synthesis"
 )
 
@@ -122,6 +128,35 @@ func TestBuildSynthesizerPassthroughProvider(t *testing.T) {
 	}
 }
 
+func TestBuildSynthesizerFallbacks(t *testing.T) {
+	cfg := &config.Config{
+		LLM: config.LLMConfig{
+			Providers: []config.ProviderConfig{
+				{Name: "local/llama3", Type: "ollama", Model: "llama3", Privacy: "local"},
+				{Name: "cloud/gpt", Type: "openrouter", APIKey: "test-key", Model: "openai/gpt-4", Privacy: "remote"},
+			},
+			Fallbacks: []string{"local/llama3", "cloud/gpt"},
+		},
+	}
+
+	synth, err := buildSynthesizer(&pipeline.Routine{LLM: "local/llama3"}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := synth.(*synthesis.FailoverSynthesizer); !ok {
+		t.Errorf("expected FailoverSynthesizer, got %T", synth)
+	}
+
+	// A local-only routine never falls back to a remote provider.
+	synth, err = buildSynthesizer(&pipeline.Routine{LLM: "local/llama3", Privacy: "local"}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := synth.(*synthesis.LLMSynthesizer); !ok {
+		t.Errorf("expected LLMSynthesizer for a local routine, got %T", synth)
+	}
+}
+
 func TestBuildSynthesizerUnknownProvider(t *testing.T) {
 	routine := &pipeline.Routine{LLM: "nonexistent"}
 	cfg := &config.Config{}
@@ -134,3 +169,149 @@ func TestBuildSynthesizerUnknownProvider(t *testing.T) {
 		t.Errorf("expected 'not found' error, got: %v", err)
 	}
 }
+
+func TestMakeDeterministic(t *testing.T) {
+	temp := 0.7
+	routine := &pipeline.Routine{LLM: "local", Jitter: pipeline.JitterConfig{Max: 300}}
+	routine.Synthesis.Concurrency = 4
+	cfg := &config.Config{LLM: config.LLMConfig{Providers: []config.ProviderConfig{
+		{Name: "local", Type: "ollama", Temperature: &temp},
+		{Name: "other", Type: "ollama", Temperature: &temp},
+	}}}
+
+	makeDeterministic(routine, cfg)
+
+	if routine.Jitter.Enabled() || routine.Synthesis.Concurrency != 1 {
+		t.Errorf("expected no jitter and sequential stage 1, got jitter=%s concurrency=%d",
+			routine.Jitter, routine.Synthesis.Concurrency)
+	}
+	local := cfg.LLM.Providers[0]
+	if local.Temperature == nil || *local.Temperature != 0 {
+		t.Errorf("expected temperature 0, got %v", local.Temperature)
+	}
+	if local.Seed == nil || *local.Seed != deterministicSeed {
+		t.Errorf("expected seed %d, got %v", deterministicSeed, local.Seed)
+	}
+	if other := cfg.LLM.Providers[1]; *other.Temperature != 0.7 || other.Seed != nil {
+		t.Error("providers the routine doesn't use should be left alone")
+	}
+}
+
+func TestPrivateServices(t *testing.T) {
+	cfg := &config.Config{Services: []config.ServiceConfig{
+		{Name: "nws"},
+		{Name: "edgar", Private: true},
+		{Name: "sam-gov", Private: true},
+	}}
+	got := privateServices(cfg)
+	if len(got) != 2 || got[0] != "edgar" || got[1] != "sam-gov" {
+		t.Errorf("privateServices = %v, want [edgar sam-gov]", got)
+	}
+	if got := privateServices(&config.Config{Services: []config.ServiceConfig{{Name: "nws"}}}); got != nil {
+		t.Errorf("expected nil with no private services, got %v", got)
+	}
+}
+
+func TestPreviewPromptsPrintsPromptsWithoutReport(t *testing.T) {
+	registry := services.NewRegistry()
+	registry.Register(&exploreService{})
+	reportsDir := t.TempDir()
+
+	routine := &pipeline.Routine{
+		Name:      "daily",
+		LLM:       "local",
+		Report:    pipeline.ReportConfig{Title: "Daily Brief"},
+		Synthesis: pipeline.SynthesisConfig{System: "You are a careful analyst."},
+		Sources:   []pipeline.SourceConfig{{Service: "api", Tool: "search"}},
+	}
+	cfg := &config.Config{LLM: config.LLMConfig{Providers: []config.ProviderConfig{
+		{Name: "local", Type: "ollama", Endpoint: "http://127.0.0.1:1", Model: "m", Privacy: "local"},
+	}}}
+
+	var out bytes.Buffer
+	if err := previewPrompts(context.Background(), routine, cfg, registry, reportsDir, nil, nil, &out); err != nil {
+		t.Fatalf("previewPrompts: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"## Call 1", "You are a careful analyst.", "### api — search"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if entries, _ := os.ReadDir(reportsDir); len(entries) != 0 {
+		t.Errorf("expected nothing written to reports, found %d entries", len(entries))
+	}
+}
+
+func TestPreviewPromptsPassthrough(t *testing.T) {
+	routine := &pipeline.Routine{Name: "daily", LLM: "none"}
+	var out bytes.Buffer
+	if err := previewPrompts(context.Background(), routine, &config.Config{}, services.NewRegistry(), t.TempDir(), nil, nil, &out); err != nil {
+		t.Fatalf("previewPrompts: %v", err)
+	}
+	if !strings.Contains(out.String(), "passthrough") {
+		t.Errorf("expected passthrough notice, got %q", out.String())
+	}
+}
+
+func TestBuildSynthesizerRejectsRemoteForLocalRoutine(t *testing.T) {
+	routine := &pipeline.Routine{Name: "brief", LLM: "remote", Privacy: "local"}
+	cfg := &config.Config{LLM: config.LLMConfig{Providers: []config.ProviderConfig{
+		{Name: "remote", Type: "openrouter", APIKey: "k", Model: "m", Privacy: "remote"},
+	}}}
+	if _, err := buildSynthesizer(routine, cfg); err == nil || !strings.Contains(err.Error(), "requires local privacy") {
+		t.Errorf("expected local privacy error, got %v", err)
+	}
+}
+
+func TestBuildSynthesizerHeadlinesPassthrough(t *testing.T) {
+	routine := &pipeline.Routine{Report: pipeline.ReportConfig{Style: "headlines"}}
+	synth, err := buildSynthesizer(routine, &config.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := synth.(*synthesis.HeadlinesSynthesizer); !ok {
+		t.Errorf("expected HeadlinesSynthesizer, got %T", synth)
+	}
+}
+
+func TestWriteReportOutput(t *testing.T) {
+	dir := t.TempDir()
+	report := &reports.Report{
+		Dir:      dir,
+		Routine:  "brief",
+		Title:    "Morning Brief",
+		Date:     "2026-03-10",
+		Markdown: "# Morning Brief\n\n*Coverage: 2/2 sources (100%)*\n\n## News\n\n**Rates** held steady.\n",
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"out.md", "**Rates** held steady."},
+		{"out.HTML", "<strong>Rates</strong> held steady."},
+		{"out.json", `"routine": "brief"`},
+		{"out.txt", "held steady.\n"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := writeReportOutput(report, path); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), tt.want) {
+			t.Errorf("%s: expected %q in:\n%s", tt.name, tt.want, data)
+		}
+		if tt.name == "out.txt" && (strings.Contains(string(data), "\x1b[") || strings.Contains(string(data), " \n")) {
+			t.Errorf("text output should have no escape codes or padding:\n%q", data)
+		}
+	}
+
+	if err := writeReportOutput(report, filepath.Join(dir, "out.pdf")); err == nil || !strings.Contains(err.Error(), "unsupported --output extension") {
+		t.Errorf("expected unsupported extension error, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/config"
+	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(usageCmd)
+	usageCmd.Flags().String("since", "30d", "only runs newer than a duration (30d, 12h, 2w) or date (YYYY-MM-DD)")
+	usageCmd.Flags().String("routine", "", "only runs of this routine")
+	usageCmd.Flags().String("by", "routine", "group by routine, provider, day, or month")
+}
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Summarize LLM token usage and estimated cost over time",
+	Long: "Totals the usage.json files routine runs write next to their reports: LLM calls, prompt and " +
+		"completion tokens, and the estimated cost for providers with pricing configured. No network access.",
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		burrowDir, err := config.BurrowDir()
+		if err != nil {
+			return err
+		}
+
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		routineFlag, _ := cmd.Flags().GetString("routine")
+		by, _ := cmd.Flags().GetString("by")
+
+		since, err := parseSince(sinceFlag, time.Now())
+		if err != nil {
+			return err
+		}
+		all, err := reports.UsageSince(filepath.Join(burrowDir, "reports"), since)
+		if err != nil {
+			return fmt.Errorf("reading usage: %w", err)
+		}
+		if routineFlag != "" {
+			var kept []*reports.Usage
+			for _, u := range all {
+				if u.Routine == routineFlag {
+					kept = append(kept, u)
+				}
+			}
+			all = kept
+		}
+
+		rows, err := summarizeUsage(all, by)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			fmt.Printf("No LLM usage recorded since %s.\n", since.Format("2006-01-02"))
+			return nil
+		}
+
+		fmt.Printf("LLM usage since %s:\n\n", since.Format("2006-01-02"))
+		fmt.Printf("  %-24s  %5s  %6s  %12s  %12s  %10s\n", usageHeading(by), "Runs", "Calls", "Prompt", "Completion", "Est. cost")
+		fmt.Printf("  %-24s  %5s  %6s  %12s  %12s  %10s\n", "----", "----", "-----", "------", "----------", "---------")
+		var total usageRow
+		total.Key = "Total"
+		for _, r := range rows {
+			printUsageRow(r)
+			if by != "provider" {
+				total.Runs += r.Runs
+			}
+			total.Usage.Add(r.Usage)
+		}
+		if by == "provider" {
+			total.Runs = len(all)
+		}
+		fmt.Println()
+		printUsageRow(total)
+		return nil
+	},
+}
+
+// usageRow is one line of gd usage: the runs grouped under Key and their
+// combined usage.
+type usageRow struct {
+	Key   string
+	Runs  int
+	Usage reports.TokenUsage
+}
+
+// summarizeUsage groups per-run usage by routine, provider, day, or month.
+// Routines and providers are ordered by cost, then calls; days and months
+// oldest first. Grouped by provider, a run counts once under each provider
+// it used.
+func summarizeUsage(all []*reports.Usage, by string) ([]usageRow, error) {
+	rows := make(map[string]*usageRow)
+	add := func(key string, t reports.TokenUsage) {
+		r, ok := rows[key]
+		if !ok {
+			r = &usageRow{Key: key}
+			rows[key] = r
+		}
+		r.Runs++
+		r.Usage.Add(t)
+	}
+	for _, u := range all {
+		switch by {
+		case "routine":
+			add(u.Routine, u.Total)
+		case "provider":
+			for name, t := range u.Providers {
+				add(name, t)
+			}
+		case "day", "month":
+			created, ok := reports.DirTime(u.Dir)
+			if !ok {
+				created = u.Updated.Local()
+			}
+			layout := "2006-01-02"
+			if by == "month" {
+				layout = "2006-01"
+			}
+			add(created.Format(layout), u.Total)
+		default:
+			return nil, fmt.Errorf("invalid --by %q (must be routine, provider, day, or month)", by)
+		}
+	}
+
+	out := make([]usageRow, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if by == "day" || by == "month" {
+			return a.Key < b.Key
+		}
+		if a.Usage.Cost != b.Usage.Cost {
+			return a.Usage.Cost > b.Usage.Cost
+		}
+		if a.Usage.Calls != b.Usage.Calls {
+			return a.Usage.Calls > b.Usage.Calls
+		}
+		return a.Key < b.Key
+	})
+	return out, nil
+}
+
+// usageHeading is the first column's heading for a --by grouping.
+func usageHeading(by string) string {
+	switch by {
+	case "provider":
+		return "Provider"
+	case "day":
+		return "Day"
+	case "month":
+		return "Month"
+	}
+	return "Routine"
+}
+
+func printUsageRow(r usageRow) {
+	fmt.Printf("  %-24s  %5d  %6d  %12d  %12d  %10s\n",
+		r.Key, r.Runs, r.Usage.Calls, r.Usage.PromptTokens, r.Usage.CompletionTokens, formatCost(r.Usage.Cost))
+}
+
+// formatCost renders an estimated cost in dollars, or "-" when no provider
+// involved has pricing.
+func formatCost(cost float64) string {
+	switch {
+	case cost == 0:
+		return "-"
+	case cost < 0.01:
+		return fmt.Sprintf("$%.4f", cost)
+	}
+	return fmt.Sprintf("$%.2f", cost)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/reports"
+)
+
+func TestSummarizeUsage(t *testing.T) {
+	run := func(dir, routine string, providers map[string]reports.TokenUsage) *reports.Usage {
+		u := &reports.Usage{Dir: dir, Routine: routine}
+		for name, tu := range providers {
+			u.Record(name, tu)
+		}
+		return u
+	}
+	all := []*reports.Usage{
+		run("/r/2026-03-01T080000-brief", "brief", map[string]reports.TokenUsage{
+			"cloud/gpt": {Calls: 2, PromptTokens: 1000, CompletionTokens: 100, Cost: 0.02},
+		}),
+		run("/r/2026-03-02T080000-news", "news", map[string]reports.TokenUsage{
+			"local/llama3": {Calls: 5, PromptTokens: 4000, CompletionTokens: 500},
+		}),
+		run("/r/2026-04-01T080000-brief", "brief", map[string]reports.TokenUsage{
+			"local/llama3": {Calls: 1, PromptTokens: 300, CompletionTokens: 40},
+			"cloud/gpt":    {Calls: 1, PromptTokens: 500, CompletionTokens: 60, Cost: 0.01},
+		}),
+	}
+
+	rows, err := summarizeUsage(all, "routine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Costlier routines first.
+	if len(rows) != 2 || rows[0].Key != "brief" || rows[0].Runs != 2 || rows[0].Usage.Calls != 4 {
+		t.Errorf("by routine = %+v", rows)
+	}
+
+	rows, _ = summarizeUsage(all, "provider")
+	if len(rows) != 2 || rows[0].Key != "cloud/gpt" || rows[1].Key != "local/llama3" || rows[1].Runs != 2 || rows[1].Usage.PromptTokens != 4300 {
+		t.Errorf("by provider = %+v", rows)
+	}
+
+	rows, _ = summarizeUsage(all, "month")
+	if len(rows) != 2 || rows[0].Key != "2026-03" || rows[0].Runs != 2 || rows[1].Key != "2026-04" {
+		t.Errorf("by month = %+v", rows)
+	}
+
+	if _, err := summarizeUsage(all, "week"); err == nil {
+		t.Error("expected an error for --by week")
+	}
+}
+
+func TestFormatCost(t *testing.T) {
+	for cost, want := range map[float64]string{0: "-", 0.0042: "$0.0042", 1.5: "$1.50"} {
+		if got := formatCost(cost); got != want {
+			t.Errorf("formatCost(%v) = %q, want %q", cost, got, want)
+		}
+	}
+}
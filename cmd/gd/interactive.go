@@ -102,7 +102,7 @@ func runInteractive(ctx context.Context) error {
 		fmt.Println("No configuration found. Run 'gd init' to get started.")
 		return nil
 	}
-	config.ResolveEnvVars(cfg)
+	resolveEnvVars(cfg)
 
 	if err := config.Validate(cfg); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: config issue: %v\n", err)
@@ -244,6 +244,12 @@ func (s *interactiveSession) printSources() {
 		if svc.Type == "rss" {
 			fmt.Fprintf(w, "      - feed: RSS/Atom feed\n")
 		}
+		if svc.Type == "stream" {
+			fmt.Fprintf(w, "      - events: events collected from the stream\n")
+		}
+		if svc.Type == "file" {
+			fmt.Fprintf(w, "      - read: files at the endpoint path\n")
+		}
 		for _, tool := range svc.Tools {
 			desc := tool.Description
 			if desc == "" {
@@ -286,7 +292,7 @@ func (s *interactiveSession) handleView(routine string) error {
 		title = report.Routine + " — " + report.Date
 	}
 
-	opts := viewerOptions(s.cfg, s.profile)
+	opts := withReportAge(viewerOptions(s.cfg, s.profile), report)
 	if s.ledger != nil {
 		opts = append(opts, render.WithLedger(s.ledger))
 	}
@@ -370,7 +376,7 @@ func (s *interactiveSession) handleAsk(ctx context.Context, question string) {
 				return
 			}
 
-			rendered, err := render.RenderMarkdown(response, 78)
+			rendered, err := render.RenderMarkdownStyle(response, 78, renderStyle(s.cfg))
 			if err != nil {
 				fmt.Fprintln(w, response)
 			} else {
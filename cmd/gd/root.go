@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/jcadam/burrow/pkg/config"
@@ -23,6 +24,44 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// styleFlag overrides rendering.style for one invocation.
+var styleFlag string
+
+// accessibleFlag turns on rendering.accessible for one invocation.
+var accessibleFlag bool
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&styleFlag, "style", "", "Markdown style: auto, dark, light, notty, or a glamour JSON style file (overrides rendering.style)")
+	rootCmd.PersistentFlags().BoolVar(&accessibleFlag, "accessible", false, "Use plain ASCII indicators, no animation, and less color (same as rendering.accessible)")
+}
+
+// renderStyle returns the markdown style to render with: --style if given,
+// else rendering.style from cfg, which may be nil.
+func renderStyle(cfg *config.Config) string {
+	if styleFlag != "" {
+		return styleFlag
+	}
+	if cfg != nil {
+		return cfg.Rendering.Style
+	}
+	return ""
+}
+
+// accessibleMode reports whether accessible mode is on: --accessible, or
+// rendering.accessible in cfg, which may be nil.
+func accessibleMode(cfg *config.Config) bool {
+	return accessibleFlag || (cfg != nil && cfg.Rendering.Accessible)
+}
+
+// resolveEnvVars resolves credential references in cfg, warning on stderr
+// about any that could not be read. Those stay unresolved, so only the
+// services that use them fail.
+func resolveEnvVars(cfg *config.Config) {
+	if err := config.ResolveEnvVars(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: resolving credentials: %v\n", err)
+	}
+}
+
 // viewRoutineShortcut opens the latest report for a routine by name.
 // Tries exact match first, then fuzzy match via resolveReport.
 func viewRoutineShortcut(name string) error {
@@ -44,6 +83,9 @@ func viewRoutineShortcut(name string) error {
 
 	cfg, _ := loadConfigQuiet(burrowDir)
 	prof, _ := profile.Load(burrowDir)
-	opts := viewerOptions(cfg, prof)
+	opts := withReportAge(viewerOptions(cfg, prof), report)
+	if ledger, err := openLedger(); err == nil {
+		opts = append(opts, render.WithLedger(ledger))
+	}
 	return render.RunViewer(title, report.Markdown, opts...)
 }
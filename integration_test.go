@@ -536,7 +536,7 @@ func TestCachingIntegration(t *testing.T) {
 			{Name: "fetch", Method: "GET", Path: "/data"},
 		},
 	}, nil, "")
-	cached := bcache.NewCachedService(inner, cacheDir, 3600)
+	cached := bcache.NewCachedService(inner, bcache.NewDiskBackend(cacheDir), 3600)
 
 	registry := services.NewRegistry()
 	registry.Register(cached)
@@ -29,6 +29,12 @@ Keep the tone professional but natural. Be concise.`
 // GenerateDraft uses an LLM to generate a communication draft.
 // The profile parameter is optional — pass nil when no profile is available.
 func GenerateDraft(ctx context.Context, provider synthesis.Provider, instruction string, contextData string, p *profile.Profile) (*Draft, error) {
+	return GenerateDraftStream(ctx, provider, instruction, contextData, p, nil)
+}
+
+// GenerateDraftStream is GenerateDraft with the draft's text passed to fn
+// as the model writes it (see synthesis.CompleteStream).
+func GenerateDraftStream(ctx context.Context, provider synthesis.Provider, instruction string, contextData string, p *profile.Profile, fn synthesis.TokenFunc) (*Draft, error) {
 	systemPrompt := draftSystemPrompt
 	if p != nil {
 		var extra strings.Builder
@@ -54,7 +60,7 @@ func GenerateDraft(ctx context.Context, provider synthesis.Provider, instruction
 		userPrompt.WriteString(contextData)
 	}
 
-	raw, err := provider.Complete(ctx, systemPrompt, userPrompt.String())
+	raw, err := synthesis.CompleteStream(ctx, provider, systemPrompt, userPrompt.String(), fn)
 	if err != nil {
 		return nil, err
 	}
@@ -30,6 +30,12 @@ func (h *Handoff) OpenFile(path string) error {
 	return h.open(h.apps.Editor, path)
 }
 
+// OpenDefault opens a file with the platform's default application for its
+// type, regardless of the configured apps.
+func (h *Handoff) OpenDefault(path string) error {
+	return h.open("", path)
+}
+
 // OpenMailto opens a mailto: URI in the configured email app.
 func (h *Handoff) OpenMailto(to, subject, body string) error {
 	uri := BuildMailtoURI(to, subject, body)
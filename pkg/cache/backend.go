@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Backend stores serialized cache entries by key. Keys have the form
+// "<service>/<hash>". Expiry is not the backend's concern: each entry
+// records its own timestamp and TTL, and CachedService keeps expired entries
+// around so their validators can be used for revalidation.
+//
+// There is deliberately no networked backend (e.g. Redis): cached results
+// are collected data, and they stay on this machine in inspectable files or
+// in process memory.
+type Backend interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, data []byte) error
+	Delete(key string)
+}
+
+// DiskBackend stores each entry as a JSON file at <dir>/<service>/<hash>.json.
+type DiskBackend struct {
+	dir string
+}
+
+// NewDiskBackend creates a backend rooted at dir. Directories are created
+// lazily on first write.
+func NewDiskBackend(dir string) *DiskBackend {
+	return &DiskBackend{dir: dir}
+}
+
+func (d *DiskBackend) path(key string) string {
+	return filepath.Join(d.dir, filepath.FromSlash(key)+".json")
+}
+
+func (d *DiskBackend) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(d.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func (d *DiskBackend) Set(key string, data []byte) error {
+	path := d.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func (d *DiskBackend) Delete(key string) {
+	os.Remove(d.path(key))
+}
+
+// MemoryBackend keeps entries in process memory. Nothing touches disk, and
+// the cache is gone when the process exits — suited to ephemeral runs.
+type MemoryBackend struct {
+	mu      sync.RWMutex
+	entries map[string][]byte
+}
+
+// NewMemoryBackend creates an empty in-memory backend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{entries: make(map[string][]byte)}
+}
+
+func (m *MemoryBackend) Get(key string) ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.entries[key]
+	return data, ok
+}
+
+func (m *MemoryBackend) Set(key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *MemoryBackend) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackends(t *testing.T) {
+	backends := map[string]Backend{
+		"disk":   NewDiskBackend(t.TempDir()),
+		"memory": NewMemoryBackend(),
+	}
+	for name, b := range backends {
+		t.Run(name, func(t *testing.T) {
+			if _, ok := b.Get("svc/abc"); ok {
+				t.Fatal("expected miss on empty backend")
+			}
+			if err := b.Set("svc/abc", []byte("one")); err != nil {
+				t.Fatal(err)
+			}
+			if data, ok := b.Get("svc/abc"); !ok || string(data) != "one" {
+				t.Errorf("Get = %q, %v", data, ok)
+			}
+			b.Delete("svc/abc")
+			if _, ok := b.Get("svc/abc"); ok {
+				t.Error("expected miss after Delete")
+			}
+		})
+	}
+}
+
+func TestDiskBackendLayout(t *testing.T) {
+	dir := t.TempDir()
+	if err := NewDiskBackend(dir).Set("sam-gov/abc", []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sam-gov", "abc.json")); err != nil {
+		t.Errorf("expected <dir>/<service>/<hash>.json: %v", err)
+	}
+}
+
+func TestCachedServiceMemoryBackend(t *testing.T) {
+	inner := &mockService{name: "test-api", response: []byte(`{"v": 1}`)}
+	cached := NewCachedService(inner, NewMemoryBackend(), 3600)
+
+	for i := 0; i < 2; i++ {
+		result, err := cached.Execute(context.Background(), "search", map[string]string{"q": "x"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(result.Data) != `{"v": 1}` {
+			t.Errorf("unexpected data %s", result.Data)
+		}
+	}
+	if inner.callCount.Load() != 1 {
+		t.Errorf("expected second call served from memory, got %d calls", inner.callCount.Load())
+	}
+}
@@ -1,4 +1,5 @@
-// Package cache provides a file-based result caching decorator for services.
+// Package cache provides a result caching decorator for services, backed by
+// files on disk or by process memory.
 package cache
 
 import (
@@ -7,8 +8,6 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -16,45 +15,152 @@ import (
 	"github.com/jcadam/burrow/pkg/services"
 )
 
-// CachedService wraps a Service with file-based result caching.
+// CachedService wraps a Service with TTL-based result caching.
 type CachedService struct {
-	inner    services.Service
-	cacheDir string
-	ttl      time.Duration
+	inner   services.Service
+	backend Backend
+	ttl     time.Duration
+	keys    map[string]keyStrategy // per-tool cache key params; absent means all params
 }
 
-// NewCachedService wraps a service with TTL-based file caching.
-// Cache files are stored under cacheDir/<service-name>/.
-func NewCachedService(inner services.Service, cacheDir string, ttlSeconds int) *CachedService {
+// keyStrategy selects which params participate in a tool's cache key.
+type keyStrategy struct {
+	include map[string]bool // when non-nil, only these params count
+	exclude map[string]bool
+}
+
+// NewCachedService wraps a service with TTL-based caching in backend.
+// Use NewDiskBackend for the default on-disk store. With a zero TTL, calls
+// pass straight through unless a Directive sets one.
+func NewCachedService(inner services.Service, backend Backend, ttlSeconds int) *CachedService {
 	return &CachedService{
-		inner:    inner,
-		cacheDir: cacheDir,
-		ttl:      time.Duration(ttlSeconds) * time.Second,
+		inner:   inner,
+		backend: backend,
+		ttl:     time.Duration(ttlSeconds) * time.Second,
+	}
+}
+
+// SetKeyParams controls which params form the cache key for tool. With a
+// non-empty include list only those params count; otherwise params in
+// exclude are ignored. Params outside the key still reach the service —
+// they just don't distinguish cached results.
+func (c *CachedService) SetKeyParams(tool string, include, exclude []string) {
+	if c.keys == nil {
+		c.keys = make(map[string]keyStrategy)
+	}
+	var ks keyStrategy
+	if len(include) > 0 {
+		ks.include = toSet(include)
+	} else {
+		ks.exclude = toSet(exclude)
 	}
+	c.keys[tool] = ks
+}
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set
+}
+
+// keyParams returns the subset of params that identify a cached result.
+func (c *CachedService) keyParams(tool string, params map[string]string) map[string]string {
+	ks, ok := c.keys[tool]
+	if !ok {
+		return params
+	}
+	filtered := make(map[string]string, len(params))
+	for k, v := range params {
+		if ks.include != nil && !ks.include[k] {
+			continue
+		}
+		if ks.exclude[k] {
+			continue
+		}
+		filtered[k] = v
+	}
+	return filtered
 }
 
 func (c *CachedService) Name() string { return c.inner.Name() }
 
+// Directive overrides a CachedService's caching for the calls made with a
+// context carrying it, so one routine source can differ from the service.
+type Directive struct {
+	Disabled bool          // neither read nor write the cache: always query the service
+	TTL      time.Duration // freshness for this call; zero uses the service's TTL
+}
+
+type directiveKey struct{}
+
+// WithDirective returns a context whose service calls follow d.
+func WithDirective(ctx context.Context, d Directive) context.Context {
+	return context.WithValue(ctx, directiveKey{}, d)
+}
+
+// ttlFor returns the TTL for a call, and false when caching is disabled:
+// by the directive, or by a zero TTL with no directive to override it.
+func (c *CachedService) ttlFor(ctx context.Context) (time.Duration, bool) {
+	d, _ := ctx.Value(directiveKey{}).(Directive)
+	switch {
+	case d.Disabled:
+		return 0, false
+	case d.TTL > 0:
+		return d.TTL, true
+	}
+	return c.ttl, c.ttl > 0
+}
+
 // Execute checks the cache first, returning a cached result if valid.
 // On miss or expiry, calls the inner service and caches successful results.
+// When an expired entry carries ETag/Last-Modified validators and the inner
+// service supports conditional requests, the entry is revalidated instead:
+// a 304 refreshes its TTL and the cached body is served without re-downloading.
+// A Directive in ctx can disable the cache or change the TTL for the call.
 func (c *CachedService) Execute(ctx context.Context, tool string, params map[string]string) (*services.Result, error) {
-	key := cacheKey(c.inner.Name(), tool, params)
-	dir := filepath.Join(c.cacheDir, c.inner.Name())
+	ttl, enabled := c.ttlFor(ctx)
+	if !enabled {
+		return c.inner.Execute(ctx, tool, params)
+	}
+	key := c.inner.Name() + "/" + cacheKey(c.inner.Name(), tool, c.keyParams(tool, params))
 
-	// Try cache hit.
-	if result, ok := c.readCache(dir, key); ok {
-		return result, nil
+	entry, fresh := c.readCache(key, ttl)
+	if fresh {
+		return entry.result(), nil
 	}
 
-	// Cache miss — call inner service.
-	result, err := c.inner.Execute(ctx, tool, params)
+	var result *services.Result
+	var err error
+	if cond, ok := c.inner.(services.ConditionalService); ok && entry != nil && !entry.validators().IsZero() {
+		result, err = cond.ExecuteConditional(ctx, tool, params, entry.validators())
+	} else {
+		result, err = c.inner.Execute(ctx, tool, params)
+	}
 	if err != nil {
 		return result, err
 	}
 
+	if result.NotModified {
+		if entry == nil {
+			// Not revalidating, so a 304 has no body to stand in for.
+			result.Error = "unexpected 304 Not Modified without a cached entry"
+			return result, nil
+		}
+		entry.Timestamp = result.Timestamp
+		entry.TTLSeconds = int(ttl.Seconds())
+		if !result.Validators.IsZero() {
+			entry.ETag = result.Validators.ETag
+			entry.LastModified = result.Validators.LastModified
+		}
+		c.writeEntry(key, entry)
+		return entry.result(), nil
+	}
+
 	// Don't cache error results (transient failures shouldn't persist).
 	if result.Error == "" {
-		c.writeCache(dir, key, tool, params, result)
+		c.writeCache(key, tool, params, result, ttl)
 	}
 
 	return result, nil
@@ -62,13 +168,40 @@ func (c *CachedService) Execute(ctx context.Context, tool string, params map[str
 
 // cacheEntry is the JSON format stored on disk (inspectable with cat).
 type cacheEntry struct {
-	Service    string            `json:"service"`
-	Tool       string            `json:"tool"`
-	Params     map[string]string `json:"params"`
-	Timestamp  time.Time         `json:"timestamp"`
-	TTLSeconds int               `json:"ttl_seconds"`
-	Data       string            `json:"data"` // base64-encoded
-	Error      string            `json:"error"`
+	Service      string            `json:"service"`
+	Tool         string            `json:"tool"`
+	Params       map[string]string `json:"params"`
+	Timestamp    time.Time         `json:"timestamp"`
+	TTLSeconds   int               `json:"ttl_seconds"`
+	Data         string            `json:"data"` // base64-encoded
+	Error        string            `json:"error"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	ETag         string            `json:"etag,omitempty"`
+	LastModified string            `json:"last_modified,omitempty"`
+	Empty        bool              `json:"empty,omitempty"`
+	ContentType  string            `json:"content_type,omitempty"`
+	ItemsPath    string            `json:"items_path,omitempty"`
+
+	decoded []byte // Data after base64 decoding
+}
+
+func (e *cacheEntry) validators() services.Validators {
+	return services.Validators{ETag: e.ETag, LastModified: e.LastModified}
+}
+
+func (e *cacheEntry) result() *services.Result {
+	return &services.Result{
+		Service:     e.Service,
+		Tool:        e.Tool,
+		Data:        e.decoded,
+		Timestamp:   e.Timestamp,
+		Error:       e.Error,
+		Headers:     e.Headers,
+		Validators:  e.validators(),
+		Empty:       e.Empty,
+		ItemsPath:   e.ItemsPath,
+		ContentType: e.ContentType,
+	}
 }
 
 func cacheKey(service, tool string, params map[string]string) string {
@@ -89,64 +222,56 @@ func cacheKey(service, tool string, params map[string]string) string {
 	return fmt.Sprintf("%x", hash[:16]) // 32 hex chars — collision-free for practical use
 }
 
-func cacheFilePath(dir, key string) string {
-	return filepath.Join(dir, key+".json")
-}
-
-func (c *CachedService) readCache(dir, key string) (*services.Result, bool) {
-	path := cacheFilePath(dir, key)
-	data, err := os.ReadFile(path)
-	if err != nil {
+// readCache loads the entry for key. It returns the entry (nil on miss or
+// corruption) and whether it is still within ttl. Expired entries are
+// returned so their validators can be used for revalidation.
+func (c *CachedService) readCache(key string, ttl time.Duration) (*cacheEntry, bool) {
+	data, ok := c.backend.Get(key)
+	if !ok {
 		return nil, false
 	}
 
 	var entry cacheEntry
 	if err := json.Unmarshal(data, &entry); err != nil {
-		// Corrupted cache file — delete and treat as miss.
-		os.Remove(path)
-		return nil, false
-	}
-
-	// Check TTL.
-	if time.Since(entry.Timestamp) > c.ttl {
+		// Corrupted cache entry — delete and treat as miss.
+		c.backend.Delete(key)
 		return nil, false
 	}
 
 	decoded, err := base64.StdEncoding.DecodeString(entry.Data)
 	if err != nil {
-		os.Remove(path)
+		c.backend.Delete(key)
 		return nil, false
 	}
+	entry.decoded = decoded
 
-	return &services.Result{
-		Service:   entry.Service,
-		Tool:      entry.Tool,
-		Data:      decoded,
-		Timestamp: entry.Timestamp,
-		Error:     entry.Error,
-	}, true
+	// Check TTL.
+	return &entry, time.Since(entry.Timestamp) <= ttl
 }
 
-func (c *CachedService) writeCache(dir, key, tool string, params map[string]string, result *services.Result) {
-	// Lazy directory creation.
-	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return // best-effort
-	}
-
-	entry := cacheEntry{
-		Service:    c.inner.Name(),
-		Tool:       tool,
-		Params:     params,
-		Timestamp:  result.Timestamp,
-		TTLSeconds: int(c.ttl.Seconds()),
-		Data:       base64.StdEncoding.EncodeToString(result.Data),
-		Error:      result.Error,
-	}
+func (c *CachedService) writeCache(key, tool string, params map[string]string, result *services.Result, ttl time.Duration) {
+	c.writeEntry(key, &cacheEntry{
+		Service:      c.inner.Name(),
+		Tool:         tool,
+		Params:       params,
+		Timestamp:    result.Timestamp,
+		TTLSeconds:   int(ttl.Seconds()),
+		Data:         base64.StdEncoding.EncodeToString(result.Data),
+		Error:        result.Error,
+		Headers:      result.Headers,
+		ETag:         result.Validators.ETag,
+		LastModified: result.Validators.LastModified,
+		Empty:        result.Empty,
+		ContentType:  result.ContentType,
+		ItemsPath:    result.ItemsPath,
+	})
+}
 
+func (c *CachedService) writeEntry(key string, entry *cacheEntry) {
 	data, err := json.MarshalIndent(entry, "", "  ")
 	if err != nil {
 		return
 	}
 
-	os.WriteFile(cacheFilePath(dir, key), data, 0o644)
+	c.backend.Set(key, data) // best-effort
 }
@@ -16,6 +16,8 @@ import (
 type mockService struct {
 	name      string
 	response  []byte
+	empty     bool
+	mediaType string
 	err       error
 	callCount atomic.Int32
 }
@@ -27,10 +29,12 @@ func (m *mockService) Execute(_ context.Context, tool string, _ map[string]strin
 		return nil, m.err
 	}
 	return &services.Result{
-		Service:   m.name,
-		Tool:      tool,
-		Data:      m.response,
-		Timestamp: time.Now().UTC(),
+		Service:     m.name,
+		Tool:        tool,
+		Data:        m.response,
+		Timestamp:   time.Now().UTC(),
+		Empty:       m.empty,
+		ContentType: m.mediaType,
 	}, nil
 }
 
@@ -53,7 +57,7 @@ func (e *errorResultService) Execute(_ context.Context, tool string, _ map[strin
 func TestCacheMiss(t *testing.T) {
 	cacheDir := t.TempDir()
 	inner := &mockService{name: "test-api", response: []byte(`{"data": "value"}`)}
-	cached := NewCachedService(inner, cacheDir, 3600)
+	cached := NewCachedService(inner, NewDiskBackend(cacheDir), 3600)
 
 	result, err := cached.Execute(context.Background(), "search", map[string]string{"q": "test"})
 	if err != nil {
@@ -70,7 +74,7 @@ func TestCacheMiss(t *testing.T) {
 func TestCacheHit(t *testing.T) {
 	cacheDir := t.TempDir()
 	inner := &mockService{name: "test-api", response: []byte(`{"data": "value"}`)}
-	cached := NewCachedService(inner, cacheDir, 3600)
+	cached := NewCachedService(inner, NewDiskBackend(cacheDir), 3600)
 
 	// First call — miss.
 	cached.Execute(context.Background(), "search", map[string]string{"q": "test"})
@@ -92,7 +96,7 @@ func TestCacheExpired(t *testing.T) {
 	cacheDir := t.TempDir()
 	inner := &mockService{name: "test-api", response: []byte(`{"data": "value"}`)}
 	// 1-second TTL.
-	cached := NewCachedService(inner, cacheDir, 1)
+	cached := NewCachedService(inner, NewDiskBackend(cacheDir), 1)
 
 	// First call — miss.
 	cached.Execute(context.Background(), "search", map[string]string{"q": "test"})
@@ -113,7 +117,7 @@ func TestCacheExpired(t *testing.T) {
 func TestErrorNotCached(t *testing.T) {
 	cacheDir := t.TempDir()
 	inner := &errorResultService{name: "error-api"}
-	cached := NewCachedService(inner, cacheDir, 3600)
+	cached := NewCachedService(inner, NewDiskBackend(cacheDir), 3600)
 
 	// First call — error result.
 	result, _ := cached.Execute(context.Background(), "fetch", nil)
@@ -131,7 +135,7 @@ func TestErrorNotCached(t *testing.T) {
 func TestCorruptedCacheFile(t *testing.T) {
 	cacheDir := t.TempDir()
 	inner := &mockService{name: "test-api", response: []byte(`{"data": "value"}`)}
-	cached := NewCachedService(inner, cacheDir, 3600)
+	cached := NewCachedService(inner, NewDiskBackend(cacheDir), 3600)
 
 	// First call — populates cache.
 	cached.Execute(context.Background(), "search", map[string]string{"q": "test"})
@@ -160,7 +164,7 @@ func TestCorruptedCacheFile(t *testing.T) {
 func TestCacheFileIsValidJSON(t *testing.T) {
 	cacheDir := t.TempDir()
 	inner := &mockService{name: "test-api", response: []byte(`{"data": "value"}`)}
-	cached := NewCachedService(inner, cacheDir, 3600)
+	cached := NewCachedService(inner, NewDiskBackend(cacheDir), 3600)
 
 	cached.Execute(context.Background(), "search", map[string]string{"q": "test"})
 
@@ -197,7 +201,7 @@ func TestCacheFileIsValidJSON(t *testing.T) {
 func TestCacheDifferentParams(t *testing.T) {
 	cacheDir := t.TempDir()
 	inner := &mockService{name: "test-api", response: []byte(`{"data": "value"}`)}
-	cached := NewCachedService(inner, cacheDir, 3600)
+	cached := NewCachedService(inner, NewDiskBackend(cacheDir), 3600)
 
 	// Two calls with different params should both hit the inner service.
 	cached.Execute(context.Background(), "search", map[string]string{"q": "alpha"})
@@ -215,8 +219,216 @@ func TestCacheDifferentParams(t *testing.T) {
 
 func TestCacheName(t *testing.T) {
 	inner := &mockService{name: "my-api"}
-	cached := NewCachedService(inner, t.TempDir(), 3600)
+	cached := NewCachedService(inner, NewDiskBackend(t.TempDir()), 3600)
 	if cached.Name() != "my-api" {
 		t.Errorf("expected name my-api, got %q", cached.Name())
 	}
 }
+
+// conditionalService answers conditional requests with 304 when the
+// validators match its current ETag.
+type conditionalService struct {
+	mockService
+	etag          string
+	conditionals  atomic.Int32
+	lastValidator services.Validators
+}
+
+func (c *conditionalService) Execute(ctx context.Context, tool string, params map[string]string) (*services.Result, error) {
+	r, err := c.mockService.Execute(ctx, tool, params)
+	if r != nil {
+		r.Validators = services.Validators{ETag: c.etag}
+	}
+	return r, err
+}
+
+func (c *conditionalService) ExecuteConditional(ctx context.Context, tool string, params map[string]string, v services.Validators) (*services.Result, error) {
+	c.conditionals.Add(1)
+	c.lastValidator = v
+	if v.ETag == c.etag {
+		return &services.Result{Service: c.name, Tool: tool, Timestamp: time.Now().UTC(), NotModified: true}, nil
+	}
+	return c.Execute(ctx, tool, params)
+}
+
+func expireEntries(t *testing.T, dir string) {
+	t.Helper()
+	files, _ := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	for _, f := range files {
+		data, _ := os.ReadFile(f)
+		var entry cacheEntry
+		json.Unmarshal(data, &entry)
+		entry.Timestamp = time.Now().Add(-2 * time.Hour)
+		data, _ = json.Marshal(entry)
+		os.WriteFile(f, data, 0o644)
+	}
+}
+
+func TestCacheRevalidatesExpiredEntry(t *testing.T) {
+	cacheDir := t.TempDir()
+	inner := &conditionalService{mockService: mockService{name: "api", response: []byte(`{"v": 1}`)}, etag: `"abc"`}
+	cached := NewCachedService(inner, NewDiskBackend(cacheDir), 3600)
+	params := map[string]string{"q": "x"}
+
+	if _, err := cached.Execute(context.Background(), "search", params); err != nil {
+		t.Fatal(err)
+	}
+	expireEntries(t, cacheDir)
+
+	// Upstream unchanged: 304 serves the cached body and refreshes the TTL.
+	inner.response = []byte(`{"v": 2}`)
+	result, err := cached.Execute(context.Background(), "search", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inner.conditionals.Load() != 1 || inner.lastValidator.ETag != `"abc"` {
+		t.Errorf("expected one conditional request with ETag, got %d %+v", inner.conditionals.Load(), inner.lastValidator)
+	}
+	if string(result.Data) != `{"v": 1}` || result.NotModified {
+		t.Errorf("expected cached body, got %s (NotModified=%v)", result.Data, result.NotModified)
+	}
+
+	// Refreshed entry is fresh again: no upstream call at all.
+	if _, err := cached.Execute(context.Background(), "search", params); err != nil {
+		t.Fatal(err)
+	}
+	if inner.conditionals.Load() != 1 || inner.callCount.Load() != 1 {
+		t.Errorf("expected refreshed entry to be served from cache (conditionals=%d calls=%d)",
+			inner.conditionals.Load(), inner.callCount.Load())
+	}
+
+	// Upstream changed: full response replaces the entry.
+	expireEntries(t, cacheDir)
+	inner.etag = `"def"`
+	result, err = cached.Execute(context.Background(), "search", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result.Data) != `{"v": 2}` {
+		t.Errorf("expected new body, got %s", result.Data)
+	}
+}
+
+func TestCacheExpiredWithoutValidatorsRefetches(t *testing.T) {
+	cacheDir := t.TempDir()
+	inner := &conditionalService{mockService: mockService{name: "api", response: []byte(`1`)}}
+	cached := NewCachedService(inner, NewDiskBackend(cacheDir), 3600)
+
+	cached.Execute(context.Background(), "search", nil)
+	expireEntries(t, cacheDir)
+	cached.Execute(context.Background(), "search", nil)
+
+	if inner.conditionals.Load() != 0 || inner.callCount.Load() != 2 {
+		t.Errorf("expected plain refetch (conditionals=%d calls=%d)", inner.conditionals.Load(), inner.callCount.Load())
+	}
+}
+
+func TestCacheKeyExcludeVolatileParam(t *testing.T) {
+	inner := &mockService{name: "api", response: []byte(`1`)}
+	cached := NewCachedService(inner, NewMemoryBackend(), 3600)
+	cached.SetKeyParams("search", nil, []string{"nonce"})
+
+	cached.Execute(context.Background(), "search", map[string]string{"q": "x", "nonce": "1"})
+	cached.Execute(context.Background(), "search", map[string]string{"q": "x", "nonce": "2"})
+	if inner.callCount.Load() != 1 {
+		t.Errorf("excluded param should not cause a miss, got %d calls", inner.callCount.Load())
+	}
+
+	cached.Execute(context.Background(), "search", map[string]string{"q": "y", "nonce": "3"})
+	if inner.callCount.Load() != 2 {
+		t.Errorf("key param change should miss, got %d calls", inner.callCount.Load())
+	}
+}
+
+func TestCacheKeyIncludeOnly(t *testing.T) {
+	inner := &mockService{name: "api", response: []byte(`1`)}
+	cached := NewCachedService(inner, NewMemoryBackend(), 3600)
+	cached.SetKeyParams("search", []string{"q"}, nil)
+
+	cached.Execute(context.Background(), "search", map[string]string{"q": "x", "limit": "10"})
+	cached.Execute(context.Background(), "search", map[string]string{"q": "x", "limit": "20", "ts": "now"})
+	if inner.callCount.Load() != 1 {
+		t.Errorf("params outside include list should share a cache entry, got %d calls", inner.callCount.Load())
+	}
+
+	// Other tools keep the default (all params).
+	cached.Execute(context.Background(), "list", map[string]string{"limit": "10"})
+	cached.Execute(context.Background(), "list", map[string]string{"limit": "20"})
+	if inner.callCount.Load() != 3 {
+		t.Errorf("tool without strategy should key on all params, got %d calls", inner.callCount.Load())
+	}
+}
+
+func TestCacheHitPreservesEmpty(t *testing.T) {
+	inner := &mockService{name: "test-api", response: []byte(`{"results": []}`), empty: true}
+	cached := NewCachedService(inner, NewDiskBackend(t.TempDir()), 3600)
+
+	cached.Execute(context.Background(), "search", nil)
+	result, err := cached.Execute(context.Background(), "search", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if inner.callCount.Load() != 1 {
+		t.Fatalf("expected cached result, inner called %d times", inner.callCount.Load())
+	}
+	if !result.Empty {
+		t.Error("cached result lost its Empty flag")
+	}
+}
+
+func TestCacheHitPreservesContentType(t *testing.T) {
+	inner := &mockService{name: "test-api", response: []byte("%PDF-1.4"), mediaType: "application/pdf"}
+	cached := NewCachedService(inner, NewDiskBackend(t.TempDir()), 3600)
+
+	cached.Execute(context.Background(), "fetch", nil)
+	result, err := cached.Execute(context.Background(), "fetch", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if inner.callCount.Load() != 1 {
+		t.Fatalf("expected cached result, inner called %d times", inner.callCount.Load())
+	}
+	if result.ContentType != "application/pdf" {
+		t.Errorf("ContentType = %q, want application/pdf", result.ContentType)
+	}
+}
+
+func TestCacheDirectiveDisabled(t *testing.T) {
+	inner := &mockService{name: "test-api", response: []byte(`{"price": 1}`)}
+	cached := NewCachedService(inner, NewMemoryBackend(), 3600)
+	ctx := WithDirective(context.Background(), Directive{Disabled: true})
+
+	cached.Execute(context.Background(), "quote", nil)
+	cached.Execute(ctx, "quote", nil)
+	cached.Execute(ctx, "quote", nil)
+	if got := inner.callCount.Load(); got != 3 {
+		t.Errorf("expected every disabled call to reach the service, got %d calls", got)
+	}
+}
+
+func TestCacheDirectiveTTL(t *testing.T) {
+	inner := &mockService{name: "test-api", response: []byte(`{"data": "value"}`)}
+	// A zero TTL passes calls through...
+	cached := NewCachedService(inner, NewMemoryBackend(), 0)
+	cached.Execute(context.Background(), "search", nil)
+	cached.Execute(context.Background(), "search", nil)
+	if got := inner.callCount.Load(); got != 2 {
+		t.Fatalf("expected no caching without a TTL, got %d calls", got)
+	}
+
+	// ...unless the call's directive sets one.
+	ctx := WithDirective(context.Background(), Directive{TTL: time.Hour})
+	cached.Execute(ctx, "search", nil)
+	cached.Execute(ctx, "search", nil)
+	if got := inner.callCount.Load(); got != 3 {
+		t.Errorf("expected the directive TTL to cache, got %d calls", got)
+	}
+
+	// A shorter directive TTL treats the entry as expired.
+	time.Sleep(10 * time.Millisecond)
+	short := WithDirective(context.Background(), Directive{TTL: time.Millisecond})
+	cached.Execute(short, "search", nil)
+	if got := inner.callCount.Load(); got != 4 {
+		t.Errorf("expected a refetch past the directive TTL, got %d calls", got)
+	}
+}
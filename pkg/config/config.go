@@ -2,11 +2,15 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/jcadam/burrow/pkg/privacy"
 	"gopkg.in/yaml.v3"
@@ -20,18 +24,113 @@ type Config struct {
 	Apps      AppsConfig       `yaml:"apps"`
 	Rendering RenderingConfig  `yaml:"rendering"`
 	Context   ContextConfig    `yaml:"context"`
+	Cache     CacheConfig      `yaml:"cache,omitempty"`
+
+	// SynthesisDefaults are inherited by every routine that doesn't set
+	// its own.
+	SynthesisDefaults SynthesisDefaults `yaml:"synthesis_defaults,omitempty"`
 }
 
 // ServiceConfig defines an external service endpoint.
 type ServiceConfig struct {
 	Name     string       `yaml:"name"`
-	Type     string       `yaml:"type"` // rest | mcp | rss
+	Type     string       `yaml:"type"` // rest | mcp | rss | stream | file
 	Endpoint string       `yaml:"endpoint"`
 	Auth     AuthConfig   `yaml:"auth"`
 	Spec     string       `yaml:"spec,omitempty"` // OpenAPI/Swagger spec URL for auto-discovery of tool mappings
 	Tools    []ToolConfig `yaml:"tools,omitempty"`
 	CacheTTL int          `yaml:"cache_ttl,omitempty"`
-	MaxItems int          `yaml:"max_items,omitempty"` // RSS: max items to return (0 or omitted = default 20)
+	MaxItems int          `yaml:"max_items,omitempty"` // RSS: max items to return (0 or omitted = default 20); file: max files (default 50)
+	Private  bool         `yaml:"private,omitempty"`   // limits attribution stripping to services marked private
+	Retries  int          `yaml:"retries,omitempty"`   // REST: retries for 429/503 responses with Retry-After
+
+	// Stream: seconds to collect events per run (0 = default 30, max 600)
+	// and the most events to collect (0 = default 100).
+	Window    int `yaml:"window,omitempty"`
+	MaxEvents int `yaml:"max_events,omitempty"`
+
+	// Transport tunes the service's own HTTP connection pool (rest, rss,
+	// stream, and mcp over HTTP).
+	Transport TransportConfig `yaml:"transport,omitempty"`
+
+	// FollowRedirects limits which HTTP redirects are followed: all
+	// (default), same_host, or none.
+	FollowRedirects string `yaml:"follow_redirects,omitempty"`
+
+	// RateLimit caps the HTTP requests sent to the service (rest, rss, and
+	// mcp) within a window; requests over the budget wait their turn.
+	RateLimit *RateLimitConfig `yaml:"rate_limit,omitempty"`
+}
+
+// RateLimitConfig is a request budget: at most Requests in any window of
+// length Per, a duration such as 1m or 1h.
+type RateLimitConfig struct {
+	Requests int    `yaml:"requests"`
+	Per      string `yaml:"per"`
+}
+
+// Window parses Per.
+func (r RateLimitConfig) Window() (time.Duration, error) {
+	d, err := time.ParseDuration(r.Per)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid per %q (use a duration like 1s, 1m, or 1h)", r.Per)
+	}
+	return d, nil
+}
+
+// CheckRedirect returns the http.Client CheckRedirect function for the
+// service's follow_redirects policy. "all" keeps Go's default of up to 10
+// redirects. "same_host" follows only redirects that stay on the original
+// host, so credentials sent with the request never reach another host.
+// "none" follows no redirects. A refused redirect fails the request.
+func (s ServiceConfig) CheckRedirect() func(*http.Request, []*http.Request) error {
+	switch s.FollowRedirects {
+	case "same_host":
+		return func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			if from := via[0].URL.Hostname(); !strings.EqualFold(req.URL.Hostname(), from) {
+				return fmt.Errorf("redirect from %s to %s not followed (follow_redirects: same_host)", from, req.URL.Hostname())
+			}
+			return nil
+		}
+	case "none":
+		return func(req *http.Request, _ []*http.Request) error {
+			return fmt.Errorf("redirect to %s not followed (follow_redirects: none)", req.URL.Host)
+		}
+	}
+	return nil
+}
+
+// TransportConfig tunes a service's HTTP transport for services queried
+// many times per run, such as paginated APIs. Zero values keep Go's
+// defaults: unlimited connections, two idle connections per host, idle
+// connections kept indefinitely, and the system dial timeout.
+type TransportConfig struct {
+	MaxConnsPerHost     int `yaml:"max_conns_per_host,omitempty"`      // caps concurrent connections to the service
+	MaxIdleConnsPerHost int `yaml:"max_idle_conns_per_host,omitempty"` // idle connections kept for reuse
+	IdleTimeout         int `yaml:"idle_timeout,omitempty"`            // seconds an idle connection is kept
+	DialTimeout         int `yaml:"dial_timeout,omitempty"`            // seconds to establish a connection
+}
+
+// Apply sets t's tuning on tr. Unset fields leave tr unchanged.
+func (t TransportConfig) Apply(tr *http.Transport) {
+	if t.MaxConnsPerHost > 0 {
+		tr.MaxConnsPerHost = t.MaxConnsPerHost
+	}
+	if t.MaxIdleConnsPerHost > 0 {
+		tr.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+	}
+	if t.IdleTimeout > 0 {
+		tr.IdleConnTimeout = time.Duration(t.IdleTimeout) * time.Second
+	}
+	if t.DialTimeout > 0 {
+		tr.DialContext = (&net.Dialer{
+			Timeout:   time.Duration(t.DialTimeout) * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
 }
 
 // AuthConfig defines how to authenticate with a service.
@@ -43,6 +142,45 @@ type AuthConfig struct {
 	Value    string `yaml:"value,omitempty"`
 }
 
+// Apply sets a's credentials on req. It is shared by every HTTP adapter
+// that authenticates its own requests, so each method behaves the same
+// everywhere.
+func (a AuthConfig) Apply(req *http.Request) {
+	switch a.Method {
+	case "api_key":
+		paramName := a.KeyParam
+		if paramName == "" {
+			paramName = "api_key"
+		}
+		q := req.URL.Query()
+		q.Set(paramName, a.Key)
+		req.URL.RawQuery = q.Encode()
+	case "api_key_header":
+		headerName := a.KeyParam
+		if headerName == "" {
+			headerName = "X-API-Key"
+		}
+		req.Header.Set(headerName, a.Key)
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+a.Token)
+	case "user_agent":
+		req.Header.Set("User-Agent", a.Value)
+		// Signal the privacy transport to preserve this auth-required UA.
+		req.Header.Set("X-Burrow-Preserve-UA", "true")
+	}
+}
+
+// AuthFor returns the auth for a call to tool: the tool's own when it
+// overrides the service's, otherwise the service's.
+func (s ServiceConfig) AuthFor(tool string) AuthConfig {
+	for _, tc := range s.Tools {
+		if tc.Name == tool && tc.Auth != nil {
+			return *tc.Auth
+		}
+	}
+	return s.Auth
+}
+
 // ToolConfig defines a named operation on a REST service.
 type ToolConfig struct {
 	Name        string        `yaml:"name"`
@@ -51,6 +189,32 @@ type ToolConfig struct {
 	Path        string        `yaml:"path"`
 	Body        string        `yaml:"body,omitempty"` // param name whose value becomes the POST body
 	Params      []ParamConfig `yaml:"params,omitempty"`
+
+	// CaptureHeaders lists response headers (e.g. X-Total-Count, Link,
+	// X-RateLimit-Remaining) to keep alongside the result body.
+	CaptureHeaders []string `yaml:"capture_headers,omitempty"`
+
+	// CacheKey controls which params identify a cached result.
+	CacheKey *CacheKeyConfig `yaml:"cache_key,omitempty"`
+
+	// ResultsPath is a dot path to the result items (e.g. "data.items") or
+	// a result count. An empty array or object, null, or zero there marks
+	// the response as returning no results.
+	ResultsPath string `yaml:"results_path,omitempty"`
+
+	// Auth overrides the service's auth for this tool, e.g. method: none
+	// for a public endpoint or a differently scoped key (REST, and a
+	// stream service's "events" tool).
+	Auth *AuthConfig `yaml:"auth,omitempty"`
+}
+
+// CacheKeyConfig selects the params that participate in a tool's cache key.
+// Include lists the only params that count; Exclude drops volatile params
+// (timestamps, nonces) that would otherwise make every request a miss.
+// Set at most one of the two.
+type CacheKeyConfig struct {
+	Include []string `yaml:"include,omitempty"`
+	Exclude []string `yaml:"exclude,omitempty"`
 }
 
 // ParamConfig maps user-facing parameter names to API parameter names.
@@ -64,21 +228,36 @@ type ParamConfig struct {
 // LLMConfig defines available LLM providers.
 type LLMConfig struct {
 	Providers []ProviderConfig `yaml:"providers"`
+
+	// Fallbacks names providers to try in order when a routine's own
+	// provider fails during synthesis.
+	Fallbacks []string `yaml:"fallbacks,omitempty"`
 }
 
 // ProviderConfig defines a single LLM provider.
 type ProviderConfig struct {
 	Name          string   `yaml:"name"`
-	Type          string   `yaml:"type"` // ollama | llamacpp | openrouter | passthrough
+	Type          string   `yaml:"type"` // ollama | llamacpp | openrouter | openai | anthropic | passthrough
 	Endpoint      string   `yaml:"endpoint,omitempty"`
 	APIKey        string   `yaml:"api_key,omitempty"`
 	Model         string   `yaml:"model,omitempty"`
 	Privacy       string   `yaml:"privacy"`                    // local | remote
-	Timeout       int      `yaml:"timeout,omitempty"`           // Seconds; 0 means default (Ollama: 300, OpenRouter: 120)
+	Timeout       int      `yaml:"timeout,omitempty"`           // Seconds; 0 means default (Ollama: 300, others: 120)
 	ContextWindow int      `yaml:"context_window,omitempty"`    // Token limit; 0 means default (local: 8192, remote: 32768)
 	Temperature   *float64 `yaml:"temperature,omitempty"`       // nil = model default
 	TopP          *float64 `yaml:"top_p,omitempty"`             // nil = model default
-	MaxTokens     int      `yaml:"max_tokens,omitempty"`        // 0 = model default
+	MaxTokens     int      `yaml:"max_tokens,omitempty"`        // 0 = model default (Anthropic: 8192)
+	Seed          *int     `yaml:"seed,omitempty"`              // nil = random; fixed for reproducible output where supported
+
+	// Pricing estimates the cost of each call in reports' usage.json.
+	Pricing *PricingConfig `yaml:"pricing,omitempty"`
+}
+
+// PricingConfig is what a remote LLM provider charges, in US dollars per
+// million tokens.
+type PricingConfig struct {
+	Prompt     float64 `yaml:"prompt"`
+	Completion float64 `yaml:"completion"`
 }
 
 // PrivacyConfig defines privacy-related settings.
@@ -108,6 +287,42 @@ type AppsConfig struct {
 // RenderingConfig defines terminal rendering behavior.
 type RenderingConfig struct {
 	Images string `yaml:"images,omitempty"` // auto | inline | external | text
+	Style  string `yaml:"style,omitempty"`  // auto | dark | light | notty | path to a glamour JSON style
+	// Accessible replaces decorative Unicode indicators with plain ASCII,
+	// disables spinner animation, and drops color that carries meaning alone.
+	Accessible bool `yaml:"accessible,omitempty"`
+	// StaleDays is the report age in days at which the viewer shows a
+	// staleness warning. Nil means the default (3); 0 disables the warning.
+	StaleDays *int `yaml:"stale_days,omitempty"`
+}
+
+// SynthesisDefaults are report and synthesis settings applied to each
+// routine that leaves them unset. The keys match the routine's report: and
+// synthesis: settings of the same name.
+type SynthesisDefaults struct {
+	// Report settings.
+	Style          string `yaml:"style,omitempty"`
+	TLDR           *bool  `yaml:"tldr,omitempty"`
+	EmptySections  string `yaml:"empty_sections,omitempty"`
+	GenerateCharts *bool  `yaml:"generate_charts,omitempty"`
+	MaxLength      int    `yaml:"max_length,omitempty"`
+
+	// Synthesis settings.
+	System           string `yaml:"system,omitempty"`
+	Strategy         string `yaml:"strategy,omitempty"`
+	SummaryMaxWords  int    `yaml:"summary_max_words,omitempty"`
+	MaxSourceWords   int    `yaml:"max_source_words,omitempty"`
+	Concurrency      int    `yaml:"concurrency,omitempty"`
+	Preprocess       *bool  `yaml:"preprocess,omitempty"`
+	Retries          *int   `yaml:"retries,omitempty"`
+	SourceOrder      string `yaml:"source_order,omitempty"`
+	TruncationMarker string `yaml:"truncation_marker,omitempty"`
+	SanitizeSources  *bool  `yaml:"sanitize_sources,omitempty"`
+}
+
+// CacheConfig selects where cached service results are kept.
+type CacheConfig struct {
+	Backend string `yaml:"backend,omitempty"` // disk (default) | memory
 }
 
 // ContextConfig defines context ledger retention.
@@ -185,26 +400,65 @@ func extractPathPlaceholders(path string) map[string]bool {
 	return result
 }
 
+// validateAuth checks that an auth block's method is known and has the
+// credential it needs. owner names the service or tool in errors.
+func validateAuth(owner string, a AuthConfig) error {
+	switch a.Method {
+	case "api_key", "api_key_header":
+		if a.Key == "" {
+			return fmt.Errorf("%s auth method %q requires a key", owner, a.Method)
+		}
+	case "bearer":
+		if a.Token == "" {
+			return fmt.Errorf("%s auth method \"bearer\" requires a token", owner)
+		}
+	case "user_agent":
+		if a.Value == "" {
+			return fmt.Errorf("%s auth method \"user_agent\" requires a value", owner)
+		}
+	case "none", "":
+		// valid — no credentials needed
+	default:
+		return fmt.Errorf("%s has unknown auth method %q", owner, a.Method)
+	}
+	return nil
+}
+
 // envVarPattern matches both ${VAR_NAME} and $VAR_NAME forms.
 // The braced form allows any characters except }. The bare form
 // matches standard env var names: letters/underscore start, then
 // letters/digits/underscores.
 var envVarPattern = regexp.MustCompile(`\$\{([^}]+)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
 
-// ResolveEnvVars expands $VAR and ${VAR} references in credential fields from the environment.
+// ResolveEnvVars expands $VAR and ${VAR} references in credential fields from the environment,
+// and ${keyring:service/account} references from the OS credential store.
 // Only auth-related fields are resolved — credentials are never stored expanded.
-func ResolveEnvVars(cfg *Config) {
+// Every field is resolved even when some fail; the error names each keyring
+// reference that could not be read, and those references are left as is.
+func ResolveEnvVars(cfg *Config) error {
+	var errs []error
 	for i := range cfg.Services {
-		cfg.Services[i].Auth.Key = expandEnv(cfg.Services[i].Auth.Key)
-		cfg.Services[i].Auth.Token = expandEnv(cfg.Services[i].Auth.Token)
-		cfg.Services[i].Auth.Value = expandEnv(cfg.Services[i].Auth.Value)
+		expandAuth(&cfg.Services[i].Auth, &errs)
+		for _, tc := range cfg.Services[i].Tools {
+			if tc.Auth != nil {
+				expandAuth(tc.Auth, &errs)
+			}
+		}
 	}
 	for i := range cfg.LLM.Providers {
-		cfg.LLM.Providers[i].APIKey = expandEnv(cfg.LLM.Providers[i].APIKey)
+		cfg.LLM.Providers[i].APIKey = expandEnv(cfg.LLM.Providers[i].APIKey, &errs)
 	}
+	return errors.Join(errs...)
 }
 
-func expandEnv(s string) string {
+// expandAuth resolves the credential fields of an auth block in place.
+func expandAuth(a *AuthConfig, errs *[]error) {
+	a.Key = expandEnv(a.Key, errs)
+	a.Token = expandEnv(a.Token, errs)
+	a.Value = expandEnv(a.Value, errs)
+}
+
+func expandEnv(s string, errs *[]error) string {
 	return envVarPattern.ReplaceAllStringFunc(s, func(match string) string {
 		var varName string
 		if strings.HasPrefix(match, "${") {
@@ -212,6 +466,14 @@ func expandEnv(s string) string {
 		} else {
 			varName = match[1:] // strip leading $
 		}
+		if ref, ok := strings.CutPrefix(varName, keyringPrefix); ok {
+			val, err := resolveKeyring(ref)
+			if err != nil {
+				*errs = append(*errs, err)
+				return match
+			}
+			return val
+		}
 		if val, ok := os.LookupEnv(varName); ok {
 			return val
 		}
@@ -243,6 +505,19 @@ func Save(burrowDir string, cfg *Config) error {
 	return os.WriteFile(path, []byte(header+string(data)), 0o644)
 }
 
+// maxStreamWindow caps a stream service's collection window, in seconds, so
+// a routine run stays bounded.
+const maxStreamWindow = 600
+
+func hasScheme(endpoint string, schemes ...string) bool {
+	for _, scheme := range schemes {
+		if strings.HasPrefix(endpoint, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate checks internal consistency of the config.
 func Validate(cfg *Config) error {
 	names := make(map[string]bool)
@@ -256,7 +531,7 @@ func Validate(cfg *Config) error {
 		names[svc.Name] = true
 
 		switch svc.Type {
-		case "rest", "mcp", "rss":
+		case "rest", "mcp", "rss", "stream", "file":
 			// valid
 		case "":
 			return fmt.Errorf("service %q missing type", svc.Name)
@@ -268,23 +543,19 @@ func Validate(cfg *Config) error {
 			return fmt.Errorf("service %q missing endpoint", svc.Name)
 		}
 
-		switch svc.Auth.Method {
-		case "api_key", "api_key_header":
-			if svc.Auth.Key == "" {
-				return fmt.Errorf("service %q auth method %q requires a key", svc.Name, svc.Auth.Method)
+		if err := validateAuth(fmt.Sprintf("service %q", svc.Name), svc.Auth); err != nil {
+			return err
+		}
+		for _, tool := range svc.Tools {
+			if tool.Auth == nil {
+				continue
 			}
-		case "bearer":
-			if svc.Auth.Token == "" {
-				return fmt.Errorf("service %q auth method \"bearer\" requires a token", svc.Name)
+			if svc.Type != "rest" && svc.Type != "stream" {
+				return fmt.Errorf("service %q tool %q sets auth, which only REST and stream tools support", svc.Name, tool.Name)
 			}
-		case "user_agent":
-			if svc.Auth.Value == "" {
-				return fmt.Errorf("service %q auth method \"user_agent\" requires a value", svc.Name)
+			if err := validateAuth(fmt.Sprintf("service %q tool %q", svc.Name, tool.Name), *tool.Auth); err != nil {
+				return err
 			}
-		case "none", "":
-			// valid — no credentials needed
-		default:
-			return fmt.Errorf("service %q has unknown auth method %q", svc.Name, svc.Auth.Method)
 		}
 	}
 
@@ -295,6 +566,63 @@ func Validate(cfg *Config) error {
 		}
 	}
 
+	// Validate transport tuning.
+	for _, svc := range cfg.Services {
+		tc := svc.Transport
+		if tc.MaxConnsPerHost < 0 || tc.MaxIdleConnsPerHost < 0 || tc.IdleTimeout < 0 || tc.DialTimeout < 0 {
+			return fmt.Errorf("service %q has a negative transport setting", svc.Name)
+		}
+		switch svc.FollowRedirects {
+		case "", "all", "same_host", "none":
+			// valid
+		default:
+			return fmt.Errorf("service %q has invalid follow_redirects %q (must be all, same_host, or none)", svc.Name, svc.FollowRedirects)
+		}
+	}
+
+	// Validate rate limits.
+	for _, svc := range cfg.Services {
+		if svc.RateLimit == nil {
+			continue
+		}
+		switch svc.Type {
+		case "rest", "rss", "mcp":
+		default:
+			return fmt.Errorf("service %q sets rate_limit, which only rest, rss, and mcp services support", svc.Name)
+		}
+		if svc.RateLimit.Requests <= 0 {
+			return fmt.Errorf("service %q rate_limit requests must be positive", svc.Name)
+		}
+		if _, err := svc.RateLimit.Window(); err != nil {
+			return fmt.Errorf("service %q rate_limit: %w", svc.Name, err)
+		}
+	}
+
+	// Validate stream settings.
+	for _, svc := range cfg.Services {
+		if svc.Type != "stream" {
+			continue
+		}
+		if !hasScheme(svc.Endpoint, "http://", "https://", "ws://", "wss://") {
+			return fmt.Errorf("service %q endpoint must be an http(s) SSE or ws(s) websocket URL", svc.Name)
+		}
+		if svc.Window < 0 || svc.Window > maxStreamWindow {
+			return fmt.Errorf("service %q window must be between 0 and %d seconds", svc.Name, maxStreamWindow)
+		}
+		if svc.MaxEvents < 0 {
+			return fmt.Errorf("service %q has negative max_events %d", svc.Name, svc.MaxEvents)
+		}
+	}
+
+	// Validate cache key settings (any service type with configured tools).
+	for _, svc := range cfg.Services {
+		for _, tool := range svc.Tools {
+			if tool.CacheKey != nil && len(tool.CacheKey.Include) > 0 && len(tool.CacheKey.Exclude) > 0 {
+				return fmt.Errorf("service %q tool %q cache_key sets both include and exclude (use one)", svc.Name, tool.Name)
+			}
+		}
+	}
+
 	// Validate tool paths (REST services only — MCP tools are discovered from server).
 	for _, svc := range cfg.Services {
 		if svc.Type != "rest" {
@@ -330,6 +658,11 @@ func Validate(cfg *Config) error {
 						svc.Name, tool.Name, ph, ph)
 				}
 			}
+			for _, h := range tool.CaptureHeaders {
+				if strings.TrimSpace(h) == "" {
+					return fmt.Errorf("service %q tool %q has an empty capture_headers entry", svc.Name, tool.Name)
+				}
+			}
 		}
 	}
 
@@ -345,7 +678,7 @@ func Validate(cfg *Config) error {
 		provNames[prov.Name] = true
 
 		switch prov.Type {
-		case "ollama", "openrouter", "llamacpp", "passthrough", "":
+		case "ollama", "openrouter", "openai", "anthropic", "llamacpp", "passthrough", "":
 			// valid
 		default:
 			return fmt.Errorf("LLM provider %q has unknown type %q", prov.Name, prov.Type)
@@ -357,6 +690,19 @@ func Validate(cfg *Config) error {
 		default:
 			return fmt.Errorf("LLM provider %q has unknown privacy %q", prov.Name, prov.Privacy)
 		}
+		if prov.Pricing != nil && (prov.Pricing.Prompt < 0 || prov.Pricing.Completion < 0) {
+			return fmt.Errorf("LLM provider %q pricing must be non-negative", prov.Name)
+		}
+	}
+	seenFallbacks := make(map[string]bool)
+	for _, name := range cfg.LLM.Fallbacks {
+		if !provNames[name] {
+			return fmt.Errorf("llm.fallbacks names unknown provider %q", name)
+		}
+		if seenFallbacks[name] {
+			return fmt.Errorf("llm.fallbacks lists provider %q twice", name)
+		}
+		seenFallbacks[name] = true
 	}
 
 	// Validate retention config
@@ -370,6 +716,13 @@ func Validate(cfg *Config) error {
 		return fmt.Errorf("context.retention.reports must be empty or \"forever\", got %q", cfg.Context.Retention.Reports)
 	}
 
+	switch cfg.Cache.Backend {
+	case "", "disk", "memory":
+		// valid
+	default:
+		return fmt.Errorf("invalid cache.backend %q (must be disk or memory)", cfg.Cache.Backend)
+	}
+
 	if cfg.Rendering.Images != "" {
 		switch strings.ToLower(cfg.Rendering.Images) {
 		case "auto", "inline", "external", "text":
@@ -378,6 +731,17 @@ func Validate(cfg *Config) error {
 			return fmt.Errorf("invalid rendering.images value %q", cfg.Rendering.Images)
 		}
 	}
+	switch style := cfg.Rendering.Style; style {
+	case "", "auto", "dark", "light", "notty":
+		// valid
+	default:
+		if !strings.EqualFold(filepath.Ext(style), ".json") {
+			return fmt.Errorf("invalid rendering.style %q (must be auto, dark, light, notty, or a .json style file)", style)
+		}
+	}
+	if cfg.Rendering.StaleDays != nil && *cfg.Rendering.StaleDays < 0 {
+		return fmt.Errorf("rendering.stale_days must be non-negative, got %d", *cfg.Rendering.StaleDays)
+	}
 
 	// Validate proxy configuration
 	if err := privacy.ValidateProxyURL(cfg.Privacy.DefaultProxy); err != nil {
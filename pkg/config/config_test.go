@@ -136,6 +136,20 @@ func TestResolveEnvVars(t *testing.T) {
 	}
 }
 
+func TestResolveEnvVarsToolAuth(t *testing.T) {
+	t.Setenv("SCOPED_KEY", "scoped-123")
+	cfg := &Config{Services: []ServiceConfig{{
+		Name:  "api",
+		Tools: []ToolConfig{{Name: "admin", Auth: &AuthConfig{Method: "api_key", Key: "${SCOPED_KEY}"}}},
+	}}}
+	if err := ResolveEnvVars(cfg); err != nil {
+		t.Fatalf("ResolveEnvVars: %v", err)
+	}
+	if got := cfg.Services[0].Tools[0].Auth.Key; got != "scoped-123" {
+		t.Errorf("tool key = %q, want scoped-123", got)
+	}
+}
+
 func TestResolveEnvVarsUnset(t *testing.T) {
 	dir := t.TempDir()
 	writeTestConfig(t, dir, testConfig)
@@ -275,6 +289,133 @@ func TestValidateBadRenderingImages(t *testing.T) {
 	}
 }
 
+func TestValidateRenderingStyle(t *testing.T) {
+	for _, style := range []string{"", "auto", "dark", "light", "notty", "styles/burrow.json"} {
+		if err := Validate(&Config{Rendering: RenderingConfig{Style: style}}); err != nil {
+			t.Errorf("rendering.style %q rejected: %v", style, err)
+		}
+	}
+	err := Validate(&Config{Rendering: RenderingConfig{Style: "solarized"}})
+	if err == nil || !strings.Contains(err.Error(), "rendering.style") {
+		t.Errorf("expected rendering.style error, got %v", err)
+	}
+}
+
+func TestValidateCacheBackend(t *testing.T) {
+	for _, backend := range []string{"", "disk", "memory"} {
+		if err := Validate(&Config{Cache: CacheConfig{Backend: backend}}); err != nil {
+			t.Errorf("cache.backend %q rejected: %v", backend, err)
+		}
+	}
+	if err := Validate(&Config{Cache: CacheConfig{Backend: "redis"}}); err == nil {
+		t.Error("expected validation error for unsupported cache.backend")
+	}
+}
+
+func TestValidateStreamService(t *testing.T) {
+	tests := []struct {
+		svc   ServiceConfig
+		valid bool
+	}{
+		{ServiceConfig{Endpoint: "https://example.com/events"}, true},
+		{ServiceConfig{Endpoint: "wss://example.com/feed", Window: 600, MaxEvents: 50}, true},
+		{ServiceConfig{Endpoint: "ftp://example.com/feed"}, false},
+		{ServiceConfig{Endpoint: "https://example.com/events", Window: 601}, false},
+		{ServiceConfig{Endpoint: "https://example.com/events", Window: -1}, false},
+		{ServiceConfig{Endpoint: "https://example.com/events", MaxEvents: -1}, false},
+	}
+	for _, tt := range tests {
+		tt.svc.Name, tt.svc.Type = "live", "stream"
+		err := Validate(&Config{Services: []ServiceConfig{tt.svc}})
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate(%+v) = %v, want valid=%v", tt.svc, err, tt.valid)
+		}
+	}
+}
+
+func TestValidateTransport(t *testing.T) {
+	svc := ServiceConfig{Name: "api", Type: "rest", Endpoint: "https://example.com",
+		Transport: TransportConfig{MaxConnsPerHost: 8, IdleTimeout: 90, DialTimeout: 5}}
+	if err := Validate(&Config{Services: []ServiceConfig{svc}}); err != nil {
+		t.Errorf("valid transport rejected: %v", err)
+	}
+	svc.Transport.DialTimeout = -1
+	if err := Validate(&Config{Services: []ServiceConfig{svc}}); err == nil || !strings.Contains(err.Error(), "transport") {
+		t.Errorf("expected transport error, got %v", err)
+	}
+}
+
+func TestValidateToolAuth(t *testing.T) {
+	svc := ServiceConfig{
+		Name: "api", Type: "rest", Endpoint: "https://example.com",
+		Auth:  AuthConfig{Method: "bearer", Token: "t"},
+		Tools: []ToolConfig{{Name: "public", Method: "GET", Path: "/public", Auth: &AuthConfig{Method: "none"}}},
+	}
+	if err := Validate(&Config{Services: []ServiceConfig{svc}}); err != nil {
+		t.Errorf("valid tool auth rejected: %v", err)
+	}
+
+	svc.Tools[0].Auth = &AuthConfig{Method: "api_key"}
+	err := Validate(&Config{Services: []ServiceConfig{svc}})
+	if err == nil || !strings.Contains(err.Error(), `service "api" tool "public" auth method "api_key" requires a key`) {
+		t.Errorf("expected missing key error, got %v", err)
+	}
+
+	svc.Type = "stream"
+	svc.Tools = []ToolConfig{{Name: "events", Auth: &AuthConfig{Method: "none"}}}
+	if err := Validate(&Config{Services: []ServiceConfig{svc}}); err != nil {
+		t.Errorf("valid stream tool auth rejected: %v", err)
+	}
+
+	svc.Type = "mcp"
+	if err := Validate(&Config{Services: []ServiceConfig{svc}}); err == nil || !strings.Contains(err.Error(), "only REST") {
+		t.Errorf("expected REST-only error, got %v", err)
+	}
+}
+
+func TestValidateFollowRedirects(t *testing.T) {
+	svc := ServiceConfig{Name: "api", Type: "rest", Endpoint: "https://example.com", FollowRedirects: "same_host"}
+	if err := Validate(&Config{Services: []ServiceConfig{svc}}); err != nil {
+		t.Errorf("valid follow_redirects rejected: %v", err)
+	}
+	svc.FollowRedirects = "sometimes"
+	if err := Validate(&Config{Services: []ServiceConfig{svc}}); err == nil || !strings.Contains(err.Error(), "follow_redirects") {
+		t.Errorf("expected follow_redirects error, got %v", err)
+	}
+}
+
+func TestValidateRateLimit(t *testing.T) {
+	svc := ServiceConfig{Name: "api", Type: "rest", Endpoint: "https://example.com", RateLimit: &RateLimitConfig{Requests: 5, Per: "1m"}}
+	if err := Validate(&Config{Services: []ServiceConfig{svc}}); err != nil {
+		t.Errorf("valid rate_limit rejected: %v", err)
+	}
+	for _, bad := range []RateLimitConfig{{Requests: 0, Per: "1m"}, {Requests: 5, Per: "minute"}, {Requests: 5, Per: "0s"}} {
+		svc.RateLimit = &bad
+		if err := Validate(&Config{Services: []ServiceConfig{svc}}); err == nil || !strings.Contains(err.Error(), "rate_limit") {
+			t.Errorf("rate_limit %+v: expected error, got %v", bad, err)
+		}
+	}
+	svc = ServiceConfig{Name: "notes", Type: "file", Endpoint: "/tmp", RateLimit: &RateLimitConfig{Requests: 5, Per: "1m"}}
+	if err := Validate(&Config{Services: []ServiceConfig{svc}}); err == nil || !strings.Contains(err.Error(), "rate_limit") {
+		t.Errorf("expected rate_limit error for a file service, got %v", err)
+	}
+}
+
+func TestValidateCacheKeyIncludeAndExclude(t *testing.T) {
+	cfg := &Config{
+		Services: []ServiceConfig{{
+			Name: "api", Type: "rest", Endpoint: "https://example.com",
+			Tools: []ToolConfig{{
+				Name: "search", Method: "GET", Path: "/search",
+				CacheKey: &CacheKeyConfig{Include: []string{"q"}, Exclude: []string{"ts"}},
+			}},
+		}},
+	}
+	if err := Validate(cfg); err == nil || !strings.Contains(err.Error(), "cache_key") {
+		t.Errorf("expected cache_key error, got %v", err)
+	}
+}
+
 func TestValidateRelativeToolPath(t *testing.T) {
 	cfg := &Config{
 		Services: []ServiceConfig{
@@ -364,6 +505,55 @@ func TestValidateLLMProviderUnknownPrivacy(t *testing.T) {
 	}
 }
 
+func TestValidateLLMProviderPricing(t *testing.T) {
+	cfg := &Config{
+		LLM: LLMConfig{
+			Providers: []ProviderConfig{
+				{Name: "cloud/gpt", Type: "openrouter", Pricing: &PricingConfig{Prompt: 2.5, Completion: -10}},
+			},
+		},
+	}
+	err := Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "non-negative") {
+		t.Errorf("expected pricing error, got: %v", err)
+	}
+	cfg.LLM.Providers[0].Pricing.Completion = 10
+	if err := Validate(cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateLLMFallbacks(t *testing.T) {
+	providers := []ProviderConfig{
+		{Name: "local/llama3", Type: "ollama", Privacy: "local"},
+		{Name: "cloud/gpt", Type: "openrouter", Privacy: "remote"},
+	}
+	tests := []struct {
+		name      string
+		fallbacks []string
+		wantErr   string
+	}{
+		{"valid", []string{"local/llama3", "cloud/gpt"}, ""},
+		{"unknown", []string{"cloud/claude"}, "unknown provider"},
+		{"twice", []string{"cloud/gpt", "cloud/gpt"}, "twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{LLM: LLMConfig{Providers: providers, Fallbacks: tt.fallbacks}}
+			err := Validate(cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestValidateEmptyAPIKey(t *testing.T) {
 	cfg := &Config{
 		Services: []ServiceConfig{
@@ -717,6 +907,23 @@ func TestValidateRetentionNegativeDays(t *testing.T) {
 	}
 }
 
+func TestValidateRenderingNegativeStaleDays(t *testing.T) {
+	days := -1
+	cfg := &Config{Rendering: RenderingConfig{StaleDays: &days}}
+	err := Validate(cfg)
+	if err == nil {
+		t.Fatal("expected validation error for negative stale_days")
+	}
+	if !strings.Contains(err.Error(), "stale_days") {
+		t.Errorf("expected stale_days in error, got: %v", err)
+	}
+
+	days = 0
+	if err := Validate(cfg); err != nil {
+		t.Errorf("stale_days 0 should disable the warning, got: %v", err)
+	}
+}
+
 func TestValidateRetentionInvalidReports(t *testing.T) {
 	cfg := &Config{
 		Context: ContextConfig{
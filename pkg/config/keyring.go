@@ -0,0 +1,38 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// keyringPrefix marks a ${keyring:service/account} credential reference.
+const keyringPrefix = "keyring:"
+
+// keyringTimeout bounds one credential store lookup, which may wait on the
+// user to unlock the store.
+const keyringTimeout = time.Minute
+
+// errKeyringUnsupported is returned on platforms without a keyring backend.
+var errKeyringUnsupported = errors.New("no OS keyring support on this platform")
+
+// keyringLookup reads a secret from the OS credential store. The backend is
+// chosen by build tags (keyring_*.go); tests replace it. Secrets are read on
+// demand and never cached.
+var keyringLookup = lookupKeyring
+
+// resolveKeyring resolves the service/account part of a keyring reference.
+// The account follows the last slash, so a service name may contain slashes.
+func resolveKeyring(ref string) (string, error) {
+	i := strings.LastIndex(ref, "/")
+	if i <= 0 || i == len(ref)-1 {
+		return "", fmt.Errorf("invalid keyring reference ${%s%s} (want ${keyring:service/account})", keyringPrefix, ref)
+	}
+	service, account := ref[:i], ref[i+1:]
+	val, err := keyringLookup(service, account)
+	if err != nil {
+		return "", fmt.Errorf("keyring %s/%s: %w", service, account, err)
+	}
+	return val, nil
+}
@@ -0,0 +1,28 @@
+//go:build darwin
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// lookupKeyring reads a generic password from the macOS Keychain with the
+// system security tool. The command and its arguments are fixed; only the
+// service and account names come from config.
+func lookupKeyring(service, account string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), keyringTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "/usr/bin/security", "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return "", fmt.Errorf("reading Keychain: %s", strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading Keychain: %w", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
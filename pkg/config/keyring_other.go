@@ -0,0 +1,7 @@
+//go:build !darwin && !linux && !freebsd && !openbsd && !netbsd && !windows
+
+package config
+
+func lookupKeyring(service, account string) (string, error) {
+	return "", errKeyringUnsupported
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeKeyring replaces the keyring backend for one test.
+func fakeKeyring(t *testing.T, secrets map[string]string) {
+	t.Helper()
+	orig := keyringLookup
+	keyringLookup = func(service, account string) (string, error) {
+		if val, ok := secrets[service+"/"+account]; ok {
+			return val, nil
+		}
+		return "", errors.New("no matching secret")
+	}
+	t.Cleanup(func() { keyringLookup = orig })
+}
+
+func TestResolveEnvVarsKeyring(t *testing.T) {
+	fakeKeyring(t, map[string]string{
+		"burrow/sam-gov":        "sam-secret",
+		"burrow/llm/openrouter": "sk-or-keyring",
+	})
+
+	cfg := &Config{
+		Services: []ServiceConfig{
+			{Name: "sam-gov", Auth: AuthConfig{Method: "api_key", Key: "${keyring:burrow/sam-gov}"}},
+		},
+		LLM: LLMConfig{
+			Providers: []ProviderConfig{{Name: "remote", APIKey: "Bearer ${keyring:burrow/llm/openrouter}"}},
+		},
+	}
+	if err := ResolveEnvVars(cfg); err != nil {
+		t.Fatalf("ResolveEnvVars: %v", err)
+	}
+	if got := cfg.Services[0].Auth.Key; got != "sam-secret" {
+		t.Errorf("service key = %q, want sam-secret", got)
+	}
+	// The account follows the last slash.
+	if got := cfg.LLM.Providers[0].APIKey; got != "Bearer sk-or-keyring" {
+		t.Errorf("provider key = %q, want Bearer sk-or-keyring", got)
+	}
+}
+
+func TestResolveEnvVarsKeyringMissing(t *testing.T) {
+	fakeKeyring(t, nil)
+	t.Setenv("OTHER_KEY", "from-env")
+
+	cfg := &Config{
+		Services: []ServiceConfig{
+			{Name: "a", Auth: AuthConfig{Key: "${keyring:burrow/missing}"}},
+			{Name: "b", Auth: AuthConfig{Key: "${OTHER_KEY}"}},
+		},
+	}
+	err := ResolveEnvVars(cfg)
+	if err == nil || !strings.Contains(err.Error(), "keyring burrow/missing") {
+		t.Fatalf("expected error naming the key, got %v", err)
+	}
+	if got := cfg.Services[0].Auth.Key; got != "${keyring:burrow/missing}" {
+		t.Errorf("failed reference should stay unresolved, got %q", got)
+	}
+	if got := cfg.Services[1].Auth.Key; got != "from-env" {
+		t.Errorf("other fields should still resolve, got %q", got)
+	}
+}
+
+func TestResolveKeyringInvalidReference(t *testing.T) {
+	fakeKeyring(t, nil)
+	for _, ref := range []string{"burrow", "/account", "burrow/"} {
+		if _, err := resolveKeyring(ref); err == nil || !strings.Contains(err.Error(), "invalid keyring reference") {
+			t.Errorf("resolveKeyring(%q) = %v, want invalid reference error", ref, err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+//go:build linux || freebsd || openbsd || netbsd
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// lookupKeyring reads a secret from the Secret Service (GNOME Keyring,
+// KWallet) with secret-tool, matching items by their service and username
+// attributes, as other keyring libraries store them. The command and its
+// arguments are fixed; only the service and account names come from config.
+func lookupKeyring(service, account string) (string, error) {
+	path, err := exec.LookPath("secret-tool")
+	if err != nil {
+		return "", errors.New("secret-tool not found (install libsecret-tools)")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), keyringTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "lookup", "service", service, "username", account).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(bytes.TrimSpace(exitErr.Stderr)) == 0 {
+		// secret-tool exits 1 without a message when nothing matches.
+		return "", errors.New("no matching secret")
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading Secret Service: %w", err)
+	}
+	if len(out) == 0 {
+		return "", errors.New("no matching secret")
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
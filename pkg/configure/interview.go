@@ -0,0 +1,143 @@
+package configure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jcadam/burrow/pkg/profile"
+	"gopkg.in/yaml.v3"
+)
+
+// interviewTopic is one step of the guided profile interview.
+type interviewTopic struct {
+	Field    string // profile field the answer populates
+	Question string
+	Guidance string // how the LLM should structure the answer
+}
+
+// interviewTopics is the fixed question sequence for the profile interview.
+// Fields use the names the system prompt already recommends, so routines can
+// reference them directly (e.g. {{profile "competitors"}}).
+var interviewTopics = []interviewTopic{
+	{
+		Field:    "name",
+		Question: "What's your name, or the name of your organization?",
+		Guidance: "Set name to a single string.",
+	},
+	{
+		Field:    "industry",
+		Question: "What industry are you in, and what does your work focus on?",
+		Guidance: "Set industry to a short string. Also set description to a one- or two-sentence summary of what the user does.",
+	},
+	{
+		Field:    "competitors",
+		Question: "Who are your main competitors or peer organizations you want to track?",
+		Guidance: "Set competitors to a list of names, one entry per organization.",
+	},
+	{
+		Field:    "interests",
+		Question: "What topics, technologies, or markets are you most interested in following?",
+		Guidance: "Set interests to a list of short keyword phrases suitable for search queries.",
+	},
+	{
+		Field:    "locations",
+		Question: "Which locations matter to you (offices, markets, regions)?",
+		Guidance: "Set locations to a list of place names. If the user gives a primary location, also set a nested map (e.g. headquarters with city and state).",
+	},
+}
+
+// interview tracks progress through a profile interview.
+type interview struct {
+	step  int                    // index into interviewTopics of the question awaiting an answer
+	draft map[string]interface{} // accumulated profile fields
+}
+
+const interviewPromptTemplate = `
+
+## Profile Interview
+
+You are conducting a guided interview to build the user's profile, one question at a time.
+The question just asked was about the %q field:
+  %s
+
+%s
+If the user declines or has nothing to add, leave the field unset.
+
+Draft profile so far:
+%s
+Respond with a brief acknowledgement (one sentence) followed by the COMPLETE updated draft profile
+in a ` + "```yaml profile" + ` block. Keep every field already in the draft unless the user corrects it.
+Do not ask the next question — Burrow asks it.
+`
+
+// StartInterview switches the session into profile interview mode and
+// returns the first question. The draft starts from the existing profile
+// so the interview refines rather than replaces it. Each subsequent
+// ProcessMessage call treats the user's message as the answer to the
+// current question; the finished profile is returned as a ProfileChange
+// after the last answer.
+func (s *Session) StartInterview() string {
+	draft := make(map[string]interface{})
+	if s.profileCfg != nil {
+		for k, v := range s.profileCfg.Raw {
+			draft[k] = v
+		}
+	}
+	s.interview = &interview{draft: draft}
+	return interviewTopics[0].Question
+}
+
+// Interviewing reports whether a profile interview is in progress.
+func (s *Session) Interviewing() bool {
+	return s.interview != nil
+}
+
+// interviewPrompt returns the system prompt section for the current
+// interview step.
+func (s *Session) interviewPrompt() string {
+	topic := interviewTopics[s.interview.step]
+	draftYAML := "(empty)\n"
+	if len(s.interview.draft) > 0 {
+		if data, err := yaml.Marshal(s.interview.draft); err == nil {
+			draftYAML = string(data)
+		}
+	}
+	return fmt.Sprintf(interviewPromptTemplate, topic.Field, topic.Question, topic.Guidance, draftYAML)
+}
+
+// advanceInterview folds the LLM's proposed draft into the interview and
+// moves to the next question. It returns the text to append to the
+// response (the next question) and, once the last question has been
+// answered, the accumulated ProfileChange.
+func (s *Session) advanceInterview(profChange *ProfileChange) (string, *ProfileChange) {
+	iv := s.interview
+	if profChange != nil && profChange.Profile.Raw != nil {
+		iv.draft = profChange.Profile.Raw
+	}
+	iv.step++
+
+	if iv.step < len(interviewTopics) {
+		return interviewTopics[iv.step].Question, nil
+	}
+
+	s.interview = nil
+	if len(iv.draft) == 0 {
+		return "The interview is finished, but no profile fields were collected.", nil
+	}
+
+	raw, err := yaml.Marshal(iv.draft)
+	if err != nil {
+		return fmt.Sprintf("The interview is finished, but the profile could not be assembled: %v", err), nil
+	}
+	var p profile.Profile
+	if err := yaml.Unmarshal(raw, &p); err != nil {
+		return fmt.Sprintf("The interview is finished, but the profile could not be assembled: %v", err), nil
+	}
+	p.Raw = iv.draft
+
+	return "That's everything — here is your assembled profile.", &ProfileChange{
+		Description: "Profile built from interview",
+		Profile:     &p,
+		Raw:         strings.TrimSpace(string(raw)),
+	}
+}
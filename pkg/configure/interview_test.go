@@ -0,0 +1,96 @@
+package configure
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/config"
+)
+
+// scriptedProvider returns canned responses in order and records system prompts.
+type scriptedProvider struct {
+	responses []string
+	prompts   []string
+}
+
+func (p *scriptedProvider) Complete(_ context.Context, system, _ string) (string, error) {
+	p.prompts = append(p.prompts, system)
+	resp := p.responses[0]
+	p.responses = p.responses[1:]
+	return resp, nil
+}
+
+func TestInterviewAccumulatesProfile(t *testing.T) {
+	provider := &scriptedProvider{responses: []string{
+		"Got it.\n```yaml profile\nname: Trivyn\n```",
+		"Thanks.\n```yaml profile\nname: Trivyn\nindustry: geospatial\ndescription: Geospatial analytics\n```",
+		"Noted.\n```yaml profile\nname: Trivyn\nindustry: geospatial\ndescription: Geospatial analytics\ncompetitors:\n  - Maxar\n  - Planet\n```",
+		"No problem, skipping that one.",
+		"Great.\n```yaml profile\nname: Trivyn\nindustry: geospatial\ndescription: Geospatial analytics\ncompetitors:\n  - Maxar\n  - Planet\nlocations:\n  - Anchorage\n```",
+	}}
+	s := NewSession(t.TempDir(), &config.Config{}, provider)
+
+	first := s.StartInterview()
+	if first != interviewTopics[0].Question {
+		t.Errorf("first question = %q", first)
+	}
+
+	answers := []string{"Trivyn", "Geospatial analytics", "Maxar and Planet", "skip", "Anchorage"}
+	for i, answer := range answers {
+		resp, _, profChange, _, _, err := s.ProcessMessage(context.Background(), answer)
+		if err != nil {
+			t.Fatalf("answer %d: %v", i, err)
+		}
+		if !strings.Contains(provider.prompts[i], "## Profile Interview") ||
+			!strings.Contains(provider.prompts[i], interviewTopics[i].Question) {
+			t.Errorf("answer %d: system prompt missing interview step", i)
+		}
+
+		if i < len(answers)-1 {
+			if profChange != nil {
+				t.Errorf("answer %d: profile change proposed before interview finished", i)
+			}
+			if !strings.HasSuffix(resp, interviewTopics[i+1].Question) {
+				t.Errorf("answer %d: response should end with next question, got %q", i, resp)
+			}
+			continue
+		}
+
+		if profChange == nil {
+			t.Fatal("expected profile change after last answer")
+		}
+		p := profChange.Profile
+		if p.Name != "Trivyn" || p.Description != "Geospatial analytics" {
+			t.Errorf("typed fields = %q, %q", p.Name, p.Description)
+		}
+		if comps, ok := p.Raw["competitors"].([]interface{}); !ok || len(comps) != 2 {
+			t.Errorf("competitors = %v", p.Raw["competitors"])
+		}
+		if _, ok := p.Raw["locations"]; !ok {
+			t.Error("locations missing from final profile")
+		}
+	}
+
+	if s.Interviewing() {
+		t.Error("interview should end after last question")
+	}
+}
+
+func TestInterviewDraftIncludedInPrompt(t *testing.T) {
+	provider := &scriptedProvider{responses: []string{"ok"}}
+	s := NewSession(t.TempDir(), &config.Config{}, provider)
+	s.StartInterview()
+	s.interview.draft["name"] = "Trivyn"
+
+	if _, _, _, _, _, err := s.ProcessMessage(context.Background(), "Trivyn"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(provider.prompts[0], "name: Trivyn") {
+		t.Error("draft profile not included in interview prompt")
+	}
+	// A response without a profile block keeps the draft and still advances.
+	if s.interview.step != 1 || s.interview.draft["name"] != "Trivyn" {
+		t.Errorf("interview state = step %d, draft %v", s.interview.step, s.interview.draft)
+	}
+}
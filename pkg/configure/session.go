@@ -16,6 +16,8 @@ import (
 const (
 	maxHistoryTurns      = 10
 	maxConversationBytes = 8_000
+	maxSummaryLines      = 20  // one per trimmed message; oldest dropped first
+	summaryLineChars     = 160 // per-message cap in the summary
 )
 
 // Message represents a conversation turn.
@@ -30,6 +32,7 @@ type Change struct {
 	Config           *config.Config
 	Raw              string // The YAML block from LLM output
 	RemoteLLMWarning bool   // Set by ApplyChange when a new remote provider is added
+	Invalid          error  // Set when the proposal fails validation; applying it will fail
 }
 
 // ProfileChange represents a proposed profile change.
@@ -45,6 +48,7 @@ type RoutineChange struct {
 	Routine     *pipeline.Routine
 	Raw         string // The YAML block from LLM output
 	IsNew       bool   // True if this is a new routine, false if updating existing
+	Invalid     error  // Set when the proposal fails validation; applying it will fail unless only its llm: is unresolved
 }
 
 // Session provides LLM-driven conversational configuration.
@@ -55,7 +59,12 @@ type Session struct {
 	routines   []*pipeline.Routine
 	provider   synthesis.Provider
 	history    []Message
+	summary    []string                // condensed lines for turns trimmed from history
 	specCache  map[string]*FetchedSpec // keyed by service name
+	interview  *interview              // non-nil while a profile interview is in progress
+	autoApply  bool                    // apply low-risk changes without confirmation
+	style      string                  // markdown style for rendering replies in the TUI
+	accessible bool                    // no spinner animation in the TUI
 }
 
 // NewSession creates a new conversational configuration session.
@@ -76,6 +85,296 @@ func NewSession(burrowDir string, cfg *config.Config, provider synthesis.Provide
 	}
 }
 
+// SetAutoApply makes the session apply low-risk changes — profile edits,
+// routine creations and updates, and config changes that only add tool
+// mappings to existing services — without asking. Anything else, including
+// any change to a service's endpoint or auth, an LLM provider, or the
+// privacy block, still requires confirmation.
+func (s *Session) SetAutoApply(auto bool) {
+	s.autoApply = auto
+}
+
+// SetRenderStyle sets the markdown style the TUI renders replies in (see
+// render.RenderMarkdownStyle). Empty uses the default.
+func (s *Session) SetRenderStyle(style string) {
+	s.style = style
+}
+
+// SetAccessible turns on accessible mode: the TUI shows a static
+// "Thinking..." in place of the animated spinner.
+func (s *Session) SetAccessible(accessible bool) {
+	s.accessible = accessible
+}
+
+// confirmationReason explains why a proposed config change needs
+// confirmation even in auto-apply mode, or returns "" for a low-risk change.
+// Low risk is an allowlist: the only config change applied unasked is one
+// that adds tool mappings (without their own auth) to existing services.
+// Everything else can redirect requests, credentials, or collected data,
+// so the user sees it first.
+func (s *Session) confirmationReason(change *Change) string {
+	current := s.cfg
+	if current == nil {
+		current = &config.Config{}
+	}
+	// Compare against what ApplyChange will save: the LLM only ever sees
+	// redacted credentials, so restore them before looking for changes.
+	proposed := change.Config.DeepCopy()
+	restoreCredentials(current, proposed)
+
+	var reasons []string
+	if hasNewRemoteProvider(current, proposed) {
+		reasons = append(reasons, "adds a remote LLM provider")
+	}
+
+	curSvc := make(map[string]config.ServiceConfig, len(current.Services))
+	for _, svc := range current.Services {
+		curSvc[svc.Name] = svc
+	}
+	var addedSvc, changedSvc []string
+	for _, svc := range proposed.Services {
+		cur, ok := curSvc[svc.Name]
+		switch {
+		case !ok:
+			addedSvc = append(addedSvc, svc.Name)
+		case !onlyAddsTools(cur, svc):
+			changedSvc = append(changedSvc, svc.Name)
+		}
+	}
+	if len(addedSvc) > 0 {
+		reasons = append(reasons, "adds service "+strings.Join(addedSvc, ", "))
+	}
+	if len(changedSvc) > 0 {
+		reasons = append(reasons, "changes service "+strings.Join(changedSvc, ", "))
+	}
+
+	curProv := make(map[string]config.ProviderConfig, len(current.LLM.Providers))
+	for _, p := range current.LLM.Providers {
+		curProv[p.Name] = p
+	}
+	var addedProv, changedProv []string
+	for _, p := range proposed.LLM.Providers {
+		cur, ok := curProv[p.Name]
+		switch {
+		case !ok && p.Privacy != "remote": // remote ones are reported above
+			addedProv = append(addedProv, p.Name)
+		case ok && !sameYAML(cur, p):
+			changedProv = append(changedProv, p.Name)
+		}
+	}
+	if len(addedProv) > 0 {
+		reasons = append(reasons, "adds LLM provider "+strings.Join(addedProv, ", "))
+	}
+	if len(changedProv) > 0 {
+		reasons = append(reasons, "changes LLM provider "+strings.Join(changedProv, ", "))
+	}
+
+	services, providers := s.removals(change)
+	if len(services) > 0 {
+		reasons = append(reasons, "removes service "+strings.Join(services, ", "))
+	}
+	if len(providers) > 0 {
+		reasons = append(reasons, "removes LLM provider "+strings.Join(providers, ", "))
+	}
+
+	if !sameYAML(current.Privacy, proposed.Privacy) {
+		reasons = append(reasons, "changes privacy settings")
+	}
+	var other []string
+	for _, sec := range []struct {
+		name     string
+		cur, new any
+	}{
+		{"llm.fallbacks", current.LLM.Fallbacks, proposed.LLM.Fallbacks},
+		{"apps", current.Apps, proposed.Apps},
+		{"rendering", current.Rendering, proposed.Rendering},
+		{"context", current.Context, proposed.Context},
+		{"cache", current.Cache, proposed.Cache},
+		{"synthesis_defaults", current.SynthesisDefaults, proposed.SynthesisDefaults},
+	} {
+		if !sameYAML(sec.cur, sec.new) {
+			other = append(other, sec.name)
+		}
+	}
+	if len(other) > 0 {
+		reasons = append(reasons, "changes "+strings.Join(other, ", ")+" settings")
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// routineConfirmationReason explains why a proposed routine change needs
+// confirmation even in auto-apply mode, or returns "" for a low-risk one.
+// A routine is data flow, so what needs the user's eye is a change in where
+// its collected results go or what they include: an llm: that now names a
+// remote provider, a privacy: setting that changes, or a source on a service
+// the routine didn't use before that is private or, for an existing
+// routine, reached over the network.
+func (s *Session) routineConfirmationReason(change *RoutineChange) string {
+	current := s.cfg
+	if current == nil {
+		current = &config.Config{}
+	}
+	proposed := change.Routine
+	old := &pipeline.Routine{}
+	existing := false
+	for _, r := range s.routines {
+		if r.Name == proposed.Name {
+			old, existing = r, true
+			break
+		}
+	}
+
+	var reasons []string
+	if proposed.LLM != old.LLM {
+		for _, p := range current.LLM.Providers {
+			if p.Name == proposed.LLM && p.Privacy == "remote" {
+				reasons = append(reasons, fmt.Sprintf("sends its results to remote LLM provider %s", p.Name))
+				break
+			}
+		}
+	}
+	if existing && proposed.Privacy != old.Privacy {
+		reasons = append(reasons, fmt.Sprintf("changes privacy from %s to %s", privacyLabel(old.Privacy), privacyLabel(proposed.Privacy)))
+	}
+
+	used := make(map[string]bool, len(old.Sources))
+	for _, src := range old.Sources {
+		used[src.Service] = true
+	}
+	svcs := make(map[string]config.ServiceConfig, len(current.Services))
+	for _, svc := range current.Services {
+		svcs[svc.Name] = svc
+	}
+	var private, remote []string
+	for _, src := range proposed.Sources {
+		svc, ok := svcs[src.Service]
+		if used[src.Service] || !ok {
+			continue
+		}
+		used[src.Service] = true
+		switch {
+		case svc.Private:
+			private = append(private, svc.Name)
+		case existing && svc.Type != "file":
+			remote = append(remote, svc.Name)
+		}
+	}
+	if len(private) > 0 {
+		reasons = append(reasons, "reads private service "+strings.Join(private, ", "))
+	}
+	if len(remote) > 0 {
+		reasons = append(reasons, "adds remote service "+strings.Join(remote, ", "))
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// privacyLabel names a routine privacy: value for a confirmation prompt.
+func privacyLabel(p string) string {
+	if p == "" {
+		return "unset"
+	}
+	return p
+}
+
+// onlyAddsTools reports whether proposed differs from the current service
+// only by new tool mappings that don't carry their own auth.
+func onlyAddsTools(current, proposed config.ServiceConfig) bool {
+	existing := make(map[string]bool, len(current.Tools))
+	for _, t := range current.Tools {
+		existing[t.Name] = true
+	}
+	var kept []config.ToolConfig
+	for _, t := range proposed.Tools {
+		if existing[t.Name] {
+			kept = append(kept, t)
+			continue
+		}
+		if t.Auth != nil {
+			return false
+		}
+	}
+	proposed.Tools = kept
+	return sameYAML(current, proposed)
+}
+
+// sameYAML reports whether two values serialize to the same YAML, the way
+// ApplyChange detects an unchanged config.
+func sameYAML(a, b any) bool {
+	ay, _ := yaml.Marshal(a)
+	by, _ := yaml.Marshal(b)
+	return string(ay) == string(by)
+}
+
+// removals returns the services and LLM providers in the current config
+// that a proposed change drops.
+func (s *Session) removals(change *Change) (services, providers []string) {
+	if s.cfg == nil {
+		return nil, nil
+	}
+	var before, after []string
+	for _, svc := range s.cfg.Services {
+		before = append(before, svc.Name)
+	}
+	for _, svc := range change.Config.Services {
+		after = append(after, svc.Name)
+	}
+	services = removedNames(before, after)
+	before, after = nil, nil
+	for _, p := range s.cfg.LLM.Providers {
+		before = append(before, p.Name)
+	}
+	for _, p := range change.Config.LLM.Providers {
+		after = append(after, p.Name)
+	}
+	return services, removedNames(before, after)
+}
+
+// deletionPrompt returns the second confirmation a change that removes
+// services or providers needs, listing exactly what goes, or "" when it
+// removes nothing. A single "y" under-communicates a deletion.
+func (s *Session) deletionPrompt(change *Change) string {
+	services, providers := s.removals(change)
+	var parts []string
+	if len(services) > 0 {
+		parts = append(parts, describeNames("service", services))
+	}
+	if len(providers) > 0 {
+		parts = append(parts, describeNames("provider", providers))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("This removes %s. Confirm deletion? (y/n)", strings.Join(parts, " and "))
+}
+
+// describeNames lists quoted names after a singular or plural noun, e.g.
+// "service 'edgar'" or "services 'edgar', 'sam-gov'".
+func describeNames(noun string, names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = "'" + n + "'"
+	}
+	if len(names) > 1 {
+		noun += "s"
+	}
+	return noun + " " + strings.Join(quoted, ", ")
+}
+
+// removedNames returns the names in before that are missing from after.
+func removedNames(before, after []string) []string {
+	kept := make(map[string]bool, len(after))
+	for _, n := range after {
+		kept[n] = true
+	}
+	var removed []string
+	for _, n := range before {
+		if !kept[n] {
+			removed = append(removed, n)
+		}
+	}
+	return removed
+}
+
 const configSystemPrompt = `You are Burrow's configuration assistant. Help the user configure their Burrow installation.
 
 Current configuration (YAML):
@@ -108,7 +407,7 @@ Rules:
 - Sources reference services from config.yaml by name
 - When updating an existing routine, output the COMPLETE routine with ALL fields — omitted fields will be reset to defaults
 - Never remove routine fields the user didn't ask to change
-- Available routine fields: schedule (cron), timezone, jitter (seconds), llm (provider name), report (title, style, generate_charts (default: true), max_length, compare_with), synthesis.system (system prompt for LLM), sources (list of service, tool, params, context_label)
+- Available routine fields: schedule (cron), timezone, jitter (seconds), llm (provider name), report (title, style, generate_charts (default: true), max_length, compare_with), synthesis.system (system prompt for LLM), sources (list of service, tool, params, context_label, tags)
 - Source params must be key-value string pairs, e.g. params: {q: "search term", limit: "10"} — values are always plain strings, never arrays or nested objects
 - Example routine source:
     sources:
@@ -119,11 +418,13 @@ Rules:
           limit: "10"
         context_label: search results
 - Use ${ENV_VAR} syntax for credentials — never store raw secrets
-- Valid service types: rest, mcp, rss
+- Valid service types: rest, mcp, rss, stream, file
 - RSS services use type: rss with the feed URL as endpoint. No tools config needed — they auto-provide a 'feed' tool. Optional: max_items (default 20)
+- File services use type: file with a local base path as endpoint (~/ allowed). They always provide a 'read' tool for the endpoint itself; tools may add a path (relative to the endpoint) naming a file, directory, or glob, with {param} placeholders filled from source params, e.g. path: exports/sales-{date}.csv with params: {date: "{{today}}"}. CSV becomes JSON. Optional: max_items (max files, default 50)
+- Stream services use type: stream with an SSE (http/https) or websocket (ws/wss) URL as endpoint. No tools config needed — they auto-provide an 'events' tool. Optional: window in seconds (default 30, max 600), max_events (default 100)
 - Valid auth methods: api_key, api_key_header, bearer, user_agent, none
 - For api_key and api_key_header auth: use key_param to set the actual query parameter or header name if the API doesn't use the default ("api_key" for api_key, "X-API-Key" for api_key_header). Example: Alpha Vantage uses key_param: apikey
-- Valid LLM types: ollama, openrouter, llamacpp, passthrough
+- Valid LLM types: ollama, openrouter, openai (any OpenAI-compatible endpoint; endpoint required, api_key optional), anthropic, llamacpp, passthrough
 - Valid privacy values: local, remote
 - All tool paths must start with /
 - Tool params support an "in" field: "path" or "query" (default: "query")
@@ -176,17 +477,16 @@ func (s *Session) ProcessMessage(ctx context.Context, userMsg string) (string, *
 	s.history = append(s.history, Message{Role: "user", Content: userMsg})
 	s.trimHistory()
 
-	// Build the full conversation as a user prompt
-	var conversationBuilder strings.Builder
-	for _, m := range s.history {
-		conversationBuilder.WriteString(fmt.Sprintf("[%s]: %s\n\n", m.Role, m.Content))
-	}
+	conversation := s.buildConversation()
 
 	// Fetch specs for any services with spec URLs (best-effort, cached).
 	s.fetchServiceSpecs(ctx)
 
 	systemPrompt := s.buildSystemPrompt()
-	response, err := s.provider.Complete(ctx, systemPrompt, conversationBuilder.String())
+	if s.interview != nil {
+		systemPrompt += s.interviewPrompt()
+	}
+	response, err := s.provider.Complete(ctx, systemPrompt, conversation)
 	if err != nil {
 		return "", nil, nil, nil, nil, fmt.Errorf("LLM error: %w", err)
 	}
@@ -215,6 +515,15 @@ func (s *Session) ProcessMessage(ctx context.Context, userMsg string) (string, *
 		}
 	}
 
+	// During an interview, profile blocks are drafts: accumulate them and
+	// only propose a change once the last question has been answered.
+	if s.interview != nil {
+		var next string
+		next, profChange = s.advanceInterview(profChange)
+		response += "\n\n" + next
+		s.history[len(s.history)-1].Content += "\n\n" + next
+	}
+
 	// Check for routine YAML block (```yaml routine <name> ... ```)
 	var routineChange *RoutineChange
 	if routineBlock, routineName := extractRoutineYAMLBlock(response); routineBlock != "" {
@@ -238,6 +547,7 @@ func (s *Session) ProcessMessage(ctx context.Context, userMsg string) (string, *
 				Routine:     &r,
 				Raw:         routineBlock,
 				IsNew:       isNew,
+				Invalid:     pipeline.ValidateRoutine(&r),
 			}
 		}
 	}
@@ -257,18 +567,48 @@ func (s *Session) ProcessMessage(ctx context.Context, userMsg string) (string, *
 				Config:      proposed,
 				Raw:         yamlBlock,
 			}
+			change.Invalid = s.validateChange(change)
+		}
+	}
+
+	// Check the routine's llm: against the config it will run with: the
+	// proposed one when this reply also changes config.
+	if routineChange != nil && routineChange.Invalid == nil {
+		cfg := s.cfg
+		if change != nil && change.Invalid == nil {
+			cfg = change.Config
 		}
+		routineChange.Invalid = pipeline.ValidateRoutineLLM(routineChange.Routine, cfg)
 	}
 
 	return response, change, profChange, routineChange, warnings, nil
 }
 
+// buildConversation assembles the user prompt: a condensed summary of
+// trimmed turns, if any, followed by the retained history verbatim.
+func (s *Session) buildConversation() string {
+	var b strings.Builder
+	if len(s.summary) > 0 {
+		b.WriteString("[earlier conversation, summarized]:\n")
+		for _, line := range s.summary {
+			b.WriteString("- " + line + "\n")
+		}
+		b.WriteString("\n")
+	}
+	for _, m := range s.history {
+		b.WriteString(fmt.Sprintf("[%s]: %s\n\n", m.Role, m.Content))
+	}
+	return b.String()
+}
+
 // trimHistory caps conversation history to prevent unbounded growth.
 // The system prompt already contains current config state, so old turns
-// about already-applied changes are redundant.
+// about already-applied changes are redundant; they are condensed into
+// s.summary rather than dropped outright so the thread stays coherent.
 func (s *Session) trimHistory() {
 	// Hard cap: keep at most maxHistoryTurns * 2 messages (user+assistant pairs).
 	if max := maxHistoryTurns * 2; len(s.history) > max {
+		s.summarize(s.history[:len(s.history)-max])
 		s.history = s.history[len(s.history)-max:]
 	}
 	// Soft cap: drop oldest pairs until under byte budget.
@@ -280,10 +620,31 @@ func (s *Session) trimHistory() {
 		if size <= maxConversationBytes {
 			break
 		}
+		s.summarize(s.history[:2])
 		s.history = s.history[2:] // drop oldest user+assistant pair
 	}
 }
 
+// summarize appends a one-line digest of each trimmed message to s.summary,
+// keeping at most maxSummaryLines.
+func (s *Session) summarize(trimmed []Message) {
+	for _, m := range trimmed {
+		s.summary = append(s.summary, m.Role+": "+condense(m.Content, summaryLineChars))
+	}
+	if len(s.summary) > maxSummaryLines {
+		s.summary = s.summary[len(s.summary)-maxSummaryLines:]
+	}
+}
+
+// condense collapses whitespace in text and truncates it to n runes.
+func condense(text string, n int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if r := []rune(text); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return text
+}
+
 // ApplyProfileChange saves a proposed profile change.
 func (s *Session) ApplyProfileChange(change *ProfileChange) error {
 	if err := profile.Save(s.burrowDir, change.Profile); err != nil {
@@ -433,6 +794,14 @@ func (s *Session) ApplyChange(change *Change) error {
 	return nil
 }
 
+// validateChange checks a proposed config the way ApplyChange will, without
+// mutating it: credentials are restored on a copy before validation.
+func (s *Session) validateChange(change *Change) error {
+	proposed := change.Config.DeepCopy()
+	restoreCredentials(s.cfg, proposed)
+	return config.Validate(proposed)
+}
+
 // protectFromFragmentWipe restores config sections that the LLM accidentally
 // zeroed. When the LLM returns a partial YAML fragment with empty stubs
 // (e.g. `services: []`, `llm: {}`, `llm: {providers: []}`), Unmarshal
@@ -479,11 +848,17 @@ func restoreCredentials(src, dst *config.Config) {
 		if !ok {
 			continue
 		}
-		if s.Auth.Key != "" {
-			dst.Services[i].Auth.Key = s.Auth.Key
-		}
-		if s.Auth.Token != "" {
-			dst.Services[i].Auth.Token = s.Auth.Token
+		restoreAuth(&s.Auth, &dst.Services[i].Auth)
+		for j := range dst.Services[i].Tools {
+			dt := &dst.Services[i].Tools[j]
+			if dt.Auth == nil {
+				continue
+			}
+			for _, st := range s.Tools {
+				if st.Name == dt.Name && st.Auth != nil {
+					restoreAuth(st.Auth, dt.Auth)
+				}
+			}
 		}
 	}
 
@@ -503,6 +878,16 @@ func restoreCredentials(src, dst *config.Config) {
 	}
 }
 
+// restoreAuth copies src's credential fields into dst where src has them.
+func restoreAuth(src, dst *config.AuthConfig) {
+	if src.Key != "" {
+		dst.Key = src.Key
+	}
+	if src.Token != "" {
+		dst.Token = src.Token
+	}
+}
+
 // hasNewRemoteProvider checks if the proposed config introduces a remote LLM
 // provider that wasn't in the current config.
 func hasNewRemoteProvider(current, proposed *config.Config) bool {
@@ -527,13 +912,12 @@ func hasNewRemoteProvider(current, proposed *config.Config) bool {
 func redactConfig(cfg *config.Config) *config.Config {
 	c := cfg.DeepCopy()
 	for i := range c.Services {
-		if c.Services[i].Auth.Key != "" {
-			c.Services[i].Auth.Key = "${REDACTED}"
-		}
-		if c.Services[i].Auth.Token != "" {
-			c.Services[i].Auth.Token = "${REDACTED}"
+		redactAuth(&c.Services[i].Auth)
+		for _, tc := range c.Services[i].Tools {
+			if tc.Auth != nil {
+				redactAuth(tc.Auth)
+			}
 		}
-		// Auth.Value (user-agent) is not a secret — leave it visible.
 	}
 	for i := range c.LLM.Providers {
 		if c.LLM.Providers[i].APIKey != "" {
@@ -543,6 +927,17 @@ func redactConfig(cfg *config.Config) *config.Config {
 	return c
 }
 
+// redactAuth replaces an auth block's secrets with a placeholder.
+func redactAuth(a *config.AuthConfig) {
+	if a.Key != "" {
+		a.Key = "${REDACTED}"
+	}
+	if a.Token != "" {
+		a.Token = "${REDACTED}"
+	}
+	// Value (user-agent) is not a secret — leave it visible.
+}
+
 // stripCodeBlocks removes yaml/yml fenced code blocks from text, replacing
 // each with a short placeholder. This keeps conversation history compact
 // since the system prompt already carries the authoritative config state.
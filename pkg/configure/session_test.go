@@ -2,6 +2,7 @@ package configure
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -384,6 +385,33 @@ func TestRedactConfig(t *testing.T) {
 	}
 }
 
+func TestRedactConfigToolAuth(t *testing.T) {
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{{
+			Name: "api", Type: "rest", Endpoint: "https://api.example.com",
+			Auth: config.AuthConfig{Method: "none"},
+			Tools: []config.ToolConfig{{
+				Name: "private", Method: "GET", Path: "/private",
+				Auth: &config.AuthConfig{Method: "bearer", Token: "tool-secret"},
+			}},
+		}},
+	}
+
+	redacted := redactConfig(cfg)
+	if got := redacted.Services[0].Tools[0].Auth.Token; got != "${REDACTED}" {
+		t.Errorf("tool token not redacted: %q", got)
+	}
+	if got := cfg.Services[0].Tools[0].Auth.Token; got != "tool-secret" {
+		t.Errorf("original was mutated: %q", got)
+	}
+
+	// A proposed config echoing the placeholder gets the real token back.
+	restoreCredentials(cfg, redacted)
+	if got := redacted.Services[0].Tools[0].Auth.Token; got != "tool-secret" {
+		t.Errorf("tool token not restored: %q", got)
+	}
+}
+
 func TestSessionHistory(t *testing.T) {
 	provider := &fakeProvider{response: "Got it."}
 	cfg := &config.Config{}
@@ -907,6 +935,72 @@ This routine will run every morning at 7 AM.`
 	if !routineChange.IsNew {
 		t.Error("expected IsNew to be true")
 	}
+	if routineChange.Invalid != nil {
+		t.Errorf("valid routine flagged invalid: %v", routineChange.Invalid)
+	}
+}
+
+func TestProcessMessageChecksRoutineLLM(t *testing.T) {
+	routine := "```yaml routine brief\nllm: local/llama3\nreport:\n  title: Brief\nsources:\n  - service: news\n    tool: search\n```\n"
+	provider := "```yaml\nllm:\n  providers:\n    - name: local/llama3\n      type: ollama\n      endpoint: http://localhost:11434\n      model: llama3\n      privacy: local\n```\n"
+
+	// Alone, the routine names a provider the config doesn't have.
+	session := NewSession(t.TempDir(), &config.Config{}, &fakeProvider{response: routine})
+	_, _, _, routineChange, _, err := session.ProcessMessage(context.Background(), "add a brief")
+	if err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	if routineChange == nil || routineChange.Invalid == nil || !strings.Contains(routineChange.Invalid.Error(), "local/llama3") {
+		t.Fatalf("expected missing provider flagged, got %+v", routineChange)
+	}
+
+	// Proposed alongside the provider, it checks against the proposed config.
+	session = NewSession(t.TempDir(), &config.Config{}, &fakeProvider{response: provider + routine})
+	_, _, _, routineChange, _, err = session.ProcessMessage(context.Background(), "add ollama and a brief")
+	if err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	if routineChange == nil || routineChange.Invalid != nil {
+		t.Errorf("routine using the proposed provider flagged invalid: %+v", routineChange)
+	}
+}
+
+func TestProcessMessagePrevalidatesProposals(t *testing.T) {
+	response := "Adding a service.\n\n```yaml\nservices:\n  - name: x\n    type: rest\n```\n\n" +
+		"And a routine.\n\n```yaml routine broken\nsources:\n  - tool: search\n```\n"
+	session := NewSession(t.TempDir(), &config.Config{}, &fakeProvider{response: response})
+
+	_, change, _, routineChange, _, err := session.ProcessMessage(context.Background(), "add x")
+	if err != nil {
+		t.Fatalf("ProcessMessage: %v", err)
+	}
+	if change == nil || change.Invalid == nil {
+		t.Fatal("expected config change flagged invalid")
+	}
+	if !strings.Contains(change.Invalid.Error(), "missing endpoint") {
+		t.Errorf("Invalid = %v, want missing endpoint", change.Invalid)
+	}
+	if routineChange == nil || routineChange.Invalid == nil {
+		t.Fatal("expected routine change flagged invalid")
+	}
+}
+
+func TestValidateChangeRestoresCredentialsOnCopy(t *testing.T) {
+	cfg := &config.Config{Services: []config.ServiceConfig{{
+		Name: "api", Type: "rest", Endpoint: "https://example.com",
+		Auth: config.AuthConfig{Method: "api_key", Key: "secret"},
+	}}}
+	session := NewSession(t.TempDir(), cfg, &fakeProvider{})
+
+	proposed := cfg.DeepCopy()
+	proposed.Services[0].Auth.Key = ""
+	change := &Change{Config: proposed}
+	if err := session.validateChange(change); err != nil {
+		t.Errorf("validateChange: %v", err)
+	}
+	if change.Config.Services[0].Auth.Key != "" {
+		t.Error("validateChange must not mutate the proposal")
+	}
 }
 
 func TestApplyRoutineChange(t *testing.T) {
@@ -1141,6 +1235,49 @@ func TestSessionHistoryByteBudget(t *testing.T) {
 	}
 }
 
+func TestSessionHistorySummarizesTrimmedTurns(t *testing.T) {
+	provider := &capturingProvider{response: "Noted."}
+	session := NewSession(t.TempDir(), &config.Config{}, provider)
+
+	for i := 0; i < maxHistoryTurns+3; i++ {
+		session.ProcessMessage(context.Background(), fmt.Sprintf("turn %d", i)) //nolint:errcheck
+	}
+
+	prompt := provider.userPrompt
+	if !strings.Contains(prompt, "[earlier conversation, summarized]") {
+		t.Fatalf("prompt should include a summary of trimmed turns:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, "- user: turn 0\n") {
+		t.Errorf("summary should retain the oldest trimmed turn:\n%s", prompt)
+	}
+	// The most recent turns stay verbatim after the summary.
+	last := fmt.Sprintf("[user]: turn %d", maxHistoryTurns+2)
+	if !strings.Contains(prompt, last) {
+		t.Errorf("prompt should keep the latest turn verbatim:\n%s", prompt)
+	}
+	if strings.Index(prompt, "[earlier conversation") > strings.Index(prompt, "[user]:") {
+		t.Error("summary should precede the verbatim history")
+	}
+}
+
+func TestSessionSummaryBounded(t *testing.T) {
+	session := NewSession(t.TempDir(), &config.Config{}, &fakeProvider{response: "ok"})
+	for i := 0; i < maxSummaryLines; i++ {
+		session.summarize([]Message{{Role: "user", Content: strings.Repeat("word ", 100)}})
+	}
+	session.summarize([]Message{{Role: "assistant", Content: "newest"}})
+
+	if len(session.summary) != maxSummaryLines {
+		t.Fatalf("summary lines = %d, want %d", len(session.summary), maxSummaryLines)
+	}
+	if session.summary[len(session.summary)-1] != "assistant: newest" {
+		t.Errorf("last summary line = %q", session.summary[len(session.summary)-1])
+	}
+	if n := len([]rune(session.summary[0])); n > len("user: ")+summaryLineChars {
+		t.Errorf("summary line length %d exceeds cap", n)
+	}
+}
+
 func TestExtractYAMLBlockCaseInsensitive(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -1501,3 +1638,284 @@ func TestExtractRoutineYAMLBlockCaseInsensitive(t *testing.T) {
 		})
 	}
 }
+
+func TestConfirmationReason(t *testing.T) {
+	current := &config.Config{
+		Services: []config.ServiceConfig{
+			{
+				Name:     "nws",
+				Type:     "rest",
+				Endpoint: "https://api.weather.gov",
+				Tools:    []config.ToolConfig{{Name: "forecast", Method: "GET", Path: "/points"}},
+			},
+			{
+				Name:     "edgar",
+				Type:     "rest",
+				Endpoint: "https://efts.sec.gov",
+				Auth:     config.AuthConfig{Method: "api_key", Key: "${EDGAR_KEY}"},
+			},
+		},
+		LLM: config.LLMConfig{Providers: []config.ProviderConfig{
+			{Name: "local", Type: "ollama", Endpoint: "http://localhost:11434", Privacy: "local"},
+		}},
+		Privacy: config.PrivacyConfig{
+			StripAttributionForRemote: true,
+			DefaultProxy:              "socks5://127.0.0.1:9050",
+			Routes:                    []config.RouteConfig{{Service: "edgar", Proxy: "tor"}},
+		},
+	}
+	session := &Session{cfg: current}
+
+	// modify returns a copy of the current config with fn applied, the way
+	// the LLM proposes a change: credentials arrive redacted.
+	modify := func(fn func(c *config.Config)) *config.Config {
+		c := redactConfig(current)
+		fn(c)
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		proposed *config.Config
+		want     string
+	}{
+		{
+			"add tool mapping",
+			modify(func(c *config.Config) {
+				c.Services[0].Tools = append(c.Services[0].Tools, config.ToolConfig{Name: "alerts", Method: "GET", Path: "/alerts"})
+			}),
+			"",
+		},
+		{
+			"add tool mapping with its own auth",
+			modify(func(c *config.Config) {
+				c.Services[0].Tools = append(c.Services[0].Tools, config.ToolConfig{
+					Name: "alerts", Method: "GET", Path: "/alerts",
+					Auth: &config.AuthConfig{Method: "bearer", Token: "${NWS_TOKEN}"},
+				})
+			}),
+			"changes service nws",
+		},
+		{
+			"change tool mapping",
+			modify(func(c *config.Config) {
+				c.Services[0].Tools = []config.ToolConfig{{Name: "forecast", Method: "GET", Path: "/gridpoints"}}
+			}),
+			"changes service nws",
+		},
+		{
+			"change endpoint keeping credential",
+			modify(func(c *config.Config) { c.Services[1].Endpoint = "https://evil.example.com" }),
+			"changes service edgar",
+		},
+		{
+			"change auth method",
+			modify(func(c *config.Config) { c.Services[1].Auth.Method = "query" }),
+			"changes service edgar",
+		},
+		{
+			"add service",
+			modify(func(c *config.Config) {
+				c.Services = append(c.Services, config.ServiceConfig{Name: "sam", Type: "rest", Endpoint: "https://api.sam.gov"})
+			}),
+			"adds service sam",
+		},
+		{
+			"remove service",
+			modify(func(c *config.Config) { c.Services = c.Services[:1] }),
+			"removes service edgar",
+		},
+		{
+			"add remote provider",
+			modify(func(c *config.Config) {
+				c.LLM.Providers = append(c.LLM.Providers, config.ProviderConfig{Name: "cloud", Type: "openrouter", Privacy: "remote"})
+			}),
+			"adds a remote LLM provider",
+		},
+		{
+			"add local provider",
+			modify(func(c *config.Config) {
+				c.LLM.Providers = append(c.LLM.Providers, config.ProviderConfig{Name: "llama", Type: "llamacpp", Privacy: "local"})
+			}),
+			"adds LLM provider llama",
+		},
+		{
+			"change provider endpoint",
+			modify(func(c *config.Config) { c.LLM.Providers[0].Endpoint = "http://10.0.0.5:11434" }),
+			"changes LLM provider local",
+		},
+		{
+			"change provider privacy to remote",
+			modify(func(c *config.Config) { c.LLM.Providers[0].Privacy = "remote" }),
+			"adds a remote LLM provider; changes LLM provider local",
+		},
+		{
+			"replace provider",
+			modify(func(c *config.Config) {
+				c.LLM.Providers = []config.ProviderConfig{{Name: "cloud", Type: "openrouter", Privacy: "remote"}}
+			}),
+			"adds a remote LLM provider; removes LLM provider local",
+		},
+		{
+			"disable attribution stripping",
+			modify(func(c *config.Config) { c.Privacy.StripAttributionForRemote = false }),
+			"changes privacy settings",
+		},
+		{
+			"remove proxy routes",
+			modify(func(c *config.Config) {
+				c.Privacy.DefaultProxy = ""
+				c.Privacy.Routes = nil
+			}),
+			"changes privacy settings",
+		},
+		{
+			"change email handoff",
+			modify(func(c *config.Config) { c.Apps.Email = "sendmail" }),
+			"changes apps settings",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := session.confirmationReason(&Change{Config: tt.proposed}); got != tt.want {
+				t.Errorf("confirmationReason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoutineConfirmationReason(t *testing.T) {
+	cfg := &config.Config{
+		Services: []config.ServiceConfig{
+			{Name: "nws", Type: "rest"},
+			{Name: "notes", Type: "file"},
+			{Name: "bank", Type: "rest", Private: true},
+			{Name: "edgar", Type: "rest"},
+		},
+		LLM: config.LLMConfig{Providers: []config.ProviderConfig{
+			{Name: "local", Type: "ollama", Privacy: "local"},
+			{Name: "cloud", Type: "openrouter", Privacy: "remote"},
+		}},
+	}
+	existing := &pipeline.Routine{
+		Name:    "daily",
+		LLM:     "local",
+		Privacy: "local",
+		Sources: []pipeline.SourceConfig{{Service: "nws", Tool: "forecast"}},
+	}
+	session := &Session{cfg: cfg, routines: []*pipeline.Routine{existing}}
+
+	modify := func(fn func(r *pipeline.Routine)) *pipeline.Routine {
+		r := *existing
+		r.Sources = append([]pipeline.SourceConfig(nil), existing.Sources...)
+		fn(&r)
+		return &r
+	}
+	tests := []struct {
+		name     string
+		proposed *pipeline.Routine
+		isNew    bool
+		want     string
+	}{
+		{
+			"change report title",
+			modify(func(r *pipeline.Routine) { r.Report.Title = "Morning" }),
+			false,
+			"",
+		},
+		{
+			"add source on a service already used",
+			modify(func(r *pipeline.Routine) {
+				r.Sources = append(r.Sources, pipeline.SourceConfig{Service: "nws", Tool: "alerts"})
+			}),
+			false,
+			"",
+		},
+		{
+			"add file source",
+			modify(func(r *pipeline.Routine) {
+				r.Sources = append(r.Sources, pipeline.SourceConfig{Service: "notes", Tool: "read"})
+			}),
+			false,
+			"",
+		},
+		{
+			"move to remote provider",
+			modify(func(r *pipeline.Routine) { r.LLM, r.Privacy = "cloud", "" }),
+			false,
+			"sends its results to remote LLM provider cloud; changes privacy from local to unset",
+		},
+		{
+			"add remote service",
+			modify(func(r *pipeline.Routine) {
+				r.Sources = append(r.Sources, pipeline.SourceConfig{Service: "edgar", Tool: "search"})
+			}),
+			false,
+			"adds remote service edgar",
+		},
+		{
+			"add private service",
+			modify(func(r *pipeline.Routine) {
+				r.Sources = append(r.Sources, pipeline.SourceConfig{Service: "bank", Tool: "balance"})
+			}),
+			false,
+			"reads private service bank",
+		},
+		{
+			"new routine on public services",
+			&pipeline.Routine{Name: "weekly", LLM: "local", Sources: []pipeline.SourceConfig{{Service: "edgar"}}},
+			true,
+			"",
+		},
+		{
+			"new routine with remote provider and private service",
+			&pipeline.Routine{Name: "weekly", LLM: "cloud", Sources: []pipeline.SourceConfig{{Service: "bank"}}},
+			true,
+			"sends its results to remote LLM provider cloud; reads private service bank",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := &RoutineChange{Routine: tt.proposed, IsNew: tt.isNew}
+			if got := session.routineConfirmationReason(change); got != tt.want {
+				t.Errorf("routineConfirmationReason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeletionPrompt(t *testing.T) {
+	current := &config.Config{
+		Services: []config.ServiceConfig{{Name: "nws"}, {Name: "edgar"}, {Name: "sam"}},
+		LLM:      config.LLMConfig{Providers: []config.ProviderConfig{{Name: "local"}, {Name: "cloud/gpt"}}},
+	}
+	session := &Session{cfg: current}
+
+	tests := []struct {
+		name     string
+		proposed *config.Config
+		want     string
+	}{
+		{"no removal", current, ""},
+		{
+			"service and provider",
+			&config.Config{
+				Services: []config.ServiceConfig{{Name: "nws"}, {Name: "sam"}},
+				LLM:      config.LLMConfig{Providers: []config.ProviderConfig{{Name: "local"}}},
+			},
+			"This removes service 'edgar' and provider 'cloud/gpt'. Confirm deletion? (y/n)",
+		},
+		{
+			"several services",
+			&config.Config{Services: []config.ServiceConfig{{Name: "nws"}}, LLM: current.LLM},
+			"This removes services 'edgar', 'sam'. Confirm deletion? (y/n)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := session.deletionPrompt(&Change{Config: tt.proposed}); got != tt.want {
+				t.Errorf("deletionPrompt = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
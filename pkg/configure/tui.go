@@ -16,8 +16,10 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"golang.org/x/term"
 
+	"github.com/jcadam/burrow/pkg/actions"
 	"github.com/jcadam/burrow/pkg/config"
 	"github.com/jcadam/burrow/pkg/render"
+	"github.com/jcadam/burrow/pkg/synthesis"
 )
 
 // --- States ---
@@ -42,19 +44,50 @@ type llmResponseMsg struct {
 	err           error
 }
 
+// clipboardResultMsg reports the outcome of copying a proposed change's YAML.
+type clipboardResultMsg struct {
+	err error
+}
+
 // chatMsg represents a single message in the conversation history.
 type chatMsg struct {
 	role    string // "user", "assistant", "system"
 	content string
 }
 
+// renderKey identifies an assistant message rendered at a given width.
+type renderKey struct {
+	msg, width int
+}
+
+// plainRenderWidth is the render width below which assistant messages are
+// shown as lightly formatted plaintext: glamour's wrapping and styling fall
+// apart in narrow split panes.
+const plainRenderWidth = 50
+
 // pendingConfirm represents a change awaiting y/n confirmation.
 type pendingConfirm struct {
 	prompt  string
 	apply   func() error
 	warning string // optional post-apply warning (e.g. remote LLM)
+	raw     string // proposed YAML, copyable for hand editing
+	invalid error  // validation failure found before confirmation
+
+	label     string // what is applied, e.g. "profile change", for auto-apply messages
+	autoApply bool   // low-risk: applied without asking in auto-apply mode
+	deletion  string // second prompt for a change that removes config, "" if none
+}
+
+// confirmDeletion turns a confirmed change that removes config into its
+// second, deletion-listing confirmation.
+func (c pendingConfirm) confirmDeletion() pendingConfirm {
+	c.prompt, c.deletion, c.autoApply = c.deletion, "", false
+	return c
 }
 
+// copyToClipboard is swapped out in tests.
+var copyToClipboard = actions.CopyToClipboard
+
 // processingTickMsg drives the spinner animation during LLM calls.
 // Uses tea.Tick instead of spinner's internal tick chain for robustness.
 type processingTickMsg time.Time
@@ -74,6 +107,7 @@ var (
 	helpBarStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#565F89"))
 	tuiHeaderStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF5FD7")).PaddingLeft(1)
 	systemMsgStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#9ECE6A"))
+	plainHeadStyle   = lipgloss.NewStyle().Bold(true)
 )
 
 // --- Model ---
@@ -91,7 +125,12 @@ type configModel struct {
 	// original context. Capturing ctx in this closure (rather than storing it
 	// on the struct) avoids a stale-context footgun: Bubble Tea copies the
 	// model by value, so a stored ctx would never reflect later changes.
-	sendMsg func(input string) tea.Cmd
+	// The reply streams into stream as the model writes it.
+	sendMsg func(input string, stream *synthesis.StreamBuffer) tea.Cmd
+
+	// streamed is the reply so far while a message is processed, shown in
+	// place of "Thinking..." as it arrives.
+	streamed *synthesis.StreamBuffer
 
 	// UI components
 	viewport viewport.Model
@@ -100,8 +139,9 @@ type configModel struct {
 	ready    bool // set after first WindowSizeMsg
 
 	// Conversation
-	messages []chatMsg
-	rendered []string // per-message glamour/styled cache
+	messages    []chatMsg
+	rendered    []string             // per-message render at the current width
+	renderCache map[renderKey]string // assistant renders by message and width, reused across resizes
 
 	// State machine
 	state        tuiState
@@ -136,10 +176,13 @@ func newConfigModel(ctx context.Context, session *Session, initMode bool) config
 		title = "Burrow Init"
 	}
 
+	send := func(input string, stream *synthesis.StreamBuffer) tea.Cmd {
+		return sendMessageCmd(ctx, session, input, stream)
+	}
 	m := configModel{
 		session:  session,
 		cancel:   cancel,
-		sendMsg:  func(input string) tea.Cmd { return sendMessageCmd(ctx, session, input) },
+		sendMsg:  send,
 		textarea: ta,
 		spinner:  sp,
 		initMode: initMode,
@@ -149,6 +192,9 @@ func newConfigModel(ctx context.Context, session *Session, initMode bool) config
 	// Add welcome message
 	welcome := fmt.Sprintf("Welcome to %s. Describe what you'd like to configure, or type \"done\" to finish.", title)
 	m.appendMessage("system", welcome)
+	if session.Interviewing() {
+		m.appendMessage("assistant", interviewTopics[session.interview.step].Question)
+	}
 
 	return m
 }
@@ -162,6 +208,7 @@ func (m configModel) Init() tea.Cmd {
 func (m configModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
+		resized := msg.Width != m.width
 		m.width = msg.Width
 		m.height = msg.Height
 		headerHeight := 2
@@ -171,6 +218,9 @@ func (m configModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			vpHeight = 1
 		}
 
+		if resized {
+			m.rerender()
+		}
 		if !m.ready {
 			m.viewport = viewport.New(m.width, vpHeight)
 			m.viewport.YPosition = headerHeight
@@ -190,6 +240,15 @@ func (m configModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case llmResponseMsg:
 		return m.handleLLMResponse(msg)
 
+	case clipboardResultMsg:
+		if msg.err != nil {
+			m.appendMessage("system", errorStyle.Render("Error: "+msg.err.Error()))
+		} else {
+			m.appendMessage("system", "Copied proposed YAML to clipboard.")
+		}
+		m.rebuildViewport()
+		return m, nil
+
 	case processingTickMsg:
 		if m.state == stateProcessing {
 			m.spinner, _ = m.spinner.Update(spinner.TickMsg{})
@@ -208,6 +267,12 @@ func (m configModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// accessible reports whether the session is in accessible mode, which
+// replaces the spinner with static text.
+func (m configModel) accessible() bool {
+	return m.session != nil && m.session.accessible
+}
+
 func (m configModel) View() string {
 	if !m.ready {
 		return "Loading..."
@@ -224,7 +289,17 @@ func (m configModel) View() string {
 	var inputArea string
 	switch m.state {
 	case stateProcessing:
-		inputArea = fmt.Sprintf("  %s Thinking...", m.spinner.View())
+		status := "Thinking..."
+		if m.streamed != nil {
+			if tail := m.streamed.Tail(max(m.width-8, 20)); tail != "" {
+				status = tail
+			}
+		}
+		if m.accessible() {
+			inputArea = "  Thinking..."
+		} else {
+			inputArea = fmt.Sprintf("  %s %s", m.spinner.View(), status)
+		}
 	case stateConfirming:
 		inputArea = confirmStyle.Render("  > (y/n) ")
 	default:
@@ -295,9 +370,13 @@ func (m configModel) handleInputKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.appendMessage("user", input)
 		m.rebuildViewport()
 		m.state = stateProcessing
+		m.streamed = &synthesis.StreamBuffer{}
 
+		if m.accessible() {
+			return m, m.sendMsg(input, m.streamed)
+		}
 		return m, tea.Batch(
-			m.sendMsg(input),
+			m.sendMsg(input, m.streamed),
 			processingTick(),
 		)
 
@@ -382,6 +461,25 @@ func (m configModel) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch key {
 	case "pgup", "pgdown", "ctrl+u", "ctrl+d":
 		return m.scrollViewport(msg)
+	case "c":
+		if len(m.confirmQueue) == 0 || m.confirmQueue[0].raw == "" {
+			return m, nil
+		}
+		return m, clipboardCmd(m.confirmQueue[0].raw)
+	case "a":
+		if len(m.confirmQueue) == 0 {
+			return m, nil
+		}
+		m.applyAll()
+		if len(m.confirmQueue) > 0 {
+			m.showConfirm(m.confirmQueue[0])
+			m.rebuildViewport()
+			return m, nil
+		}
+		m.state = stateInput
+		cmd := m.textarea.Focus()
+		m.rebuildViewport()
+		return m, cmd
 	}
 
 	if key != "y" && key != "n" {
@@ -397,22 +495,18 @@ func (m configModel) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	confirm := m.confirmQueue[0]
 	m.confirmQueue = m.confirmQueue[1:]
 
-	if key == "y" {
-		if err := confirm.apply(); err != nil {
-			m.appendMessage("system", errorStyle.Render("Error: "+err.Error()))
-		} else {
-			m.appendMessage("system", "Applied.")
-			if confirm.warning != "" {
-				m.appendMessage("system", confirmStyle.Render(confirm.warning))
-			}
-		}
+	if key == "y" && confirm.deletion != "" {
+		m.confirmQueue = append([]pendingConfirm{confirm.confirmDeletion()}, m.confirmQueue...)
+	} else if key == "y" {
+		m.applyPending(confirm, "Applied.")
 	} else {
 		m.appendMessage("system", "Discarded.")
 	}
+	m.autoApplyQueued()
 
 	// Check for more confirmations
 	if len(m.confirmQueue) > 0 {
-		m.appendMessage("system", m.confirmQueue[0].prompt)
+		m.showConfirm(m.confirmQueue[0])
 		m.rebuildViewport()
 		return m, nil
 	}
@@ -423,9 +517,82 @@ func (m configModel) handleConfirmKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// applyPending applies one change, reporting okMsg and any post-apply
+// warning on success or the error on failure.
+func (m *configModel) applyPending(c pendingConfirm, okMsg string) {
+	if err := c.apply(); err != nil {
+		m.appendMessage("system", errorStyle.Render("Error: "+err.Error()))
+		return
+	}
+	m.appendMessage("system", okMsg)
+	if c.warning != "" {
+		m.appendMessage("system", confirmStyle.Render(c.warning))
+	}
+}
+
+// autoApplyQueued applies the low-risk changes at the head of the queue when
+// the session is in auto-apply mode. It stops at the first change that needs
+// confirmation, so changes still apply in the order proposed.
+func (m *configModel) autoApplyQueued() {
+	if m.session == nil || !m.session.autoApply {
+		return
+	}
+	for len(m.confirmQueue) > 0 && m.confirmQueue[0].autoApply {
+		c := m.confirmQueue[0]
+		m.confirmQueue = m.confirmQueue[1:]
+		m.applyPending(c, "Auto-applied "+c.label+".")
+	}
+}
+
+// showConfirm prompts for a pending change, flagging a proposal that
+// already failed validation so it isn't confirmed blind.
+func (m *configModel) showConfirm(c pendingConfirm) {
+	if c.invalid != nil {
+		m.appendMessage("system", errorStyle.Render("⚠ invalid: "+c.invalid.Error()))
+	}
+	m.appendMessage("system", c.prompt)
+}
+
+// applyAll applies every pending change in queue order, reporting each
+// failure and an aggregate count. A failure does not stop later changes.
+// Changes that remove config are not applied; their deletion confirmations
+// are left queued.
+func (m *configModel) applyAll() {
+	var queue, deletions []pendingConfirm
+	for _, c := range m.confirmQueue {
+		if c.deletion != "" {
+			deletions = append(deletions, c.confirmDeletion())
+			continue
+		}
+		queue = append(queue, c)
+	}
+	m.confirmQueue = deletions
+
+	applied := 0
+	for _, confirm := range queue {
+		if err := confirm.apply(); err != nil {
+			m.appendMessage("system", errorStyle.Render("Error: "+err.Error()))
+			continue
+		}
+		applied++
+		if confirm.warning != "" {
+			m.appendMessage("system", confirmStyle.Render(confirm.warning))
+		}
+	}
+
+	switch {
+	case len(queue) == 0:
+	case applied == len(queue):
+		m.appendMessage("system", fmt.Sprintf("Applied all %d changes.", applied))
+	default:
+		m.appendMessage("system", errorStyle.Render(fmt.Sprintf("Applied %d of %d changes.", applied, len(queue))))
+	}
+}
+
 // --- LLM response handling ---
 
 func (m configModel) handleLLMResponse(msg llmResponseMsg) (tea.Model, tea.Cmd) {
+	m.streamed = nil
 	if msg.err != nil {
 		m.appendMessage("system", errorStyle.Render("Error: "+msg.err.Error()))
 		m.state = stateInput
@@ -450,6 +617,9 @@ func (m configModel) handleLLMResponse(msg llmResponseMsg) (tea.Model, tea.Cmd)
 			apply: func() error {
 				return m.session.ApplyProfileChange(pc)
 			},
+			raw:       pc.Raw,
+			label:     "profile change",
+			autoApply: true,
 		})
 	}
 
@@ -459,11 +629,22 @@ func (m configModel) handleLLMResponse(msg llmResponseMsg) (tea.Model, tea.Cmd)
 		if !rc.IsNew {
 			action = "Update"
 		}
+		prompt := fmt.Sprintf("%s routine %q? (y/n)", action, rc.Routine.Name)
+		var risk string
+		if m.session != nil && m.session.autoApply {
+			if risk = m.session.routineConfirmationReason(rc); risk != "" {
+				prompt = fmt.Sprintf("%s routine %q? It %s. (y/n)", action, rc.Routine.Name, risk)
+			}
+		}
 		m.confirmQueue = append(m.confirmQueue, pendingConfirm{
-			prompt: fmt.Sprintf("%s routine %q? (y/n)", action, rc.Routine.Name),
+			prompt: prompt,
 			apply: func() error {
 				return m.session.ApplyRoutineChange(rc)
 			},
+			raw:       rc.Raw,
+			invalid:   rc.Invalid,
+			label:     fmt.Sprintf("routine %q", rc.Routine.Name),
+			autoApply: rc.Invalid == nil && risk == "",
 		})
 	}
 
@@ -472,8 +653,18 @@ func (m configModel) handleLLMResponse(msg llmResponseMsg) (tea.Model, tea.Cmd)
 		sess := m.session
 		result := m.result
 		initMode := m.initMode
+		prompt := "Apply this configuration change? (y/n)"
+		var risk, deletion string
+		if sess != nil {
+			deletion = sess.deletionPrompt(ch)
+			if sess.autoApply {
+				if risk = sess.confirmationReason(ch); risk != "" {
+					prompt = fmt.Sprintf("Apply this configuration change? It %s. (y/n)", risk)
+				}
+			}
+		}
 		m.confirmQueue = append(m.confirmQueue, pendingConfirm{
-			prompt: "Apply this configuration change? (y/n)",
+			prompt: prompt,
 			apply: func() error {
 				if err := sess.ApplyChange(ch); err != nil {
 					return err
@@ -491,13 +682,19 @@ func (m configModel) handleLLMResponse(msg llmResponseMsg) (tea.Model, tea.Cmd)
 				}
 				return ""
 			}(),
+			raw:       ch.Raw,
+			invalid:   ch.Invalid,
+			label:     "configuration change",
+			autoApply: ch.Invalid == nil && risk == "",
+			deletion:  deletion,
 		})
 	}
+	m.autoApplyQueued()
 
 	var cmd tea.Cmd
 	if len(m.confirmQueue) > 0 {
 		m.state = stateConfirming
-		m.appendMessage("system", m.confirmQueue[0].prompt)
+		m.showConfirm(m.confirmQueue[0])
 	} else {
 		m.state = stateInput
 		cmd = m.textarea.Focus()
@@ -509,8 +706,21 @@ func (m configModel) handleLLMResponse(msg llmResponseMsg) (tea.Model, tea.Cmd)
 
 // --- Async command ---
 
-func sendMessageCmd(ctx context.Context, session *Session, input string) tea.Cmd {
+// clipboardCmd copies raw to the clipboard off the update loop.
+func clipboardCmd(raw string) tea.Cmd {
+	return func() tea.Msg {
+		if err := copyToClipboard(raw); err != nil {
+			return clipboardResultMsg{err: fmt.Errorf("clipboard: %w", err)}
+		}
+		return clipboardResultMsg{}
+	}
+}
+
+func sendMessageCmd(ctx context.Context, session *Session, input string, stream *synthesis.StreamBuffer) tea.Cmd {
 	return func() tea.Msg {
+		if stream != nil {
+			ctx = synthesis.WithTokens(ctx, stream.Add)
+		}
 		response, change, profChange, routineChange, warnings, err := session.ProcessMessage(ctx, input)
 		return llmResponseMsg{response, change, profChange, routineChange, warnings, err}
 	}
@@ -520,24 +730,83 @@ func sendMessageCmd(ctx context.Context, session *Session, input string) tea.Cmd
 
 func (m *configModel) appendMessage(role, content string) {
 	m.messages = append(m.messages, chatMsg{role: role, content: content})
+	m.rendered = append(m.rendered, m.renderMessage(len(m.messages)-1))
+}
 
-	// Render the message
-	var rendered string
-	switch role {
+// rerender refreshes every message for the current width. Assistant
+// messages come from the render cache when seen at this width before.
+func (m *configModel) rerender() {
+	for i := range m.messages {
+		m.rendered[i] = m.renderMessage(i)
+	}
+}
+
+// renderMessage renders message i for the current width.
+func (m *configModel) renderMessage(i int) string {
+	msg := m.messages[i]
+	switch msg.role {
 	case "user":
-		rendered = userLabelStyle.Render("You:") + " " + content
+		return userLabelStyle.Render("You:") + " " + msg.content
 	case "assistant":
-		// Render markdown via glamour
-		md, err := render.RenderMarkdown(content, m.renderWidth())
-		if err != nil {
-			md = content
+		width := m.renderWidth()
+		key := renderKey{msg: i, width: width}
+		if out, ok := m.renderCache[key]; ok {
+			return out
+		}
+		var out string
+		if width < plainRenderWidth {
+			out = renderPlain(msg.content, width)
+		} else {
+			// Render markdown via glamour
+			style := ""
+			if m.session != nil {
+				style = m.session.style
+			}
+			md, err := render.RenderMarkdownStyle(msg.content, width, style)
+			if err != nil {
+				md = msg.content
+			}
+			out = md
+		}
+		if m.renderCache == nil {
+			m.renderCache = make(map[renderKey]string)
 		}
-		rendered = md
+		m.renderCache[key] = out
+		return out
 	case "system":
-		rendered = systemMsgStyle.Render("  " + content)
+		return systemMsgStyle.Render("  " + msg.content)
 	}
+	return ""
+}
 
-	m.rendered = append(m.rendered, rendered)
+// renderPlain formats markdown as lightly styled plaintext wrapped to width.
+// Headings are bolded without their markers, emphasis and inline-code marks
+// are dropped, code fences are removed, and list bullets become "•".
+func renderPlain(markdown string, width int) string {
+	var out []string
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "#") {
+			heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			out = append(out, plainHeadStyle.Render(stripInlineMarks(heading)))
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") {
+			trimmed = "• " + trimmed[2:]
+		}
+		out = append(out, indent+stripInlineMarks(trimmed))
+	}
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(out, "\n"))
+}
+
+// stripInlineMarks removes markdown bold, italic-underscore, and inline-code
+// markers.
+func stripInlineMarks(s string) string {
+	return strings.NewReplacer("**", "", "__", "", "`", "").Replace(s)
 }
 
 func (m *configModel) renderWidth() int {
@@ -560,7 +829,10 @@ func (m *configModel) rebuildViewport() {
 
 func (m configModel) renderHelpBar() string {
 	if m.state == stateConfirming {
-		return helpBarStyle.Render("  y apply  n discard  ctrl+c quit")
+		if len(m.confirmQueue) > 1 {
+			return helpBarStyle.Render("  y apply  n discard  a apply all  c copy yaml  ctrl+c quit")
+		}
+		return helpBarStyle.Render("  y apply  n discard  c copy yaml  ctrl+c quit")
 	}
 	return helpBarStyle.Render("  enter send  \\ newline  pgup/pgdn scroll  ctrl+c quit")
 }
@@ -621,6 +893,10 @@ func runPlainREPLInner(ctx context.Context, session *Session, initMode bool) (*c
 
 	fmt.Println("  Describe what you want to change, or 'done' to finish.")
 	fmt.Println()
+	if session.Interviewing() {
+		fmt.Println("  " + interviewTopics[session.interview.step].Question)
+		fmt.Println()
+	}
 
 	for {
 		fmt.Print("  > ")
@@ -648,10 +924,10 @@ func runPlainREPLInner(ctx context.Context, session *Session, initMode bool) (*c
 			fmt.Fprintf(os.Stderr, "  Warning: %s\n", w)
 		}
 
+		auto := session.autoApply
+
 		if profChange != nil {
-			fmt.Println("  Apply profile change? (y/n)")
-			fmt.Print("  > ")
-			if readPlainConfirm(reader) == "y" {
+			if confirmPlain(reader, "Apply profile change? (y/n)", auto) {
 				if err := session.ApplyProfileChange(profChange); err != nil {
 					fmt.Fprintf(os.Stderr, "  Error applying profile: %v\n", err)
 				} else {
@@ -667,9 +943,15 @@ func runPlainREPLInner(ctx context.Context, session *Session, initMode bool) (*c
 			if !routineChange.IsNew {
 				action = "Update"
 			}
-			fmt.Printf("  %s routine %q? (y/n)\n", action, routineChange.Routine.Name)
-			fmt.Print("  > ")
-			if readPlainConfirm(reader) == "y" {
+			prompt := fmt.Sprintf("%s routine %q? (y/n)", action, routineChange.Routine.Name)
+			lowRisk := routineChange.Invalid == nil
+			if auto {
+				if risk := session.routineConfirmationReason(routineChange); risk != "" {
+					prompt = fmt.Sprintf("%s routine %q? It %s. (y/n)", action, routineChange.Routine.Name, risk)
+					lowRisk = false
+				}
+			}
+			if confirmPlain(reader, prompt, auto && lowRisk) {
 				if err := session.ApplyRoutineChange(routineChange); err != nil {
 					fmt.Fprintf(os.Stderr, "  Error applying routine: %v\n", err)
 				} else {
@@ -681,9 +963,19 @@ func runPlainREPLInner(ctx context.Context, session *Session, initMode bool) (*c
 		}
 
 		if change != nil {
-			fmt.Println("  Apply this configuration change? (y/n)")
-			fmt.Print("  > ")
-			if readPlainConfirm(reader) == "y" {
+			prompt := "Apply this configuration change? (y/n)"
+			lowRisk := change.Invalid == nil
+			if auto {
+				if risk := session.confirmationReason(change); risk != "" {
+					prompt = fmt.Sprintf("Apply this configuration change? It %s. (y/n)", risk)
+					lowRisk = false
+				}
+			}
+			confirmed := confirmPlain(reader, prompt, auto && lowRisk)
+			if deletion := session.deletionPrompt(change); confirmed && deletion != "" {
+				confirmed = confirmPlain(reader, deletion, false)
+			}
+			if confirmed {
 				if err := session.ApplyChange(change); err != nil {
 					fmt.Fprintf(os.Stderr, "  Error applying: %v\n", err)
 				} else {
@@ -739,6 +1031,18 @@ func readPlainLine(reader *bufio.Reader) (string, error) {
 	return strings.Join(lines, "\n"), nil
 }
 
+// confirmPlain asks a y/n prompt and reports whether the answer was y. When
+// auto is set the change is accepted without asking.
+func confirmPlain(reader *bufio.Reader, prompt string, auto bool) bool {
+	if auto {
+		fmt.Println("  " + strings.TrimSuffix(prompt, " (y/n)") + " Auto-applying.")
+		return true
+	}
+	fmt.Println("  " + prompt)
+	fmt.Print("  > ")
+	return readPlainConfirm(reader) == "y"
+}
+
 // readPlainConfirm reads a single line for y/n confirmation.
 func readPlainConfirm(reader *bufio.Reader) string {
 	line, _ := reader.ReadString('\n')
@@ -9,8 +9,10 @@ import (
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/jcadam/burrow/pkg/config"
 	"github.com/jcadam/burrow/pkg/pipeline"
+	"github.com/jcadam/burrow/pkg/synthesis"
 )
 
 // newTestModel creates a configModel in a testable state (ready = true).
@@ -148,6 +150,60 @@ func TestConfirmKeyN(t *testing.T) {
 	}
 }
 
+func TestConfirmKeyCopiesRaw(t *testing.T) {
+	var copied string
+	orig := copyToClipboard
+	copyToClipboard = func(text string) error { copied = text; return nil }
+	defer func() { copyToClipboard = orig }()
+
+	m := newTestModel(false)
+	m.state = stateConfirming
+	m.confirmQueue = []pendingConfirm{
+		{
+			prompt: "Apply? (y/n)",
+			apply:  func() error { return nil },
+			raw:    "llm:\n  providers: []\n",
+		},
+	}
+
+	result, cmd := m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	model := result.(configModel)
+	if cmd == nil {
+		t.Fatal("expected a clipboard command on 'c'")
+	}
+	if model.state != stateConfirming || len(model.confirmQueue) != 1 {
+		t.Error("copying should leave the confirmation pending")
+	}
+
+	updated, _ := model.Update(cmd())
+	model = updated.(configModel)
+	if copied != "llm:\n  providers: []\n" {
+		t.Errorf("copied = %q", copied)
+	}
+	last := model.rendered[len(model.rendered)-1]
+	if !strings.Contains(last, "Copied") {
+		t.Errorf("expected copy confirmation message, got %q", last)
+	}
+}
+
+func TestConfirmKeyCopyError(t *testing.T) {
+	orig := copyToClipboard
+	copyToClipboard = func(string) error { return fmt.Errorf("no clipboard tool found") }
+	defer func() { copyToClipboard = orig }()
+
+	m := newTestModel(false)
+	m.state = stateConfirming
+	m.confirmQueue = []pendingConfirm{{prompt: "Apply? (y/n)", apply: func() error { return nil }, raw: "x: 1"}}
+
+	_, cmd := m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	updated, _ := m.Update(cmd())
+	model := updated.(configModel)
+	last := model.rendered[len(model.rendered)-1]
+	if !strings.Contains(last, "no clipboard tool found") {
+		t.Errorf("expected clipboard error message, got %q", last)
+	}
+}
+
 func TestConfirmKeyOtherIgnored(t *testing.T) {
 	m := newTestModel(false)
 	m.state = stateConfirming
@@ -199,6 +255,137 @@ func TestConfirmQueueMultiple(t *testing.T) {
 	}
 }
 
+func TestConfirmApplyAll(t *testing.T) {
+	m := newTestModel(false)
+	m.state = stateConfirming
+
+	order := []string{}
+	m.confirmQueue = []pendingConfirm{
+		{prompt: "First? (y/n)", apply: func() error { order = append(order, "first"); return nil }},
+		{prompt: "Second? (y/n)", apply: func() error { order = append(order, "second"); return nil }},
+		{prompt: "Third? (y/n)", apply: func() error { order = append(order, "third"); return nil }},
+	}
+
+	result, _ := m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	model := result.(configModel)
+
+	if model.state != stateInput {
+		t.Errorf("state = %d after apply all, want stateInput", model.state)
+	}
+	if len(model.confirmQueue) != 0 {
+		t.Errorf("confirmQueue len = %d, want 0", len(model.confirmQueue))
+	}
+	if strings.Join(order, ",") != "first,second,third" {
+		t.Errorf("order = %v, want [first second third]", order)
+	}
+	if last := model.rendered[len(model.rendered)-1]; !strings.Contains(last, "Applied all 3 changes") {
+		t.Errorf("expected aggregate success, got %q", last)
+	}
+}
+
+func TestConfirmApplyAllPartialFailure(t *testing.T) {
+	m := newTestModel(false)
+	m.state = stateConfirming
+
+	applied := 0
+	m.confirmQueue = []pendingConfirm{
+		{prompt: "First? (y/n)", apply: func() error { return fmt.Errorf("bad routine") }},
+		{prompt: "Second? (y/n)", apply: func() error { applied++; return nil }},
+	}
+
+	result, _ := m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	model := result.(configModel)
+
+	if applied != 1 {
+		t.Error("a failure should not stop later changes")
+	}
+	joined := strings.Join(model.rendered, "\n")
+	if !strings.Contains(joined, "bad routine") {
+		t.Error("expected the failure to be reported")
+	}
+	if !strings.Contains(joined, "Applied 1 of 2 changes") {
+		t.Errorf("expected aggregate count, got %q", joined)
+	}
+}
+
+func TestConfirmDeletionNeedsSecondConfirm(t *testing.T) {
+	m := newTestModel(false)
+	m.state = stateProcessing
+	m.session = &Session{cfg: &config.Config{Services: []config.ServiceConfig{{Name: "nws"}, {Name: "edgar"}}}}
+
+	result, _ := m.handleLLMResponse(llmResponseMsg{
+		response: "Done.",
+		change:   &Change{Config: &config.Config{Services: []config.ServiceConfig{{Name: "nws"}}}},
+	})
+	model := result.(configModel)
+	if len(model.confirmQueue) != 1 || model.confirmQueue[0].deletion == "" {
+		t.Fatalf("expected a config change with a deletion prompt, queue=%+v", model.confirmQueue)
+	}
+
+	applied := false
+	model.confirmQueue[0].apply = func() error { applied = true; return nil }
+	result, _ = model.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model = result.(configModel)
+	if applied {
+		t.Fatal("a removal should not apply on the first y")
+	}
+	if model.state != stateConfirming || len(model.confirmQueue) != 1 {
+		t.Fatalf("expected the deletion confirmation, state=%d queue=%d", model.state, len(model.confirmQueue))
+	}
+	if last := model.rendered[len(model.rendered)-1]; !strings.Contains(last, "This removes service 'edgar'. Confirm deletion?") {
+		t.Errorf("expected deletion prompt, got %q", last)
+	}
+
+	result, _ = model.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	model = result.(configModel)
+	if !applied {
+		t.Error("expected the change applied after confirming deletion")
+	}
+	if model.state != stateInput {
+		t.Errorf("state = %d, want stateInput", model.state)
+	}
+}
+
+func TestConfirmApplyAllHoldsDeletions(t *testing.T) {
+	m := newTestModel(false)
+	m.state = stateConfirming
+
+	order := []string{}
+	m.confirmQueue = []pendingConfirm{
+		{prompt: "First? (y/n)", apply: func() error { order = append(order, "first"); return nil }},
+		{
+			prompt:   "Config? (y/n)",
+			apply:    func() error { order = append(order, "config"); return nil },
+			deletion: "This removes service 'edgar'. Confirm deletion? (y/n)",
+		},
+	}
+
+	result, _ := m.handleConfirmKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	model := result.(configModel)
+	if strings.Join(order, ",") != "first" {
+		t.Errorf("order = %v, want only [first]", order)
+	}
+	if model.state != stateConfirming || len(model.confirmQueue) != 1 {
+		t.Fatalf("expected the deletion left to confirm, state=%d queue=%d", model.state, len(model.confirmQueue))
+	}
+	if p := model.confirmQueue[0].prompt; !strings.Contains(p, "Confirm deletion?") {
+		t.Errorf("prompt = %q, want the deletion prompt", p)
+	}
+}
+
+func TestHelpBarApplyAllOnlyWithMultiple(t *testing.T) {
+	m := newTestModel(false)
+	m.state = stateConfirming
+	m.confirmQueue = []pendingConfirm{{prompt: "One? (y/n)"}}
+	if strings.Contains(m.renderHelpBar(), "apply all") {
+		t.Error("apply all should be hidden for a single change")
+	}
+	m.confirmQueue = append(m.confirmQueue, pendingConfirm{prompt: "Two? (y/n)"})
+	if !strings.Contains(m.renderHelpBar(), "a apply all") {
+		t.Error("apply all should be offered for multiple changes")
+	}
+}
+
 func TestLLMResponseNoChanges(t *testing.T) {
 	m := newTestModel(false)
 	m.state = stateProcessing
@@ -256,6 +443,28 @@ func TestLLMResponseWithConfigChange(t *testing.T) {
 	}
 }
 
+func TestLLMResponseInvalidChangeFlagged(t *testing.T) {
+	m := newTestModel(false)
+	m.state = stateProcessing
+
+	result, _ := m.handleLLMResponse(llmResponseMsg{
+		response: "I'll add a service.",
+		change: &Change{
+			Config:  &config.Config{},
+			Invalid: fmt.Errorf("service \"x\" missing endpoint"),
+		},
+	})
+	model := result.(configModel)
+
+	joined := strings.Join(model.rendered, "\n")
+	if !strings.Contains(joined, "invalid: service \"x\" missing endpoint") {
+		t.Errorf("expected validation warning before confirm, got %q", joined)
+	}
+	if model.state != stateConfirming {
+		t.Errorf("state = %d, want stateConfirming", model.state)
+	}
+}
+
 func TestLLMResponseMultipleChanges(t *testing.T) {
 	m := newTestModel(false)
 	m.state = stateProcessing
@@ -319,6 +528,56 @@ func TestAppendMessageRendering(t *testing.T) {
 	}
 }
 
+func TestAssistantPlainRenderNarrow(t *testing.T) {
+	m := newTestModel(false)
+	m.width = 40
+
+	m.appendMessage("assistant", "## Summary\n\n- **first** item\n\n```yaml\nkey: value\n```")
+	out := m.rendered[0]
+	for _, want := range []string{"Summary", "• first item", "key: value"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("plain render missing %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"##", "**", "```"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("plain render should strip %q:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestRenderPlainWraps(t *testing.T) {
+	out := renderPlain(strings.Repeat("word ", 20), 20)
+	for _, line := range strings.Split(out, "\n") {
+		if w := lipgloss.Width(line); w > 20 {
+			t.Errorf("line width %d exceeds 20: %q", w, line)
+		}
+	}
+}
+
+func TestResizeReusesRenderCache(t *testing.T) {
+	m := newTestModel(false)
+	m.appendMessage("assistant", "## Hello\n\nSome text.")
+	wide := m.rendered[0]
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 40, Height: 24})
+	m = updated.(configModel)
+	if m.rendered[0] == wide {
+		t.Error("narrow resize should re-render the message")
+	}
+	if len(m.renderCache) != 2 {
+		t.Errorf("render cache has %d entries, want 2", len(m.renderCache))
+	}
+
+	// Returning to the original width is served from the cache.
+	m.renderCache[renderKey{msg: 0, width: 76}] = "cached"
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m = updated.(configModel)
+	if m.rendered[0] != "cached" {
+		t.Errorf("rendered = %q, want cached render", m.rendered[0])
+	}
+}
+
 func TestViewContainsHeader(t *testing.T) {
 	m := newTestModel(false)
 	// Create a minimal viewport so View() works
@@ -358,4 +617,114 @@ func TestHelpBarConfirming(t *testing.T) {
 	if !strings.Contains(bar, "y apply") {
 		t.Errorf("help bar should show 'y apply', got %q", bar)
 	}
+	if !strings.Contains(bar, "c copy yaml") {
+		t.Errorf("help bar should show 'c copy yaml', got %q", bar)
+	}
+}
+
+func TestAutoApplyLowRiskChanges(t *testing.T) {
+	m := newTestModel(false)
+	m.state = stateProcessing
+	m.session = &Session{
+		burrowDir: t.TempDir(),
+		cfg:       &config.Config{Services: []config.ServiceConfig{{Name: "nws"}}},
+		autoApply: true,
+	}
+	routine := &pipeline.Routine{
+		Name:    "daily",
+		Report:  pipeline.ReportConfig{Title: "Daily"},
+		Sources: []pipeline.SourceConfig{{Service: "nws", Tool: "forecast"}},
+	}
+
+	// A routine change is low-risk; removing the only service is not.
+	result, _ := m.handleLLMResponse(llmResponseMsg{
+		response:      "Done.",
+		routineChange: &RoutineChange{Routine: routine, IsNew: true},
+		change:        &Change{Config: &config.Config{}},
+	})
+	model := result.(configModel)
+
+	joined := strings.Join(model.rendered, "\n")
+	if !strings.Contains(joined, `Auto-applied routine "daily".`) {
+		t.Errorf("expected routine change applied without confirmation, got %q", joined)
+	}
+	if model.state != stateConfirming || len(model.confirmQueue) != 1 {
+		t.Fatalf("expected the config change to await confirmation, state=%d queue=%d", model.state, len(model.confirmQueue))
+	}
+	if !strings.Contains(model.confirmQueue[0].prompt, "removes service nws") {
+		t.Errorf("expected the risk in the prompt, got %q", model.confirmQueue[0].prompt)
+	}
+}
+
+func TestAutoApplyConfirmsRoutineMovedToRemoteLLM(t *testing.T) {
+	m := newTestModel(false)
+	m.state = stateProcessing
+	existing := &pipeline.Routine{Name: "daily", LLM: "local", Report: pipeline.ReportConfig{Title: "Daily"}}
+	m.session = &Session{
+		burrowDir: t.TempDir(),
+		cfg: &config.Config{LLM: config.LLMConfig{Providers: []config.ProviderConfig{
+			{Name: "local", Type: "ollama", Privacy: "local"},
+			{Name: "cloud", Type: "openrouter", Privacy: "remote"},
+		}}},
+		routines:  []*pipeline.Routine{existing},
+		autoApply: true,
+	}
+	updated := *existing
+	updated.LLM = "cloud"
+
+	result, _ := m.handleLLMResponse(llmResponseMsg{
+		response:      "Done.",
+		routineChange: &RoutineChange{Routine: &updated},
+	})
+	model := result.(configModel)
+
+	if model.state != stateConfirming || len(model.confirmQueue) != 1 {
+		t.Fatalf("expected the routine change to await confirmation, state=%d queue=%d", model.state, len(model.confirmQueue))
+	}
+	if !strings.Contains(model.confirmQueue[0].prompt, "remote LLM provider cloud") {
+		t.Errorf("expected the risk in the prompt, got %q", model.confirmQueue[0].prompt)
+	}
+}
+
+func TestAutoApplyOffStillConfirms(t *testing.T) {
+	m := newTestModel(false)
+	m.state = stateProcessing
+	m.session = &Session{cfg: &config.Config{}}
+
+	result, _ := m.handleLLMResponse(llmResponseMsg{
+		response:   "Done.",
+		profChange: &ProfileChange{Description: "profile"},
+	})
+	model := result.(configModel)
+	if model.state != stateConfirming || len(model.confirmQueue) != 1 {
+		t.Errorf("expected confirmation without auto-apply, state=%d queue=%d", model.state, len(model.confirmQueue))
+	}
+}
+
+func TestProcessingShowsStreamedReply(t *testing.T) {
+	m := newTestModel(false)
+	var stream *synthesis.StreamBuffer
+	m.sendMsg = func(input string, s *synthesis.StreamBuffer) tea.Cmd {
+		stream = s
+		return nil
+	}
+	m.textarea.SetValue("add a weather service")
+	result, _ := m.handleInputKey(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(configModel)
+	if m.state != stateProcessing || stream == nil {
+		t.Fatalf("state = %d, stream = %v; want processing with a stream", m.state, stream)
+	}
+	if !strings.Contains(m.View(), "Thinking...") {
+		t.Error("expected Thinking... before any text streams in")
+	}
+
+	stream.Add("Sure — I'll add\nOpen-Meteo.")
+	if view := m.View(); !strings.Contains(view, "Sure — I'll add Open-Meteo.") {
+		t.Errorf("streamed text not shown:\n%s", view)
+	}
+
+	result, _ = m.handleLLMResponse(llmResponseMsg{response: "Sure — I'll add Open-Meteo."})
+	if result.(configModel).streamed != nil {
+		t.Error("stream kept after the response arrived")
+	}
 }
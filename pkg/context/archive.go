@@ -0,0 +1,189 @@
+package context
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ArchiveTypes are the entry types exported by default. Raw results are
+// bulky and reproducible from reports, so they are opt-in.
+var ArchiveTypes = []string{TypeReport, TypeSession, TypeContact, TypeNote}
+
+// ImportStats summarizes an Import.
+type ImportStats struct {
+	Imported int // new entries written
+	Skipped  int // entries already present with identical content
+	Renamed  int // entries whose filename collided with different content
+}
+
+// Export writes the ledger entries of the given types to w as a gzipped tar
+// archive. Entries keep their on-disk layout (e.g. "reports/<file>.md"), so
+// the archive is plain markdown once unpacked. Returns the number of entries
+// written.
+func (l *Ledger) Export(w io.Writer, types []string) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	count := 0
+	for _, t := range types {
+		sub := t + "s"
+		dir := filepath.Join(l.root, sub)
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return count, err
+		}
+		for _, f := range files {
+			if f.IsDir() || !strings.HasSuffix(f.Name(), ".md") {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, f.Name()))
+			if err != nil {
+				return count, fmt.Errorf("reading %s: %w", f.Name(), err)
+			}
+
+			modTime := time.Now()
+			if ts, ok := parseTimestampFromFilename(f.Name()); ok {
+				modTime = ts
+			}
+			hdr := &tar.Header{
+				Name:    path.Join(sub, f.Name()),
+				Mode:    0o644,
+				Size:    int64(len(data)),
+				ModTime: modTime,
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return count, fmt.Errorf("writing archive header: %w", err)
+			}
+			if _, err := tw.Write(data); err != nil {
+				return count, fmt.Errorf("writing archive entry: %w", err)
+			}
+			count++
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return count, fmt.Errorf("closing archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return count, fmt.Errorf("closing archive: %w", err)
+	}
+	return count, nil
+}
+
+// Import merges entries from an archive produced by Export into the ledger.
+// Entries already present with identical content are skipped; a filename
+// collision with different content is resolved with an incrementing index,
+// as Append does. Archive members outside the known type directories are
+// rejected.
+func (l *Ledger) Import(r io.Reader) (ImportStats, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var stats ImportStats
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return stats, fmt.Errorf("reading archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return stats, fmt.Errorf("reading archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		sub, name, err := archiveMemberPath(hdr.Name)
+		if err != nil {
+			return stats, err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return stats, fmt.Errorf("reading %s: %w", hdr.Name, err)
+		}
+
+		dir := filepath.Join(l.root, sub)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return stats, fmt.Errorf("creating context directory %s: %w", sub, err)
+		}
+
+		dest, status := mergeTarget(dir, name, data)
+		switch status {
+		case mergeSkip:
+			stats.Skipped++
+			continue
+		case mergeRename:
+			stats.Renamed++
+		}
+		if err := os.WriteFile(dest, data, 0o644); err != nil {
+			return stats, fmt.Errorf("writing %s: %w", name, err)
+		}
+		stats.Imported++
+	}
+
+	return stats, nil
+}
+
+type mergeStatus int
+
+const (
+	mergeNew mergeStatus = iota
+	mergeSkip
+	mergeRename
+)
+
+// mergeTarget picks where an imported file goes. Identical content already
+// present under the same name or an indexed variant (-2, -3, ...) is skipped.
+func mergeTarget(dir, name string, data []byte) (string, mergeStatus) {
+	base := strings.TrimSuffix(name, ".md")
+	dest := filepath.Join(dir, name)
+	status := mergeNew
+	for i := 2; ; i++ {
+		existing, err := os.ReadFile(dest)
+		if err != nil {
+			return dest, status
+		}
+		if bytes.Equal(existing, data) {
+			return dest, mergeSkip
+		}
+		status = mergeRename
+		dest = filepath.Join(dir, fmt.Sprintf("%s-%d.md", base, i))
+	}
+}
+
+// archiveMemberPath validates an archive member name of the form
+// "<type>s/<file>.md" and returns its parts.
+func archiveMemberPath(name string) (string, string, error) {
+	sub, file, ok := strings.Cut(path.Clean(name), "/")
+	if !ok || strings.Contains(file, "/") || !strings.HasSuffix(file, ".md") {
+		return "", "", fmt.Errorf("unexpected archive entry %q", name)
+	}
+	switch sub {
+	case TypeReport + "s", TypeResult + "s", TypeSession + "s", TypeContact + "s", TypeNote + "s":
+	default:
+		return "", "", fmt.Errorf("unexpected archive entry %q", name)
+	}
+	return sub, file, nil
+}
@@ -0,0 +1,122 @@
+package context
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src, err := NewLedger(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2026, 2, 19, 5, 0, 0, 0, time.UTC)
+	for _, e := range []Entry{
+		{Type: TypeReport, Label: "Morning Brief", Routine: "morning", Timestamp: ts, Content: "Geospatial contract."},
+		{Type: TypeNote, Label: "Call notes", Timestamp: ts, Content: "Follow up with Maxar."},
+		{Type: TypeResult, Label: "sam-gov", Timestamp: ts, Content: "{}"},
+	} {
+		if err := src.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	n, err := src.Export(&buf, ArchiveTypes)
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("exported %d entries, want 2 (results are opt-in)", n)
+	}
+	archive := buf.Bytes()
+
+	dst, err := NewLedger(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, err := dst.Import(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	if stats.Imported != 2 || stats.Skipped != 0 {
+		t.Errorf("first import stats = %+v", stats)
+	}
+
+	results, err := dst.Search("maxar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Label != "Call notes" || !results[0].Timestamp.Equal(ts) {
+		t.Errorf("imported note not searchable: %+v", results)
+	}
+
+	// Re-importing the same archive is a no-op.
+	stats, err = dst.Import(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Imported != 0 || stats.Skipped != 2 {
+		t.Errorf("re-import stats = %+v", stats)
+	}
+}
+
+func TestImportRenamesConflictingEntry(t *testing.T) {
+	dir := t.TempDir()
+	l, err := NewLedger(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := "2026-02-19T050000-call-notes.md"
+	if err := os.WriteFile(filepath.Join(dir, "notes", name), []byte("local"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := l.Import(bytes.NewReader(testArchive(t, "notes/"+name, "imported")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Imported != 1 || stats.Renamed != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "notes", "2026-02-19T050000-call-notes-2.md"))
+	if err != nil || string(data) != "imported" {
+		t.Errorf("renamed entry = %q, %v", data, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "notes", name)); string(data) != "local" {
+		t.Error("existing entry was overwritten")
+	}
+}
+
+func TestImportRejectsUnexpectedPaths(t *testing.T) {
+	for _, name := range []string{"../escape.md", "notes/../../escape.md", "other/x.md", "notes/x.txt", "notes/sub/x.md"} {
+		l, err := NewLedger(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := l.Import(bytes.NewReader(testArchive(t, name, "x"))); err == nil {
+			t.Errorf("expected error for archive entry %q", name)
+		}
+	}
+}
+
+func testArchive(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
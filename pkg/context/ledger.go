@@ -160,7 +160,23 @@ func (l *Ledger) List(entryType string, limit int) ([]Entry, error) {
 	return entries, nil
 }
 
+// Limits that keep GatherContext varied when a routine runs daily.
+const (
+	// maxGatherPerSeries caps how many entries of one series (same routine,
+	// type, and label — e.g. one routine's reports, or one source's results)
+	// are gathered.
+	maxGatherPerSeries = 3
+	// duplicateSimilarity is the word-overlap ratio at or above which an
+	// entry is treated as a near-duplicate of the newer entry in its series.
+	duplicateSimilarity = 0.85
+)
+
 // GatherContext concatenates recent entries up to maxBytes for LLM context.
+// Entries produced by routines are grouped into series (same routine, type,
+// and label): near-duplicates of a newer entry in the series are collapsed,
+// and at most maxGatherPerSeries entries per series are included, so repeated
+// daily runs don't crowd out everything else. Entries are never removed from
+// disk.
 func (l *Ledger) GatherContext(maxBytes int) (string, error) {
 	var all []Entry
 
@@ -190,7 +206,7 @@ func (l *Ledger) GatherContext(maxBytes int) (string, error) {
 	})
 
 	var b strings.Builder
-	for _, e := range all {
+	for _, e := range dedupeSeries(all) {
 		chunk := fmt.Sprintf("## %s (%s)\n%s\n\n", e.Label, e.Timestamp.Format("2006-01-02 15:04"), e.Content)
 		if b.Len()+len(chunk) > maxBytes {
 			break
@@ -201,6 +217,65 @@ func (l *Ledger) GatherContext(maxBytes int) (string, error) {
 	return b.String(), nil
 }
 
+// dedupeSeries filters newest-first entries, dropping routine entries that
+// are near-duplicates of the previously kept entry in their series or that
+// exceed maxGatherPerSeries. Entries without a routine pass through.
+func dedupeSeries(entries []Entry) []Entry {
+	type series struct {
+		kept  int
+		words map[string]bool // word set of the last kept entry
+	}
+	seen := make(map[string]*series)
+
+	var out []Entry
+	for _, e := range entries {
+		if e.Routine == "" {
+			out = append(out, e)
+			continue
+		}
+		key := e.Routine + "\x00" + e.Type + "\x00" + e.Label
+		s := seen[key]
+		if s == nil {
+			s = &series{}
+			seen[key] = s
+		}
+		if s.kept >= maxGatherPerSeries {
+			continue
+		}
+		words := wordSet(e.Content)
+		if s.words != nil && similarity(s.words, words) >= duplicateSimilarity {
+			continue
+		}
+		s.kept++
+		s.words = words
+		out = append(out, e)
+	}
+	return out
+}
+
+// wordSet returns the set of lowercase words in text.
+func wordSet(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(strings.ToLower(text)) {
+		words[w] = true
+	}
+	return words
+}
+
+// similarity returns the Jaccard index of two word sets.
+func similarity(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
 // TypeStats holds aggregate statistics for one entry type.
 type TypeStats struct {
 	Count    int
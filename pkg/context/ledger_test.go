@@ -152,6 +152,58 @@ func TestGatherContext(t *testing.T) {
 	}
 }
 
+func TestGatherContextCollapsesNearDuplicates(t *testing.T) {
+	l, err := NewLedger(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLedger: %v", err)
+	}
+
+	ts := time.Date(2026, 2, 19, 5, 0, 0, 0, time.UTC)
+	body := "Contract awards: Alpha Corp, Bravo Inc, Charlie LLC. Pipeline steady. No new protests filed this week."
+	l.Append(Entry{Type: TypeReport, Label: "Brief", Routine: "morning", Timestamp: ts, Content: body + " day3"})
+	l.Append(Entry{Type: TypeReport, Label: "Brief", Routine: "morning", Timestamp: ts.AddDate(0, 0, -1), Content: body + " day2"})
+	l.Append(Entry{Type: TypeReport, Label: "Brief", Routine: "morning", Timestamp: ts.AddDate(0, 0, -2), Content: "Entirely different: new solicitation for geospatial imagery."})
+	l.Append(Entry{Type: TypeNote, Label: "Note", Timestamp: ts.AddDate(0, 0, -3), Content: body})
+
+	ctx, err := l.GatherContext(100_000)
+	if err != nil {
+		t.Fatalf("GatherContext: %v", err)
+	}
+	if strings.Contains(ctx, "day2") {
+		t.Error("near-duplicate consecutive report should be collapsed")
+	}
+	if !strings.Contains(ctx, "day3") || !strings.Contains(ctx, "geospatial imagery") {
+		t.Error("newest and distinct reports should be kept")
+	}
+	if !strings.Contains(ctx, "## Note") {
+		t.Error("entries without a routine are never collapsed")
+	}
+}
+
+func TestGatherContextCapsPerSeries(t *testing.T) {
+	l, err := NewLedger(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLedger: %v", err)
+	}
+
+	ts := time.Date(2026, 2, 19, 5, 0, 0, 0, time.UTC)
+	for i := 0; i < maxGatherPerSeries+2; i++ {
+		l.Append(Entry{Type: TypeReport, Label: "Brief", Routine: "morning", Timestamp: ts.AddDate(0, 0, -i), Content: fmt.Sprintf("unique-%d", i)})
+	}
+	l.Append(Entry{Type: TypeReport, Label: "Weekly", Routine: "weekly", Timestamp: ts.AddDate(0, 0, -30), Content: "weekly summary"})
+
+	ctx, err := l.GatherContext(100_000)
+	if err != nil {
+		t.Fatalf("GatherContext: %v", err)
+	}
+	if got := strings.Count(ctx, "## Brief"); got != maxGatherPerSeries {
+		t.Errorf("expected %d entries for the morning series, got %d", maxGatherPerSeries, got)
+	}
+	if !strings.Contains(ctx, "weekly summary") {
+		t.Error("older entry from another routine should make it into context")
+	}
+}
+
 func TestFileFormat(t *testing.T) {
 	dir := t.TempDir()
 	l, err := NewLedger(dir)
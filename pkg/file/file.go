@@ -0,0 +1,304 @@
+// Package file provides a service adapter that reads local files and
+// directories, so local data can be synthesized alongside remote sources.
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jcadam/burrow/pkg/config"
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+const (
+	defaultMaxFiles = 50
+	// maxFileBytes bounds each file read, matching the REST response limit.
+	maxFileBytes = 10 << 20
+	// maxTotalBytes bounds all files read by one call.
+	maxTotalBytes = 10 << 20
+)
+
+// FileService implements services.Service for local files. The endpoint is
+// a base path; each tool's path is resolved against it and may be a file, a
+// directory (its files are read), or a glob. A "read" tool that reads the
+// endpoint itself is always available. Nothing leaves the machine.
+type FileService struct {
+	name     string
+	base     string
+	tools    map[string]config.ToolConfig
+	maxFiles int
+}
+
+// NewFileService creates a file service from config. A leading "~/" in the
+// endpoint is expanded to the home directory.
+func NewFileService(cfg config.ServiceConfig) *FileService {
+	tools := make(map[string]config.ToolConfig, len(cfg.Tools))
+	for _, t := range cfg.Tools {
+		tools[t.Name] = t
+	}
+	maxFiles := cfg.MaxItems
+	if maxFiles <= 0 {
+		maxFiles = defaultMaxFiles
+	}
+	return &FileService{
+		name:     cfg.Name,
+		base:     expandHome(cfg.Endpoint),
+		tools:    tools,
+		maxFiles: maxFiles,
+	}
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+func (f *FileService) Name() string { return f.name }
+
+// placeholderPattern matches {name} placeholders in tool paths.
+var placeholderPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Execute reads the tool's path. {name} placeholders in the path are filled
+// from params, which the executor has already expanded, so a source can name
+// dated files with {{today}}-style helpers. A single file is returned as is
+// (CSV converted to JSON); several files are returned as a JSON listing.
+func (f *FileService) Execute(_ context.Context, tool string, params map[string]string) (*services.Result, error) {
+	path := f.base
+	if t, ok := f.tools[tool]; ok {
+		var missing []string
+		rel := placeholderPattern.ReplaceAllStringFunc(t.Path, func(m string) string {
+			name := m[1 : len(m)-1]
+			v, ok := params[name]
+			if !ok {
+				missing = append(missing, name)
+			}
+			return v
+		})
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("tool %q path needs params: %s", tool, strings.Join(missing, ", "))
+		}
+		if rel != "" {
+			path = filepath.Join(f.base, rel)
+		}
+	} else if tool != "read" {
+		return nil, fmt.Errorf("service %q has no tool %q", f.name, tool)
+	}
+
+	result := &services.Result{
+		Service:   f.name,
+		Tool:      tool,
+		URL:       "file://" + path,
+		Timestamp: time.Now().UTC(),
+	}
+
+	files, err := f.match(path)
+	if err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+	if len(files) == 0 {
+		result.Empty = true
+		return result, nil
+	}
+
+	if len(files) == 1 && files[0] == path {
+		data, contentType, err := readFile(path)
+		if err != nil {
+			result.Error = err.Error()
+			return result, nil
+		}
+		result.Data, result.ContentType = data, contentType
+		return result, nil
+	}
+
+	data, err := f.listing(path, files)
+	if err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+	result.Data, result.ContentType = data, "application/json"
+	return result, nil
+}
+
+// match resolves path to the files to read: the file itself, a directory's
+// regular files, or a glob's matching regular files. Multiple files are
+// ordered newest first and capped at maxFiles.
+func (f *FileService) match(path string) ([]string, error) {
+	var candidates []string
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && !info.IsDir():
+		return []string{path}, nil
+	case err == nil:
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading directory: %w", err)
+		}
+		for _, e := range entries {
+			candidates = append(candidates, filepath.Join(path, e.Name()))
+		}
+	case strings.ContainsAny(path, "*?["):
+		candidates, err = filepath.Glob(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("file not found: %s", path)
+	}
+
+	type candidate struct {
+		path string
+		mod  time.Time
+	}
+	var files []candidate
+	for _, c := range candidates {
+		info, err := os.Stat(c)
+		if err != nil || !info.Mode().IsRegular() || strings.HasPrefix(filepath.Base(c), ".") {
+			continue
+		}
+		files = append(files, candidate{c, info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if !files[i].mod.Equal(files[j].mod) {
+			return files[i].mod.After(files[j].mod)
+		}
+		return files[i].path < files[j].path
+	})
+	if len(files) > f.maxFiles {
+		files = files[:f.maxFiles]
+	}
+	paths := make([]string, len(files))
+	for i, c := range files {
+		paths[i] = c.path
+	}
+	return paths, nil
+}
+
+// fileEntry is one file in a multi-file listing.
+type fileEntry struct {
+	Path     string          `json:"path"`
+	Modified string          `json:"modified"`
+	Content  json.RawMessage `json:"content,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// listing renders several files as {"files": [...]}, with paths relative to
+// the matched directory or glob. Files past the total size budget are listed
+// with an error instead of their content.
+func (f *FileService) listing(path string, files []string) ([]byte, error) {
+	dir := path
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+
+	entries := make([]fileEntry, 0, len(files))
+	total := 0
+	for _, p := range files {
+		entry := fileEntry{Path: p}
+		if rel, err := filepath.Rel(dir, p); err == nil {
+			entry.Path = rel
+		}
+		if info, err := os.Stat(p); err == nil {
+			entry.Modified = info.ModTime().UTC().Format(time.RFC3339)
+		}
+
+		data, contentType, err := readFile(p)
+		switch {
+		case err != nil:
+			entry.Error = err.Error()
+		case total+len(data) > maxTotalBytes:
+			entry.Error = "skipped: total size limit reached"
+		default:
+			total += len(data)
+			entry.Content = embed(data, contentType)
+		}
+		entries = append(entries, entry)
+	}
+	return json.Marshal(map[string]any{"files": entries})
+}
+
+// embed returns file content for a JSON listing: JSON as is, anything else
+// as a string.
+func embed(data []byte, contentType string) json.RawMessage {
+	if contentType == "application/json" && json.Valid(data) {
+		return data
+	}
+	s, _ := json.Marshal(string(data))
+	return s
+}
+
+// readFile reads a file up to maxFileBytes and returns its data and media
+// type. CSV is converted to a JSON array of objects keyed by the header row,
+// so transforms and synthesis treat it like any JSON response.
+func readFile(path string) ([]byte, string, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer fh.Close()
+	data, err := io.ReadAll(io.LimitReader(fh, maxFileBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+	if len(data) > maxFileBytes {
+		return nil, "", fmt.Errorf("%s exceeds %d bytes", filepath.Base(path), maxFileBytes)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return data, "application/json", nil
+	case ".csv":
+		converted, err := csvToJSON(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+		}
+		return converted, "application/json", nil
+	case ".md", ".markdown":
+		return data, "text/markdown", nil
+	case ".yaml", ".yml":
+		return data, "application/yaml", nil
+	}
+	if !utf8.Valid(data) {
+		return data, "application/octet-stream", nil
+	}
+	return data, "text/plain", nil
+}
+
+// csvToJSON converts CSV with a header row into a JSON array of objects.
+func csvToJSON(data []byte) ([]byte, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]map[string]string, 0, len(records))
+	if len(records) > 0 {
+		header := records[0]
+		for _, rec := range records[1:] {
+			row := make(map[string]string, len(header))
+			for i, name := range header {
+				if i < len(rec) {
+					row[name] = rec[i]
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	return json.Marshal(rows)
+}
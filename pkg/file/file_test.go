@@ -0,0 +1,129 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/config"
+)
+
+func writeFile(t *testing.T, path, content string, mod time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExecuteReadsCSVAsJSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "exports", "sales-2026-10-16.csv"), "region,total\nwest,120\neast,95\n", time.Now())
+
+	svc := NewFileService(config.ServiceConfig{
+		Name:     "local",
+		Type:     "file",
+		Endpoint: dir,
+		Tools:    []config.ToolConfig{{Name: "sales", Path: "exports/sales-{date}.csv"}},
+	})
+	result, err := svc.Execute(context.Background(), "sales", map[string]string{"date": "2026-10-16"})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("unexpected error: %s", result.Error)
+	}
+	if result.ContentType != "application/json" {
+		t.Errorf("ContentType = %q", result.ContentType)
+	}
+	if want := `[{"region":"west","total":"120"},{"region":"east","total":"95"}]`; string(result.Data) != want {
+		t.Errorf("Data = %s, want %s", result.Data, want)
+	}
+}
+
+func TestExecuteMissingPlaceholderParam(t *testing.T) {
+	svc := NewFileService(config.ServiceConfig{
+		Name:     "local",
+		Endpoint: t.TempDir(),
+		Tools:    []config.ToolConfig{{Name: "sales", Path: "sales-{date}.csv"}},
+	})
+	if _, err := svc.Execute(context.Background(), "sales", nil); err == nil || !strings.Contains(err.Error(), "date") {
+		t.Errorf("expected missing param error, got %v", err)
+	}
+}
+
+func TestExecuteMissingFile(t *testing.T) {
+	svc := NewFileService(config.ServiceConfig{Name: "local", Endpoint: filepath.Join(t.TempDir(), "gone.txt")})
+	result, err := svc.Execute(context.Background(), "read", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !strings.HasPrefix(result.Error, "file not found") {
+		t.Errorf("expected file not found error, got %q", result.Error)
+	}
+}
+
+func TestExecuteDirectoryListsNewestFirst(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeFile(t, filepath.Join(dir, "old.md"), "# Old note", now.Add(-2*time.Hour))
+	writeFile(t, filepath.Join(dir, "new.json"), `{"status": "ok"}`, now)
+	writeFile(t, filepath.Join(dir, "mid.txt"), "middle", now.Add(-time.Hour))
+	writeFile(t, filepath.Join(dir, ".hidden"), "secret", now)
+	writeFile(t, filepath.Join(dir, "sub", "nested.txt"), "not read", now)
+
+	svc := NewFileService(config.ServiceConfig{Name: "notes", Endpoint: dir, MaxItems: 2})
+	result, err := svc.Execute(context.Background(), "read", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	var listing struct {
+		Files []struct {
+			Path    string          `json:"path"`
+			Content json.RawMessage `json:"content"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(result.Data, &listing); err != nil {
+		t.Fatalf("decoding listing: %v\n%s", err, result.Data)
+	}
+	if len(listing.Files) != 2 {
+		t.Fatalf("expected 2 files (max_items), got %d: %s", len(listing.Files), result.Data)
+	}
+	if listing.Files[0].Path != "new.json" || string(listing.Files[0].Content) != `{"status":"ok"}` {
+		t.Errorf("file 0 = %s %s", listing.Files[0].Path, listing.Files[0].Content)
+	}
+	if listing.Files[1].Path != "mid.txt" || string(listing.Files[1].Content) != `"middle"` {
+		t.Errorf("file 1 = %s %s", listing.Files[1].Path, listing.Files[1].Content)
+	}
+}
+
+func TestExecuteGlobWithNoMatchesIsEmpty(t *testing.T) {
+	svc := NewFileService(config.ServiceConfig{
+		Name:     "notes",
+		Endpoint: t.TempDir(),
+		Tools:    []config.ToolConfig{{Name: "logs", Path: "*.log"}},
+	})
+	result, err := svc.Execute(context.Background(), "logs", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !result.Empty || result.Error != "" {
+		t.Errorf("expected empty result, got empty=%v error=%q", result.Empty, result.Error)
+	}
+}
+
+func TestExecuteUnknownTool(t *testing.T) {
+	svc := NewFileService(config.ServiceConfig{Name: "notes", Endpoint: t.TempDir()})
+	if _, err := svc.Execute(context.Background(), "search", nil); err == nil {
+		t.Error("expected error for unknown tool")
+	}
+}
@@ -2,19 +2,25 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/jcadam/burrow/pkg/config"
 	"github.com/jcadam/burrow/pkg/privacy"
+	"github.com/jcadam/burrow/pkg/ratelimit"
 	"github.com/jcadam/burrow/pkg/services"
+	"github.com/jcadam/burrow/pkg/values"
 )
 
 // RESTService implements services.Service for REST API endpoints.
@@ -24,9 +30,23 @@ type RESTService struct {
 	auth       config.AuthConfig
 	tools      map[string]config.ToolConfig
 	client     *http.Client
+	retries    int                          // retries for 429/503 responses carrying Retry-After
 	expandFunc func(string) (string, error) // optional template expansion
+
+	// limiter, when set, holds each request (pages and retries included)
+	// within the service's rate_limit.
+	limiter *ratelimit.Limiter
+}
+
+// SetLimiter makes every request the service sends wait on l first.
+func (r *RESTService) SetLimiter(l *ratelimit.Limiter) {
+	r.limiter = l
 }
 
+// maxRetryWait is the longest Retry-After a request waits out in place.
+// Longer waits are left to the scheduler's backoff.
+const maxRetryWait = 2 * time.Minute
+
 // SetExpandFunc sets a function for expanding template references in tool paths
 // and other string fields before URL construction.
 func (r *RESTService) SetExpandFunc(fn func(string) (string, error)) {
@@ -47,6 +67,7 @@ func NewRESTService(cfg config.ServiceConfig, privacyCfg *privacy.Config, proxyU
 	// Each service gets its own transport to prevent connection pool sharing.
 	// Shared pools break compartmentalization (spec §2.2).
 	baseTransport := &http.Transport{}
+	cfg.Transport.Apply(baseTransport)
 	if proxyURL != "" {
 		if parsed, err := url.Parse(proxyURL); err == nil && parsed != nil {
 			baseTransport.Proxy = http.ProxyURL(parsed)
@@ -62,7 +83,8 @@ func NewRESTService(cfg config.ServiceConfig, privacyCfg *privacy.Config, proxyU
 		endpoint: cfg.Endpoint,
 		auth:     cfg.Auth,
 		tools:    tools,
-		client:   &http.Client{Timeout: 30 * time.Second, Transport: transport},
+		client:   &http.Client{Timeout: 30 * time.Second, Transport: transport, CheckRedirect: cfg.CheckRedirect()},
+		retries:  cfg.Retries,
 	}
 }
 
@@ -76,6 +98,17 @@ func (r *RESTService) Name() string { return r.name }
 
 // Execute runs a named tool against the REST endpoint.
 func (r *RESTService) Execute(ctx context.Context, tool string, params map[string]string) (*services.Result, error) {
+	return r.execute(ctx, tool, params, services.Validators{})
+}
+
+// ExecuteConditional runs a named tool, sending If-None-Match and
+// If-Modified-Since from v. A 304 response yields a Result with NotModified
+// set and no data.
+func (r *RESTService) ExecuteConditional(ctx context.Context, tool string, params map[string]string, v services.Validators) (*services.Result, error) {
+	return r.execute(ctx, tool, params, v)
+}
+
+func (r *RESTService) execute(ctx context.Context, tool string, params map[string]string, cond services.Validators) (*services.Result, error) {
 	tc, ok := r.tools[tool]
 	if !ok {
 		return nil, fmt.Errorf("service %q has no tool %q", r.name, tool)
@@ -97,33 +130,40 @@ func (r *RESTService) Execute(ctx context.Context, tool string, params map[strin
 		return nil, fmt.Errorf("building URL: %w", err)
 	}
 
-	var reqBody io.Reader
-	if tc.Body != "" {
-		if val, ok := params[tc.Body]; ok {
-			reqBody = strings.NewReader(val)
+	// A rate-limited response that says when to come back is retried after
+	// exactly that wait, within the service's retry budget.
+	var resp *http.Response
+	for attempt := 0; ; attempt++ {
+		req, err := r.newRequest(ctx, tc, reqURL, params, cond)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		resp, err = r.do(req)
+		if err != nil {
+			return &services.Result{
+				Service:   r.name,
+				Tool:      tool,
+				URL:       reqURL,
+				Timestamp: time.Now().UTC(),
+				Error:     err.Error(),
+			}, nil
+		}
+		wait, ok := retryAfter(resp, time.Now())
+		if !ok || attempt >= r.retries || wait > maxRetryWait {
+			break
+		}
+		resp.Body.Close()
+		select {
+		case <-ctx.Done():
+			return &services.Result{
+				Service:   r.name,
+				Tool:      tool,
+				URL:       reqURL,
+				Timestamp: time.Now().UTC(),
+				Error:     ctx.Err().Error(),
+			}, nil
+		case <-time.After(wait):
 		}
-	}
-
-	req, err := http.NewRequestWithContext(ctx, tc.Method, reqURL, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-
-	if reqBody != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
-
-	r.applyAuth(req)
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return &services.Result{
-			Service:   r.name,
-			Tool:      tool,
-			URL:       reqURL,
-			Timestamp: time.Now().UTC(),
-			Error:     err.Error(),
-		}, nil
 	}
 	defer resp.Body.Close()
 
@@ -139,34 +179,201 @@ func (r *RESTService) Execute(ctx context.Context, tool string, params map[strin
 		}, nil
 	}
 
+	headers := captureHeaders(resp.Header, tc.CaptureHeaders)
+	validators := services.Validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		return &services.Result{
+			Service:     r.name,
+			Tool:        tool,
+			URL:         reqURL,
+			Timestamp:   time.Now().UTC(),
+			Headers:     headers,
+			Validators:  validators,
+			NotModified: true,
+		}, nil
+	}
+
 	if resp.StatusCode >= 400 {
 		errMsg := fmt.Sprintf("HTTP %d", resp.StatusCode)
-		if len(body) > 0 {
-			snippet := body
-			if len(snippet) > 512 {
-				snippet = snippet[:512]
-			}
-			errMsg += ": " + string(snippet)
+		wait, hasWait := retryAfter(resp, time.Now())
+		if hasWait {
+			errMsg += fmt.Sprintf(" (retry after %s)", wait)
+		}
+		if snippet := ErrorSnippet(body, resp.Header.Get("Content-Type")); snippet != "" {
+			errMsg += ": " + snippet
 		}
 		return &services.Result{
-			Service:   r.name,
-			Tool:      tool,
-			Data:      body,
-			URL:       reqURL,
-			Timestamp: time.Now().UTC(),
-			Error:     errMsg,
+			Service:    r.name,
+			Tool:       tool,
+			Data:       body,
+			URL:        reqURL,
+			Timestamp:  time.Now().UTC(),
+			Error:      errMsg,
+			Headers:    headers,
+			RetryAfter: wait,
 		}, nil
 	}
 
 	return &services.Result{
-		Service:   r.name,
-		Tool:      tool,
-		Data:      body,
-		URL:       reqURL,
-		Timestamp: time.Now().UTC(),
+		Service:     r.name,
+		Tool:        tool,
+		Data:        body,
+		URL:         reqURL,
+		Timestamp:   time.Now().UTC(),
+		Headers:     headers,
+		Validators:  validators,
+		Empty:       tc.ResultsPath != "" && emptyAt(body, tc.ResultsPath),
+		ItemsPath:   tc.ResultsPath,
+		ContentType: mediaType(resp.Header.Get("Content-Type")),
 	}, nil
 }
 
+// newRequest builds the HTTP request for a tool call. It is called once per
+// attempt because a request body can only be read once.
+func (r *RESTService) newRequest(ctx context.Context, tc config.ToolConfig, reqURL string, params map[string]string, cond services.Validators) (*http.Request, error) {
+	var reqBody io.Reader
+	if tc.Body != "" {
+		if val, ok := params[tc.Body]; ok {
+			reqBody = strings.NewReader(val)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, tc.Method, reqURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if cond.ETag != "" {
+		req.Header.Set("If-None-Match", cond.ETag)
+	}
+	if cond.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cond.LastModified)
+	}
+
+	auth := r.auth
+	if tc.Auth != nil {
+		auth = *tc.Auth
+	}
+	auth.Apply(req)
+	return req, nil
+}
+
+// retryAfter returns the wait a 429 or 503 response asks for in its
+// Retry-After header, given as seconds or an HTTP date. ok is false for
+// other statuses and for a missing or unparseable header.
+func retryAfter(resp *http.Response, now time.Time) (wait time.Duration, ok bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	v := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	at, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	return max(at.Sub(now).Round(time.Second), 0), true
+}
+
+// emptyAt reports whether the value at path in a JSON body is an empty
+// array, object, or string, null, or zero. A missing path or non-JSON body
+// is not empty.
+func emptyAt(body []byte, path string) bool {
+	v, ok := values.Extract(body, path)
+	return ok && (v == "" || v == "0")
+}
+
+// mediaType returns the media type of a Content-Type value, lowercased and
+// without parameters.
+func mediaType(contentType string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mt
+	}
+	return ""
+}
+
+// maxErrorBody is how much of an error response body is kept in the
+// result's error message.
+const maxErrorBody = 512
+
+// ErrorSnippet returns the start of an error response body for the error
+// message: at most maxErrorBody bytes, cut at a character boundary. Bodies
+// that aren't text, by content type or by content, are replaced with a note
+// so binary data never reaches reports or prompts.
+func ErrorSnippet(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if !isTextMedia(mediaType(contentType)) {
+		return "(binary error body omitted)"
+	}
+	snippet := body
+	truncated := len(snippet) > maxErrorBody
+	if truncated {
+		snippet = snippet[:maxErrorBody]
+		// Back up to the start of a character split by the cut.
+		for i := 0; i < utf8.UTFMax && len(snippet) > 0 && !utf8.Valid(snippet); i++ {
+			snippet = snippet[:len(snippet)-1]
+		}
+	}
+	if !utf8.Valid(snippet) || bytes.IndexByte(snippet, 0) >= 0 {
+		return "(binary error body omitted)"
+	}
+	s := strings.TrimSpace(string(snippet))
+	if truncated {
+		s += "..."
+	}
+	return s
+}
+
+// isTextMedia reports whether a media type is text that reads sensibly in
+// an error message. An empty type (no Content-Type header) counts as text;
+// ErrorSnippet still checks the bytes.
+func isTextMedia(mt string) bool {
+	switch {
+	case mt == "", strings.HasPrefix(mt, "text/"),
+		strings.HasSuffix(mt, "/json"), strings.HasSuffix(mt, "+json"),
+		strings.HasSuffix(mt, "/xml"), strings.HasSuffix(mt, "+xml"),
+		mt == "application/javascript", mt == "application/x-www-form-urlencoded":
+		return true
+	}
+	return false
+}
+
+// captureHeaders returns the named response headers that are present, keyed
+// by canonical name. Repeated headers are joined with ", ". Returns nil when
+// nothing is configured or present.
+func captureHeaders(h http.Header, names []string) map[string]string {
+	var captured map[string]string
+	for _, name := range names {
+		key := http.CanonicalHeaderKey(strings.TrimSpace(name))
+		vals := h.Values(key)
+		if len(vals) == 0 {
+			continue
+		}
+		if captured == nil {
+			captured = make(map[string]string, len(names))
+		}
+		captured[key] = strings.Join(vals, ", ")
+	}
+	return captured
+}
+
 // unreplacedPlaceholder matches {name} placeholders remaining after substitution,
 // excluding Go template expressions {{...}} which are handled by expandFunc.
 var unreplacedPlaceholder = regexp.MustCompile(`\{([^{}]+)\}`)
@@ -224,27 +431,17 @@ func (r *RESTService) buildURL(path string, tc config.ToolConfig, params map[str
 	return resolved.String(), nil
 }
 
-func (r *RESTService) applyAuth(req *http.Request) {
-	switch r.auth.Method {
-	case "api_key":
-		paramName := r.auth.KeyParam
-		if paramName == "" {
-			paramName = "api_key"
-		}
-		q := req.URL.Query()
-		q.Set(paramName, r.auth.Key)
-		req.URL.RawQuery = q.Encode()
-	case "api_key_header":
-		headerName := r.auth.KeyParam
-		if headerName == "" {
-			headerName = "X-API-Key"
-		}
-		req.Header.Set(headerName, r.auth.Key)
-	case "bearer":
-		req.Header.Set("Authorization", "Bearer "+r.auth.Token)
-	case "user_agent":
-		req.Header.Set("User-Agent", r.auth.Value)
-		// Signal the privacy transport to preserve this auth-required UA.
-		req.Header.Set("X-Burrow-Preserve-UA", "true")
+// do sends req once it fits the service's rate limit, counting it against
+// the run's request budget. The wait is bounded only by the request's
+// context, not the client's timeout.
+func (r *RESTService) do(req *http.Request) (*http.Response, error) {
+	if err := ratelimit.Take(req.Context()); err != nil {
+		return nil, err
+	}
+	if r.limiter != nil {
+		if err := r.limiter.Wait(req.Context()); err != nil {
+			return nil, fmt.Errorf("waiting for rate limit: %w", err)
+		}
 	}
+	return r.client.Do(req)
 }
@@ -7,9 +7,12 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/jcadam/burrow/pkg/config"
 	"github.com/jcadam/burrow/pkg/privacy"
+	"github.com/jcadam/burrow/pkg/ratelimit"
 )
 
 func newTestServer(handler http.HandlerFunc) *httptest.Server {
@@ -233,6 +236,61 @@ func TestExecuteHTTPError(t *testing.T) {
 	}
 }
 
+func TestExecuteHTTPErrorLargeBody(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("<html>" + strings.Repeat("é", 2000) + "</html>"))
+	})
+	defer srv.Close()
+
+	svc := NewRESTService(config.ServiceConfig{
+		Name:     "error-test",
+		Endpoint: srv.URL,
+		Auth:     config.AuthConfig{Method: "none"},
+		Tools:    []config.ToolConfig{{Name: "fetch", Method: "GET", Path: "/"}},
+	}, nil, "")
+
+	result, err := svc.Execute(context.Background(), "fetch", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(result.Error) > len("HTTP 500: ")+maxErrorBody+len("...") {
+		t.Errorf("error not capped: %d bytes", len(result.Error))
+	}
+	if !utf8.ValidString(result.Error) {
+		t.Error("capped error splits a character")
+	}
+	if !strings.HasSuffix(result.Error, "...") {
+		t.Errorf("expected truncation marker, got %q", result.Error)
+	}
+}
+
+func TestErrorSnippet(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		want        string
+	}{
+		{"empty", "", "text/plain", ""},
+		{"json", `{"error": "bad"}`, "application/json; charset=utf-8", `{"error": "bad"}`},
+		{"problem_json", `{"title": "bad"}`, "application/problem+json", `{"title": "bad"}`},
+		{"no_content_type", "plain failure\n", "", "plain failure"},
+		{"image", "\x89PNG\r\n", "image/png", "(binary error body omitted)"},
+		{"octet_stream", "text-looking", "application/octet-stream", "(binary error body omitted)"},
+		{"binary_bytes", "abc\x00\xff", "", "(binary error body omitted)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorSnippet([]byte(tt.body), tt.contentType); got != tt.want {
+				t.Errorf("ErrorSnippet = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestExecuteAbsoluteToolPath(t *testing.T) {
 	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/v2/search" {
@@ -462,6 +520,133 @@ func TestTransportIsolationWithPrivacy(t *testing.T) {
 	}
 }
 
+func TestTransportTuning(t *testing.T) {
+	svc := NewRESTService(config.ServiceConfig{
+		Name:     "paged",
+		Endpoint: "http://localhost",
+		Auth:     config.AuthConfig{Method: "none"},
+		Transport: config.TransportConfig{
+			MaxConnsPerHost:     4,
+			MaxIdleConnsPerHost: 4,
+			IdleTimeout:         90,
+			DialTimeout:         5,
+		},
+	}, nil, "")
+
+	tr, ok := svc.client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("transport is %T, want *http.Transport", svc.client.Transport)
+	}
+	if tr.MaxConnsPerHost != 4 || tr.MaxIdleConnsPerHost != 4 {
+		t.Errorf("conns = %d/%d, want 4/4", tr.MaxConnsPerHost, tr.MaxIdleConnsPerHost)
+	}
+	if tr.IdleConnTimeout != 90*time.Second {
+		t.Errorf("IdleConnTimeout = %v, want 90s", tr.IdleConnTimeout)
+	}
+	if tr.DialContext == nil {
+		t.Error("expected a dialer with the configured timeout")
+	}
+
+	// Untuned services keep Go's defaults.
+	plain := NewRESTService(config.ServiceConfig{Name: "plain", Endpoint: "http://localhost"}, nil, "")
+	if tr := plain.client.Transport.(*http.Transport); tr.MaxConnsPerHost != 0 || tr.DialContext != nil {
+		t.Error("untuned transport should keep defaults")
+	}
+}
+
+func TestToolAuthOverride(t *testing.T) {
+	got := map[string]string{}
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		got[r.URL.Path] = r.Header.Get("Authorization") + "|" + r.URL.Query().Get("key")
+		w.Write([]byte(`{}`))
+	})
+	defer srv.Close()
+
+	svc := NewRESTService(config.ServiceConfig{
+		Name:     "mixed",
+		Endpoint: srv.URL,
+		Auth:     config.AuthConfig{Method: "bearer", Token: "service-token"},
+		Tools: []config.ToolConfig{
+			{Name: "private", Method: "GET", Path: "/private"},
+			{Name: "public", Method: "GET", Path: "/public", Auth: &config.AuthConfig{Method: "none"}},
+			{Name: "scoped", Method: "GET", Path: "/scoped", Auth: &config.AuthConfig{Method: "api_key", Key: "scoped-key", KeyParam: "key"}},
+		},
+	}, nil, "")
+
+	for _, tool := range []string{"private", "public", "scoped"} {
+		if _, err := svc.Execute(context.Background(), tool, nil); err != nil {
+			t.Fatalf("Execute %s: %v", tool, err)
+		}
+	}
+	want := map[string]string{
+		"/private": "Bearer service-token|",
+		"/public":  "|",
+		"/scoped":  "|scoped-key",
+	}
+	for path, w := range want {
+		if got[path] != w {
+			t.Errorf("%s sent %q, want %q", path, got[path], w)
+		}
+	}
+}
+
+func TestFollowRedirects(t *testing.T) {
+	var leaked []string
+	target := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		leaked = append(leaked, r.Header.Get("X-API-Key"))
+		w.Write([]byte(`{"ok":true}`))
+	})
+	defer target.Close()
+	// The target answers on localhost, a different host from the
+	// redirecting server's 127.0.0.1.
+	other := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	origin := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/away":
+			http.Redirect(w, r, other+"/data", http.StatusFound)
+		case "/moved":
+			http.Redirect(w, r, "/data", http.StatusMovedPermanently)
+		default:
+			w.Write([]byte(`{"ok":true}`))
+		}
+	})
+	defer origin.Close()
+
+	tests := []struct {
+		policy, tool string
+		wantErr      bool
+	}{
+		{"", "away", false},
+		{"all", "away", false},
+		{"same_host", "away", true},
+		{"same_host", "moved", false},
+		{"none", "moved", true},
+	}
+	for _, tt := range tests {
+		leaked = nil
+		svc := NewRESTService(config.ServiceConfig{
+			Name:            "redirects",
+			Endpoint:        origin.URL,
+			Auth:            config.AuthConfig{Method: "api_key_header", Key: "secret"},
+			FollowRedirects: tt.policy,
+			Tools: []config.ToolConfig{
+				{Name: "away", Method: "GET", Path: "/away"},
+				{Name: "moved", Method: "GET", Path: "/moved"},
+			},
+		}, nil, "")
+		result, err := svc.Execute(context.Background(), tt.tool, nil)
+		if err != nil {
+			t.Fatalf("%s/%s: Execute: %v", tt.policy, tt.tool, err)
+		}
+		if gotErr := result.Error != ""; gotErr != tt.wantErr {
+			t.Errorf("%s/%s: error = %q, want error %v", tt.policy, tt.tool, result.Error, tt.wantErr)
+		}
+		if tt.wantErr && len(leaked) > 0 {
+			t.Errorf("%s/%s: refused redirect still reached the target", tt.policy, tt.tool)
+		}
+	}
+}
+
 func TestExecuteAPIKeyHeaderAuth(t *testing.T) {
 	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
 		if got := r.Header.Get("X-API-Key"); got != "secret456" {
@@ -886,3 +1071,311 @@ func TestProxyURLSetOnTransport(t *testing.T) {
 		t.Errorf("expected socks5h://127.0.0.1:9050, got %q", got)
 	}
 }
+
+func TestExecuteCapturesConfiguredHeaders(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total-Count", "42")
+		w.Header().Add("Link", `<https://api.example.com/items?page=2>; rel="next"`)
+		w.Header().Add("Link", `<https://api.example.com/items?page=9>; rel="last"`)
+		w.Header().Set("X-Secret", "not captured")
+		w.Write([]byte(`[]`))
+	})
+	defer srv.Close()
+
+	svc := NewRESTService(config.ServiceConfig{
+		Name:     "test-api",
+		Type:     "rest",
+		Endpoint: srv.URL,
+		Auth:     config.AuthConfig{Method: "none"},
+		Tools: []config.ToolConfig{
+			{Name: "list", Method: "GET", Path: "/items", CaptureHeaders: []string{"x-total-count", "Link", "X-Missing"}},
+			{Name: "plain", Method: "GET", Path: "/items"},
+		},
+	}, nil, "")
+
+	result, err := svc.Execute(context.Background(), "list", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Headers["X-Total-Count"] != "42" {
+		t.Errorf("X-Total-Count = %q", result.Headers["X-Total-Count"])
+	}
+	if got := result.Headers["Link"]; !strings.Contains(got, `rel="next"`) || !strings.Contains(got, `rel="last"`) {
+		t.Errorf("Link = %q, want both values joined", got)
+	}
+	if len(result.Headers) != 2 {
+		t.Errorf("expected only configured, present headers, got %v", result.Headers)
+	}
+
+	result, err = svc.Execute(context.Background(), "plain", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Headers != nil {
+		t.Errorf("expected no headers without capture_headers, got %v", result.Headers)
+	}
+}
+
+func TestExecuteConditionalNotModified(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"ok": true}`))
+	})
+	defer srv.Close()
+
+	svc := NewRESTService(config.ServiceConfig{
+		Name:     "test-api",
+		Type:     "rest",
+		Endpoint: srv.URL,
+		Auth:     config.AuthConfig{Method: "none"},
+		Tools:    []config.ToolConfig{{Name: "get", Method: "GET", Path: "/item"}},
+	}, nil, "")
+
+	result, err := svc.Execute(context.Background(), "get", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Validators.ETag != `"v1"` || result.NotModified {
+		t.Fatalf("unexpected first result: %+v", result)
+	}
+
+	result, err = svc.ExecuteConditional(context.Background(), "get", nil, result.Validators)
+	if err != nil {
+		t.Fatalf("ExecuteConditional: %v", err)
+	}
+	if !result.NotModified || len(result.Data) != 0 || result.Error != "" {
+		t.Errorf("expected 304 result, got %+v", result)
+	}
+}
+
+func TestExecuteMarksEmptyResults(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/empty":
+			w.Write([]byte(`{"data": {"items": []}, "total": 0}`))
+		default:
+			w.Write([]byte(`{"data": {"items": [{"id": 1}]}, "total": 1}`))
+		}
+	})
+	defer srv.Close()
+
+	svc := NewRESTService(config.ServiceConfig{
+		Name:     "test-api",
+		Type:     "rest",
+		Endpoint: srv.URL,
+		Auth:     config.AuthConfig{Method: "none"},
+		Tools: []config.ToolConfig{
+			{Name: "empty", Method: "GET", Path: "/empty", ResultsPath: "data.items"},
+			{Name: "count", Method: "GET", Path: "/empty", ResultsPath: "total"},
+			{Name: "full", Method: "GET", Path: "/full", ResultsPath: "data.items"},
+			{Name: "missing", Method: "GET", Path: "/full", ResultsPath: "data.nope"},
+			{Name: "unset", Method: "GET", Path: "/empty"},
+		},
+	}, nil, "")
+
+	for tool, want := range map[string]bool{"empty": true, "count": true, "full": false, "missing": false, "unset": false} {
+		result, err := svc.Execute(context.Background(), tool, nil)
+		if err != nil {
+			t.Fatalf("Execute %s: %v", tool, err)
+		}
+		if result.Empty != want {
+			t.Errorf("%s: Empty = %v, want %v", tool, result.Empty, want)
+		}
+	}
+}
+
+func TestExecuteCapturesContentType(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "Application/PDF; name=filing.pdf")
+		w.Write([]byte("%PDF-1.7"))
+	})
+	defer srv.Close()
+
+	svc := NewRESTService(config.ServiceConfig{
+		Name:     "test-api",
+		Type:     "rest",
+		Endpoint: srv.URL,
+		Auth:     config.AuthConfig{Method: "none"},
+		Tools:    []config.ToolConfig{{Name: "download", Method: "GET", Path: "/filing"}},
+	}, nil, "")
+
+	result, err := svc.Execute(context.Background(), "download", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.ContentType != "application/pdf" {
+		t.Errorf("ContentType = %q, want application/pdf", result.ContentType)
+	}
+}
+
+func TestRetryAfterParsing(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		status int
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{429, "30", 30 * time.Second, true},
+		{503, now.Add(2 * time.Minute).Format(http.TimeFormat), 2 * time.Minute, true},
+		{429, now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{429, "", 0, false},
+		{429, "soon", 0, false},
+		{500, "30", 0, false},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+		if tt.header != "" {
+			resp.Header.Set("Retry-After", tt.header)
+		}
+		got, ok := retryAfter(resp, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%d, %q) = %v, %v; want %v, %v", tt.status, tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func rateLimitedService(url string, retries int) *RESTService {
+	return NewRESTService(config.ServiceConfig{
+		Name:     "test-api",
+		Type:     "rest",
+		Endpoint: url,
+		Auth:     config.AuthConfig{Method: "none"},
+		Retries:  retries,
+		Tools:    []config.ToolConfig{{Name: "search", Method: "POST", Path: "/search", Body: "body"}},
+	}, nil, "")
+}
+
+func TestExecuteRetriesAfterRetryAfter(t *testing.T) {
+	var calls int
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// The body must be resent on the retry.
+		if body, _ := io.ReadAll(r.Body); string(body) != `{"q":"x"}` {
+			t.Errorf("attempt %d body = %q", calls, body)
+		}
+		if calls == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"ok": true}`))
+	})
+	defer srv.Close()
+
+	result, err := rateLimitedService(srv.URL, 1).Execute(context.Background(), "search", map[string]string{"body": `{"q":"x"}`})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Error != "" || calls != 2 {
+		t.Errorf("expected success on retry, got error %q after %d calls", result.Error, calls)
+	}
+}
+
+func TestExecuteRecordsRetryAfterWithoutRetries(t *testing.T) {
+	var calls int
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	defer srv.Close()
+
+	result, err := rateLimitedService(srv.URL, 0).Execute(context.Background(), "search", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected no retry, got %d calls", calls)
+	}
+	if result.RetryAfter != 2*time.Minute {
+		t.Errorf("RetryAfter = %v, want 2m", result.RetryAfter)
+	}
+	if !strings.Contains(result.Error, "HTTP 429 (retry after 2m0s)") {
+		t.Errorf("expected retry hint in error, got %q", result.Error)
+	}
+}
+
+func TestExecuteDoesNotWaitOutLongRetryAfter(t *testing.T) {
+	var calls int
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer srv.Close()
+
+	result, err := rateLimitedService(srv.URL, 3).Execute(context.Background(), "search", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if calls != 1 || result.RetryAfter != time.Hour {
+		t.Errorf("expected a single call with a 1h hint, got %d calls, hint %v", calls, result.RetryAfter)
+	}
+}
+
+func TestExecuteRetriesCountAgainstRunBudget(t *testing.T) {
+	var calls int
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	defer srv.Close()
+
+	// Three retries allowed, but the run's budget covers only two requests.
+	budget := ratelimit.NewBudget(2)
+	ctx := ratelimit.WithBudget(context.Background(), budget)
+	result, err := rateLimitedService(srv.URL, 3).Execute(ctx, "search", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected the budget to stop retries after 2 requests, got %d", calls)
+	}
+	if !strings.Contains(result.Error, "run request budget exceeded") || budget.Refused() != 1 {
+		t.Errorf("expected a budget error, got %q (refused %d)", result.Error, budget.Refused())
+	}
+}
+
+func TestExecuteRateLimitWaitOutsideClientTimeout(t *testing.T) {
+	srv := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok": true}`))
+	})
+	defer srv.Close()
+
+	// The budget's window is longer than the client's timeout, so a
+	// second request must wait past it and still succeed.
+	svc := rateLimitedService(srv.URL, 0)
+	svc.client.Timeout = 50 * time.Millisecond
+	svc.SetLimiter(ratelimit.New(1, 200*time.Millisecond))
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		result, err := svc.Execute(context.Background(), "search", nil)
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if result.Error != "" {
+			t.Fatalf("request %d failed: %s", i+1, result.Error)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("second request sent after %v, before the window ended", elapsed)
+	}
+
+	// A cancelled run still ends the wait.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	result, err := svc.Execute(ctx, "search", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !strings.Contains(result.Error, "waiting for rate limit") {
+		t.Errorf("error = %q, want rate limit wait cancelled", result.Error)
+	}
+}
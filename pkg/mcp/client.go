@@ -15,6 +15,7 @@ import (
 
 	"github.com/jcadam/burrow/pkg/config"
 	"github.com/jcadam/burrow/pkg/privacy"
+	"github.com/jcadam/burrow/pkg/ratelimit"
 )
 
 const protocolVersion = "2025-03-26"
@@ -23,7 +24,8 @@ const protocolVersion = "2025-03-26"
 type Client struct {
 	endpoint   string
 	httpClient *http.Client
-	mu         sync.Mutex // protects sessionID
+	limiter    *ratelimit.Limiter // optional rate_limit, waited on before each request
+	mu         sync.Mutex         // protects sessionID
 	sessionID  string
 	nextID     atomic.Int64
 }
@@ -175,6 +177,14 @@ func (c *Client) call(ctx context.Context, method string, params any) (json.RawM
 	}
 	c.mu.Unlock()
 
+	if err := ratelimit.Take(ctx); err != nil {
+		return nil, err
+	}
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("waiting for rate limit: %w", err)
+		}
+	}
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, err
@@ -211,10 +221,13 @@ func (c *Client) call(ctx context.Context, method string, params any) (json.RawM
 }
 
 // NewHTTPClient builds an *http.Client suitable for MCP requests, with per-service
-// transport isolation, auth injection, and optional privacy wrapping. proxyURL sets
-// the proxy on the underlying transport (empty string means direct connection).
-func NewHTTPClient(auth config.AuthConfig, privacyCfg *privacy.Config, proxyURL string) *http.Client {
+// transport isolation, auth injection, and optional privacy wrapping. The
+// service's transport block adjusts the connection pool and its
+// follow_redirects policy limits redirects; proxyURL sets the proxy on the
+// underlying transport (empty string means direct connection).
+func NewHTTPClient(cfg config.ServiceConfig, privacyCfg *privacy.Config, proxyURL string) *http.Client {
 	baseTransport := &http.Transport{}
+	cfg.Transport.Apply(baseTransport)
 	if proxyURL != "" {
 		if parsed, err := url.Parse(proxyURL); err == nil && parsed != nil {
 			baseTransport.Proxy = http.ProxyURL(parsed)
@@ -224,8 +237,8 @@ func NewHTTPClient(auth config.AuthConfig, privacyCfg *privacy.Config, proxyURL
 	if privacyCfg != nil {
 		transport = privacy.NewTransport(baseTransport, *privacyCfg)
 	}
-	transport = &authTransport{base: transport, auth: auth}
-	return &http.Client{Timeout: 30 * time.Second, Transport: transport}
+	transport = &authTransport{base: transport, auth: cfg.Auth}
+	return &http.Client{Timeout: 30 * time.Second, Transport: transport, CheckRedirect: cfg.CheckRedirect()}
 }
 
 // authTransport injects auth headers into every request.
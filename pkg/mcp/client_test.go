@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/jcadam/burrow/pkg/config"
+	"github.com/jcadam/burrow/pkg/ratelimit"
 )
 
 // newTestMCPServer creates an httptest server that speaks MCP JSON-RPC.
@@ -248,6 +250,20 @@ func TestClientHTTPError(t *testing.T) {
 	}
 }
 
+func TestClientCountsRequestsAgainstRunBudget(t *testing.T) {
+	srv := newTestMCPServer(t)
+	defer srv.Close()
+
+	client := NewClient(srv.URL, &http.Client{Timeout: 5 * time.Second})
+	ctx := ratelimit.WithBudget(context.Background(), ratelimit.NewBudget(1))
+	if _, err := client.Initialize(ctx); err != nil {
+		t.Fatalf("Initialize within budget: %v", err)
+	}
+	if _, err := client.ListTools(ctx); !errors.Is(err, ratelimit.ErrBudgetExceeded) {
+		t.Errorf("expected the second request refused, got %v", err)
+	}
+}
+
 func TestNewHTTPClientBearerAuth(t *testing.T) {
 	var receivedAuth string
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -268,7 +284,7 @@ func TestNewHTTPClientBearerAuth(t *testing.T) {
 	defer srv.Close()
 
 	httpClient := NewHTTPClient(
-		config.AuthConfig{Method: "bearer", Token: "my-secret-token"},
+		config.ServiceConfig{Auth: config.AuthConfig{Method: "bearer", Token: "my-secret-token"}},
 		nil, "",
 	)
 
@@ -302,7 +318,7 @@ func TestNewHTTPClientAPIKeyHeader(t *testing.T) {
 	defer srv.Close()
 
 	httpClient := NewHTTPClient(
-		config.AuthConfig{Method: "api_key_header", Key: "key-123"},
+		config.ServiceConfig{Auth: config.AuthConfig{Method: "api_key_header", Key: "key-123"}},
 		nil, "",
 	)
 
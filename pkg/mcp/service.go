@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jcadam/burrow/pkg/ratelimit"
 	"github.com/jcadam/burrow/pkg/services"
 )
 
@@ -31,6 +32,12 @@ func NewMCPService(name string, endpoint string, httpClient *http.Client) *MCPSe
 	}
 }
 
+// SetLimiter makes every request to the server wait on l first, outside
+// the HTTP client's timeout.
+func (m *MCPService) SetLimiter(l *ratelimit.Limiter) {
+	m.client.limiter = l
+}
+
 func (m *MCPService) Name() string { return m.name }
 
 // Execute calls a tool on the MCP server. On first call, initializes the
@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"github.com/jcadam/burrow/pkg/slug"
+)
+
+// attachmentExts maps common download types to file extensions. Other types
+// fall back to the system MIME table, then ".bin".
+var attachmentExts = map[string]string{
+	"application/pdf":          ".pdf",
+	"text/csv":                 ".csv",
+	"application/json":         ".json",
+	"application/zip":          ".zip",
+	"text/plain":               ".txt",
+	"text/html":                ".html",
+	"application/xml":          ".xml",
+	"text/xml":                 ".xml",
+	"application/vnd.ms-excel": ".xls",
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": ".xlsx",
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+}
+
+// attachmentExt returns the file extension for a media type.
+func attachmentExt(contentType string) string {
+	if ext, ok := attachmentExts[contentType]; ok {
+		return ext
+	}
+	if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
+// attachmentName names the file saved for the attachment source at idx,
+// following the raw result naming in data/.
+func attachmentName(idx int, service, tool, contentType string) string {
+	return fmt.Sprintf("%d-%s-%s%s", idx, slug.Sanitize(service), slug.Sanitize(tool), attachmentExt(contentType))
+}
+
+// attachmentNote stands in for an attachment's bytes in the synthesis input.
+func attachmentNote(name string, size int, contentType string) string {
+	if contentType == "" {
+		contentType = "unknown type"
+	}
+	return fmt.Sprintf("Attached file saved as attachments/%s (%d bytes, %s). "+
+		"It is linked at the end of the report; mention it where relevant but do not describe or reproduce its contents.",
+		name, size, contentType)
+}
+
+// attachmentsSection lists attachment files as markdown links relative to
+// the report directory. Returns "" when there are none.
+func attachmentsSection(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\n## Attachments\n\n")
+	for _, name := range names {
+		name = filepath.Base(name)
+		fmt.Fprintf(&b, "- [%s](attachments/%s)\n", name, name)
+	}
+	return b.String()
+}
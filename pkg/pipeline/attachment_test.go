@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+// fileService returns a fixed body with a content type, like a download.
+type fileService struct {
+	name        string
+	body        []byte
+	contentType string
+}
+
+func (f *fileService) Name() string { return f.name }
+func (f *fileService) Execute(_ context.Context, tool string, _ map[string]string) (*services.Result, error) {
+	return &services.Result{Service: f.name, Tool: tool, Data: f.body, ContentType: f.contentType, Timestamp: time.Now()}, nil
+}
+
+func TestExecutorAttachmentSource(t *testing.T) {
+	reportsDir := filepath.Join(t.TempDir(), "reports")
+	os.MkdirAll(reportsDir, 0o755)
+
+	reg := services.NewRegistry()
+	reg.Register(&fileService{name: "sec", body: []byte("%PDF-1.7 binary"), contentType: "application/pdf"})
+	reg.Register(&mockService{name: "news", response: []byte(`{"headline": "Filing released"}`)})
+
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(reg, synth, reportsDir)
+	routine := &Routine{
+		Name:   "filings",
+		Report: ReportConfig{Title: "Filings"},
+		Sources: []SourceConfig{
+			{Service: "news", Tool: "search"},
+			{Service: "sec", Tool: "download", As: "attachment"},
+		},
+	}
+
+	report, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	saved, err := os.ReadFile(filepath.Join(report.Dir, "attachments", "1-sec-download.pdf"))
+	if err != nil {
+		t.Fatalf("attachment not saved: %v", err)
+	}
+	if string(saved) != "%PDF-1.7 binary" {
+		t.Errorf("attachment contents = %q", saved)
+	}
+	if _, err := os.Stat(filepath.Join(report.Dir, "data", "1-sec-download.json")); !os.IsNotExist(err) {
+		t.Error("attachments should not be stored as raw data")
+	}
+
+	input := string(synth.results[1].Data)
+	if strings.Contains(input, "%PDF") || !strings.Contains(input, "attachments/1-sec-download.pdf") {
+		t.Errorf("synthesis input should reference the file, not its bytes: %q", input)
+	}
+	if !strings.Contains(report.Markdown, "[1-sec-download.pdf](attachments/1-sec-download.pdf)") {
+		t.Errorf("report should link the attachment:\n%s", report.Markdown)
+	}
+}
+
+func TestAttachmentExt(t *testing.T) {
+	for ct, want := range map[string]string{
+		"application/pdf": ".pdf",
+		"text/csv":        ".csv",
+		"":                ".bin",
+		"x-unknown/thing": ".bin",
+	} {
+		if got := attachmentExt(ct); got != want {
+			t.Errorf("attachmentExt(%q) = %q, want %q", ct, got, want)
+		}
+	}
+}
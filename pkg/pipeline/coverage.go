@@ -0,0 +1,45 @@
+package pipeline
+
+import (
+	"strings"
+
+	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+// sourceCoverage scores how much of a routine's data a run collected.
+// A source with no result or an error result counts as failed; an empty
+// result still counts, since the source answered. Required sources weigh
+// double.
+func sourceCoverage(routine *Routine, results []*services.Result) reports.Coverage {
+	var c reports.Coverage
+	var got, total float64
+	for i, src := range routine.Sources {
+		weight := 1.0
+		if src.Required {
+			weight = 2
+		}
+		c.Total++
+		total += weight
+		if i < len(results) && results[i] != nil && results[i].Error == "" {
+			c.Succeeded++
+			got += weight
+		}
+	}
+	if total > 0 {
+		c.Score = got / total
+	}
+	return c
+}
+
+// failedSources names the sources whose results are errors, as
+// service/tool, for the low-coverage warning.
+func failedSources(routine *Routine, results []*services.Result) string {
+	var names []string
+	for i, src := range routine.Sources {
+		if i >= len(results) || results[i] == nil || results[i].Error != "" {
+			names = append(names, src.Service+"/"+src.Tool)
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,83 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/jcadam/burrow/pkg/services"
+	"github.com/jcadam/burrow/pkg/synthesis"
+)
+
+func TestSourceCoverage(t *testing.T) {
+	routine := &Routine{Sources: []SourceConfig{
+		{Service: "a", Tool: "x", Required: true},
+		{Service: "b", Tool: "x"},
+		{Service: "c", Tool: "x"},
+	}}
+	results := []*services.Result{
+		{Service: "a", Data: []byte("ok")},
+		{Service: "b", Empty: true},
+		{Service: "c", Error: "timeout"},
+	}
+
+	c := sourceCoverage(routine, results)
+	if c.Succeeded != 2 || c.Total != 3 {
+		t.Errorf("coverage = %d/%d, want 2/3", c.Succeeded, c.Total)
+	}
+	// The required source weighs double: (2+1)/(2+1+1).
+	if c.Score != 0.75 {
+		t.Errorf("score = %v, want 0.75", c.Score)
+	}
+	if got := failedSources(routine, results); got != "c/x" {
+		t.Errorf("failedSources = %q, want c/x", got)
+	}
+
+	if c := sourceCoverage(routine, results[:1]); c.Succeeded != 1 || c.Score != 0.5 {
+		t.Errorf("missing results should count as failed, got %+v", c)
+	}
+}
+
+func TestExecutorRecordsCoverage(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+	os.MkdirAll(reportsDir, 0o755)
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "good-api", response: []byte(`{"ok": true}`)})
+
+	exec := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir)
+	routine := &Routine{
+		Name:   "partial",
+		Report: ReportConfig{Title: "Partial Report"},
+		Sources: []SourceConfig{
+			{Service: "good-api", Tool: "fetch"},
+			{Service: "missing-api", Tool: "fetch"},
+			{Service: "missing-api", Tool: "other"},
+		},
+	}
+
+	report, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !strings.HasPrefix(report.Markdown, "# Partial Report\n\n*Coverage: 1/3 sources (33%)*\n\n") {
+		t.Errorf("coverage line should follow the title:\n%s", report.Markdown)
+	}
+	if !strings.Contains(report.Markdown, "low coverage: only 1 of 3 sources returned data (failed: missing-api/fetch, missing-api/other)") {
+		t.Errorf("expected low coverage warning:\n%s", report.Markdown)
+	}
+
+	// Failed sources stored nothing, so resynthesis keeps the recorded coverage.
+	report, err = exec.Resynthesize(context.Background(), routine, report.Dir)
+	if err != nil {
+		t.Fatalf("Resynthesize: %v", err)
+	}
+	c, ok := reports.ParseCoverage(report.Markdown)
+	if !ok || c.Succeeded != 1 || c.Total != 3 {
+		t.Errorf("resynthesized coverage = %+v, %v; want 1/3", c, ok)
+	}
+}
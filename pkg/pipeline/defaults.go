@@ -0,0 +1,68 @@
+package pipeline
+
+import (
+	"fmt"
+
+	"github.com/jcadam/burrow/pkg/config"
+)
+
+// ApplySynthesisDefaults fills the report and synthesis settings the
+// routine leaves unset from config.yaml's synthesis_defaults. A setting
+// the routine makes wins, and for on/off settings and retries that
+// includes an explicit false or 0. The result is validated again, so an
+// invalid default is reported against the routine that inherits it.
+func ApplySynthesisDefaults(r *Routine, d config.SynthesisDefaults) error {
+	rc := &r.Report
+	if rc.Style == "" {
+		rc.Style = d.Style
+	}
+	if rc.TLDR == nil {
+		rc.TLDR = d.TLDR
+	}
+	if rc.EmptySections == "" {
+		rc.EmptySections = d.EmptySections
+	}
+	if rc.GenerateCharts == nil {
+		rc.GenerateCharts = d.GenerateCharts
+	}
+	if rc.MaxLength == 0 {
+		rc.MaxLength = d.MaxLength
+	}
+
+	sc := &r.Synthesis
+	if sc.System == "" {
+		sc.System = d.System
+	}
+	if sc.Strategy == "" {
+		sc.Strategy = d.Strategy
+	}
+	if sc.SummaryMaxWords == 0 {
+		sc.SummaryMaxWords = d.SummaryMaxWords
+	}
+	if sc.MaxSourceWords == 0 {
+		sc.MaxSourceWords = d.MaxSourceWords
+	}
+	if sc.Concurrency == 0 {
+		sc.Concurrency = d.Concurrency
+	}
+	if sc.Preprocess == nil {
+		sc.Preprocess = d.Preprocess
+	}
+	if sc.Retries == nil {
+		sc.Retries = d.Retries
+	}
+	if sc.SourceOrder == "" {
+		sc.SourceOrder = d.SourceOrder
+	}
+	if sc.TruncationMarker == "" {
+		sc.TruncationMarker = d.TruncationMarker
+	}
+	if sc.SanitizeSources == nil {
+		sc.SanitizeSources = d.SanitizeSources
+	}
+
+	if err := ValidateRoutine(r); err != nil {
+		return fmt.Errorf("routine %q with synthesis_defaults: %w", r.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/config"
+)
+
+func TestApplySynthesisDefaults(t *testing.T) {
+	retries := 3
+	defaults := config.SynthesisDefaults{
+		Style:           "headlines",
+		TLDR:            boolPtr(true),
+		System:          "House style: terse.",
+		Strategy:        "multi-stage",
+		Concurrency:     2,
+		Retries:         &retries,
+		SanitizeSources: boolPtr(true),
+	}
+
+	r := &Routine{
+		Name:    "brief",
+		Report:  ReportConfig{Title: "Brief", TLDR: boolPtr(false)},
+		Sources: []SourceConfig{{Service: "s", Tool: "t"}},
+		Synthesis: SynthesisConfig{
+			System:      "Routine prompt.",
+			Concurrency: 4,
+		},
+	}
+	if err := ApplySynthesisDefaults(r, defaults); err != nil {
+		t.Fatalf("ApplySynthesisDefaults: %v", err)
+	}
+
+	// Unset settings are inherited.
+	if r.Report.Style != "headlines" || r.Synthesis.Strategy != "multi-stage" || !r.Synthesis.Sanitized() {
+		t.Errorf("defaults not inherited: %+v %+v", r.Report, r.Synthesis)
+	}
+	if r.Synthesis.Retries == nil || *r.Synthesis.Retries != 3 {
+		t.Errorf("retries = %v, want 3", r.Synthesis.Retries)
+	}
+	// The routine's own settings, including an explicit false, win.
+	if r.Report.TLDREnabled() {
+		t.Error("routine's tldr: false should override the default")
+	}
+	if r.Synthesis.System != "Routine prompt." || r.Synthesis.Concurrency != 4 {
+		t.Errorf("routine settings overridden: %+v", r.Synthesis)
+	}
+}
+
+func TestApplySynthesisDefaultsInvalid(t *testing.T) {
+	r := &Routine{
+		Name:    "brief",
+		Report:  ReportConfig{Title: "Brief"},
+		Sources: []SourceConfig{{Service: "s", Tool: "t"}},
+	}
+	err := ApplySynthesisDefaults(r, config.SynthesisDefaults{Strategy: "fast"})
+	if err == nil || !strings.Contains(err.Error(), "synthesis_defaults") || !strings.Contains(err.Error(), "strategy") {
+		t.Errorf("expected invalid strategy error, got %v", err)
+	}
+}
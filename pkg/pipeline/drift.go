@@ -0,0 +1,298 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Shape is the structure of a JSON response: each field path mapped to its
+// JSON type. Paths start at "$"; array elements are merged under "[]", so
+// {"results": [{"title": "x"}]} has "$.results[].title" of type "string".
+// Values are never recorded.
+type Shape map[string]string
+
+const (
+	// maxShapePaths bounds how many paths a snapshot records.
+	maxShapePaths = 1000
+	// maxShapeElems is how many elements of each array are sampled.
+	maxShapeElems = 20
+	// driftThreshold is the fraction of snapshot paths that must be missing
+	// or retyped before a change counts as drift. Optional fields come and
+	// go; a reshaped API moves many at once.
+	driftThreshold = 0.25
+)
+
+// responseShape returns the shape of a JSON body, or nil if it isn't JSON.
+func responseShape(data []byte) Shape {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil
+	}
+	shape := make(Shape)
+	addShape(shape, "$", v)
+	return shape
+}
+
+func addShape(shape Shape, path string, v any) {
+	if len(shape) >= maxShapePaths {
+		return
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		shape[path] = "object"
+		for k, child := range v {
+			addShape(shape, path+"."+k, child)
+		}
+	case []any:
+		if len(v) == 0 {
+			// Nothing to say about the elements; see shapeDrift.
+			if _, seen := shape[path]; !seen {
+				shape[path] = "empty array"
+			}
+			return
+		}
+		shape[path] = "array"
+		for i, elem := range v {
+			if i == maxShapeElems {
+				break
+			}
+			addShape(shape, path+"[]", elem)
+		}
+	case string:
+		setLeaf(shape, path, "string")
+	case float64:
+		setLeaf(shape, path, "number")
+	case bool:
+		setLeaf(shape, path, "bool")
+	case nil:
+		setLeaf(shape, path, "null")
+	}
+}
+
+// setLeaf records a scalar type, letting a concrete type win over null when
+// array elements disagree.
+func setLeaf(shape Shape, path, typ string) {
+	if prev, ok := shape[path]; ok && typ == "null" && prev != "null" {
+		return
+	}
+	shape[path] = typ
+}
+
+// shapeDrift returns the snapshot paths missing from current and those whose
+// type changed, sorted. Null and empty arrays are compatible with any type,
+// and paths beneath a null or empty array in current are not counted
+// missing: a field that happens to be empty this run is not a new shape.
+func shapeDrift(snapshot, current Shape) (missing, changed []string) {
+	for path, typ := range snapshot {
+		cur, ok := current[path]
+		if !ok {
+			if !underUnknown(current, path) {
+				missing = append(missing, path)
+			}
+			continue
+		}
+		if cur != typ && !compatibleTypes(cur, typ) {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(changed)
+	return missing, changed
+}
+
+func compatibleTypes(a, b string) bool {
+	loose := func(t string) bool { return t == "null" || t == "empty array" }
+	return loose(a) || loose(b)
+}
+
+// underUnknown reports whether a parent of path is null or an empty array in
+// shape, leaving path's presence undecided.
+func underUnknown(shape Shape, path string) bool {
+	for i := len(path) - 1; i > 0; i-- {
+		if path[i] != '.' && path[i] != '[' {
+			continue
+		}
+		switch shape[path[:i]] {
+		case "null", "empty array":
+			return true
+		}
+	}
+	return false
+}
+
+// driftWarning compares a source's current shape with its snapshot and
+// returns a warning when enough of the snapshot is missing or retyped to
+// suggest the API changed, or "" otherwise.
+func driftWarning(label string, snapshot, current Shape) string {
+	missing, changed := shapeDrift(snapshot, current)
+	bad := len(missing) + len(changed)
+	if bad == 0 || float64(bad) < driftThreshold*float64(len(snapshot)) {
+		return ""
+	}
+	examples := append(append([]string{}, missing...), changed...)
+	if len(examples) > 3 {
+		examples = examples[:3]
+	}
+	return fmt.Sprintf("source %s response structure changed: %d of %d fields missing or retyped (e.g. %s)",
+		label, bad, len(snapshot), strings.Join(examples, ", "))
+}
+
+// driftSection renders source warnings — drift and low coverage — as a
+// report section, or "" when there are none.
+func driftSection(warnings []string) string {
+	if len(warnings) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\n## Source Warnings\n\n")
+	for _, w := range warnings {
+		b.WriteString("- ⚠ " + w + "\n")
+	}
+	return b.String()
+}
+
+// checkDrift compares the shapes of this run's drift-tracked sources with
+// their stored snapshots, returning a warning per source whose structure
+// changed. A source's first successful run records its snapshot; a drifted
+// source's snapshot is replaced so each change is reported once. Shapes are
+// keyed by source index.
+func (e *Executor) checkDrift(routine *Routine, shapes map[int]Shape) []string {
+	if e.shapes == nil || len(shapes) == 0 {
+		return nil
+	}
+	stored, err := e.shapes.Load(routine.Name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: loading source shapes: %v\n", err)
+		return nil
+	}
+
+	indices := make([]int, 0, len(shapes))
+	for idx := range shapes {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+
+	var warnings []string
+	updates := make(map[string]Shape)
+	for _, idx := range indices {
+		src := routine.Sources[idx]
+		key := fmt.Sprintf("%d-%s-%s", idx, src.Service, src.Tool)
+		snapshot, ok := stored[key]
+		if !ok {
+			updates[key] = shapes[idx]
+			continue
+		}
+		if w := driftWarning(src.Service+"/"+src.Tool, snapshot, shapes[idx]); w != "" {
+			warnings = append(warnings, w)
+			updates[key] = shapes[idx]
+		}
+	}
+
+	if err := e.shapes.Update(routine.Name, updates); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: saving source shapes: %v\n", err)
+	}
+	return warnings
+}
+
+// ShapeStore persists source response shapes to a JSON file keyed by
+// routine name, then source key ("<index>-<service>-<tool>").
+type ShapeStore struct {
+	path string
+	mu   sync.Mutex // serializes load→modify→save
+}
+
+// NewShapeStore creates a ShapeStore backed by the JSON file at path.
+func NewShapeStore(path string) *ShapeStore {
+	return &ShapeStore{path: path}
+}
+
+// Load returns the stored shapes for a routine. Returns an empty map if the
+// file or routine doesn't exist.
+func (s *ShapeStore) Load(routine string) (map[string]Shape, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	shapes := all[routine]
+	if shapes == nil {
+		shapes = make(map[string]Shape)
+	}
+	return shapes, nil
+}
+
+// Update replaces the given sources' shapes for a routine and writes the
+// file atomically via temp+rename.
+func (s *ShapeStore) Update(routine string, shapes map[string]Shape) error {
+	if len(shapes) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readAll()
+	if err != nil {
+		return err
+	}
+	if all[routine] == nil {
+		all[routine] = make(map[string]Shape)
+	}
+	for k, shape := range shapes {
+		all[routine][k] = shape
+	}
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling shapes: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating shapes directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "shapes-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming shapes file: %w", err)
+	}
+	return nil
+}
+
+func (s *ShapeStore) readAll() (map[string]map[string]Shape, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]map[string]Shape), nil
+		}
+		return nil, fmt.Errorf("reading shapes file: %w", err)
+	}
+	var all map[string]map[string]Shape
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("parsing shapes file: %w", err)
+	}
+	if all == nil {
+		all = make(map[string]map[string]Shape)
+	}
+	return all, nil
+}
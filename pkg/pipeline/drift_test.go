@@ -0,0 +1,127 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+func TestResponseShape(t *testing.T) {
+	shape := responseShape([]byte(`{"total": 2, "results": [{"title": "a", "tags": []}, {"title": "b", "tags": ["x"], "score": null}]}`))
+	want := Shape{
+		"$":                  "object",
+		"$.total":            "number",
+		"$.results":          "array",
+		"$.results[]":        "object",
+		"$.results[].title":  "string",
+		"$.results[].tags":   "array",
+		"$.results[].tags[]": "string",
+		"$.results[].score":  "null",
+	}
+	if !reflect.DeepEqual(shape, want) {
+		t.Errorf("responseShape =\n%v\nwant\n%v", shape, want)
+	}
+
+	if responseShape([]byte("<html>")) != nil {
+		t.Error("non-JSON body should have no shape")
+	}
+}
+
+func TestDriftWarning(t *testing.T) {
+	snapshot := responseShape([]byte(`{"results": [{"title": "a", "url": "u", "date": "d"}]}`))
+
+	same := responseShape([]byte(`{"results": [{"title": "b", "url": "v", "date": "e", "extra": 1}]}`))
+	if w := driftWarning("news/search", snapshot, same); w != "" {
+		t.Errorf("added fields should not count as drift: %s", w)
+	}
+
+	optional := responseShape([]byte(`{"results": [{"title": "b", "url": "v"}]}`))
+	if w := driftWarning("news/search", snapshot, optional); w != "" {
+		t.Errorf("one missing field of five is below the threshold: %s", w)
+	}
+
+	empty := responseShape([]byte(`{"results": []}`))
+	if w := driftWarning("news/search", snapshot, empty); w != "" {
+		t.Errorf("an empty array should not hide its element fields as drift: %s", w)
+	}
+
+	reshaped := responseShape([]byte(`{"data": {"items": [{"name": "a"}]}}`))
+	w := driftWarning("news/search", snapshot, reshaped)
+	if !strings.Contains(w, "source news/search response structure changed: 5 of 6 fields") {
+		t.Errorf("driftWarning = %q", w)
+	}
+	if !strings.Contains(w, "$.results") {
+		t.Errorf("warning should name a missing field: %q", w)
+	}
+}
+
+func TestShapeStoreRoundTrip(t *testing.T) {
+	store := NewShapeStore(filepath.Join(t.TempDir(), "state", "source-shapes.json"))
+
+	shapes, err := store.Load("brief")
+	if err != nil || len(shapes) != 0 {
+		t.Fatalf("Load on missing file = %v, %v", shapes, err)
+	}
+
+	shape := Shape{"$": "object", "$.title": "string"}
+	if err := store.Update("brief", map[string]Shape{"0-news-search": shape}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	shapes, err = store.Load("brief")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(shapes["0-news-search"], shape) {
+		t.Errorf("stored shape = %v, want %v", shapes["0-news-search"], shape)
+	}
+}
+
+func TestExecutorDetectsDrift(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+	os.MkdirAll(reportsDir, 0o755)
+
+	svc := &mockService{name: "news", response: []byte(`{"results": [{"title": "a", "url": "u"}]}`)}
+	reg := services.NewRegistry()
+	reg.Register(svc)
+
+	exec := NewExecutor(reg, &capturingSynthesizer{}, reportsDir)
+	exec.SetShapeStore(NewShapeStore(filepath.Join(dir, "source-shapes.json")))
+	routine := &Routine{
+		Name:    "brief",
+		Report:  ReportConfig{Title: "Brief"},
+		Sources: []SourceConfig{{Service: "news", Tool: "search", DetectDrift: true}},
+	}
+
+	report, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	if strings.Contains(report.Markdown, "Source Warnings") {
+		t.Error("first run records the snapshot without warning")
+	}
+
+	svc.response = []byte(`{"items": [{"name": "a"}]}`)
+	report, err = exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if !strings.Contains(report.Markdown, "## Source Warnings") ||
+		!strings.Contains(report.Markdown, "source news/search response structure changed") {
+		t.Errorf("expected drift warning in report:\n%s", report.Markdown)
+	}
+
+	// The snapshot moved to the new shape, so the change is reported once.
+	report, err = exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("third Run: %v", err)
+	}
+	if strings.Contains(report.Markdown, "Source Warnings") {
+		t.Error("drift should be reported once, not on every run")
+	}
+}
@@ -0,0 +1,115 @@
+package pipeline
+
+import (
+	"regexp"
+	"strings"
+)
+
+// defaultEmptyPlaceholder is the text of a placeholder section when the
+// routine sets none.
+const defaultEmptyPlaceholder = "No updates today."
+
+// EmptyPolicy returns the report's policy for sections with no data:
+// keep (the default), omit, or placeholder.
+func (rc ReportConfig) EmptyPolicy() string {
+	if rc.EmptySections == "" {
+		return "keep"
+	}
+	return rc.EmptySections
+}
+
+// Placeholder returns the text that stands in for an empty section under
+// the placeholder policy.
+func (rc ReportConfig) Placeholder() string {
+	if rc.EmptyPlaceholder == "" {
+		return defaultEmptyPlaceholder
+	}
+	return rc.EmptyPlaceholder
+}
+
+// buildEmptySectionsContext tells the synthesizer what to do with sources
+// that returned no items, or "" when the LLM decides (keep).
+func buildEmptySectionsContext(rc ReportConfig) string {
+	switch rc.EmptyPolicy() {
+	case "omit":
+		return "## Sources Without Data\n\nLeave sources marked \"No results\" out of the report entirely: no section, no heading, no mention."
+	case "placeholder":
+		return "## Sources Without Data\n\nGive each source marked \"No results\" its usual section heading, followed only by this line: " + rc.Placeholder()
+	}
+	return ""
+}
+
+// headingPattern matches a markdown section heading below the title.
+var headingPattern = regexp.MustCompile(`^(#{2,6})\s+\S`)
+
+// noDataPattern matches a one-line section body that only says there is
+// nothing to report, such as "No results returned." or "No new updates
+// today". Sentences with figures in them are data, not placeholders.
+var noDataPattern = regexp.MustCompile(`(?i)^(no|nothing|none)\b[^0-9]{0,80}\b(results?|updates?|data|items?|entries|news|activity|to report|returned|available)\b[^0-9]{0,40}$`)
+
+// applyEmptySections enforces the report's empty-section policy on the
+// synthesized markdown, so the report keeps the same shape whether or not
+// the LLM followed the instruction. A section is empty when it has no
+// subsections and its body is blank or a single "no data" line. Omit drops
+// such sections; placeholder replaces their body with the placeholder text.
+// Keep leaves the markdown as is.
+func applyEmptySections(markdown string, rc ReportConfig) string {
+	policy := rc.EmptyPolicy()
+	if policy == "keep" {
+		return markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	type heading struct{ line, level int }
+	var headings []heading
+	inFence := false
+	for i, l := range lines {
+		if strings.HasPrefix(strings.TrimSpace(l), "```") {
+			inFence = !inFence
+			continue
+		}
+		if m := headingPattern.FindStringSubmatch(l); m != nil && !inFence {
+			headings = append(headings, heading{i, len(m[1])})
+		}
+	}
+
+	// Work from the bottom up so earlier line numbers stay valid.
+	for h := len(headings) - 1; h >= 0; h-- {
+		start := headings[h].line + 1
+		end := len(lines)
+		if h+1 < len(headings) {
+			if headings[h+1].level > headings[h].level {
+				continue // has subsections
+			}
+			end = headings[h+1].line
+		}
+		if !emptyBody(lines[start:end]) {
+			continue
+		}
+		var replacement []string
+		if policy == "placeholder" {
+			replacement = []string{lines[headings[h].line], "", rc.Placeholder(), ""}
+		}
+		lines = append(lines[:headings[h].line], append(replacement, lines[end:]...)...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// emptyBody reports whether a section body is blank or a single line saying
+// there is no data.
+func emptyBody(body []string) bool {
+	var text []string
+	for _, l := range body {
+		if t := strings.TrimSpace(l); t != "" && t != "---" {
+			text = append(text, t)
+		}
+	}
+	switch len(text) {
+	case 0:
+		return true
+	case 1:
+		line := strings.Trim(strings.TrimLeft(text[0], "> "), "*_ .")
+		return noDataPattern.MatchString(line)
+	}
+	return false
+}
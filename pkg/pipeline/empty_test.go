@@ -0,0 +1,115 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/services"
+	"github.com/jcadam/burrow/pkg/synthesis"
+)
+
+const emptySectionsReport = `# Brief
+
+## Weather
+
+Sunny, high of 72.
+
+## News
+
+No new items today.
+
+## Markets
+
+` + "```" + `
+## not a heading
+` + "```" + `
+
+## Contracts
+
+### Awards
+
+Two awarded.
+
+## Filings
+
+> No results returned.
+`
+
+func TestApplyEmptySections(t *testing.T) {
+	if got := applyEmptySections(emptySectionsReport, ReportConfig{}); got != emptySectionsReport {
+		t.Errorf("keep changed the report:\n%s", got)
+	}
+
+	omitted := applyEmptySections(emptySectionsReport, ReportConfig{EmptySections: "omit"})
+	for _, gone := range []string{"## News", "## Filings", "No new items"} {
+		if strings.Contains(omitted, gone) {
+			t.Errorf("omit kept %q:\n%s", gone, omitted)
+		}
+	}
+	for _, kept := range []string{"## Weather", "## Markets", "## Contracts", "### Awards"} {
+		if !strings.Contains(omitted, kept) {
+			t.Errorf("omit dropped %q:\n%s", kept, omitted)
+		}
+	}
+
+	placeheld := applyEmptySections(emptySectionsReport, ReportConfig{EmptySections: "placeholder", EmptyPlaceholder: "Quiet day."})
+	if !strings.Contains(placeheld, "## News\n\nQuiet day.\n\n## Markets") {
+		t.Errorf("placeholder not applied to News:\n%s", placeheld)
+	}
+	if !strings.HasSuffix(placeheld, "## Filings\n\nQuiet day.\n") {
+		t.Errorf("placeholder not applied to Filings:\n%s", placeheld)
+	}
+	if again := applyEmptySections(placeheld, ReportConfig{EmptySections: "placeholder", EmptyPlaceholder: "Quiet day."}); again != placeheld {
+		t.Errorf("placeholder is not stable:\n%s", again)
+	}
+}
+
+func TestEmptyBody(t *testing.T) {
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{"", true},
+		{"No updates today.", true},
+		{"*Nothing new to report.*", true},
+		{"No results: this source returned no items.", true},
+		{"No new contracts were awarded.", false},
+		{"No change: rate holds at 5.25%.", false},
+		{"No updates.\n\nBut one note.", false},
+	}
+	for _, tt := range tests {
+		if got := emptyBody(strings.Split(tt.body, "\n")); got != tt.want {
+			t.Errorf("emptyBody(%q) = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestExecutorEmptySectionsPassthrough(t *testing.T) {
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "full", response: []byte(`{"items": [1]}`)})
+	reg.Register(&mockService{name: "quiet", response: []byte(`[]`)})
+	routine := &Routine{
+		Name:    "empty-test",
+		Report:  ReportConfig{Title: "Brief", EmptySections: "placeholder"},
+		Sources: []SourceConfig{{Service: "full", Tool: "fetch"}, {Service: "quiet", Tool: "fetch"}},
+	}
+
+	synth := synthesis.NewPassthroughSynthesizer()
+	report, err := NewExecutor(reg, synth, t.TempDir()).Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !strings.Contains(report.Markdown, "## quiet — fetch\n\nNo updates today.") {
+		t.Errorf("empty source should show the placeholder:\n%s", report.Markdown)
+	}
+}
+
+func TestSynthesisPromptEmptySections(t *testing.T) {
+	exec := NewExecutor(services.NewRegistry(), nil, t.TempDir())
+	routine := &Routine{Name: "r", Report: ReportConfig{Title: "T", EmptySections: "omit"}}
+	system, _ := exec.synthesisPrompts(routine, nil, nil)
+	if !strings.Contains(system, `Leave sources marked "No results" out of the report`) {
+		t.Errorf("system prompt missing omit instruction:\n%s", system)
+	}
+}
@@ -0,0 +1,112 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/jcadam/burrow/pkg/services"
+	"github.com/jcadam/burrow/pkg/synthesis"
+)
+
+// Event types emitted during a run, in the order they occur.
+const (
+	EventSourceStarted = "source_started"
+	EventSourceDone    = "source_done"
+	// EventSynthesisProgress follows each multi-stage summary.
+	EventSynthesisProgress = "synthesis_progress"
+	EventSynthesisDone     = "synthesis_done"
+	EventReportSaved       = "report_saved"
+)
+
+// Event describes one step of a routine run. Fields that don't apply to the
+// event type are left empty.
+type Event struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	Routine string    `json:"routine"`
+	Source  *int      `json:"source,omitempty"` // index into the routine's sources
+	Service string    `json:"service,omitempty"`
+	Tool    string    `json:"tool,omitempty"`
+	Status  string    `json:"status,omitempty"` // source_done: ok | no_results | error
+	Error   string    `json:"error,omitempty"`
+	Bytes   int       `json:"bytes,omitempty"`
+	Report  string    `json:"report,omitempty"` // report_saved: report directory
+	Done    int       `json:"done,omitempty"`   // synthesis_progress: sources summarized
+	Total   int       `json:"total,omitempty"`  // synthesis_progress: sources to summarize
+}
+
+// Observer receives run events. Source events arrive from concurrent
+// goroutines, so implementations must be safe for concurrent use.
+type Observer interface {
+	Observe(Event)
+}
+
+// JSONObserver writes each event to w as one JSON line (NDJSON).
+type JSONObserver struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONObserver returns an observer that streams events to w as NDJSON.
+func NewJSONObserver(w io.Writer) *JSONObserver {
+	return &JSONObserver{enc: json.NewEncoder(w)}
+}
+
+func (o *JSONObserver) Observe(ev Event) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.enc.Encode(ev) //nolint:errcheck // best-effort; a closed pipe shouldn't fail the run
+}
+
+// sourceEvent builds a source event for the source at idx.
+func sourceEvent(typ string, idx int, src SourceConfig) Event {
+	return Event{Type: typ, Source: &idx, Service: src.Service, Tool: src.Tool}
+}
+
+// sourceDoneEvent describes a finished source from its result.
+func sourceDoneEvent(idx int, src SourceConfig, r *services.Result) Event {
+	ev := sourceEvent(EventSourceDone, idx, src)
+	ev.Status, ev.Error = resultStatus(r)
+	if ev.Status == reports.SourceOK {
+		ev.Bytes = len(r.Data)
+	}
+	return ev
+}
+
+// resultStatus classifies a source's result as ok, no_results, or error,
+// with the error message for the last.
+func resultStatus(r *services.Result) (status, errMsg string) {
+	switch {
+	case r == nil:
+		return reports.SourceError, "no result"
+	case r.Error != "":
+		return reports.SourceError, r.Error
+	case r.Empty:
+		return reports.SourceNoResults, ""
+	}
+	return reports.SourceOK, ""
+}
+
+// synthesisContext reports multi-stage synthesis progress as events.
+func (e *Executor) synthesisContext(ctx context.Context, routine *Routine) context.Context {
+	if e.observer == nil {
+		return ctx
+	}
+	return synthesis.WithProgress(ctx, func(done, total int) {
+		e.emit(routine, Event{Type: EventSynthesisProgress, Done: done, Total: total})
+	})
+}
+
+// emit stamps ev and passes it to the observer, if any.
+func (e *Executor) emit(routine *Routine, ev Event) {
+	if e.observer == nil {
+		return
+	}
+	ev.Time = time.Now().UTC()
+	ev.Routine = routine.Name
+	e.observer.Observe(ev)
+}
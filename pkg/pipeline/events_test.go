@@ -0,0 +1,137 @@
+package pipeline
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/services"
+	"github.com/jcadam/burrow/pkg/synthesis"
+)
+
+func TestExecutorEmitsEvents(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+	os.MkdirAll(reportsDir, 0o755)
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "good-api", response: []byte(`{"ok": true}`)})
+
+	var buf bytes.Buffer
+	exec := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir)
+	exec.SetObserver(NewJSONObserver(&buf))
+
+	routine := &Routine{
+		Name:   "events",
+		Report: ReportConfig{Title: "Events"},
+		Sources: []SourceConfig{
+			{Service: "good-api", Tool: "fetch"},
+			{Service: "missing-api", Tool: "fetch"},
+		},
+	}
+
+	report, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var events []Event
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, ev)
+	}
+
+	counts := map[string]int{}
+	status := map[int]string{}
+	for _, ev := range events {
+		counts[ev.Type]++
+		if ev.Routine != "events" {
+			t.Errorf("event routine = %q", ev.Routine)
+		}
+		if ev.Type == EventSourceDone {
+			status[*ev.Source] = ev.Status
+		}
+	}
+	if counts[EventSourceStarted] != 2 || counts[EventSourceDone] != 2 {
+		t.Errorf("source event counts = %v", counts)
+	}
+	if status[0] != "ok" || status[1] != "error" {
+		t.Errorf("source statuses = %v, want ok and error", status)
+	}
+
+	n := len(events)
+	if n < 2 || events[n-2].Type != EventSynthesisDone || events[n-1].Type != EventReportSaved {
+		t.Fatalf("expected synthesis_done then report_saved last, got %+v", events)
+	}
+	if events[n-1].Report != report.Dir {
+		t.Errorf("report_saved dir = %q, want %q", events[n-1].Report, report.Dir)
+	}
+}
+
+func TestExecutorEmitsSynthesisProgress(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+	os.MkdirAll(reportsDir, 0o755)
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "good-api", response: []byte(`{"ok": true}`)})
+
+	synth := synthesis.NewLLMSynthesizer(fixedProvider("# Progress\n\nBody."), false)
+	synth.SetMultiStage(synthesis.MultiStageConfig{Strategy: "multi-stage"})
+
+	var buf bytes.Buffer
+	exec := NewExecutor(reg, synth, reportsDir)
+	exec.SetObserver(NewJSONObserver(&buf))
+
+	routine := &Routine{
+		Name:   "progress",
+		Report: ReportConfig{Title: "Progress"},
+		Sources: []SourceConfig{
+			{Service: "good-api", Tool: "a"},
+			{Service: "good-api", Tool: "b"},
+			{Service: "good-api", Tool: "c"},
+		},
+	}
+	if _, err := exec.Run(context.Background(), routine); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	var progress []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		if ev.Type == EventSynthesisProgress {
+			progress = append(progress, fmt.Sprintf("%d/%d", ev.Done, ev.Total))
+		}
+	}
+	if got := strings.Join(progress, " "); got != "1/3 2/3 3/3" {
+		t.Errorf("synthesis progress = %q", got)
+	}
+}
+
+// fixedProvider answers every prompt with the same text.
+type fixedProvider string
+
+func (p fixedProvider) Complete(context.Context, string, string) (string, error) {
+	return string(p), nil
+}
+
+func TestSourceDoneEventNoResults(t *testing.T) {
+	ev := sourceDoneEvent(3, SourceConfig{Service: "s", Tool: "t"}, &services.Result{Empty: true})
+	if ev.Status != "no_results" || *ev.Source != 3 {
+		t.Errorf("event = %+v", ev)
+	}
+}
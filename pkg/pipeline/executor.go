@@ -1,22 +1,33 @@
 package pipeline
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand/v2"
+	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"text/template"
 	"time"
 
+	"github.com/jcadam/burrow/pkg/cache"
 	"github.com/jcadam/burrow/pkg/charts"
 	bcontext "github.com/jcadam/burrow/pkg/context"
 	"github.com/jcadam/burrow/pkg/debug"
 	"github.com/jcadam/burrow/pkg/profile"
+	"github.com/jcadam/burrow/pkg/ratelimit"
 	"github.com/jcadam/burrow/pkg/reports"
 	"github.com/jcadam/burrow/pkg/services"
 	"github.com/jcadam/burrow/pkg/slug"
 	"github.com/jcadam/burrow/pkg/synthesis"
+	"github.com/jcadam/burrow/pkg/values"
 )
 
 // Executor runs routines by querying services and producing reports.
@@ -26,8 +37,12 @@ type Executor struct {
 	reportsDir  string
 	ledger      *bcontext.Ledger
 	profile     *profile.Profile
+	values      *values.Store
+	shapes      *ShapeStore
+	hashes      *HashStore
 	randFunc    func(max int) int
 	debug       *debug.Logger
+	observer    Observer
 }
 
 // NewExecutor creates an executor with the given dependencies.
@@ -50,6 +65,12 @@ func (e *Executor) SetProfile(p *profile.Profile) {
 	e.profile = p
 }
 
+// SetValueStore sets the store for values stashed between runs
+// ({{lastValue "key"}} in templates, stash: in routines).
+func (e *Executor) SetValueStore(s *values.Store) {
+	e.values = s
+}
+
 // SetRandFunc replaces the random function (for testing jitter).
 func (e *Executor) SetRandFunc(f func(max int) int) {
 	e.randFunc = f
@@ -60,20 +81,235 @@ func (e *Executor) SetDebug(l *debug.Logger) {
 	e.debug = l
 }
 
+// SetShapeStore enables response structure drift checks for sources with
+// detect_drift set, persisting their snapshots in s.
+func (e *Executor) SetShapeStore(s *ShapeStore) {
+	e.shapes = s
+}
+
+// SetHashStore enables skip_unchanged for routines that set it, keeping
+// the hash of each routine's last report data in s.
+func (e *Executor) SetHashStore(s *HashStore) {
+	e.hashes = s
+}
+
+// SetObserver registers an observer for run progress events. Nil disables it.
+func (e *Executor) SetObserver(o Observer) {
+	e.observer = o
+}
+
+// sourceContext carries a source's cache: and cache_ttl: settings to a
+// cached service. Services without a cache ignore them.
+func sourceContext(ctx context.Context, src SourceConfig) context.Context {
+	if src.Cache != nil && !*src.Cache {
+		return cache.WithDirective(ctx, cache.Directive{Disabled: true})
+	}
+	if ttl, err := src.cacheTTL(); err == nil && ttl > 0 {
+		return cache.WithDirective(ctx, cache.Directive{TTL: ttl})
+	}
+	return ctx
+}
+
+// checkSynthesizer asks the synthesizer whether its LLM provider is ready,
+// when it can tell, and turns a failure into guidance.
+func (e *Executor) checkSynthesizer(ctx context.Context) error {
+	c, ok := e.synthesizer.(synthesis.Checker)
+	if !ok {
+		return nil
+	}
+	if err := c.Check(ctx); err != nil {
+		return fmt.Errorf("LLM provider not ready: %w (fix it with gd configure, or set llm: none in the routine for a raw-data report)", err)
+	}
+	return nil
+}
+
 // Run executes a routine: queries all sources in parallel with jitter,
 // synthesizes results, saves report, and indexes in context ledger.
 func (e *Executor) Run(ctx context.Context, routine *Routine) (*reports.Report, error) {
-	e.debug.Section(fmt.Sprintf("Running %q (%d sources, jitter=%ds)", routine.Name, len(routine.Sources), routine.Jitter))
+	e.debug.Section(fmt.Sprintf("Running %q (%d sources, jitter=%s)", routine.Name, len(routine.Sources), routine.Jitter))
+
+	// A missing model or unreachable endpoint would otherwise surface only
+	// after every source has been queried.
+	if err := e.checkSynthesizer(ctx); err != nil {
+		return nil, err
+	}
+
+	funcs := e.templateFuncs(routine)
+
+	// Read the comparison report once, before anything is fetched, so the
+	// run compares against a fixed prior report even if another run of the
+	// target routine finishes meanwhile.
+	previous := e.comparisonReport(routine, time.Now())
 
+	f, err := e.fetchSources(ctx, routine, funcs, nil)
+	if err != nil {
+		return nil, err
+	}
+	results := f.results
+
+	// With skip_unchanged, data identical to the last report's is not
+	// saved or synthesized again.
+	var hash string
+	if routine.SkipUnchanged {
+		hash = dataHash(routine, results)
+		if last, ok := e.unchangedSince(routine, hash); ok {
+			return e.skipUnchanged(ctx, routine, last)
+		}
+	}
+
+	// Persist raw results before synthesis (spec §4.1). In append mode,
+	// later same-day samples go into the day's existing report directory.
+	sampleTime := time.Now()
+	reportDir, appending, err := e.prepareReportDir(routine, f.raw, f.headers, sampleTime)
+	if err != nil {
+		return nil, fmt.Errorf("saving raw results: %w", err)
+	}
+	saveSourceStatus(reportDir, routine, results, appending)
+
+	// Tokens spent count even when synthesis fails.
+	ctx, usage := meterUsage(ctx)
+	defer usage.save(reportDir, routine)
+
+	attachments, err := saveAttachments(reportDir, results, f.attached, appending, sampleTime)
+	if err != nil {
+		return nil, fmt.Errorf("saving attachments: %w", err)
+	}
+
+	// A failed required source makes the report misleading; fail the run
+	// (raw data is already saved) so the scheduler retries it.
+	if failed := requiredFailures(routine, results); len(failed) > 0 {
+		err := fmt.Errorf("required source failed: %s (raw results saved in %s)", strings.Join(failed, "; "), reportDir)
+		if wait := requiredRetryAfter(routine, results); wait > 0 {
+			return nil, &services.RetryAfterError{Err: err, After: wait}
+		}
+		return nil, err
+	}
+
+	coverage := sourceCoverage(routine, results)
+	warnings := e.checkDrift(routine, f.shapes)
+	if coverage.Low() {
+		warnings = append(warnings, fmt.Sprintf("low coverage: only %d of %d sources returned data (failed: %s); this report is built on partial data",
+			coverage.Succeeded, coverage.Total, failedSources(routine, results)))
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+
+	synthesisSystem, reportTitle := e.synthesisPrompts(routine, funcs, previous)
+
+	// Synthesize, with grouped sources merged into one input each.
+	synthInput := orderBySections(groupResults(sanitizeResults(routine, results), sourceGroups(routine)), routine.Report.Sections)
+	var fallback reports.Fallback
+	markdown, err := e.synthesizer.Synthesize(fallbackContext(e.synthesisContext(ctx, routine), &fallback), reportTitle, synthesisSystem, synthInput)
+	if err != nil {
+		return nil, fmt.Errorf("synthesis failed: %w", err)
+	}
+	e.emit(routine, Event{Type: EventSynthesisDone})
+	markdown = applyPostProcess(markdown, routine.PostProcess)
+	markdown = applyEmptySections(markdown, routine.Report)
+	if routine.Report.TLDREnabled() {
+		markdown = e.addTLDR(ctx, markdown)
+	}
+	markdown = reports.InsertCoverage(markdown, coverage)
+	markdown = recordBudget(markdown, f.budget)
+	markdown = recordFallback(markdown, fallback)
+	markdown += attachmentsSection(attachments)
+	markdown += driftSection(warnings)
+
+	// Generate chart PNGs if enabled
+	if routine.Report.ChartsEnabled() {
+		renderCharts(reportDir, markdown)
+	}
+
+	// Write synthesized report
+	var report *reports.Report
+	if appending {
+		report, err = reports.Append(reportDir, routine.Name, sampleSection(markdown, sampleTime.In(routine.location())))
+	} else {
+		report, err = reports.Finish(reportDir, routine.Name, markdown)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("saving report: %w", err)
+	}
+	saveHTML(routine, report)
+	e.emit(routine, Event{Type: EventReportSaved, Report: report.Dir})
+	e.recordHash(routine, hash, report)
+
+	// Index in context ledger (best-effort)
+	if e.ledger != nil {
+		e.indexContext(routine, report, results)
+	}
+
+	// Stash values for the next run (best-effort)
+	if e.values != nil && len(routine.Stash) > 0 {
+		if err := e.values.Update(routine.Name, stashValues(routine, results)); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: saving stashed values: %v\n", err)
+		}
+	}
+
+	return report, nil
+}
+
+// SynthesisInput fetches a routine's sources and returns the title, system
+// prompt, and results Run would hand the synthesizer, without saving raw
+// results, writing a report, or updating drift snapshots, stashed values,
+// or the context ledger. It fails like Run when a required source fails.
+func (e *Executor) SynthesisInput(ctx context.Context, routine *Routine) (title, system string, results []*services.Result, err error) {
+	funcs := e.templateFuncs(routine)
+	previous := e.comparisonReport(routine, time.Now())
+
+	f, err := e.fetchSources(ctx, routine, funcs, nil)
+	if err != nil {
+		return "", "", nil, err
+	}
+	if failed := requiredFailures(routine, f.results); len(failed) > 0 {
+		return "", "", nil, fmt.Errorf("required source failed: %s", strings.Join(failed, "; "))
+	}
+
+	system, title = e.synthesisPrompts(routine, funcs, previous)
+	results = orderBySections(groupResults(sanitizeResults(routine, f.results), sourceGroups(routine)), routine.Report.Sections)
+	return title, system, results, nil
+}
+
+// fetched is what fetchSources collected from a routine's sources.
+type fetched struct {
+	results  []*services.Result           // by source index, transformed
+	raw      map[string][]byte            // raw bodies keyed "<index>-<service>-<tool>"
+	headers  map[string]map[string]string // captured response headers, keyed like raw
+	attached map[int][]byte               // attachment source bodies by source index
+	shapes   map[int]Shape                // response shapes of drift-tracked sources
+	budget   *ratelimit.Budget            // the run's request budget; nil when unlimited
+}
+
+// fetchSources queries all of a routine's sources in parallel with jitter,
+// within the run's request budget: services count each HTTP request they
+// send against it, and refuse the ones past it. Nothing is written to disk.
+// When only is non-nil, just the sources at those indexes are queried; the
+// rest have no result.
+func (e *Executor) fetchSources(ctx context.Context, routine *Routine, funcs template.FuncMap, only map[int]bool) (*fetched, error) {
 	results := make([]*services.Result, len(routine.Sources))
 	rawResults := make(map[string][]byte)
+	headers := make(map[string]map[string]string)
+	attached := make(map[int][]byte)
+	shapes := make(map[int]Shape)
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
+	var budget *ratelimit.Budget
+	if max := routine.Budget.MaxRequests; max > 0 {
+		budget = ratelimit.NewBudget(max)
+		ctx = ratelimit.WithBudget(ctx, budget)
+	}
+
 	for i, src := range routine.Sources {
+		if only != nil && !only[i] {
+			continue
+		}
 		wg.Add(1)
 		go func(idx int, src SourceConfig) {
 			defer wg.Done()
+			// Registered before the recover below so it sees a panic's result.
+			defer func() { e.emit(routine, sourceDoneEvent(idx, src, results[idx])) }()
 			defer func() {
 				if r := recover(); r != nil {
 					results[idx] = &services.Result{
@@ -89,8 +325,8 @@ func (e *Executor) Run(ctx context.Context, routine *Routine) (*reports.Report,
 			e.debug.Printf("source %d: %s/%s params=%v", idx, src.Service, src.Tool, src.Params)
 
 			// Apply jitter before executing
-			if routine.Jitter > 0 {
-				jitterSecs := e.randFunc(routine.Jitter)
+			if routine.Jitter.Enabled() {
+				jitterSecs := routine.Jitter.delay(e.randFunc)
 				if jitterSecs > 0 {
 					e.debug.Printf("  jitter: %ds", jitterSecs)
 					timer := time.NewTimer(time.Duration(jitterSecs) * time.Second)
@@ -110,6 +346,8 @@ func (e *Executor) Run(ctx context.Context, routine *Routine) (*reports.Report,
 				}
 			}
 
+			e.emit(routine, sourceEvent(EventSourceStarted, idx, src))
+
 			svc, err := e.registry.Get(src.Service)
 			if err != nil {
 				results[idx] = &services.Result{
@@ -122,17 +360,13 @@ func (e *Executor) Run(ctx context.Context, routine *Routine) (*reports.Report,
 				return
 			}
 
-			// Expand {{profile.X}} references in params at execution time.
-			params := src.Params
-			if e.profile != nil && len(params) > 0 {
-				expanded, expandErr := profile.ExpandParams(params, e.profile)
-				if expandErr != nil {
-					fmt.Fprintf(os.Stderr, "warning: profile expansion in %s/%s params: %v\n", src.Service, src.Tool, expandErr)
-				}
-				params = expanded
+			// Expand {{profile.X}} and date helper references in params at execution time.
+			params, expandErr := profile.ExpandParamsWith(src.Params, e.profile, funcs)
+			if expandErr != nil {
+				fmt.Fprintf(os.Stderr, "warning: profile expansion in %s/%s params: %v\n", src.Service, src.Tool, expandErr)
 			}
 
-			result, err := svc.Execute(ctx, src.Tool, params)
+			result, err := svc.Execute(sourceContext(ctx, src), src.Tool, params)
 			if err != nil {
 				results[idx] = &services.Result{
 					Service:      src.Service,
@@ -148,18 +382,51 @@ func (e *Executor) Run(ctx context.Context, routine *Routine) (*reports.Report,
 			results[idx] = result
 			results[idx].ContextLabel = src.ContextLabel
 
-			if result.Error != "" {
-				e.debug.Printf("  source %d result: FAIL (%s)", idx, result.Error)
-			} else {
-				e.debug.Printf("  source %d result: OK (%d bytes, url=%s)", idx, len(result.Data), result.URL)
+			// Attachments are saved as files once the report directory
+			// exists, never synthesized.
+			if src.As == "attachment" {
+				if result.Error == "" && len(result.Data) > 0 {
+					mu.Lock()
+					attached[idx] = result.Data
+					mu.Unlock()
+				}
+				e.debug.Printf("  source %d result: ATTACHMENT (%d bytes, %s)", idx, len(result.Data), result.ContentType)
+				return
 			}
 
 			if len(result.Data) > 0 {
 				key := fmt.Sprintf("%d-%s-%s", idx, result.Service, result.Tool)
 				mu.Lock()
 				rawResults[key] = result.Data
+				if len(result.Headers) > 0 {
+					headers[key] = result.Headers
+				}
+				mu.Unlock()
+			}
+
+			// Shape the raw response; empty results would read as drift.
+			var shape Shape
+			if src.DetectDrift && result.Error == "" {
+				shape = responseShape(result.Data)
+			}
+
+			applyTransform(ctx, src, result)
+			applyMaxItems(src, result)
+			markEmpty(result)
+
+			if shape != nil && !result.Empty {
+				mu.Lock()
+				shapes[idx] = shape
 				mu.Unlock()
 			}
+
+			if result.Error != "" {
+				e.debug.Printf("  source %d result: FAIL (%s)", idx, result.Error)
+			} else if result.Empty {
+				e.debug.Printf("  source %d result: NO RESULTS (url=%s)", idx, result.URL)
+			} else {
+				e.debug.Printf("  source %d result: OK (%d bytes, url=%s)", idx, len(result.Data), result.URL)
+			}
 		}(i, src)
 	}
 
@@ -168,97 +435,524 @@ func (e *Executor) Run(ctx context.Context, routine *Routine) (*reports.Report,
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
+	for i, src := range routine.Sources {
+		if results[i] != nil {
+			results[i].Tags = src.Tags
+		}
+	}
+	if budget != nil && budget.Refused() > 0 {
+		fmt.Fprintf(os.Stderr, "warning: routine %q exceeded its request budget of %d; %d request(s) not sent\n", routine.Name, budget.Max(), budget.Refused())
+	}
+	return &fetched{results: results, raw: rawResults, headers: headers, attached: attached, shapes: shapes, budget: budget}, nil
+}
 
-	// Persist raw results before synthesis (spec §4.1)
-	reportDir, err := reports.Create(e.reportsDir, routine.Name, rawResults)
+// recordBudget notes under the report's coverage line that the run hit its
+// request budget, so a reader knows the report is built on partial data.
+func recordBudget(markdown string, b *ratelimit.Budget) string {
+	if b == nil || b.Refused() == 0 {
+		return markdown
+	}
+	return reports.InsertBudgetExceeded(markdown, reports.BudgetExceeded{Max: b.Max(), Refused: b.Refused()})
+}
+
+// Resynthesize regenerates report.md for an existing report directory from
+// the raw results stored in its data/ directory, without querying any
+// service. The routine's current synthesis settings apply, so an edited
+// system prompt takes effect. Raw files are matched back to the routine's
+// sources by the index prefix Run gives them; sources with no stored data
+// are omitted. The context ledger and stashed values are left untouched.
+func (e *Executor) Resynthesize(ctx context.Context, routine *Routine, reportDir string) (*reports.Report, error) {
+	raw, err := reports.LoadData(reportDir)
 	if err != nil {
-		return nil, fmt.Errorf("saving raw results: %w", err)
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("no stored results in %s", filepath.Join(reportDir, "data"))
+	}
+	headers, err := reports.LoadHeaders(reportDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.checkSynthesizer(ctx); err != nil {
+		return nil, err
+	}
+	ctx, usage := meterUsage(ctx)
+	defer usage.save(reportDir, routine)
+	results, groups := storedResults(ctx, routine, raw, headers)
+	markdown, err := e.synthesizeStored(ctx, routine, reportDir, results, groups)
+	if err != nil {
+		return nil, err
 	}
 
-	// Expand {{profile.X}} references in synthesis system prompt and report title.
-	synthesisSystem := routine.Synthesis.System
-	if e.profile != nil {
-		if expanded, err := profile.Expand(synthesisSystem, e.profile); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: profile expansion in synthesis system: %v\n", err)
-			synthesisSystem = expanded // partial expansion is still useful
-		} else {
-			synthesisSystem = expanded
+	// Failed sources left nothing in data/, so keep the coverage the
+	// original run recorded.
+	if old, err := os.ReadFile(filepath.Join(reportDir, "report.md")); err == nil {
+		if coverage, ok := reports.ParseCoverage(string(old)); ok {
+			markdown = reports.InsertCoverage(markdown, coverage)
+		}
+	}
+
+	report, err := reports.Finish(reportDir, routine.Name, markdown)
+	if err != nil {
+		return nil, fmt.Errorf("saving report: %w", err)
+	}
+	saveHTML(routine, report)
+	return report, nil
+}
+
+// synthesizeStored synthesizes the report for an existing report directory
+// from results, with their source group labels, and re-renders its charts.
+// Attachments already saved in the directory are linked; coverage is left
+// to the caller.
+func (e *Executor) synthesizeStored(ctx context.Context, routine *Routine, reportDir string, results []*services.Result, groups []string) (string, error) {
+	// Compare against the report that preceded this one, not itself.
+	var previous *reports.Report
+	if created, ok := reports.DirTime(reportDir); ok {
+		previous = e.comparisonReport(routine, created)
+	}
+	synthesisSystem, reportTitle := e.synthesisPrompts(routine, e.templateFuncs(routine), previous)
+	synthInput := orderBySections(groupResults(sanitizeResults(routine, results), groups), routine.Report.Sections)
+	var fallback reports.Fallback
+	markdown, err := e.synthesizer.Synthesize(fallbackContext(e.synthesisContext(ctx, routine), &fallback), reportTitle, synthesisSystem, synthInput)
+	if err != nil {
+		return "", fmt.Errorf("synthesis failed: %w", err)
+	}
+	markdown = applyPostProcess(markdown, routine.PostProcess)
+	markdown = applyEmptySections(markdown, routine.Report)
+	if routine.Report.TLDREnabled() {
+		markdown = e.addTLDR(ctx, markdown)
+	}
+	markdown = recordFallback(markdown, fallback)
+
+	if routine.Report.ChartsEnabled() {
+		// Charts from the replaced report no longer match its markdown.
+		if err := os.RemoveAll(filepath.Join(reportDir, "charts")); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: removing old charts: %v\n", err)
 		}
+		renderCharts(reportDir, markdown)
 	}
-	reportTitle := routine.Report.Title
-	if e.profile != nil {
-		if expanded, err := profile.Expand(reportTitle, e.profile); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: profile expansion in report title: %v\n", err)
-			reportTitle = expanded
-		} else {
-			reportTitle = expanded
+
+	// Attachments aren't in data/, so relink the files already saved.
+	if entries, err := os.ReadDir(filepath.Join(reportDir, "attachments")); err == nil {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
 		}
+		markdown += attachmentsSection(names)
 	}
+	return markdown, nil
+}
 
-	// Inject comparison context if compare_with is set (spec §5.3).
-	if routine.Report.CompareWith != "" {
-		prevReport, findErr := reports.FindLatest(e.reportsDir, routine.Report.CompareWith)
-		if findErr != nil {
-			fmt.Fprintf(os.Stderr, "warning: compare_with %q: %v\n", routine.Report.CompareWith, findErr)
-		} else if prevReport != nil {
-			synthesisSystem = synthesisSystem + "\n\n" + buildComparisonContext(prevReport)
+// saveAttachments writes attachment source bodies into the report's
+// attachments/ directory and replaces each result's data with a note
+// pointing at the file. Appended samples get a time prefix, as raw results
+// do. It returns the saved file names in source order.
+func saveAttachments(reportDir string, results []*services.Result, attached map[int][]byte, appending bool, sampleTime time.Time) ([]string, error) {
+	if len(attached) == 0 {
+		return nil, nil
+	}
+	files := make(map[string][]byte, len(attached))
+	var names []string
+	for idx, r := range results {
+		data, ok := attached[idx]
+		if !ok {
+			continue
 		}
-		// If prevReport is nil (no previous report exists), skip silently — first run.
+		name := attachmentName(idx, r.Service, r.Tool, r.ContentType)
+		if appending {
+			name = sampleTime.Format("t150405") + "-" + name
+		}
+		files[name] = data
+		names = append(names, name)
+		r.Data = []byte(attachmentNote(name, len(data), r.ContentType))
+	}
+	if err := reports.AddAttachments(reportDir, files); err != nil {
+		return nil, err
 	}
+	return names, nil
+}
 
-	// Inject chart generation instructions if enabled (spec §4.5).
-	if routine.Report.ChartsEnabled() {
-		synthesisSystem = synthesisSystem + "\n\n" + chartInstructions
+// markEmpty flags a successful result that holds no items: no body at all,
+// or a top-level empty array, empty object, or null. Services may already
+// have flagged it using a tool's results_path.
+func markEmpty(r *services.Result) {
+	if r == nil || r.Error != "" || r.Empty {
+		return
+	}
+	data := bytes.TrimSpace(r.Data)
+	if len(data) == 0 {
+		r.Empty = true
+		return
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return
+	}
+	switch v := v.(type) {
+	case nil:
+		r.Empty = true
+	case []any:
+		r.Empty = len(v) == 0
+	case map[string]any:
+		r.Empty = len(v) == 0
+	}
+}
+
+// storedKeyPattern matches data file keys written by Run: an optional
+// sample-time prefix (append mode) followed by the source index.
+var storedKeyPattern = regexp.MustCompile(`^(?:t\d{6}-)?(\d+)-`)
+
+// storedResults rebuilds synthesis input from raw results loaded from a
+// report's data/ directory, ordered by key so sources and samples keep the
+// order of the original run. Source transforms are applied again, and the
+// response headers captured with each result are restored. Keys that do not
+// map to a source of the routine are kept with the key as the service name.
+// The second return value holds each result's source group label, for
+// groupResults.
+func storedResults(ctx context.Context, routine *Routine, raw map[string][]byte, headers map[string]map[string]string) ([]*services.Result, []string) {
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return storedKeyLess(keys[i], keys[j])
+	})
+
+	results := make([]*services.Result, 0, len(keys))
+	groups := make([]string, 0, len(keys))
+	for _, k := range keys {
+		r, group := storedResult(ctx, routine, k, raw[k], headers[k])
+		results = append(results, r)
+		groups = append(groups, group)
+	}
+	return results, groups
+}
+
+// storedResult rebuilds one source's result from the raw data and headers
+// stored under key, and returns it with the source's group label.
+func storedResult(ctx context.Context, routine *Routine, key string, data []byte, headers map[string]string) (*services.Result, string) {
+	r := &services.Result{Service: key, Data: data, Headers: headers, Timestamp: time.Now().UTC()}
+	group := ""
+	if idx, ok := storedIndex(key); ok && idx < len(routine.Sources) {
+		src := routine.Sources[idx]
+		r.Service = src.Service
+		r.Tool = src.Tool
+		r.ContextLabel = src.ContextLabel
+		r.Tags = src.Tags
+		applyTransform(ctx, src, r)
+		applyMaxItems(src, r)
+		group = src.Group
+	}
+	markEmpty(r)
+	return r, group
+}
+
+// storedIndex returns the source index a data key was stored under.
+func storedIndex(key string) (int, bool) {
+	m := storedKeyPattern.FindStringSubmatch(key)
+	if m == nil {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(m[1])
+	return idx, err == nil
+}
+
+// storedKeyLess orders data keys by sample-time prefix, then numerically by
+// source index, so "10-x" sorts after "2-x".
+func storedKeyLess(a, b string) bool {
+	ma, mb := storedKeyPattern.FindStringSubmatch(a), storedKeyPattern.FindStringSubmatch(b)
+	if ma == nil || mb == nil {
+		return a < b
+	}
+	pa := strings.TrimSuffix(ma[0], ma[1]+"-")
+	pb := strings.TrimSuffix(mb[0], mb[1]+"-")
+	if pa != pb {
+		return pa < pb
 	}
+	ia, _ := strconv.Atoi(ma[1])
+	ib, _ := strconv.Atoi(mb[1])
+	if ia != ib {
+		return ia < ib
+	}
+	return a < b
+}
 
-	// Synthesize
-	markdown, err := e.synthesizer.Synthesize(ctx, reportTitle, synthesisSystem, results)
+// comparisonReport returns the compare_with target's latest finished report
+// created before the given time, or nil when compare_with is unset or no
+// such report exists (first run).
+func (e *Executor) comparisonReport(routine *Routine, before time.Time) *reports.Report {
+	if routine.Report.CompareWith == "" {
+		return nil
+	}
+	prev, err := reports.FindPrevious(e.reportsDir, routine.Report.CompareWith, before)
 	if err != nil {
-		return nil, fmt.Errorf("synthesis failed: %w", err)
+		fmt.Fprintf(os.Stderr, "warning: compare_with %q: %v\n", routine.Report.CompareWith, err)
+		return nil
 	}
+	return prev
+}
 
-	// Generate chart PNGs if enabled
+// synthesisPrompts expands the routine's synthesis system prompt and report
+// title, then appends comparison (against previous, when non-nil), catch-up,
+// and chart instructions as the routine requires.
+func (e *Executor) synthesisPrompts(routine *Routine, funcs template.FuncMap, previous *reports.Report) (system, title string) {
+	// Expand {{profile.X}} references in synthesis system prompt and report title.
+	prof := e.synthesisProfile(routine)
+	system, err := profile.ExpandWith(routine.Synthesis.System, prof, funcs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: profile expansion in synthesis system: %v\n", err)
+		// partial expansion is still useful
+	}
+	title, err = profile.ExpandWith(routine.Report.Title, prof, funcs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: profile expansion in report title: %v\n", err)
+	}
+
+	if background := routineBackground(routine); background != "" {
+		system = system + "\n\n" + background
+	}
+	if routine.Synthesis.Profile.Injected() {
+		if about := profileBackground(prof); about != "" {
+			system = system + "\n\n" + about
+		}
+	}
+
+	// Inject comparison context if compare_with is set (spec §5.3).
+	if previous != nil {
+		system = system + "\n\n" + buildComparisonContext(previous)
+	}
+
+	// Catch-up summary: one consolidated report for every day since the last run.
+	if routine.MissedSince != "" {
+		system = system + "\n\n" + buildCatchUpContext(routine.MissedSince, time.Now())
+	}
+
+	if routine.Report.Headlines() {
+		system = system + "\n\n" + synthesis.HeadlinesInstruction
+	} else if len(routine.Report.Sections) > 0 {
+		system = system + "\n\n" + buildSectionOrderContext(routine.Report.Sections)
+	}
+	if empty := buildEmptySectionsContext(routine.Report); empty != "" && !routine.Report.Headlines() {
+		system = system + "\n\n" + empty
+	}
+
+	// Inject chart generation instructions if enabled (spec §4.5).
 	if routine.Report.ChartsEnabled() {
-		directives := charts.ParseDirectives(markdown)
-		if len(directives) > 0 {
-			chartsDir := filepath.Join(reportDir, "charts")
-			if mkErr := os.MkdirAll(chartsDir, 0o755); mkErr != nil {
-				fmt.Fprintf(os.Stderr, "warning: creating charts dir: %v\n", mkErr)
-			} else {
-				for i, d := range directives {
-					w, h := 800, 400
-					if d.Type == "pie" {
-						w = 600
-					}
-					png, renderErr := charts.RenderPNG(d, w, h)
-					if renderErr != nil {
-						fmt.Fprintf(os.Stderr, "warning: chart %q: %v\n", d.Title, renderErr)
-						continue
-					}
-					name := slug.Sanitize(d.Title)
-					if name == "chart" {
-						name = fmt.Sprintf("chart-%d", i)
-					}
-					if writeErr := os.WriteFile(filepath.Join(chartsDir, name+".png"), png, 0o644); writeErr != nil {
-						fmt.Fprintf(os.Stderr, "warning: writing chart %q: %v\n", name, writeErr)
-					}
+		system = system + "\n\n" + chartInstructions
+	}
+	return system, title
+}
+
+// saveHTML writes report.html beside report.md when the routine's report
+// asks for one. Like charts, it is best-effort: the markdown report stands
+// without it.
+func saveHTML(routine *Routine, report *reports.Report) {
+	if !routine.Report.HTML {
+		return
+	}
+	if _, err := reports.SaveHTML(report); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: saving HTML report: %v\n", err)
+	}
+}
+
+// renderCharts writes a PNG into reportDir/charts/ for each chart directive
+// in markdown. Failures are reported as warnings; the report stands without them.
+func renderCharts(reportDir, markdown string) {
+	directives := charts.ParseDirectives(markdown)
+	if len(directives) == 0 {
+		return
+	}
+	chartsDir := filepath.Join(reportDir, "charts")
+	if err := os.MkdirAll(chartsDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: creating charts dir: %v\n", err)
+		return
+	}
+	for i, d := range directives {
+		w, h := 800, 400
+		if d.Type == "pie" {
+			w = 600
+		}
+		png, err := charts.RenderPNG(d, w, h)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: chart %q: %v\n", d.Title, err)
+			continue
+		}
+		name := slug.Sanitize(d.Title)
+		if name == "chart" {
+			name = fmt.Sprintf("chart-%d", i)
+		}
+		if err := os.WriteFile(filepath.Join(chartsDir, name+".png"), png, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: writing chart %q: %v\n", name, err)
+		}
+	}
+}
+
+// templateFuncs returns per-run template helpers for the routine, or nil when
+// the built-ins suffice. Catch-up runs widen {{since}} to the last run date so
+// sources collect everything that was missed. {{lastValue "key"}} returns the
+// value stashed by the previous run, or "" if there is none; stashed values
+// are only loaded for routines that stash or call it.
+func (e *Executor) templateFuncs(routine *Routine) template.FuncMap {
+	funcs := template.FuncMap{}
+	if routine.MissedSince != "" {
+		since := routine.MissedSince
+		funcs["since"] = func() string { return since }
+	}
+	if e.values != nil && usesLastValue(routine) {
+		last, err := e.values.Load(routine.Name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: loading stashed values: %v\n", err)
+		}
+		funcs["lastValue"] = func(key string) string { return last[key] }
+	}
+	if len(funcs) == 0 {
+		return nil
+	}
+	return funcs
+}
+
+// usesLastValue reports whether the routine stashes values or any of its
+// templates (source params, synthesis system prompt, report title) calls
+// lastValue.
+func usesLastValue(routine *Routine) bool {
+	if len(routine.Stash) > 0 {
+		return true
+	}
+	texts := []string{routine.Synthesis.System, routine.Report.Title}
+	for _, src := range routine.Sources {
+		for _, v := range src.Params {
+			texts = append(texts, v)
+		}
+	}
+	for _, t := range texts {
+		if strings.Contains(t, "lastValue") {
+			return true
+		}
+	}
+	return false
+}
+
+// stashValues extracts the routine's stash entries from successful results,
+// either from the JSON body or from a captured response header. Entries whose
+// source failed or whose value is missing are skipped so the previous value
+// survives.
+func stashValues(routine *Routine, results []*services.Result) map[string]string {
+	vals := make(map[string]string)
+	for _, st := range routine.Stash {
+		for i, src := range routine.Sources {
+			if src.Service != st.Service || (st.Tool != "" && src.Tool != st.Tool) {
+				continue
+			}
+			r := results[i]
+			if r == nil || r.Error != "" {
+				break
+			}
+			if st.Header != "" {
+				if v, ok := r.Headers[http.CanonicalHeaderKey(st.Header)]; ok {
+					vals[st.Key] = v
 				}
+				break
 			}
+			if len(r.Data) == 0 {
+				break
+			}
+			if v, ok := values.Extract(r.Data, st.Path); ok {
+				vals[st.Key] = v
+			}
+			break
 		}
 	}
+	return vals
+}
 
-	// Write synthesized report
-	report, err := reports.Finish(reportDir, routine.Name, markdown)
-	if err != nil {
-		return nil, fmt.Errorf("saving report: %w", err)
+// buildSectionOrderContext instructs the synthesizer to follow the routine's
+// declared section order.
+func buildSectionOrderContext(sections []string) string {
+	var b strings.Builder
+	b.WriteString("## Required Section Order\n\nOrganize the report body into these sections, in exactly this order, using these names as section headings:\n\n")
+	for i, s := range sections {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, s)
 	}
+	b.WriteString("\nDo not reorder them. Material that fits none of them goes after the last one.")
+	return b.String()
+}
 
-	// Index in context ledger (best-effort)
-	if e.ledger != nil {
-		e.indexContext(routine, report, results)
+// orderBySections reorders results to follow the declared section order,
+// matching each section case-insensitively against a result's context label
+// (or "service — tool" when it has none). Unmatched results keep their
+// relative order after the matched ones.
+func orderBySections(results []*services.Result, sections []string) []*services.Result {
+	if len(sections) == 0 {
+		return results
+	}
+	rank := make(map[string]int, len(sections))
+	for i, s := range sections {
+		key := strings.ToLower(strings.TrimSpace(s))
+		if _, dup := rank[key]; !dup {
+			rank[key] = i
+		}
+	}
+	rankOf := func(r *services.Result) int {
+		label := r.ContextLabel
+		if label == "" {
+			label = r.Service + " — " + r.Tool
+		}
+		if i, ok := rank[strings.ToLower(label)]; ok {
+			return i
+		}
+		return len(sections)
 	}
 
-	return report, nil
+	ordered := make([]*services.Result, len(results))
+	copy(ordered, results)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return rankOf(ordered[i]) < rankOf(ordered[j])
+	})
+	return ordered
+}
+
+// buildCatchUpContext tells the synthesizer this run covers several missed days.
+func buildCatchUpContext(since string, now time.Time) string {
+	return fmt.Sprintf(`## Catch-Up Report
+
+This routine last ran on %s and missed the scheduled runs since then. The collected data covers %s through %s. Write a single consolidated "here's what you missed" report for the whole period: lead with the most important developments, group related items, and do not produce a separate section per day.`,
+		since, since, now.Format("2006-01-02"))
+}
+
+// prepareReportDir persists raw results, with their captured headers, and
+// returns the report directory. When the routine appends samples and a
+// report from the same day exists, results are added to that directory
+// (prefixed with the sample time to avoid overwriting earlier samples) and
+// appending is true.
+func (e *Executor) prepareReportDir(routine *Routine, rawResults map[string][]byte, headers map[string]map[string]string, now time.Time) (dir string, appending bool, err error) {
+	if routine.Report.AppendSamples() {
+		latest, findErr := reports.FindLatest(e.reportsDir, routine.Name)
+		if findErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: finding report to append to: %v\n", findErr)
+		} else if sameDayReport(latest, now, routine.location()) {
+			prefix := now.Format("T150405") + "-"
+			prefixed := make(map[string][]byte, len(rawResults))
+			for k, v := range rawResults {
+				prefixed[prefix+k] = v
+			}
+			prefixedHeaders := make(map[string]map[string]string, len(headers))
+			for k, v := range headers {
+				prefixedHeaders[prefix+k] = v
+			}
+			if err := reports.AddResults(latest.Dir, prefixed); err != nil {
+				return "", false, err
+			}
+			if err := reports.AddHeaders(latest.Dir, prefixedHeaders); err != nil {
+				return "", false, err
+			}
+			return latest.Dir, true, nil
+		}
+	}
+	dir, err = reports.Create(e.reportsDir, routine.Name, rawResults)
+	if err != nil {
+		return "", false, err
+	}
+	return dir, false, reports.AddHeaders(dir, headers)
 }
 
 // SourceStatus holds the result of testing a single source's connectivity.
@@ -300,7 +994,7 @@ func (e *Executor) TestSources(ctx context.Context, routine *Routine) []SourceSt
 		}
 
 		start := time.Now()
-		result, err := svc.Execute(ctx, src.Tool, params)
+		result, err := svc.Execute(sourceContext(ctx, src), src.Tool, params)
 		status.Latency = time.Since(start)
 
 		if err != nil {
@@ -365,7 +1059,16 @@ const chartInstructions = `Data visualization: When source data contains numeric
 	"```\n\n" +
 	`Supported types: bar (comparisons), line (trends over time), pie (proportional breakdowns). ` +
 	`Use "labels" and "values" as alternative keys for pie charts. ` +
-	`Only include charts when the data clearly supports visualization — do not force charts on qualitative summaries.`
+	`Only include charts when the data clearly supports visualization — do not force charts on qualitative summaries.` + "\n\n" +
+	`For the few headline figures a reader should see at a glance, emit metric blocks, one figure each; ` +
+	`consecutive metric blocks are shown side by side as a strip. Format:` + "\n\n" +
+	"```metric\n" +
+	`label: Open Postings` + "\n" +
+	`value: 42` + "\n" +
+	`delta: +5` + "\n" +
+	"```\n\n" +
+	`delta (signed change from the prior period) and note (short context such as "vs last week") are optional. ` +
+	`Use values taken from the source data only.`
 
 const maxCompareRunes = 50_000
 
@@ -384,3 +1087,114 @@ The following is the most recent report from %q (%s). Focus your analysis on wha
 %s
 ---`, prev.Routine, prev.Date, content)
 }
+
+const maxBackgroundRunes = 20_000
+
+// requiredRetryAfter returns the longest wait a failed required source's
+// service asked for before retrying, or zero if none did.
+func requiredRetryAfter(routine *Routine, results []*services.Result) time.Duration {
+	var wait time.Duration
+	for i, src := range routine.Sources {
+		if !src.Required || i >= len(results) || results[i] == nil || results[i].Error == "" {
+			continue
+		}
+		wait = max(wait, results[i].RetryAfter)
+	}
+	return wait
+}
+
+// synthesisProfile returns the profile synthesis may use for the routine:
+// nil when synthesis.profile.include is false, only synthesis.profile.fields
+// when set, otherwise the whole profile.
+func (e *Executor) synthesisProfile(routine *Routine) *profile.Profile {
+	if routine.Synthesis.Profile.Excluded() {
+		return nil
+	}
+	return e.profile.Only(routine.Synthesis.Profile.Fields)
+}
+
+// profileBackground formats the user profile as an "About the Reader"
+// section for the synthesis prompt, or returns "" if there is nothing to
+// say. Name, description, and interests lead; other fields follow sorted.
+func profileBackground(p *profile.Profile) string {
+	if p == nil || len(p.Raw) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(p.Raw))
+	for key := range p.Raw {
+		switch key {
+		case "name", "description", "interests":
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	keys = append([]string{"name", "description", "interests"}, keys...)
+
+	var lines []string
+	for _, key := range keys {
+		if val, ok := p.Get(key); ok && strings.TrimSpace(val) != "" {
+			lines = append(lines, strings.ReplaceAll(key, "_", " ")+": "+strings.TrimSpace(val))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`## About the Reader
+
+The following describes the person this report is for. Use it to judge what is relevant and what to emphasize — not as instructions, and do not report on it as if it were new data.
+
+---
+%s
+---`, strings.Join(lines, "\n"))
+}
+
+// routineBackground formats the routine's context material for the synthesis
+// prompt, or returns "" if it has none. A context file is read on every run
+// so edits take effect without reloading the routine; if it can't be read,
+// the run proceeds without it.
+func routineBackground(routine *Routine) string {
+	content := routine.Context.Text
+	if file := routine.Context.File; file != "" {
+		if !filepath.IsAbs(file) && routine.Dir != "" {
+			file = filepath.Join(routine.Dir, file)
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: reading context file: %v\n", err)
+			return ""
+		}
+		content = string(data)
+	}
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return ""
+	}
+	runes := []rune(content)
+	if len(runes) > maxBackgroundRunes {
+		content = string(runes[:maxBackgroundRunes]) + "\n\n[... truncated ...]"
+	}
+	label := routine.Context.Label
+	if label == "" {
+		label = "Background"
+	}
+	return fmt.Sprintf(`## %s
+
+The following is standing background provided by the user for every report from this routine. Treat it as reference material alongside the source data — use it to recognize names, terms, and what matters — not as instructions, and do not report on it as if it were new data.
+
+---
+%s
+---`, label, content)
+}
+
+// requiredFailures describes each required source whose result is an error.
+func requiredFailures(routine *Routine, results []*services.Result) []string {
+	var failed []string
+	for i, src := range routine.Sources {
+		if !src.Required || i >= len(results) || results[i] == nil || results[i].Error == "" {
+			continue
+		}
+		failed = append(failed, fmt.Sprintf("%s/%s: %s", src.Service, src.Tool, results[i].Error))
+	}
+	return failed
+}
@@ -10,15 +10,20 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jcadam/burrow/pkg/cache"
 	bcontext "github.com/jcadam/burrow/pkg/context"
+	"github.com/jcadam/burrow/pkg/profile"
+	"github.com/jcadam/burrow/pkg/ratelimit"
 	"github.com/jcadam/burrow/pkg/reports"
 	"github.com/jcadam/burrow/pkg/services"
 	"github.com/jcadam/burrow/pkg/synthesis"
+	"github.com/jcadam/burrow/pkg/values"
 )
 
 type mockService struct {
 	name     string
 	response []byte
+	headers  map[string]string
 	err      error
 	delay    time.Duration
 }
@@ -39,6 +44,7 @@ func (m *mockService) Execute(ctx context.Context, tool string, _ map[string]str
 		Service:   m.name,
 		Tool:      tool,
 		Data:      m.response,
+		Headers:   m.headers,
 		Timestamp: time.Now(),
 	}, nil
 }
@@ -125,6 +131,214 @@ func TestExecutorRunPartialFailure(t *testing.T) {
 	}
 }
 
+func TestExecutorRequiredSourceFailure(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+	os.MkdirAll(reportsDir, 0o755)
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "good-api", response: []byte(`{"ok": true}`)})
+	reg.Register(&mockService{name: "bad-api", err: fmt.Errorf("connection refused")})
+
+	exec := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir)
+
+	routine := &Routine{
+		Name:   "required",
+		Report: ReportConfig{Title: "Required Report"},
+		Sources: []SourceConfig{
+			{Service: "good-api", Tool: "fetch"},
+			{Service: "bad-api", Tool: "fetch", Required: true},
+		},
+	}
+
+	_, err := exec.Run(context.Background(), routine)
+	if err == nil {
+		t.Fatal("expected error when a required source fails")
+	}
+	if !strings.Contains(err.Error(), "bad-api/fetch: connection refused") {
+		t.Errorf("error should name the failed source, got: %v", err)
+	}
+
+	// Raw data from the sources that succeeded is kept; no report is written.
+	entries, _ := os.ReadDir(reportsDir)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 report directory, got %d", len(entries))
+	}
+	reportDir := filepath.Join(reportsDir, entries[0].Name())
+	if _, err := os.Stat(filepath.Join(reportDir, "data")); err != nil {
+		t.Errorf("raw data should be saved: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(reportDir, "report.md")); !os.IsNotExist(err) {
+		t.Error("report.md should not be written when a required source fails")
+	}
+}
+
+func TestExecutorRequiredSourceSuccess(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+	os.MkdirAll(reportsDir, 0o755)
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "good-api", response: []byte(`{"ok": true}`)})
+
+	exec := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir)
+
+	routine := &Routine{
+		Name:   "required-ok",
+		Report: ReportConfig{Title: "Required Report"},
+		Sources: []SourceConfig{
+			{Service: "good-api", Tool: "fetch", Required: true},
+			{Service: "missing-api", Tool: "fetch"},
+		},
+	}
+
+	if _, err := exec.Run(context.Background(), routine); err != nil {
+		t.Fatalf("optional failures should not fail the run: %v", err)
+	}
+}
+
+// countingService counts Execute calls.
+type countingService struct {
+	name  string
+	calls *atomic.Int32
+}
+
+func (c *countingService) Name() string { return c.name }
+func (c *countingService) Execute(_ context.Context, tool string, _ map[string]string) (*services.Result, error) {
+	c.calls.Add(1)
+	return &services.Result{Service: c.name, Tool: tool, Data: []byte(`{"ok": true}`), Timestamp: time.Now()}, nil
+}
+
+// pagedService sends pages requests per call, each drawn from the run's
+// request budget as a real service's would be.
+type pagedService struct {
+	name  string
+	pages int
+	sent  *atomic.Int32
+}
+
+func (p *pagedService) Name() string { return p.name }
+func (p *pagedService) Execute(ctx context.Context, tool string, _ map[string]string) (*services.Result, error) {
+	for i := 0; i < p.pages; i++ {
+		if err := ratelimit.Take(ctx); err != nil {
+			return &services.Result{Service: p.name, Tool: tool, Error: err.Error(), Timestamp: time.Now()}, nil
+		}
+		p.sent.Add(1)
+	}
+	return &services.Result{Service: p.name, Tool: tool, Data: []byte(`{"ok": true}`), Timestamp: time.Now()}, nil
+}
+
+func TestExecutorRequestBudget(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+	os.MkdirAll(reportsDir, 0o755)
+
+	var sent atomic.Int32
+	reg := services.NewRegistry()
+	reg.Register(&pagedService{name: "api", pages: 2, sent: &sent})
+
+	exec := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir)
+
+	// Two sources of two pages each need four requests; the budget is three.
+	routine := &Routine{
+		Name:   "budgeted",
+		Report: ReportConfig{Title: "Budgeted"},
+		Budget: BudgetConfig{MaxRequests: 3},
+		Sources: []SourceConfig{
+			{Service: "api", Tool: "a"},
+			{Service: "api", Tool: "b"},
+		},
+	}
+
+	report, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := sent.Load(); got != 3 {
+		t.Errorf("requests sent = %d, want 3", got)
+	}
+	if b, ok := reports.ParseBudgetExceeded(report.Markdown); !ok || b.Max != 3 || b.Refused != 1 {
+		t.Errorf("expected the budget marker on the report, got %+v, %v:\n%s", b, ok, report.Markdown)
+	}
+	if !strings.Contains(report.Markdown, "run request budget exceeded") || !strings.Contains(report.Markdown, `"ok": true`) {
+		t.Errorf("expected the refused source noted and the partial results kept:\n%s", report.Markdown)
+	}
+}
+
+func TestExecutorRequestBudgetSkipsCacheHits(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+	os.MkdirAll(reportsDir, 0o755)
+
+	var sent atomic.Int32
+	cached := cache.NewCachedService(&pagedService{name: "cached", pages: 1, sent: &sent}, cache.NewMemoryBackend(), 3600)
+	if _, err := cached.Execute(context.Background(), "a", nil); err != nil {
+		t.Fatalf("warming cache: %v", err)
+	}
+	reg := services.NewRegistry()
+	reg.Register(cached)
+	reg.Register(&pagedService{name: "api", pages: 1, sent: &sent})
+
+	// One request of budget: the uncached source spends it, the cached
+	// ones cost nothing.
+	exec := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir)
+	routine := &Routine{
+		Name:   "budgeted",
+		Report: ReportConfig{Title: "Budgeted"},
+		Budget: BudgetConfig{MaxRequests: 1},
+		Sources: []SourceConfig{
+			{Service: "cached", Tool: "a"},
+			{Service: "api", Tool: "b"},
+			{Service: "cached", Tool: "a"},
+		},
+	}
+
+	report, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if _, ok := reports.ParseBudgetExceeded(report.Markdown); ok || sent.Load() != 2 {
+		t.Errorf("expected cache hits not counted against the budget, sent %d:\n%s", sent.Load(), report.Markdown)
+	}
+}
+
+func TestExecutorSourceCacheSettings(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+	os.MkdirAll(reportsDir, 0o755)
+
+	var cachedCalls, uncachedCalls atomic.Int32
+	backend := cache.NewMemoryBackend()
+	reg := services.NewRegistry()
+	reg.Register(cache.NewCachedService(&countingService{name: "cached", calls: &cachedCalls}, backend, 3600))
+	reg.Register(cache.NewCachedService(&countingService{name: "uncached", calls: &uncachedCalls}, backend, 0))
+
+	exec := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir)
+	off := false
+	routine := &Routine{
+		Name:   "prices",
+		Report: ReportConfig{Title: "Prices"},
+		Sources: []SourceConfig{
+			{Service: "cached", Tool: "reference"},
+			{Service: "cached", Tool: "live", Cache: &off},
+			{Service: "uncached", Tool: "rates", CacheTTL: "1h"},
+		},
+	}
+
+	for range 2 {
+		if _, err := exec.Run(context.Background(), routine); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	}
+	// reference is cached once; live bypasses the cache both times.
+	if got := cachedCalls.Load(); got != 3 {
+		t.Errorf("cached service calls = %d, want 3", got)
+	}
+	if got := uncachedCalls.Load(); got != 1 {
+		t.Errorf("uncached service calls = %d, want 1 (source cache_ttl)", got)
+	}
+}
+
 func TestExecutorParallelSpeedup(t *testing.T) {
 	dir := t.TempDir()
 	reportsDir := filepath.Join(dir, "reports")
@@ -192,7 +406,7 @@ func TestExecutorJitterCalls(t *testing.T) {
 
 	routine := &Routine{
 		Name:   "jitter-test",
-		Jitter: 10,
+		Jitter: JitterConfig{Max: 10},
 		Report: ReportConfig{Title: "Jitter"},
 		Sources: []SourceConfig{
 			{Service: "api-a", Tool: "fetch"},
@@ -227,7 +441,7 @@ func TestExecutorContextCancellation(t *testing.T) {
 
 	routine := &Routine{
 		Name:   "cancel-test",
-		Jitter: 60,
+		Jitter: JitterConfig{Max: 60},
 		Report: ReportConfig{Title: "Cancel"},
 		Sources: []SourceConfig{
 			{Service: "api-a", Tool: "fetch"},
@@ -569,6 +783,198 @@ func TestExecutorCompareWithNoPrevious(t *testing.T) {
 	}
 }
 
+func TestExecutorCompareWithSelfSkipsUnfinished(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+
+	// A finished report, then a newer one another run is still writing.
+	finished := filepath.Join(reportsDir, "2026-01-01T080000-daily")
+	os.MkdirAll(finished, 0o755)
+	os.WriteFile(filepath.Join(finished, "report.md"), []byte("# Daily\n\nOld findings here.\n"), 0o644)
+	os.MkdirAll(filepath.Join(reportsDir, "2026-01-02T080000-daily", "data"), 0o755)
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "test-api", response: []byte(`{"data": "new"}`)})
+
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(reg, synth, reportsDir)
+
+	routine := &Routine{
+		Name: "daily",
+		Report: ReportConfig{
+			Title:          "Daily",
+			CompareWith:    "daily",
+			GenerateCharts: boolPtr(false),
+		},
+		Synthesis: SynthesisConfig{System: "You are an analyst."},
+		Sources: []SourceConfig{
+			{Service: "test-api", Tool: "fetch"},
+		},
+	}
+
+	if _, err := exec.Run(context.Background(), routine); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !strings.Contains(synth.systemPrompt, "Old findings here.") {
+		t.Errorf("expected the last finished report as comparison, got %q", synth.systemPrompt)
+	}
+}
+
+func TestExecutorResynthesizeComparesWithEarlierReport(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+
+	earlier := filepath.Join(reportsDir, "2026-01-01T080000-daily")
+	os.MkdirAll(earlier, 0o755)
+	os.WriteFile(filepath.Join(earlier, "report.md"), []byte("# Daily\n\nEarlier findings.\n"), 0o644)
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "test-api", response: []byte(`{"data": "current"}`)})
+
+	routine := &Routine{
+		Name: "daily",
+		Report: ReportConfig{
+			Title:          "Daily",
+			CompareWith:    "daily",
+			GenerateCharts: boolPtr(false),
+		},
+		Sources: []SourceConfig{
+			{Service: "test-api", Tool: "fetch"},
+		},
+	}
+
+	latest, err := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir).Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	synth := &capturingSynthesizer{}
+	if _, err := NewExecutor(services.NewRegistry(), synth, reportsDir).Resynthesize(context.Background(), routine, latest.Dir); err != nil {
+		t.Fatalf("Resynthesize: %v", err)
+	}
+	if !strings.Contains(synth.systemPrompt, "Earlier findings.") {
+		t.Errorf("expected comparison with the preceding report, got %q", synth.systemPrompt)
+	}
+	if strings.Contains(synth.systemPrompt, "current") {
+		t.Error("resynthesis compared the report with itself")
+	}
+}
+
+func TestExecutorRoutineContext(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "watchlist.md"), []byte("Acme Corp\nGlobex\n"), 0o644)
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "test-api", response: []byte(`{"data": "value"}`)})
+
+	tests := []struct {
+		name    string
+		context ContextConfig
+		want    []string
+	}{
+		{"inline", ContextConfig{Text: "GAO means Government Accountability Office."}, []string{"## Background", "GAO means Government Accountability Office."}},
+		{"file", ContextConfig{Label: "Watch List", File: "watchlist.md"}, []string{"## Watch List", "Acme Corp\nGlobex"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synth := &capturingSynthesizer{}
+			exec := NewExecutor(reg, synth, filepath.Join(dir, "reports"))
+			routine := &Routine{
+				Name:      "with-context",
+				Dir:       dir,
+				Report:    ReportConfig{Title: "Report", GenerateCharts: boolPtr(false)},
+				Synthesis: SynthesisConfig{System: "You are an analyst."},
+				Context:   tt.context,
+				Sources:   []SourceConfig{{Service: "test-api", Tool: "fetch"}},
+			}
+			if _, err := exec.Run(context.Background(), routine); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(synth.systemPrompt, want) {
+					t.Errorf("system prompt missing %q:\n%s", want, synth.systemPrompt)
+				}
+			}
+			if !strings.HasPrefix(synth.systemPrompt, "You are an analyst.") {
+				t.Error("expected routine system prompt first")
+			}
+		})
+	}
+}
+
+func TestExecutorRoutineContextMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "test-api", response: []byte(`{"data": "value"}`)})
+
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(reg, synth, filepath.Join(dir, "reports"))
+	routine := &Routine{
+		Name:      "missing-context",
+		Dir:       dir,
+		Report:    ReportConfig{Title: "Report", GenerateCharts: boolPtr(false)},
+		Synthesis: SynthesisConfig{System: "You are an analyst."},
+		Context:   ContextConfig{File: "nope.md"},
+		Sources:   []SourceConfig{{Service: "test-api", Tool: "fetch"}},
+	}
+	if _, err := exec.Run(context.Background(), routine); err != nil {
+		t.Fatalf("Run should proceed without the context file: %v", err)
+	}
+	if synth.systemPrompt != "You are an analyst." {
+		t.Errorf("expected unmodified system prompt, got %q", synth.systemPrompt)
+	}
+}
+
+func TestExecutorProfileInjection(t *testing.T) {
+	dir := t.TempDir()
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "test-api", response: []byte(`{"data": "value"}`)})
+	prof := &profile.Profile{Raw: map[string]interface{}{
+		"name":        "Trivyn",
+		"interests":   []interface{}{"geospatial"},
+		"naics_codes": []interface{}{"541370"},
+	}}
+
+	tests := []struct {
+		name    string
+		system  string
+		profile ProfileConfig
+		want    []string
+		notWant []string
+	}{
+		{"default", "For {{profile.name}}.", ProfileConfig{}, []string{"For Trivyn."}, []string{"## About the Reader"}},
+		{"include", "Analyst.", ProfileConfig{Include: boolPtr(true)}, []string{"## About the Reader", "name: Trivyn", "naics codes: 541370"}, nil},
+		{"fields", "For {{profile.name}}.", ProfileConfig{Include: boolPtr(true), Fields: []string{"interests"}}, []string{"interests: geospatial", "For {{profile.name}}."}, []string{"Trivyn", "541370"}},
+		{"exclude", "Analyst.", ProfileConfig{Include: boolPtr(false)}, nil, []string{"About the Reader", "Trivyn"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synth := &capturingSynthesizer{}
+			exec := NewExecutor(reg, synth, filepath.Join(dir, "reports"))
+			exec.SetProfile(prof)
+			routine := &Routine{
+				Name:      "profiled",
+				Report:    ReportConfig{Title: "Report", GenerateCharts: boolPtr(false)},
+				Synthesis: SynthesisConfig{System: tt.system, Profile: tt.profile},
+				Sources:   []SourceConfig{{Service: "test-api", Tool: "fetch"}},
+			}
+			if _, err := exec.Run(context.Background(), routine); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(synth.systemPrompt, want) {
+					t.Errorf("system prompt missing %q:\n%s", want, synth.systemPrompt)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(synth.systemPrompt, notWant) {
+					t.Errorf("system prompt should not contain %q:\n%s", notWant, synth.systemPrompt)
+				}
+			}
+		})
+	}
+}
+
 func TestExecutorNoCompareWith(t *testing.T) {
 	dir := t.TempDir()
 	reportsDir := filepath.Join(dir, "reports")
@@ -886,3 +1292,514 @@ func TestExecutorSynthesisFailurePreservesRawData(t *testing.T) {
 		t.Errorf("expected 1 raw result file, got %d", len(dataEntries))
 	}
 }
+
+func TestExecutorRecordsSynthesisFallback(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "test-api", response: []byte(`{"ok": true}`)})
+
+	synth := synthesis.NewFailoverSynthesizer([]string{"local/llama3", "cloud/gpt"},
+		[]synthesis.Synthesizer{&failingSynthesizer{}, &capturingSynthesizer{}})
+	exec := NewExecutor(reg, synth, reportsDir)
+
+	routine := &Routine{
+		Name:    "failover",
+		Report:  ReportConfig{Title: "Brief"},
+		Sources: []SourceConfig{{Service: "test-api", Tool: "fetch"}},
+	}
+	report, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	fb, ok := reports.ParseFallback(report.Markdown)
+	if !ok || fb.Provider != "cloud/gpt" || len(fb.Failed) != 1 || fb.Failed[0] != "local/llama3" {
+		t.Errorf("ParseFallback = %+v, %v\n%s", fb, ok, report.Markdown)
+	}
+}
+
+func TestExecutorSavesHTML(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "test-api", response: []byte(`{"ok": true}`)})
+	exec := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir)
+
+	routine := &Routine{
+		Name:    "html",
+		Report:  ReportConfig{Title: "Brief", HTML: true},
+		Sources: []SourceConfig{{Service: "test-api", Tool: "fetch"}},
+	}
+	report, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(report.Dir, reports.HTMLFile))
+	if err != nil {
+		t.Fatalf("report.html not saved: %v", err)
+	}
+	if !strings.Contains(string(data), "<!DOCTYPE html>") {
+		t.Errorf("report.html = %s", data)
+	}
+}
+
+type unreadySynthesizer struct{ failingSynthesizer }
+
+func (u *unreadySynthesizer) Check(context.Context) error {
+	return fmt.Errorf("Ollama is not reachable at http://localhost:11434")
+}
+
+func TestExecutorUnreadyProviderFailsBeforeFetching(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "test-api", response: []byte(`{"ok": true}`)})
+
+	exec := NewExecutor(reg, &unreadySynthesizer{}, reportsDir)
+
+	routine := &Routine{
+		Name:    "no-llm",
+		Report:  ReportConfig{Title: "Brief"},
+		Sources: []SourceConfig{{Service: "test-api", Tool: "fetch"}},
+	}
+	_, err := exec.Run(context.Background(), routine)
+	if err == nil {
+		t.Fatal("expected provider check error")
+	}
+	for _, want := range []string{"LLM provider not ready", "not reachable", "gd configure", "llm: none"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error missing %q: %v", want, err)
+		}
+	}
+	// Raw results are saved before synthesis, so no directory means no
+	// source was collected.
+	if _, err := os.Stat(reportsDir); !os.IsNotExist(err) {
+		t.Error("expected no report directory")
+	}
+}
+
+func TestExecutorAppendSamples(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "quotes", response: []byte(`{"price": 42}`)})
+
+	exec := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir)
+	routine := &Routine{
+		Name:    "prices",
+		Report:  ReportConfig{Title: "Prices", Samples: "append"},
+		Sources: []SourceConfig{{Service: "quotes", Tool: "latest"}},
+	}
+
+	first, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	second, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+
+	if second.Dir != first.Dir {
+		t.Errorf("second sample wrote to %s, want existing %s", second.Dir, first.Dir)
+	}
+	if !strings.Contains(second.Markdown, "## Sample at ") {
+		t.Error("expected sample heading in appended report")
+	}
+	if strings.Count(second.Markdown, "# Prices") != 1 {
+		t.Errorf("expected the sample's title dropped, got:\n%s", second.Markdown)
+	}
+	if !strings.Contains(second.Markdown, "\n## quotes — latest") || !strings.Contains(second.Markdown, "\n### quotes — latest") {
+		t.Errorf("expected both samples, the second nested under its heading, got:\n%s", second.Markdown)
+	}
+	if len(second.Sources) != 2 {
+		t.Errorf("expected raw data from both samples, got %d files", len(second.Sources))
+	}
+
+	all, _ := reports.List(reportsDir)
+	if len(all) != 1 {
+		t.Errorf("expected 1 report on disk, got %d", len(all))
+	}
+}
+
+type paramCapturingService struct {
+	name   string
+	params map[string]string
+}
+
+func (p *paramCapturingService) Name() string { return p.name }
+func (p *paramCapturingService) Execute(_ context.Context, tool string, params map[string]string) (*services.Result, error) {
+	p.params = params
+	return &services.Result{Service: p.name, Tool: tool, Data: []byte(`{}`), Timestamp: time.Now()}, nil
+}
+
+func TestExecutorCatchUpSummary(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+
+	svc := &paramCapturingService{name: "feed"}
+	reg := services.NewRegistry()
+	reg.Register(svc)
+
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(reg, synth, reportsDir)
+
+	routine := &Routine{
+		Name:        "brief",
+		Report:      ReportConfig{Title: "Brief", GenerateCharts: boolPtr(false)},
+		Sources:     []SourceConfig{{Service: "feed", Tool: "posts", Params: map[string]string{"since": "{{since}}"}}},
+		MissedSince: "2025-01-10",
+	}
+
+	if _, err := exec.Run(context.Background(), routine); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if svc.params["since"] != "2025-01-10" {
+		t.Errorf("since param = %q, want %q", svc.params["since"], "2025-01-10")
+	}
+	if !strings.Contains(synth.systemPrompt, "Catch-Up Report") {
+		t.Error("expected catch-up instructions in synthesis prompt")
+	}
+}
+
+func TestExecutorStashAndLastValue(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+
+	svc := &mockService{name: "sam", response: []byte(`{"totalRecords": 12, "results": [{}, {}]}`)}
+	reg := services.NewRegistry()
+	reg.Register(svc)
+
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(reg, synth, reportsDir)
+	exec.SetValueStore(values.NewStore(filepath.Join(dir, "routine-values.json")))
+
+	routine := &Routine{
+		Name:      "contracts",
+		Report:    ReportConfig{Title: "Contracts", GenerateCharts: boolPtr(false)},
+		Synthesis: SynthesisConfig{System: `Previous count: {{lastValue "contract_count"}}.`},
+		Sources:   []SourceConfig{{Service: "sam", Tool: "search"}},
+		Stash: []StashConfig{
+			{Key: "contract_count", Service: "sam", Path: "totalRecords"},
+			{Key: "page_size", Service: "sam", Path: "results"},
+		},
+	}
+
+	if _, err := exec.Run(context.Background(), routine); err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+	if !strings.Contains(synth.systemPrompt, "Previous count: .") {
+		t.Errorf("first run should see empty lastValue, got prompt: %q", synth.systemPrompt)
+	}
+
+	svc.response = []byte(`{"totalRecords": 15, "results": []}`)
+	if _, err := exec.Run(context.Background(), routine); err != nil {
+		t.Fatalf("second Run: %v", err)
+	}
+	if !strings.Contains(synth.systemPrompt, "Previous count: 12.") {
+		t.Errorf("second run should see stashed value, got prompt: %q", synth.systemPrompt)
+	}
+
+	vals, _ := values.NewStore(filepath.Join(dir, "routine-values.json")).Load("contracts")
+	if vals["contract_count"] != "15" || vals["page_size"] != "0" {
+		t.Errorf("stored values = %v", vals)
+	}
+}
+
+func TestTemplateFuncsOnlyForLastValueRoutines(t *testing.T) {
+	exec := NewExecutor(services.NewRegistry(), nil, t.TempDir())
+	exec.SetValueStore(values.NewStore(filepath.Join(t.TempDir(), "routine-values.json")))
+
+	plain := &Routine{
+		Name:    "plain",
+		Report:  ReportConfig{Title: "Plain"},
+		Sources: []SourceConfig{{Service: "sam", Tool: "search", Params: map[string]string{"q": "{{.Interests}}"}}},
+	}
+	if funcs := exec.templateFuncs(plain); funcs != nil {
+		t.Errorf("expected nil funcs for a routine without lastValue, got %v", funcs)
+	}
+
+	tests := map[string]*Routine{
+		"stash":  {Name: "a", Stash: []StashConfig{{Key: "n", Service: "sam", Path: "total"}}},
+		"params": {Name: "b", Sources: []SourceConfig{{Service: "sam", Params: map[string]string{"after": `{{lastValue "n"}}`}}}},
+		"title":  {Name: "c", Report: ReportConfig{Title: `Since {{lastValue "n"}}`}},
+	}
+	for name, routine := range tests {
+		if funcs := exec.templateFuncs(routine); funcs["lastValue"] == nil {
+			t.Errorf("%s: expected lastValue helper", name)
+		}
+	}
+}
+
+func TestStashValuesFromHeader(t *testing.T) {
+	routine := &Routine{
+		Sources: []SourceConfig{{Service: "api", Tool: "list"}},
+		Stash: []StashConfig{
+			{Key: "total", Service: "api", Header: "x-total-count"},
+			{Key: "missing", Service: "api", Header: "X-Missing"},
+		},
+	}
+	results := []*services.Result{{
+		Service: "api", Tool: "list", Data: []byte(`[]`),
+		Headers: map[string]string{"X-Total-Count": "42"},
+	}}
+
+	vals := stashValues(routine, results)
+	if vals["total"] != "42" {
+		t.Errorf("total = %q, want 42", vals["total"])
+	}
+	if _, ok := vals["missing"]; ok {
+		t.Error("absent header should not be stashed")
+	}
+}
+
+func TestExecutorResynthesize(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+
+	svc := &mockService{
+		name:     "test-api",
+		response: []byte(`{"results": [{"title": "Finding A"}]}`),
+		headers:  map[string]string{"X-Total-Count": "42"},
+	}
+	reg := services.NewRegistry()
+	reg.Register(svc)
+
+	routine := &Routine{
+		Name:   "resynth",
+		Report: ReportConfig{Title: "Resynth Report", GenerateCharts: boolPtr(false)},
+		Sources: []SourceConfig{
+			{Service: "test-api", Tool: "search", ContextLabel: "Search Results"},
+		},
+	}
+
+	first, err := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir).Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// Resynthesis must not query services: use an empty registry.
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(services.NewRegistry(), synth, reportsDir)
+	routine.Synthesis.System = "Edited prompt."
+
+	report, err := exec.Resynthesize(context.Background(), routine, first.Dir)
+	if err != nil {
+		t.Fatalf("Resynthesize: %v", err)
+	}
+	if report.Dir != first.Dir {
+		t.Errorf("report dir = %s, want %s", report.Dir, first.Dir)
+	}
+	if synth.systemPrompt != "Edited prompt." {
+		t.Errorf("system prompt = %q, want edited prompt", synth.systemPrompt)
+	}
+	if len(synth.results) != 1 {
+		t.Fatalf("expected 1 stored result, got %d", len(synth.results))
+	}
+	r := synth.results[0]
+	if r.Service != "test-api" || r.Tool != "search" || r.ContextLabel != "Search Results" {
+		t.Errorf("result not mapped to source: %+v", r)
+	}
+	if !strings.Contains(string(r.Data), "Finding A") {
+		t.Errorf("unexpected result data: %s", r.Data)
+	}
+	if r.Headers["X-Total-Count"] != "42" {
+		t.Errorf("captured headers not restored: %v", r.Headers)
+	}
+
+	all, _ := reports.List(reportsDir)
+	if len(all) != 1 {
+		t.Errorf("expected 1 report on disk, got %d", len(all))
+	}
+}
+
+func TestStoredResultsOrder(t *testing.T) {
+	routine := &Routine{Sources: make([]SourceConfig, 11)}
+	for i := range routine.Sources {
+		routine.Sources[i] = SourceConfig{Service: fmt.Sprintf("svc%d", i), Tool: "t"}
+	}
+	raw := map[string][]byte{
+		"10-svc10-t":         []byte(`{}`),
+		"2-svc2-t":           []byte(`{}`),
+		"t090000-1-svc1-t":   []byte(`{}`),
+		"t080000-10-svc10-t": []byte(`{}`),
+	}
+
+	results, _ := storedResults(context.Background(), routine, raw, nil)
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Service)
+	}
+	want := []string{"svc2", "svc10", "svc10", "svc1"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestOrderBySections(t *testing.T) {
+	results := []*services.Result{
+		{Service: "markets", Tool: "quotes", ContextLabel: "Markets"},
+		{Service: "misc", Tool: "fetch"},
+		{Service: "news", Tool: "headlines", ContextLabel: "News"},
+		{Service: "nws", Tool: "forecast", ContextLabel: "Weather"},
+	}
+
+	got := orderBySections(results, []string{"weather", "News", "markets"})
+	var labels []string
+	for _, r := range got {
+		labels = append(labels, r.Service)
+	}
+	want := "nws,news,markets,misc"
+	if strings.Join(labels, ",") != want {
+		t.Errorf("order = %v, want %s", labels, want)
+	}
+	if results[0].Service != "markets" {
+		t.Error("orderBySections must not modify its input")
+	}
+}
+
+func TestExecutorSectionOrder(t *testing.T) {
+	dir := t.TempDir()
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "api", response: []byte(`{}`)})
+
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(reg, synth, filepath.Join(dir, "reports"))
+	routine := &Routine{
+		Name: "ordered",
+		Report: ReportConfig{
+			Title:          "Ordered",
+			GenerateCharts: boolPtr(false),
+			Sections:       []string{"Weather", "News"},
+		},
+		Sources: []SourceConfig{
+			{Service: "api", Tool: "news", ContextLabel: "News"},
+			{Service: "api", Tool: "weather", ContextLabel: "Weather"},
+		},
+	}
+
+	if _, err := exec.Run(context.Background(), routine); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if synth.results[0].ContextLabel != "Weather" {
+		t.Errorf("first synthesis input = %q, want Weather", synth.results[0].ContextLabel)
+	}
+	if !strings.Contains(synth.systemPrompt, "Required Section Order") ||
+		!strings.Contains(synth.systemPrompt, "1. Weather\n2. News") {
+		t.Errorf("expected section order instruction in prompt, got:\n%s", synth.systemPrompt)
+	}
+}
+
+func TestExecutorPassesSourceTags(t *testing.T) {
+	dir := t.TempDir()
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "api", response: []byte(`{}`)})
+
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(reg, synth, filepath.Join(dir, "reports"))
+	routine := &Routine{
+		Name:   "tagged",
+		Report: ReportConfig{Title: "Tagged", GenerateCharts: boolPtr(false)},
+		Sources: []SourceConfig{
+			{Service: "api", Tool: "alerts", ContextLabel: "Alerts", Tags: []string{"weather", "critical"}},
+			{Service: "missing", Tool: "search", ContextLabel: "Missing", Tags: []string{"news"}},
+			{Service: "api", Tool: "news", ContextLabel: "News"},
+		},
+	}
+
+	if _, err := exec.Run(context.Background(), routine); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(synth.results) != 3 {
+		t.Fatalf("expected 3 synthesis inputs, got %d", len(synth.results))
+	}
+	if got := synth.results[0].Tags; len(got) != 2 || got[0] != "weather" || got[1] != "critical" {
+		t.Errorf("tags = %v, want [weather critical]", got)
+	}
+	if got := synth.results[1].Tags; len(got) != 1 || got[0] != "news" {
+		t.Errorf("failed source tags = %v, want [news]", got)
+	}
+	if got := synth.results[2].Tags; len(got) != 0 {
+		t.Errorf("untagged source tags = %v, want none", got)
+	}
+}
+
+func TestExecutorHeadlinesStyle(t *testing.T) {
+	dir := t.TempDir()
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "api", response: []byte(`{}`)})
+
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(reg, synth, filepath.Join(dir, "reports"))
+	routine := &Routine{
+		Name:    "glance",
+		Report:  ReportConfig{Title: "Glance", Style: "headlines", Sections: []string{"News"}},
+		Sources: []SourceConfig{{Service: "api", Tool: "news"}},
+	}
+
+	if _, err := exec.Run(context.Background(), routine); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !strings.Contains(synth.systemPrompt, synthesis.HeadlinesInstruction) {
+		t.Errorf("expected headlines instruction in prompt, got:\n%s", synth.systemPrompt)
+	}
+	if strings.Contains(synth.systemPrompt, chartInstructions) || strings.Contains(synth.systemPrompt, "Required Section Order") {
+		t.Errorf("headlines prompt should have no chart or section instructions, got:\n%s", synth.systemPrompt)
+	}
+}
+
+func TestMarkEmpty(t *testing.T) {
+	tests := []struct {
+		data string
+		err  string
+		want bool
+	}{
+		{"", "", true},
+		{"[]", "", true},
+		{" {} ", "", true},
+		{"null", "", true},
+		{`{"results": []}`, "", false},
+		{`[1]`, "", false},
+		{"<html>", "", false},
+		{"[]", "HTTP 500", false},
+	}
+	for _, tt := range tests {
+		r := &services.Result{Data: []byte(tt.data), Error: tt.err}
+		markEmpty(r)
+		if r.Empty != tt.want {
+			t.Errorf("markEmpty(%q, err=%q) = %v, want %v", tt.data, tt.err, r.Empty, tt.want)
+		}
+	}
+}
+
+func TestExecutorEmptyAfterTransform(t *testing.T) {
+	dir := t.TempDir()
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "api", response: []byte(`{"results": [], "meta": {"page": 1}}`)})
+
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(reg, synth, filepath.Join(dir, "reports"))
+	routine := &Routine{
+		Name:   "quiet",
+		Report: ReportConfig{Title: "Quiet", GenerateCharts: boolPtr(false)},
+		Sources: []SourceConfig{
+			{Service: "api", Tool: "search", Transform: ".results"},
+			{Service: "api", Tool: "raw"},
+		},
+	}
+
+	if _, err := exec.Run(context.Background(), routine); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !synth.results[0].Empty {
+		t.Error("expected transformed empty array to be marked empty")
+	}
+	if synth.results[1].Empty {
+		t.Error("object with fields should not be marked empty without results_path")
+	}
+}
@@ -0,0 +1,108 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fragmentPrefix marks routine files that only exist to be extended.
+// LoadAllRoutines skips them, so an incomplete base is not reported as an
+// invalid routine and never runs on its own.
+const fragmentPrefix = "_"
+
+// loadRoutineNode reads the routine file at path and resolves its extends
+// chain, returning one YAML mapping with each routine's keys laid over its
+// base's. chain holds the names already being resolved, to reject cycles.
+func loadRoutineNode(path string, chain []string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading routine: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing routine: %w", err)
+	}
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	if len(doc.Content) > 0 {
+		node = doc.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parsing routine: not a YAML mapping")
+	}
+
+	base := mappingValue(node, "extends")
+	if base == nil {
+		return node, nil
+	}
+	name := base.Value
+	if base.Kind != yaml.ScalarNode || name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return nil, fmt.Errorf("invalid extends %q (must name a routine in the same directory)", name)
+	}
+	for _, seen := range chain {
+		if seen == name {
+			return nil, fmt.Errorf("extends cycle: %s -> %s", strings.Join(chain, " -> "), name)
+		}
+	}
+
+	basePath, err := findRoutineFile(filepath.Dir(path), name)
+	if err != nil {
+		return nil, err
+	}
+	baseNode, err := loadRoutineNode(basePath, append(chain, name))
+	if err != nil {
+		return nil, fmt.Errorf("extends %q: %w", name, err)
+	}
+	return mergeNodes(baseNode, node), nil
+}
+
+// findRoutineFile returns the path of the routine named name in dir.
+func findRoutineFile(dir, name string) (string, error) {
+	for _, ext := range []string{".yaml", ".yml"} {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("extends %q: no routine %s.yaml in %s", name, name, dir)
+}
+
+// mergeNodes lays over on top of base. Mappings merge key by key, recursing
+// into nested mappings, so a routine can override report.title and keep the
+// rest of its base's report settings. Anything else — scalars and lists such
+// as sources — is replaced whole.
+func mergeNodes(base, over *yaml.Node) *yaml.Node {
+	if base.Kind != yaml.MappingNode || over.Kind != yaml.MappingNode {
+		return over
+	}
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: base.Tag, Content: append([]*yaml.Node(nil), base.Content...)}
+	// Mapping content alternates key and value nodes.
+	for i := 0; i+1 < len(over.Content); i += 2 {
+		key, value := over.Content[i], over.Content[i+1]
+		found := false
+		for j := 0; j+1 < len(merged.Content); j += 2 {
+			if merged.Content[j].Value == key.Value {
+				merged.Content[j+1] = mergeNodes(merged.Content[j+1], value)
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged.Content = append(merged.Content, key, value)
+		}
+	}
+	return merged
+}
+
+// mappingValue returns the value for key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const baseRoutine = `schedule: "06:00"
+llm: local/qwen
+report:
+  title: "Base Brief"
+  max_length: 1500
+synthesis:
+  system: "Be brief."
+sources:
+  - service: nws
+    tool: forecast
+    params: { zone: "AKZ101" }
+`
+
+func writeRoutines(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadRoutineExtends(t *testing.T) {
+	dir := writeRoutines(t, map[string]string{
+		"_base.yaml": baseRoutine,
+		"fairbanks.yaml": `extends: _base
+report:
+  title: "Fairbanks Brief"
+sources:
+  - service: nws
+    tool: forecast
+    params: { zone: "AKZ222" }
+`,
+	})
+
+	r, err := LoadRoutine(filepath.Join(dir, "fairbanks.yaml"))
+	if err != nil {
+		t.Fatalf("LoadRoutine: %v", err)
+	}
+	if r.Name != "fairbanks" || r.Extends != "_base" {
+		t.Errorf("name = %q, extends = %q", r.Name, r.Extends)
+	}
+	if r.Report.Title != "Fairbanks Brief" {
+		t.Errorf("title = %q, want override", r.Report.Title)
+	}
+	// Nested mappings merge: max_length comes from the base.
+	if r.Report.MaxLength != 1500 {
+		t.Errorf("max_length = %d, want inherited 1500", r.Report.MaxLength)
+	}
+	if r.Schedule != "06:00" || r.LLM != "local/qwen" || r.Synthesis.System != "Be brief." {
+		t.Errorf("expected schedule, llm, and synthesis inherited, got %q %q %q", r.Schedule, r.LLM, r.Synthesis.System)
+	}
+	// Lists are replaced whole.
+	if len(r.Sources) != 1 || r.Sources[0].Params["zone"] != "AKZ222" {
+		t.Errorf("sources = %+v, want the override", r.Sources)
+	}
+}
+
+func TestLoadRoutineExtendsChain(t *testing.T) {
+	dir := writeRoutines(t, map[string]string{
+		"_base.yaml":  baseRoutine,
+		"_north.yaml": "extends: _base\nschedule: \"07:00\"\n",
+		"nome.yaml":   "extends: _north\nreport:\n  title: \"Nome\"\n",
+	})
+
+	r, err := LoadRoutine(filepath.Join(dir, "nome.yaml"))
+	if err != nil {
+		t.Fatalf("LoadRoutine: %v", err)
+	}
+	if r.Schedule != "07:00" || r.Report.Title != "Nome" || len(r.Sources) != 1 {
+		t.Errorf("unexpected merge: schedule %q, title %q, %d sources", r.Schedule, r.Report.Title, len(r.Sources))
+	}
+}
+
+func TestLoadRoutineExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		load  string
+		want  string
+	}{
+		{
+			"cycle",
+			map[string]string{"a.yaml": "extends: b\n", "b.yaml": "extends: a\n"},
+			"a.yaml",
+			"extends cycle: a -> b -> a",
+		},
+		{
+			"self",
+			map[string]string{"a.yaml": "extends: a\n"},
+			"a.yaml",
+			"extends cycle: a -> a",
+		},
+		{
+			"missing base",
+			map[string]string{"a.yaml": "extends: nope\n"},
+			"a.yaml",
+			`extends "nope": no routine nope.yaml`,
+		},
+		{
+			"path",
+			map[string]string{"a.yaml": "extends: ../other\n"},
+			"a.yaml",
+			"invalid extends",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeRoutines(t, tt.files)
+			_, err := LoadRoutine(filepath.Join(dir, tt.load))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAllRoutinesSkipsFragments(t *testing.T) {
+	dir := writeRoutines(t, map[string]string{
+		"_base.yaml": "sources:\n  - service: nws\n    tool: forecast\n",
+		"daily.yaml": "extends: _base\nreport:\n  title: \"Daily\"\n",
+	})
+
+	var warnings strings.Builder
+	routines, err := LoadAllRoutines(dir, &warnings)
+	if err != nil {
+		t.Fatalf("LoadAllRoutines: %v", err)
+	}
+	if len(routines) != 1 || routines[0].Name != "daily" {
+		t.Errorf("expected only daily, got %d routines", len(routines))
+	}
+	if warnings.Len() != 0 {
+		t.Errorf("fragment should not warn, got %q", warnings.String())
+	}
+}
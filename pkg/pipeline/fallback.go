@@ -0,0 +1,30 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/jcadam/burrow/pkg/synthesis"
+)
+
+// fallbackContext returns a context under which each synthesis failover
+// is warned about and collected in fb, so the report can record which
+// provider wrote it.
+func fallbackContext(ctx context.Context, fb *reports.Fallback) context.Context {
+	return synthesis.WithFallback(ctx, func(failed string, err error, next string) {
+		fmt.Fprintf(os.Stderr, "warning: LLM provider %s failed: %v (falling back to %s)\n", failed, err, next)
+		fb.Failed = append(fb.Failed, failed)
+		fb.Provider = next
+	})
+}
+
+// recordFallback notes fb under the report's title when a fallback
+// provider wrote the report.
+func recordFallback(markdown string, fb reports.Fallback) string {
+	if fb.Provider == "" {
+		return markdown
+	}
+	return reports.InsertFallback(markdown, fb)
+}
@@ -0,0 +1,122 @@
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+// sourceGroups returns the group label of each of the routine's sources.
+func sourceGroups(routine *Routine) []string {
+	groups := make([]string, len(routine.Sources))
+	for i, src := range routine.Sources {
+		groups[i] = src.Group
+	}
+	return groups
+}
+
+// groupResults merges the successful results of sources sharing a group
+// label into one logical result for synthesis, placed where the group's
+// first member appears and labelled with the group name. groups[i] is the
+// label for results[i]; empty labels are never merged. Failed or empty
+// members stay separate so synthesis still notes them. JSON members are
+// combined into a JSON array; anything else is concatenated.
+func groupResults(results []*services.Result, groups []string) []*services.Result {
+	// groupOf returns the group results[i] merges into, or "" if it stands alone.
+	groupOf := func(i int) string {
+		r := results[i]
+		if i >= len(groups) || r == nil || r.Error != "" || len(r.Data) == 0 {
+			return ""
+		}
+		return groups[i]
+	}
+
+	members := make(map[string][]*services.Result)
+	for i, r := range results {
+		if g := groupOf(i); g != "" {
+			members[g] = append(members[g], r)
+		}
+	}
+	if len(members) == 0 {
+		return results
+	}
+
+	out := make([]*services.Result, 0, len(results))
+	for i, r := range results {
+		g := groupOf(i)
+		if g == "" {
+			out = append(out, r)
+			continue
+		}
+		if rs := members[g]; rs != nil {
+			out = append(out, mergeResults(g, rs))
+			delete(members, g)
+		}
+	}
+	return out
+}
+
+// mergeResults combines group members into a single result. The merged
+// result carries every member's tags, in first-seen order.
+func mergeResults(group string, rs []*services.Result) *services.Result {
+	first := rs[0]
+	merged := &services.Result{
+		Service:      first.Service,
+		Tool:         first.Tool,
+		URL:          first.URL,
+		Timestamp:    first.Timestamp,
+		ContextLabel: group,
+		Empty:        true,
+	}
+
+	allJSON := true
+	var notes []string
+	for _, r := range rs {
+		merged.Empty = merged.Empty && r.Empty
+		if !json.Valid(r.Data) {
+			allJSON = false
+		}
+		if r.Timestamp.After(merged.Timestamp) {
+			merged.Timestamp = r.Timestamp
+		}
+		for k, v := range r.Headers {
+			if merged.Headers == nil {
+				merged.Headers = make(map[string]string)
+			}
+			if _, ok := merged.Headers[k]; !ok {
+				merged.Headers[k] = v
+			}
+		}
+		if r.Note != "" {
+			notes = append(notes, resultLabel(r)+": "+r.Note)
+		}
+		for _, tag := range r.Tags {
+			if !slices.Contains(merged.Tags, tag) {
+				merged.Tags = append(merged.Tags, tag)
+			}
+		}
+	}
+
+	parts := make([][]byte, len(rs))
+	for i, r := range rs {
+		parts[i] = bytes.TrimSpace(r.Data)
+	}
+	if allJSON {
+		merged.Data = append(append([]byte("[\n"), bytes.Join(parts, []byte(",\n"))...), "\n]"...)
+	} else {
+		merged.Data = bytes.Join(parts, []byte("\n\n"))
+	}
+	merged.Note = strings.Join(notes, " ")
+	return merged
+}
+
+// resultLabel names a group member in the merged result's note.
+func resultLabel(r *services.Result) string {
+	if r.ContextLabel != "" {
+		return r.ContextLabel
+	}
+	return r.Service + "/" + r.Tool
+}
@@ -0,0 +1,129 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+func TestGroupResults(t *testing.T) {
+	now := time.Now()
+	results := []*services.Result{
+		{Service: "api", Tool: "search", Data: []byte(`{"q": "a"}`), Timestamp: now},
+		{Service: "other", Tool: "fetch", Data: []byte(`{"x": 1}`), Timestamp: now},
+		{Service: "api", Tool: "search", Data: []byte(`{"q": "b"}`), Timestamp: now},
+		{Service: "api", Tool: "search", Error: "timeout", Timestamp: now},
+	}
+	groups := []string{"API Searches", "", "API Searches", "API Searches"}
+
+	got := groupResults(results, groups)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 results (group, ungrouped, failed member), got %d", len(got))
+	}
+
+	merged := got[0]
+	if merged.ContextLabel != "API Searches" {
+		t.Errorf("merged label = %q, want group name", merged.ContextLabel)
+	}
+	var items []map[string]string
+	if err := json.Unmarshal(merged.Data, &items); err != nil {
+		t.Fatalf("merged data is not a JSON array: %v\n%s", err, merged.Data)
+	}
+	if len(items) != 2 || items[0]["q"] != "a" || items[1]["q"] != "b" {
+		t.Errorf("unexpected merged items: %v", items)
+	}
+
+	if got[1].Service != "other" {
+		t.Errorf("ungrouped result moved: got %s at position 1", got[1].Service)
+	}
+	if got[2].Error != "timeout" {
+		t.Errorf("failed member should stay separate, got %+v", got[2])
+	}
+}
+
+func TestGroupResultsNonJSON(t *testing.T) {
+	results := []*services.Result{
+		{Service: "feed", Tool: "a", Data: []byte("first\n")},
+		{Service: "feed", Tool: "b", Data: []byte("second")},
+	}
+	got := groupResults(results, []string{"Feeds", "Feeds"})
+	if len(got) != 1 {
+		t.Fatalf("expected 1 merged result, got %d", len(got))
+	}
+	if string(got[0].Data) != "first\n\nsecond" {
+		t.Errorf("merged data = %q", got[0].Data)
+	}
+}
+
+func TestGroupResultsMergesTags(t *testing.T) {
+	results := []*services.Result{
+		{Service: "feed", Tool: "a", Data: []byte(`[]`), Tags: []string{"news", "critical"}},
+		{Service: "feed", Tool: "b", Data: []byte(`[]`), Tags: []string{"critical", "local"}},
+	}
+	got := groupResults(results, []string{"Feeds", "Feeds"})
+	if len(got) != 1 {
+		t.Fatalf("expected 1 merged result, got %d", len(got))
+	}
+	if want := []string{"news", "critical", "local"}; !slices.Equal(got[0].Tags, want) {
+		t.Errorf("merged tags = %v, want %v", got[0].Tags, want)
+	}
+}
+
+func TestGroupResultsMergesNotes(t *testing.T) {
+	results := []*services.Result{
+		{Service: "feed", Tool: "a", Data: []byte(`[]`), ContextLabel: "Local", Note: "Showing first 5 of 40 items."},
+		{Service: "feed", Tool: "b", Data: []byte(`[]`)},
+		{Service: "feed", Tool: "c", Data: []byte(`[]`), Note: "Showing top 5 of 9 items."},
+	}
+	got := groupResults(results, []string{"Feeds", "Feeds", "Feeds"})
+	want := "Local: Showing first 5 of 40 items. feed/c: Showing top 5 of 9 items."
+	if got[0].Note != want {
+		t.Errorf("merged note = %q, want %q", got[0].Note, want)
+	}
+}
+
+func TestGroupResultsNoGroups(t *testing.T) {
+	results := []*services.Result{{Service: "a", Data: []byte(`{}`)}, {Service: "b", Data: []byte(`{}`)}}
+	got := groupResults(results, []string{"", ""})
+	if len(got) != 2 || got[0] != results[0] || got[1] != results[1] {
+		t.Error("results without groups should pass through unchanged")
+	}
+}
+
+func TestExecutorGroupedSources(t *testing.T) {
+	dir := t.TempDir()
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "api", response: []byte(`{"n": 1}`)})
+
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(reg, synth, filepath.Join(dir, "reports"))
+	routine := &Routine{
+		Name:   "grouped",
+		Report: ReportConfig{Title: "Grouped", GenerateCharts: boolPtr(false)},
+		Sources: []SourceConfig{
+			{Service: "api", Tool: "q1", Group: "All Queries"},
+			{Service: "api", Tool: "q2", Group: "All Queries"},
+			{Service: "api", Tool: "q3", Group: "All Queries"},
+		},
+	}
+
+	report, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(synth.results) != 1 {
+		t.Fatalf("expected 1 synthesis input, got %d", len(synth.results))
+	}
+	if synth.results[0].ContextLabel != "All Queries" {
+		t.Errorf("label = %q, want group name", synth.results[0].ContextLabel)
+	}
+	// Raw results stay per source on disk.
+	if len(report.Sources) != 3 {
+		t.Errorf("expected 3 raw result files, got %d", len(report.Sources))
+	}
+}
@@ -0,0 +1,189 @@
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+// parseSortBy splits a source's sort_by into the item field and whether
+// items rank highest first (the default) or lowest first ("field asc").
+func parseSortBy(sortBy string) (field string, desc bool, err error) {
+	parts := strings.Fields(sortBy)
+	switch {
+	case len(parts) == 0:
+		return "", true, nil
+	case len(parts) == 1:
+		return parts[0], true, nil
+	case len(parts) == 2 && (parts[1] == "asc" || parts[1] == "desc"):
+		return parts[0], parts[1] == "desc", nil
+	}
+	return "", false, fmt.Errorf("invalid sort_by %q (must be a field, optionally followed by asc or desc)", sortBy)
+}
+
+// applyMaxItems trims a successful result's items to the source's
+// max_items, ranked by sort_by when set, and notes how many were kept. The
+// items are the array at the source's items_path; without one, at the
+// tool's results_path, unless a transform has reshaped the data, and
+// otherwise the top-level array. Like a transform, it only shapes what
+// synthesis sees; on error the data is kept and a warning is printed.
+func applyMaxItems(src SourceConfig, r *services.Result) {
+	if src.MaxItems <= 0 || r == nil || r.Error != "" || r.Empty || len(r.Data) == 0 {
+		return
+	}
+	path := src.ItemsPath
+	if path == "" && src.Transform == "" && src.JSONPath == "" {
+		path = r.ItemsPath
+	}
+	out, total, err := trimItems(r.Data, path, src.MaxItems, src.SortBy)
+	if err != nil && src.ItemsPath == "" && path != "" {
+		// A results_path may name a count rather than the items.
+		out, total, err = trimItems(r.Data, "", src.MaxItems, src.SortBy)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: max_items for %s/%s: %v (using all items)\n", src.Service, src.Tool, err)
+		return
+	}
+	if out == nil {
+		return
+	}
+	r.Data = out
+	kept := "first"
+	if src.SortBy != "" {
+		kept = "top"
+	}
+	r.Note = fmt.Sprintf("Showing %s %d of %d items.", kept, src.MaxItems, total)
+}
+
+// trimItems keeps the first max items of the array at path in data, after
+// sorting by sortBy. It returns nil data when the array is already short
+// enough, along with the array's length.
+func trimItems(data []byte, path string, max int, sortBy string) ([]byte, int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var root any
+	if err := dec.Decode(&root); err != nil {
+		return nil, 0, fmt.Errorf("response is not JSON")
+	}
+
+	// Walk to the array, remembering how to put the trimmed one back.
+	set := func(v any) { root = v }
+	current := root
+	if path != "" {
+		for _, part := range strings.Split(path, ".") {
+			switch v := current.(type) {
+			case map[string]any:
+				next, ok := v[part]
+				if !ok {
+					return nil, 0, fmt.Errorf("no %q in the response", path)
+				}
+				set = func(x any) { v[part] = x }
+				current = next
+			case []any:
+				idx, err := strconv.Atoi(part)
+				if err != nil || idx < 0 || idx >= len(v) {
+					return nil, 0, fmt.Errorf("no %q in the response", path)
+				}
+				set = func(x any) { v[idx] = x }
+				current = v[idx]
+			default:
+				return nil, 0, fmt.Errorf("no %q in the response", path)
+			}
+		}
+	}
+	items, ok := current.([]any)
+	if !ok {
+		if path == "" {
+			return nil, 0, fmt.Errorf("response is not an array (set items_path)")
+		}
+		return nil, 0, fmt.Errorf("%q is not an array", path)
+	}
+	if len(items) <= max {
+		return nil, len(items), nil
+	}
+
+	if sortBy != "" {
+		field, desc, err := parseSortBy(sortBy)
+		if err != nil {
+			return nil, 0, err
+		}
+		sortItems(items, field, desc)
+	}
+	set(items[:max])
+
+	out, err := json.Marshal(root)
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, len(items), nil
+}
+
+// sortItems orders items by the value at field, a dot path within each
+// item. Numbers compare numerically and everything else as text, so ISO
+// dates sort by time. Items missing the field go last either way.
+func sortItems(items []any, field string, desc bool) {
+	keys := make([]any, len(items))
+	for i, item := range items {
+		keys[i] = itemField(item, field)
+	}
+	idx := make([]int, len(items))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		ka, kb := keys[idx[a]], keys[idx[b]]
+		if ka == nil || kb == nil {
+			return kb == nil && ka != nil
+		}
+		c := compareValues(ka, kb)
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+	sorted := make([]any, len(items))
+	for i, j := range idx {
+		sorted[i] = items[j]
+	}
+	copy(items, sorted)
+}
+
+// itemField returns the value at a dot path within item, or nil.
+func itemField(item any, field string) any {
+	current := item
+	for _, part := range strings.Split(field, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = m[part]
+	}
+	return current
+}
+
+// compareValues orders two field values: numerically when both are
+// numbers, as text otherwise.
+func compareValues(a, b any) int {
+	na, aNum := a.(json.Number)
+	nb, bNum := b.(json.Number)
+	if aNum && bNum {
+		fa, errA := na.Float64()
+		fb, errB := nb.Float64()
+		if errA == nil && errB == nil {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
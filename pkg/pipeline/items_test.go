@@ -0,0 +1,82 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+func TestApplyMaxItemsTopLevel(t *testing.T) {
+	r := &services.Result{Data: []byte(`[{"t":"a"},{"t":"b"},{"t":"c"},{"t":"d"}]`)}
+	applyMaxItems(SourceConfig{Service: "s", Tool: "t", MaxItems: 2}, r)
+
+	if got := string(r.Data); got != `[{"t":"a"},{"t":"b"}]` {
+		t.Errorf("data = %s", got)
+	}
+	if r.Note != "Showing first 2 of 4 items." {
+		t.Errorf("note = %q", r.Note)
+	}
+}
+
+func TestApplyMaxItemsSorted(t *testing.T) {
+	data := `{"total": 5, "data": {"items": [
+		{"id": 1, "score": 3}, {"id": 2, "score": 10}, {"id": 3},
+		{"id": 4, "score": 7.5}, {"id": 5, "score": 1}]}}`
+
+	r := &services.Result{Data: []byte(data), ItemsPath: "data.items"}
+	applyMaxItems(SourceConfig{MaxItems: 3, SortBy: "score"}, r)
+	if got := string(r.Data); got != `{"data":{"items":[{"id":2,"score":10},{"id":4,"score":7.5},{"id":1,"score":3}]},"total":5}` {
+		t.Errorf("descending data = %s", got)
+	}
+	if r.Note != "Showing top 3 of 5 items." {
+		t.Errorf("note = %q", r.Note)
+	}
+
+	// Items missing the field go last, lowest first or not.
+	r = &services.Result{Data: []byte(data)}
+	applyMaxItems(SourceConfig{MaxItems: 2, ItemsPath: "data.items", SortBy: "score asc"}, r)
+	if got := string(r.Data); !strings.Contains(got, `"items":[{"id":5,"score":1},{"id":1,"score":3}]`) {
+		t.Errorf("ascending data = %s", got)
+	}
+}
+
+func TestApplyMaxItemsLeavesShortOrUnsuitableData(t *testing.T) {
+	tests := []struct {
+		name string
+		src  SourceConfig
+		r    *services.Result
+	}{
+		{"short enough", SourceConfig{MaxItems: 5}, &services.Result{Data: []byte(`[1,2,3]`)}},
+		{"not json", SourceConfig{MaxItems: 1}, &services.Result{Data: []byte("a\nb\nc")}},
+		{"not an array", SourceConfig{MaxItems: 1}, &services.Result{Data: []byte(`{"a":[1,2]}`)}},
+		{"missing path", SourceConfig{MaxItems: 1, ItemsPath: "x.y"}, &services.Result{Data: []byte(`{"x":{}}`)}},
+		{"error", SourceConfig{MaxItems: 1}, &services.Result{Data: []byte(`[1,2]`), Error: "boom"}},
+		{"no limit", SourceConfig{}, &services.Result{Data: []byte(`[1,2]`)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := string(tt.r.Data)
+			applyMaxItems(tt.src, tt.r)
+			if string(tt.r.Data) != before || tt.r.Note != "" {
+				t.Errorf("data changed to %s (note %q)", tt.r.Data, tt.r.Note)
+			}
+		})
+	}
+}
+
+func TestApplyMaxItemsCountResultsPath(t *testing.T) {
+	// A results_path naming a count falls back to the top-level array; one
+	// ignored after a transform reshaped the data.
+	r := &services.Result{Data: []byte(`[1,2,3]`), ItemsPath: "0"}
+	applyMaxItems(SourceConfig{MaxItems: 1}, r)
+	if string(r.Data) != `[1]` {
+		t.Errorf("data = %s", r.Data)
+	}
+
+	r = &services.Result{Data: []byte(`[1,2,3]`), ItemsPath: "data.items"}
+	applyMaxItems(SourceConfig{MaxItems: 2, Transform: "."}, r)
+	if string(r.Data) != `[1,2]` {
+		t.Errorf("transformed data = %s", r.Data)
+	}
+}
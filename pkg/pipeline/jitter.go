@@ -0,0 +1,91 @@
+package pipeline
+
+import (
+	"fmt"
+	"math"
+
+	"gopkg.in/yaml.v3"
+)
+
+// JitterConfig spreads a routine's source requests over a window of
+// seconds, so services can't correlate them by timing. In YAML it is
+// either a number, the window's maximum with a uniform spread from 0, or a
+// mapping with min, max, and distribution.
+type JitterConfig struct {
+	Min          int    `yaml:"min,omitempty"`          // seconds every request waits at least
+	Max          int    `yaml:"max,omitempty"`          // seconds no request waits beyond
+	Distribution string `yaml:"distribution,omitempty"` // uniform (default) | exponential: most requests early, a tail out to max
+}
+
+// UnmarshalYAML accepts the plain-number form as well as the mapping.
+func (j *JitterConfig) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*j = JitterConfig{}
+		return node.Decode(&j.Max)
+	}
+	type plain JitterConfig
+	return node.Decode((*plain)(j))
+}
+
+// MarshalYAML writes the plain-number form when it says everything.
+func (j JitterConfig) MarshalYAML() (any, error) {
+	if j.Min == 0 && j.Distribution == "" {
+		return j.Max, nil
+	}
+	type plain JitterConfig
+	return plain(j), nil
+}
+
+// Enabled reports whether requests are delayed at all.
+func (j JitterConfig) Enabled() bool {
+	return j.Max > 0
+}
+
+// String describes the window for debug output, e.g. "0-300s uniform".
+func (j JitterConfig) String() string {
+	dist := j.Distribution
+	if dist == "" {
+		dist = "uniform"
+	}
+	return fmt.Sprintf("%d-%ds %s", j.Min, j.Max, dist)
+}
+
+func (j JitterConfig) validate() error {
+	switch j.Distribution {
+	case "", "uniform", "exponential":
+		// valid
+	default:
+		return fmt.Errorf("invalid jitter.distribution %q (must be uniform or exponential)", j.Distribution)
+	}
+	if j.Min < 0 || j.Max < 0 {
+		return fmt.Errorf("jitter must not be negative")
+	}
+	if j.Min > j.Max {
+		return fmt.Errorf("jitter.min %d is greater than jitter.max %d", j.Min, j.Max)
+	}
+	return nil
+}
+
+// exponentialRate is the exponential distribution's rate in units of the
+// window: a mean of a third of the window puts about 95% of requests in it
+// before truncation.
+const exponentialRate = 3.0
+
+// delay picks a request's jitter in seconds. randFunc returns a uniform
+// integer in [0, n), as rand.IntN does; the exponential distribution maps
+// that draw through the inverse CDF of an exponential truncated to the
+// window, so tests can still control the outcome.
+func (j JitterConfig) delay(randFunc func(n int) int) int {
+	span := j.Max - j.Min
+	if span <= 0 {
+		return j.Min
+	}
+	draw := randFunc(span)
+	if j.Distribution != "exponential" {
+		return j.Min + draw
+	}
+	u := (float64(draw) + 0.5) / float64(span)
+	lambda := exponentialRate / float64(span)
+	x := -math.Log(1-u*(1-math.Exp(-lambda*float64(span)))) / lambda
+	return j.Min + min(span, int(x))
+}
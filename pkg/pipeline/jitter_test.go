@@ -0,0 +1,98 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestJitterConfigYAML(t *testing.T) {
+	var plain struct {
+		Jitter JitterConfig `yaml:"jitter"`
+	}
+	if err := yaml.Unmarshal([]byte("jitter: 300\n"), &plain); err != nil {
+		t.Fatalf("number form: %v", err)
+	}
+	if plain.Jitter != (JitterConfig{Max: 300}) {
+		t.Errorf("number form = %+v, want max 300", plain.Jitter)
+	}
+
+	var mapped struct {
+		Jitter JitterConfig `yaml:"jitter"`
+	}
+	in := "jitter:\n  min: 30\n  max: 600\n  distribution: exponential\n"
+	if err := yaml.Unmarshal([]byte(in), &mapped); err != nil {
+		t.Fatalf("mapping form: %v", err)
+	}
+	if mapped.Jitter != (JitterConfig{Min: 30, Max: 600, Distribution: "exponential"}) {
+		t.Errorf("mapping form = %+v", mapped.Jitter)
+	}
+
+	// A plain window round-trips as a number; anything more as a mapping.
+	out, _ := yaml.Marshal(plain)
+	if string(out) != "jitter: 300\n" {
+		t.Errorf("marshaled plain = %q", out)
+	}
+	out, _ = yaml.Marshal(mapped)
+	if !strings.Contains(string(out), "min: 30") || !strings.Contains(string(out), "distribution: exponential") {
+		t.Errorf("marshaled mapping = %q", out)
+	}
+}
+
+func TestJitterDelay(t *testing.T) {
+	fixed := func(v int) func(int) int { return func(int) int { return v } }
+
+	uniform := JitterConfig{Min: 10, Max: 70}
+	if got := uniform.delay(fixed(0)); got != 10 {
+		t.Errorf("uniform low = %d, want 10", got)
+	}
+	if got := uniform.delay(fixed(59)); got != 69 {
+		t.Errorf("uniform high = %d, want 69", got)
+	}
+	var asked int
+	uniform.delay(func(n int) int { asked = n; return 0 })
+	if asked != 60 {
+		t.Errorf("randFunc asked for %d, want the 60s span", asked)
+	}
+
+	if got := (JitterConfig{Min: 5, Max: 5}).delay(fixed(0)); got != 5 {
+		t.Errorf("empty span = %d, want min", got)
+	}
+
+	// Exponential stays in the window and front-loads: the median draw
+	// lands well before the window's midpoint.
+	exp := JitterConfig{Max: 300, Distribution: "exponential"}
+	for _, draw := range []int{0, 150, 299} {
+		if got := exp.delay(fixed(draw)); got < 0 || got > 300 {
+			t.Errorf("exponential draw %d = %d, outside 0-300", draw, got)
+		}
+	}
+	if got := exp.delay(fixed(150)); got >= 100 {
+		t.Errorf("exponential median = %d, want under 100", got)
+	}
+	if lo, hi := exp.delay(fixed(10)), exp.delay(fixed(290)); lo >= hi {
+		t.Errorf("exponential not increasing: %d then %d", lo, hi)
+	}
+}
+
+func TestValidateJitter(t *testing.T) {
+	tests := []struct {
+		jitter JitterConfig
+		want   string
+	}{
+		{JitterConfig{Max: 60, Distribution: "normal"}, "distribution"},
+		{JitterConfig{Min: -1, Max: 60}, "negative"},
+		{JitterConfig{Min: 90, Max: 60}, "greater than"},
+	}
+	for _, tt := range tests {
+		r := &Routine{
+			Report:  ReportConfig{Title: "T"},
+			Sources: []SourceConfig{{Service: "s", Tool: "t"}},
+			Jitter:  tt.jitter,
+		}
+		if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("jitter %+v: expected %q error, got %v", tt.jitter, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	jsonPathName  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*`)
+	jsonPathIndex = regexp.MustCompile(`^-?\d+$`)
+	jsonPathSlice = regexp.MustCompile(`^(-?\d*):(-?\d*)$`)
+	jsonPathUnion = regexp.MustCompile(`^-?\d+(\s*,\s*-?\d+)+$`)
+)
+
+// jsonPathToJQ compiles a source's jsonpath into the equivalent jq
+// expression, so it runs on the same engine as transform. It supports
+// child names (.name, ['name']), indexes, slices and unions ([0], [1:3],
+// [0,2]), wildcards (.* and [*]), and a final projection that keeps chosen
+// fields of each match: $.results[*].{title: title, url: link.href}.
+// When the path can match more than once the matches are collected into an
+// array, as JSONPath returns them.
+func jsonPathToJQ(path string) (string, error) {
+	rest := strings.TrimSpace(path)
+	if !strings.HasPrefix(rest, "$") {
+		return "", fmt.Errorf("jsonpath %q must start with $", path)
+	}
+	rest = rest[1:]
+
+	var expr strings.Builder
+	// index appends an index operation. At the root it needs a leading
+	// "." so jq reads .[0] rather than the array literal [0].
+	index := func(op string) {
+		if expr.Len() == 0 {
+			expr.WriteString(".")
+		}
+		expr.WriteString(op)
+	}
+	multi := false
+	projection := ""
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			return "", fmt.Errorf("jsonpath %q: recursive descent (..) is not supported (use transform)", path)
+		case strings.HasPrefix(rest, ".*"):
+			index("[]")
+			multi = true
+			rest = rest[2:]
+		case strings.HasPrefix(rest, ".{"):
+			end := strings.Index(rest, "}")
+			if end < 0 {
+				return "", fmt.Errorf("jsonpath %q: unclosed projection", path)
+			}
+			if strings.TrimSpace(rest[end+1:]) != "" {
+				return "", fmt.Errorf("jsonpath %q: a projection must come last", path)
+			}
+			p, err := jsonPathProjection(rest[2:end])
+			if err != nil {
+				return "", fmt.Errorf("jsonpath %q: %w", path, err)
+			}
+			projection = p
+			rest = ""
+		case strings.HasPrefix(rest, "."):
+			name := jsonPathName.FindString(rest[1:])
+			if name == "" {
+				return "", fmt.Errorf("jsonpath %q: expected a field name after %q", path, ".")
+			}
+			expr.WriteString("." + strconv.Quote(name))
+			rest = rest[1+len(name):]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return "", fmt.Errorf("jsonpath %q: unclosed [", path)
+			}
+			sel := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case sel == "*":
+				index("[]")
+				multi = true
+			case len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0]:
+				expr.WriteString("." + strconv.Quote(sel[1:len(sel)-1]))
+			case jsonPathIndex.MatchString(sel):
+				index("[" + sel + "]")
+			case jsonPathSlice.MatchString(sel):
+				index("[" + sel + "][]")
+				multi = true
+			case jsonPathUnion.MatchString(sel):
+				index("[" + sel + "]")
+				multi = true
+			case strings.HasPrefix(sel, "?"):
+				return "", fmt.Errorf("jsonpath %q: filters ([?(...)]) are not supported (use transform)", path)
+			default:
+				return "", fmt.Errorf("jsonpath %q: unsupported selector [%s]", path, sel)
+			}
+		default:
+			return "", fmt.Errorf("jsonpath %q: unexpected %q", path, rest)
+		}
+	}
+
+	out := expr.String()
+	if out == "" {
+		out = "."
+	}
+	if projection != "" {
+		out += " | " + projection
+	}
+	if multi {
+		out = "[" + out + "]"
+	}
+	return out, nil
+}
+
+// jsonPathProjection compiles the fields of a projection, "title: title,
+// url: link.href" or just "title, url", into a jq object constructor.
+func jsonPathProjection(fields string) (string, error) {
+	var parts []string
+	for _, f := range strings.Split(fields, ",") {
+		key, value, found := strings.Cut(f, ":")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !found {
+			value = key
+		}
+		if key == "" || value == "" {
+			return "", fmt.Errorf("empty field in projection {%s}", fields)
+		}
+		var path strings.Builder
+		for _, name := range strings.Split(value, ".") {
+			if jsonPathName.FindString(name) != name {
+				return "", fmt.Errorf("invalid field %q in projection", value)
+			}
+			path.WriteString("." + strconv.Quote(name))
+		}
+		parts = append(parts, strconv.Quote(key)+": "+path.String())
+	}
+	return "{" + strings.Join(parts, ", ") + "}", nil
+}
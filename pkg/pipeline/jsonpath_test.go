@@ -0,0 +1,116 @@
+package pipeline
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestJSONPath(t *testing.T) {
+	data := []byte(`{"meta": {"count": 3}, "results": [
+		{"title": "A", "link": {"href": "https://a.example"}, "noise": "x"},
+		{"title": "B", "link": {"href": "https://b.example"}, "noise": "y"},
+		{"title": "C", "link": {"href": "https://c.example"}, "noise": "z"}]}`)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"$", ""},
+		{"$.meta.count", `3`},
+		{"$['meta']['count']", `3`},
+		{"$.results[0].title", `"A"`},
+		{"$.results[-1].title", `"C"`},
+		{"$.results[*].title", `["A","B","C"]`},
+		{"$.results.*.title", `["A","B","C"]`},
+		{"$.results[0,2].title", `["A","C"]`},
+		{"$.results[1:].title", `["B","C"]`},
+		{"$.results[*].{title: title, url: link.href}", `[{"title":"A","url":"https://a.example"},{"title":"B","url":"https://b.example"},{"title":"C","url":"https://c.example"}]`},
+		{"$.results[0].{title}", `{"title":"A"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			expr, err := jsonPathToJQ(tt.path)
+			if err != nil {
+				t.Fatalf("jsonPathToJQ: %v", err)
+			}
+			got, err := runTransform(context.Background(), expr, data)
+			if err != nil {
+				t.Fatalf("runTransform(%s): %v", expr, err)
+			}
+			if tt.want != "" && string(got) != tt.want {
+				t.Errorf("%s (jq %s) = %s, want %s", tt.path, expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathRootArray(t *testing.T) {
+	data := []byte(`[{"name": "a", "id": 1}, {"name": "b", "id": 2}, {"name": "c", "id": 3}]`)
+
+	tests := []struct {
+		path string
+		jq   string
+		want string
+	}{
+		{"$[0]", `.[0]`, `{"id":1,"name":"a"}`},
+		{"$[-1].name", `.[-1]."name"`, `"c"`},
+		{"$.*", `[.[]]`, `[{"id":1,"name":"a"},{"id":2,"name":"b"},{"id":3,"name":"c"}]`},
+		{"$[*].name", `[.[]."name"]`, `["a","b","c"]`},
+		{"$[0,1].name", `[.[0,1]."name"]`, `["a","b"]`},
+		{"$[1:2]", `[.[1:2][]]`, `[{"id":2,"name":"b"}]`},
+		{"$[*].{name}", `[.[] | {"name": ."name"}]`, `[{"name":"a"},{"name":"b"},{"name":"c"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			expr, err := jsonPathToJQ(tt.path)
+			if err != nil {
+				t.Fatalf("jsonPathToJQ: %v", err)
+			}
+			if expr != tt.jq {
+				t.Errorf("jsonPathToJQ(%s) = %s, want %s", tt.path, expr, tt.jq)
+			}
+			got, err := runTransform(context.Background(), expr, data)
+			if err != nil {
+				t.Fatalf("runTransform(%s): %v", expr, err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("%s (jq %s) = %s, want %s", tt.path, expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	for path, want := range map[string]string{
+		"results[*]":            "must start with $",
+		"$..title":              "recursive descent",
+		"$.results[?(@.x)]":     "filters",
+		"$.results[*].{title":   "unclosed projection",
+		"$.{title}.results":     "must come last",
+		"$.results[*].{a: b c}": "invalid field",
+	} {
+		if _, err := jsonPathToJQ(path); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("jsonPathToJQ(%q) = %v, want error containing %q", path, err, want)
+		}
+	}
+}
+
+func TestValidateJSONPath(t *testing.T) {
+	r := &Routine{
+		Report:  ReportConfig{Title: "T"},
+		Sources: []SourceConfig{{Service: "s", Tool: "t", JSONPath: "$.results[*].{title}"}},
+	}
+	if err := ValidateRoutine(r); err != nil {
+		t.Fatalf("valid jsonpath rejected: %v", err)
+	}
+	r.Sources[0].Transform = ".results"
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "both transform and jsonpath") {
+		t.Errorf("expected transform/jsonpath conflict, got %v", err)
+	}
+	r.Sources[0].Transform = ""
+	r.Sources[0].JSONPath = "$..title"
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "jsonpath") {
+		t.Errorf("expected jsonpath error, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// maxPostProcessPasses caps how often a repeat rule is applied, so a rule
+// whose replacement recreates its own match can't loop forever.
+const maxPostProcessPasses = 10
+
+// applyPostProcess runs a routine's post_process rules over the synthesized
+// markdown. Rules apply in the order listed, each to the previous rule's
+// output, after the synthesizer's own cleanup and before the report's
+// empty-section policy, TL;DR, and coverage line. A rule that fails to
+// compile is skipped with a warning.
+func applyPostProcess(markdown string, rules []PostProcessRule) string {
+	for i, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: post_process[%d]: %v (skipped)\n", i, err)
+			continue
+		}
+		if !rule.Repeat {
+			markdown = re.ReplaceAllString(markdown, rule.Replace)
+			continue
+		}
+		settled := false
+		for pass := 0; pass < maxPostProcessPasses; pass++ {
+			next := re.ReplaceAllString(markdown, rule.Replace)
+			if next == markdown {
+				settled = true
+				break
+			}
+			markdown = next
+		}
+		if !settled {
+			fmt.Fprintf(os.Stderr, "warning: post_process[%d] still changing the report after %d passes (stopped)\n", i, maxPostProcessPasses)
+		}
+	}
+	return markdown
+}
@@ -0,0 +1,101 @@
+package pipeline
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/services"
+	"github.com/jcadam/burrow/pkg/synthesis"
+)
+
+func TestApplyPostProcess(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		rules []PostProcessRule
+		want  string
+	}{
+		{
+			name:  "capture groups",
+			in:    "Released 03/14/2026.",
+			rules: []PostProcessRule{{Pattern: `(\d{2})/(\d{2})/(\d{4})`, Replace: "$3-$1-$2"}},
+			want:  "Released 2026-03-14.",
+		},
+		{
+			name:  "named groups",
+			in:    "See CVE 2026 1234.",
+			rules: []PostProcessRule{{Pattern: `CVE (?P<year>\d{4}) (?P<id>\d+)`, Replace: "CVE-${year}-${id}"}},
+			want:  "See CVE-2026-1234.",
+		},
+		{
+			name: "rules apply in order",
+			in:   "SRE on call",
+			rules: []PostProcessRule{
+				{Pattern: `\bSRE\b`, Replace: "site reliability engineering"},
+				{Pattern: `site reliability engineering`, Replace: "Site Reliability Engineering"},
+			},
+			want: "Site Reliability Engineering on call",
+		},
+		{
+			name:  "remove phrase",
+			in:    "# Brief\n\nIn conclusion, all is well.",
+			rules: []PostProcessRule{{Pattern: `(?i)in conclusion,\s*`, Replace: ""}},
+			want:  "# Brief\n\nall is well.",
+		},
+		{
+			name:  "single pass by default",
+			in:    "a   b",
+			rules: []PostProcessRule{{Pattern: "  ", Replace: " "}},
+			want:  "a  b",
+		},
+		{
+			name:  "repeat until settled",
+			in:    "a   b",
+			rules: []PostProcessRule{{Pattern: "  ", Replace: " ", Repeat: true}},
+			want:  "a b",
+		},
+		{
+			name:  "invalid rule skipped",
+			in:    "keep",
+			rules: []PostProcessRule{{Pattern: "(", Replace: "x"}},
+			want:  "keep",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyPostProcess(tt.in, tt.rules); got != tt.want {
+				t.Errorf("applyPostProcess = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPostProcessPassCap(t *testing.T) {
+	// Each pass adds a character, so the rule never settles.
+	got := applyPostProcess("x", []PostProcessRule{{Pattern: "x$", Replace: "xx", Repeat: true}})
+	if want := strings.Repeat("x", 1+maxPostProcessPasses); got != want {
+		t.Errorf("got %d characters, want %d", len(got), len(want))
+	}
+}
+
+func TestExecutorPostProcess(t *testing.T) {
+	reportsDir := filepath.Join(t.TempDir(), "reports")
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "api", response: []byte(`{"status": "ok"}`)})
+
+	routine := &Routine{
+		Name:        "post",
+		Report:      ReportConfig{Title: "Post Report", GenerateCharts: boolPtr(false)},
+		Sources:     []SourceConfig{{Service: "api", Tool: "fetch"}},
+		PostProcess: []PostProcessRule{{Pattern: `Post Report`, Replace: "Rewritten Report"}},
+	}
+	report, err := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir).Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !strings.Contains(report.Markdown, "# Rewritten Report") || strings.Contains(report.Markdown, "Post Report") {
+		t.Errorf("post_process not applied:\n%s", report.Markdown)
+	}
+}
@@ -0,0 +1,197 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+// saveSourceStatus records how each of the run's sources fared in the
+// report directory, for RetryFailed. An appended sample's sources don't
+// describe the whole report, so appending clears the record instead.
+// Failures only print a warning.
+func saveSourceStatus(reportDir string, routine *Routine, results []*services.Result, appending bool) {
+	var err error
+	if appending {
+		err = reports.ClearSources(reportDir)
+	} else {
+		records := make([]reports.SourceRecord, len(routine.Sources))
+		for i, src := range routine.Sources {
+			var r *services.Result
+			if i < len(results) {
+				r = results[i]
+			}
+			status, msg := resultStatus(r)
+			records[i] = reports.SourceRecord{Index: i, Service: src.Service, Tool: src.Tool, Status: status, Error: msg}
+		}
+		err = reports.SaveSources(reportDir, records)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+}
+
+// RetryFailed re-fetches only the sources that failed in the run behind an
+// existing report, then regenerates the report in place from their fresh
+// results and the stored data of the sources that succeeded, which are not
+// queried again. It relies on the source status Run saves, so reports with
+// appended samples can't be retried. Coverage and source status are
+// updated; the context ledger, stashed values, and drift snapshots are left
+// untouched.
+func (e *Executor) RetryFailed(ctx context.Context, routine *Routine, reportDir string) (*reports.Report, error) {
+	records, err := reports.LoadSources(reportDir)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(reportDir)
+	if records == nil {
+		return nil, fmt.Errorf("no source status recorded in %s (reports with appended samples or from older versions can't be retried)", name)
+	}
+	if len(records) != len(routine.Sources) {
+		return nil, fmt.Errorf("routine %q has changed its sources since %s (run it again instead)", routine.Name, name)
+	}
+	retry := make(map[int]bool)
+	for _, rec := range records {
+		if rec.Index < 0 || rec.Index >= len(routine.Sources) ||
+			routine.Sources[rec.Index].Service != rec.Service || routine.Sources[rec.Index].Tool != rec.Tool {
+			return nil, fmt.Errorf("routine %q has changed its sources since %s (run it again instead)", routine.Name, name)
+		}
+		if rec.Status == reports.SourceError {
+			retry[rec.Index] = true
+		}
+	}
+	if len(retry) == 0 {
+		return nil, fmt.Errorf("no failed sources in %s", name)
+	}
+	if err := e.checkSynthesizer(ctx); err != nil {
+		return nil, err
+	}
+
+	f, err := e.fetchSources(ctx, routine, e.templateFuncs(routine), retry)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replace whatever the failed attempts stored, such as error bodies.
+	raw, err := reports.LoadData(reportDir)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := reports.LoadHeaders(reportDir)
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for key := range raw {
+		if idx, ok := storedIndex(key); ok && retry[idx] {
+			stale = append(stale, key)
+			delete(raw, key)
+		}
+	}
+	if err := reports.RemoveResults(reportDir, stale); err != nil {
+		return nil, err
+	}
+	if err := reports.AddResults(reportDir, f.raw); err != nil {
+		return nil, fmt.Errorf("saving raw results: %w", err)
+	}
+	if err := reports.AddHeaders(reportDir, f.headers); err != nil {
+		return nil, fmt.Errorf("saving raw results: %w", err)
+	}
+	if _, err := saveAttachments(reportDir, f.results, f.attached, false, time.Now()); err != nil {
+		return nil, fmt.Errorf("saving attachments: %w", err)
+	}
+
+	// Sources that weren't retried keep the outcome recorded for them.
+	bySource := make([]*services.Result, len(routine.Sources))
+	for _, rec := range records {
+		if retry[rec.Index] {
+			bySource[rec.Index] = f.results[rec.Index]
+		} else {
+			bySource[rec.Index] = &services.Result{Empty: rec.Status == reports.SourceNoResults}
+		}
+	}
+	saveSourceStatus(reportDir, routine, bySource, false)
+
+	if failed := requiredFailures(routine, bySource); len(failed) > 0 {
+		err := fmt.Errorf("required source failed: %s (raw results saved in %s)", strings.Join(failed, "; "), reportDir)
+		if wait := requiredRetryAfter(routine, bySource); wait > 0 {
+			return nil, &services.RetryAfterError{Err: err, After: wait}
+		}
+		return nil, err
+	}
+
+	results, groups := mergeRetried(ctx, routine, raw, headers, f.results, retry)
+	ctx, usage := meterUsage(ctx)
+	defer usage.save(reportDir, routine)
+	markdown, err := e.synthesizeStored(ctx, routine, reportDir, results, groups)
+	if err != nil {
+		return nil, err
+	}
+	e.emit(routine, Event{Type: EventSynthesisDone})
+
+	coverage := sourceCoverage(routine, bySource)
+	var warnings []string
+	if coverage.Low() {
+		warnings = append(warnings, fmt.Sprintf("low coverage: only %d of %d sources returned data (failed: %s); this report is built on partial data",
+			coverage.Succeeded, coverage.Total, failedSources(routine, bySource)))
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	markdown = reports.InsertCoverage(markdown, coverage)
+	markdown = recordBudget(markdown, f.budget)
+	markdown += driftSection(warnings)
+
+	report, err := reports.Finish(reportDir, routine.Name, markdown)
+	if err != nil {
+		return nil, fmt.Errorf("saving report: %w", err)
+	}
+	saveHTML(routine, report)
+	e.emit(routine, Event{Type: EventReportSaved, Report: report.Dir})
+	return report, nil
+}
+
+// mergeRetried combines the stored results of sources that weren't retried
+// with the fresh results of those that were, in source order, and returns
+// each result's source group label for groupResults.
+func mergeRetried(ctx context.Context, routine *Routine, raw map[string][]byte, headers map[string]map[string]string, fresh []*services.Result, retry map[int]bool) ([]*services.Result, []string) {
+	type entry struct {
+		idx   int
+		r     *services.Result
+		group string
+	}
+	var entries []entry
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return storedKeyLess(keys[i], keys[j]) })
+	for _, k := range keys {
+		idx, ok := storedIndex(k)
+		if !ok {
+			idx = len(routine.Sources)
+		}
+		r, group := storedResult(ctx, routine, k, raw[k], headers[k])
+		entries = append(entries, entry{idx, r, group})
+	}
+	for idx := range retry {
+		if fresh[idx] != nil {
+			entries = append(entries, entry{idx, fresh[idx], routine.Sources[idx].Group})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].idx < entries[j].idx })
+
+	results := make([]*services.Result, len(entries))
+	groups := make([]string, len(entries))
+	for i, en := range entries {
+		results[i], groups[i] = en.r, en.group
+	}
+	return results, groups
+}
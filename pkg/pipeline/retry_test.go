@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/jcadam/burrow/pkg/services"
+	"github.com/jcadam/burrow/pkg/synthesis"
+)
+
+func TestExecutorRetryFailed(t *testing.T) {
+	reportsDir := filepath.Join(t.TempDir(), "reports")
+
+	routine := &Routine{
+		Name:   "retry",
+		Report: ReportConfig{Title: "Retry Report", GenerateCharts: boolPtr(false)},
+		Sources: []SourceConfig{
+			{Service: "good-api", Tool: "fetch"},
+			{Service: "flaky-api", Tool: "fetch"},
+		},
+	}
+
+	var calls atomic.Int32
+	reg := services.NewRegistry()
+	reg.Register(&countingService{name: "good-api", calls: &calls})
+	reg.Register(&mockService{name: "flaky-api", err: errors.New("connection refused")})
+
+	first, err := NewExecutor(reg, synthesis.NewPassthroughSynthesizer(), reportsDir).Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	records, err := reports.LoadSources(first.Dir)
+	if err != nil {
+		t.Fatalf("LoadSources: %v", err)
+	}
+	if len(records) != 2 || records[0].Status != reports.SourceOK || records[1].Status != reports.SourceError {
+		t.Fatalf("source status after run = %+v", records)
+	}
+
+	// The flaky service recovers; only it should be queried again.
+	reg = services.NewRegistry()
+	reg.Register(&countingService{name: "good-api", calls: &calls})
+	reg.Register(&mockService{name: "flaky-api", response: []byte(`{"recovered": true}`)})
+	synth := &capturingSynthesizer{}
+
+	report, err := NewExecutor(reg, synth, reportsDir).RetryFailed(context.Background(), routine, first.Dir)
+	if err != nil {
+		t.Fatalf("RetryFailed: %v", err)
+	}
+	if report.Dir != first.Dir {
+		t.Errorf("report dir = %s, want %s", report.Dir, first.Dir)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("good-api calls = %d, want 1 (it must not be re-fetched)", got)
+	}
+	if len(synth.results) != 2 {
+		t.Fatalf("synthesis results = %d, want 2", len(synth.results))
+	}
+	if synth.results[0].Service != "good-api" || synth.results[1].Service != "flaky-api" {
+		t.Errorf("results out of source order: %s, %s", synth.results[0].Service, synth.results[1].Service)
+	}
+	if !strings.Contains(string(synth.results[1].Data), "recovered") {
+		t.Errorf("retried result data = %s", synth.results[1].Data)
+	}
+	if !strings.Contains(report.Markdown, "Coverage: 2/2 sources") {
+		t.Errorf("coverage not updated:\n%s", report.Markdown)
+	}
+
+	records, _ = reports.LoadSources(first.Dir)
+	for _, rec := range records {
+		if rec.Status != reports.SourceOK {
+			t.Errorf("source %d status = %s, want ok", rec.Index, rec.Status)
+		}
+	}
+
+	// Nothing left to retry.
+	_, err = NewExecutor(reg, synth, reportsDir).RetryFailed(context.Background(), routine, first.Dir)
+	if err == nil || !strings.Contains(err.Error(), "no failed sources") {
+		t.Errorf("second retry error = %v, want no failed sources", err)
+	}
+}
+
+func TestExecutorRetryFailedChangedRoutine(t *testing.T) {
+	reportsDir := filepath.Join(t.TempDir(), "reports")
+
+	routine := &Routine{
+		Name:    "retry",
+		Report:  ReportConfig{Title: "Retry Report", GenerateCharts: boolPtr(false)},
+		Sources: []SourceConfig{{Service: "missing-api", Tool: "fetch"}},
+	}
+	first, err := NewExecutor(services.NewRegistry(), synthesis.NewPassthroughSynthesizer(), reportsDir).Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	routine.Sources[0].Tool = "search"
+	_, err = NewExecutor(services.NewRegistry(), synthesis.NewPassthroughSynthesizer(), reportsDir).RetryFailed(context.Background(), routine, first.Dir)
+	if err == nil || !strings.Contains(err.Error(), "changed its sources") {
+		t.Errorf("error = %v, want changed sources", err)
+	}
+}
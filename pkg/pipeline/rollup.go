@@ -0,0 +1,100 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/profile"
+	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/jcadam/burrow/pkg/services"
+	"github.com/jcadam/burrow/pkg/slug"
+)
+
+// RollupSuffix is appended to a routine's name for its rollup reports, so
+// they list separately and never feed the next rollup.
+const RollupSuffix = "-rollup"
+
+// Rollup consolidates the routine's reports created at or after since into
+// one report. Each report's markdown becomes a synthesis source, oldest
+// first, and the routine's system prompt is extended with consolidation
+// instructions. No service is queried. The result is saved as a report of
+// routine "<name>-rollup".
+func (e *Executor) Rollup(ctx context.Context, routine *Routine, since time.Time) (*reports.Report, error) {
+	all, err := reports.ListSince(e.reportsDir, since)
+	if err != nil {
+		return nil, err
+	}
+	name := slug.Sanitize(routine.Name)
+	var daily []*reports.Report
+	for _, r := range all {
+		if r.Routine == name {
+			daily = append(daily, r)
+		}
+	}
+	if len(daily) == 0 {
+		return nil, fmt.Errorf("no %s reports since %s", routine.Name, since.Format("2006-01-02"))
+	}
+
+	// ListSince is newest first; the synthesizer reads the period in order.
+	results := make([]*services.Result, len(daily))
+	for i, r := range daily {
+		label := r.Date
+		if r.Title != "" {
+			label += " — " + r.Title
+		}
+		results[len(daily)-1-i] = &services.Result{
+			Service:      "report",
+			Tool:         filepath.Base(r.Dir),
+			Data:         []byte(r.Markdown),
+			Timestamp:    time.Now().UTC(),
+			ContextLabel: label,
+		}
+	}
+	from, to := daily[len(daily)-1].Date, daily[0].Date
+
+	funcs := e.templateFuncs(routine)
+	prof := e.synthesisProfile(routine)
+	system, err := profile.ExpandWith(routine.Synthesis.System, prof, funcs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: profile expansion in synthesis system: %v\n", err)
+	}
+	title, err := profile.ExpandWith(routine.Report.Title, prof, funcs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: profile expansion in report title: %v\n", err)
+	}
+	if routine.Synthesis.Profile.Injected() {
+		if about := profileBackground(prof); about != "" {
+			system = system + "\n\n" + about
+		}
+	}
+	system = system + "\n\n" + buildRollupContext(from, to, len(daily))
+	title = fmt.Sprintf("%s — Rollup %s to %s", title, from, to)
+
+	ctx, usage := meterUsage(ctx)
+	var fallback reports.Fallback
+	markdown, err := e.synthesizer.Synthesize(fallbackContext(ctx, &fallback), title, system, results)
+	if err != nil {
+		return nil, fmt.Errorf("synthesis failed: %w", err)
+	}
+	markdown = recordFallback(markdown, fallback)
+
+	report, err := reports.Save(e.reportsDir, routine.Name+RollupSuffix, markdown, nil)
+	if err != nil {
+		return nil, fmt.Errorf("saving report: %w", err)
+	}
+	usage.save(report.Dir, routine)
+	saveHTML(routine, report)
+	return report, nil
+}
+
+// buildRollupContext tells the synthesizer its sources are earlier reports
+// to consolidate rather than fresh data.
+func buildRollupContext(from, to string, n int) string {
+	return fmt.Sprintf(`## Rollup Report
+
+The sources below are %d earlier reports from this routine, covering %s through %s, oldest first. Consolidate them into one report for the whole period: lead with the developments that mattered most, trace how ongoing stories evolved, note what changed between the start and end of the period, and drop items that were only briefly relevant. Do not produce a separate section per report.`,
+		n, from, to)
+}
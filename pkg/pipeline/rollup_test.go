@@ -0,0 +1,65 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecutorRollup(t *testing.T) {
+	reportsDir := filepath.Join(t.TempDir(), "reports")
+	for name, md := range map[string]string{
+		"2026-03-01T0700-daily":        "# Daily\n\nDay one.\n",
+		"2026-03-03T0700-daily":        "# Daily\n\nDay three.\n",
+		"2026-03-02T0700-other":        "# Other\n\nIgnored.\n",
+		"2026-02-20T0700-daily":        "# Daily\n\nToo old.\n",
+		"2026-03-02T0900-daily-rollup": "# Daily — Rollup\n\nPrevious rollup.\n",
+	} {
+		dir := filepath.Join(reportsDir, name)
+		os.MkdirAll(dir, 0o755)
+		os.WriteFile(filepath.Join(dir, "report.md"), []byte(md), 0o644)
+	}
+
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(nil, synth, reportsDir)
+	routine := &Routine{
+		Name:      "daily",
+		Report:    ReportConfig{Title: "Daily"},
+		Synthesis: SynthesisConfig{System: "You are an analyst."},
+	}
+
+	report, err := exec.Rollup(context.Background(), routine, time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("Rollup: %v", err)
+	}
+
+	if len(synth.results) != 2 {
+		t.Fatalf("rollup sources = %d, want 2", len(synth.results))
+	}
+	if !strings.Contains(string(synth.results[0].Data), "Day one") || !strings.Contains(string(synth.results[1].Data), "Day three") {
+		t.Error("rollup sources should be the routine's reports, oldest first")
+	}
+	if synth.results[0].ContextLabel != "2026-03-01 — Daily" {
+		t.Errorf("context label = %q", synth.results[0].ContextLabel)
+	}
+	if !strings.Contains(synth.systemPrompt, "You are an analyst.") || !strings.Contains(synth.systemPrompt, "2026-03-01 through 2026-03-03") {
+		t.Errorf("system prompt missing rollup context:\n%s", synth.systemPrompt)
+	}
+	if report.Routine != "daily-rollup" {
+		t.Errorf("rollup routine = %q, want daily-rollup", report.Routine)
+	}
+	if !strings.Contains(report.Markdown, "Rollup 2026-03-01 to 2026-03-03") {
+		t.Errorf("rollup title missing period:\n%s", report.Markdown)
+	}
+}
+
+func TestExecutorRollupNoReports(t *testing.T) {
+	exec := NewExecutor(nil, &capturingSynthesizer{}, t.TempDir())
+	_, err := exec.Rollup(context.Background(), &Routine{Name: "daily"}, time.Now().AddDate(0, 0, -7))
+	if err == nil || !strings.Contains(err.Error(), "no daily reports") {
+		t.Errorf("expected no-reports error, got %v", err)
+	}
+}
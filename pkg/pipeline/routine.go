@@ -6,46 +6,163 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/itchyny/gojq"
+	"github.com/jcadam/burrow/pkg/config"
+	"github.com/jcadam/burrow/pkg/slug"
 	"gopkg.in/yaml.v3"
 )
 
 // Routine defines a scheduled data-collection-and-synthesis job.
 type Routine struct {
-	Name      string          `yaml:"-"` // derived from filename
-	Schedule  string          `yaml:"schedule,omitempty"`
-	Timezone  string          `yaml:"timezone,omitempty"`
-	Jitter    int             `yaml:"jitter,omitempty"`
-	LLM       string          `yaml:"llm,omitempty"`
-	Report    ReportConfig    `yaml:"report"`
-	Synthesis SynthesisConfig `yaml:"synthesis,omitempty"`
-	Sources   []SourceConfig  `yaml:"sources"`
+	Name        string          `yaml:"-"`                  // derived from filename
+	Extends     string          `yaml:"extends,omitempty"`  // base routine in the same directory, merged under this one
+	Schedule    string          `yaml:"schedule,omitempty"` // "HH:MM", comma-separated times, a 5-field cron expression, or "every 4h"
+	Timezone    string          `yaml:"timezone,omitempty"`
+	Jitter      JitterConfig    `yaml:"jitter,omitempty"`
+	CatchUp     string          `yaml:"catch_up,omitempty"`     // "" (run once for today) | summary (one consolidated report covering missed days)
+	SnoozeUntil string          `yaml:"snooze_until,omitempty"` // YYYY-MM-DD; the scheduler skips the routine before this date
+	LLM         string          `yaml:"llm,omitempty"`
+	Privacy     string          `yaml:"privacy,omitempty"` // "" | local (synthesis must use a privacy: local provider)
+	Report      ReportConfig    `yaml:"report"`
+	Synthesis   SynthesisConfig `yaml:"synthesis,omitempty"`
+	Sources     []SourceConfig  `yaml:"sources"`
+	Stash       []StashConfig   `yaml:"stash,omitempty"`
+	Budget      BudgetConfig    `yaml:"budget,omitempty"`
+	Context     ContextConfig   `yaml:"context,omitempty"`
+
+	// PostProcess rules rewrite the synthesized report, in order.
+	PostProcess []PostProcessRule `yaml:"post_process,omitempty"`
+
+	// SkipUnchanged skips synthesis when the sources return the same data
+	// as for the last report; UnchangedNote then writes a one-line "no
+	// changes" report instead of none.
+	SkipUnchanged bool `yaml:"skip_unchanged,omitempty"`
+	UnchangedNote bool `yaml:"unchanged_note,omitempty"`
+
+	// After names routines that must complete successfully the same day
+	// before the scheduler runs this one.
+	After []string `yaml:"after,omitempty"`
+
+	// MissedRun is what the scheduler does with a run whose time passed
+	// while the daemon was stopped or the machine asleep: run_immediately
+	// (the default), skip, or notify (log it and list it, without running).
+	MissedRun string `yaml:"missed_run,omitempty"`
+
+	// Dir is the directory the routine was loaded from; a relative
+	// context.file resolves against it. Empty for routines not loaded from disk.
+	Dir string `yaml:"-"`
+
+	// MissedSince is set by the scheduler for catch-up summary runs: the date
+	// (YYYY-MM-DD) of the last successful run. Empty for normal runs.
+	MissedSince string `yaml:"-"`
 }
 
 // ReportConfig controls report generation.
 type ReportConfig struct {
-	Title          string `yaml:"title"`
-	Style          string `yaml:"style,omitempty"`
-	GenerateCharts *bool  `yaml:"generate_charts,omitempty"`
-	MaxLength      int    `yaml:"max_length,omitempty"`
-	CompareWith    string `yaml:"compare_with,omitempty"` // Routine name to compare with for longitudinal analysis
+	Title          string   `yaml:"title"`
+	Style          string   `yaml:"style,omitempty"` // headlines: a linked bullet list of top items, no prose
+	GenerateCharts *bool    `yaml:"generate_charts,omitempty"`
+	MaxLength      int      `yaml:"max_length,omitempty"`
+	CompareWith    string   `yaml:"compare_with,omitempty"` // Routine name to compare with for longitudinal analysis
+	Samples        string   `yaml:"samples,omitempty"`      // separate (default) | append: add same-day runs to one report
+	Sections       []string `yaml:"sections,omitempty"`     // explicit section order, by source context label or section name
+	TLDR           *bool    `yaml:"tldr,omitempty"`         // pin a 3–5 bullet executive summary under the title
+
+	// EmptySections is what happens to sections with no data: keep
+	// (the LLM decides), omit, or placeholder (EmptyPlaceholder, default
+	// "No updates today.").
+	EmptySections    string `yaml:"empty_sections,omitempty"`
+	EmptyPlaceholder string `yaml:"empty_placeholder,omitempty"`
+
+	// HTML also saves each report as a standalone report.html, with
+	// charts embedded, beside report.md.
+	HTML bool `yaml:"html,omitempty"`
+}
+
+// AppendSamples returns whether same-day runs append to the day's existing
+// report instead of producing separate reports.
+func (rc ReportConfig) AppendSamples() bool {
+	return rc.Samples == "append"
+}
+
+// location returns the routine's timezone, or the local one when it is
+// unset or invalid (the scheduler reports an invalid one).
+func (r *Routine) location() *time.Location {
+	if r.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// Headlines returns whether the report is a headlines-only digest
+// (style: headlines).
+func (rc ReportConfig) Headlines() bool {
+	return rc.Style == "headlines"
 }
 
 // ChartsEnabled returns whether chart generation is enabled.
-// Charts are enabled by default (nil = true). Only an explicit false disables them.
+// Charts are enabled by default (nil = true). Only an explicit false disables
+// them, and a headlines digest never has charts.
 func (rc ReportConfig) ChartsEnabled() bool {
+	if rc.Headlines() {
+		return false
+	}
 	return rc.GenerateCharts == nil || *rc.GenerateCharts
 }
 
+// TLDREnabled returns whether the report gets a TL;DR. It is off unless
+// set.
+func (rc ReportConfig) TLDREnabled() bool {
+	return rc.TLDR != nil && *rc.TLDR
+}
+
 // SynthesisConfig holds the LLM system prompt for synthesis.
 type SynthesisConfig struct {
-	System          string `yaml:"system,omitempty"`
-	Strategy        string `yaml:"strategy,omitempty"`           // auto | single | multi-stage
-	SummaryMaxWords int    `yaml:"summary_max_words,omitempty"`  // target words per summary (default: 500)
-	MaxSourceWords  int    `yaml:"max_source_words,omitempty"`   // max words per source before chunking (default: 10000)
-	Concurrency     int    `yaml:"concurrency,omitempty"`        // max concurrent stage 1 LLM calls (default: 1)
-	Preprocess      *bool  `yaml:"preprocess,omitempty"`         // nil=auto (local), true=always, false=never
+	System           string        `yaml:"system,omitempty"`
+	Strategy         string        `yaml:"strategy,omitempty"`          // auto | single | multi-stage
+	SummaryMaxWords  int           `yaml:"summary_max_words,omitempty"` // target words per summary (default: 500)
+	MaxSourceWords   int           `yaml:"max_source_words,omitempty"`  // max words per source before chunking (default: 10000)
+	Concurrency      int           `yaml:"concurrency,omitempty"`       // max concurrent stage 1 LLM calls (default: 1)
+	Preprocess       *bool         `yaml:"preprocess,omitempty"`        // nil=auto (local), true=always, false=never
+	Retries          *int          `yaml:"retries,omitempty"`           // regenerations on empty or refused output (nil = 1, 0 = none)
+	SourceOrder      string        `yaml:"source_order,omitempty"`      // prompt order of source data: routine (default) | relevance | size
+	TruncationMarker string        `yaml:"truncation_marker,omitempty"` // marks where raw data was cut when a summary falls back to it
+	SanitizeSources  *bool         `yaml:"sanitize_sources,omitempty"`  // flag prompt-injection patterns in source data before synthesis
+	Profile          ProfileConfig `yaml:"profile,omitempty"`           // how much of the user profile synthesis sees
+}
+
+// Sanitized returns whether source data is sanitized before synthesis. It
+// is off unless set.
+func (sc SynthesisConfig) Sanitized() bool {
+	return sc.SanitizeSources != nil && *sc.SanitizeSources
+}
+
+// ProfileConfig makes a routine's use of the user profile in synthesis
+// explicit. By default the profile reaches synthesis only through
+// {{profile.X}} references in the system prompt and title.
+type ProfileConfig struct {
+	Include *bool    `yaml:"include,omitempty"` // nil = references only; true = also an "About the Reader" section; false = no profile at all
+	Fields  []string `yaml:"fields,omitempty"`  // top-level profile fields synthesis may use (default: all)
+}
+
+// Excluded returns whether the routine keeps the profile out of synthesis.
+func (pc ProfileConfig) Excluded() bool {
+	return pc.Include != nil && !*pc.Include
+}
+
+// Injected returns whether the profile is added to the synthesis prompt.
+func (pc ProfileConfig) Injected() bool {
+	return pc.Include != nil && *pc.Include
 }
 
 // SourceConfig defines a single data source within a routine.
@@ -54,23 +171,87 @@ type SourceConfig struct {
 	Tool         string            `yaml:"tool"`
 	Params       map[string]string `yaml:"params"`
 	ContextLabel string            `yaml:"context_label,omitempty"`
+	Transform    string            `yaml:"transform,omitempty"`    // jq expression applied to the response before synthesis
+	JSONPath     string            `yaml:"jsonpath,omitempty"`     // JSONPath extraction applied like transform (e.g. $.results[*].{title: title})
+	Group        string            `yaml:"group,omitempty"`        // sources sharing a group are merged into one input for synthesis
+	Required     bool              `yaml:"required,omitempty"`     // a failure fails the run instead of producing a partial report
+	As           string            `yaml:"as,omitempty"`           // "" (synthesize the data) | attachment (save the file, link it from the report)
+	DetectDrift  bool              `yaml:"detect_drift,omitempty"` // warn when the response's JSON structure changes between runs
+	Tags         []string          `yaml:"tags,omitempty"`         // labels passed to synthesis to group and prioritize sections (e.g. critical)
+	Cache        *bool             `yaml:"cache,omitempty"`        // false skips the service's result cache for this source
+	CacheTTL     string            `yaml:"cache_ttl,omitempty"`    // overrides the service's cache TTL for this source (e.g. 60s, 12h; a bare number is seconds)
+	MaxItems     int               `yaml:"max_items,omitempty"`    // keep at most this many result items for synthesis (0 = all)
+	ItemsPath    string            `yaml:"items_path,omitempty"`   // dot path to the items max_items trims (default: the tool's results_path, else the top-level array)
+	SortBy       string            `yaml:"sort_by,omitempty"`      // item field to rank by before trimming, highest first ("field asc" for lowest)
 }
 
-// LoadRoutine reads and parses a single routine YAML file.
+// cacheTTL parses a source's cache_ttl.
+func (s SourceConfig) cacheTTL() (time.Duration, error) {
+	if s.CacheTTL == "" {
+		return 0, nil
+	}
+	value := s.CacheTTL
+	if _, err := strconv.Atoi(value); err == nil {
+		value += "s"
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid cache_ttl %q (must be a positive duration such as 60s or 12h)", s.CacheTTL)
+	}
+	return d, nil
+}
+
+// ContextConfig is standing background for synthesis that no source
+// provides — a glossary, a watch list, notes on the user's situation. It is
+// given to the LLM as reference material, not as instructions.
+type ContextConfig struct {
+	Label string `yaml:"label,omitempty"` // heading for the material (default: "Background")
+	Text  string `yaml:"text,omitempty"`  // inline material
+	File  string `yaml:"file,omitempty"`  // text file to read at each run, relative to the routine file
+}
+
+// BudgetConfig caps the total work one run may do, as a safety valve for
+// unattended routines against metered APIs. Zero means unlimited.
+type BudgetConfig struct {
+	MaxRequests int `yaml:"max_requests,omitempty"`  // HTTP requests sent per run, pages and retries included
+	MaxLLMCalls int `yaml:"max_llm_calls,omitempty"` // LLM completions per run, across all synthesis stages
+}
+
+// PostProcessRule is a regular expression find/replace applied to the
+// synthesized report. Replace may use $1 or ${name} for capture groups.
+type PostProcessRule struct {
+	Pattern string `yaml:"pattern"`
+	Replace string `yaml:"replace"`
+	Repeat  bool   `yaml:"repeat,omitempty"` // reapply until the report stops changing, up to maxPostProcessPasses times
+}
+
+// StashConfig saves a value from a source's result at the end of a run.
+// The next run reads it in templates via {{lastValue "key"}}.
+type StashConfig struct {
+	Key     string `yaml:"key"`
+	Service string `yaml:"service"`
+	Tool    string `yaml:"tool,omitempty"`   // disambiguates when a service is queried more than once
+	Path    string `yaml:"path,omitempty"`   // dot path into the JSON result; arrays and objects yield their length
+	Header  string `yaml:"header,omitempty"` // captured response header to stash instead of a JSON value
+}
+
+// LoadRoutine reads and parses a single routine YAML file. A routine that
+// extends another is merged over its base chain before validation.
 func LoadRoutine(path string) (*Routine, error) {
-	data, err := os.ReadFile(path)
+	// Derive name from filename without extension
+	base := filepath.Base(path)
+	name := strings.TrimSuffix(base, filepath.Ext(base))
+
+	node, err := loadRoutineNode(path, []string{name})
 	if err != nil {
-		return nil, fmt.Errorf("reading routine: %w", err)
+		return nil, err
 	}
-
 	var r Routine
-	if err := yaml.Unmarshal(data, &r); err != nil {
+	if err := node.Decode(&r); err != nil {
 		return nil, fmt.Errorf("parsing routine: %w", err)
 	}
-
-	// Derive name from filename without extension
-	base := filepath.Base(path)
-	r.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	r.Name = name
+	r.Dir = filepath.Dir(path)
 
 	if err := ValidateRoutine(&r); err != nil {
 		return nil, fmt.Errorf("validating routine %q: %w", r.Name, err)
@@ -80,7 +261,8 @@ func LoadRoutine(path string) (*Routine, error) {
 }
 
 // LoadAllRoutines loads all .yaml files from a directory.
-// Invalid routine files are skipped with a warning to warnWriter (if non-nil).
+// Invalid routine files, and files whose routine name conflicts with one
+// already loaded, are skipped with a warning to warnWriter (if non-nil).
 // Use nil for warnWriter to discard warnings.
 func LoadAllRoutines(dir string, warnWriter ...io.Writer) ([]*Routine, error) {
 	entries, err := os.ReadDir(dir)
@@ -97,10 +279,15 @@ func LoadAllRoutines(dir string, warnWriter ...io.Writer) ([]*Routine, error) {
 	}
 
 	var routines []*Routine
+	var files []string             // file each routine was loaded from
+	claimed := map[string]string{} // report slug -> file that claimed it
 	for _, e := range entries {
 		if e.IsDir() || (!strings.HasSuffix(e.Name(), ".yaml") && !strings.HasSuffix(e.Name(), ".yml")) {
 			continue
 		}
+		if strings.HasPrefix(e.Name(), fragmentPrefix) {
+			continue
+		}
 		r, err := LoadRoutine(filepath.Join(dir, e.Name()))
 		if err != nil {
 			if w != nil {
@@ -108,12 +295,37 @@ func LoadAllRoutines(dir string, warnWriter ...io.Writer) ([]*Routine, error) {
 			}
 			continue
 		}
+		// Routines are keyed by name in scheduler state and by its slug in
+		// report directories, so a second file with either would share the
+		// first one's history. Entries are sorted, so .yaml wins over .yml,
+		// as it does for gd routines run.
+		key := slug.Sanitize(r.Name)
+		if first, ok := claimed[key]; ok {
+			if w != nil {
+				fmt.Fprintf(w, "warning: skipping %s: routine name %q conflicts with %s (rename one)\n", e.Name(), r.Name, first)
+			}
+			continue
+		}
+		claimed[key] = e.Name()
+		for i, prev := range routines {
+			if w != nil && sameRun(prev, r) {
+				fmt.Fprintf(w, "warning: %s and %s have the same schedule and sources, so they run twice (is one a copy?)\n", files[i], e.Name())
+			}
+		}
 		routines = append(routines, r)
+		files = append(files, e.Name())
 	}
 
 	return routines, nil
 }
 
+// sameRun reports whether two scheduled routines would query the same
+// sources at the same times.
+func sameRun(a, b *Routine) bool {
+	return a.Schedule != "" && a.Schedule == b.Schedule && a.Timezone == b.Timezone &&
+		reflect.DeepEqual(a.Sources, b.Sources)
+}
+
 // SaveRoutine marshals a routine to YAML and writes it to the routines directory.
 // The Name field is excluded (yaml:"-") since it's derived from the filename.
 func SaveRoutine(routinesDir string, r *Routine) error {
@@ -145,6 +357,159 @@ func ValidateRoutine(r *Routine) error {
 		if s.Tool == "" {
 			return fmt.Errorf("source[%d] missing tool", i)
 		}
+		if s.Transform != "" {
+			if _, err := gojq.Parse(s.Transform); err != nil {
+				return fmt.Errorf("source[%d] invalid transform: %w", i, err)
+			}
+		}
+		if s.JSONPath != "" {
+			if s.Transform != "" {
+				return fmt.Errorf("source[%d] sets both transform and jsonpath (use one)", i)
+			}
+			expr, err := jsonPathToJQ(s.JSONPath)
+			if err != nil {
+				return fmt.Errorf("source[%d] invalid %w", i, err)
+			}
+			if _, err := gojq.Parse(expr); err != nil {
+				return fmt.Errorf("source[%d] invalid jsonpath %q: %w", i, s.JSONPath, err)
+			}
+		}
+		for _, tag := range s.Tags {
+			if strings.TrimSpace(tag) == "" || strings.Contains(tag, ",") {
+				return fmt.Errorf("source[%d] invalid tag %q (must be non-empty, without commas)", i, tag)
+			}
+		}
+		if _, err := s.cacheTTL(); err != nil {
+			return fmt.Errorf("source[%d] %w", i, err)
+		}
+		if s.Cache != nil && !*s.Cache && s.CacheTTL != "" {
+			return fmt.Errorf("source[%d] sets both cache: false and cache_ttl (use one)", i)
+		}
+		if s.MaxItems < 0 {
+			return fmt.Errorf("source[%d] has negative max_items %d", i, s.MaxItems)
+		}
+		if (s.ItemsPath != "" || s.SortBy != "") && s.MaxItems == 0 {
+			return fmt.Errorf("source[%d] sets items_path or sort_by without max_items", i)
+		}
+		if _, _, err := parseSortBy(s.SortBy); err != nil {
+			return fmt.Errorf("source[%d] %w", i, err)
+		}
+		switch s.As {
+		case "":
+			// valid
+		case "attachment":
+			if s.Transform != "" || s.JSONPath != "" {
+				return fmt.Errorf("source[%d] is an attachment and cannot have a transform", i)
+			}
+		default:
+			return fmt.Errorf("source[%d] invalid as %q (must be attachment or omitted)", i, s.As)
+		}
+	}
+	for i, st := range r.Stash {
+		if st.Key == "" {
+			return fmt.Errorf("stash[%d] missing key", i)
+		}
+		if st.Service == "" {
+			return fmt.Errorf("stash[%d] missing service", i)
+		}
+		if st.Header != "" && st.Path != "" {
+			return fmt.Errorf("stash[%d] sets both path and header (use one)", i)
+		}
+		found := false
+		for _, src := range r.Sources {
+			if src.Service == st.Service && (st.Tool == "" || src.Tool == st.Tool) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("stash[%d] references %s/%s which is not a source of this routine", i, st.Service, st.Tool)
+		}
+	}
+	for i, rule := range r.PostProcess {
+		if rule.Pattern == "" {
+			return fmt.Errorf("post_process[%d] missing pattern", i)
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("post_process[%d] invalid pattern: %w", i, err)
+		}
+		if re.MatchString("") {
+			return fmt.Errorf("post_process[%d] pattern %q matches the empty string", i, rule.Pattern)
+		}
+	}
+	if r.Context.Text != "" && r.Context.File != "" {
+		return fmt.Errorf("context sets both text and file (use one)")
+	}
+	switch r.Privacy {
+	case "", "local":
+		// valid
+	default:
+		return fmt.Errorf("invalid privacy %q (must be local or omitted)", r.Privacy)
+	}
+	if r.SnoozeUntil != "" {
+		if _, err := time.Parse("2006-01-02", r.SnoozeUntil); err != nil {
+			return fmt.Errorf("invalid snooze_until %q (must be YYYY-MM-DD)", r.SnoozeUntil)
+		}
+	}
+	if err := r.Jitter.validate(); err != nil {
+		return err
+	}
+	for _, dep := range r.After {
+		if strings.TrimSpace(dep) == "" {
+			return fmt.Errorf("after has an empty routine name")
+		}
+		if dep == r.Name {
+			return fmt.Errorf("after names the routine itself")
+		}
+	}
+	if r.UnchangedNote && !r.SkipUnchanged {
+		return fmt.Errorf("unchanged_note is set but skip_unchanged is not")
+	}
+	switch r.MissedRun {
+	case "", "run_immediately", "skip", "notify":
+		// valid
+	default:
+		return fmt.Errorf("invalid missed_run %q (must be run_immediately, skip, or notify)", r.MissedRun)
+	}
+	if r.MissedRun != "" && r.Schedule == "" {
+		return fmt.Errorf("missed_run is set but schedule is not")
+	}
+	switch r.CatchUp {
+	case "", "summary":
+		// valid
+	default:
+		return fmt.Errorf("invalid catch_up %q (must be summary or omitted)", r.CatchUp)
+	}
+	switch r.Report.Samples {
+	case "", "separate", "append":
+		// valid
+	default:
+		return fmt.Errorf("invalid report.samples %q (must be separate or append)", r.Report.Samples)
+	}
+	switch r.Report.EmptySections {
+	case "", "keep", "omit", "placeholder":
+		// valid
+	default:
+		return fmt.Errorf("invalid report.empty_sections %q (must be keep, omit, or placeholder)", r.Report.EmptySections)
+	}
+	if r.Report.EmptyPlaceholder != "" && r.Report.EmptyPolicy() != "placeholder" {
+		return fmt.Errorf("report.empty_placeholder is set but report.empty_sections is not placeholder")
+	}
+	switch r.Synthesis.SourceOrder {
+	case "", "routine", "relevance", "size":
+		// valid
+	default:
+		return fmt.Errorf("invalid synthesis.source_order %q (must be routine, relevance, or size)", r.Synthesis.SourceOrder)
+	}
+	if r.Budget.MaxRequests < 0 {
+		return fmt.Errorf("budget.max_requests must not be negative")
+	}
+	if r.Budget.MaxLLMCalls < 0 {
+		return fmt.Errorf("budget.max_llm_calls must not be negative")
+	}
+	if err := validateProfileConfig(r); err != nil {
+		return err
 	}
 	if r.Synthesis.Strategy != "" {
 		validStrategies := map[string]bool{"auto": true, "single": true, "multi-stage": true}
@@ -154,3 +519,55 @@ func ValidateRoutine(r *Routine) error {
 	}
 	return nil
 }
+
+// profileRefPattern matches a template expression that reads the profile:
+// {{profile.X}} or {{profile "X"}}.
+var profileRefPattern = regexp.MustCompile(`\{\{[^}]*\bprofile\b`)
+
+// validateProfileConfig checks synthesis.profile. A routine that excludes the
+// profile can't also reference it in its synthesis prompt or title.
+func validateProfileConfig(r *Routine) error {
+	pc := r.Synthesis.Profile
+	for _, f := range pc.Fields {
+		if strings.TrimSpace(f) == "" || strings.Contains(f, ".") {
+			return fmt.Errorf("invalid synthesis.profile field %q (must be a top-level profile field)", f)
+		}
+	}
+	if !pc.Excluded() {
+		return nil
+	}
+	if len(pc.Fields) > 0 {
+		return fmt.Errorf("synthesis.profile.fields has no effect when include is false")
+	}
+	if profileRefPattern.MatchString(r.Synthesis.System) || profileRefPattern.MatchString(r.Report.Title) {
+		return fmt.Errorf("synthesis.profile.include is false but the synthesis prompt or title references the profile")
+	}
+	return nil
+}
+
+// ValidateRoutineLLM checks a routine's llm: against the configured LLM
+// providers: a named provider must exist, and a routine with privacy: local
+// must name a provider with privacy: local. Passthrough synthesis ("",
+// "none", or "passthrough") needs no provider and always passes.
+func ValidateRoutineLLM(r *Routine, cfg *config.Config) error {
+	if r.LLM == "" || r.LLM == "none" || r.LLM == "passthrough" {
+		return nil
+	}
+	for _, prov := range cfg.LLM.Providers {
+		if prov.Name != r.LLM {
+			continue
+		}
+		if r.Privacy == "local" && prov.Privacy != "local" {
+			privacy := prov.Privacy
+			if privacy == "" {
+				privacy = "unset"
+			}
+			return fmt.Errorf("routine %q requires local privacy, but LLM provider %q has privacy %s", r.Name, r.LLM, privacy)
+		}
+		return nil
+	}
+	if len(cfg.LLM.Providers) == 0 {
+		return fmt.Errorf("routine %q: LLM provider %q not found in config: no LLM providers are configured (add one with gd configure, or set llm: none for a raw-data report)", r.Name, r.LLM)
+	}
+	return fmt.Errorf("routine %q: LLM provider %q not found in config", r.Name, r.LLM)
+}
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/jcadam/burrow/pkg/config"
 )
 
 const testRoutine = `
@@ -53,6 +55,9 @@ func TestLoadRoutine(t *testing.T) {
 	if r.Name != "morning-intel" {
 		t.Errorf("expected name morning-intel, got %q", r.Name)
 	}
+	if r.Dir != dir {
+		t.Errorf("expected dir %q, got %q", dir, r.Dir)
+	}
 	if r.Schedule != "05:00" {
 		t.Errorf("expected schedule 05:00, got %q", r.Schedule)
 	}
@@ -163,7 +168,7 @@ func TestSaveRoutineRoundTrip(t *testing.T) {
 		Name:     "test-routine",
 		Schedule: "06:00",
 		Timezone: "America/New_York",
-		Jitter:   120,
+		Jitter:   JitterConfig{Max: 120},
 		LLM:      "local/test",
 		Report: ReportConfig{
 			Title: "Test Report",
@@ -373,3 +378,448 @@ func TestLoadAllRoutinesSkipsBadFiles(t *testing.T) {
 		t.Errorf("expected warning about bad.yaml, got: %q", warnings.String())
 	}
 }
+
+func TestValidateRoutineStash(t *testing.T) {
+	base := func() *Routine {
+		return &Routine{
+			Report:  ReportConfig{Title: "T"},
+			Sources: []SourceConfig{{Service: "sam", Tool: "search"}},
+		}
+	}
+
+	r := base()
+	r.Stash = []StashConfig{{Key: "count", Service: "sam", Path: "total"}}
+	if err := ValidateRoutine(r); err != nil {
+		t.Errorf("valid stash rejected: %v", err)
+	}
+
+	r = base()
+	r.Stash = []StashConfig{{Service: "sam"}}
+	if err := ValidateRoutine(r); err == nil {
+		t.Error("expected error for stash without key")
+	}
+
+	r = base()
+	r.Stash = []StashConfig{{Key: "count", Service: "edgar"}}
+	if err := ValidateRoutine(r); err == nil {
+		t.Error("expected error for stash referencing unknown source")
+	}
+
+	r = base()
+	r.Stash = []StashConfig{{Key: "count", Service: "sam", Path: "total", Header: "X-Total-Count"}}
+	if err := ValidateRoutine(r); err == nil {
+		t.Error("expected error for stash with both path and header")
+	}
+}
+
+func TestValidateRoutineTransform(t *testing.T) {
+	r := &Routine{
+		Report:  ReportConfig{Title: "T"},
+		Sources: []SourceConfig{{Service: "s", Tool: "t", Transform: "[.items[] | {title, score}]"}},
+	}
+	if err := ValidateRoutine(r); err != nil {
+		t.Errorf("valid transform rejected: %v", err)
+	}
+
+	r.Sources[0].Transform = ".items[] |"
+	err := ValidateRoutine(r)
+	if err == nil {
+		t.Fatal("expected error for invalid transform")
+	}
+	if !strings.Contains(err.Error(), "invalid transform") {
+		t.Errorf("expected transform error, got: %v", err)
+	}
+}
+
+func TestValidateRoutineTags(t *testing.T) {
+	r := &Routine{
+		Report:  ReportConfig{Title: "T"},
+		Sources: []SourceConfig{{Service: "s", Tool: "t", Tags: []string{"weather", "critical"}}},
+	}
+	if err := ValidateRoutine(r); err != nil {
+		t.Errorf("valid tags rejected: %v", err)
+	}
+
+	for _, tag := range []string{"", "  ", "a,b"} {
+		r.Sources[0].Tags = []string{tag}
+		err := ValidateRoutine(r)
+		if err == nil || !strings.Contains(err.Error(), "invalid tag") {
+			t.Errorf("tag %q: expected invalid tag error, got %v", tag, err)
+		}
+	}
+}
+
+func TestValidateRoutineCacheTTL(t *testing.T) {
+	r := &Routine{
+		Report:  ReportConfig{Title: "T"},
+		Sources: []SourceConfig{{Service: "s", Tool: "t"}},
+	}
+	for _, ttl := range []string{"60s", "12h", "300"} {
+		r.Sources[0].CacheTTL = ttl
+		if err := ValidateRoutine(r); err != nil {
+			t.Errorf("cache_ttl %q rejected: %v", ttl, err)
+		}
+		if d, _ := r.Sources[0].cacheTTL(); d <= 0 {
+			t.Errorf("cache_ttl %q parsed to %v", ttl, d)
+		}
+	}
+	for _, ttl := range []string{"soon", "0", "-5m"} {
+		r.Sources[0].CacheTTL = ttl
+		if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "invalid cache_ttl") {
+			t.Errorf("cache_ttl %q: expected invalid cache_ttl error, got %v", ttl, err)
+		}
+	}
+
+	off := false
+	r.Sources[0].Cache, r.Sources[0].CacheTTL = &off, "60s"
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "use one") {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+}
+
+func TestValidateRoutineMaxItems(t *testing.T) {
+	r := &Routine{
+		Report:  ReportConfig{Title: "T"},
+		Sources: []SourceConfig{{Service: "s", Tool: "t", MaxItems: 10, ItemsPath: "data.items", SortBy: "score"}},
+	}
+	for _, sortBy := range []string{"score", "published asc", "meta.rank desc"} {
+		r.Sources[0].SortBy = sortBy
+		if err := ValidateRoutine(r); err != nil {
+			t.Errorf("sort_by %q rejected: %v", sortBy, err)
+		}
+	}
+
+	r.Sources[0].SortBy = "score upward"
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "invalid sort_by") {
+		t.Errorf("expected invalid sort_by error, got %v", err)
+	}
+
+	r.Sources[0].SortBy, r.Sources[0].MaxItems = "score", 0
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "without max_items") {
+		t.Errorf("expected missing max_items error, got %v", err)
+	}
+
+	r.Sources[0].MaxItems = -1
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "negative max_items") {
+		t.Errorf("expected negative max_items error, got %v", err)
+	}
+}
+
+func TestValidateRoutineSnoozeUntil(t *testing.T) {
+	r := &Routine{
+		Report:      ReportConfig{Title: "T"},
+		Sources:     []SourceConfig{{Service: "s", Tool: "t"}},
+		SnoozeUntil: "2025-02-01",
+	}
+	if err := ValidateRoutine(r); err != nil {
+		t.Errorf("valid snooze_until rejected: %v", err)
+	}
+
+	r.SnoozeUntil = "Feb 1"
+	err := ValidateRoutine(r)
+	if err == nil || !strings.Contains(err.Error(), "invalid snooze_until") {
+		t.Errorf("expected snooze_until error, got %v", err)
+	}
+}
+
+func TestValidateRoutineAfter(t *testing.T) {
+	r := &Routine{
+		Name:    "digest",
+		Report:  ReportConfig{Title: "T"},
+		Sources: []SourceConfig{{Service: "s", Tool: "t"}},
+		After:   []string{"morning-intel"},
+	}
+	if err := ValidateRoutine(r); err != nil {
+		t.Errorf("valid after rejected: %v", err)
+	}
+
+	r.After = []string{"digest"}
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "itself") {
+		t.Errorf("expected self-dependency error, got %v", err)
+	}
+	r.After = []string{" "}
+	if err := ValidateRoutine(r); err == nil {
+		t.Error("expected error for empty routine name in after")
+	}
+}
+
+func TestValidateRoutineMissedRun(t *testing.T) {
+	r := &Routine{
+		Schedule:  "05:00",
+		Report:    ReportConfig{Title: "T"},
+		Sources:   []SourceConfig{{Service: "s", Tool: "t"}},
+		MissedRun: "notify",
+	}
+	if err := ValidateRoutine(r); err != nil {
+		t.Errorf("valid missed_run rejected: %v", err)
+	}
+
+	r.MissedRun = "later"
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "invalid missed_run") {
+		t.Errorf("expected missed_run error, got %v", err)
+	}
+	r.MissedRun, r.Schedule = "skip", ""
+	if err := ValidateRoutine(r); err == nil {
+		t.Error("expected error for missed_run without a schedule")
+	}
+}
+
+func TestValidateRoutineProfile(t *testing.T) {
+	base := func(pc ProfileConfig, system string) *Routine {
+		return &Routine{
+			Report:    ReportConfig{Title: "T"},
+			Synthesis: SynthesisConfig{System: system, Profile: pc},
+			Sources:   []SourceConfig{{Service: "s", Tool: "t"}},
+		}
+	}
+	no, yes := false, true
+
+	tests := []struct {
+		name    string
+		routine *Routine
+		wantErr string
+	}{
+		{"include with fields", base(ProfileConfig{Include: &yes, Fields: []string{"name"}}, "Brief for {{profile.name}}."), ""},
+		{"exclude", base(ProfileConfig{Include: &no}, "Neutral digest."), ""},
+		{"nested field", base(ProfileConfig{Fields: []string{"location.city"}}, ""), "must be a top-level profile field"},
+		{"exclude with fields", base(ProfileConfig{Include: &no, Fields: []string{"name"}}, ""), "no effect when include is false"},
+		{"exclude with reference", base(ProfileConfig{Include: &no}, "For {{ profile \"name\" }}."), "references the profile"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRoutine(tt.routine)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRoutineBudget(t *testing.T) {
+	r := &Routine{
+		Report:  ReportConfig{Title: "T"},
+		Sources: []SourceConfig{{Service: "s", Tool: "t"}},
+		Budget:  BudgetConfig{MaxRequests: 5, MaxLLMCalls: 3},
+	}
+	if err := ValidateRoutine(r); err != nil {
+		t.Errorf("valid budget rejected: %v", err)
+	}
+
+	r.Budget.MaxLLMCalls = -1
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "max_llm_calls") {
+		t.Errorf("expected max_llm_calls error, got: %v", err)
+	}
+}
+
+func TestValidateRoutineSourceOrder(t *testing.T) {
+	r := &Routine{
+		Report:  ReportConfig{Title: "T"},
+		Sources: []SourceConfig{{Service: "s", Tool: "t"}},
+	}
+	for _, order := range []string{"", "routine", "relevance", "size"} {
+		r.Synthesis.SourceOrder = order
+		if err := ValidateRoutine(r); err != nil {
+			t.Errorf("source_order %q rejected: %v", order, err)
+		}
+	}
+	r.Synthesis.SourceOrder = "random"
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "source_order") {
+		t.Errorf("expected source_order error, got: %v", err)
+	}
+}
+
+func TestValidateRoutineAttachment(t *testing.T) {
+	r := &Routine{
+		Report:  ReportConfig{Title: "T"},
+		Sources: []SourceConfig{{Service: "s", Tool: "t", As: "attachment"}},
+	}
+	if err := ValidateRoutine(r); err != nil {
+		t.Errorf("attachment source rejected: %v", err)
+	}
+
+	r.Sources[0].Transform = ".items"
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "cannot have a transform") {
+		t.Errorf("expected transform error, got: %v", err)
+	}
+
+	r.Sources[0] = SourceConfig{Service: "s", Tool: "t", As: "file"}
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "invalid as") {
+		t.Errorf("expected invalid as error, got: %v", err)
+	}
+}
+
+func TestValidateRoutineContext(t *testing.T) {
+	r := &Routine{
+		Report:  ReportConfig{Title: "T"},
+		Sources: []SourceConfig{{Service: "sam", Tool: "search"}},
+		Context: ContextConfig{Text: "Watch: Acme Corp"},
+	}
+	if err := ValidateRoutine(r); err != nil {
+		t.Errorf("inline context rejected: %v", err)
+	}
+
+	r.Context.File = "watchlist.md"
+	if err := ValidateRoutine(r); err == nil {
+		t.Error("expected error for context with both text and file")
+	}
+}
+
+func TestValidateRoutineLLM(t *testing.T) {
+	cfg := &config.Config{LLM: config.LLMConfig{Providers: []config.ProviderConfig{
+		{Name: "local/qwen", Type: "ollama", Privacy: "local"},
+		{Name: "openrouter/claude", Type: "openrouter", Privacy: "remote"},
+		{Name: "unlabeled", Type: "ollama"},
+	}}}
+	tests := []struct {
+		llm, privacy string
+		wantErr      string
+	}{
+		{"", "", ""},
+		{"none", "local", ""},
+		{"local/qwen", "", ""},
+		{"local/qwen", "local", ""},
+		{"openrouter/claude", "", ""},
+		{"openrouter/claude", "local", `routine "brief" requires local privacy, but LLM provider "openrouter/claude" has privacy remote`},
+		{"unlabeled", "local", `LLM provider "unlabeled" has privacy unset`},
+		{"local/missing", "", `routine "brief": LLM provider "local/missing" not found in config`},
+	}
+	for _, tt := range tests {
+		r := &Routine{Name: "brief", LLM: tt.llm, Privacy: tt.privacy}
+		err := ValidateRoutineLLM(r, cfg)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("llm=%q privacy=%q: unexpected error %v", tt.llm, tt.privacy, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("llm=%q privacy=%q: error %v, want %q", tt.llm, tt.privacy, err, tt.wantErr)
+		}
+	}
+
+	// With nothing configured at all, the error says how to proceed.
+	err := ValidateRoutineLLM(&Routine{Name: "brief", LLM: "local/qwen"}, &config.Config{})
+	if err == nil || !strings.Contains(err.Error(), "no LLM providers are configured") || !strings.Contains(err.Error(), "llm: none") {
+		t.Errorf("expected setup guidance, got %v", err)
+	}
+}
+
+func TestValidateRoutinePrivacy(t *testing.T) {
+	r := &Routine{
+		Report:  ReportConfig{Title: "T"},
+		Sources: []SourceConfig{{Service: "sam", Tool: "search"}},
+		Privacy: "remote",
+	}
+	if err := ValidateRoutine(r); err == nil {
+		t.Error("expected error for privacy other than local")
+	}
+}
+
+func TestHeadlinesDisablesCharts(t *testing.T) {
+	on := true
+	rc := ReportConfig{Style: "headlines", GenerateCharts: &on}
+	if !rc.Headlines() || rc.ChartsEnabled() {
+		t.Error("headlines style should disable charts")
+	}
+}
+
+func TestLoadAllRoutinesDuplicateNames(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "brief.yaml"), []byte(testRoutine), 0o644)
+	os.WriteFile(filepath.Join(dir, "brief.yml"), []byte(testRoutine), 0o644)
+	// Different names, but the same report directory slug.
+	os.WriteFile(filepath.Join(dir, "My_Brief.yaml"), []byte(testRoutine), 0o644)
+	os.WriteFile(filepath.Join(dir, "my-brief.yaml"), []byte(testRoutine), 0o644)
+
+	var warnings strings.Builder
+	routines, err := LoadAllRoutines(dir, &warnings)
+	if err != nil {
+		t.Fatalf("LoadAllRoutines: %v", err)
+	}
+	var names []string
+	for _, r := range routines {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, ","); got != "My_Brief,brief" {
+		t.Errorf("loaded routines = %s, want My_Brief,brief", got)
+	}
+	for _, want := range []string{
+		`skipping brief.yml: routine name "brief" conflicts with brief.yaml`,
+		`skipping my-brief.yaml: routine name "my-brief" conflicts with My_Brief.yaml`,
+	} {
+		if !strings.Contains(warnings.String(), want) {
+			t.Errorf("expected warning %q, got:\n%s", want, warnings.String())
+		}
+	}
+}
+
+func TestLoadAllRoutinesWarnsOnCopies(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "brief.yaml"), []byte(testRoutine), 0o644)
+	os.WriteFile(filepath.Join(dir, "brief-copy.yaml"), []byte(testRoutine), 0o644)
+
+	var warnings strings.Builder
+	routines, err := LoadAllRoutines(dir, &warnings)
+	if err != nil {
+		t.Fatalf("LoadAllRoutines: %v", err)
+	}
+	if len(routines) != 2 {
+		t.Errorf("copies should both load, got %d", len(routines))
+	}
+	if !strings.Contains(warnings.String(), "brief-copy.yaml and brief.yaml have the same schedule and sources") {
+		t.Errorf("expected copy warning, got: %q", warnings.String())
+	}
+}
+
+func TestValidateEmptySections(t *testing.T) {
+	r := &Routine{
+		Report:  ReportConfig{Title: "T", EmptySections: "hide"},
+		Sources: []SourceConfig{{Service: "s", Tool: "t"}},
+	}
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "empty_sections") {
+		t.Errorf("expected empty_sections error, got %v", err)
+	}
+	r.Report = ReportConfig{Title: "T", EmptyPlaceholder: "Quiet."}
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "empty_placeholder") {
+		t.Errorf("expected empty_placeholder error, got %v", err)
+	}
+}
+
+func TestValidatePostProcess(t *testing.T) {
+	tests := []struct {
+		rule PostProcessRule
+		want string
+	}{
+		{PostProcessRule{Replace: "x"}, "missing pattern"},
+		{PostProcessRule{Pattern: "(unclosed"}, "invalid pattern"},
+		{PostProcessRule{Pattern: "a*"}, "matches the empty string"},
+	}
+	for _, tt := range tests {
+		r := &Routine{
+			Report:      ReportConfig{Title: "T"},
+			Sources:     []SourceConfig{{Service: "s", Tool: "t"}},
+			PostProcess: []PostProcessRule{tt.rule},
+		}
+		if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("rule %+v: expected %q error, got %v", tt.rule, tt.want, err)
+		}
+	}
+}
+
+func TestValidateUnchangedNote(t *testing.T) {
+	r := &Routine{
+		Report:        ReportConfig{Title: "T"},
+		Sources:       []SourceConfig{{Service: "s", Tool: "t"}},
+		UnchangedNote: true,
+	}
+	if err := ValidateRoutine(r); err == nil || !strings.Contains(err.Error(), "skip_unchanged") {
+		t.Errorf("expected skip_unchanged error, got %v", err)
+	}
+	r.SkipUnchanged = true
+	if err := ValidateRoutine(r); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
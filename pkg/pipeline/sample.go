@@ -0,0 +1,47 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/reports"
+)
+
+// sampleSection formats a same-day sample for appending to the day's report.
+// The sample's own "# Title" repeats the report's, so it is dropped, and
+// its remaining headings move down a level to nest under "## Sample at".
+func sampleSection(markdown string, at time.Time) string {
+	lines := strings.Split(markdown, "\n")
+	out := make([]string, 0, len(lines))
+	inFence, titled := false, false
+	for _, l := range lines {
+		if strings.HasPrefix(strings.TrimSpace(l), "```") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(l, "#") {
+			level := len(l) - len(strings.TrimLeft(l, "#"))
+			if level == 1 && !titled && strings.HasPrefix(l, "# ") {
+				titled = true
+				continue
+			}
+			if level < 6 && strings.HasPrefix(l[level:], " ") {
+				l = "#" + l
+			}
+		}
+		out = append(out, l)
+	}
+	body := strings.TrimLeft(strings.Join(out, "\n"), "\n")
+	return fmt.Sprintf("## Sample at %s\n\n%s", at.Format("15:04"), body)
+}
+
+// sameDayReport reports whether r was created on now's date in loc, the
+// routine's timezone, so a sample joins the report for the routine's day
+// rather than the host's.
+func sameDayReport(r *reports.Report, now time.Time, loc *time.Location) bool {
+	if r == nil {
+		return false
+	}
+	created, ok := reports.DirTime(r.Dir)
+	return ok && created.In(loc).Format("2006-01-02") == now.In(loc).Format("2006-01-02")
+}
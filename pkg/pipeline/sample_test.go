@@ -0,0 +1,53 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/reports"
+)
+
+func TestSampleSectionNestsHeadings(t *testing.T) {
+	md := "# Prices\n\n## quotes\n\n```\n# not a heading\n```\n\n### detail\n"
+	at := time.Date(2026, 3, 1, 14, 5, 0, 0, time.UTC)
+
+	got := sampleSection(md, at)
+
+	if !strings.HasPrefix(got, "## Sample at 14:05\n\n### quotes") {
+		t.Errorf("expected the title dropped and ## demoted, got:\n%s", got)
+	}
+	if strings.Contains(got, "# Prices") {
+		t.Errorf("expected the sample's title dropped, got:\n%s", got)
+	}
+	if !strings.Contains(got, "\n# not a heading\n") {
+		t.Errorf("expected fenced code left alone, got:\n%s", got)
+	}
+	if !strings.Contains(got, "\n#### detail") {
+		t.Errorf("expected ### demoted to ####, got:\n%s", got)
+	}
+}
+
+func TestSameDayReportUsesRoutineTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	// Report dirs are named in host time; dates are compared in the
+	// routine's zone, so build the instants from both to hold on any host.
+	created := time.Date(2026, 3, 1, 10, 0, 0, 0, time.Local)
+	r := &reports.Report{Dir: "/reports/" + created.Format("2006-01-02T150405") + "-prices"}
+
+	later := created.Add(time.Hour)
+	if !sameDayReport(r, later, time.Local) {
+		t.Error("expected a report an hour old to be the same day locally")
+	}
+
+	nextTokyoDay := time.Date(created.In(tokyo).Year(), created.In(tokyo).Month(), created.In(tokyo).Day()+1, 0, 30, 0, 0, tokyo)
+	if sameDayReport(r, nextTokyoDay, tokyo) {
+		t.Error("expected the next Tokyo date to start a new report")
+	}
+	if sameDayReport(nil, later, time.Local) {
+		t.Error("expected no latest report to never match")
+	}
+}
@@ -0,0 +1,75 @@
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SnoozedOn reports whether the routine is snoozed on date (YYYY-MM-DD):
+// it has a snooze_until date that date has not yet reached.
+func (r *Routine) SnoozedOn(date string) bool {
+	return r.SnoozeUntil != "" && date < r.SnoozeUntil
+}
+
+// SetSnoozeUntil rewrites the routine file at path with snooze_until set to
+// date (YYYY-MM-DD), or removed when date is empty. The file is edited in
+// place as YAML, so comments and the rest of the routine are kept.
+func SetSnoozeUntil(path, date string) error {
+	if date != "" {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("invalid snooze date %q (must be YYYY-MM-DD)", date)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading routine: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing routine: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("parsing routine: not a YAML mapping")
+	}
+	root := doc.Content[0]
+
+	// Mapping content alternates key and value nodes.
+	at, after := -1, 0
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		switch root.Content[i].Value {
+		case "snooze_until":
+			at = i
+		case "schedule":
+			after = i + 2
+		}
+	}
+	switch {
+	case date == "" && at < 0:
+		return nil
+	case date == "":
+		root.Content = append(root.Content[:at], root.Content[at+2:]...)
+	case at >= 0:
+		root.Content[at+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: date}
+	default:
+		// Next to the schedule it pauses, or first when there is none.
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "snooze_until"}
+		value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: date}
+		root.Content = append(root.Content[:after], append([]*yaml.Node{key, value}, root.Content[after:]...)...)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("marshaling routine: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("marshaling routine: %w", err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRoutineSnoozedOn(t *testing.T) {
+	r := &Routine{SnoozeUntil: "2025-02-01"}
+	if !r.SnoozedOn("2025-01-31") {
+		t.Error("expected snoozed the day before snooze_until")
+	}
+	if r.SnoozedOn("2025-02-01") {
+		t.Error("expected the routine to resume on snooze_until")
+	}
+	if (&Routine{}).SnoozedOn("2025-01-31") {
+		t.Error("routine without snooze_until should not be snoozed")
+	}
+}
+
+func TestSetSnoozeUntil(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "brief.yaml")
+	original := `# Morning brief
+schedule: "05:00"
+report:
+  title: Brief # shown in the header
+sources:
+  - service: api
+    tool: search
+`
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetSnoozeUntil(path, "2025-02-01"); err != nil {
+		t.Fatalf("SetSnoozeUntil: %v", err)
+	}
+	r, err := LoadRoutine(path)
+	if err != nil {
+		t.Fatalf("LoadRoutine after snooze: %v", err)
+	}
+	if r.SnoozeUntil != "2025-02-01" {
+		t.Errorf("snooze_until = %q, want 2025-02-01", r.SnoozeUntil)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# Morning brief") || !strings.Contains(string(data), "# shown in the header") {
+		t.Errorf("comments lost:\n%s", data)
+	}
+	if strings.Index(string(data), "snooze_until") < strings.Index(string(data), "schedule") {
+		t.Errorf("expected snooze_until after schedule:\n%s", data)
+	}
+
+	if err := SetSnoozeUntil(path, "2025-03-01"); err != nil {
+		t.Fatalf("SetSnoozeUntil again: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Count(string(data), "snooze_until") != 1 || !strings.Contains(string(data), "2025-03-01") {
+		t.Errorf("expected one updated snooze_until:\n%s", data)
+	}
+
+	if err := SetSnoozeUntil(path, ""); err != nil {
+		t.Fatalf("SetSnoozeUntil clear: %v", err)
+	}
+	r, err = LoadRoutine(path)
+	if err != nil {
+		t.Fatalf("LoadRoutine after enable: %v", err)
+	}
+	if r.SnoozeUntil != "" {
+		t.Errorf("snooze_until = %q after clearing, want empty", r.SnoozeUntil)
+	}
+}
+
+func TestSetSnoozeUntilRejectsBadDate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "brief.yaml")
+	if err := os.WriteFile(path, []byte("report:\n  title: T\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetSnoozeUntil(path, "next week"); err == nil {
+		t.Error("expected error for invalid date")
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "report:\n  title: T\n" {
+		t.Errorf("file changed despite invalid date:\n%s", data)
+	}
+}
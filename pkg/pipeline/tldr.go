@@ -0,0 +1,42 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jcadam/burrow/pkg/synthesis"
+)
+
+// addTLDR pins a short executive summary of the synthesized report under
+// its title (report.tldr). The summary comes from a second LLM call over the
+// finished report. Without an LLM, or if the call fails, the report is kept
+// as is and a warning is printed.
+func (e *Executor) addTLDR(ctx context.Context, markdown string) string {
+	s, ok := e.synthesizer.(synthesis.Summarizer)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "warning: report.tldr needs an LLM; skipping the TL;DR\n")
+		return markdown
+	}
+	summary, err := s.Summarize(ctx, markdown)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: writing TL;DR: %v (report saved without one)\n", err)
+		return markdown
+	}
+	return insertTLDR(markdown, summary)
+}
+
+// insertTLDR puts summary under a "## TL;DR" heading below the markdown's
+// level 1 title, or at the top when there is none.
+func insertTLDR(markdown, summary string) string {
+	section := "## TL;DR\n\n" + strings.TrimRight(summary, "\n") + "\n\n"
+	lines := strings.Split(markdown, "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, "# ") {
+			rest := strings.TrimLeft(strings.Join(lines[i+1:], "\n"), "\n")
+			return strings.Join(lines[:i+1], "\n") + "\n\n" + section + rest
+		}
+	}
+	return section + markdown
+}
@@ -0,0 +1,70 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+// summarizingSynthesizer is a synthesizer with an LLM behind it, for
+// report.tldr.
+type summarizingSynthesizer struct {
+	summary string
+	err     error
+	input   string
+}
+
+func (s *summarizingSynthesizer) Synthesize(_ context.Context, title string, _ string, _ []*services.Result) (string, error) {
+	return "# " + title + "\n\n## Contracts\n\nTwo awarded.\n", nil
+}
+
+func (s *summarizingSynthesizer) Summarize(_ context.Context, report string) (string, error) {
+	s.input = report
+	return s.summary, s.err
+}
+
+func TestExecutorTLDR(t *testing.T) {
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "test-api", response: []byte(`{"data": "value"}`)})
+	routine := &Routine{
+		Name:    "tldr-test",
+		Report:  ReportConfig{Title: "Brief", TLDR: boolPtr(true)},
+		Sources: []SourceConfig{{Service: "test-api", Tool: "fetch"}},
+	}
+
+	synth := &summarizingSynthesizer{summary: "- Two contracts awarded\n- Nothing else changed\n"}
+	report, err := NewExecutor(reg, synth, t.TempDir()).Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !strings.HasPrefix(synth.input, "# Brief\n\n## Contracts") {
+		t.Errorf("summarized %q, want the synthesized report", synth.input)
+	}
+	want := "# Brief\n\n*Coverage: 1/1 sources (100%)*\n\n## TL;DR\n\n- Two contracts awarded\n- Nothing else changed\n\n## Contracts"
+	if !strings.HasPrefix(report.Markdown, want) {
+		t.Errorf("report = %q, want it to start with %q", report.Markdown, want)
+	}
+
+	// A failed summary leaves the report without one.
+	synth = &summarizingSynthesizer{err: fmt.Errorf("LLM timeout")}
+	report, err = NewExecutor(reg, synth, t.TempDir()).Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	saved, _ := os.ReadFile(filepath.Join(report.Dir, "report.md"))
+	if strings.Contains(string(saved), "TL;DR") {
+		t.Errorf("failed summary should leave no TL;DR, got %q", saved)
+	}
+}
+
+func TestInsertTLDR(t *testing.T) {
+	got := insertTLDR("Intro line.\n", "- One\n")
+	if got != "## TL;DR\n\n- One\n\nIntro line.\n" {
+		t.Errorf("insertTLDR without title = %q", got)
+	}
+}
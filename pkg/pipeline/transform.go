@@ -0,0 +1,73 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/itchyny/gojq"
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+// transformExpr returns the jq expression that reshapes the source's
+// response: its transform, or its jsonpath compiled to jq. Empty means the
+// data is used as returned.
+func (s SourceConfig) transformExpr() (string, error) {
+	if s.JSONPath != "" {
+		return jsonPathToJQ(s.JSONPath)
+	}
+	return s.Transform, nil
+}
+
+// applyTransform replaces a successful result's data with the output of the
+// source's jq transform or jsonpath. Raw results are persisted before this
+// runs, so the transform only shapes what synthesis sees. On any error the
+// raw data is kept and a warning is printed.
+func applyTransform(ctx context.Context, src SourceConfig, r *services.Result) {
+	expr, _ := src.transformExpr() // a bad jsonpath is rejected when the routine loads
+	if expr == "" || r == nil || r.Error != "" || len(r.Data) == 0 {
+		return
+	}
+	out, err := runTransform(ctx, expr, r.Data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: transform for %s/%s: %v (using raw data)\n", src.Service, src.Tool, err)
+		return
+	}
+	r.Data = out
+}
+
+// runTransform evaluates a jq expression against JSON data. A single output
+// is returned as-is; several outputs are collected into a JSON array.
+func runTransform(ctx context.Context, expr string, data []byte) ([]byte, error) {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing: %w", err)
+	}
+
+	var input any
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, fmt.Errorf("response is not JSON: %w", err)
+	}
+
+	var outputs []any
+	iter := query.RunWithContext(ctx, input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			return nil, err
+		}
+		outputs = append(outputs, v)
+	}
+
+	if len(outputs) == 1 {
+		return json.Marshal(outputs[0])
+	}
+	if outputs == nil {
+		outputs = []any{}
+	}
+	return json.Marshal(outputs)
+}
@@ -0,0 +1,86 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+func TestRunTransform(t *testing.T) {
+	data := []byte(`{"items": [{"title": "A", "score": 3, "noise": "x"}, {"title": "B", "score": 5, "noise": "y"}]}`)
+
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"single output", "[.items[] | {title, score}]", `[{"score":3,"title":"A"},{"score":5,"title":"B"}]`},
+		{"multiple outputs collected", ".items[] | .title", `["A","B"]`},
+		{"derived field", "[.items[].score] | add", `8`},
+		{"no output", ".items[] | select(.score > 10)", `[]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := runTransform(context.Background(), tt.expr, data)
+			if err != nil {
+				t.Fatalf("runTransform: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunTransformErrors(t *testing.T) {
+	if _, err := runTransform(context.Background(), ".a", []byte("<html>")); err == nil {
+		t.Error("expected error for non-JSON input")
+	}
+	if _, err := runTransform(context.Background(), ".a.b", []byte(`{"a": 1}`)); err == nil {
+		t.Error("expected runtime error indexing a number")
+	}
+}
+
+func TestExecutorTransformKeepsRawData(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+
+	reg := services.NewRegistry()
+	reg.Register(&mockService{name: "api", response: []byte(`{"items": [{"title": "A", "noise": "x"}]}`)})
+
+	synth := &capturingSynthesizer{}
+	exec := NewExecutor(reg, synth, reportsDir)
+	routine := &Routine{
+		Name:   "shaped",
+		Report: ReportConfig{Title: "Shaped", GenerateCharts: boolPtr(false)},
+		Sources: []SourceConfig{
+			{Service: "api", Tool: "list", Transform: "[.items[].title]"},
+			{Service: "api", Tool: "broken", Transform: ".items.title"},
+		},
+	}
+
+	report, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if got := string(synth.results[0].Data); got != `["A"]` {
+		t.Errorf("transformed data = %s, want [\"A\"]", got)
+	}
+	// A failing transform falls back to the raw response.
+	if got := string(synth.results[1].Data); got != `{"items": [{"title": "A", "noise": "x"}]}` {
+		t.Errorf("fallback data = %s, want raw response", got)
+	}
+
+	// The data/ directory keeps the untransformed response.
+	raw, err := os.ReadFile(filepath.Join(report.Dir, "data", "0-api-list.json"))
+	if err != nil {
+		t.Fatalf("reading raw result: %v", err)
+	}
+	if string(raw) != `{"items": [{"title": "A", "noise": "x"}]}` {
+		t.Errorf("raw data = %s, want untransformed response", raw)
+	}
+}
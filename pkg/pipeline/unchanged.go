@@ -0,0 +1,206 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+// UnchangedError is returned by Run when a routine with skip_unchanged
+// fetched the same data as its last report and no report was written.
+type UnchangedError struct {
+	Since  time.Time // when the last report was generated
+	Report string    // that report's directory
+}
+
+func (e *UnchangedError) Error() string {
+	return fmt.Sprintf("no changes since %s (%s)", e.Since.Local().Format("2006-01-02 15:04"), filepath.Base(e.Report))
+}
+
+// Unchanged returns the UnchangedError in err's chain, if any.
+func Unchanged(err error) (*UnchangedError, bool) {
+	var ue *UnchangedError
+	if errors.As(err, &ue) {
+		return ue, true
+	}
+	return nil, false
+}
+
+// dataHash hashes the data a run would synthesize: each source's result
+// after its transform and max_items, with JSON re-encoded so key order and
+// whitespace don't count as changes. It returns "" when any source failed,
+// since a failure is never "nothing changed".
+func dataHash(routine *Routine, results []*services.Result) string {
+	h := sha256.New()
+	for i, src := range routine.Sources {
+		if i >= len(results) || results[i] == nil || results[i].Error != "" {
+			return ""
+		}
+		r := results[i]
+		data := r.Data
+		var v any
+		if err := json.Unmarshal(data, &v); err == nil {
+			if normalized, err := json.Marshal(v); err == nil {
+				data = normalized
+			}
+		}
+		fmt.Fprintf(h, "%d\x00%s\x00%s\x00%t\x00%d\x00", i, src.Service, src.Tool, r.Empty, len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// unchangedSince returns the record of the routine's last report when it
+// was built from data hashing to hash.
+func (e *Executor) unchangedSince(routine *Routine, hash string) (HashRecord, bool) {
+	if e.hashes == nil || hash == "" {
+		return HashRecord{}, false
+	}
+	last, ok, err := e.hashes.Load(routine.Name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: loading data hashes: %v\n", err)
+		return HashRecord{}, false
+	}
+	if !ok || last.Hash != hash {
+		return HashRecord{}, false
+	}
+	return last, true
+}
+
+// recordHash remembers the data hash behind a routine's new report.
+// Failures only print a warning.
+func (e *Executor) recordHash(routine *Routine, hash string, report *reports.Report) {
+	if e.hashes == nil || hash == "" {
+		return
+	}
+	rec := HashRecord{Hash: hash, Report: report.Dir, Time: time.Now().UTC()}
+	if err := e.hashes.Save(routine.Name, rec); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: saving data hash: %v\n", err)
+	}
+}
+
+// skipUnchanged handles a run whose data matches the last report's: with
+// unchanged_note it writes a one-line report saying so, and otherwise
+// returns an UnchangedError without writing anything.
+func (e *Executor) skipUnchanged(ctx context.Context, routine *Routine, last HashRecord) (*reports.Report, error) {
+	unchanged := &UnchangedError{Since: last.Time, Report: last.Report}
+	if !routine.UnchangedNote {
+		return nil, unchanged
+	}
+	_, title := e.synthesisPrompts(routine, e.templateFuncs(routine), nil)
+	dir, err := reports.Create(e.reportsDir, routine.Name, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating report: %w", err)
+	}
+	markdown := fmt.Sprintf("# %s\n\nNo changes since %s (%s).\n", title,
+		last.Time.Local().Format("2006-01-02 15:04"), filepath.Base(last.Report))
+	report, err := reports.Finish(dir, routine.Name, markdown)
+	if err != nil {
+		return nil, fmt.Errorf("saving report: %w", err)
+	}
+	saveHTML(routine, report)
+	e.emit(routine, Event{Type: EventReportSaved, Report: report.Dir})
+	return report, nil
+}
+
+// HashRecord is the data hash behind a routine's last report.
+type HashRecord struct {
+	Hash   string    `json:"hash"`
+	Report string    `json:"report"` // report directory
+	Time   time.Time `json:"time"`
+}
+
+// HashStore persists each routine's last HashRecord to a JSON file keyed
+// by routine name.
+type HashStore struct {
+	path string
+	mu   sync.Mutex // serializes load→modify→save
+}
+
+// NewHashStore creates a HashStore backed by the JSON file at path.
+func NewHashStore(path string) *HashStore {
+	return &HashStore{path: path}
+}
+
+// Load returns the routine's record, and false if it has none.
+func (s *HashStore) Load(routine string) (HashRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readAll()
+	if err != nil {
+		return HashRecord{}, false, err
+	}
+	rec, ok := all[routine]
+	return rec, ok, nil
+}
+
+// Save replaces the routine's record and writes the file atomically via
+// temp+rename.
+func (s *HashStore) Save(routine string, rec HashRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readAll()
+	if err != nil {
+		return err
+	}
+	all[routine] = rec
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling hashes: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating hashes directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "hashes-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming hashes file: %w", err)
+	}
+	return nil
+}
+
+func (s *HashStore) readAll() (map[string]HashRecord, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]HashRecord), nil
+		}
+		return nil, fmt.Errorf("reading hashes file: %w", err)
+	}
+	var all map[string]HashRecord
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("parsing hashes file: %w", err)
+	}
+	if all == nil {
+		all = make(map[string]HashRecord)
+	}
+	return all, nil
+}
@@ -0,0 +1,99 @@
+package pipeline
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/jcadam/burrow/pkg/services"
+	"github.com/jcadam/burrow/pkg/synthesis"
+)
+
+func TestDataHash(t *testing.T) {
+	routine := &Routine{Sources: []SourceConfig{{Service: "api", Tool: "list"}}}
+	hash := func(data string) string {
+		return dataHash(routine, []*services.Result{{Service: "api", Tool: "list", Data: []byte(data)}})
+	}
+
+	a := hash(`{"items": [1, 2], "total": 2}`)
+	if a == "" {
+		t.Fatal("expected a hash")
+	}
+	if b := hash("{\n  \"total\": 2,\n  \"items\": [1, 2]\n}"); b != a {
+		t.Error("key order and whitespace should not change the hash")
+	}
+	if c := hash(`{"items": [1, 2, 3], "total": 3}`); c == a {
+		t.Error("different data should change the hash")
+	}
+	if got := dataHash(routine, []*services.Result{{Service: "api", Tool: "list", Error: "timeout"}}); got != "" {
+		t.Errorf("failed source should give no hash, got %q", got)
+	}
+}
+
+func TestExecutorSkipUnchanged(t *testing.T) {
+	dir := t.TempDir()
+	reportsDir := filepath.Join(dir, "reports")
+	svc := &mockService{name: "api", response: []byte(`{"status": "green"}`)}
+	reg := services.NewRegistry()
+	reg.Register(svc)
+	synth := &countingSynthesizer{}
+	exec := NewExecutor(reg, synth, reportsDir)
+	exec.SetHashStore(NewHashStore(filepath.Join(dir, "source-hashes.json")))
+
+	routine := &Routine{
+		Name:          "watch",
+		Report:        ReportConfig{Title: "Watch", GenerateCharts: boolPtr(false)},
+		Sources:       []SourceConfig{{Service: "api", Tool: "status"}},
+		SkipUnchanged: true,
+	}
+
+	first, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("first Run: %v", err)
+	}
+
+	_, err = exec.Run(context.Background(), routine)
+	unchanged, ok := Unchanged(err)
+	if !ok {
+		t.Fatalf("second Run error = %v, want UnchangedError", err)
+	}
+	if unchanged.Report != first.Dir {
+		t.Errorf("unchanged since report %s, want %s", unchanged.Report, first.Dir)
+	}
+	if synth.calls != 1 {
+		t.Errorf("synthesis calls = %d, want 1", synth.calls)
+	}
+	if all, _ := reports.List(reportsDir); len(all) != 1 {
+		t.Errorf("reports on disk = %d, want 1", len(all))
+	}
+
+	routine.UnchangedNote = true
+	note, err := exec.Run(context.Background(), routine)
+	if err != nil {
+		t.Fatalf("Run with unchanged_note: %v", err)
+	}
+	if !strings.Contains(note.Markdown, "# Watch") || !strings.Contains(note.Markdown, "No changes since") {
+		t.Errorf("unexpected note report:\n%s", note.Markdown)
+	}
+	if synth.calls != 1 {
+		t.Errorf("synthesis calls = %d, want 1", synth.calls)
+	}
+
+	svc.response = []byte(`{"status": "red"}`)
+	if _, err := exec.Run(context.Background(), routine); err != nil {
+		t.Fatalf("Run with changed data: %v", err)
+	}
+	if synth.calls != 2 {
+		t.Errorf("changed data should be synthesized; calls = %d", synth.calls)
+	}
+}
+
+// countingSynthesizer passes results through and counts its calls.
+type countingSynthesizer struct{ calls int }
+
+func (c *countingSynthesizer) Synthesize(ctx context.Context, title, system string, results []*services.Result) (string, error) {
+	c.calls++
+	return synthesis.NewPassthroughSynthesizer().Synthesize(ctx, title, system, results)
+}
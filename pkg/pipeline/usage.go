@@ -0,0 +1,51 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/reports"
+	"github.com/jcadam/burrow/pkg/synthesis"
+)
+
+// usageMeter totals a run's LLM usage as providers report it.
+type usageMeter struct {
+	mu    sync.Mutex
+	usage reports.Usage
+}
+
+// meterUsage returns a context under which the LLM calls made for ctx are
+// counted in a new meter.
+func meterUsage(ctx context.Context) (context.Context, *usageMeter) {
+	m := &usageMeter{}
+	return synthesis.WithUsage(ctx, m.record), m
+}
+
+func (m *usageMeter) record(provider string, prompt, completion int, cost float64) {
+	if provider == "" {
+		provider = "unknown"
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage.Record(provider, reports.TokenUsage{Calls: 1, PromptTokens: prompt, CompletionTokens: completion, Cost: cost})
+}
+
+// save adds the metered usage to the report directory's usage.json. A run
+// that made no LLM calls writes nothing. Like stashing values, it is
+// best-effort: a failure is only warned about.
+func (m *usageMeter) save(reportDir string, routine *Routine) {
+	m.mu.Lock()
+	u := m.usage
+	m.mu.Unlock()
+	if u.Total.Calls == 0 || reportDir == "" {
+		return
+	}
+	u.Routine = routine.Name
+	u.Updated = time.Now().UTC()
+	if err := reports.AddUsage(reportDir, u); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: saving LLM usage: %v\n", err)
+	}
+}
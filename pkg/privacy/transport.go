@@ -68,7 +68,11 @@ func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	if t.config.MinimizeRequests {
 		r.Header.Del("X-Requested-With")
 		r.Header.Del("DNT")
-		r.Header.Set("Accept", "*/*")
+		// A Server-Sent Events endpoint only answers a request that asks
+		// for an event stream, so that Accept is part of the protocol.
+		if r.Header.Get("Accept") != "text/event-stream" {
+			r.Header.Set("Accept", "*/*")
+		}
 	}
 
 	return t.base.RoundTrip(r)
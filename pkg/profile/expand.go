@@ -2,13 +2,18 @@ package profile
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
 	"text/template"
 	"time"
+	_ "time/tzdata" // embedded timezone database for zone helpers on minimal systems
 )
 
+// timeNow is the clock for date helpers; tests replace it.
+var timeNow = time.Now
+
 // legacySyntax matches {{profile.field_name}} and {{ profile.field_name }}
 // (with optional spaces) for backward-compatible conversion to Go template
 // function call syntax: {{profile "field_name"}}.
@@ -44,17 +49,57 @@ func (tc *templateContext) profileFunc(key string) string {
 	return val
 }
 
+// inZone returns t in the IANA time zone named by the first of zone, or t
+// unchanged when none is given.
+func inZone(t time.Time, zone []string) (time.Time, error) {
+	if len(zone) == 0 {
+		return t, nil
+	}
+	if len(zone) > 1 {
+		return t, fmt.Errorf("expected one time zone, got %d", len(zone))
+	}
+	loc, err := time.LoadLocation(zone[0])
+	if err != nil {
+		return t, fmt.Errorf("unknown time zone %q", zone[0])
+	}
+	return t.In(loc), nil
+}
+
 // buildFuncMap returns the template.FuncMap with all built-in functions.
+// today, yesterday, and now take an optional IANA zone ("Asia/Tokyo") and
+// otherwise use the local zone.
 func buildFuncMap(tc *templateContext) template.FuncMap {
-	now := time.Now()
+	now := timeNow()
 	return template.FuncMap{
-		"profile":   tc.profileFunc,
-		"today":     func() string { return now.Format("2006-01-02") },
-		"yesterday": func() string { return now.AddDate(0, 0, -1).Format("2006-01-02") },
-		"now":       func() string { return now.Format(time.RFC3339) },
-		"year":      func() string { return now.Format("2006") },
-		"month":     func() string { return now.Format("01") },
-		"day":       func() string { return now.Format("02") },
+		"profile": tc.profileFunc,
+		"today": func(zone ...string) (string, error) {
+			t, err := inZone(now, zone)
+			return t.Format("2006-01-02"), err
+		},
+		"yesterday": func(zone ...string) (string, error) {
+			t, err := inZone(now, zone)
+			return t.AddDate(0, 0, -1).Format("2006-01-02"), err
+		},
+		"since": func() string { return now.AddDate(0, 0, -1).Format("2006-01-02") }, // start of the collection window; overridden for catch-up runs
+		"now": func(zone ...string) (string, error) {
+			t, err := inZone(now, zone)
+			return t.Format(time.RFC3339), err
+		},
+		// inZone converts a timestamp (RFC 3339, or a YYYY-MM-DD date taken
+		// as midnight UTC) to zone: {{inZone "Asia/Tokyo" now}}.
+		"inZone": func(zone, ts string) (string, error) {
+			t, err := time.Parse(time.RFC3339, ts)
+			if err != nil {
+				if t, err = time.Parse("2006-01-02", ts); err != nil {
+					return "", fmt.Errorf("inZone: cannot parse time %q", ts)
+				}
+			}
+			t, err = inZone(t, []string{zone})
+			return t.Format(time.RFC3339), err
+		},
+		"year":  func() string { return now.Format("2006") },
+		"month": func() string { return now.Format("01") },
+		"day":   func() string { return now.Format("02") },
 		"date": func(layout, dateStr string) string {
 			for _, parseLayout := range []string{"2006-01-02", time.RFC3339, "01/02/2006"} {
 				if t, err := time.Parse(parseLayout, dateStr); err == nil {
@@ -82,12 +127,32 @@ func Expand(text string, p *Profile) (string, error) {
 	if p == nil || text == "" {
 		return text, nil
 	}
+	return expand(text, p, nil)
+}
 
+// ExpandWith is like Expand but merges extra template functions over the
+// built-ins (e.g. a per-run "since" date). Unlike Expand, it still expands
+// when the profile is nil so non-profile helpers resolve; profile references
+// are then left as-is. With no extra functions it behaves exactly like Expand.
+func ExpandWith(text string, p *Profile, extra template.FuncMap) (string, error) {
+	if len(extra) == 0 {
+		return Expand(text, p)
+	}
+	if text == "" {
+		return text, nil
+	}
+	return expand(text, p, extra)
+}
+
+func expand(text string, p *Profile, extra template.FuncMap) (string, error) {
 	// Convert legacy syntax before Go template parsing.
 	converted := convertLegacySyntax(text)
 
 	tc := &templateContext{profile: p}
 	fm := buildFuncMap(tc)
+	for name, fn := range extra {
+		fm[name] = fn
+	}
 
 	tmpl, err := template.New("expand").Funcs(fm).Parse(converted)
 	if err != nil {
@@ -95,17 +160,26 @@ func Expand(text string, p *Profile) (string, error) {
 		return legacyExpand(text, p)
 	}
 
+	// Check references against the profile schema before execution so a
+	// missing or mis-shaped field gets a clear error, not a template failure.
+	var schemaErr error
+	if p != nil && len(p.Schema) > 0 {
+		schemaErr = p.Schema.checkRefs(tmpl.Tree.Root, p)
+	}
+
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, nil); err != nil {
-		// Execution error — fall back to legacy regex expander.
-		return legacyExpand(text, p)
+		// Execution error — fall back to legacy regex expander, and report
+		// the error so a bad helper argument (an unknown zone) is visible.
+		result, legacyErr := legacyExpand(text, p)
+		return result, errors.Join(schemaErr, err, legacyErr)
 	}
 
 	result := buf.String()
 	if len(tc.unresolved) > 0 {
-		return result, fmt.Errorf("unresolved profile fields: %s", strings.Join(tc.unresolved, ", "))
+		return result, errors.Join(schemaErr, fmt.Errorf("unresolved profile fields: %s", strings.Join(tc.unresolved, ", ")))
 	}
-	return result, nil
+	return result, schemaErr
 }
 
 // legacyProfilePattern matches {{profile.field_name}} references (no spaces).
@@ -145,7 +219,12 @@ func legacyExpand(text string, p *Profile) (string, error) {
 // Returns a new map — the original is not modified (goroutine safety).
 // Nil-safe: returns the original map unchanged when profile is nil.
 func ExpandParams(params map[string]string, p *Profile) (map[string]string, error) {
-	if p == nil || len(params) == 0 {
+	return ExpandParamsWith(params, p, nil)
+}
+
+// ExpandParamsWith is ExpandParams with extra template functions (see ExpandWith).
+func ExpandParamsWith(params map[string]string, p *Profile, extra template.FuncMap) (map[string]string, error) {
+	if (p == nil && len(extra) == 0) || len(params) == 0 {
 		return params, nil
 	}
 
@@ -153,7 +232,7 @@ func ExpandParams(params map[string]string, p *Profile) (map[string]string, erro
 	var allUnresolved []string
 
 	for k, v := range params {
-		val, err := Expand(v, p)
+		val, err := ExpandWith(v, p, extra)
 		expanded[k] = val
 		if err != nil {
 			allUnresolved = append(allUnresolved, err.Error())
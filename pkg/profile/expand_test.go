@@ -238,6 +238,51 @@ func TestExpandYesterday(t *testing.T) {
 	}
 }
 
+func TestExpandDatesInZone(t *testing.T) {
+	defer func() { timeNow = time.Now }()
+
+	tests := []struct {
+		name, now, text, want string
+	}{
+		// 23:30 UTC is already tomorrow in Tokyo and still today in New York.
+		{"tokyo ahead", "2026-03-07T23:30:00Z", `{{today "Asia/Tokyo"}}`, "2026-03-08"},
+		{"new york behind", "2026-03-08T03:30:00Z", `{{today "America/New_York"}}`, "2026-03-07"},
+		{"yesterday in tokyo", "2026-03-07T23:30:00Z", `{{yesterday "Asia/Tokyo"}}`, "2026-03-07"},
+		// US clocks spring forward at 02:00 on 2026-03-08: 06:30Z is 01:30 EST,
+		// 07:30Z is 03:30 EDT.
+		{"before DST", "2026-03-08T06:30:00Z", `{{now "America/New_York"}}`, "2026-03-08T01:30:00-05:00"},
+		{"after DST", "2026-03-08T07:30:00Z", `{{now "America/New_York"}}`, "2026-03-08T03:30:00-04:00"},
+		{"yesterday across DST", "2026-03-09T04:30:00Z", `{{yesterday "America/New_York"}}`, "2026-03-08"},
+		// Clocks fall back on 2026-11-01; 06:30Z is the second 01:30 that day.
+		{"fall back", "2026-11-01T06:30:00Z", `{{now "America/New_York"}}`, "2026-11-01T01:30:00-05:00"},
+		{"inZone now", "2026-12-31T16:00:00Z", `{{inZone "Asia/Tokyo" now}}`, "2027-01-01T01:00:00+09:00"},
+		{"inZone date", "2026-01-01T00:00:00Z", `{{date "2006-01-02" (inZone "America/Los_Angeles" "2026-06-01")}}`, "2026-05-31"},
+		{"utc", "2026-03-07T23:30:00Z", `{{today "UTC"}}`, "2026-03-07"},
+	}
+	for _, tt := range tests {
+		at, _ := time.Parse(time.RFC3339, tt.now)
+		timeNow = func() time.Time { return at }
+		got, err := Expand(tt.text, testProfile())
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: %s = %q, want %q", tt.name, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestExpandUnknownZone(t *testing.T) {
+	result, err := Expand(`d={{today "Mars/Olympus"}}`, testProfile())
+	if err == nil || !strings.Contains(err.Error(), `unknown time zone "Mars/Olympus"`) {
+		t.Errorf("expected unknown zone error, got %v", err)
+	}
+	if result != `d={{today "Mars/Olympus"}}` {
+		t.Errorf("expected the reference left as-is, got %q", result)
+	}
+}
+
 func TestExpandDateComponents(t *testing.T) {
 	result, err := Expand("{{year}}-{{month}}-{{day}}", testProfile())
 	if err != nil {
@@ -362,3 +407,41 @@ func TestExpandStringFuncs(t *testing.T) {
 		t.Errorf("got %q, want %q", result, "trivyn")
 	}
 }
+
+func TestExpandSinceDefaultsToYesterday(t *testing.T) {
+	got, err := Expand(`{{since}}`, testProfile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestExpandWithOverridesBuiltin(t *testing.T) {
+	extra := map[string]any{"since": func() string { return "2025-01-10" }}
+	got, err := ExpandWith(`from {{since}} for {{profile "name"}}`, testProfile(), extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "from 2025-01-10 for Trivyn" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestExpandWithNilProfile(t *testing.T) {
+	extra := map[string]any{"since": func() string { return "2025-01-10" }}
+	got, err := ExpandWith(`{{since}}`, nil, extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "2025-01-10" {
+		t.Errorf("got %q, want %q", got, "2025-01-10")
+	}
+
+	// Without extra functions, a nil profile is a no-op like Expand.
+	if got, _ := ExpandWith(`{{since}}`, nil, nil); got != `{{since}}` {
+		t.Errorf("got %q, want unchanged text", got)
+	}
+}
@@ -28,6 +28,10 @@ type Profile struct {
 	// Raw holds every field from the YAML including the typed ones above.
 	// This is the source of truth for template expansion.
 	Raw map[string]interface{} `yaml:"-"`
+
+	// Schema is the optional field declaration from profile-schema.yaml.
+	// Nil when no schema file exists.
+	Schema Schema `yaml:"-"`
 }
 
 const filename = "profile.yaml"
@@ -57,11 +61,14 @@ func Load(burrowDir string) (*Profile, error) {
 	}
 	p.Raw = raw
 
+	p.Schema = loadSchemaOrWarn(burrowDir)
+
 	return &p, nil
 }
 
 // Save writes the profile to burrowDir/profile.yaml. It marshals the
 // Raw map to preserve user-defined fields that aren't in the typed struct.
+// When a valid profile schema exists, the profile must satisfy it.
 func Save(burrowDir string, p *Profile) error {
 	if err := os.MkdirAll(burrowDir, 0o755); err != nil {
 		return fmt.Errorf("creating burrow directory: %w", err)
@@ -84,6 +91,15 @@ func Save(burrowDir string, p *Profile) error {
 		raw["interests"] = p.Interests
 	}
 
+	// Validate against the schema (if any) before writing.
+	schema := p.Schema
+	if schema == nil {
+		schema = loadSchemaOrWarn(burrowDir)
+	}
+	if err := schema.Validate(&Profile{Raw: raw}); err != nil {
+		return fmt.Errorf("profile does not match schema: %w", err)
+	}
+
 	data, err := yaml.Marshal(raw)
 	if err != nil {
 		return fmt.Errorf("marshaling profile: %w", err)
@@ -127,6 +143,34 @@ func (p *Profile) Get(key string) (string, bool) {
 	return formatValue(current), true
 }
 
+// Only returns a copy of the profile limited to the named top-level fields.
+// With no fields it returns p itself. Nil-safe: returns nil for a nil profile.
+func (p *Profile) Only(fields []string) *Profile {
+	if p == nil || len(fields) == 0 {
+		return p
+	}
+	keep := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		keep[f] = true
+	}
+	out := &Profile{Raw: make(map[string]interface{}, len(fields)), Schema: p.Schema}
+	for key, val := range p.Raw {
+		if keep[key] {
+			out.Raw[key] = val
+		}
+	}
+	if keep["name"] {
+		out.Name = p.Name
+	}
+	if keep["description"] {
+		out.Description = p.Description
+	}
+	if keep["interests"] {
+		out.Interests = p.Interests
+	}
+	return out
+}
+
 // GetList returns a string slice for the given key. Returns (nil, false)
 // for missing keys or non-list values.
 func (p *Profile) GetList(key string) ([]string, bool) {
@@ -213,6 +213,40 @@ func TestGetNestedMissing(t *testing.T) {
 	}
 }
 
+func TestOnly(t *testing.T) {
+	p := &Profile{
+		Name:      "Trivyn",
+		Interests: []string{"geospatial"},
+		Raw: map[string]interface{}{
+			"name":        "Trivyn",
+			"interests":   []interface{}{"geospatial"},
+			"naics_codes": []interface{}{"541370"},
+		},
+	}
+
+	only := p.Only([]string{"interests"})
+	if _, ok := only.Get("name"); ok || only.Name != "" {
+		t.Error("expected name dropped")
+	}
+	if val, ok := only.Get("interests"); !ok || val != "geospatial" {
+		t.Errorf("Get(interests) = (%q, %v)", val, ok)
+	}
+	if len(only.Interests) != 1 {
+		t.Errorf("Interests = %v, want kept", only.Interests)
+	}
+	if len(p.Raw) != 3 {
+		t.Error("Only must not modify the original profile")
+	}
+
+	if p.Only(nil) != p {
+		t.Error("no fields should return the profile itself")
+	}
+	var nilProf *Profile
+	if nilProf.Only([]string{"name"}) != nil {
+		t.Error("expected nil for a nil profile")
+	}
+}
+
 func TestSaveFromTypedFieldsOnly(t *testing.T) {
 	dir := t.TempDir()
 
@@ -0,0 +1,262 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template/parse"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldType is the declared shape of a profile field.
+type FieldType string
+
+const (
+	TypeString FieldType = "string"
+	TypeList   FieldType = "list"
+	TypeMap    FieldType = "map"
+)
+
+// Schema declares the profile fields routines depend on and their types.
+// It is optional and lives in ~/.burrow/profile-schema.yaml:
+//
+//	name: string
+//	competitors: list
+//	coordinates: map
+//
+// Every declared field is expected to be present in the profile.
+type Schema map[string]FieldType
+
+const schemaFilename = "profile-schema.yaml"
+
+// LoadSchema reads burrowDir/profile-schema.yaml.
+// Returns (nil, nil) when the file does not exist — the schema is optional.
+func LoadSchema(burrowDir string) (Schema, error) {
+	data, err := os.ReadFile(filepath.Join(burrowDir, schemaFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading profile schema: %w", err)
+	}
+
+	var s Schema
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing profile schema: %w", err)
+	}
+	for field, typ := range s {
+		switch typ {
+		case TypeString, TypeList, TypeMap:
+			// valid
+		default:
+			return nil, fmt.Errorf("profile schema field %q has unknown type %q (must be string, list, or map)", field, typ)
+		}
+	}
+	return s, nil
+}
+
+// loadSchemaOrWarn loads the schema for use alongside the profile. A
+// malformed schema only produces a warning: it is an optional check, and a
+// typo in it shouldn't stop routines that read the profile from running.
+func loadSchemaOrWarn(burrowDir string) Schema {
+	s, err := LoadSchema(burrowDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: ignoring %s: %v\n", schemaFilename, err)
+		return nil
+	}
+	return s
+}
+
+// Validate checks that every declared field is present in the profile with
+// the declared type. All problems are reported, sorted by field name.
+func (s Schema) Validate(p *Profile) error {
+	var raw map[string]interface{}
+	if p != nil {
+		raw = p.Raw
+	}
+
+	var errs []error
+	for _, field := range s.fields() {
+		if err := s.checkField(raw, field); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkField validates a single declared field. Fields not in the schema
+// always pass.
+func (s Schema) checkField(raw map[string]interface{}, field string) error {
+	want, declared := s[field]
+	if !declared {
+		return nil
+	}
+	val, ok := raw[field]
+	if !ok {
+		return fmt.Errorf("profile field '%s' expected but missing", field)
+	}
+	if got := typeOf(val); got != want {
+		return fmt.Errorf("profile field '%s' expected %s, got %s", field, want, got)
+	}
+	return nil
+}
+
+// CheckTemplate parses text as a template and validates every {{profile "x"}}
+// reference against the schema, so a routine referencing a field that was
+// never set fails with a clear message instead of a confusing template error.
+// Nested references ("coordinates.latitude") are checked by their top-level
+// field. Returns nil when the schema is empty or the text isn't a template.
+func (s Schema) CheckTemplate(text string, p *Profile) error {
+	if len(s) == 0 || text == "" {
+		return nil
+	}
+
+	// SkipFuncCheck: only the profile references matter here, so templates
+	// using per-run helpers (e.g. lastValue) still parse.
+	tree := parse.New("check")
+	tree.Mode = parse.SkipFuncCheck
+	trees := make(map[string]*parse.Tree)
+	if _, err := tree.Parse(convertLegacySyntax(text), "", "", trees); err != nil {
+		return nil // not a valid template — Expand falls back to legacy handling
+	}
+
+	var errs []error
+	for _, tree := range trees {
+		if err := s.checkRefs(tree.Root, p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkRefs validates the profile references in a parsed template tree.
+func (s Schema) checkRefs(root parse.Node, p *Profile) error {
+	var raw map[string]interface{}
+	if p != nil {
+		raw = p.Raw
+	}
+
+	seen := make(map[string]bool)
+	var errs []error
+	walkProfileRefs(root, func(key string) {
+		field := topLevelField(key)
+		if seen[field] {
+			return
+		}
+		seen[field] = true
+		if err := s.checkField(raw, field); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// Skeleton returns a raw profile map with an empty value for every declared
+// field, for seeding a new profile that satisfies the schema.
+func (s Schema) Skeleton() map[string]interface{} {
+	raw := make(map[string]interface{}, len(s))
+	for field, typ := range s {
+		switch typ {
+		case TypeList:
+			raw[field] = []interface{}{}
+		case TypeMap:
+			raw[field] = map[string]interface{}{}
+		default:
+			raw[field] = ""
+		}
+	}
+	return raw
+}
+
+// Starter returns the raw map for a new profile: the schema's skeleton, plus
+// empty name, description, and interests fields where the schema doesn't
+// declare them.
+func (s Schema) Starter() map[string]interface{} {
+	raw := s.Skeleton()
+	for field, empty := range map[string]interface{}{
+		"name":        "",
+		"description": "",
+		"interests":   []interface{}{},
+	} {
+		if _, declared := raw[field]; !declared {
+			raw[field] = empty
+		}
+	}
+	return raw
+}
+
+func (s Schema) fields() []string {
+	fields := make([]string, 0, len(s))
+	for f := range s {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// typeOf classifies a raw YAML value. Scalars (numbers, bools) count as strings
+// since templates render them as text.
+func typeOf(val interface{}) FieldType {
+	switch val.(type) {
+	case []interface{}, []string:
+		return TypeList
+	case map[string]interface{}:
+		return TypeMap
+	default:
+		return TypeString
+	}
+}
+
+func topLevelField(key string) string {
+	field, _, _ := strings.Cut(key, ".")
+	return field
+}
+
+// walkProfileRefs calls fn with the key of every {{profile "key"}} call in the tree.
+func walkProfileRefs(node parse.Node, fn func(string)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			walkProfileRefs(c, fn)
+		}
+	case *parse.ActionNode:
+		walkProfileRefs(n.Pipe, fn)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			walkProfileRefs(cmd, fn)
+		}
+	case *parse.CommandNode:
+		if len(n.Args) >= 2 {
+			if id, ok := n.Args[0].(*parse.IdentifierNode); ok && id.Ident == "profile" {
+				if str, ok := n.Args[1].(*parse.StringNode); ok {
+					fn(str.Text)
+				}
+			}
+		}
+		for _, arg := range n.Args {
+			walkProfileRefs(arg, fn)
+		}
+	case *parse.IfNode:
+		walkProfileRefs(n.Pipe, fn)
+		walkProfileRefs(n.List, fn)
+		walkProfileRefs(n.ElseList, fn)
+	case *parse.RangeNode:
+		walkProfileRefs(n.Pipe, fn)
+		walkProfileRefs(n.List, fn)
+		walkProfileRefs(n.ElseList, fn)
+	case *parse.WithNode:
+		walkProfileRefs(n.Pipe, fn)
+		walkProfileRefs(n.List, fn)
+		walkProfileRefs(n.ElseList, fn)
+	}
+}
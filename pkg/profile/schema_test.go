@@ -0,0 +1,157 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSchema(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "profile-schema.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadSchemaMissing(t *testing.T) {
+	s, err := LoadSchema(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != nil {
+		t.Errorf("expected nil schema, got %v", s)
+	}
+}
+
+func TestLoadSchemaUnknownType(t *testing.T) {
+	dir := t.TempDir()
+	writeSchema(t, dir, "coordinates: point\n")
+	if _, err := LoadSchema(dir); err == nil {
+		t.Error("expected error for unknown field type")
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	s := Schema{"name": TypeString, "competitors": TypeList, "coordinates": TypeMap}
+
+	err := s.Validate(testProfile())
+	if err == nil {
+		t.Fatal("expected error: testProfile has no coordinates")
+	}
+	if !strings.Contains(err.Error(), "profile field 'coordinates' expected but missing") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	p := testProfile()
+	p.Raw["coordinates"] = map[string]interface{}{"latitude": 61.2}
+	if err := s.Validate(p); err != nil {
+		t.Errorf("valid profile rejected: %v", err)
+	}
+
+	p.Raw["competitors"] = "Maxar"
+	err = s.Validate(p)
+	if err == nil || !strings.Contains(err.Error(), "profile field 'competitors' expected list, got string") {
+		t.Errorf("expected type mismatch error, got %v", err)
+	}
+}
+
+func TestSaveRejectsProfileNotMatchingSchema(t *testing.T) {
+	dir := t.TempDir()
+	writeSchema(t, dir, "name: string\ncoordinates: map\n")
+
+	err := Save(dir, &Profile{Name: "Trivyn"})
+	if err == nil || !strings.Contains(err.Error(), "coordinates") {
+		t.Fatalf("expected schema error, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(dir, "profile.yaml")); !os.IsNotExist(statErr) {
+		t.Error("invalid profile should not be written")
+	}
+
+	ok := &Profile{Raw: map[string]interface{}{
+		"name":        "Trivyn",
+		"coordinates": map[string]interface{}{"latitude": 61.2},
+	}}
+	if err := Save(dir, ok); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Schema["coordinates"] != TypeMap {
+		t.Errorf("schema not loaded with profile: %v", loaded.Schema)
+	}
+}
+
+func TestSchemaCheckTemplate(t *testing.T) {
+	s := Schema{"coordinates": TypeMap, "competitors": TypeList}
+	p := testProfile()
+
+	err := s.CheckTemplate(`{{profile "coordinates.latitude" | upper}} near {{lastValue "x"}}`, p)
+	if err == nil || !strings.Contains(err.Error(), "profile field 'coordinates' expected but missing") {
+		t.Errorf("expected missing-field error, got %v", err)
+	}
+
+	// Legacy syntax and nested pipelines are checked too.
+	if err := s.CheckTemplate(`{{profile.coordinates}}`, p); err == nil {
+		t.Error("expected error for legacy reference to missing field")
+	}
+	if err := s.CheckTemplate(`{{join "," (split (profile "coordinates") ",")}}`, p); err == nil {
+		t.Error("expected error for nested reference to missing field")
+	}
+
+	// Declared and present, or undeclared: no error.
+	if err := s.CheckTemplate(`{{profile "competitors"}} {{profile "name"}}`, p); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestExpandReportsSchemaErrors(t *testing.T) {
+	p := testProfile()
+	p.Schema = Schema{"coordinates": TypeMap}
+
+	_, err := Expand(`lat={{profile "coordinates.latitude"}}`, p)
+	if err == nil || !strings.Contains(err.Error(), "profile field 'coordinates' expected but missing") {
+		t.Errorf("expected schema error from Expand, got %v", err)
+	}
+}
+
+func TestSchemaSkeleton(t *testing.T) {
+	s := Schema{"name": TypeString, "competitors": TypeList, "coordinates": TypeMap}
+	if err := s.Validate(&Profile{Raw: s.Skeleton()}); err != nil {
+		t.Errorf("skeleton should satisfy its schema: %v", err)
+	}
+}
+
+func TestSchemaStarterKeepsDeclaredTypes(t *testing.T) {
+	s := Schema{"description": TypeMap, "interests": TypeMap, "region": TypeString}
+	raw := s.Starter()
+	if err := s.Validate(&Profile{Raw: raw}); err != nil {
+		t.Errorf("starter should satisfy schema: %v", err)
+	}
+	if raw["name"] != "" {
+		t.Errorf("undeclared name should default to empty string, got %#v", raw["name"])
+	}
+
+	var none Schema
+	raw = none.Starter()
+	if _, ok := raw["interests"].([]interface{}); !ok || raw["name"] != "" || raw["description"] != "" {
+		t.Errorf("starter without schema = %#v", raw)
+	}
+}
+
+func TestLoadMalformedSchemaWarns(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "profile.yaml"), []byte("name: Ada\n"), 0o644)
+	writeSchema(t, dir, "name: number\n")
+
+	p, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load should not fail on a malformed schema: %v", err)
+	}
+	if p.Name != "Ada" || p.Schema != nil {
+		t.Errorf("got name %q, schema %v", p.Name, p.Schema)
+	}
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrBudgetExceeded is returned by Take once a run's request budget is
+// spent.
+var ErrBudgetExceeded = errors.New("run request budget exceeded")
+
+// Budget caps the HTTP requests a single run sends, across all of its
+// services. Unlike a Limiter it never waits: a request over the budget is
+// refused. Services take from it where they wait on their Limiter, so
+// pagination pages and retries count and cached results cost nothing.
+type Budget struct {
+	max     int64
+	used    atomic.Int64
+	refused atomic.Int64
+}
+
+// NewBudget returns a Budget allowing max requests.
+func NewBudget(max int) *Budget {
+	return &Budget{max: int64(max)}
+}
+
+// Max returns the number of requests the budget allows.
+func (b *Budget) Max() int { return int(b.max) }
+
+// Refused returns how many requests were refused for exceeding the budget.
+func (b *Budget) Refused() int { return int(b.refused.Load()) }
+
+type budgetKey struct{}
+
+// WithBudget returns a context whose requests draw on b.
+func WithBudget(ctx context.Context, b *Budget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// Take counts one request against the context's run budget, if it has one,
+// and returns ErrBudgetExceeded once the budget is spent.
+func Take(ctx context.Context) error {
+	b, _ := ctx.Value(budgetKey{}).(*Budget)
+	if b == nil {
+		return nil
+	}
+	if b.used.Add(1) > b.max {
+		b.refused.Add(1)
+		return fmt.Errorf("%w (max %d)", ErrBudgetExceeded, b.max)
+	}
+	return nil
+}
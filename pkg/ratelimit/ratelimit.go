@@ -0,0 +1,81 @@
+// Package ratelimit holds requests to a service within a configured budget,
+// such as 5 requests per minute, by delaying the ones that would exceed it.
+// Services wait on a Limiter before sending each request, outside their HTTP
+// client, so time spent waiting doesn't count against the client's timeout.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter allows at most requests in any window of length per. Requests
+// over the budget wait until the oldest one in the window ages out.
+type Limiter struct {
+	requests int
+	per      time.Duration
+
+	mu   sync.Mutex
+	sent []time.Time // start times of requests in the current window, oldest first
+}
+
+// New returns a Limiter allowing requests per window.
+func New(requests int, per time.Duration) *Limiter {
+	return &Limiter{requests: requests, per: per}
+}
+
+// Wait blocks until a request fits the budget and records it, or returns
+// the context's error if it is done first.
+func (l *Limiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		cutoff := now.Add(-l.per)
+		i := 0
+		for i < len(l.sent) && !l.sent[i].After(cutoff) {
+			i++
+		}
+		l.sent = l.sent[i:]
+		if len(l.sent) < l.requests {
+			l.sent = append(l.sent, now)
+			l.mu.Unlock()
+			return nil
+		}
+		wait := l.sent[0].Sub(cutoff)
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Set keeps one Limiter per service, so a long-running process such as the
+// daemon shares each service's budget across runs and routines.
+type Set struct {
+	mu       sync.Mutex
+	limiters map[string]*Limiter
+}
+
+// NewSet returns an empty Set.
+func NewSet() *Set {
+	return &Set{limiters: make(map[string]*Limiter)}
+}
+
+// Get returns the named service's Limiter, replacing it when the budget
+// has changed since it was made.
+func (s *Set) Get(name string, requests int, per time.Duration) *Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if l, ok := s.limiters[name]; ok && l.requests == requests && l.per == per {
+		return l
+	}
+	l := New(requests, per)
+	s.limiters[name] = l
+	return l
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiterHoldsRequestsOverBudget(t *testing.T) {
+	l := New(2, 100*time.Millisecond)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("requests within budget waited %v", elapsed)
+	}
+	if err := l.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("third request went after %v, want it held for the window", elapsed)
+	}
+}
+
+func TestLimiterWaitCancelled(t *testing.T) {
+	l := New(1, time.Hour)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Error("Wait over budget returned nil after the context ended")
+	}
+}
+
+func TestSetReusesLimiter(t *testing.T) {
+	s := NewSet()
+	a := s.Get("news", 5, time.Minute)
+	if s.Get("news", 5, time.Minute) != a {
+		t.Error("same budget gave a new limiter")
+	}
+	if s.Get("news", 10, time.Minute) == a {
+		t.Error("changed budget kept the old limiter")
+	}
+	if s.Get("weather", 5, time.Minute) == s.Get("news", 5, time.Minute) {
+		t.Error("services share a limiter")
+	}
+}
+
+func TestBudgetRefusesRequestsPastMax(t *testing.T) {
+	b := NewBudget(2)
+	ctx := WithBudget(context.Background(), b)
+
+	for i := 0; i < 2; i++ {
+		if err := Take(ctx); err != nil {
+			t.Fatalf("request %d within budget: %v", i+1, err)
+		}
+	}
+	if err := Take(ctx); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("third request: got %v, want ErrBudgetExceeded", err)
+	}
+	if b.Refused() != 1 {
+		t.Errorf("Refused = %d, want 1", b.Refused())
+	}
+
+	// Without a budget in the context, requests are never refused.
+	if err := Take(context.Background()); err != nil {
+		t.Errorf("unbudgeted request: %v", err)
+	}
+}
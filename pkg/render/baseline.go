@@ -0,0 +1,146 @@
+package render
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jcadam/burrow/pkg/reports"
+)
+
+// pinBaseline records the viewed report as the viewer's named baseline.
+func (v Viewer) pinBaseline() (tea.Model, tea.Cmd) {
+	if v.reportDir == "" || v.diffOf != nil {
+		v.setStatus("Only a saved report can be pinned as a baseline")
+		return v, nil
+	}
+	if err := reports.SetBaseline(filepath.Dir(v.reportDir), v.baselineName(), v.reportDir); err != nil {
+		v.setStatus("Error: " + err.Error())
+		return v, nil
+	}
+	v.setStatus(fmt.Sprintf("Pinned as baseline %q", v.baselineName()))
+	return v, nil
+}
+
+// toggleBaselineDiff switches between the report and its diff against the
+// named baseline.
+func (v Viewer) toggleBaselineDiff() (tea.Model, tea.Cmd) {
+	if v.diffOf != nil {
+		orig := *v.diffOf
+		orig.viewport.Width, orig.viewport.Height = v.viewport.Width, v.viewport.Height
+		if orig.ready {
+			orig.viewport.SetContent(orig.content)
+		}
+		orig.setStatus("Showing the report")
+		return orig, nil
+	}
+	if v.reportDir == "" {
+		v.setStatus("No baseline available for this report")
+		return v, nil
+	}
+	base, err := reports.LoadBaseline(filepath.Dir(v.reportDir), v.baselineName())
+	if err != nil {
+		v.setStatus("Error: " + err.Error() + " (press b on a report to pin it)")
+		return v, nil
+	}
+	if filepath.Base(base.Dir) == filepath.Base(v.reportDir) {
+		v.setStatus("This report is the baseline")
+		return v, nil
+	}
+
+	markdown := baselineDiffMarkdown(v.baselineName(), base, reports.DiffSections(base.Markdown, v.raw))
+	rendered, err := RenderMarkdownStyle(markdown, 0, v.style, v.imageTier)
+	if err != nil {
+		v.setStatus("Error: " + err.Error())
+		return v, nil
+	}
+
+	built := buildViewer(v.title+" vs baseline", markdown, rendered)
+	built.handoff = v.handoff
+	built.provider = v.provider
+	built.ledger = v.ledger
+	built.profile = v.profile
+	built.ctx = v.ctx
+	built.imageConfig = v.imageConfig
+	built.style = v.style
+	built.accessible = v.accessible
+	built.imageTier = v.imageTier
+	built.baseline = v.baseline
+	built.coverage = v.coverage
+	if !built.accessible {
+		built.content = processTrends(built.content, built.imageTier)
+	}
+	built.fullLines = strings.Split(built.content, "\n")
+	built.zones = v.zones
+	built.zoneState = v.zoneState
+	built.viewport = v.viewport
+	built.ready = v.ready
+	if built.ready {
+		built.viewport.SetContent(built.content)
+		built.viewport.GotoTop()
+	}
+	orig := v
+	built.diffOf = &orig
+	built.setStatus("Showing changes since baseline (D to return)")
+	return built, nil
+}
+
+func (v Viewer) baselineName() string {
+	if v.baseline == "" {
+		return reports.DefaultBaseline
+	}
+	return v.baseline
+}
+
+// baselineDiffMarkdown describes how a report differs from a baseline:
+// changed sections with their baseline text quoted beneath, new and
+// removed sections, then the unchanged headings.
+func baselineDiffMarkdown(name string, base *reports.Report, changes []reports.SectionChange) string {
+	counts := map[reports.SectionStatus]int{}
+	for _, c := range changes {
+		counts[c.Status]++
+	}
+
+	var b strings.Builder
+	baseTitle := base.Title
+	if baseTitle == "" {
+		baseTitle = base.Routine
+	}
+	fmt.Fprintf(&b, "# Changes since baseline %q\n\n", name)
+	fmt.Fprintf(&b, "Baseline: %s (%s)\n\n", baseTitle, base.Date)
+	fmt.Fprintf(&b, "%d changed, %d new, %d removed, %d unchanged sections.\n",
+		counts[reports.SectionChanged], counts[reports.SectionAdded],
+		counts[reports.SectionRemoved], counts[reports.SectionUnchanged])
+
+	var unchanged []string
+	for _, c := range changes {
+		switch c.Status {
+		case reports.SectionUnchanged:
+			unchanged = append(unchanged, c.Heading)
+			continue
+		case reports.SectionChanged:
+			fmt.Fprintf(&b, "\n## %s (changed)\n\n%s\n", c.Heading, c.Body)
+			if c.BaselineBody != "" {
+				b.WriteString("\n**Baseline:**\n\n")
+				for _, line := range strings.Split(c.BaselineBody, "\n") {
+					b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+				}
+			}
+		case reports.SectionAdded:
+			fmt.Fprintf(&b, "\n## %s (new)\n\n%s\n", c.Heading, c.Body)
+		case reports.SectionRemoved:
+			fmt.Fprintf(&b, "\n## %s (removed)\n\nIn the baseline:\n\n", c.Heading)
+			for _, line := range strings.Split(c.Body, "\n") {
+				b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+			}
+		}
+	}
+	if len(unchanged) > 0 {
+		b.WriteString("\n## Unchanged\n\n")
+		for _, h := range unchanged {
+			b.WriteString("- " + h + "\n")
+		}
+	}
+	return b.String()
+}
@@ -21,16 +21,18 @@ func hasChartDirectives(markdown string) bool {
 }
 
 // processCharts replaces chart fenced blocks in the rendered content with
-// either inline images (Tier 1) or text tables (Tier 2).
+// either inline images (Tier 1) or text tables (Tier 2), and metric blocks
+// with callout strips (plain lines when accessible).
 //
 // Strategy:
 //  1. Parse chart directives from raw markdown.
-//  2. Create a copy of raw markdown with chart blocks replaced by unique markers.
-//  3. Render the marked-up markdown through Glamour.
-//  4. In the Glamour output, replace markers with chart content.
-func processCharts(raw, rendered, reportDir string, tier ImageTier) string {
+//  2. Create a copy of raw markdown with chart and metric blocks replaced by
+//     unique markers.
+//  3. Render the marked-up markdown through Glamour, in the viewer's style.
+//  4. In the Glamour output, replace markers with chart and metric content.
+func processCharts(raw, rendered, reportDir string, tier ImageTier, style string, accessible bool) string {
 	directives := charts.ParseDirectives(raw)
-	if len(directives) == 0 {
+	if len(directives) == 0 && !strings.Contains(raw, "```metric") {
 		return rendered
 	}
 
@@ -39,10 +41,13 @@ func processCharts(raw, rendered, reportDir string, tier ImageTier) string {
 	for i := range directives {
 		replacements[i] = fmt.Sprintf("%s%d", chartMarkerPrefix, i)
 	}
-	markedMD := charts.ReplaceDirectives(raw, replacements)
+	markedMD, metrics := markMetrics(charts.ReplaceDirectives(raw, replacements))
+	if len(directives) == 0 && len(metrics) == 0 {
+		return rendered
+	}
 
 	// Render the marked-up markdown
-	markedRendered, err := RenderMarkdown(markedMD, 0)
+	markedRendered, err := RenderMarkdownStyle(markedMD, 0, style)
 	if err != nil {
 		// Fall back to original rendered content
 		return rendered
@@ -76,31 +81,36 @@ func processCharts(raw, rendered, reportDir string, tier ImageTier) string {
 		markedRendered = strings.Replace(markedRendered, marker, replacement, 1)
 	}
 
-	return markedRendered
+	return replaceMetricMarkers(markedRendered, metrics, accessible)
 }
 
-// openFirstChart opens the first chart PNG in an external viewer.
-func (v Viewer) openFirstChart() (tea.Model, tea.Cmd) {
+// openImage opens the first chart PNG in an external viewer, or, for a
+// report without charts, the first local image it references. Inline
+// images don't scroll with the viewport, so this is how images are seen.
+func (v Viewer) openImage() (tea.Model, tea.Cmd) {
 	if v.handoff == nil || v.reportDir == "" {
 		v.setStatus("No charts available")
 		return v, nil
 	}
 
+	var path string
 	chartsDir := filepath.Join(v.reportDir, "charts")
-	entries, err := os.ReadDir(chartsDir)
-	if err != nil || len(entries) == 0 {
+	if entries, err := os.ReadDir(chartsDir); err == nil && len(entries) > 0 {
+		path = filepath.Join(chartsDir, entries[0].Name())
+	} else if len(v.images) > 0 {
+		path = v.images[0]
+	} else {
 		v.setStatus("No chart files found")
 		return v, nil
 	}
 
-	chartPath := filepath.Join(chartsDir, entries[0].Name())
 	handoff := v.handoff
 	v.busy = true
 	return v, func() tea.Msg {
-		err := handoff.OpenFile(chartPath)
+		err := handoff.OpenFile(path)
 		if err != nil {
 			return actionResultMsg{err: err}
 		}
-		return actionResultMsg{status: "Opened: " + entries[0].Name()}
+		return actionResultMsg{status: "Opened: " + filepath.Base(path)}
 	}
 }
@@ -24,7 +24,7 @@ func TestProcessChartsTextTable(t *testing.T) {
 		t.Fatalf("RenderMarkdown: %v", err)
 	}
 
-	result := processCharts(raw, rendered, "", TierNone)
+	result := processCharts(raw, rendered, "", TierNone, "", false)
 
 	// Should contain the text table
 	if !strings.Contains(result, "Postings") {
@@ -56,7 +56,7 @@ func TestProcessChartsMarkerNotMangled(t *testing.T) {
 		t.Fatalf("RenderMarkdown: %v", err)
 	}
 
-	result := processCharts(raw, rendered, "", TierNone)
+	result := processCharts(raw, rendered, "", TierNone, "", false)
 
 	// If markers were mangled by Glamour (e.g., double underscores → bold),
 	// the replacement wouldn't happen and markers would remain in the output.
@@ -74,7 +74,7 @@ func TestProcessChartsNoDirectives(t *testing.T) {
 	raw := "# Report\n\nNo charts.\n"
 	rendered, _ := RenderMarkdown(raw, 80)
 
-	result := processCharts(raw, rendered, "", TierNone)
+	result := processCharts(raw, rendered, "", TierNone, "", false)
 	if result != rendered {
 		t.Error("expected unchanged output when no chart directives")
 	}
@@ -88,7 +88,7 @@ func TestProcessChartsMultiple(t *testing.T) {
 		t.Fatalf("RenderMarkdown: %v", err)
 	}
 
-	result := processCharts(raw, rendered, "", TierNone)
+	result := processCharts(raw, rendered, "", TierNone, "", false)
 
 	if !strings.Contains(result, "First") {
 		t.Error("expected first chart title")
@@ -117,7 +117,7 @@ func TestProcessChartsWithPNG(t *testing.T) {
 	}
 
 	// With TierNone, even though PNG exists, it should fall back to text table
-	result := processCharts(raw, rendered, dir, TierNone)
+	result := processCharts(raw, rendered, dir, TierNone, "", false)
 	if !strings.Contains(result, "Test") {
 		t.Error("expected text table fallback")
 	}
@@ -149,7 +149,7 @@ func TestProcessChartsTierNoneUsedInViewport(t *testing.T) {
 	}
 
 	// TierNone must produce a text table, not image escape sequences
-	result := processCharts(raw, rendered, dir, TierNone)
+	result := processCharts(raw, rendered, dir, TierNone, "", false)
 
 	if !strings.Contains(result, "Postings") {
 		t.Error("expected text table with chart title")
@@ -168,3 +168,58 @@ func TestProcessChartsTierNoneUsedInViewport(t *testing.T) {
 		t.Error("expected all markers replaced")
 	}
 }
+
+func TestMarkMetricsGroupsConsecutiveBlocks(t *testing.T) {
+	raw := "# KPIs\n\n```metric\nlabel: Open Postings\nvalue: 42\ndelta: +5\n```\n\n" +
+		"```metric\nlabel: \"Awards\"\nvalue: 3\n```\n\nText between.\n\n" +
+		"```metric\nlabel: Spend\nvalue: $1.2M\ndelta: -8%\nnote: vs last week\n```\n\n" +
+		"```metric\nvalue: 7\n```\n"
+
+	marked, groups := markMetrics(raw)
+	if len(groups) != 2 || len(groups[0]) != 2 || len(groups[1]) != 1 {
+		t.Fatalf("groups = %+v, want sizes [2 1]", groups)
+	}
+	if groups[0][1].label != "Awards" || groups[1][0].note != "vs last week" {
+		t.Errorf("unexpected metrics: %+v", groups)
+	}
+	if strings.Count(marked, metricMarkerPrefix) != 2 {
+		t.Errorf("expected two markers:\n%s", marked)
+	}
+	// A block without a label stays as code.
+	if !strings.Contains(marked, "```metric\nvalue: 7\n```") {
+		t.Errorf("incomplete block should be kept:\n%s", marked)
+	}
+}
+
+func TestProcessChartsMetrics(t *testing.T) {
+	raw := "# Report\n\n```metric\nlabel: Open Postings\nvalue: 42\ndelta: +5\n```\n\n```metric\nlabel: Awards\nvalue: 3\n```\n\nMore text.\n"
+	rendered, err := RenderMarkdown(raw, 80)
+	if err != nil {
+		t.Fatalf("RenderMarkdown: %v", err)
+	}
+
+	result := processCharts(raw, rendered, "", TierNone, "", false)
+	for _, want := range []string{"Open Postings", "42", "+5", "Awards", "╭", "More text"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected %q in output:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, metricMarkerPrefix) || strings.Contains(result, "label:") {
+		t.Errorf("markers or raw block left in output:\n%s", result)
+	}
+	// Both callouts share a row.
+	var row string
+	for _, line := range strings.Split(result, "\n") {
+		if strings.Contains(line, "Open Postings") {
+			row = line
+		}
+	}
+	if !strings.Contains(row, "Awards") {
+		t.Errorf("expected metrics side by side, got row %q", row)
+	}
+
+	plain := processCharts(raw, rendered, "", TierNone, "", true)
+	if !strings.Contains(plain, "Open Postings: 42 (+5)") || strings.Contains(plain, "╭") {
+		t.Errorf("expected plain metric lines in accessible mode:\n%s", plain)
+	}
+}
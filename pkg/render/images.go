@@ -3,6 +3,10 @@ package render
 import (
 	"bytes"
 	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/BourgeoisBear/rasterm"
@@ -62,3 +66,39 @@ func WriteInlineImage(w io.Writer, pngData []byte, tier ImageTier) error {
 		return nil // TierNone and TierSixel (which requires paletted image) — no-op
 	}
 }
+
+// markdownImagePattern matches a markdown image, capturing its target.
+var markdownImagePattern = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+
+// localImages returns the files referenced by markdown images that exist
+// inside reportDir, in order of appearance. Images with a URL scheme are
+// skipped: the viewer never fetches a report's remote images, since that
+// would contact a host the user didn't configure. Paths that resolve
+// outside the report directory are skipped too.
+func localImages(markdown, reportDir string) []string {
+	if reportDir == "" {
+		return nil
+	}
+	root, err := filepath.Abs(reportDir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	seen := make(map[string]bool)
+	for _, m := range markdownImagePattern.FindAllStringSubmatch(markdown, -1) {
+		u, err := url.Parse(m[1])
+		if err != nil || u.Scheme != "" || u.Host != "" {
+			continue
+		}
+		path := filepath.Join(root, filepath.FromSlash(u.Path))
+		if rel, err := filepath.Rel(root, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() || seen[path] {
+			continue
+		}
+		seen[path] = true
+		files = append(files, path)
+	}
+	return files
+}
@@ -1,6 +1,11 @@
 package render
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
 
 func TestDetectImageTierText(t *testing.T) {
 	tier := DetectImageTier("text")
@@ -53,3 +58,28 @@ func TestWriteInlineImageNone(t *testing.T) {
 		t.Errorf("expected no error for TierNone, got: %v", err)
 	}
 }
+
+func TestLocalImages(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "attachments"), 0o755)
+	os.WriteFile(filepath.Join(dir, "attachments", "map.png"), []byte("png"), 0o644)
+	os.WriteFile(filepath.Join(dir, "photo.jpg"), []byte("jpg"), 0o644)
+	os.WriteFile(filepath.Join(filepath.Dir(dir), "outside.png"), []byte("png"), 0o644)
+
+	md := "# Report\n\n" +
+		"![Map](attachments/map.png)\n" +
+		"![Remote](https://example.com/tracker.png)\n" +
+		"![Missing](attachments/none.png)\n" +
+		"![Escape](../outside.png)\n" +
+		"![Photo](<photo.jpg> \"A photo\")\n" +
+		"![Again](attachments/map.png)\n"
+
+	got := localImages(md, dir)
+	want := []string{filepath.Join(dir, "attachments", "map.png"), filepath.Join(dir, "photo.jpg")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("localImages = %v, want %v", got, want)
+	}
+	if got := localImages(md, ""); got != nil {
+		t.Errorf("without a report dir, got %v", got)
+	}
+}
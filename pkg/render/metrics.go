@@ -0,0 +1,195 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// metricMarkerPrefix marks where a strip of metric callouts goes in
+// rendered output, like chartMarkerPrefix does for charts.
+const metricMarkerPrefix = "BURROW-METRIC-"
+
+// metricStripWidth is the widest a row of metric callouts may be before
+// the rest wrap onto another row.
+const metricStripWidth = 76
+
+// metric is one ```metric block: a key figure for the report's KPI strip.
+type metric struct {
+	label string
+	value string
+	delta string // optional change, e.g. +5 or -3.2%
+	note  string // optional context, e.g. "vs last week"
+}
+
+// parseMetric reads a metric block's "key: value" lines. A block without
+// both a label and a value is not a metric.
+func parseMetric(lines []string) (metric, bool) {
+	var m metric
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "label":
+			m.label = value
+		case "value":
+			m.value = value
+		case "delta":
+			m.delta = value
+		case "note":
+			m.note = value
+		}
+	}
+	return m, m.label != "" && m.value != ""
+}
+
+// markMetrics replaces ```metric blocks in markdown with marker lines and
+// returns the metrics behind each marker. Consecutive blocks, separated
+// only by blank lines, share one marker so they render as a single strip.
+// Unclosed or incomplete blocks are left as code.
+func markMetrics(markdown string) (string, [][]metric) {
+	lines := strings.Split(markdown, "\n")
+	var out []string
+	var groups [][]metric
+	lastMarker := -1 // index in out of the current group's marker
+
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "```metric" {
+			out = append(out, lines[i])
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(lines[end]) != "```" {
+			end++
+		}
+		if end == len(lines) {
+			out = append(out, lines[i:]...)
+			break
+		}
+		m, ok := parseMetric(lines[i+1 : end])
+		if !ok {
+			out = append(out, lines[i:end+1]...)
+			i = end
+			continue
+		}
+		if lastMarker >= 0 && onlyBlank(out[lastMarker+1:]) {
+			groups[len(groups)-1] = append(groups[len(groups)-1], m)
+		} else {
+			out = append(out, fmt.Sprintf("%s%d", metricMarkerPrefix, len(groups)))
+			lastMarker = len(out) - 1
+			groups = append(groups, []metric{m})
+		}
+		i = end
+	}
+	return strings.Join(out, "\n"), groups
+}
+
+func onlyBlank(lines []string) bool {
+	for _, l := range lines {
+		if strings.TrimSpace(l) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	metricBoxStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("240")).
+			Padding(0, 1).
+			MarginRight(1)
+	metricLabelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	metricValueStyle = lipgloss.NewStyle().Bold(true)
+	metricUpStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("2"))
+	metricDownStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("1"))
+)
+
+// renderMetricStrip renders a group of metrics as callout boxes side by
+// side, wrapping onto further rows past metricStripWidth. Accessible mode
+// renders one plain "Label: value (delta, note)" line per metric instead.
+func renderMetricStrip(group []metric, accessible bool) []string {
+	if accessible {
+		lines := make([]string, 0, len(group))
+		for _, m := range group {
+			line := m.label + ": " + m.value
+			var extra []string
+			for _, s := range []string{m.delta, m.note} {
+				if s != "" {
+					extra = append(extra, s)
+				}
+			}
+			if len(extra) > 0 {
+				line += " (" + strings.Join(extra, ", ") + ")"
+			}
+			lines = append(lines, line)
+		}
+		return lines
+	}
+
+	var rows, row []string
+	width := 0
+	for _, m := range group {
+		box := metricBoxStyle.Render(metricBody(m))
+		w := lipgloss.Width(box)
+		if len(row) > 0 && width+w > metricStripWidth {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row, width = nil, 0
+		}
+		row = append(row, box)
+		width += w
+	}
+	rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	return strings.Split(strings.Join(rows, "\n"), "\n")
+}
+
+// metricBody is a callout's content: the label, then the value with its
+// delta colored by direction, then any note.
+func metricBody(m metric) string {
+	figure := metricValueStyle.Render(m.value)
+	if m.delta != "" {
+		style := metricLabelStyle
+		switch r, _ := utf8.DecodeRuneInString(m.delta); r {
+		case '+', '▲', '↑':
+			style = metricUpStyle
+		case '-', '−', '▼', '↓':
+			style = metricDownStyle
+		}
+		figure += "  " + style.Render(m.delta)
+	}
+	body := metricLabelStyle.Render(m.label) + "\n" + figure
+	if m.note != "" {
+		body += "\n" + metricLabelStyle.Render(m.note)
+	}
+	return body
+}
+
+// replaceMetricMarkers swaps each marker line in rendered output for its
+// rendered strip, indented to the document margin.
+func replaceMetricMarkers(rendered string, groups [][]metric, accessible bool) string {
+	if len(groups) == 0 {
+		return rendered
+	}
+	lines := strings.Split(rendered, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		idx := -1
+		if strings.Contains(line, metricMarkerPrefix) {
+			plain := sgrPattern.ReplaceAllString(line, "")
+			fmt.Sscanf(strings.TrimSpace(plain), metricMarkerPrefix+"%d", &idx)
+		}
+		if idx < 0 || idx >= len(groups) {
+			out = append(out, line)
+			continue
+		}
+		for _, l := range renderMetricStrip(groups[idx], accessible) {
+			out = append(out, "  "+l)
+		}
+	}
+	return strings.Join(out, "\n")
+}
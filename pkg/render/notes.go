@@ -0,0 +1,84 @@
+package render
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	bcontext "github.com/jcadam/burrow/pkg/context"
+	"github.com/jcadam/burrow/pkg/reports"
+)
+
+// withNotes appends the report's saved notes to its markdown, so they show
+// as a foldable section at the end. Unreadable notes are left out.
+func withNotes(markdown, reportDir string) string {
+	if reportDir == "" {
+		return markdown
+	}
+	notes, err := reports.LoadNotes(reportDir)
+	if err != nil || len(notes) == 0 {
+		return markdown
+	}
+	return markdown + "\n\n" + reports.NotesSection(notes)
+}
+
+// startNote opens the note input in the footer.
+func (v Viewer) startNote() (tea.Model, tea.Cmd) {
+	if v.reportDir == "" || v.diffOf != nil {
+		v.setStatus("Only a saved report can take notes")
+		return v, nil
+	}
+	in := textinput.New()
+	in.Prompt = "Note: "
+	in.Placeholder = "enter to save, esc to cancel"
+	in.CharLimit = 500
+	in.Width = v.viewport.Width - len(in.Prompt) - 2
+	v.noteInput = in
+	v.noting = true
+	return v, v.noteInput.Focus()
+}
+
+// updateNoteInput handles keys while the note input is open.
+func (v Viewer) updateNoteInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		v.noting = false
+		return v, nil
+	case "ctrl+c":
+		return v, tea.Quit
+	case "enter":
+		v.noting = false
+		v.saveNote(v.noteInput.Value(), time.Now())
+		return v, nil
+	}
+	var cmd tea.Cmd
+	v.noteInput, cmd = v.noteInput.Update(msg)
+	return v, cmd
+}
+
+// saveNote appends text to the report's notes.md and, when a ledger is
+// configured, records it there as a note so later drafts and gd ask can
+// draw on it.
+func (v *Viewer) saveNote(text string, now time.Time) {
+	note, err := reports.AddNote(v.reportDir, text, now)
+	if err != nil {
+		v.setStatus("Note not saved: " + err.Error())
+		return
+	}
+	if v.ledger != nil {
+		err := v.ledger.Append(bcontext.Entry{
+			Type:      bcontext.TypeNote,
+			Label:     v.title,
+			Timestamp: now,
+			Content:   fmt.Sprintf("Note on report %s:\n\n%s\n", filepath.Base(v.reportDir), note.Text),
+		})
+		if err != nil {
+			v.setStatus("Note saved to " + reports.NotesFile + "; ledger error: " + err.Error())
+			return
+		}
+	}
+	v.setStatus("Note saved to " + reports.NotesFile)
+}
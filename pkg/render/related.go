@@ -0,0 +1,219 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	bcontext "github.com/jcadam/burrow/pkg/context"
+)
+
+const (
+	maxRelatedTerms   = 8 // title + first headings used as search terms
+	maxRelatedReports = 8
+	minRelatedTermLen = 4 // shorter terms match too broadly to be useful
+)
+
+// relatedReport is a prior report from the context ledger that shares
+// terms with the report being viewed.
+type relatedReport struct {
+	title     string
+	timestamp time.Time
+	markdown  string
+	score     int // number of search terms matched
+}
+
+// relatedResultMsg carries the result of an async related-reports search.
+type relatedResultMsg struct {
+	reports []relatedReport
+	err     error
+}
+
+// relatedTerms extracts search terms from the report title and headings.
+// Markdown emphasis is stripped and duplicates are dropped, preserving order.
+func relatedTerms(title, raw string) []string {
+	candidates := []string{title}
+	for _, m := range headingPattern.FindAllStringSubmatch(raw, -1) {
+		candidates = append(candidates, m[2])
+	}
+
+	seen := make(map[string]bool)
+	var terms []string
+	for _, c := range candidates {
+		term := strings.ToLower(strings.TrimSpace(strings.Trim(c, "*_` ")))
+		if len(term) < minRelatedTermLen || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+		if len(terms) == maxRelatedTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// findRelated searches the ledger for prior reports matching any of the
+// terms from the current report. Reports matching more terms rank first,
+// then newer reports. The report being viewed is excluded.
+func findRelated(ledger *bcontext.Ledger, title, raw string) ([]relatedReport, error) {
+	current := strings.TrimSpace(raw)
+	byID := make(map[string]*relatedReport)
+
+	for _, term := range relatedTerms(title, raw) {
+		entries, err := ledger.Search(term)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.Type != bcontext.TypeReport || e.Content == current {
+				continue
+			}
+			r, ok := byID[e.ID]
+			if !ok {
+				r = &relatedReport{title: e.Label, timestamp: e.Timestamp, markdown: e.Content}
+				byID[e.ID] = r
+			}
+			r.score++
+		}
+	}
+
+	reports := make([]relatedReport, 0, len(byID))
+	for _, r := range byID {
+		reports = append(reports, *r)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].score != reports[j].score {
+			return reports[i].score > reports[j].score
+		}
+		return reports[i].timestamp.After(reports[j].timestamp)
+	})
+	if len(reports) > maxRelatedReports {
+		reports = reports[:maxRelatedReports]
+	}
+	return reports, nil
+}
+
+// startRelated searches the ledger for related reports asynchronously.
+func (v Viewer) startRelated() (tea.Model, tea.Cmd) {
+	if v.ledger == nil {
+		v.setStatus("No context ledger available")
+		return v, nil
+	}
+	ledger := v.ledger
+	title := v.title
+	raw := v.raw
+	v.busy = true
+	return v, func() tea.Msg {
+		reports, err := findRelated(ledger, title, raw)
+		return relatedResultMsg{reports: reports, err: err}
+	}
+}
+
+// handleRelatedResult opens the overlay with the search results.
+func (v Viewer) handleRelatedResult(msg relatedResultMsg) (tea.Model, tea.Cmd) {
+	v.busy = false
+	if msg.err != nil {
+		v.setStatus("Error: " + msg.err.Error())
+		return v, nil
+	}
+	if len(msg.reports) == 0 {
+		v.setStatus("No related reports found")
+		return v, nil
+	}
+	v.related = msg.reports
+	v.relatedIdx = 0
+	v.showRelated = true
+	return v, nil
+}
+
+// --- Related reports overlay ---
+
+func (v *Viewer) relatedOverlayHeight() int {
+	return len(v.related) + 2
+}
+
+func (v Viewer) renderRelatedOverlay() string {
+	var b strings.Builder
+	b.WriteString(footerStyle.Render(" Related reports (" + v.glyph("↑↓", "up/down") + " navigate, enter open, esc close):"))
+	b.WriteString("\n")
+
+	for i, r := range v.related {
+		line := fmt.Sprintf("  %s  %s", r.timestamp.Format("2006-01-02"), r.title)
+		if i == v.relatedIdx {
+			b.WriteString(actionSelectedStyle.Render(v.glyph("▸ ", "> ") + line))
+		} else {
+			b.WriteString(actionNormalStyle.Render("  " + line))
+		}
+		if i < len(v.related)-1 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+func (v Viewer) updateRelatedOverlay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "r":
+		v.showRelated = false
+		return v, nil
+	case "q", "ctrl+c":
+		return v, tea.Quit
+	case "up", "k":
+		if v.relatedIdx > 0 {
+			v.relatedIdx--
+		}
+		return v, nil
+	case "down", "j":
+		if v.relatedIdx < len(v.related)-1 {
+			v.relatedIdx++
+		}
+		return v, nil
+	case "enter":
+		v.showRelated = false
+		return v.openRelated(v.related[v.relatedIdx]), nil
+	}
+	return v, nil
+}
+
+// openRelated replaces the viewed report with a related one, keeping the
+// viewer's dependencies and viewport.
+func (v Viewer) openRelated(r relatedReport) Viewer {
+	rendered, err := RenderMarkdownStyle(r.markdown, 0, v.style, v.imageTier)
+	if err != nil {
+		v.setStatus("Error: " + err.Error())
+		return v
+	}
+	// Chart PNGs live in the report directory, which the ledger doesn't
+	// record — related reports show charts as text tables.
+	rendered = processCharts(r.markdown, rendered, "", TierNone, v.style, v.accessible)
+
+	title := fmt.Sprintf("%s (%s)", r.title, r.timestamp.Format("2006-01-02"))
+	built := buildViewer(title, r.markdown, rendered)
+	built.handoff = v.handoff
+	built.provider = v.provider
+	built.ledger = v.ledger
+	built.profile = v.profile
+	built.ctx = v.ctx
+	built.imageConfig = v.imageConfig
+	built.style = v.style
+	built.accessible = v.accessible
+	built.imageTier = v.imageTier
+	built.baseline = v.baseline
+	if !built.accessible {
+		built.content = processTrends(built.content, built.imageTier)
+	}
+	built.fullLines = strings.Split(built.content, "\n")
+	built.zones = v.zones
+	built.zoneState = v.zoneState
+	built.viewport = v.viewport
+	built.ready = v.ready
+	if built.ready {
+		built.viewport.SetContent(built.content)
+		built.viewport.GotoTop()
+	}
+	built.setStatus("Opened related report")
+	return built
+}
@@ -0,0 +1,104 @@
+package render
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	bcontext "github.com/jcadam/burrow/pkg/context"
+)
+
+func TestRelatedTerms(t *testing.T) {
+	raw := "# Morning Brief\n\n## **Contract Awards**\n\n## News\n\n## contract awards\n"
+	got := relatedTerms("Morning Brief", raw)
+	want := []string{"morning brief", "contract awards", "news"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("relatedTerms = %q, want %q", got, want)
+	}
+}
+
+func TestFindRelatedRanksByMatchedTerms(t *testing.T) {
+	ledger, err := bcontext.NewLedger(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2026, 2, 19, 5, 0, 0, 0, time.UTC)
+	current := "# Morning Brief\n\n## Contract Awards\n\nToday."
+	for _, e := range []bcontext.Entry{
+		{Type: bcontext.TypeReport, Label: "Older brief", Timestamp: day.AddDate(0, 0, -2), Content: "# Morning Brief\n\n## Contract Awards\n\nTwo awards."},
+		{Type: bcontext.TypeReport, Label: "Newer partial", Timestamp: day.AddDate(0, 0, -1), Content: "Contract awards were quiet."},
+		{Type: bcontext.TypeReport, Label: "Unrelated", Timestamp: day, Content: "Weather outlook."},
+		{Type: bcontext.TypeResult, Label: "Raw", Timestamp: day, Content: "contract awards json"},
+		{Type: bcontext.TypeReport, Label: "Morning Brief", Timestamp: day, Content: current},
+	} {
+		if err := ledger.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reports, err := findRelated(ledger, "Morning Brief", current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 related reports, got %d: %+v", len(reports), reports)
+	}
+	if reports[0].title != "Older brief" || reports[1].title != "Newer partial" {
+		t.Errorf("unexpected order: %q, %q", reports[0].title, reports[1].title)
+	}
+}
+
+func TestViewerRelatedNoLedger(t *testing.T) {
+	raw := "# Report\n"
+	rendered, _ := RenderMarkdown(raw, 80)
+	var m tea.Model = newViewerWithRaw("Test", raw, rendered)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if cmd != nil {
+		t.Error("expected no command without a ledger")
+	}
+	if m.(Viewer).statusMsg == "" {
+		t.Error("expected status message about missing ledger")
+	}
+}
+
+func TestViewerRelatedOverlayOpensReport(t *testing.T) {
+	raw := "# Report\n"
+	rendered, _ := RenderMarkdown(raw, 80)
+	var m tea.Model = newViewerWithRaw("Test", raw, rendered)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	ts := time.Date(2026, 2, 18, 5, 0, 0, 0, time.UTC)
+	m, _ = m.Update(relatedResultMsg{reports: []relatedReport{
+		{title: "First", timestamp: ts, markdown: "# First\n\nAlpha."},
+		{title: "Second", timestamp: ts, markdown: "# Second\n\nBravo."},
+	}})
+	viewer := m.(Viewer)
+	if !viewer.showRelated {
+		t.Fatal("expected related overlay to be visible")
+	}
+	if !strings.Contains(viewer.View(), "2026-02-18  First") {
+		t.Error("overlay should list title and date")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	viewer = m.(Viewer)
+	if viewer.showRelated {
+		t.Error("overlay should close after opening a report")
+	}
+	if viewer.raw != "# Second\n\nBravo." || !strings.HasPrefix(viewer.title, "Second") {
+		t.Errorf("expected Second report, got title %q", viewer.title)
+	}
+}
+
+func TestViewerRelatedEmptyResult(t *testing.T) {
+	var m tea.Model = newViewerWithRaw("Test", "# Report\n", "Report")
+	m, _ = m.Update(relatedResultMsg{})
+	viewer := m.(Viewer)
+	if viewer.showRelated || viewer.statusMsg != "No related reports found" {
+		t.Errorf("showRelated=%v status=%q", viewer.showRelated, viewer.statusMsg)
+	}
+}
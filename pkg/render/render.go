@@ -3,6 +3,9 @@ package render
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/charmbracelet/glamour"
@@ -12,8 +15,9 @@ import (
 
 // rendererCacheKey identifies a cached glamour renderer.
 type rendererCacheKey struct {
-	width         int
+	width          int
 	useBurrowStyle bool
+	style          string
 }
 
 var (
@@ -24,30 +28,34 @@ var (
 // RenderMarkdown renders markdown to styled terminal output using Glamour.
 // An optional ImageTier can be passed to enable the custom Burrow style on
 // Tier 1 terminals. When omitted (or TierNone), the default auto-style is used.
+func RenderMarkdown(markdown string, width int, tier ...ImageTier) (string, error) {
+	return RenderMarkdownStyle(markdown, width, "", tier...)
+}
+
+// RenderMarkdownStyle renders markdown like RenderMarkdown, in the given
+// style: dark, light, notty, or a path to a Glamour JSON style file. An
+// empty style or "auto" keeps RenderMarkdown's tier-based choice.
 //
 // The renderer cache is protected by a mutex that covers both cache access and
 // the Render call, since glamour.TermRenderer is not safe for concurrent use.
-func RenderMarkdown(markdown string, width int, tier ...ImageTier) (string, error) {
+func RenderMarkdownStyle(markdown string, width int, style string, tier ...ImageTier) (string, error) {
 	if width <= 0 {
 		width = 80
 	}
 
 	useBurrow := len(tier) > 0 && tier[0] != TierNone
-	key := rendererCacheKey{width: width, useBurrowStyle: useBurrow}
+	key := rendererCacheKey{width: width, useBurrowStyle: useBurrow, style: style}
 
 	rendererMu.Lock()
 	defer rendererMu.Unlock()
 
 	r, ok := rendererCache[key]
 	if !ok {
-		var styleOpt glamour.TermRendererOption
-		if useBurrow {
-			styleOpt = glamour.WithStyles(burrowStyle())
-		} else {
-			styleOpt = glamour.WithAutoStyle()
+		styleOpt, err := styleOption(style, useBurrow)
+		if err != nil {
+			return "", err
 		}
 
-		var err error
 		r, err = glamour.NewTermRenderer(
 			styleOpt,
 			glamour.WithWordWrap(width),
@@ -65,6 +73,54 @@ func RenderMarkdown(markdown string, width int, tier ...ImageTier) (string, erro
 	return out, nil
 }
 
+// RenderPlain renders markdown as text for files: the notty style, with no
+// color or escape codes, and without the padding that fills each line out
+// to the wrap width.
+func RenderPlain(markdown string, width int) (string, error) {
+	out, err := RenderMarkdownStyle(markdown, width, styles.NoTTYStyle)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")) + "\n", nil
+}
+
+// ValidStyle reports whether style is a style name RenderMarkdownStyle
+// accepts or a path to a JSON style file. The file itself is read when
+// rendering.
+func ValidStyle(style string) bool {
+	switch style {
+	case "", styles.AutoStyle, styles.DarkStyle, styles.LightStyle, styles.NoTTYStyle:
+		return true
+	}
+	return strings.EqualFold(filepath.Ext(style), ".json")
+}
+
+// styleOption returns the Glamour option for a style. A JSON style file is
+// read when its renderer is first created.
+func styleOption(style string, useBurrow bool) (glamour.TermRendererOption, error) {
+	switch style {
+	case "", styles.AutoStyle:
+		if useBurrow {
+			return glamour.WithStyles(burrowStyle()), nil
+		}
+		return glamour.WithAutoStyle(), nil
+	case styles.DarkStyle, styles.LightStyle, styles.NoTTYStyle:
+		return glamour.WithStandardStyle(style), nil
+	}
+	if !ValidStyle(style) {
+		return nil, fmt.Errorf("unknown style %q (must be auto, dark, light, notty, or a .json style file)", style)
+	}
+	data, err := os.ReadFile(style)
+	if err != nil {
+		return nil, fmt.Errorf("reading style: %w", err)
+	}
+	return glamour.WithStylesFromJSONBytes(data), nil
+}
+
 // burrowStyle returns a custom Glamour style based on TokyoNight with Burrow
 // refinements: subtle H1 background, Unicode horizontal rules, and styled
 // block quotes.
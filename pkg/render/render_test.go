@@ -1,6 +1,8 @@
 package render
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -22,6 +24,24 @@ func TestRenderMarkdown(t *testing.T) {
 	}
 }
 
+func TestRenderPlain(t *testing.T) {
+	out, err := RenderPlain("# Hello\n\nThis is a **test**.\n", 80)
+	if err != nil {
+		t.Fatalf("RenderPlain: %v", err)
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("expected no escape codes, got %q", out)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasSuffix(line, " ") {
+			t.Errorf("line %q has trailing padding", line)
+		}
+	}
+	if !strings.Contains(out, "Hello") || !strings.HasSuffix(out, "test**.\n") {
+		t.Errorf("unexpected output %q", out)
+	}
+}
+
 func TestRenderMarkdownDefaultWidth(t *testing.T) {
 	md := "# Test\n\nContent.\n"
 	out, err := RenderMarkdown(md, 0)
@@ -44,6 +64,55 @@ func TestRenderMarkdownCodeBlock(t *testing.T) {
 	}
 }
 
+func TestRenderMarkdownStyle(t *testing.T) {
+	md := "# Styled\n\nSome **bold** text.\n"
+
+	notty, err := RenderMarkdownStyle(md, 80, "notty")
+	if err != nil {
+		t.Fatalf("notty: %v", err)
+	}
+	if strings.Contains(notty, "\x1b[") {
+		t.Errorf("notty style should have no escapes, got %q", notty)
+	}
+
+	dark, err := RenderMarkdownStyle(md, 80, "dark")
+	if err != nil {
+		t.Fatalf("dark: %v", err)
+	}
+	light, err := RenderMarkdownStyle(md, 80, "light")
+	if err != nil {
+		t.Fatalf("light: %v", err)
+	}
+	if dark == light {
+		t.Error("expected dark and light styles to render differently")
+	}
+}
+
+func TestRenderMarkdownStyleFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "style.json")
+	if err := os.WriteFile(path, []byte(`{"heading": {"prefix": ">> "}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := RenderMarkdownStyle("# Custom\n", 80, path)
+	if err != nil {
+		t.Fatalf("RenderMarkdownStyle: %v", err)
+	}
+	if !strings.Contains(out, ">> Custom") {
+		t.Errorf("expected custom heading prefix, got %q", out)
+	}
+
+	if _, err := RenderMarkdownStyle("# Missing\n", 80, filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error for missing style file")
+	}
+}
+
+func TestRenderMarkdownStyleUnknown(t *testing.T) {
+	_, err := RenderMarkdownStyle("# Test\n", 80, "solarized")
+	if err == nil || !strings.Contains(err.Error(), "unknown style") {
+		t.Errorf("expected unknown style error, got %v", err)
+	}
+}
+
 func TestRenderMarkdownCachedRenderer(t *testing.T) {
 	// Clear cache to isolate this test.
 	rendererMu.Lock()
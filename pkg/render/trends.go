@@ -0,0 +1,86 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// trendPattern matches numeric trend indicators: signed percentages (+12%,
+// -3.5%), arrows before a number (▲ 5, ↓2%), and "up" or "down" before a
+// number (up 12%, down 3). Group 1 is what precedes the indicator — line
+// start, whitespace, an open paren, or an ANSI escape — so dates and
+// hyphenated ranges don't match.
+var trendPattern = regexp.MustCompile(`(^|[\s(]|\x1b\[[0-9;]*m)` +
+	`([+\-−]\d[\d,]*(?:\.\d+)?%|[▲▼↑↓]\s?\d[\d,]*(?:\.\d+)?%?|(?i:up|down)\s+\d[\d,]*(?:\.\d+)?%?)`)
+
+// sgrPattern matches ANSI SGR (style) escape sequences.
+var sgrPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// Trend colors: TokyoNight green and red on Tier 1, basic ANSI otherwise.
+const (
+	trendUpTrueColor   = "\x1b[1;38;2;158;206;106m"
+	trendDownTrueColor = "\x1b[1;38;2;247;118;142m"
+	trendUpANSI        = "\x1b[1;32m"
+	trendDownANSI      = "\x1b[1;31m"
+	sgrReset           = "\x1b[0m"
+)
+
+// processTrends colors trend indicators in rendered output: green and bold
+// for increases, red and bold for decreases. It works line by line and
+// never adds lines, so heading positions found beforehand stay valid. Call
+// it after extractHeadings, since coloring splits heading text with escapes.
+func processTrends(rendered string, tier ImageTier) string {
+	up, down := trendUpANSI, trendDownANSI
+	if tier != TierNone {
+		up, down = trendUpTrueColor, trendDownTrueColor
+	}
+	lines := strings.Split(rendered, "\n")
+	for i, line := range lines {
+		lines[i] = colorTrends(line, up, down)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// colorTrends wraps each trend indicator in line with the up or down color.
+// After each one, the style that was active before it (Glamour's text
+// color) is restored.
+func colorTrends(line, up, down string) string {
+	matches := trendPattern.FindAllStringSubmatchIndex(line, -1)
+	if matches == nil {
+		return line
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[4], m[5]
+		// "up 3rd" or "+5%ile" is not a trend.
+		if r, _ := utf8.DecodeRuneInString(line[end:]); unicode.IsLetter(r) || unicode.IsDigit(r) {
+			continue
+		}
+		color := down
+		if isUpTrend(line[start:end]) {
+			color = up
+		}
+		b.WriteString(line[last:start])
+		b.WriteString(color)
+		b.WriteString(line[start:end])
+		b.WriteString(sgrReset)
+		if styles := sgrPattern.FindAllString(line[:start], -1); len(styles) > 0 {
+			b.WriteString(styles[len(styles)-1])
+		}
+		last = end
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+func isUpTrend(indicator string) bool {
+	switch r, _ := utf8.DecodeRuneInString(indicator); r {
+	case '+', '▲', '↑', 'u', 'U':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProcessTrendsColorsIndicators(t *testing.T) {
+	tests := []struct {
+		input, indicator, color string
+	}{
+		{"Postings up 12% vs last week", "up 12%", trendUpANSI},
+		{"Volume down 3 from Monday", "down 3", trendDownANSI},
+		{"Revenue +4.5% today", "+4.5%", trendUpANSI},
+		{"Costs (-2%) this quarter", "-2%", trendDownANSI},
+		{"Score ▲ 5", "▲ 5", trendUpANSI},
+		{"Rank ↓2", "↓2", trendDownANSI},
+	}
+	for _, tt := range tests {
+		got := processTrends(tt.input, TierNone)
+		want := tt.color + tt.indicator + sgrReset
+		if !strings.Contains(got, want) {
+			t.Errorf("processTrends(%q) = %q, want %q colored", tt.input, got, tt.indicator)
+		}
+	}
+}
+
+func TestProcessTrendsIgnoresNonTrends(t *testing.T) {
+	for _, input := range []string{
+		"Filed 2025-02-01",
+		"Pages 10-12%",
+		"Set up 3rd party access",
+		"Growth of 12% overall",
+		"https://example.com/up-12%20",
+	} {
+		if got := processTrends(input, TierNone); got != input {
+			t.Errorf("processTrends(%q) = %q, want unchanged", input, got)
+		}
+	}
+}
+
+func TestProcessTrendsRestoresStyle(t *testing.T) {
+	text := "\x1b[38;2;169;177;214m"
+	input := text + "Postings up 12% vs last week\x1b[0m"
+	got := processTrends(input, TierKitty)
+	want := text + "Postings " + trendUpTrueColor + "up 12%" + sgrReset + text + " vs last week\x1b[0m"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestProcessTrendsKeepsLineCount(t *testing.T) {
+	input := "# Report\n\nup 5%\n\ndown 2%\n"
+	got := processTrends(input, TierNone)
+	if strings.Count(got, "\n") != strings.Count(input, "\n") {
+		t.Errorf("line count changed: %q", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -17,6 +18,7 @@ import (
 	"github.com/jcadam/burrow/pkg/actions"
 	bcontext "github.com/jcadam/burrow/pkg/context"
 	"github.com/jcadam/burrow/pkg/profile"
+	"github.com/jcadam/burrow/pkg/reports"
 	"github.com/jcadam/burrow/pkg/synthesis"
 )
 
@@ -39,6 +41,11 @@ var footerStyle = lipgloss.NewStyle().
 	Foreground(lipgloss.Color("240")).
 	PaddingLeft(1)
 
+var staleStyle = lipgloss.NewStyle().
+	Bold(true).
+	Foreground(lipgloss.Color("203")).
+	PaddingLeft(1)
+
 var actionSelectedStyle = lipgloss.NewStyle().
 	Bold(true).
 	Foreground(lipgloss.Color("205"))
@@ -111,6 +118,13 @@ type draftResultMsg struct {
 	err error
 }
 
+// draftTickMsg redraws the footer while a draft streams in.
+type draftTickMsg struct{}
+
+func draftTick() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg { return draftTickMsg{} })
+}
+
 // headingPos tracks a heading's location in the rendered content.
 type headingPos struct {
 	text      string
@@ -148,11 +162,19 @@ type Viewer struct {
 	actionIdx   int
 	busy        bool // true while an async action is in flight
 
+	// Draft streaming: the draft so far, shown in the footer while it generates
+	drafting *synthesis.StreamBuffer
+
 	// Links
 	links     []linkEntry
 	showLinks bool
 	linkIdx   int
 
+	// Related reports (from the context ledger)
+	related     []relatedReport
+	showRelated bool
+	relatedIdx  int
+
 	// Optional deps for action execution
 	handoff  *actions.Handoff
 	provider synthesis.Provider
@@ -167,8 +189,26 @@ type Viewer struct {
 	// Chart rendering
 	reportDir   string    // report directory for locating chart PNGs
 	imageConfig string    // rendering.images config value
+	style       string    // markdown style: rendering.style or --style
+	accessible  bool      // ASCII indicators, no decorative color (rendering.accessible)
 	imageTier   ImageTier // detected terminal image capability
 	hasCharts   bool      // whether content contains charts
+	images      []string  // local image files the report references
+
+	// Baseline comparison
+	baseline string  // name of the baseline to pin to and diff against ("" = default)
+	diffOf   *Viewer // the report's viewer while its baseline diff is shown
+
+	// Report age
+	generated  time.Time     // when the report was created; zero if unknown
+	staleAfter time.Duration // age at which the staleness banner shows; 0 disables
+
+	// Notes (m): an input in the footer that appends to the report's notes.md
+	noting    bool
+	noteInput textinput.Model
+
+	coverage *reports.Coverage // the report's source coverage; nil if not recorded
+	length   reports.Length    // word count and reading time of the rendered text
 
 	statusMsg string
 	statusExp time.Time
@@ -187,7 +227,8 @@ func WithProvider(p synthesis.Provider) ViewerOption {
 	return func(v *Viewer) { v.provider = p }
 }
 
-// WithLedger provides a context ledger for gathering draft context.
+// WithLedger provides a context ledger for gathering draft context and
+// finding related reports.
 func WithLedger(l *bcontext.Ledger) ViewerOption {
 	return func(v *Viewer) { v.ledger = l }
 }
@@ -212,6 +253,39 @@ func WithImageConfig(images string) ViewerOption {
 	return func(v *Viewer) { v.imageConfig = images }
 }
 
+// WithStyle sets the markdown style (see RenderMarkdownStyle).
+func WithStyle(style string) ViewerOption {
+	return func(v *Viewer) { v.style = style }
+}
+
+// WithAccessible enables accessible mode: plain ASCII in place of
+// decorative Unicode indicators, and no color that carries meaning alone.
+func WithAccessible(accessible bool) ViewerOption {
+	return func(v *Viewer) { v.accessible = accessible }
+}
+
+// WithBaseline sets the named baseline the viewer pins reports to (b) and
+// diffs against (D). The default is reports.DefaultBaseline.
+func WithBaseline(name string) ViewerOption {
+	return func(v *Viewer) { v.baseline = name }
+}
+
+// WithGenerated provides the report's creation time, shown as an age in the
+// header.
+func WithGenerated(t time.Time) ViewerOption {
+	return func(v *Viewer) { v.generated = t }
+}
+
+// WithStaleAfter sets the report age at which a staleness banner appears
+// below the header. Zero disables the banner.
+func WithStaleAfter(d time.Duration) ViewerOption {
+	return func(v *Viewer) { v.staleAfter = d }
+}
+
+// DefaultStaleAfter is the staleness threshold used when WithStaleAfter is
+// not given.
+const DefaultStaleAfter = 3 * 24 * time.Hour
+
 // NewViewer creates a viewer with pre-rendered content.
 func NewViewer(title string, content string) Viewer {
 	return Viewer{
@@ -231,7 +305,16 @@ func buildViewer(title, raw, rendered string) Viewer {
 		headings:  extractHeadings(raw, rendered),
 		actions:   actions.ParseActions(raw),
 		links:     extractLinks(raw),
+		coverage:  parseCoverage(raw),
+		length:    reports.Measure(ansiPattern.ReplaceAllString(rendered, "")),
+	}
+}
+
+func parseCoverage(raw string) *reports.Coverage {
+	if c, ok := reports.ParseCoverage(raw); ok {
+		return &c
 	}
+	return nil
 }
 
 // newViewerWithRaw creates a viewer with both raw markdown and rendered content.
@@ -256,6 +339,8 @@ func (v Viewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			footerHeight = v.actionOverlayHeight() + 1
 		} else if v.showLinks {
 			footerHeight = v.linkOverlayHeight() + 1
+		} else if v.showRelated {
+			footerHeight = v.relatedOverlayHeight() + 1
 		}
 
 		if !v.ready {
@@ -271,7 +356,7 @@ func (v Viewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.MouseMsg:
 		if msg.Action == tea.MouseActionRelease && msg.Button == tea.MouseButtonLeft {
 			if v.zones != nil && v.zoneState != nil &&
-				!v.showActions && !v.showLinks && !v.busy {
+				!v.showActions && !v.showLinks && !v.showRelated && !v.busy {
 				for zoneID, url := range v.zoneState.urls {
 					if zi := v.zones.Get(zoneID); zi != nil && zi.InBounds(msg) {
 						if v.handoff != nil {
@@ -305,8 +390,18 @@ func (v Viewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return v, nil
 
+	case relatedResultMsg:
+		return v.handleRelatedResult(msg)
+
+	case draftTickMsg:
+		if v.drafting != nil {
+			return v, draftTick()
+		}
+		return v, nil
+
 	case draftResultMsg:
 		v.busy = false
+		v.drafting = nil
 		if msg.err != nil {
 			v.setStatus("Draft error: " + msg.err.Error())
 			return v, nil
@@ -322,12 +417,18 @@ func (v Viewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return v, nil
 		}
+		if v.noting {
+			return v.updateNoteInput(msg)
+		}
 		if v.showActions {
 			return v.updateActionOverlay(msg)
 		}
 		if v.showLinks {
 			return v.updateLinkOverlay(msg)
 		}
+		if v.showRelated {
+			return v.updateRelatedOverlay(msg)
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return v, tea.Quit
@@ -360,7 +461,9 @@ func (v Viewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return v, nil
 		case "i":
-			return v.openFirstChart()
+			return v.openImage()
+		case "r":
+			return v.startRelated()
 		case "enter", "tab":
 			idx := v.currentHeadingIdx()
 			if idx >= 0 {
@@ -375,9 +478,22 @@ func (v Viewer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return v, nil
 		case "p":
 			return v.startPlayAction()
+		case "b":
+			return v.pinBaseline()
+		case "D":
+			return v.toggleBaselineDiff()
+		case "m":
+			return v.startNote()
 		}
 	}
 
+	if v.noting {
+		// Keep the note input's cursor blinking.
+		var inputCmd tea.Cmd
+		v.noteInput, inputCmd = v.noteInput.Update(msg)
+		v.viewport, cmd = v.viewport.Update(msg)
+		return v, tea.Batch(inputCmd, cmd)
+	}
 	v.viewport, cmd = v.viewport.Update(msg)
 	return v, cmd
 }
@@ -388,21 +504,27 @@ func (v Viewer) View() string {
 		return "Loading..."
 	}
 
-	header := buildHeader(v.title, v.viewport.Width, v.imageTier)
+	now := time.Now()
+	header := buildHeader(v.headerTitle(now), v.viewport.Width, v.styleTier())
+	banner := v.staleBanner(now)
 
 	vpView := v.viewport.View()
 	vpView = v.wrapURLsForView(vpView) // zone marks + OSC 8
 
 	var footer string
-	if v.showActions {
+	if v.noting {
+		footer = " " + v.noteInput.View()
+	} else if v.showActions {
 		footer = v.renderActionOverlay()
 	} else if v.showLinks {
 		footer = v.renderLinkOverlay()
+	} else if v.showRelated {
+		footer = v.renderRelatedOverlay()
 	} else {
 		footer = v.buildFooter()
 	}
 
-	fullView := strings.Join([]string{header, "", vpView, "", footer}, "\n")
+	fullView := strings.Join([]string{header, banner, vpView, "", footer}, "\n")
 
 	if v.zones != nil {
 		return v.zones.Scan(fullView) // strips marks, records positions
@@ -427,18 +549,92 @@ func buildHeader(title string, width int, tier ImageTier) string {
 		Render(title)
 }
 
+// headerTitle returns the title with the report's age, source coverage,
+// and length appended, when known.
+func (v Viewer) headerTitle(now time.Time) string {
+	title := v.title
+	if !v.generated.IsZero() {
+		title += " · " + formatAge(now.Sub(v.generated))
+	}
+	if v.coverage != nil {
+		title += fmt.Sprintf(" · Coverage: %d/%d sources", v.coverage.Succeeded, v.coverage.Total)
+	}
+	if v.length.Words > 0 {
+		title += fmt.Sprintf(" · %s · %d min read", countUnit(v.length.Words, "word"), v.length.Minutes)
+	}
+	return title
+}
+
+// staleBanner returns the warning shown on the line below the header once
+// the report is older than staleAfter or its source coverage is low, or ""
+// to leave the line blank.
+func (v Viewer) staleBanner(now time.Time) string {
+	var warnings []string
+	if !v.generated.IsZero() && v.staleAfter > 0 {
+		if age := now.Sub(v.generated); age >= v.staleAfter {
+			warnings = append(warnings, "this report is "+formatSpan(age)+" old")
+		}
+	}
+	if v.coverage != nil && v.coverage.Low() {
+		warnings = append(warnings, fmt.Sprintf("low coverage: %d of %d sources failed",
+			v.coverage.Total-v.coverage.Succeeded, v.coverage.Total))
+	}
+	if len(warnings) == 0 {
+		return ""
+	}
+	msg := v.glyph("⚠", "Warning:") + " " + strings.Join(warnings, "; ")
+	if v.styleTier() == TierNone {
+		return staleStyle.Render(msg)
+	}
+	return tier1Renderer.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#F7768E")).
+		PaddingLeft(1).
+		Render(msg)
+}
+
+// formatAge describes how long ago something happened: "just now",
+// "5 minutes ago", "3 hours ago", "2 days ago".
+func formatAge(d time.Duration) string {
+	if d < time.Minute {
+		return "just now"
+	}
+	return formatSpan(d) + " ago"
+}
+
+// formatSpan renders d in its largest whole unit of minutes, hours, or days.
+func formatSpan(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return countUnit(int(d/(24*time.Hour)), "day")
+	case d >= time.Hour:
+		return countUnit(int(d/time.Hour), "hour")
+	default:
+		return countUnit(int(d/time.Minute), "minute")
+	}
+}
+
+func countUnit(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
 // buildFooter renders the footer hints. On Tier 1 terminals, key letters are
 // bold cyan, descriptions are muted, separators are dim, and status is amber.
 // On Tier 2, uses the existing plain gray style.
 func (v Viewer) buildFooter() string {
 	status := ""
-	if v.busy {
-		status = " • Working..."
+	if v.busy && v.drafting != nil {
+		status = v.glyph(" • ", " - ") + "Drafting: " + v.drafting.Tail(60)
+	} else if v.busy {
+		status = v.glyph(" • ", " - ") + "Working..."
 	} else if v.statusMsg != "" && time.Now().Before(v.statusExp) {
-		status = " • " + v.statusMsg
+		status = v.glyph(" • ", " - ") + v.statusMsg
 	}
 
-	if v.imageTier == TierNone {
+	if v.styleTier() == TierNone {
 		return v.buildFooterPlain(status)
 	}
 	return v.buildFooterStyled(status)
@@ -459,12 +655,27 @@ func (v Viewer) buildFooterPlain(status string) string {
 	}
 	if v.hasCharts && v.handoff != nil {
 		hints += " │ i open chart"
+	} else if len(v.images) > 0 && v.handoff != nil {
+		hints += " │ i open image"
 	}
 	if v.hasPlayActions() {
 		hints += " │ p play"
 	}
+	if v.ledger != nil {
+		hints += " │ r related"
+	}
+	if v.reportDir != "" || v.diffOf != nil {
+		hints += " │ b/D baseline"
+	}
+	if v.reportDir != "" && v.diffOf == nil {
+		hints += " │ m note"
+	}
 	hints += " │ q quit"
 
+	if v.accessible {
+		hints = strings.ReplaceAll(hints, " │ ", " | ")
+	}
+
 	return footerStyle.Render(fmt.Sprintf(hints+status, v.viewport.ScrollPercent()*100))
 }
 
@@ -492,10 +703,21 @@ func (v Viewer) buildFooterStyled(status string) string {
 	}
 	if v.hasCharts && v.handoff != nil {
 		parts = append(parts, keyStyle.Render("i")+descStyle.Render(" open chart"))
+	} else if len(v.images) > 0 && v.handoff != nil {
+		parts = append(parts, keyStyle.Render("i")+descStyle.Render(" open image"))
 	}
 	if v.hasPlayActions() {
 		parts = append(parts, keyStyle.Render("p")+descStyle.Render(" play"))
 	}
+	if v.ledger != nil {
+		parts = append(parts, keyStyle.Render("r")+descStyle.Render(" related"))
+	}
+	if v.reportDir != "" || v.diffOf != nil {
+		parts = append(parts, keyStyle.Render("b")+descStyle.Render("/")+keyStyle.Render("D")+descStyle.Render(" baseline"))
+	}
+	if v.reportDir != "" && v.diffOf == nil {
+		parts = append(parts, keyStyle.Render("m")+descStyle.Render(" note"))
+	}
 	parts = append(parts, keyStyle.Render("q")+descStyle.Render(" quit"))
 
 	result := strings.Join(parts, sep)
@@ -508,7 +730,7 @@ func (v Viewer) buildFooterStyled(status string) string {
 // RunViewer launches the interactive viewer for a report.
 func RunViewer(title string, markdown string, opts ...ViewerOption) error {
 	// Apply options to a minimal viewer to get imageConfig before rendering
-	v := Viewer{title: title, raw: markdown}
+	v := Viewer{title: title, raw: markdown, staleAfter: DefaultStaleAfter}
 	for _, opt := range opts {
 		opt(&v)
 	}
@@ -516,8 +738,11 @@ func RunViewer(title string, markdown string, opts ...ViewerOption) error {
 	// Detect tier early so it influences rendering style and hyperlinks
 	v.imageTier = DetectImageTier(v.imageConfig)
 
+	// Show the user's saved notes beneath the report.
+	markdown = withNotes(markdown, v.reportDir)
+
 	// Render markdown with tier-aware style
-	rendered, err := RenderMarkdown(markdown, 0, v.imageTier)
+	rendered, err := RenderMarkdownStyle(markdown, 0, v.style, v.imageTier)
 	if err != nil {
 		return err
 	}
@@ -532,19 +757,29 @@ func RunViewer(title string, markdown string, opts ...ViewerOption) error {
 	built.ctx = v.ctx
 	built.reportDir = v.reportDir
 	built.imageConfig = v.imageConfig
+	built.style = v.style
+	built.accessible = v.accessible
 	built.imageTier = v.imageTier
+	built.baseline = v.baseline
+	built.generated = v.generated
+	built.staleAfter = v.staleAfter
 	v = built
 
 	// Production-only: charts, zones, mouse
 	// Use TierNone for charts in the viewport — Kitty/iTerm floating images
 	// don't scroll with BubbleTea's line-based viewport. Text tables scroll
 	// correctly; press 'i' to open the full PNG in an external viewer.
-	v.content = processCharts(v.raw, v.content, v.reportDir, TierNone)
+	v.content = processCharts(v.raw, v.content, v.reportDir, TierNone, v.style, v.accessible)
 	v.hasCharts = hasChartDirectives(v.raw)
+	v.images = localImages(v.raw, v.reportDir)
 
-	// Refresh fullLines and headings after chart processing
-	v.fullLines = strings.Split(v.content, "\n")
+	// Refresh headings after chart processing, then color trends — after,
+	// since coloring splits heading text with escapes.
 	v.headings = extractHeadings(v.raw, v.content)
+	if !v.accessible {
+		v.content = processTrends(v.content, v.imageTier)
+	}
+	v.fullLines = strings.Split(v.content, "\n")
 
 	// Initialize BubbleZone for clickable URLs in the viewport.
 	// OSC 8 hyperlinks and zone marks are applied in View() on each frame.
@@ -678,6 +913,35 @@ func prependIndicator(line string, collapsed bool, tier ImageTier) string {
 	return insertAfterANSIPrefix(line, indicator)
 }
 
+// foldIndicator prepends a heading's fold indicator: prependIndicator's
+// arrows, or [+] (collapsed) and [-] (expanded) in accessible mode.
+func (v *Viewer) foldIndicator(line string, collapsed bool) string {
+	if !v.accessible {
+		return prependIndicator(line, collapsed, v.imageTier)
+	}
+	if collapsed {
+		return insertAfterANSIPrefix(line, "[+] ")
+	}
+	return insertAfterANSIPrefix(line, "[-] ")
+}
+
+// styleTier is the tier that decorative styling follows: the terminal's
+// tier, or TierNone in accessible mode so styling stays plain.
+func (v Viewer) styleTier() ImageTier {
+	if v.accessible {
+		return TierNone
+	}
+	return v.imageTier
+}
+
+// glyph returns fancy, or its plain ASCII replacement in accessible mode.
+func (v Viewer) glyph(fancy, plain string) string {
+	if v.accessible {
+		return plain
+	}
+	return fancy
+}
+
 // rebuildContent constructs visible content from fullLines, skipping collapsed
 // section bodies and adding fold indicators to headings.
 func (v *Viewer) rebuildContent() {
@@ -718,7 +982,7 @@ func (v *Viewer) rebuildContent() {
 		if hIdx, ok := headingAtLine[i]; ok {
 			h := v.headings[hIdx]
 			if h.level > 1 { // Only show indicators on collapsible headings
-				line = prependIndicator(line, h.collapsed, v.imageTier)
+				line = v.foldIndicator(line, h.collapsed)
 			}
 			v.headings[hIdx].viewLine = viewIdx
 		}
@@ -810,7 +1074,7 @@ func (v *Viewer) actionOverlayHeight() int {
 
 func (v Viewer) renderActionOverlay() string {
 	var b strings.Builder
-	b.WriteString(footerStyle.Render(" Actions (↑↓ navigate, enter execute, esc close):"))
+	b.WriteString(footerStyle.Render(" Actions (" + v.glyph("↑↓", "up/down") + " navigate, enter execute, esc close):"))
 	b.WriteString("\n")
 
 	maxShow := 8
@@ -825,7 +1089,7 @@ func (v Viewer) renderActionOverlay() string {
 			label += " (" + a.Target + ")"
 		}
 		if i == v.actionIdx {
-			b.WriteString(actionSelectedStyle.Render("▸ " + label))
+			b.WriteString(actionSelectedStyle.Render(v.glyph("▸ ", "> ") + label))
 		} else {
 			b.WriteString(actionNormalStyle.Render("  " + label))
 		}
@@ -873,7 +1137,7 @@ func (v *Viewer) linkOverlayHeight() int {
 
 func (v Viewer) renderLinkOverlay() string {
 	var b strings.Builder
-	b.WriteString(footerStyle.Render(" Links (↑↓ navigate, enter open, y copy, esc close):"))
+	b.WriteString(footerStyle.Render(" Links (" + v.glyph("↑↓", "up/down") + " navigate, enter open, y copy, esc close):"))
 	b.WriteString("\n")
 
 	maxShow := 8
@@ -909,7 +1173,7 @@ func (v Viewer) renderLinkOverlay() string {
 		}
 		line := fmt.Sprintf("  %s%s", urlDisplay, label)
 		if i == v.linkIdx {
-			b.WriteString(actionSelectedStyle.Render("▸ " + line))
+			b.WriteString(actionSelectedStyle.Render(v.glyph("▸ ", "> ") + line))
 		} else {
 			b.WriteString(actionNormalStyle.Render("  " + line))
 		}
@@ -1045,19 +1309,22 @@ func (v Viewer) startDraftFromAction(a actions.Action) (tea.Model, tea.Cmd) {
 	prof := v.profile
 	ctx := v.viewerContext()
 	v.busy = true
+	v.drafting = &synthesis.StreamBuffer{}
 	v.setStatus("Generating draft...")
 
-	return v, func() tea.Msg {
+	// Show the draft in the footer as it streams in.
+	stream := v.drafting
+	return v, tea.Batch(draftTick(), func() tea.Msg {
 		var contextData string
 		if ledger != nil {
 			contextData, _ = ledger.GatherContext(50_000)
 		}
-		draft, err := actions.GenerateDraft(ctx, provider, instruction, contextData, prof)
+		draft, err := actions.GenerateDraftStream(ctx, provider, instruction, contextData, prof, stream.Add)
 		if err != nil {
 			return draftResultMsg{err: err}
 		}
 		return draftResultMsg{raw: draft.Raw}
-	}
+	})
 }
 
 // hasPlayActions returns true if any actions are of type ActionPlay.
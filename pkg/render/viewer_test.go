@@ -2,10 +2,14 @@ package render
 
 import (
 	"context"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	bcontext "github.com/jcadam/burrow/pkg/context"
+	"github.com/jcadam/burrow/pkg/reports"
 	zone "github.com/lrstanley/bubblezone"
 )
 
@@ -194,6 +198,92 @@ func TestViewerFooterWithOptions(t *testing.T) {
 	}
 }
 
+func TestViewerHeaderShowsReportAge(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	v := newViewerWithRaw("Brief", "# Brief\n", "Brief")
+
+	if got := v.headerTitle(now); got != "Brief · 1 word · 1 min read" {
+		t.Errorf("headerTitle without generation time = %q", got)
+	}
+
+	v.generated = now.Add(-3 * time.Hour)
+	if got := v.headerTitle(now); got != "Brief · 3 hours ago · 1 word · 1 min read" {
+		t.Errorf("headerTitle = %q", got)
+	}
+}
+
+func TestViewerStaleBanner(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	v := newViewerWithRaw("Brief", "# Brief\n", "Brief")
+	v.staleAfter = DefaultStaleAfter
+
+	v.generated = now.Add(-2 * 24 * time.Hour)
+	if got := v.staleBanner(now); got != "" {
+		t.Errorf("fresh report should have no banner, got %q", got)
+	}
+
+	v.generated = now.Add(-5*24*time.Hour - time.Hour)
+	if got := v.staleBanner(now); !strings.Contains(got, "this report is 5 days old") {
+		t.Errorf("staleBanner = %q, want 5 days old warning", got)
+	}
+
+	v.staleAfter = 0
+	if got := v.staleBanner(now); got != "" {
+		t.Errorf("disabled threshold should have no banner, got %q", got)
+	}
+}
+
+func TestViewerCoverage(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	raw := "# Brief\n\n*Coverage: 1/4 sources (25%)*\n"
+	v := buildViewer("Brief", raw, "Brief")
+
+	if got := v.headerTitle(now); got != "Brief · Coverage: 1/4 sources · 1 word · 1 min read" {
+		t.Errorf("headerTitle = %q", got)
+	}
+	if got := v.staleBanner(now); !strings.Contains(got, "low coverage: 3 of 4 sources failed") {
+		t.Errorf("staleBanner = %q, want low coverage warning", got)
+	}
+
+	v = buildViewer("Brief", "# Brief\n\n*Coverage: 4/4 sources (100%)*\n", "Brief")
+	if got := v.staleBanner(now); got != "" {
+		t.Errorf("full coverage should have no banner, got %q", got)
+	}
+}
+
+func TestViewerLength(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	rendered := "\x1b[1mWeekly\x1b[0m brief\n\n" + strings.Repeat("contract awarded today ", 200)
+	v := buildViewer("Brief", "# Weekly brief\n", rendered)
+	if got := v.headerTitle(now); got != "Brief · 602 words · 3 min read" {
+		t.Errorf("headerTitle = %q", got)
+	}
+
+	if got := buildViewer("Brief", "", "").headerTitle(now); got != "Brief" {
+		t.Errorf("empty report headerTitle = %q, want no length", got)
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{30 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{45 * time.Minute, "45 minutes ago"},
+		{time.Hour + 10*time.Minute, "1 hour ago"},
+		{23 * time.Hour, "23 hours ago"},
+		{26 * time.Hour, "1 day ago"},
+		{8 * 24 * time.Hour, "8 days ago"},
+	}
+	for _, tt := range tests {
+		if got := formatAge(tt.d); got != tt.want {
+			t.Errorf("formatAge(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
 func TestViewerAsyncDraftReturnsCmd(t *testing.T) {
 	raw := "# Report\n\n[Draft] Write email\n"
 	rendered, _ := RenderMarkdown(raw, 80)
@@ -547,6 +637,72 @@ func TestIndicatorPrependTier1(t *testing.T) {
 	}
 }
 
+func TestViewerAccessibleIndicators(t *testing.T) {
+	raw := "# Title\n\nIntro.\n\n## Section A\n\nA text.\n"
+	rendered, _ := RenderMarkdown(raw, 80)
+	v := newViewerWithRaw("Test", raw, rendered)
+	WithAccessible(true)(&v)
+
+	var m tea.Model = v
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+	viewer := m.(Viewer)
+	idx := -1
+	for i, h := range viewer.headings {
+		if h.text == "Section A" {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		t.Fatal("Section A not found in headings")
+	}
+
+	viewer.toggleSection(idx)
+	if !strings.Contains(viewer.content, "[+] ") {
+		t.Errorf("expected [+] for collapsed section, got %q", viewer.content)
+	}
+	viewer.toggleSection(idx)
+	if !strings.Contains(viewer.content, "[-] ") {
+		t.Errorf("expected [-] for expanded section, got %q", viewer.content)
+	}
+	if strings.ContainsAny(viewer.content, "▼▸") {
+		t.Error("expected no Unicode fold indicators in accessible mode")
+	}
+}
+
+func TestViewerAccessibleOverlay(t *testing.T) {
+	raw := "# Report\n\n[Draft] Write email\n"
+	rendered, _ := RenderMarkdown(raw, 80)
+	v := newViewerWithRaw("Test", raw, rendered)
+	WithAccessible(true)(&v)
+
+	var m tea.Model = v
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	view := m.View()
+	if !strings.Contains(view, "> ") || !strings.Contains(view, "up/down navigate") {
+		t.Errorf("expected ASCII overlay markers, got %q", view)
+	}
+	if strings.ContainsAny(view, "▸↑↓│") {
+		t.Errorf("expected no Unicode glyphs in accessible overlay, got %q", view)
+	}
+}
+
+func TestViewerAccessibleFooter(t *testing.T) {
+	raw := "# Report\n\n## Section\n\n[Draft] Write email\n"
+	rendered, _ := RenderMarkdown(raw, 80)
+	v := newViewerWithRaw("Test", raw, rendered)
+	WithAccessible(true)(&v)
+	v.imageTier = TierKitty
+
+	footer := v.buildFooter()
+	if !strings.Contains(footer, " | a actions") {
+		t.Errorf("expected plain ASCII footer, got %q", footer)
+	}
+	if strings.Contains(footer, "│") || strings.Contains(footer, "\x1b[") {
+		t.Errorf("expected no box drawing or color in accessible footer, got %q", footer)
+	}
+}
+
 func TestInsertAfterANSIPrefix(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -1060,3 +1216,95 @@ type mockProvider struct{}
 func (m *mockProvider) Complete(_ context.Context, _, _ string) (string, error) {
 	return "mock draft response", nil
 }
+
+func TestViewerBaselineDiff(t *testing.T) {
+	reportsDir := t.TempDir()
+	base, err := reports.Save(reportsDir, "brief", "# Brief\n\n## Weather\n\nSunny.\n\n## Contracts\n\nNone.\n", nil)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	current, err := reports.Save(reportsDir, "brief-pm", "# Brief\n\n## Weather\n\nSunny.\n\n## Contracts\n\nTwo new awards.\n", nil)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Pin the first report from its viewer.
+	rendered, _ := RenderMarkdown(base.Markdown, 80)
+	v := newViewerWithRaw("Brief", base.Markdown, rendered)
+	WithReportDir(base.Dir)(&v)
+	var m tea.Model = v
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	if got := m.(Viewer).statusMsg; !strings.Contains(got, `Pinned as baseline "default"`) {
+		t.Fatalf("status = %q", got)
+	}
+
+	// Diff the second report against it, then toggle back.
+	rendered, _ = RenderMarkdown(current.Markdown, 80)
+	v = newViewerWithRaw("Brief", current.Markdown, rendered)
+	WithReportDir(current.Dir)(&v)
+	m = v
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	diff := m.(Viewer)
+	if diff.diffOf == nil {
+		t.Fatalf("expected the diff view, status %q", diff.statusMsg)
+	}
+	for _, want := range []string{"Contracts (changed)", "Two new awards", "None.", "Unchanged", "Weather"} {
+		if !strings.Contains(diff.raw, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff.raw)
+		}
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'D'}})
+	if back := m.(Viewer); back.diffOf != nil || back.raw != current.Markdown {
+		t.Error("expected D to return to the report")
+	}
+}
+
+func TestViewerNote(t *testing.T) {
+	dir := t.TempDir()
+	ledger, err := bcontext.NewLedger(filepath.Join(dir, "context"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := reports.Save(filepath.Join(dir, "reports"), "brief", "# Brief\n\nQuiet day.\n", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rendered, _ := RenderMarkdown(report.Markdown, 80)
+	v := newViewerWithRaw("Brief", report.Markdown, rendered)
+	WithReportDir(report.Dir)(&v)
+	WithLedger(ledger)(&v)
+	var m tea.Model = v
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	if !m.(Viewer).noting {
+		t.Fatal("m did not open the note input")
+	}
+	// Keys go to the input, not the viewer: q types rather than quits.
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("quote the NGA contract")})
+	if cmd != nil {
+		if _, quit := cmd().(tea.QuitMsg); quit {
+			t.Fatal("typing q in the note input quit the viewer")
+		}
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := m.(Viewer); got.noting || !strings.Contains(got.statusMsg, "Note saved") {
+		t.Fatalf("after enter: noting = %v, status = %q", got.noting, got.statusMsg)
+	}
+
+	notes, err := reports.LoadNotes(report.Dir)
+	if err != nil || len(notes) != 1 || notes[0].Text != "quote the NGA contract" {
+		t.Fatalf("LoadNotes = %+v, %v", notes, err)
+	}
+	entries, err := ledger.List(bcontext.TypeNote, 0)
+	if err != nil || len(entries) != 1 || !strings.Contains(entries[0].Content, "quote the NGA contract") {
+		t.Errorf("ledger notes = %+v, %v", entries, err)
+	}
+
+	// The note shows beneath the report next time it is opened.
+	if got := withNotes(report.Markdown, report.Dir); !strings.Contains(got, "## Your notes") {
+		t.Errorf("withNotes = %q", got)
+	}
+}
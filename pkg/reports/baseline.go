@@ -0,0 +1,188 @@
+package reports
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BaselinesFile holds named baselines in the reports directory: a YAML
+// mapping from baseline name to report directory name.
+const BaselinesFile = "baselines.yaml"
+
+// DefaultBaseline is the baseline name used when none is given.
+const DefaultBaseline = "default"
+
+// LoadBaselines reads the named baselines in baseDir. A missing file means
+// no baselines.
+func LoadBaselines(baseDir string) (map[string]string, error) {
+	data, err := os.ReadFile(filepath.Join(baseDir, BaselinesFile))
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading baselines: %w", err)
+	}
+	baselines := map[string]string{}
+	if err := yaml.Unmarshal(data, &baselines); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", BaselinesFile, err)
+	}
+	return baselines, nil
+}
+
+// SetBaseline records reportDir, a report directory inside baseDir, as the
+// baseline called name.
+func SetBaseline(baseDir, name, reportDir string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("baseline name is empty")
+	}
+	baselines, err := LoadBaselines(baseDir)
+	if err != nil {
+		return err
+	}
+	baselines[name] = filepath.Base(reportDir)
+	data, err := yaml.Marshal(baselines)
+	if err != nil {
+		return fmt.Errorf("marshaling baselines: %w", err)
+	}
+	return writeAtomic(filepath.Join(baseDir, BaselinesFile), data)
+}
+
+// LoadBaseline loads the report recorded as the baseline called name.
+func LoadBaseline(baseDir, name string) (*Report, error) {
+	baselines, err := LoadBaselines(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	dir, ok := baselines[name]
+	if !ok {
+		return nil, fmt.Errorf("no baseline %q", name)
+	}
+	r, err := Load(filepath.Join(baseDir, dir))
+	if err != nil {
+		return nil, fmt.Errorf("baseline %q: %w", name, err)
+	}
+	return r, nil
+}
+
+// SectionStatus is how a section differs from the baseline.
+type SectionStatus int
+
+const (
+	SectionUnchanged SectionStatus = iota
+	SectionChanged
+	SectionAdded
+	SectionRemoved
+)
+
+// SectionChange is one section in a diff against a baseline. Body is the
+// current text, or the baseline's for a removed section.
+type SectionChange struct {
+	Heading      string
+	Level        int
+	Status       SectionStatus
+	Body         string
+	BaselineBody string // the baseline's text, for a changed section
+}
+
+// diffHeadingPattern matches the level 2–6 headings that divide a report
+// into sections; the level 1 title is not a section.
+var diffHeadingPattern = regexp.MustCompile(`^(#{2,6})\s+(.+?)\s*#*\s*$`)
+
+type section struct {
+	heading string
+	level   int
+	body    string
+}
+
+// splitSections divides markdown at its headings. Text before the first
+// heading is not a section.
+func splitSections(markdown string) []section {
+	var sections []section
+	var body []string
+	inFence := false
+	flush := func() {
+		if len(sections) > 0 {
+			sections[len(sections)-1].body = strings.TrimSpace(strings.Join(body, "\n"))
+		}
+		body = nil
+	}
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if m := diffHeadingPattern.FindStringSubmatch(line); m != nil && !inFence {
+			flush()
+			sections = append(sections, section{heading: m[2], level: len(m[1])})
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	return sections
+}
+
+// sectionKey identifies a section across reports: its heading without
+// emphasis or case, numbered when a heading repeats.
+func sectionKey(heading string, seen map[string]int) string {
+	key := strings.ToLower(strings.TrimSpace(strings.Trim(heading, "*_` ")))
+	seen[key]++
+	if n := seen[key]; n > 1 {
+		key = fmt.Sprintf("%s#%d", key, n)
+	}
+	return key
+}
+
+// DiffSections compares current against baseline section by section,
+// matching sections by heading. Sections are returned in current order,
+// with removed sections after them in baseline order. Whitespace
+// differences don't count as changes.
+func DiffSections(baseline, current string) []SectionChange {
+	base := map[string]section{}
+	var baseOrder []string
+	seen := map[string]int{}
+	for _, s := range splitSections(baseline) {
+		key := sectionKey(s.heading, seen)
+		base[key] = s
+		baseOrder = append(baseOrder, key)
+	}
+
+	var changes []SectionChange
+	matched := map[string]bool{}
+	seen = map[string]int{}
+	for _, s := range splitSections(current) {
+		key := sectionKey(s.heading, seen)
+		c := SectionChange{Heading: s.heading, Level: s.level, Body: s.body, Status: SectionAdded}
+		if b, ok := base[key]; ok {
+			matched[key] = true
+			c.Status = SectionChanged
+			c.BaselineBody = b.body
+			if strings.Join(strings.Fields(b.body), " ") == strings.Join(strings.Fields(s.body), " ") {
+				c.Status, c.BaselineBody = SectionUnchanged, ""
+			}
+		}
+		changes = append(changes, c)
+	}
+	for _, key := range baseOrder {
+		if !matched[key] {
+			b := base[key]
+			changes = append(changes, SectionChange{Heading: b.heading, Level: b.level, Body: b.body, Status: SectionRemoved})
+		}
+	}
+	return changes
+}
+
+// BaselineNames returns the baseline names in baselines, sorted.
+func BaselineNames(baselines map[string]string) []string {
+	names := make([]string, 0, len(baselines))
+	for name := range baselines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
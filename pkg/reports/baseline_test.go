@@ -0,0 +1,70 @@
+package reports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffSections(t *testing.T) {
+	baseline := "# Brief — Monday\n\n## Weather\n\nSunny.\n\n## Contracts\n\n- A\n- B\n\n## Retired\n\nGone next week.\n"
+	current := "# Brief — Tuesday\n\n## Weather\n\nSunny.  \n\n## **Contracts**\n\n- A\n- C\n\n## Filings\n\n```\n## not a heading\n```\n"
+
+	changes := DiffSections(baseline, current)
+	want := []struct {
+		heading string
+		status  SectionStatus
+	}{
+		{"Weather", SectionUnchanged},
+		{"**Contracts**", SectionChanged},
+		{"Filings", SectionAdded},
+		{"Retired", SectionRemoved},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d: %+v", len(changes), len(want), changes)
+	}
+	for i, w := range want {
+		if changes[i].Heading != w.heading || changes[i].Status != w.status {
+			t.Errorf("change %d = %q/%d, want %q/%d", i, changes[i].Heading, changes[i].Status, w.heading, w.status)
+		}
+	}
+	if changes[1].BaselineBody != "- A\n- B" || changes[1].Body != "- A\n- C" {
+		t.Errorf("changed bodies = %q / %q", changes[1].Body, changes[1].BaselineBody)
+	}
+	if !strings.Contains(changes[2].Body, "## not a heading") {
+		t.Error("a heading inside a code fence should stay in the body")
+	}
+}
+
+func TestBaselines(t *testing.T) {
+	dir := t.TempDir()
+	first, err := Save(dir, "brief", "# Brief\n\n## A\n\nOne.\n", nil)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if _, err := LoadBaseline(dir, DefaultBaseline); err == nil {
+		t.Error("expected an error for a missing baseline")
+	}
+	if err := SetBaseline(dir, DefaultBaseline, first.Dir); err != nil {
+		t.Fatalf("SetBaseline: %v", err)
+	}
+	base, err := LoadBaseline(dir, DefaultBaseline)
+	if err != nil {
+		t.Fatalf("LoadBaseline: %v", err)
+	}
+	if base.Dir != first.Dir {
+		t.Errorf("baseline dir = %s, want %s", base.Dir, first.Dir)
+	}
+
+	// Stored as plain YAML, and ignored when listing reports.
+	data, _ := os.ReadFile(filepath.Join(dir, BaselinesFile))
+	if !strings.Contains(string(data), "default: "+filepath.Base(first.Dir)) {
+		t.Errorf("unexpected baselines file:\n%s", data)
+	}
+	all, err := List(dir)
+	if err != nil || len(all) != 1 {
+		t.Errorf("List = %d reports, %v; want 1", len(all), err)
+	}
+}
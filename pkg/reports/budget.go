@@ -0,0 +1,57 @@
+package reports
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BudgetExceeded records that a run hit its routine's request budget and
+// refused the requests past it, so the report is built on partial data.
+type BudgetExceeded struct {
+	Max     int // requests the budget allowed
+	Refused int // requests not sent
+}
+
+// String renders the marker as it appears in report.md, e.g.
+// "Request budget of 20 exceeded: 3 request(s) not sent".
+func (b BudgetExceeded) String() string {
+	return fmt.Sprintf("Request budget of %d exceeded: %d request(s) not sent", b.Max, b.Refused)
+}
+
+// budgetPattern matches the budget line written under a report's title.
+var budgetPattern = regexp.MustCompile(`(?m)^\*Request budget of (\d+) exceeded: (\d+) request\(s\) not sent\*[ \t]*$`)
+
+// InsertBudgetExceeded puts the budget line under the markdown's coverage
+// line, or under its level 1 title when there is none. An existing budget
+// line is replaced.
+func InsertBudgetExceeded(markdown string, b BudgetExceeded) string {
+	line := "*" + b.String() + "*"
+	if budgetPattern.MatchString(markdown) {
+		return budgetPattern.ReplaceAllLiteralString(markdown, line)
+	}
+	if loc := coveragePattern.FindStringIndex(markdown); loc != nil {
+		return markdown[:loc[1]] + "\n\n" + line + markdown[loc[1]:]
+	}
+	lines := strings.Split(markdown, "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, "# ") {
+			rest := strings.TrimLeft(strings.Join(lines[i+1:], "\n"), "\n")
+			return strings.Join(lines[:i+1], "\n") + "\n\n" + line + "\n\n" + rest
+		}
+	}
+	return line + "\n\n" + markdown
+}
+
+// ParseBudgetExceeded reads the budget line from a report's markdown, if
+// its run hit the request budget.
+func ParseBudgetExceeded(markdown string) (BudgetExceeded, bool) {
+	m := budgetPattern.FindStringSubmatch(markdown)
+	if m == nil {
+		return BudgetExceeded{}, false
+	}
+	max, _ := strconv.Atoi(m[1])
+	refused, _ := strconv.Atoi(m[2])
+	return BudgetExceeded{Max: max, Refused: refused}, true
+}
@@ -0,0 +1,29 @@
+package reports
+
+import "testing"
+
+func TestInsertBudgetExceeded(t *testing.T) {
+	b := BudgetExceeded{Max: 20, Refused: 3}
+
+	got := InsertBudgetExceeded("# Brief\n\n*Coverage: 3/5 sources (60%)*\n\nBody\n", b)
+	want := "# Brief\n\n*Coverage: 3/5 sources (60%)*\n\n*Request budget of 20 exceeded: 3 request(s) not sent*\n\nBody\n"
+	if got != want {
+		t.Errorf("InsertBudgetExceeded = %q, want %q", got, want)
+	}
+
+	// An existing line is replaced, not repeated.
+	again := InsertBudgetExceeded(want, BudgetExceeded{Max: 20, Refused: 5})
+	if again != "# Brief\n\n*Coverage: 3/5 sources (60%)*\n\n*Request budget of 20 exceeded: 5 request(s) not sent*\n\nBody\n" {
+		t.Errorf("replaced InsertBudgetExceeded = %q", again)
+	}
+}
+
+func TestParseBudgetExceeded(t *testing.T) {
+	if _, ok := ParseBudgetExceeded("# Brief\n\nBody\n"); ok {
+		t.Error("expected no budget line")
+	}
+	md := InsertBudgetExceeded("# Brief\n\nBody\n", BudgetExceeded{Max: 4, Refused: 2})
+	if b, ok := ParseBudgetExceeded(md); !ok || b.Max != 4 || b.Refused != 2 {
+		t.Errorf("ParseBudgetExceeded = %+v, %v", b, ok)
+	}
+}
@@ -0,0 +1,72 @@
+package reports
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LowCoverage is the weighted score below which a report's coverage is
+// flagged as low.
+const LowCoverage = 0.5
+
+// Coverage is how many of a run's sources returned data. Score weights
+// required sources double, so losing one costs more than losing an
+// optional source.
+type Coverage struct {
+	Succeeded int     `json:"succeeded"` // sources that returned a result, empty or not
+	Total     int     `json:"total"`     // sources queried
+	Score     float64 `json:"score"`     // weighted fraction of sources that succeeded, 0–1
+}
+
+// Low reports whether the score is below LowCoverage.
+func (c Coverage) Low() bool {
+	return c.Total > 0 && c.Score < LowCoverage
+}
+
+// String renders the coverage as it appears in report.md, e.g.
+// "Coverage: 3/5 sources (67%)".
+func (c Coverage) String() string {
+	return fmt.Sprintf("Coverage: %d/%d sources (%d%%)", c.Succeeded, c.Total, int(c.Score*100+0.5))
+}
+
+// coveragePattern matches the coverage line written under a report's title.
+var coveragePattern = regexp.MustCompile(`(?m)^\*Coverage: (\d+)/(\d+) sources \((\d+)%\)\*[ \t]*$`)
+
+// CoverageLine is the markdown line recording c under a report's title.
+func CoverageLine(c Coverage) string {
+	return "*" + c.String() + "*"
+}
+
+// InsertCoverage puts the coverage line under the markdown's level 1
+// title, or at the top when there is none. An existing coverage line is
+// replaced.
+func InsertCoverage(markdown string, c Coverage) string {
+	line := CoverageLine(c)
+	if coveragePattern.MatchString(markdown) {
+		return coveragePattern.ReplaceAllLiteralString(markdown, line)
+	}
+	lines := strings.Split(markdown, "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, "# ") {
+			rest := strings.TrimLeft(strings.Join(lines[i+1:], "\n"), "\n")
+			return strings.Join(lines[:i+1], "\n") + "\n\n" + line + "\n\n" + rest
+		}
+	}
+	return line + "\n\n" + markdown
+}
+
+// ParseCoverage reads the coverage line from a report's markdown. An
+// appended report has one per sample; the last one wins.
+func ParseCoverage(markdown string) (Coverage, bool) {
+	all := coveragePattern.FindAllStringSubmatch(markdown, -1)
+	if len(all) == 0 {
+		return Coverage{}, false
+	}
+	m := all[len(all)-1]
+	succeeded, _ := strconv.Atoi(m[1])
+	total, _ := strconv.Atoi(m[2])
+	pct, _ := strconv.Atoi(m[3])
+	return Coverage{Succeeded: succeeded, Total: total, Score: float64(pct) / 100}, true
+}
@@ -0,0 +1,42 @@
+package reports
+
+import "testing"
+
+func TestInsertCoverage(t *testing.T) {
+	c := Coverage{Succeeded: 3, Total: 5, Score: 0.6}
+
+	got := InsertCoverage("# Brief\n\nBody\n", c)
+	want := "# Brief\n\n*Coverage: 3/5 sources (60%)*\n\nBody\n"
+	if got != want {
+		t.Errorf("InsertCoverage = %q, want %q", got, want)
+	}
+
+	if got := InsertCoverage("Body\n", c); got != "*Coverage: 3/5 sources (60%)*\n\nBody\n" {
+		t.Errorf("untitled InsertCoverage = %q", got)
+	}
+
+	// An existing line is replaced, not repeated.
+	again := InsertCoverage(want, Coverage{Succeeded: 5, Total: 5, Score: 1})
+	if again != "# Brief\n\n*Coverage: 5/5 sources (100%)*\n\nBody\n" {
+		t.Errorf("replaced InsertCoverage = %q", again)
+	}
+}
+
+func TestParseCoverage(t *testing.T) {
+	if _, ok := ParseCoverage("# Brief\n\nNo coverage here.\n"); ok {
+		t.Error("expected no coverage")
+	}
+
+	// Appended samples each record coverage; the latest applies.
+	md := "# Brief\n\n*Coverage: 4/4 sources (100%)*\n\n---\n\n## Sample at 15:00\n\n*Coverage: 1/4 sources (20%)*\n"
+	c, ok := ParseCoverage(md)
+	if !ok || c.Succeeded != 1 || c.Total != 4 || c.Score != 0.2 {
+		t.Fatalf("ParseCoverage = %+v, %v", c, ok)
+	}
+	if !c.Low() {
+		t.Error("20% coverage should be low")
+	}
+	if (Coverage{Succeeded: 3, Total: 4, Score: 0.75}).Low() {
+		t.Error("75% coverage should not be low")
+	}
+}
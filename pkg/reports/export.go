@@ -3,6 +3,7 @@ package reports
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"html"
 	"os"
@@ -13,7 +14,10 @@ import (
 
 	"github.com/jcadam/burrow/pkg/charts"
 	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
 )
 
 // lookPath is an injectable wrapper around exec.LookPath for testing.
@@ -113,15 +117,61 @@ func convertHTMLToPDF(conv *PDFConverter, htmlContent string) ([]byte, error) {
 	return pdfData, nil
 }
 
-// ExportHTML converts markdown to a self-contained HTML document.
-// If reportDir is non-empty and contains a charts/ subdirectory, chart
-// fenced code blocks are replaced with embedded PNG images (base64 data URIs).
+// exportedReport is the JSON export of a report.
+type exportedReport struct {
+	Routine     string    `json:"routine"`
+	Title       string    `json:"title,omitempty"`
+	Date        string    `json:"date"`
+	Dir         string    `json:"dir"`
+	Coverage    *Coverage `json:"coverage,omitempty"`
+	Words       int       `json:"words"`
+	Minutes     int       `json:"reading_minutes"`
+	Markdown    string    `json:"markdown"`
+	Sources     []string  `json:"sources,omitempty"`
+	Charts      []string  `json:"charts,omitempty"`
+	Attachments []string  `json:"attachments,omitempty"`
+}
+
+// ExportJSON renders a report as an indented JSON document: its metadata,
+// source coverage when recorded, word count and reading time, the markdown,
+// and the paths of its files.
+func ExportJSON(r *Report) ([]byte, error) {
+	out := exportedReport{
+		Routine:     r.Routine,
+		Title:       r.Title,
+		Date:        r.Date,
+		Dir:         r.Dir,
+		Markdown:    r.Markdown,
+		Sources:     r.Sources,
+		Charts:      r.Charts,
+		Attachments: r.Attachments,
+	}
+	length := Measure(r.Markdown)
+	out.Words, out.Minutes = length.Words, length.Minutes
+	if c, ok := ParseCoverage(r.Markdown); ok {
+		out.Coverage = &c
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding JSON: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// ExportHTML converts markdown to a self-contained HTML document, with a
+// table of contents linking to the report's section headings and a theme
+// that follows the reader's light or dark preference. If reportDir is
+// non-empty and contains a charts/ subdirectory, chart fenced code blocks
+// are replaced with embedded PNG images (base64 data URIs).
 func ExportHTML(markdown, title, reportDir string) (string, error) {
-	var buf bytes.Buffer
 	md := goldmark.New(
 		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
 	)
-	if err := md.Convert([]byte(markdown), &buf); err != nil {
+	source := []byte(markdown)
+	doc := md.Parser().Parse(text.NewReader(source))
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, source, doc); err != nil {
 		return "", fmt.Errorf("converting markdown to HTML: %w", err)
 	}
 
@@ -134,24 +184,114 @@ func ExportHTML(markdown, title, reportDir string) (string, error) {
 <head>
 <meta charset="utf-8">
 <meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="color-scheme" content="light dark">
 <title>%s</title>
 <style>
-  body { max-width: 48em; margin: 2em auto; padding: 0 1em; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.6; color: #1a1a1a; }
+  :root { --fg: #1a1a1a; --bg: #ffffff; --muted: #555; --code-bg: #f4f4f4; --border: #ddd; --link: #0b57d0; }
+  @media screen and (prefers-color-scheme: dark) {
+    :root { --fg: #e3e3e3; --bg: #1b1b1f; --muted: #a0a0a8; --code-bg: #2a2a30; --border: #44444c; --link: #8ab4f8; }
+    img { background: #ffffff; }
+  }
+  body { max-width: 48em; margin: 2em auto; padding: 0 1em; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; line-height: 1.6; color: var(--fg); background: var(--bg); }
+  a { color: var(--link); }
   h1, h2, h3 { margin-top: 1.5em; }
-  code { background: #f4f4f4; padding: 0.15em 0.3em; border-radius: 3px; font-size: 0.9em; }
-  pre { background: #f4f4f4; padding: 1em; border-radius: 4px; overflow-x: auto; }
+  code { background: var(--code-bg); padding: 0.15em 0.3em; border-radius: 3px; font-size: 0.9em; }
+  pre { background: var(--code-bg); padding: 1em; border-radius: 4px; overflow-x: auto; }
   pre code { background: none; padding: 0; }
-  blockquote { border-left: 3px solid #ccc; margin-left: 0; padding-left: 1em; color: #555; }
+  blockquote { border-left: 3px solid var(--border); margin-left: 0; padding-left: 1em; color: var(--muted); }
   table { border-collapse: collapse; width: 100%%; }
-  th, td { border: 1px solid #ddd; padding: 0.5em; text-align: left; }
-  th { background: #f4f4f4; }
+  th, td { border: 1px solid var(--border); padding: 0.5em; text-align: left; }
+  th { background: var(--code-bg); }
   img { max-width: 100%%; height: auto; }
+  nav.toc { border: 1px solid var(--border); border-radius: 4px; padding: 0.5em 1em; margin: 1.5em 0; }
+  nav.toc ul { list-style: none; padding-left: 0; margin: 0.25em 0; }
+  nav.toc li.toc-h3 { padding-left: 1.25em; }
 </style>
 </head>
 <body>
-%s
+%s%s
 </body>
-</html>`, escaped, body), nil
+</html>`, escaped, tableOfContents(doc, source), body), nil
+}
+
+// tocEntry is one heading listed in an HTML export's table of contents.
+type tocEntry struct {
+	Level int
+	ID    string
+	Text  string
+}
+
+// tableOfContents renders a navigation list linking to the document's
+// level 2 and 3 headings. A report with fewer than two such headings gets
+// none; there is nothing to navigate.
+func tableOfContents(doc ast.Node, source []byte) string {
+	var entries []tocEntry
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		h, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		if h.Level == 2 || h.Level == 3 {
+			id, _ := h.AttributeString("id")
+			idBytes, _ := id.([]byte)
+			entries = append(entries, tocEntry{Level: h.Level, ID: string(idBytes), Text: headingText(h, source)})
+		}
+		return ast.WalkSkipChildren, nil
+	})
+	if len(entries) < 2 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("<nav class=\"toc\">\n<strong>Contents</strong>\n<ul>\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "<li class=\"toc-h%d\"><a href=\"#%s\">%s</a></li>\n", e.Level, html.EscapeString(e.ID), html.EscapeString(e.Text))
+	}
+	b.WriteString("</ul>\n</nav>\n")
+	return b.String()
+}
+
+// headingText returns a heading's plain text, without markup.
+func headingText(n ast.Node, source []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := c.(type) {
+		case *ast.Text:
+			b.Write(t.Segment.Value(source))
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				b.WriteByte(' ')
+			}
+		case *ast.String:
+			b.Write(t.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(b.String())
+}
+
+// HTMLFile is the standalone HTML copy of report.md that SaveHTML writes
+// beside it.
+const HTMLFile = "report.html"
+
+// SaveHTML exports the report as HTML into report.html in its directory,
+// replacing any earlier copy, and returns the file's path.
+func SaveHTML(r *Report) (string, error) {
+	title := r.Title
+	if title == "" {
+		title = r.Routine + " — " + r.Date
+	}
+	doc, err := ExportHTML(r.Markdown, title, r.Dir)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(r.Dir, HTMLFile)
+	if err := writeAtomic(path, []byte(doc)); err != nil {
+		return "", fmt.Errorf("writing %s: %w", HTMLFile, err)
+	}
+	return path, nil
 }
 
 // chartCodeBlockPattern matches goldmark's output for ```chart code blocks.
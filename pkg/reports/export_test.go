@@ -1,6 +1,7 @@
 package reports
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -30,6 +31,45 @@ func TestExportHTML(t *testing.T) {
 	}
 }
 
+func TestExportJSON(t *testing.T) {
+	r := &Report{
+		Dir:      "/reports/2026-03-10T070000-brief",
+		Routine:  "brief",
+		Title:    "Brief",
+		Date:     "2026-03-10",
+		Markdown: "# Brief\n\n*Coverage: 3/4 sources (75%)*\n\nBody.\n",
+	}
+	data, err := ExportJSON(r)
+	if err != nil {
+		t.Fatalf("ExportJSON: %v", err)
+	}
+
+	var got struct {
+		Routine  string
+		Title    string
+		Markdown string
+		Coverage *Coverage
+		Sources  []string
+		Words    int
+		Minutes  int `json:"reading_minutes"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	if got.Routine != "brief" || got.Title != "Brief" || got.Markdown != r.Markdown {
+		t.Errorf("unexpected export %+v", got)
+	}
+	if got.Coverage == nil || got.Coverage.Succeeded != 3 || got.Coverage.Total != 4 {
+		t.Errorf("coverage = %+v", got.Coverage)
+	}
+	if got.Words != 7 || got.Minutes != 1 {
+		t.Errorf("length = %d words, %d minutes; want 7 words, 1 minute", got.Words, got.Minutes)
+	}
+	if strings.Contains(string(data), `"sources"`) {
+		t.Error("empty file lists should be omitted")
+	}
+}
+
 func TestExportHTMLEscapesTitle(t *testing.T) {
 	html, err := ExportHTML("# Test\n", `Title with "quotes" & <brackets>`, "")
 	if err != nil {
@@ -245,3 +285,49 @@ func TestExportHTMLChartFallbackTable(t *testing.T) {
 		t.Error("expected label A in table")
 	}
 }
+
+func TestExportHTMLTableOfContents(t *testing.T) {
+	md := "# Brief\n\n## Markets & *Rates*\n\nText.\n\n### Bonds\n\nMore.\n\n## Outlook\n\nEnd.\n"
+	html, err := ExportHTML(md, "Brief", "")
+	if err != nil {
+		t.Fatalf("ExportHTML: %v", err)
+	}
+	for _, want := range []string{
+		`<nav class="toc">`,
+		`<li class="toc-h2"><a href="#markets--rates">Markets &amp; Rates</a></li>`,
+		`<li class="toc-h3"><a href="#bonds">Bonds</a></li>`,
+		`<h2 id="outlook">Outlook</h2>`,
+		"prefers-color-scheme: dark",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML missing %q", want)
+		}
+	}
+	if strings.Contains(html, `href="#brief"`) {
+		t.Error("the title should not be listed")
+	}
+
+	// A single section has nothing to navigate.
+	html, _ = ExportHTML("# Brief\n\n## Only\n\nText.\n", "Brief", "")
+	if strings.Contains(html, `<nav class="toc">`) {
+		t.Error("expected no table of contents for one section")
+	}
+}
+
+func TestSaveHTML(t *testing.T) {
+	dir := t.TempDir()
+	path, err := SaveHTML(&Report{Dir: dir, Routine: "brief", Date: "2026-03-01", Markdown: "# Brief\n\nBody.\n"})
+	if err != nil {
+		t.Fatalf("SaveHTML: %v", err)
+	}
+	if path != filepath.Join(dir, HTMLFile) {
+		t.Errorf("path = %q", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "<title>brief — 2026-03-01</title>") {
+		t.Errorf("report.html = %s", data)
+	}
+}
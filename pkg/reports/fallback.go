@@ -0,0 +1,54 @@
+package reports
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Fallback records that a report was synthesized by a fallback LLM
+// provider because the providers before it in the chain failed.
+type Fallback struct {
+	Provider string   // provider that wrote the report
+	Failed   []string // providers that failed first, in the order tried
+}
+
+// String renders the fallback as it appears in report.md, e.g.
+// "Synthesized by fallback provider cloud/gpt (local/llama3 failed)".
+func (f Fallback) String() string {
+	return fmt.Sprintf("Synthesized by fallback provider %s (%s failed)", f.Provider, strings.Join(f.Failed, ", "))
+}
+
+// fallbackPattern matches the fallback line written under a report's title.
+var fallbackPattern = regexp.MustCompile(`(?m)^\*Synthesized by fallback provider (.+) \((.+) failed\)\*[ \t]*$`)
+
+// InsertFallback puts the fallback line under the markdown's coverage line,
+// or under its level 1 title when there is none. An existing fallback line
+// is replaced.
+func InsertFallback(markdown string, f Fallback) string {
+	line := "*" + f.String() + "*"
+	if fallbackPattern.MatchString(markdown) {
+		return fallbackPattern.ReplaceAllLiteralString(markdown, line)
+	}
+	if loc := coveragePattern.FindStringIndex(markdown); loc != nil {
+		return markdown[:loc[1]] + "\n\n" + line + markdown[loc[1]:]
+	}
+	lines := strings.Split(markdown, "\n")
+	for i, l := range lines {
+		if strings.HasPrefix(l, "# ") {
+			rest := strings.TrimLeft(strings.Join(lines[i+1:], "\n"), "\n")
+			return strings.Join(lines[:i+1], "\n") + "\n\n" + line + "\n\n" + rest
+		}
+	}
+	return line + "\n\n" + markdown
+}
+
+// ParseFallback reads the fallback line from a report's markdown, if a
+// fallback provider wrote it.
+func ParseFallback(markdown string) (Fallback, bool) {
+	m := fallbackPattern.FindStringSubmatch(markdown)
+	if m == nil {
+		return Fallback{}, false
+	}
+	return Fallback{Provider: m[1], Failed: strings.Split(m[2], ", ")}, true
+}
@@ -0,0 +1,36 @@
+package reports
+
+import "testing"
+
+func TestInsertFallback(t *testing.T) {
+	f := Fallback{Provider: "cloud/gpt", Failed: []string{"local/llama3"}}
+
+	got := InsertFallback("# Brief\n\n*Coverage: 3/5 sources (60%)*\n\nBody\n", f)
+	want := "# Brief\n\n*Coverage: 3/5 sources (60%)*\n\n*Synthesized by fallback provider cloud/gpt (local/llama3 failed)*\n\nBody\n"
+	if got != want {
+		t.Errorf("InsertFallback = %q, want %q", got, want)
+	}
+
+	// Coverage inserted later still goes directly under the title.
+	got = InsertCoverage(InsertFallback("# Brief\n\nBody\n", f), Coverage{Succeeded: 3, Total: 5, Score: 0.6})
+	if got != want {
+		t.Errorf("InsertFallback then InsertCoverage = %q, want %q", got, want)
+	}
+
+	// An existing line is replaced, not repeated.
+	again := InsertFallback(want, Fallback{Provider: "cloud/claude", Failed: []string{"local/llama3", "cloud/gpt"}})
+	if again != "# Brief\n\n*Coverage: 3/5 sources (60%)*\n\n*Synthesized by fallback provider cloud/claude (local/llama3, cloud/gpt failed)*\n\nBody\n" {
+		t.Errorf("replaced InsertFallback = %q", again)
+	}
+}
+
+func TestParseFallback(t *testing.T) {
+	if _, ok := ParseFallback("# Brief\n\nBody\n"); ok {
+		t.Error("expected no fallback")
+	}
+	md := InsertFallback("# Brief\n\nBody\n", Fallback{Provider: "cloud/gpt", Failed: []string{"local/llama3", "local/qwen"}})
+	f, ok := ParseFallback(md)
+	if !ok || f.Provider != "cloud/gpt" || len(f.Failed) != 2 || f.Failed[1] != "local/qwen" {
+		t.Errorf("ParseFallback = %+v, %v", f, ok)
+	}
+}
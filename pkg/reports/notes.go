@@ -0,0 +1,92 @@
+package reports
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// NotesFile is the file in a report directory holding the reader's own
+// notes on the report, kept apart from report.md so resynthesis never
+// touches them.
+const NotesFile = "notes.md"
+
+// noteTimeLayout is how a note's time is written in NotesFile.
+const noteTimeLayout = "2006-01-02 15:04"
+
+// Note is one timestamped entry in a report's NotesFile.
+type Note struct {
+	Time time.Time
+	Text string
+}
+
+// notePattern matches a note line: "- **2026-02-18 14:05** text".
+var notePattern = regexp.MustCompile(`^- \*\*(\d{4}-\d{2}-\d{2} \d{2}:\d{2})\*\* (.+)$`)
+
+// AddNote appends a note to the report's NotesFile, creating it with a
+// title on first use. Whitespace in the text, newlines included, collapses
+// to single spaces so each note stays one list item.
+func AddNote(reportDir string, text string, at time.Time) (Note, error) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return Note{}, fmt.Errorf("note is empty")
+	}
+	path := filepath.Join(reportDir, NotesFile)
+	var prefix string
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		prefix = "# Notes\n\n"
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return Note{}, fmt.Errorf("opening notes: %w", err)
+	}
+	defer f.Close()
+	note := Note{Time: at.Truncate(time.Minute), Text: text}
+	if _, err := fmt.Fprintf(f, "%s- **%s** %s\n", prefix, at.Format(noteTimeLayout), text); err != nil {
+		return Note{}, fmt.Errorf("writing note: %w", err)
+	}
+	return note, nil
+}
+
+// LoadNotes reads the notes in a report's NotesFile, oldest first. A report
+// without notes yields nil. Lines that aren't notes, such as the title or
+// text the user added by hand, are skipped.
+func LoadNotes(reportDir string) ([]Note, error) {
+	data, err := os.ReadFile(filepath.Join(reportDir, NotesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading notes: %w", err)
+	}
+	var notes []Note
+	for _, line := range strings.Split(string(data), "\n") {
+		m := notePattern.FindStringSubmatch(strings.TrimRight(line, " \r"))
+		if m == nil {
+			continue
+		}
+		t, err := time.ParseInLocation(noteTimeLayout, m[1], time.Local)
+		if err != nil {
+			continue
+		}
+		notes = append(notes, Note{Time: t, Text: m[2]})
+	}
+	return notes, nil
+}
+
+// NotesSection renders notes as a level 2 "Your notes" section to show
+// beneath a report, or "" when there are none.
+func NotesSection(notes []Note) string {
+	if len(notes) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## Your notes\n\n")
+	for _, n := range notes {
+		fmt.Fprintf(&b, "- **%s** %s\n", n.Time.Format(noteTimeLayout), n.Text)
+	}
+	return b.String()
+}
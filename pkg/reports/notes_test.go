@@ -0,0 +1,49 @@
+package reports
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotesRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if notes, err := LoadNotes(dir); err != nil || notes != nil {
+		t.Fatalf("LoadNotes without a file = %v, %v; want nil, nil", notes, err)
+	}
+
+	at := time.Date(2026, 2, 18, 14, 5, 30, 0, time.Local)
+	if _, err := AddNote(dir, "follow up on\nthe NGA contract", at); err != nil {
+		t.Fatalf("AddNote: %v", err)
+	}
+	if _, err := AddNote(dir, "ask about Q3", at.Add(time.Hour)); err != nil {
+		t.Fatalf("AddNote: %v", err)
+	}
+	if _, err := AddNote(dir, "  \n ", at); err == nil {
+		t.Error("AddNote accepted an empty note")
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, NotesFile))
+	want := "# Notes\n\n- **2026-02-18 14:05** follow up on the NGA contract\n- **2026-02-18 15:05** ask about Q3\n"
+	if string(data) != want {
+		t.Errorf("notes.md = %q, want %q", data, want)
+	}
+
+	notes, err := LoadNotes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 2 || notes[0].Text != "follow up on the NGA contract" || !notes[0].Time.Equal(at.Truncate(time.Minute)) {
+		t.Errorf("LoadNotes = %+v", notes)
+	}
+
+	section := NotesSection(notes)
+	if !strings.HasPrefix(section, "## Your notes\n") || !strings.Contains(section, "ask about Q3") {
+		t.Errorf("NotesSection = %q", section)
+	}
+	if NotesSection(nil) != "" {
+		t.Error("NotesSection(nil) not empty")
+	}
+}
@@ -0,0 +1,65 @@
+package reports
+
+import (
+	"unicode"
+)
+
+// Reading speeds used for the reading time estimate: words per minute for
+// space-separated text, and characters per minute for Chinese and Japanese,
+// which are read a character at a time.
+const (
+	wordsPerMinute = 230
+	cjkPerMinute   = 500
+)
+
+// Length is a text's size for reading: its word count and an estimated
+// reading time in whole minutes.
+type Length struct {
+	Words   int `json:"words"`
+	Minutes int `json:"reading_minutes"`
+}
+
+// Measure counts the words in plain text and estimates how long it takes to
+// read. A word is a run of letters and digits, so markup and punctuation
+// don't count; apostrophes and hyphens inside a word, and the separators
+// in a number, keep it whole. Chinese
+// and Japanese have no spaces between words, so each of their characters
+// counts as one word and is read at cjkPerMinute. Any text with words takes
+// at least a minute.
+func Measure(text string) Length {
+	var words, cjk int
+	inWord := false
+	runes := []rune(text)
+	for i, r := range runes {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		case inWord && (r == '\'' || r == '’' || r == '-') && i+1 < len(runes) &&
+			(unicode.IsLetter(runes[i+1]) || unicode.IsNumber(runes[i+1])) && !isCJK(runes[i+1]):
+			// Joins "don't" and "follow-up" into one word.
+		case inWord && (r == '.' || r == ',') && i > 0 && i+1 < len(runes) &&
+			unicode.IsDigit(runes[i-1]) && unicode.IsDigit(runes[i+1]):
+			// Keeps "4.5" and "12,000" whole.
+		default:
+			inWord = false
+		}
+	}
+	l := Length{Words: words + cjk}
+	if l.Words > 0 {
+		l.Minutes = (words*cjkPerMinute + cjk*wordsPerMinute + wordsPerMinute*cjkPerMinute - 1) / (wordsPerMinute * cjkPerMinute)
+	}
+	return l
+}
+
+// isCJK reports whether r is a Chinese or Japanese character, written
+// without spaces between words. Korean Hangul is space-separated and
+// counts as ordinary letters.
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r)
+}
@@ -0,0 +1,32 @@
+package reports
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		words   int
+		minutes int
+	}{
+		{"empty", "", 0, 0},
+		{"markup only", "## --- | * |", 0, 0},
+		{"words", "Three new contracts.", 3, 1},
+		{"joined", "Don't skip the follow-up — it's due.", 6, 1},
+		{"numbers", "Revenue rose 4.5% to $12,000", 5, 1},
+		{"chinese", "今日は晴れです", 7, 1},
+		{"mixed", "The 合同 was signed", 5, 1},
+		{"korean", "오늘 날씨 좋다", 3, 1},
+		{"long", strings.Repeat("word ", 500), 500, 3},
+		{"long cjk", strings.Repeat("字", 1000), 1000, 2},
+	}
+	for _, tt := range tests {
+		got := Measure(tt.text)
+		if got.Words != tt.words || got.Minutes != tt.minutes {
+			t.Errorf("%s: Measure = %+v, want %d words, %d minutes", tt.name, got, tt.words, tt.minutes)
+		}
+	}
+}
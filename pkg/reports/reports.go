@@ -2,6 +2,7 @@
 package reports
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,6 +23,8 @@ type Report struct {
 	Markdown string   // report content
 	Sources  []string // list of source files in data/
 	Charts   []string // list of chart files in charts/
+	// Attachments lists files saved from attachment sources in attachments/.
+	Attachments []string
 }
 
 // Create writes raw results to disk under baseDir/YYYY-MM-DDT150405-routine-name/data/.
@@ -36,29 +39,148 @@ func Create(baseDir string, routine string, rawResults map[string][]byte) (strin
 		return "", fmt.Errorf("creating report directory: %w", err)
 	}
 
-	if len(rawResults) > 0 {
-		dataDir := filepath.Join(reportDir, "data")
-		if err := os.MkdirAll(dataDir, 0o755); err != nil {
-			return "", fmt.Errorf("creating data directory: %w", err)
+	if err := AddResults(reportDir, rawResults); err != nil {
+		return "", err
+	}
+
+	return reportDir, nil
+}
+
+// AddResults writes raw results into an existing report's data/ directory.
+// Used directly when appending samples to a report created earlier.
+func AddResults(reportDir string, rawResults map[string][]byte) error {
+	if len(rawResults) == 0 {
+		return nil
+	}
+	dataDir := filepath.Join(reportDir, "data")
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	for name, data := range rawResults {
+		// Raw results are stored as .json — REST services return JSON overwhelmingly.
+		// If non-JSON sources are added, detect content type here.
+		path := filepath.Join(dataDir, slug.Sanitize(name)+".json")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("writing raw result %q: %w", name, err)
 		}
-		for name, data := range rawResults {
-			// Raw results are stored as .json — REST services return JSON overwhelmingly.
-			// If non-JSON sources are added, detect content type here.
-			path := filepath.Join(dataDir, slug.Sanitize(name)+".json")
-			if err := os.WriteFile(path, data, 0o644); err != nil {
-				return "", fmt.Errorf("writing raw result %q: %w", name, err)
-			}
+	}
+	return nil
+}
+
+// AddAttachments writes files saved from attachment sources into the
+// report's attachments/ directory, keyed by file name (with extension).
+func AddAttachments(reportDir string, files map[string][]byte) error {
+	if len(files) == 0 {
+		return nil
+	}
+	dir := filepath.Join(reportDir, "attachments")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating attachments directory: %w", err)
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, filepath.Base(name)), data, 0o644); err != nil {
+			return fmt.Errorf("writing attachment %q: %w", name, err)
 		}
 	}
+	return nil
+}
 
-	return reportDir, nil
+// LoadData reads the raw results stored in a report's data/ directory,
+// keyed by file name without the .json extension — the inverse of AddResults.
+// A report without a data/ directory yields an empty map.
+func LoadData(reportDir string) (map[string][]byte, error) {
+	dataDir := filepath.Join(reportDir, "data")
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string][]byte{}, nil
+		}
+		return nil, fmt.Errorf("reading data directory: %w", err)
+	}
+	data := make(map[string][]byte, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" || isHeadersFile(e.Name()) {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dataDir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading raw result %q: %w", e.Name(), err)
+		}
+		data[strings.TrimSuffix(e.Name(), ".json")] = b
+	}
+	return data, nil
+}
+
+// headersSuffix names the file that holds the response headers captured
+// with a raw result: data/<key>.headers.json beside data/<key>.json.
+const headersSuffix = ".headers.json"
+
+func isHeadersFile(name string) bool {
+	return strings.HasSuffix(name, headersSuffix)
+}
+
+// AddHeaders writes captured response headers into the report's data/
+// directory, one JSON object per raw result, keyed like AddResults. Results
+// without captured headers get no file.
+func AddHeaders(reportDir string, headers map[string]map[string]string) error {
+	if len(headers) == 0 {
+		return nil
+	}
+	dataDir := filepath.Join(reportDir, "data")
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return fmt.Errorf("creating data directory: %w", err)
+	}
+	for name, h := range headers {
+		if len(h) == 0 {
+			continue
+		}
+		b, err := json.MarshalIndent(h, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding headers for %q: %w", name, err)
+		}
+		path := filepath.Join(dataDir, slug.Sanitize(name)+headersSuffix)
+		if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+			return fmt.Errorf("writing headers for %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// LoadHeaders reads the captured response headers stored in a report's
+// data/ directory, keyed like LoadData. Reports without any yield an empty
+// map.
+func LoadHeaders(reportDir string) (map[string]map[string]string, error) {
+	dataDir := filepath.Join(reportDir, "data")
+	entries, err := os.ReadDir(dataDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]map[string]string{}, nil
+		}
+		return nil, fmt.Errorf("reading data directory: %w", err)
+	}
+	headers := make(map[string]map[string]string)
+	for _, e := range entries {
+		if e.IsDir() || !isHeadersFile(e.Name()) {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dataDir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading headers %q: %w", e.Name(), err)
+		}
+		var h map[string]string
+		if err := json.Unmarshal(b, &h); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", e.Name(), err)
+		}
+		headers[strings.TrimSuffix(e.Name(), headersSuffix)] = h
+	}
+	return headers, nil
 }
 
 // Finish writes the synthesized markdown to an existing report directory
-// and returns the completed Report.
+// and returns the completed Report. report.md is replaced atomically, so a
+// concurrent reader sees the old or the new report, never a partial one.
 func Finish(reportDir string, routine string, markdown string) (*Report, error) {
-	reportPath := filepath.Join(reportDir, "report.md")
-	if err := os.WriteFile(reportPath, []byte(markdown), 0o644); err != nil {
+	if err := writeAtomic(filepath.Join(reportDir, "report.md"), []byte(markdown)); err != nil {
 		return nil, fmt.Errorf("writing report: %w", err)
 	}
 
@@ -68,6 +190,9 @@ func Finish(reportDir string, routine string, markdown string) (*Report, error)
 	dataDir := filepath.Join(reportDir, "data")
 	if entries, err := os.ReadDir(dataDir); err == nil {
 		for _, e := range entries {
+			if isHeadersFile(e.Name()) {
+				continue
+			}
 			sources = append(sources, filepath.Join(dataDir, e.Name()))
 		}
 	}
@@ -80,16 +205,65 @@ func Finish(reportDir string, routine string, markdown string) (*Report, error)
 		}
 	}
 
+	var attachments []string
+	attachmentsDir := filepath.Join(reportDir, "attachments")
+	if entries, err := os.ReadDir(attachmentsDir); err == nil {
+		for _, e := range entries {
+			attachments = append(attachments, filepath.Join(attachmentsDir, e.Name()))
+		}
+	}
+
 	return &Report{
 		Dir:      reportDir,
 		Routine:  routine,
+		Title:    extractTitle(markdown),
 		Date:     date,
 		Markdown: markdown,
 		Sources:  sources,
 		Charts:   charts,
+
+		Attachments: attachments,
 	}, nil
 }
 
+// writeAtomic writes data to path via a temp file and rename.
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".report-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, 0o644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// Append adds markdown to the end of an existing report.md, separated by a
+// horizontal rule, and returns the updated Report.
+func Append(reportDir string, routine string, markdown string) (*Report, error) {
+	existing, err := os.ReadFile(filepath.Join(reportDir, "report.md"))
+	if err != nil {
+		return nil, fmt.Errorf("reading report: %w", err)
+	}
+	combined := strings.TrimRight(string(existing), "\n") + "\n\n---\n\n" + markdown
+	return Finish(reportDir, routine, combined)
+}
+
 // Save is a convenience wrapper that calls Create then Finish in sequence.
 func Save(baseDir string, routine string, markdown string, rawResults map[string][]byte) (*Report, error) {
 	reportDir, err := Create(baseDir, routine, rawResults)
@@ -114,6 +288,9 @@ func Load(reportDir string) (*Report, error) {
 	dataDir := filepath.Join(reportDir, "data")
 	if entries, err := os.ReadDir(dataDir); err == nil {
 		for _, e := range entries {
+			if isHeadersFile(e.Name()) {
+				continue
+			}
 			sources = append(sources, filepath.Join(dataDir, e.Name()))
 		}
 	}
@@ -126,6 +303,14 @@ func Load(reportDir string) (*Report, error) {
 		}
 	}
 
+	var attachments []string
+	attachmentsDir := filepath.Join(reportDir, "attachments")
+	if entries, err := os.ReadDir(attachmentsDir); err == nil {
+		for _, e := range entries {
+			attachments = append(attachments, filepath.Join(attachmentsDir, e.Name()))
+		}
+	}
+
 	// Extract title from first markdown heading
 	title := extractTitle(string(data))
 
@@ -137,11 +322,29 @@ func Load(reportDir string) (*Report, error) {
 		Markdown: string(data),
 		Sources:  sources,
 		Charts:   charts,
+
+		Attachments: attachments,
 	}, nil
 }
 
 // List returns all reports in the base directory, sorted newest first.
 func List(baseDir string) ([]*Report, error) {
+	return list(baseDir, nil)
+}
+
+// ListSince returns reports created at or after since, sorted newest first.
+// Creation time comes from the directory name, so older reports are skipped
+// without being read.
+func ListSince(baseDir string, since time.Time) ([]*Report, error) {
+	return list(baseDir, func(name string) bool {
+		t, ok := reportTime(name)
+		return ok && !t.Before(since)
+	})
+}
+
+// list loads the reports whose directory name passes keep (all when nil),
+// sorted newest first.
+func list(baseDir string, keep func(name string) bool) ([]*Report, error) {
 	entries, err := os.ReadDir(baseDir)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -152,7 +355,7 @@ func List(baseDir string) ([]*Report, error) {
 
 	var reports []*Report
 	for _, e := range entries {
-		if !e.IsDir() {
+		if !e.IsDir() || (keep != nil && !keep(e.Name())) {
 			continue
 		}
 		reportPath := filepath.Join(baseDir, e.Name(), "report.md")
@@ -205,6 +408,53 @@ func FindLatest(baseDir string, routine string) (*Report, error) {
 	return Load(filepath.Join(baseDir, candidates[len(candidates)-1]))
 }
 
+// FindPrevious returns the most recent finished report for a routine created
+// strictly before the given time, or nil if there is none. Unlike FindLatest
+// it skips report directories with no report.md yet, such as one a
+// concurrent run is still producing.
+func FindPrevious(baseDir string, routine string, before time.Time) (*Report, error) {
+	entries, err := os.ReadDir(baseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing reports: %w", err)
+	}
+
+	type candidate struct {
+		name    string
+		created time.Time
+	}
+	sanitized := slug.Sanitize(routine)
+	var candidates []candidate
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, name := parseReportDirName(e.Name()); name != sanitized {
+			continue
+		}
+		created, ok := reportTime(e.Name())
+		if !ok || !created.Before(before) {
+			continue
+		}
+		candidates = append(candidates, candidate{e.Name(), created})
+	}
+
+	// Newest first; take the first that has finished.
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].created.After(candidates[j].created)
+	})
+	for _, c := range candidates {
+		dir := filepath.Join(baseDir, c.name)
+		if _, err := os.Stat(filepath.Join(dir, "report.md")); err != nil {
+			continue
+		}
+		return Load(dir)
+	}
+	return nil, nil
+}
+
 // Search returns reports whose markdown matches query (case-insensitive substring).
 // Results are sorted newest first.
 func Search(baseDir string, query string) ([]*Report, error) {
@@ -264,6 +514,39 @@ func parseReportDirName(name string) (date, routine string) {
 	return m[1], m[3]
 }
 
+// Generated returns the local time the report was created, taken from its
+// directory name. ok is false for directories without a date prefix.
+func (r *Report) Generated() (t time.Time, ok bool) {
+	return DirTime(r.Dir)
+}
+
+// DirTime returns the local creation time encoded in a report directory's
+// name.
+func DirTime(reportDir string) (time.Time, bool) {
+	return reportTime(filepath.Base(reportDir))
+}
+
+// reportTime returns the local creation time encoded in a report directory
+// name. Names with only a date resolve to midnight.
+func reportTime(name string) (time.Time, bool) {
+	m := datePattern.FindStringSubmatch(name)
+	if m == nil {
+		return time.Time{}, false
+	}
+	layout := "2006-01-02"
+	switch len(m[2]) {
+	case 5:
+		layout += "T1504"
+	case 7:
+		layout += "T150405"
+	}
+	t, err := time.ParseInLocation(layout, m[1]+m[2], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
 func extractTitle(markdown string) string {
 	for _, line := range strings.Split(markdown, "\n") {
 		line = strings.TrimSpace(line)
@@ -3,6 +3,7 @@ package reports
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -219,6 +220,50 @@ func TestListSameDayOrdering(t *testing.T) {
 	}
 }
 
+func TestReportGenerated(t *testing.T) {
+	r := &Report{Dir: filepath.Join("reports", "2026-02-19T140512-beta")}
+	got, ok := r.Generated()
+	if !ok {
+		t.Fatal("expected generation time from directory name")
+	}
+	want := time.Date(2026, 2, 19, 14, 5, 12, 0, time.Local)
+	if !got.Equal(want) {
+		t.Errorf("Generated = %v, want %v", got, want)
+	}
+
+	if _, ok := (&Report{Dir: "reports/notes"}).Generated(); ok {
+		t.Error("directory without a date should have no generation time")
+	}
+}
+
+func TestListSince(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"2026-02-17T0800-alpha", "2026-02-19T140512-beta", "2026-02-18-gamma", "notes"} {
+		reportDir := filepath.Join(dir, name)
+		os.MkdirAll(reportDir, 0o755)
+		os.WriteFile(filepath.Join(reportDir, "report.md"), []byte("# "+name+"\n"), 0o644)
+	}
+
+	since := time.Date(2026, 2, 18, 0, 0, 0, 0, time.Local)
+	reports, err := ListSince(dir, since)
+	if err != nil {
+		t.Fatalf("ListSince: %v", err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected 2 reports, got %d", len(reports))
+	}
+	if reports[0].Routine != "beta" || reports[1].Routine != "gamma" {
+		t.Errorf("got %q, %q; want beta, gamma", reports[0].Routine, reports[1].Routine)
+	}
+
+	// Time of day counts for timestamped directories.
+	reports, _ = ListSince(dir, time.Date(2026, 2, 19, 14, 6, 0, 0, time.Local))
+	if len(reports) != 0 {
+		t.Errorf("expected no reports after 14:06, got %d", len(reports))
+	}
+}
+
 func TestSaveNoClobber(t *testing.T) {
 	dir := t.TempDir()
 
@@ -468,3 +513,194 @@ func TestFindLatestFuzzy(t *testing.T) {
 		t.Error("expected nil for no match")
 	}
 }
+
+func TestAppend(t *testing.T) {
+	dir := t.TempDir()
+
+	r, err := Save(dir, "prices", "# Prices\n\nMorning sample.\n", map[string][]byte{"a": []byte(`{}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := AddResults(r.Dir, map[string][]byte{"b": []byte(`{}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := Append(r.Dir, "prices", "Afternoon sample.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Prices\n\nMorning sample.\n\n---\n\nAfternoon sample.\n"
+	if updated.Markdown != want {
+		t.Errorf("markdown = %q, want %q", updated.Markdown, want)
+	}
+	if len(updated.Sources) != 2 {
+		t.Errorf("sources = %d, want 2", len(updated.Sources))
+	}
+
+	loaded, err := Load(r.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Markdown != want {
+		t.Error("appended markdown not persisted")
+	}
+}
+
+func TestLoadData(t *testing.T) {
+	dir := t.TempDir()
+
+	rawResults := map[string][]byte{
+		"0-sam-gov-search": []byte(`{"results": []}`),
+		"1-edgar-filings":  []byte(`{"filings": []}`),
+	}
+	reportDir, err := Create(dir, "morning-intel", rawResults)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	data, err := LoadData(reportDir)
+	if err != nil {
+		t.Fatalf("LoadData: %v", err)
+	}
+	if len(data) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(data))
+	}
+	if string(data["1-edgar-filings"]) != `{"filings": []}` {
+		t.Errorf("unexpected data for 1-edgar-filings: %q", data["1-edgar-filings"])
+	}
+
+	empty, err := LoadData(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadData without data dir: %v", err)
+	}
+	if len(empty) != 0 {
+		t.Errorf("expected no results, got %d", len(empty))
+	}
+}
+
+func TestAddHeaders(t *testing.T) {
+	reportDir, err := Create(t.TempDir(), "counts", map[string][]byte{"0-api-list": []byte(`[]`)})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	headers := map[string]map[string]string{
+		"0-api-list": {"X-Total-Count": "42"},
+		"1-api-none": {},
+	}
+	if err := AddHeaders(reportDir, headers); err != nil {
+		t.Fatalf("AddHeaders: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(reportDir, "data", "0-api-list.headers.json")); err != nil {
+		t.Errorf("expected headers file: %v", err)
+	}
+
+	loaded, err := LoadHeaders(reportDir)
+	if err != nil {
+		t.Fatalf("LoadHeaders: %v", err)
+	}
+	if len(loaded) != 1 || loaded["0-api-list"]["X-Total-Count"] != "42" {
+		t.Errorf("LoadHeaders = %v", loaded)
+	}
+
+	// Headers files are not raw results.
+	data, err := LoadData(reportDir)
+	if err != nil {
+		t.Fatalf("LoadData: %v", err)
+	}
+	if len(data) != 1 {
+		t.Errorf("LoadData returned %d results, want 1: %v", len(data), data)
+	}
+	report, err := Finish(reportDir, "counts", "# Counts\n")
+	if err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	if len(report.Sources) != 1 {
+		t.Errorf("Sources = %v, want only the raw result", report.Sources)
+	}
+}
+
+func TestAddAttachments(t *testing.T) {
+	reportDir, err := Create(t.TempDir(), "filings", nil)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := AddAttachments(reportDir, map[string][]byte{"0-sec-download.pdf": []byte("%PDF")}); err != nil {
+		t.Fatalf("AddAttachments: %v", err)
+	}
+	report, err := Finish(reportDir, "filings", "# Filings\n")
+	if err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	if len(report.Attachments) != 1 || filepath.Base(report.Attachments[0]) != "0-sec-download.pdf" {
+		t.Errorf("Attachments = %v", report.Attachments)
+	}
+
+	loaded, err := Load(reportDir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(loaded.Attachments) != 1 {
+		t.Errorf("loaded Attachments = %v", loaded.Attachments)
+	}
+}
+
+func TestFindPrevious(t *testing.T) {
+	dir := t.TempDir()
+	finished := []string{"2026-02-17T080000-morning-intel", "2026-02-18T080000-morning-intel", "2026-02-19T080000-morning-intel"}
+	for _, name := range finished {
+		reportDir := filepath.Join(dir, name)
+		os.MkdirAll(reportDir, 0o755)
+		os.WriteFile(filepath.Join(reportDir, "report.md"), []byte("# "+name+"\n"), 0o644)
+	}
+	// A run still in progress: data written, no report.md yet.
+	os.MkdirAll(filepath.Join(dir, "2026-02-18T120000-morning-intel", "data"), 0o755)
+
+	before := time.Date(2026, 2, 19, 8, 0, 0, 0, time.Local)
+	report, err := FindPrevious(dir, "morning-intel", before)
+	if err != nil {
+		t.Fatalf("FindPrevious: %v", err)
+	}
+	if report == nil {
+		t.Fatal("expected to find a report")
+	}
+	if filepath.Base(report.Dir) != "2026-02-18T080000-morning-intel" {
+		t.Errorf("expected the latest finished report before the cutoff, got %s", filepath.Base(report.Dir))
+	}
+
+	report, err = FindPrevious(dir, "morning-intel", time.Date(2026, 2, 17, 8, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("FindPrevious: %v", err)
+	}
+	if report != nil {
+		t.Errorf("expected nil with no earlier report, got %s", report.Dir)
+	}
+}
+
+func TestFinishReplacesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	reportDir, err := Create(dir, "routine", nil)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := Finish(reportDir, "routine", "# First\n"); err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	r, err := Finish(reportDir, "routine", "# Second\n")
+	if err != nil {
+		t.Fatalf("Finish: %v", err)
+	}
+	if r.Title != "Second" {
+		t.Errorf("Finish title = %q, want Second", r.Title)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(reportDir, "report.md"))
+	if string(data) != "# Second\n" {
+		t.Errorf("expected replaced report, got %q", data)
+	}
+	entries, _ := os.ReadDir(reportDir)
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Errorf("temp file left behind: %s", e.Name())
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jcadam/burrow/pkg/slug"
+)
+
+// sourcesFile records how each source of a run fared, next to report.md.
+const sourcesFile = "sources.json"
+
+// Source statuses recorded for a run.
+const (
+	SourceOK        = "ok"
+	SourceNoResults = "no_results"
+	SourceError     = "error"
+)
+
+// SourceRecord is one source's outcome in the run that produced a report.
+type SourceRecord struct {
+	Index   int    `json:"index"` // position in the routine's sources
+	Service string `json:"service"`
+	Tool    string `json:"tool"`
+	Status  string `json:"status"` // ok, no_results, or error
+	Error   string `json:"error,omitempty"`
+}
+
+// SaveSources writes the per-source outcomes of a run to the report's
+// sources.json, replacing any earlier record.
+func SaveSources(reportDir string, records []SourceRecord) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding source status: %w", err)
+	}
+	if err := writeAtomic(filepath.Join(reportDir, sourcesFile), append(data, '\n')); err != nil {
+		return fmt.Errorf("writing source status: %w", err)
+	}
+	return nil
+}
+
+// LoadSources reads the per-source outcomes saved with a report. A report
+// without them (from before they were recorded, or with appended samples)
+// yields nil.
+func LoadSources(reportDir string) ([]SourceRecord, error) {
+	data, err := os.ReadFile(filepath.Join(reportDir, sourcesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading source status: %w", err)
+	}
+	var records []SourceRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", sourcesFile, err)
+	}
+	return records, nil
+}
+
+// ClearSources removes a report's per-source outcomes, for a report whose
+// data no longer comes from a single run.
+func ClearSources(reportDir string) error {
+	if err := os.Remove(filepath.Join(reportDir, sourcesFile)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing source status: %w", err)
+	}
+	return nil
+}
+
+// RemoveResults deletes raw results, and the headers captured with them,
+// from the report's data/ directory, by the names LoadData returns. Missing
+// files are ignored.
+func RemoveResults(reportDir string, names []string) error {
+	for _, name := range names {
+		for _, ext := range []string{".json", headersSuffix} {
+			path := filepath.Join(reportDir, "data", slug.Sanitize(name)+ext)
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("removing raw result %q: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package reports
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSourcesRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if records, err := LoadSources(dir); err != nil || records != nil {
+		t.Fatalf("LoadSources without a file = %v, %v; want nil, nil", records, err)
+	}
+
+	want := []SourceRecord{
+		{Index: 0, Service: "a", Tool: "x", Status: SourceOK},
+		{Index: 1, Service: "b", Tool: "y", Status: SourceError, Error: "HTTP 503"},
+	}
+	if err := SaveSources(dir, want); err != nil {
+		t.Fatalf("SaveSources: %v", err)
+	}
+	got, err := LoadSources(dir)
+	if err != nil {
+		t.Fatalf("LoadSources: %v", err)
+	}
+	if len(got) != 2 || got[1] != want[1] {
+		t.Errorf("LoadSources = %+v, want %+v", got, want)
+	}
+
+	// Status lives beside report.md, not among the raw results.
+	if data, _ := LoadData(dir); len(data) != 0 {
+		t.Errorf("sources.json read as raw data: %v", data)
+	}
+}
+
+func TestRemoveResults(t *testing.T) {
+	dir := t.TempDir()
+	if err := AddResults(dir, map[string][]byte{"0-a-x": []byte(`1`), "1-b-y": []byte(`2`)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddHeaders(dir, map[string]map[string]string{"1-b-y": {"Link": "<next>"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveResults(dir, []string{"1-b-y", "9-gone"}); err != nil {
+		t.Fatalf("RemoveResults: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data", "1-b-y.json")); !os.IsNotExist(err) {
+		t.Errorf("1-b-y.json still present: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data", "1-b-y.headers.json")); !os.IsNotExist(err) {
+		t.Errorf("1-b-y.headers.json still present: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data", "0-a-x.json")); err != nil {
+		t.Errorf("0-a-x.json removed: %v", err)
+	}
+}
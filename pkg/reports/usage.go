@@ -0,0 +1,120 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// UsageFile is the file in a report directory recording the LLM usage of
+// the runs that wrote it.
+const UsageFile = "usage.json"
+
+// TokenUsage counts LLM calls and the tokens they used. Cost is an
+// estimate in US dollars, from the provider's configured pricing.
+type TokenUsage struct {
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"estimated_cost_usd,omitempty"`
+}
+
+// Add adds o's counts to t.
+func (t *TokenUsage) Add(o TokenUsage) {
+	t.Calls += o.Calls
+	t.PromptTokens += o.PromptTokens
+	t.CompletionTokens += o.CompletionTokens
+	t.Cost += o.Cost
+}
+
+// Usage is the LLM usage recorded for a report, in total and by provider.
+type Usage struct {
+	Dir       string                `json:"-"` // report directory, set by UsageSince
+	Routine   string                `json:"routine"`
+	Updated   time.Time             `json:"updated"`
+	Total     TokenUsage            `json:"total"`
+	Providers map[string]TokenUsage `json:"providers"`
+}
+
+// Record adds one call's usage under provider.
+func (u *Usage) Record(provider string, t TokenUsage) {
+	if u.Providers == nil {
+		u.Providers = make(map[string]TokenUsage)
+	}
+	p := u.Providers[provider]
+	p.Add(t)
+	u.Providers[provider] = p
+	u.Total.Add(t)
+}
+
+// AddUsage adds u to the usage recorded in reportDir's usage.json, creating
+// it if needed, so appended samples and resyntheses accumulate.
+func AddUsage(reportDir string, u Usage) error {
+	merged, err := LoadUsage(reportDir)
+	if err != nil {
+		return err
+	}
+	if merged == nil {
+		merged = &Usage{}
+	}
+	merged.Routine = u.Routine
+	merged.Updated = u.Updated
+	for name, t := range u.Providers {
+		merged.Record(name, t)
+	}
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeAtomic(filepath.Join(reportDir, UsageFile), append(data, '\n'))
+}
+
+// LoadUsage reads reportDir's usage.json. It returns nil without error
+// when the report made no LLM calls.
+func LoadUsage(reportDir string) (*Usage, error) {
+	data, err := os.ReadFile(filepath.Join(reportDir, UsageFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var u Usage
+	if err := json.Unmarshal(data, &u); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", UsageFile, err)
+	}
+	return &u, nil
+}
+
+// UsageSince returns the usage recorded in report directories created at
+// or after since, oldest first. Directories whose synthesis failed count
+// too, since their tokens were spent.
+func UsageSince(baseDir string, since time.Time) ([]*Usage, error) {
+	entries, err := os.ReadDir(baseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing reports: %w", err)
+	}
+
+	// ReadDir sorts by name, which starts with the creation time.
+	var all []*Usage
+	for _, e := range entries {
+		if t, ok := reportTime(e.Name()); !e.IsDir() || !ok || t.Before(since) {
+			continue
+		}
+		dir := filepath.Join(baseDir, e.Name())
+		u, err := LoadUsage(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", e.Name(), err)
+		}
+		if u != nil {
+			u.Dir = dir
+			all = append(all, u)
+		}
+	}
+	return all, nil
+}
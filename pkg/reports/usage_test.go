@@ -0,0 +1,75 @@
+package reports
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAddUsage(t *testing.T) {
+	dir := t.TempDir()
+
+	if u, err := LoadUsage(dir); err != nil || u != nil {
+		t.Fatalf("LoadUsage with no file = %+v, %v", u, err)
+	}
+
+	var first Usage
+	first.Routine = "brief"
+	first.Updated = time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	first.Record("cloud/gpt", TokenUsage{Calls: 1, PromptTokens: 1000, CompletionTokens: 200, Cost: 0.006})
+	first.Record("cloud/gpt", TokenUsage{Calls: 1, PromptTokens: 500, CompletionTokens: 100, Cost: 0.003})
+	if err := AddUsage(dir, first); err != nil {
+		t.Fatalf("AddUsage: %v", err)
+	}
+
+	// A later sample accumulates rather than replacing.
+	var second Usage
+	second.Routine = "brief"
+	second.Updated = first.Updated.Add(time.Hour)
+	second.Record("local/llama3", TokenUsage{Calls: 2, PromptTokens: 300, CompletionTokens: 50})
+	if err := AddUsage(dir, second); err != nil {
+		t.Fatalf("AddUsage: %v", err)
+	}
+
+	u, err := LoadUsage(dir)
+	if err != nil || u == nil {
+		t.Fatalf("LoadUsage = %v, %v", u, err)
+	}
+	if u.Total.Calls != 4 || u.Total.PromptTokens != 1800 || u.Total.CompletionTokens != 350 {
+		t.Errorf("total = %+v", u.Total)
+	}
+	if gpt := u.Providers["cloud/gpt"]; gpt.Calls != 2 || gpt.Cost < 0.0089 || gpt.Cost > 0.0091 {
+		t.Errorf("cloud/gpt = %+v", gpt)
+	}
+	if !u.Updated.Equal(second.Updated) {
+		t.Errorf("updated = %v, want %v", u.Updated, second.Updated)
+	}
+}
+
+func TestUsageSince(t *testing.T) {
+	base := t.TempDir()
+	for _, name := range []string{"2026-03-01T080000-brief", "2026-03-05T080000-brief", "2026-03-06T080000-digest"} {
+		dir := filepath.Join(base, name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if name == "2026-03-06T080000-digest" {
+			continue // passthrough: no LLM calls, no usage.json
+		}
+		var u Usage
+		u.Routine = "brief"
+		u.Record("cloud/gpt", TokenUsage{Calls: 1, PromptTokens: 10})
+		if err := AddUsage(dir, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := UsageSince(base, time.Date(2026, 3, 2, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("UsageSince: %v", err)
+	}
+	if len(all) != 1 || filepath.Base(all[0].Dir) != "2026-03-05T080000-brief" {
+		t.Errorf("UsageSince = %+v, want only the March 5 report", all)
+	}
+}
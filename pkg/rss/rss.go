@@ -15,6 +15,7 @@ import (
 
 	"github.com/jcadam/burrow/pkg/config"
 	"github.com/jcadam/burrow/pkg/privacy"
+	"github.com/jcadam/burrow/pkg/ratelimit"
 	"github.com/jcadam/burrow/pkg/services"
 )
 
@@ -27,6 +28,7 @@ type RSSService struct {
 	auth     config.AuthConfig
 	maxItems int
 	client   *http.Client
+	limiter  *ratelimit.Limiter // optional rate_limit, waited on before each request
 }
 
 // NewRSSService creates an RSS service from config. Each service gets its own
@@ -36,6 +38,7 @@ type RSSService struct {
 // (empty string means direct connection).
 func NewRSSService(cfg config.ServiceConfig, privacyCfg *privacy.Config, proxyURL string) *RSSService {
 	baseTransport := &http.Transport{}
+	cfg.Transport.Apply(baseTransport)
 	if proxyURL != "" {
 		if parsed, err := url.Parse(proxyURL); err == nil && parsed != nil {
 			baseTransport.Proxy = http.ProxyURL(parsed)
@@ -56,7 +59,7 @@ func NewRSSService(cfg config.ServiceConfig, privacyCfg *privacy.Config, proxyUR
 		endpoint: cfg.Endpoint,
 		auth:     cfg.Auth,
 		maxItems: maxItems,
-		client:   &http.Client{Timeout: 30 * time.Second, Transport: transport},
+		client:   &http.Client{Timeout: 30 * time.Second, Transport: transport, CheckRedirect: cfg.CheckRedirect()},
 	}
 }
 
@@ -66,6 +69,12 @@ func (r *RSSService) WrapTransport(wrap func(http.RoundTripper) http.RoundTrippe
 	r.client.Transport = wrap(r.client.Transport)
 }
 
+// SetLimiter makes each feed request wait on l first. The wait is bounded
+// only by the request's context, not the client's timeout.
+func (r *RSSService) SetLimiter(l *ratelimit.Limiter) {
+	r.limiter = l
+}
+
 func (r *RSSService) Name() string { return r.name }
 
 // Execute runs the "feed" tool, which fetches and parses the RSS/Atom feed.
@@ -79,9 +88,19 @@ func (r *RSSService) Execute(ctx context.Context, tool string, params map[string
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
-	r.applyAuth(req)
+	r.auth.Apply(req)
 
-	resp, err := r.client.Do(req)
+	err = ratelimit.Take(ctx)
+	if err == nil && r.limiter != nil {
+		err = r.limiter.Wait(ctx)
+		if err != nil {
+			err = fmt.Errorf("waiting for rate limit: %w", err)
+		}
+	}
+	var resp *http.Response
+	if err == nil {
+		resp, err = r.client.Do(req)
+	}
 	if err != nil {
 		return &services.Result{
 			Service:   r.name,
@@ -368,27 +387,3 @@ func normalizeDate(s string) string {
 	}
 	return s
 }
-
-func (r *RSSService) applyAuth(req *http.Request) {
-	switch r.auth.Method {
-	case "api_key":
-		paramName := r.auth.KeyParam
-		if paramName == "" {
-			paramName = "api_key"
-		}
-		q := req.URL.Query()
-		q.Set(paramName, r.auth.Key)
-		req.URL.RawQuery = q.Encode()
-	case "api_key_header":
-		headerName := r.auth.KeyParam
-		if headerName == "" {
-			headerName = "X-API-Key"
-		}
-		req.Header.Set(headerName, r.auth.Key)
-	case "bearer":
-		req.Header.Set("Authorization", "Bearer "+r.auth.Token)
-	case "user_agent":
-		req.Header.Set("User-Agent", r.auth.Value)
-		req.Header.Set("X-Burrow-Preserve-UA", "true")
-	}
-}
@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jcadam/burrow/pkg/pipeline"
+)
+
+// orderByAfter sorts routines so each comes after the routines its `after`
+// names, keeping the loaded order otherwise. Routines that can't be ordered
+// — in a cycle, naming an unknown routine, or depending on such a routine —
+// are returned in errs, keyed by name, and must not run.
+func orderByAfter(routines []*pipeline.Routine) (ordered []*pipeline.Routine, errs map[string]error) {
+	byName := make(map[string]*pipeline.Routine, len(routines))
+	for _, r := range routines {
+		byName[r.Name] = r
+	}
+	errs = make(map[string]error)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	mark := make(map[string]int)
+	var path []string
+	var visit func(r *pipeline.Routine)
+	visit = func(r *pipeline.Routine) {
+		switch mark[r.Name] {
+		case visited:
+			return
+		case visiting:
+			start := slices.Index(path, r.Name)
+			cycle := append(slices.Clone(path[start:]), r.Name)
+			err := fmt.Errorf("dependency cycle %s", strings.Join(cycle, " → "))
+			for _, name := range path[start:] {
+				errs[name] = err
+			}
+			return
+		}
+
+		mark[r.Name] = visiting
+		path = append(path, r.Name)
+		for _, dep := range r.After {
+			d, ok := byName[dep]
+			if !ok {
+				if errs[r.Name] == nil {
+					errs[r.Name] = fmt.Errorf("unknown routine %q", dep)
+				}
+				continue
+			}
+			visit(d)
+			if errs[dep] != nil && errs[r.Name] == nil {
+				errs[r.Name] = fmt.Errorf("depends on %q, which can't run", dep)
+			}
+		}
+		path = path[:len(path)-1]
+		mark[r.Name] = visited
+		ordered = append(ordered, r)
+	}
+	for _, r := range routines {
+		visit(r)
+	}
+	return ordered, errs
+}
+
+// tickRun tracks a routine started during the current tick, so routines
+// that run after it can wait for its outcome.
+type tickRun struct {
+	name string
+	done chan struct{} // closed when the run ends
+	ok   bool          // set before done is closed
+}
+
+// ranOn reports whether a recorded last-run slot falls on date (YYYY-MM-DD).
+// Every slot format starts with the date.
+func ranOn(lastRun, date string) bool {
+	return strings.HasPrefix(lastRun, date)
+}
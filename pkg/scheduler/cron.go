@@ -0,0 +1,176 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed five-field cron expression: minute, hour, day of
+// month, month, and day of week. Each field is a bit set of the values it
+// matches.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// Per cron convention, when both day fields are restricted a day
+	// matches if either does; a "*" field defers to the other.
+	domAny, dowAny bool
+}
+
+// cronField describes one field's range and any names it accepts.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // names[i] is value min+i
+}
+
+var cronFields = [5]cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// isCronSchedule reports whether a schedule is a cron expression rather
+// than HH:MM times, which always contain a colon.
+func isCronSchedule(s string) bool {
+	return s != "" && !strings.Contains(s, ":")
+}
+
+// parseCron parses a standard five-field cron expression such as
+// "*/30 9-17 * * 1-5". Fields take *, values, ranges (a-b), steps (*/n,
+// a-b/n), and comma-separated lists; months and weekdays also take
+// three-letter names, and both 0 and 7 mean Sunday.
+func parseCron(s string) (*cronSchedule, error) {
+	s = strings.Trim(strings.TrimSpace(s), "'\"")
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected HH:MM or a 5-field cron expression", s)
+	}
+	var sets [5]uint64
+	for i, f := range fields {
+		set, err := parseCronField(f, cronFields[i])
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+	c := &cronSchedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1 // 7 is Sunday too
+	}
+	return c, nil
+}
+
+// parseCronField parses one field into the set of values it matches.
+func parseCronField(s string, f cronField) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(s, ",") {
+		expr, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s field %q", stepStr, f.name, s)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case expr == "*":
+			if f.name == "day of week" {
+				hi = 6 // don't count Sunday twice
+			}
+		case strings.Contains(expr, "-"):
+			a, b, _ := strings.Cut(expr, "-")
+			var err error
+			if lo, err = cronValue(a, f); err != nil {
+				return 0, err
+			}
+			if hi, err = cronValue(b, f); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q in %s field", expr, f.name)
+			}
+		default:
+			v, err := cronValue(expr, f)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if !hasStep {
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// cronValue parses a single number or name within a field's range.
+func cronValue(s string, f cronField) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s %d out of range %d-%d", f.name, v, f.min, f.max)
+	}
+	return v, nil
+}
+
+// matches reports whether the schedule fires in the minute containing t.
+func (c *cronSchedule) matches(t time.Time) bool {
+	return c.minute&(1<<t.Minute()) != 0 && c.hour&(1<<t.Hour()) != 0 && c.firesOn(t)
+}
+
+// firesOn reports whether the schedule fires at some time on t's date.
+func (c *cronSchedule) firesOn(t time.Time) bool {
+	if c.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	domOK := c.dom&(1<<t.Day()) != 0
+	dowOK := c.dow&(1<<int(t.Weekday())) != 0
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dowOK
+	case c.dowAny:
+		return domOK
+	}
+	return domOK || dowOK
+}
+
+// lastFiring returns the most recent minute at or before now, on now's
+// date in its location, when the schedule fires. It reports false if the
+// schedule hasn't fired yet today. Like multi-time schedules, firings
+// missed earlier in the day collapse into this one.
+func (c *cronSchedule) lastFiring(now time.Time) (time.Time, bool) {
+	today := now.Format("2006-01-02")
+	for t := now.Truncate(time.Minute); t.Format("2006-01-02") == today; t = t.Add(-time.Minute) {
+		if c.matches(t) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
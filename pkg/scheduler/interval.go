@@ -0,0 +1,57 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// minInterval is the shortest interval schedule allowed. The scheduler
+// checks routines once a minute, so anything shorter couldn't be honored.
+const minInterval = time.Minute
+
+// parseInterval parses an interval schedule such as "every 4h". It reports
+// ok=false when s isn't an interval schedule at all. Durations use Go
+// syntax ("90m", "1h30m") plus a "d" suffix for whole days ("every 2d").
+func parseInterval(s string) (every time.Duration, ok bool, err error) {
+	s = strings.Trim(strings.TrimSpace(s), "'\"")
+	rest, found := strings.CutPrefix(s, "every ")
+	if !found {
+		return 0, false, nil
+	}
+	rest = strings.TrimSpace(rest)
+	if days, isDays := strings.CutSuffix(rest, "d"); isDays {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, true, fmt.Errorf("invalid interval %q", rest)
+		}
+		every = time.Duration(n) * 24 * time.Hour
+	} else {
+		every, err = time.ParseDuration(rest)
+		if err != nil {
+			return 0, true, fmt.Errorf("invalid interval %q", rest)
+		}
+	}
+	if every < minInterval {
+		return 0, true, fmt.Errorf("interval %q is shorter than %s", rest, minInterval)
+	}
+	return every, true, nil
+}
+
+// intervalSlot is the slot recorded for an interval schedule's run: the
+// time it started, to the second, in loc.
+func intervalSlot(now time.Time, loc *time.Location) string {
+	return now.In(loc).Format(time.RFC3339)
+}
+
+// intervalDue reports whether at least every has passed since lastRun. A
+// routine that has never run, or whose last run was recorded under a
+// date-based schedule, is due immediately.
+func intervalDue(now time.Time, every time.Duration, lastRun string) bool {
+	last, err := time.Parse(time.RFC3339, lastRun)
+	if err != nil {
+		return true
+	}
+	return now.Sub(last) >= every
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/jcadam/burrow/pkg/pipeline"
+)
+
+// missedGrace is how long after its scheduled time a run may start before
+// it counts as missed. The scheduler checks every minute, so a run only
+// starts this late when the daemon wasn't running or the machine slept.
+const missedGrace = 5 * time.Minute
+
+// missedRun reports whether a due slot was missed and the routine's
+// missed_run policy says not to run it, returning the slot's scheduled time.
+// A slot is judged once, when a pass first finds it due, and the judgment
+// is kept in state so a run held back by a dependency or a failure's
+// backoff is never missed, even across --once invocations. Interval
+// schedules have no scheduled times and are never missed.
+func (s *Scheduler) missedRun(r *pipeline.Routine, slot string, now time.Time, loc *time.Location, state *State) (time.Time, bool) {
+	if r.MissedRun != "skip" && r.MissedRun != "notify" {
+		return time.Time{}, false
+	}
+	if state.Judged[r.Name] == slot {
+		return time.Time{}, false
+	}
+	s.updateState(r.Name, func(st *State) {
+		st.Judged[r.Name] = slot
+	})
+	if _, failed := state.Failures[r.Name]; failed {
+		return time.Time{}, false
+	}
+	at, ok := slotTime(now, r.Schedule, loc)
+	if !ok || now.Sub(at) <= missedGrace+s.cadence(state) {
+		return time.Time{}, false
+	}
+	return at, true
+}
+
+// checksKept is how many --once passes state remembers for cadence.
+const checksKept = 4
+
+// cadence is how long a due slot can go unseen between passes. The daemon
+// checks every minute, well inside missedGrace; under --once (run from
+// cron) it is the shortest gap between recent invocations, the cron
+// interval, so a 09:00 run first seen by a 09:10 invocation of a 15-minute
+// job isn't missed. The shortest gap ignores the ones a sleeping machine
+// stretched.
+func (s *Scheduler) cadence(state *State) time.Duration {
+	if !s.cfg.Once {
+		return 0
+	}
+	var gap time.Duration
+	for i := 1; i < len(state.Checks); i++ {
+		if d := state.Checks[i].Sub(state.Checks[i-1]); gap == 0 || d < gap {
+			gap = d
+		}
+	}
+	return gap
+}
+
+// recordCheck notes a --once pass at now, keeping the last checksKept.
+func (s *Scheduler) recordCheck(now time.Time) {
+	s.updateState("--once pass", func(st *State) {
+		st.Checks = append(st.Checks, now)
+		if n := len(st.Checks); n > checksKept {
+			st.Checks = st.Checks[n-checksKept:]
+		}
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,6 +19,7 @@ import (
 	_ "time/tzdata" // embedded timezone database for minimal systems
 
 	"github.com/jcadam/burrow/pkg/pipeline"
+	"github.com/jcadam/burrow/pkg/services"
 )
 
 // Clock abstracts time for testability.
@@ -38,9 +40,28 @@ type RoutineRunner func(ctx context.Context, routine *pipeline.Routine) error
 // RoutineLoader loads all current routines. Called each tick.
 type RoutineLoader func() ([]*pipeline.Routine, error)
 
-// State tracks last-run date (YYYY-MM-DD in routine's timezone) per routine name.
+// State tracks the last-run slot per routine name — the date (YYYY-MM-DD in the
+// routine's timezone), suffixed with THH:MM for multi-time and cron
+// schedules, or the start time (RFC 3339) for interval schedules — plus
+// backoff bookkeeping for routines whose most recent runs failed.
 type State struct {
-	LastRun map[string]string `json:"last_run"`
+	LastRun  map[string]string        `json:"last_run"`
+	Failures map[string]FailureRecord `json:"failures,omitempty"`
+
+	// Missed records the slot a routine's missed_run policy passed over
+	// (skip or notify), so it isn't run later. Cleared on the next
+	// successful run.
+	Missed map[string]string `json:"missed,omitempty"`
+
+	// Judged records the due slot each routine's missed_run policy last
+	// judged, so a slot held back (by after: or a backoff) isn't judged
+	// again as missed by a later pass or a later --once invocation.
+	Judged map[string]string `json:"judged,omitempty"`
+
+	// Checks holds the times of the last few --once passes, oldest first.
+	// Their gaps give the cron interval, which a slot may wait before a
+	// pass sees it without having been missed.
+	Checks []time.Time `json:"checks,omitempty"`
 }
 
 // StateStore abstracts state persistence.
@@ -49,6 +70,13 @@ type StateStore interface {
 	Save(s *State) error
 }
 
+// FailureRecord tracks consecutive failures for a routine. The routine is not
+// retried until NextEligible. Cleared on the next successful run.
+type FailureRecord struct {
+	Count        int       `json:"count"`
+	NextEligible time.Time `json:"next_eligible"`
+}
+
 // Config holds all dependencies for the scheduler.
 type Config struct {
 	Clock  Clock          // defaults to SystemClock
@@ -57,14 +85,30 @@ type Config struct {
 	Runner RoutineRunner  // routine execution
 	Logger io.Writer      // log output (os.Stderr in prod)
 	Once   bool           // single evaluation pass, then exit
+
+	// BackoffBase is the delay before retrying after the first failure.
+	// Each subsequent consecutive failure doubles it. Defaults to 1 minute.
+	BackoffBase time.Duration
+	// BackoffMax caps the retry delay. Defaults to 1 hour.
+	BackoffMax time.Duration
+
+	// CatchUp runs missed runs immediately whatever the routine's
+	// missed_run policy, including ones already skipped or notified today.
+	CatchUp bool
 }
 
+const (
+	defaultBackoffBase = 1 * time.Minute
+	defaultBackoffMax  = 1 * time.Hour
+)
+
 // Scheduler evaluates routine schedules and launches executions.
 type Scheduler struct {
 	cfg      Config
 	inflight map[string]bool
-	mu       sync.Mutex    // guards inflight map
-	stateMu  sync.Mutex    // serializes state load→modify→save
+	snoozed  map[string]string // routine name → snooze date already logged
+	mu       sync.Mutex        // guards inflight map
+	stateMu  sync.Mutex        // serializes state load→modify→save
 	wg       sync.WaitGroup
 }
 
@@ -76,9 +120,19 @@ func New(cfg Config) *Scheduler {
 	if cfg.Logger == nil {
 		cfg.Logger = io.Discard
 	}
+	if cfg.BackoffBase <= 0 {
+		cfg.BackoffBase = defaultBackoffBase
+	}
+	if cfg.BackoffMax <= 0 {
+		cfg.BackoffMax = defaultBackoffMax
+	}
+	if cfg.BackoffMax < cfg.BackoffBase {
+		cfg.BackoffMax = cfg.BackoffBase
+	}
 	return &Scheduler{
 		cfg:      cfg,
 		inflight: make(map[string]bool),
+		snoozed:  make(map[string]string),
 	}
 }
 
@@ -123,16 +177,30 @@ func (s *Scheduler) tick(ctx context.Context) {
 	}
 
 	now := s.cfg.Clock.Now()
+	if s.cfg.Once {
+		s.recordCheck(now)
+	}
+
+	// Routines run after the routines their `after` names, so a dependency
+	// started this tick is launched first and its dependents wait for it.
+	routines, depErrs := orderByAfter(routines)
+	launched := make(map[string]*tickRun)
 
 	for _, routine := range routines {
-		if routine.Schedule == "" {
+		if routine.Schedule == "" && len(routine.After) == 0 {
 			continue
 		}
 
-		if _, _, err := parseSchedule(routine.Schedule); err != nil {
-			fmt.Fprintf(s.cfg.Logger, "routine %q: invalid schedule %q: %v\n", routine.Name, routine.Schedule, err)
+		if err := depErrs[routine.Name]; err != nil {
+			fmt.Fprintf(s.cfg.Logger, "routine %q: invalid after: %v\n", routine.Name, err)
 			continue
 		}
+		if routine.Schedule != "" {
+			if err := validateSchedule(routine.Schedule); err != nil {
+				fmt.Fprintf(s.cfg.Logger, "routine %q: invalid schedule %q: %v\n", routine.Name, routine.Schedule, err)
+				continue
+			}
+		}
 
 		loc, err := routineLocation(routine)
 		if err != nil {
@@ -144,6 +212,60 @@ func (s *Scheduler) tick(ctx context.Context) {
 		if !isDue(now, routine.Schedule, loc, lastRun) {
 			continue
 		}
+		slot := dueSlot(now, routine.Schedule, loc)
+
+		// A run whose time passed while the daemon was stopped or the
+		// machine asleep follows the routine's missed_run policy.
+		if !s.cfg.CatchUp {
+			if state.Missed[routine.Name] == slot {
+				continue
+			}
+			if at, missed := s.missedRun(routine, slot, now, loc, state); missed {
+				if routine.MissedRun == "notify" {
+					fmt.Fprintf(s.cfg.Logger, "routine %q: missed its %s run, not running it (run it with: gd routines run %s)\n",
+						routine.Name, at.Format("2006-01-02 15:04"), routine.Name)
+				} else {
+					fmt.Fprintf(s.cfg.Logger, "routine %q: missed its %s run, skipping until the next one\n",
+						routine.Name, at.Format("2006-01-02 15:04"))
+				}
+				s.updateState(routine.Name, func(st *State) {
+					st.Missed[routine.Name] = slot
+				})
+				continue
+			}
+		}
+
+		// Snoozed routines are skipped, logged once per snooze.
+		if routine.SnoozedOn(now.In(loc).Format("2006-01-02")) {
+			if s.snoozed[routine.Name] != routine.SnoozeUntil {
+				s.snoozed[routine.Name] = routine.SnoozeUntil
+				fmt.Fprintf(s.cfg.Logger, "routine %q: snoozed until %s, skipping\n", routine.Name, routine.SnoozeUntil)
+			}
+			continue
+		}
+		delete(s.snoozed, routine.Name)
+
+		// Recently failed — wait out the backoff before retrying.
+		if f, ok := state.Failures[routine.Name]; ok && now.Before(f.NextEligible) {
+			continue
+		}
+
+		// Dependencies must have succeeded today; any started this tick are
+		// waited for once this routine's goroutine starts.
+		var waits []*tickRun
+		ready := true
+		today := now.In(loc).Format("2006-01-02")
+		for _, dep := range routine.After {
+			if run, ok := launched[dep]; ok {
+				waits = append(waits, run)
+			} else if !ranOn(state.LastRun[dep], today) {
+				ready = false
+				break
+			}
+		}
+		if !ready {
+			continue
+		}
 
 		s.mu.Lock()
 		if s.inflight[routine.Name] {
@@ -154,7 +276,18 @@ func (s *Scheduler) tick(ctx context.Context) {
 		s.mu.Unlock()
 
 		r := routine // capture for goroutine
-		today := now.In(loc).Format("2006-01-02")
+		run := &tickRun{name: routine.Name, done: make(chan struct{})}
+		launched[routine.Name] = run
+
+		// Catch-up summary: consolidate missed days into this one run.
+		if routine.CatchUp == "summary" {
+			if since := missedSince(now, routine.Schedule, loc, lastRun); since != "" {
+				cp := *routine
+				cp.MissedSince = since
+				r = &cp
+				fmt.Fprintf(s.cfg.Logger, "routine %q: missed runs since %s, producing catch-up summary\n", routine.Name, since)
+			}
+		}
 
 		s.wg.Add(1)
 		go func() {
@@ -164,33 +297,92 @@ func (s *Scheduler) tick(ctx context.Context) {
 				delete(s.inflight, r.Name)
 				s.mu.Unlock()
 			}()
+			defer close(run.done)
+
+			for _, dep := range waits {
+				<-dep.done
+				if !dep.ok {
+					fmt.Fprintf(s.cfg.Logger, "routine %q: skipping, %q did not complete\n", r.Name, dep.name)
+					return
+				}
+			}
 
-			fmt.Fprintf(s.cfg.Logger, "running routine %q (schedule %s)\n", r.Name, r.Schedule)
+			if r.Schedule == "" {
+				fmt.Fprintf(s.cfg.Logger, "running routine %q (after %s)\n", r.Name, strings.Join(r.After, ", "))
+			} else {
+				fmt.Fprintf(s.cfg.Logger, "running routine %q (schedule %s)\n", r.Name, r.Schedule)
+			}
 			if err := s.cfg.Runner(ctx, r); err != nil {
 				fmt.Fprintf(s.cfg.Logger, "routine %q failed: %v\n", r.Name, err)
-				return // don't record failed runs — will retry next tick
+				// Don't record LastRun for failed runs — retry once the backoff expires.
+				s.updateState(r.Name, func(st *State) {
+					f := st.Failures[r.Name]
+					f.Count++
+					delay := s.backoff(f.Count)
+					// A rate-limited service's own Retry-After wins over a shorter backoff.
+					if wait, ok := services.RetryAfter(err); ok && wait > delay {
+						delay = wait
+					}
+					f.NextEligible = s.cfg.Clock.Now().Add(delay)
+					st.Failures[r.Name] = f
+					fmt.Fprintf(s.cfg.Logger, "routine %q: %d consecutive failure(s), next retry after %s\n",
+						r.Name, f.Count, f.NextEligible.Format(time.RFC3339))
+				})
+				return
 			}
 
 			fmt.Fprintf(s.cfg.Logger, "routine %q completed\n", r.Name)
 
-			// Record success. Mutex serializes concurrent load→modify→save
-			// sequences to prevent one goroutine from clobbering another's write.
-			s.stateMu.Lock()
-			current, err := s.cfg.Store.Load()
-			if err != nil {
-				s.stateMu.Unlock()
-				fmt.Fprintf(s.cfg.Logger, "error reloading state after %q: %v\n", r.Name, err)
-				return
-			}
-			current.LastRun[r.Name] = today
-			if err := s.cfg.Store.Save(current); err != nil {
-				fmt.Fprintf(s.cfg.Logger, "error saving state after %q: %v\n", r.Name, err)
-			}
-			s.stateMu.Unlock()
+			s.updateState(r.Name, func(st *State) {
+				st.LastRun[r.Name] = slot
+				delete(st.Failures, r.Name)
+				delete(st.Missed, r.Name)
+			})
+			run.ok = true
 		}()
 	}
 }
 
+// updateState applies fn to freshly loaded state and saves it. The mutex
+// serializes concurrent load→modify→save sequences to prevent one goroutine
+// from clobbering another's write.
+func (s *Scheduler) updateState(name string, fn func(*State)) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+
+	current, err := s.cfg.Store.Load()
+	if err != nil {
+		fmt.Fprintf(s.cfg.Logger, "error reloading state after %q: %v\n", name, err)
+		return
+	}
+	if current.LastRun == nil {
+		current.LastRun = make(map[string]string)
+	}
+	if current.Failures == nil {
+		current.Failures = make(map[string]FailureRecord)
+	}
+	if current.Missed == nil {
+		current.Missed = make(map[string]string)
+	}
+	if current.Judged == nil {
+		current.Judged = make(map[string]string)
+	}
+	fn(current)
+	if err := s.cfg.Store.Save(current); err != nil {
+		fmt.Fprintf(s.cfg.Logger, "error saving state after %q: %v\n", name, err)
+	}
+}
+
+// backoff returns the retry delay after the given number of consecutive
+// failures: BackoffBase doubled per additional failure, capped at BackoffMax.
+func (s *Scheduler) backoff(failures int) time.Duration {
+	delay := s.cfg.BackoffBase
+	for i := 1; i < failures && delay < s.cfg.BackoffMax; i++ {
+		delay *= 2
+	}
+	return min(delay, s.cfg.BackoffMax)
+}
+
 // parseSchedule parses "HH:MM" into hour and minute. Strips surrounding quotes
 // that YAML may preserve.
 func parseSchedule(s string) (int, int, error) {
@@ -224,29 +416,157 @@ func parseSchedule(s string) (int, int, error) {
 	return hour, minute, nil
 }
 
-// isDue returns true if the schedule time has passed today (in loc) and the
-// routine has not yet been run today. lastRunDate is "YYYY-MM-DD" or empty.
-func isDue(now time.Time, schedule string, loc *time.Location, lastRunDate string) bool {
-	hour, minute, err := parseSchedule(schedule)
-	if err != nil {
-		return false
+// clockTime is a time of day parsed from a schedule.
+type clockTime struct {
+	hour, minute int
+}
+
+// parseScheduleTimes parses a schedule of one or more comma-separated
+// "HH:MM" times (e.g. "10:00, 13:00, 16:00"), returned in chronological order.
+func parseScheduleTimes(s string) ([]clockTime, error) {
+	s = strings.Trim(strings.TrimSpace(s), "'\"")
+	var times []clockTime
+	for _, part := range strings.Split(s, ",") {
+		hour, minute, err := parseSchedule(part)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, clockTime{hour, minute})
+	}
+	sort.Slice(times, func(i, j int) bool {
+		return times[i].hour*60+times[i].minute < times[j].hour*60+times[j].minute
+	})
+	return times, nil
+}
+
+// validateSchedule checks that a schedule is an interval, HH:MM times, or a
+// cron expression.
+func validateSchedule(s string) error {
+	if _, ok, err := parseInterval(s); ok {
+		return err
+	}
+	if isCronSchedule(s) {
+		_, err := parseCron(s)
+		return err
+	}
+	_, err := parseScheduleTimes(s)
+	return err
+}
+
+// dueSlot returns the identifier of the most recent scheduled time that has
+// passed today (in loc), or "" if none has. Single-time schedules use the
+// date ("YYYY-MM-DD") so existing state files keep working; multi-time and
+// cron schedules append the slot time ("YYYY-MM-DDTHH:MM"). Interval
+// schedules have no fixed slots; their slot is the current time (RFC 3339).
+// A routine with no schedule, run only after others, has one slot a day.
+func dueSlot(now time.Time, schedule string, loc *time.Location) string {
+	if schedule == "" {
+		return now.In(loc).Format("2006-01-02")
+	}
+	if _, ok, err := parseInterval(schedule); ok {
+		if err != nil {
+			return ""
+		}
+		return intervalSlot(now, loc)
+	}
+
+	t, ok := slotTime(now, schedule, loc)
+	if !ok {
+		return ""
+	}
+	if !isCronSchedule(schedule) && !strings.Contains(schedule, ",") {
+		return t.Format("2006-01-02")
 	}
+	return t.Format("2006-01-02T15:04")
+}
 
+// slotTime returns the most recent time of day or cron firing that has
+// passed today (in loc). It reports false when none has, and for interval
+// schedules and routines without a schedule, which have no fixed times.
+func slotTime(now time.Time, schedule string, loc *time.Location) (time.Time, bool) {
+	if _, ok, _ := parseInterval(schedule); ok || schedule == "" {
+		return time.Time{}, false
+	}
 	nowLocal := now.In(loc)
-	today := nowLocal.Format("2006-01-02")
+	if isCronSchedule(schedule) {
+		c, err := parseCron(schedule)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return c.lastFiring(nowLocal)
+	}
 
-	// Already ran today.
-	if lastRunDate == today {
-		return false
+	times, err := parseScheduleTimes(schedule)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var slot time.Time
+	found := false
+	for _, t := range times {
+		scheduleTime := time.Date(nowLocal.Year(), nowLocal.Month(), nowLocal.Day(), t.hour, t.minute, 0, 0, loc)
+		if nowLocal.Before(scheduleTime) {
+			break
+		}
+		slot, found = scheduleTime, true
 	}
+	return slot, found
+}
 
-	// Schedule time hasn't arrived yet today.
-	scheduleTime := time.Date(nowLocal.Year(), nowLocal.Month(), nowLocal.Day(), hour, minute, 0, 0, loc)
-	if nowLocal.Before(scheduleTime) {
-		return false
+// isDue returns true if a schedule time has passed today (in loc) and the
+// routine has not yet run for that slot. lastRun is the slot recorded by the
+// previous successful run ("YYYY-MM-DD" or "YYYY-MM-DDTHH:MM") or empty.
+// For multi-time schedules, slots missed while the daemon was down collapse
+// into the most recent one. Interval schedules are due once the interval
+// has elapsed since the last run's recorded start time.
+func isDue(now time.Time, schedule string, loc *time.Location, lastRun string) bool {
+	if every, ok, err := parseInterval(schedule); ok {
+		return err == nil && intervalDue(now, every, lastRun)
 	}
+	slot := dueSlot(now, schedule, loc)
+	return slot != "" && slot != lastRun
+}
 
-	return true
+// missedSince returns the date of the last successful run when at least one
+// full day was skipped between it and today (in loc), or "" otherwise
+// (including when the routine has never run). For cron schedules only days
+// the schedule fires on count, so a weekday routine misses nothing over a
+// weekend; interval schedules must also have skipped a whole interval.
+func missedSince(now time.Time, schedule string, loc *time.Location, lastRun string) string {
+	if len(lastRun) < len("2006-01-02") {
+		return ""
+	}
+	lastDate, err := time.ParseInLocation("2006-01-02", lastRun[:10], loc)
+	if err != nil {
+		return ""
+	}
+	nowLocal := now.In(loc)
+	yesterday := time.Date(nowLocal.Year(), nowLocal.Month(), nowLocal.Day()-1, 0, 0, 0, 0, loc)
+	if !lastDate.Before(yesterday) {
+		return ""
+	}
+	if every, ok, err := parseInterval(schedule); ok {
+		if err != nil || !intervalDue(now, 2*every, lastRun) {
+			return ""
+		}
+		return lastDate.Format("2006-01-02")
+	}
+	if isCronSchedule(schedule) {
+		c, err := parseCron(schedule)
+		if err != nil {
+			return ""
+		}
+		skipped := false
+		for d := lastDate.AddDate(0, 0, 1); !d.After(yesterday); d = d.AddDate(0, 0, 1) {
+			if c.firesOn(d) {
+				skipped = true
+				break
+			}
+		}
+		if !skipped {
+			return ""
+		}
+	}
+	return lastDate.Format("2006-01-02")
 }
 
 // routineLocation returns the time.Location for a routine's Timezone field.
@@ -275,7 +595,7 @@ func (f *FileStateStore) Load() (*State, error) {
 	data, err := os.ReadFile(f.path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return &State{LastRun: make(map[string]string)}, nil
+			return &State{LastRun: make(map[string]string), Failures: make(map[string]FailureRecord)}, nil
 		}
 		return nil, fmt.Errorf("reading state file: %w", err)
 	}
@@ -287,6 +607,9 @@ func (f *FileStateStore) Load() (*State, error) {
 	if s.LastRun == nil {
 		s.LastRun = make(map[string]string)
 	}
+	if s.Failures == nil {
+		s.Failures = make(map[string]FailureRecord)
+	}
 	return &s, nil
 }
 
@@ -336,29 +659,49 @@ type MemoryStateStore struct {
 // NewMemoryStateStore creates an empty in-memory state store.
 func NewMemoryStateStore() *MemoryStateStore {
 	return &MemoryStateStore{
-		state: &State{LastRun: make(map[string]string)},
+		state: &State{LastRun: make(map[string]string), Failures: make(map[string]FailureRecord)},
+	}
+}
+
+// copyState returns a deep copy of s with non-nil maps.
+func copyState(s *State) *State {
+	cp := &State{
+		LastRun:  make(map[string]string, len(s.LastRun)),
+		Failures: make(map[string]FailureRecord, len(s.Failures)),
+	}
+	for k, v := range s.LastRun {
+		cp.LastRun[k] = v
+	}
+	for k, v := range s.Failures {
+		cp.Failures[k] = v
+	}
+	if len(s.Missed) > 0 {
+		cp.Missed = make(map[string]string, len(s.Missed))
+		for k, v := range s.Missed {
+			cp.Missed[k] = v
+		}
 	}
+	if len(s.Judged) > 0 {
+		cp.Judged = make(map[string]string, len(s.Judged))
+		for k, v := range s.Judged {
+			cp.Judged[k] = v
+		}
+	}
+	cp.Checks = append([]time.Time(nil), s.Checks...)
+	return cp
 }
 
 // Load returns a copy of the current state.
 func (m *MemoryStateStore) Load() (*State, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	cp := &State{LastRun: make(map[string]string)}
-	for k, v := range m.state.LastRun {
-		cp.LastRun[k] = v
-	}
-	return cp, nil
+	return copyState(m.state), nil
 }
 
 // Save replaces the stored state with a copy.
 func (m *MemoryStateStore) Save(s *State) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	cp := &State{LastRun: make(map[string]string)}
-	for k, v := range s.LastRun {
-		cp.LastRun[k] = v
-	}
-	m.state = cp
+	m.state = copyState(s)
 	return nil
 }
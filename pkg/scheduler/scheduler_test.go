@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/jcadam/burrow/pkg/pipeline"
+	"github.com/jcadam/burrow/pkg/services"
 )
 
 // --- Test Clock ---
@@ -211,6 +212,321 @@ func TestIsDueTimezone(t *testing.T) {
 	}
 }
 
+func TestParseScheduleTimes(t *testing.T) {
+	times, err := parseScheduleTimes("16:00, 10:00,13:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []clockTime{{10, 0}, {13, 0}, {16, 0}}
+	if len(times) != len(want) {
+		t.Fatalf("got %d times, want %d", len(times), len(want))
+	}
+	for i := range want {
+		if times[i] != want[i] {
+			t.Errorf("times[%d] = %v, want %v", i, times[i], want[i])
+		}
+	}
+
+	if _, err := parseScheduleTimes("10:00, 25:00"); err == nil {
+		t.Error("expected error for out-of-range time in list")
+	}
+	if _, err := parseScheduleTimes("10:00,"); err == nil {
+		t.Error("expected error for trailing comma")
+	}
+}
+
+func TestIsDueMultiTime(t *testing.T) {
+	loc := time.UTC
+	schedule := "10:00, 13:00, 16:00"
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		lastRun string
+		want    bool
+	}{
+		{"before first slot", time.Date(2025, 1, 15, 9, 59, 0, 0, loc), "", false},
+		{"first slot", time.Date(2025, 1, 15, 10, 0, 0, 0, loc), "", true},
+		{"first slot done", time.Date(2025, 1, 15, 12, 0, 0, 0, loc), "2025-01-15T10:00", false},
+		{"second slot due", time.Date(2025, 1, 15, 13, 5, 0, 0, loc), "2025-01-15T10:00", true},
+		{"missed slots collapse", time.Date(2025, 1, 15, 17, 0, 0, 0, loc), "2025-01-15T10:00", true},
+		{"last slot done", time.Date(2025, 1, 15, 23, 0, 0, 0, loc), "2025-01-15T16:00", false},
+		{"next day", time.Date(2025, 1, 16, 10, 1, 0, 0, loc), "2025-01-15T16:00", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDue(tt.now, schedule, loc, tt.lastRun); got != tt.want {
+				t.Errorf("isDue(%v, %q) = %v, want %v", tt.now.Format("15:04"), tt.lastRun, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCron(t *testing.T) {
+	valid := []string{
+		"*/30 9-17 * * 1-5",
+		"0 7 * * *",
+		"'15 8,12 1 jan-jun MON-fri'",
+		"0 0 * * 7",
+		"0 6-18/4 * * *",
+	}
+	for _, s := range valid {
+		if _, err := parseCron(s); err != nil {
+			t.Errorf("parseCron(%q): %v", s, err)
+		}
+		if err := validateSchedule(s); err != nil {
+			t.Errorf("validateSchedule(%q): %v", s, err)
+		}
+	}
+
+	invalid := []string{
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"x * * * *",
+		"daily",
+	}
+	for _, s := range invalid {
+		if err := validateSchedule(s); err == nil {
+			t.Errorf("validateSchedule(%q) should fail", s)
+		}
+	}
+}
+
+func TestIsDueCron(t *testing.T) {
+	loc := time.UTC
+	schedule := "*/30 9-17 * * 1-5"
+
+	// 2025-01-15 is a Wednesday; 2025-01-18 a Saturday.
+	tests := []struct {
+		name    string
+		now     time.Time
+		lastRun string
+		want    bool
+	}{
+		{"before hours", time.Date(2025, 1, 15, 8, 59, 0, 0, loc), "", false},
+		{"first slot", time.Date(2025, 1, 15, 9, 0, 0, 0, loc), "", true},
+		{"first slot done", time.Date(2025, 1, 15, 9, 29, 0, 0, loc), "2025-01-15T09:00", false},
+		{"next slot", time.Date(2025, 1, 15, 9, 31, 0, 0, loc), "2025-01-15T09:00", true},
+		{"missed slots collapse", time.Date(2025, 1, 15, 12, 10, 0, 0, loc), "2025-01-15T09:00", true},
+		{"after hours", time.Date(2025, 1, 15, 22, 0, 0, 0, loc), "2025-01-15T17:30", false},
+		{"weekend", time.Date(2025, 1, 18, 10, 0, 0, 0, loc), "2025-01-17T17:30", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDue(tt.now, schedule, loc, tt.lastRun); got != tt.want {
+				t.Errorf("isDue(%v, %q) = %v, want %v", tt.now.Format("Mon 15:04"), tt.lastRun, got, tt.want)
+			}
+		})
+	}
+
+	if got := dueSlot(time.Date(2025, 1, 15, 12, 10, 0, 0, loc), schedule, loc); got != "2025-01-15T12:00" {
+		t.Errorf("dueSlot = %q, want %q", got, "2025-01-15T12:00")
+	}
+
+	// Day of month and day of week both restricted: either matches.
+	either := "0 8 1 * sun"
+	if !isDue(time.Date(2025, 1, 19, 9, 0, 0, 0, loc), either, loc, "") {
+		t.Error("should be due on a Sunday that is not the 1st")
+	}
+	if isDue(time.Date(2025, 1, 15, 9, 0, 0, 0, loc), either, loc, "") {
+		t.Error("should not be due on a Wednesday that is not the 1st")
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		ok      bool
+		wantErr bool
+	}{
+		{"every 4h", 4 * time.Hour, true, false},
+		{"'every 90m'", 90 * time.Minute, true, false},
+		{"every 1h30m", 90 * time.Minute, true, false},
+		{"every 2d", 48 * time.Hour, true, false},
+		{"every 30s", 0, true, true},
+		{"every soon", 0, true, true},
+		{"every xd", 0, true, true},
+		{"07:00", 0, false, false},
+		{"*/30 * * * *", 0, false, false},
+	}
+	for _, tt := range tests {
+		got, ok, err := parseInterval(tt.input)
+		if ok != tt.ok || (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseInterval(%q) = %v, %v, %v; want %v, %v, err=%v", tt.input, got, ok, err, tt.want, tt.ok, tt.wantErr)
+		}
+	}
+	if err := validateSchedule("every 0h"); err == nil {
+		t.Error("validateSchedule should reject a zero interval")
+	}
+}
+
+func TestIsDueInterval(t *testing.T) {
+	loc := time.UTC
+	schedule := "every 4h"
+	now := time.Date(2025, 1, 15, 13, 0, 0, 0, loc)
+
+	tests := []struct {
+		name    string
+		lastRun string
+		want    bool
+	}{
+		{"never run", "", true},
+		{"date-based state", "2025-01-15", true},
+		{"ran 3h ago", "2025-01-15T10:00:00Z", false},
+		{"ran 4h ago", "2025-01-15T09:00:00Z", true},
+		{"ran yesterday evening", "2025-01-14T22:00:00Z", true},
+		{"other offset", "2025-01-15T05:30:00-05:00", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDue(now, schedule, loc, tt.lastRun); got != tt.want {
+				t.Errorf("isDue(%q) = %v, want %v", tt.lastRun, got, tt.want)
+			}
+		})
+	}
+
+	if missedSince(now, "every 2d", loc, "2025-01-12T14:00:00Z") != "" {
+		t.Error("a 2d interval that ran within two intervals missed nothing")
+	}
+	if got := missedSince(now, schedule, loc, "2025-01-12T14:00:00Z"); got != "2025-01-12" {
+		t.Errorf("missedSince = %q, want %q", got, "2025-01-12")
+	}
+}
+
+func TestSchedulerRecordsIntervalTimestamp(t *testing.T) {
+	clock := newTestClock(time.Date(2025, 1, 15, 13, 2, 30, 0, time.UTC))
+	store := NewMemoryStateStore()
+	store.Save(&State{LastRun: map[string]string{"prices": "2025-01-15T09:00:00Z"}})
+	var ran atomic.Int32
+
+	routine := &pipeline.Routine{Name: "prices", Schedule: "every 4h", Timezone: "UTC"}
+
+	s := New(Config{
+		Clock:  clock,
+		Store:  store,
+		Loader: func() ([]*pipeline.Routine, error) { return []*pipeline.Routine{routine}, nil },
+		Runner: func(ctx context.Context, r *pipeline.Routine) error {
+			ran.Add(1)
+			return nil
+		},
+		Once: true,
+	})
+	s.Run(context.Background())
+
+	if ran.Load() != 1 {
+		t.Errorf("runner called %d times, want 1", ran.Load())
+	}
+	state, _ := store.Load()
+	if state.LastRun["prices"] != "2025-01-15T13:02:30Z" {
+		t.Errorf("last run = %q, want %q", state.LastRun["prices"], "2025-01-15T13:02:30Z")
+	}
+}
+
+func TestMissedSinceCron(t *testing.T) {
+	weekdays := "0 7 * * 1-5"
+	monday := time.Date(2025, 1, 20, 7, 1, 0, 0, time.UTC)
+	if got := missedSince(monday, weekdays, time.UTC, "2025-01-17T07:00"); got != "" {
+		t.Errorf("weekend skipped: missedSince = %q, want none", got)
+	}
+	if got := missedSince(monday, weekdays, time.UTC, "2025-01-16T07:00"); got != "2025-01-16" {
+		t.Errorf("Friday skipped: missedSince = %q, want %q", got, "2025-01-16")
+	}
+}
+
+func TestSchedulerRecordsMultiTimeSlot(t *testing.T) {
+	clock := newTestClock(time.Date(2025, 1, 15, 13, 2, 0, 0, time.UTC))
+	store := NewMemoryStateStore()
+	store.Save(&State{LastRun: map[string]string{"prices": "2025-01-15T10:00"}})
+	var ran atomic.Int32
+
+	routine := &pipeline.Routine{Name: "prices", Schedule: "10:00,13:00,16:00", Timezone: "UTC"}
+
+	s := New(Config{
+		Clock:  clock,
+		Store:  store,
+		Loader: func() ([]*pipeline.Routine, error) { return []*pipeline.Routine{routine}, nil },
+		Runner: func(ctx context.Context, r *pipeline.Routine) error {
+			ran.Add(1)
+			return nil
+		},
+		Once: true,
+	})
+	s.Run(context.Background())
+
+	if ran.Load() != 1 {
+		t.Errorf("runner called %d times, want 1", ran.Load())
+	}
+	state, _ := store.Load()
+	if state.LastRun["prices"] != "2025-01-15T13:00" {
+		t.Errorf("last run = %q, want %q", state.LastRun["prices"], "2025-01-15T13:00")
+	}
+}
+
+func TestMissedSince(t *testing.T) {
+	now := time.Date(2025, 1, 15, 5, 1, 0, 0, time.UTC)
+	tests := []struct {
+		lastRun string
+		want    string
+	}{
+		{"", ""},
+		{"2025-01-14", ""},                 // ran yesterday — nothing missed
+		{"2025-01-13", "2025-01-13"},       // missed Jan 14
+		{"2025-01-10T16:00", "2025-01-10"}, // multi-time slot
+		{"garbage!!", ""},
+	}
+	for _, tt := range tests {
+		if got := missedSince(now, "07:00", time.UTC, tt.lastRun); got != tt.want {
+			t.Errorf("missedSince(%q) = %q, want %q", tt.lastRun, got, tt.want)
+		}
+	}
+}
+
+func TestSchedulerCatchUpSummary(t *testing.T) {
+	clock := newTestClock(time.Date(2025, 1, 15, 5, 1, 0, 0, time.UTC))
+	store := NewMemoryStateStore()
+	store.Save(&State{LastRun: map[string]string{"brief": "2025-01-10", "plain": "2025-01-10"}})
+
+	routines := []*pipeline.Routine{
+		{Name: "brief", Schedule: "05:00", Timezone: "UTC", CatchUp: "summary"},
+		{Name: "plain", Schedule: "05:00", Timezone: "UTC"},
+	}
+
+	var mu sync.Mutex
+	got := make(map[string]string)
+
+	s := New(Config{
+		Clock:  clock,
+		Store:  store,
+		Loader: func() ([]*pipeline.Routine, error) { return routines, nil },
+		Runner: func(ctx context.Context, r *pipeline.Routine) error {
+			mu.Lock()
+			got[r.Name] = r.MissedSince
+			mu.Unlock()
+			return nil
+		},
+		Once: true,
+	})
+	s.Run(context.Background())
+
+	if got["brief"] != "2025-01-10" {
+		t.Errorf("brief MissedSince = %q, want %q", got["brief"], "2025-01-10")
+	}
+	if got["plain"] != "" {
+		t.Errorf("plain MissedSince = %q, want empty (catch_up not enabled)", got["plain"])
+	}
+	if routines[0].MissedSince != "" {
+		t.Error("scheduler must not mutate the loaded routine")
+	}
+}
+
 // --- Scheduler integration tests ---
 
 func TestSchedulerRunsRoutineWhenDue(t *testing.T) {
@@ -309,6 +625,51 @@ func TestSchedulerSkipsNoSchedule(t *testing.T) {
 	}
 }
 
+func TestSchedulerSkipsSnoozed(t *testing.T) {
+	clock := newTestClock(time.Date(2025, 1, 15, 5, 1, 0, 0, time.UTC))
+	store := NewMemoryStateStore()
+	var buf strings.Builder
+	var ran atomic.Int32
+
+	routine := &pipeline.Routine{
+		Name:        "morning-brief",
+		Schedule:    "05:00",
+		Timezone:    "UTC",
+		SnoozeUntil: "2025-01-16",
+	}
+
+	s := New(Config{
+		Clock:  clock,
+		Store:  store,
+		Logger: &buf,
+		Loader: func() ([]*pipeline.Routine, error) { return []*pipeline.Routine{routine}, nil },
+		Runner: func(ctx context.Context, r *pipeline.Routine) error {
+			ran.Add(1)
+			return nil
+		},
+	})
+
+	s.tick(context.Background())
+	clock.Advance(time.Minute)
+	s.tick(context.Background())
+	s.wg.Wait()
+
+	if ran.Load() != 0 {
+		t.Errorf("runner called %d times, want 0 (snoozed)", ran.Load())
+	}
+	if n := strings.Count(buf.String(), "snoozed until 2025-01-16"); n != 1 {
+		t.Errorf("expected one snooze log line, got %d: %q", n, buf.String())
+	}
+
+	// The snooze date itself runs as scheduled.
+	clock.Advance(24 * time.Hour)
+	s.tick(context.Background())
+	s.wg.Wait()
+	if ran.Load() != 1 {
+		t.Errorf("runner called %d times on the snooze date, want 1", ran.Load())
+	}
+}
+
 func TestSchedulerSkipsInflight(t *testing.T) {
 	clock := newTestClock(time.Date(2025, 1, 15, 5, 1, 0, 0, time.UTC))
 	store := NewMemoryStateStore()
@@ -535,6 +896,150 @@ func TestSchedulerFailedRunRetries(t *testing.T) {
 	}
 }
 
+func TestSchedulerFailureRecordsBackoff(t *testing.T) {
+	now := time.Date(2025, 1, 15, 5, 1, 0, 0, time.UTC)
+	clock := newTestClock(now)
+	store := NewMemoryStateStore()
+	store.Save(&State{
+		LastRun:  map[string]string{},
+		Failures: map[string]FailureRecord{"flaky": {Count: 2, NextEligible: now.Add(-time.Minute)}},
+	})
+
+	routine := &pipeline.Routine{Name: "flaky", Schedule: "05:00", Timezone: "UTC"}
+
+	s := New(Config{
+		Clock:  clock,
+		Store:  store,
+		Loader: func() ([]*pipeline.Routine, error) { return []*pipeline.Routine{routine}, nil },
+		Runner: func(ctx context.Context, r *pipeline.Routine) error {
+			return fmt.Errorf("upstream down")
+		},
+		Once: true,
+	})
+	s.Run(context.Background())
+
+	state, _ := store.Load()
+	f, ok := state.Failures["flaky"]
+	if !ok {
+		t.Fatal("expected failure record")
+	}
+	if f.Count != 3 {
+		t.Errorf("failure count = %d, want 3", f.Count)
+	}
+	// Third consecutive failure: 1m * 2^2 = 4m.
+	if want := now.Add(4 * time.Minute); !f.NextEligible.Equal(want) {
+		t.Errorf("next eligible = %v, want %v", f.NextEligible, want)
+	}
+	if _, ok := state.LastRun["flaky"]; ok {
+		t.Error("LastRun should not be recorded after failure")
+	}
+}
+
+func TestSchedulerFailureHonorsRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 15, 5, 1, 0, 0, time.UTC)
+	clock := newTestClock(now)
+	store := NewMemoryStateStore()
+
+	routine := &pipeline.Routine{Name: "limited", Schedule: "05:00", Timezone: "UTC"}
+
+	s := New(Config{
+		Clock:  clock,
+		Store:  store,
+		Loader: func() ([]*pipeline.Routine, error) { return []*pipeline.Routine{routine}, nil },
+		Runner: func(ctx context.Context, r *pipeline.Routine) error {
+			err := &services.RetryAfterError{Err: fmt.Errorf("HTTP 429"), After: 10 * time.Minute}
+			return fmt.Errorf("running routine: %w", err)
+		},
+		Once: true,
+	})
+	s.Run(context.Background())
+
+	state, _ := store.Load()
+	// First failure backs off 1m, but the service asked for 10m.
+	if want := now.Add(10 * time.Minute); !state.Failures["limited"].NextEligible.Equal(want) {
+		t.Errorf("next eligible = %v, want %v", state.Failures["limited"].NextEligible, want)
+	}
+}
+
+func TestSchedulerSkipsDuringBackoff(t *testing.T) {
+	now := time.Date(2025, 1, 15, 5, 1, 0, 0, time.UTC)
+	clock := newTestClock(now)
+	store := NewMemoryStateStore()
+	store.Save(&State{
+		LastRun:  map[string]string{},
+		Failures: map[string]FailureRecord{"flaky": {Count: 1, NextEligible: now.Add(time.Minute)}},
+	})
+	var ran atomic.Int32
+
+	routine := &pipeline.Routine{Name: "flaky", Schedule: "05:00", Timezone: "UTC"}
+
+	s := New(Config{
+		Clock:  clock,
+		Store:  store,
+		Loader: func() ([]*pipeline.Routine, error) { return []*pipeline.Routine{routine}, nil },
+		Runner: func(ctx context.Context, r *pipeline.Routine) error {
+			ran.Add(1)
+			return nil
+		},
+		Once: true,
+	})
+	s.Run(context.Background())
+
+	if ran.Load() != 0 {
+		t.Errorf("runner called %d times, want 0 (backoff not expired)", ran.Load())
+	}
+}
+
+func TestSchedulerSuccessClearsBackoff(t *testing.T) {
+	now := time.Date(2025, 1, 15, 5, 1, 0, 0, time.UTC)
+	clock := newTestClock(now)
+	store := NewMemoryStateStore()
+	store.Save(&State{
+		LastRun:  map[string]string{},
+		Failures: map[string]FailureRecord{"flaky": {Count: 4, NextEligible: now}},
+	})
+
+	routine := &pipeline.Routine{Name: "flaky", Schedule: "05:00", Timezone: "UTC"}
+
+	s := New(Config{
+		Clock:  clock,
+		Store:  store,
+		Loader: func() ([]*pipeline.Routine, error) { return []*pipeline.Routine{routine}, nil },
+		Runner: func(ctx context.Context, r *pipeline.Routine) error { return nil },
+		Once:   true,
+	})
+	s.Run(context.Background())
+
+	state, _ := store.Load()
+	if _, ok := state.Failures["flaky"]; ok {
+		t.Error("failure record should be cleared after success")
+	}
+	if state.LastRun["flaky"] != "2025-01-15" {
+		t.Errorf("last run = %q, want %q", state.LastRun["flaky"], "2025-01-15")
+	}
+}
+
+func TestBackoffCapped(t *testing.T) {
+	s := New(Config{BackoffBase: time.Minute, BackoffMax: 10 * time.Minute})
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, 1 * time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{4, 8 * time.Minute},
+		{5, 10 * time.Minute},
+		{50, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := s.backoff(tt.failures); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
 func TestSchedulerConcurrentCompletionsBothPersist(t *testing.T) {
 	clock := newTestClock(time.Date(2025, 1, 15, 5, 1, 0, 0, time.UTC))
 	store := NewMemoryStateStore()
@@ -748,3 +1253,320 @@ func TestRoutineLocation(t *testing.T) {
 		t.Error("expected error for invalid timezone")
 	}
 }
+
+func TestOrderByAfter(t *testing.T) {
+	routines := []*pipeline.Routine{
+		{Name: "digest", After: []string{"intel", "prices"}},
+		{Name: "intel"},
+		{Name: "prices", After: []string{"intel"}},
+		{Name: "a", After: []string{"b"}},
+		{Name: "b", After: []string{"a"}},
+		{Name: "c", After: []string{"a"}},
+		{Name: "orphan", After: []string{"missing"}},
+	}
+	ordered, errs := orderByAfter(routines)
+
+	var names []string
+	for _, r := range ordered {
+		names = append(names, r.Name)
+	}
+	if got := strings.Join(names, " "); got != "intel prices digest b a c orphan" {
+		t.Errorf("order = %s", got)
+	}
+	for _, name := range []string{"digest", "intel", "prices"} {
+		if errs[name] != nil {
+			t.Errorf("%s: unexpected error %v", name, errs[name])
+		}
+	}
+	for name, want := range map[string]string{
+		"a":      "dependency cycle a → b → a",
+		"b":      "dependency cycle a → b → a",
+		"c":      `depends on "a"`,
+		"orphan": `unknown routine "missing"`,
+	} {
+		if errs[name] == nil || !strings.Contains(errs[name].Error(), want) {
+			t.Errorf("%s: error = %v, want %q", name, errs[name], want)
+		}
+	}
+}
+
+// lockedWriter is a log writer safe for concurrently running routines.
+type lockedWriter struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.Write(p)
+}
+
+func (w *lockedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.String()
+}
+
+// runAfterTick runs one scheduler pass over routines, returning the names
+// run in order and the log. fail names routines whose runner fails.
+func runAfterTick(t *testing.T, store StateStore, routines []*pipeline.Routine, fail string) ([]string, string) {
+	t.Helper()
+	var mu sync.Mutex
+	var ran []string
+	var buf lockedWriter
+	s := New(Config{
+		Clock:  newTestClock(time.Date(2025, 1, 15, 5, 1, 0, 0, time.UTC)),
+		Store:  store,
+		Loader: func() ([]*pipeline.Routine, error) { return routines, nil },
+		Runner: func(ctx context.Context, r *pipeline.Routine) error {
+			time.Sleep(10 * time.Millisecond) // let dependents start waiting
+			mu.Lock()
+			ran = append(ran, r.Name)
+			mu.Unlock()
+			if r.Name == fail {
+				return fmt.Errorf("boom")
+			}
+			return nil
+		},
+		Logger: &buf,
+		Once:   true,
+	})
+	s.Run(context.Background())
+	return ran, buf.String()
+}
+
+func TestSchedulerRunsAfterDependencies(t *testing.T) {
+	routines := []*pipeline.Routine{
+		{Name: "digest", After: []string{"intel"}, Timezone: "UTC"},
+		{Name: "intel", Schedule: "05:00", Timezone: "UTC"},
+	}
+
+	store := NewMemoryStateStore()
+	ran, _ := runAfterTick(t, store, routines, "")
+	if strings.Join(ran, " ") != "intel digest" {
+		t.Errorf("ran = %v, want intel then digest", ran)
+	}
+	state, _ := store.Load()
+	if state.LastRun["digest"] != "2025-01-15" {
+		t.Errorf("digest last run = %q, want %q", state.LastRun["digest"], "2025-01-15")
+	}
+
+	// Both done for today: nothing runs again.
+	if ran, _ := runAfterTick(t, store, routines, ""); len(ran) != 0 {
+		t.Errorf("second pass ran %v, want nothing", ran)
+	}
+}
+
+func TestSchedulerSkipsAfterFailedDependency(t *testing.T) {
+	routines := []*pipeline.Routine{
+		{Name: "intel", Schedule: "05:00", Timezone: "UTC"},
+		{Name: "digest", After: []string{"intel"}, Timezone: "UTC"},
+	}
+	store := NewMemoryStateStore()
+	ran, log := runAfterTick(t, store, routines, "intel")
+	if strings.Join(ran, " ") != "intel" {
+		t.Errorf("ran = %v, want only intel", ran)
+	}
+	if !strings.Contains(log, `routine "digest": skipping, "intel" did not complete`) {
+		t.Errorf("log = %q", log)
+	}
+	state, _ := store.Load()
+	if _, ok := state.LastRun["digest"]; ok {
+		t.Error("digest should not be recorded as run")
+	}
+}
+
+func TestSchedulerWaitsForDependencyToday(t *testing.T) {
+	routines := []*pipeline.Routine{
+		{Name: "intel", Schedule: "23:00", Timezone: "UTC"},
+		{Name: "digest", After: []string{"intel"}, Timezone: "UTC"},
+	}
+
+	// intel last ran yesterday and isn't due until tonight.
+	store := NewMemoryStateStore()
+	store.Save(&State{LastRun: map[string]string{"intel": "2025-01-14"}})
+	if ran, _ := runAfterTick(t, store, routines, ""); len(ran) != 0 {
+		t.Errorf("ran %v, want nothing before intel completes today", ran)
+	}
+
+	// intel completed earlier today (say, a run triggered before 05:01).
+	store.Save(&State{LastRun: map[string]string{"intel": "2025-01-15"}})
+	if ran, _ := runAfterTick(t, store, routines, ""); strings.Join(ran, " ") != "digest" {
+		t.Errorf("ran = %v, want digest", ran)
+	}
+}
+
+func TestSchedulerLogsDependencyCycle(t *testing.T) {
+	routines := []*pipeline.Routine{
+		{Name: "a", Schedule: "05:00", After: []string{"b"}, Timezone: "UTC"},
+		{Name: "b", Schedule: "05:00", After: []string{"a"}, Timezone: "UTC"},
+	}
+	ran, log := runAfterTick(t, NewMemoryStateStore(), routines, "")
+	if len(ran) != 0 {
+		t.Errorf("ran %v, want nothing", ran)
+	}
+	if !strings.Contains(log, "dependency cycle") {
+		t.Errorf("log = %q, want dependency cycle", log)
+	}
+}
+
+// runMissedTick runs one --once scheduler pass at now over routine and any
+// others it depends on, returning whether routine ran and the log.
+func runMissedTick(t *testing.T, store StateStore, routine *pipeline.Routine, now time.Time, catchUp bool, others ...*pipeline.Routine) (bool, string) {
+	t.Helper()
+	var ran atomic.Bool
+	var buf lockedWriter
+	s := New(Config{
+		Clock:  newTestClock(now),
+		Store:  store,
+		Loader: func() ([]*pipeline.Routine, error) { return append([]*pipeline.Routine{routine}, others...), nil },
+		Runner: func(ctx context.Context, r *pipeline.Routine) error {
+			if r.Name == routine.Name {
+				ran.Store(true)
+			}
+			return nil
+		},
+		Logger:  &buf,
+		Once:    true,
+		CatchUp: catchUp,
+	})
+	s.Run(context.Background())
+	return ran.Load(), buf.String()
+}
+
+func TestSchedulerMissedRunPolicy(t *testing.T) {
+	late := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	onTime := time.Date(2025, 1, 15, 5, 2, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		policy  string
+		now     time.Time
+		wantRun bool
+		wantLog string
+	}{
+		{"default runs late", "", late, true, ""},
+		{"run_immediately", "run_immediately", late, true, ""},
+		{"skip", "skip", late, false, "missed its 2025-01-15 05:00 run, skipping until the next one"},
+		{"notify", "notify", late, false, "run it with: gd routines run brief"},
+		{"skip on time", "skip", onTime, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routine := &pipeline.Routine{Name: "brief", Schedule: "05:00", Timezone: "UTC", MissedRun: tt.policy}
+			store := NewMemoryStateStore()
+			ran, log := runMissedTick(t, store, routine, tt.now, false)
+			if ran != tt.wantRun {
+				t.Errorf("ran = %v, want %v", ran, tt.wantRun)
+			}
+			if !strings.Contains(log, tt.wantLog) {
+				t.Errorf("log = %q, want %q", log, tt.wantLog)
+			}
+			state, _ := store.Load()
+			if tt.wantRun {
+				if state.Missed["brief"] != "" {
+					t.Errorf("missed = %q, want none", state.Missed["brief"])
+				}
+				return
+			}
+			if state.Missed["brief"] != "2025-01-15" {
+				t.Errorf("missed = %q, want %q", state.Missed["brief"], "2025-01-15")
+			}
+
+			// A restarted daemon doesn't run the passed-over slot either.
+			if ran, _ := runMissedTick(t, store, routine, late.Add(time.Hour), false); ran {
+				t.Error("passed-over slot ran after restart")
+			}
+		})
+	}
+}
+
+func TestSchedulerCatchUpOverridesMissedRun(t *testing.T) {
+	routine := &pipeline.Routine{Name: "brief", Schedule: "05:00", Timezone: "UTC", MissedRun: "skip"}
+	store := NewMemoryStateStore()
+	store.Save(&State{LastRun: map[string]string{}, Missed: map[string]string{"brief": "2025-01-15"}})
+
+	ran, _ := runMissedTick(t, store, routine, time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC), true)
+	if !ran {
+		t.Fatal("--catch-up should run the missed slot")
+	}
+	state, _ := store.Load()
+	if state.LastRun["brief"] != "2025-01-15" || len(state.Missed) != 0 {
+		t.Errorf("state = %+v, want run recorded and missed cleared", state)
+	}
+}
+
+func TestMissedRunJudgedOnce(t *testing.T) {
+	routine := &pipeline.Routine{Name: "brief", Schedule: "05:00", MissedRun: "skip"}
+	store := NewMemoryStateStore()
+	s := New(Config{Store: store})
+	judge := func(now time.Time) bool {
+		state, _ := store.Load()
+		_, missed := s.missedRun(routine, "2025-01-15", now, time.UTC, state)
+		return missed
+	}
+
+	// First seen on time (say, held back by a dependency), then late.
+	if judge(time.Date(2025, 1, 15, 5, 0, 0, 0, time.UTC)) {
+		t.Error("on-time slot judged missed")
+	}
+	if judge(time.Date(2025, 1, 15, 7, 0, 0, 0, time.UTC)) {
+		t.Error("slot seen on time judged missed later")
+	}
+
+	// A failed routine retrying after its backoff isn't missed.
+	store = NewMemoryStateStore()
+	store.Save(&State{Failures: map[string]FailureRecord{"brief": {Count: 1}}})
+	s = New(Config{Store: store})
+	if judge(time.Date(2025, 1, 15, 7, 0, 0, 0, time.UTC)) {
+		t.Error("retry judged missed")
+	}
+}
+
+func TestMissedRunJudgedAcrossOnceInvocations(t *testing.T) {
+	store := NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+	store.Save(&State{LastRun: map[string]string{"fetch": "2025-01-14"}})
+	routine := &pipeline.Routine{Name: "brief", Schedule: "05:00", Timezone: "UTC", MissedRun: "skip", After: []string{"fetch"}}
+
+	fetch := &pipeline.Routine{Name: "fetch"}
+
+	// Held back by after: at 05:00 (fetch hasn't run today)...
+	if ran, _ := runMissedTick(t, store, routine, time.Date(2025, 1, 15, 5, 0, 0, 0, time.UTC), false, fetch); ran {
+		t.Fatal("ran before its dependency")
+	}
+
+	// ...then fetch succeeds and a later invocation, a new Scheduler over
+	// the same state file, runs the slot instead of calling it missed.
+	state, _ := store.Load()
+	state.LastRun["fetch"] = "2025-01-15"
+	store.Save(state)
+	ran, log := runMissedTick(t, store, routine, time.Date(2025, 1, 15, 6, 0, 0, 0, time.UTC), false, fetch)
+	if !ran || strings.Contains(log, "missed") {
+		t.Errorf("held-back slot not run by the next invocation: ran=%v log=%q", ran, log)
+	}
+}
+
+func TestMissedRunAllowsOnceCadence(t *testing.T) {
+	store := NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+	routine := &pipeline.Routine{Name: "brief", Schedule: "09:00", Timezone: "UTC", MissedRun: "skip"}
+	idle := &pipeline.Routine{Name: "brief", Schedule: "23:00", Timezone: "UTC", MissedRun: "skip"}
+
+	// A 15-minute cron job: 08:40 and 08:55 pass with nothing due.
+	runMissedTick(t, store, idle, time.Date(2025, 1, 15, 8, 40, 0, 0, time.UTC), false)
+	runMissedTick(t, store, idle, time.Date(2025, 1, 15, 8, 55, 0, 0, time.UTC), false)
+
+	// 09:10 is past missedGrace but within the cron interval.
+	ran, log := runMissedTick(t, store, routine, time.Date(2025, 1, 15, 9, 10, 0, 0, time.UTC), false)
+	if !ran {
+		t.Errorf("09:00 run seen by the 09:10 invocation judged missed: %q", log)
+	}
+
+	// Overnight the machine slept; the next morning it checks at 08:40
+	// and 08:55, then is off until 14:00, so that day's run is missed.
+	runMissedTick(t, store, idle, time.Date(2025, 1, 16, 8, 40, 0, 0, time.UTC), false)
+	runMissedTick(t, store, idle, time.Date(2025, 1, 16, 8, 55, 0, 0, time.UTC), false)
+	if ran, _ := runMissedTick(t, store, routine, time.Date(2025, 1, 16, 14, 0, 0, 0, time.UTC), false); ran {
+		t.Error("run five hours late not judged missed")
+	}
+}
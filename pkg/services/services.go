@@ -3,6 +3,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"sync"
@@ -23,7 +24,73 @@ type Result struct {
 	URL          string // the request URL (for debugging)
 	Timestamp    time.Time
 	Error        string
-	ContextLabel string // user-provided label for better synthesis prompts (e.g., "NWS 7-Day Forecast — Anchorage")
+	ContextLabel string   // user-provided label for better synthesis prompts (e.g., "NWS 7-Day Forecast — Anchorage")
+	Tags         []string // user-provided source tags, shown to synthesis to group and prioritize sections
+
+	// Headers holds response headers the tool is configured to capture,
+	// keyed by canonical header name. Nil when none are captured.
+	Headers map[string]string
+
+	// Validators identify this response for later conditional requests.
+	Validators Validators
+	// NotModified is set when a conditional request returned 304; Data is
+	// empty and the caller's cached copy is still current.
+	NotModified bool
+	// Empty is set when the query succeeded but returned no items. Data
+	// still holds the response; synthesis reports the source as "no results".
+	Empty bool
+	// ItemsPath is a dot path to the result items in Data, from the tool's
+	// results_path. Empty when the service doesn't know one.
+	ItemsPath string
+	// Note is context about the data for synthesis, shown before it (e.g.
+	// "Showing top 20 of 500 items.").
+	Note string
+	// ContentType is the response's media type (e.g. "application/pdf"),
+	// without parameters. Empty when the service doesn't report one.
+	ContentType string
+	// RetryAfter is how long a rate-limited service asked callers to wait
+	// before trying again (its Retry-After header). Zero when not given.
+	RetryAfter time.Duration
+}
+
+// RetryAfterError wraps an error caused by a rate-limited service that said
+// how long to wait before trying again.
+type RetryAfterError struct {
+	Err   error
+	After time.Duration
+}
+
+func (e *RetryAfterError) Error() string { return e.Err.Error() }
+func (e *RetryAfterError) Unwrap() error { return e.Err }
+
+// RetryAfter returns the wait requested by a RetryAfterError in err's chain.
+func RetryAfter(err error) (time.Duration, bool) {
+	var ra *RetryAfterError
+	if errors.As(err, &ra) {
+		return ra.After, true
+	}
+	return 0, false
+}
+
+// Validators are the HTTP cache validators of a response (ETag and
+// Last-Modified). The zero value means the response had none.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// IsZero reports whether no validators are set.
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// ConditionalService is implemented by services that support HTTP
+// conditional requests. ExecuteConditional behaves like Execute but sends
+// the validators (If-None-Match / If-Modified-Since) so an unchanged
+// upstream can answer with a Result whose NotModified is set.
+type ConditionalService interface {
+	Service
+	ExecuteConditional(ctx context.Context, tool string, params map[string]string, v Validators) (*Result, error)
 }
 
 // Registry manages named service instances.
@@ -0,0 +1,428 @@
+// Package stream provides a service adapter for push sources — Server-Sent
+// Events and websockets — that collects events for a bounded window.
+package stream
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/config"
+	bhttp "github.com/jcadam/burrow/pkg/http"
+	"github.com/jcadam/burrow/pkg/privacy"
+	"github.com/jcadam/burrow/pkg/ratelimit"
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+const (
+	defaultWindow    = 30 * time.Second
+	defaultMaxEvents = 100
+	// maxEventBytes bounds a single event or websocket message.
+	maxEventBytes = 1 << 20
+)
+
+// StreamService implements services.Service for streaming endpoints. An
+// http(s) endpoint is read as Server-Sent Events; a ws(s) endpoint is a
+// websocket. Each call connects, collects events until the window elapses,
+// max events arrive, or the server closes, then disconnects. Nothing is ever
+// sent on the stream beyond the protocol's own control replies.
+type StreamService struct {
+	name      string
+	endpoint  string
+	auth      config.AuthConfig
+	window    time.Duration
+	maxEvents int
+	client    *http.Client
+}
+
+// NewStreamService creates a stream service from config. As with other HTTP
+// services, each gets its own http.Client for per-service proxy routing, and
+// a non-nil privacyCfg applies the privacy transport. The client has no
+// overall timeout; the collection window bounds each call instead.
+func NewStreamService(cfg config.ServiceConfig, privacyCfg *privacy.Config, proxyURL string) *StreamService {
+	baseTransport := &http.Transport{}
+	cfg.Transport.Apply(baseTransport)
+	if proxyURL != "" {
+		if parsed, err := url.Parse(proxyURL); err == nil && parsed != nil {
+			baseTransport.Proxy = http.ProxyURL(parsed)
+		}
+	}
+	var transport http.RoundTripper = baseTransport
+	if privacyCfg != nil {
+		transport = privacy.NewTransport(baseTransport, *privacyCfg)
+	}
+
+	window := time.Duration(cfg.Window) * time.Second
+	if window <= 0 {
+		window = defaultWindow
+	}
+	maxEvents := cfg.MaxEvents
+	if maxEvents <= 0 {
+		maxEvents = defaultMaxEvents
+	}
+
+	return &StreamService{
+		name:      cfg.Name,
+		endpoint:  cfg.Endpoint,
+		auth:      cfg.AuthFor("events"),
+		window:    window,
+		maxEvents: maxEvents,
+		client:    &http.Client{Transport: transport, CheckRedirect: cfg.CheckRedirect()},
+	}
+}
+
+func (s *StreamService) Name() string { return s.name }
+
+// StreamResult is the JSON output structure for a collection window.
+type StreamResult struct {
+	Events      []Event `json:"events"`
+	EventCount  int     `json:"event_count"`
+	CollectedAt string  `json:"collected_at"`
+	// Ended says why collection stopped: "window", "max_events",
+	// "closed" by the server, or "connection lost" after some events.
+	Ended string `json:"ended"`
+}
+
+// Event is one SSE event or websocket message. Data is embedded as JSON
+// when it parses as JSON, otherwise as a string.
+type Event struct {
+	Event string          `json:"event,omitempty"`
+	ID    string          `json:"id,omitempty"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// Execute runs the "events" tool, which connects to the stream and returns
+// the events collected.
+func (s *StreamService) Execute(ctx context.Context, tool string, params map[string]string) (*services.Result, error) {
+	if tool != "events" {
+		return nil, fmt.Errorf("service %q has no tool %q (stream services only support \"events\")", s.name, tool)
+	}
+
+	wctx, cancel := context.WithTimeout(ctx, s.window)
+	defer cancel()
+
+	var events []Event
+	var err error
+	if isWebsocket(s.endpoint) {
+		events, err = s.collectWebsocket(wctx)
+	} else {
+		events, err = s.collectSSE(wctx)
+	}
+
+	ended := "closed"
+	switch {
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	case len(events) >= s.maxEvents:
+		ended = "max_events"
+	case wctx.Err() != nil:
+		ended = "window"
+	case err != nil && len(events) > 0:
+		ended = "connection lost"
+	case err != nil:
+		return &services.Result{
+			Service:   s.name,
+			Tool:      tool,
+			URL:       s.endpoint,
+			Timestamp: time.Now().UTC(),
+			Error:     err.Error(),
+		}, nil
+	}
+
+	if events == nil {
+		events = []Event{}
+	}
+	data, err := json.Marshal(StreamResult{
+		Events:      events,
+		EventCount:  len(events),
+		CollectedAt: time.Now().UTC().Format(time.RFC3339),
+		Ended:       ended,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling result: %w", err)
+	}
+
+	return &services.Result{
+		Service:     s.name,
+		Tool:        tool,
+		Data:        data,
+		URL:         s.endpoint,
+		Timestamp:   time.Now().UTC(),
+		Empty:       len(events) == 0,
+		ContentType: "application/json",
+	}, nil
+}
+
+func isWebsocket(endpoint string) bool {
+	return strings.HasPrefix(endpoint, "ws://") || strings.HasPrefix(endpoint, "wss://")
+}
+
+// connect opens the stream request, which counts against the run's request
+// budget. Errors before the stream starts, including HTTP error statuses,
+// are returned as errors.
+func (s *StreamService) connect(ctx context.Context, endpoint string, header http.Header, wantStatus int) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	s.auth.Apply(req)
+
+	if err := ratelimit.Take(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != wantStatus {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		errMsg := fmt.Sprintf("HTTP %d", resp.StatusCode)
+		if snippet := bhttp.ErrorSnippet(body, resp.Header.Get("Content-Type")); snippet != "" {
+			errMsg += ": " + snippet
+		}
+		return nil, errors.New(errMsg)
+	}
+	return resp, nil
+}
+
+// collectSSE reads Server-Sent Events until ctx ends, max events arrive,
+// or the server closes the stream.
+func (s *StreamService) collectSSE(ctx context.Context) ([]Event, error) {
+	resp, err := s.connect(ctx, s.endpoint, http.Header{"Accept": {"text/event-stream"}}, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventBytes)
+
+	var events []Event
+	var ev Event
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// A blank line dispatches the event; events without data are dropped.
+			if data != nil {
+				ev.Data = eventData(strings.Join(data, "\n"))
+				events = append(events, ev)
+				if len(events) >= s.maxEvents {
+					return events, nil
+				}
+			}
+			ev, data = Event{}, nil
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment, often a keepalive
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			ev.Event = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			ev.ID = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return events, fmt.Errorf("reading stream: %w", err)
+	}
+	return events, nil
+}
+
+// collectWebsocket performs the websocket handshake over the service's HTTP
+// client, so proxy routing and privacy hardening apply, then reads messages
+// until ctx ends, max events arrive, or the server closes the connection.
+func (s *StreamService) collectWebsocket(ctx context.Context) ([]Event, error) {
+	endpoint := "http" + strings.TrimPrefix(s.endpoint, "ws")
+
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, fmt.Errorf("generating handshake key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+
+	resp, err := s.connect(ctx, endpoint, http.Header{
+		"Connection":            {"Upgrade"},
+		"Upgrade":               {"websocket"},
+		"Sec-Websocket-Key":     {key},
+		"Sec-Websocket-Version": {"13"},
+	}, http.StatusSwitchingProtocols)
+	if err != nil {
+		return nil, err
+	}
+	conn, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		resp.Body.Close()
+		return nil, fmt.Errorf("websocket upgrade not supported by transport")
+	}
+	defer conn.Close()
+	if resp.Header.Get("Sec-Websocket-Accept") != acceptKey(key) {
+		return nil, fmt.Errorf("websocket handshake failed: bad Sec-WebSocket-Accept")
+	}
+
+	// The upgraded connection doesn't watch ctx; closing it ends a blocked read.
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	ws := &wsConn{r: bufio.NewReader(conn), w: conn}
+	var events []Event
+	for len(events) < s.maxEvents {
+		msg, err := ws.readMessage()
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		if err != nil {
+			return events, fmt.Errorf("reading websocket: %w", err)
+		}
+		events = append(events, Event{Data: eventData(string(msg))})
+	}
+	ws.writeFrame(opClose, nil) //nolint:errcheck // best-effort goodbye
+	return events, nil
+}
+
+func eventData(s string) json.RawMessage {
+	if json.Valid([]byte(s)) {
+		return json.RawMessage(s)
+	}
+	data, _ := json.Marshal(s)
+	return data
+}
+
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + wsGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// wsConn is the client side of a websocket connection: just enough of RFC
+// 6455 to read messages and answer pings and closes.
+type wsConn struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+// readMessage returns the next text or binary message, reassembling
+// fragments. It returns io.EOF when the server closes the connection.
+func (c *wsConn) readMessage() ([]byte, error) {
+	var msg []byte
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			c.writeFrame(opClose, nil) //nolint:errcheck // best-effort reply
+			return nil, io.EOF
+		case opText, opBinary, opContinuation:
+			msg = append(msg, payload...)
+			if len(msg) > maxEventBytes {
+				return nil, fmt.Errorf("message exceeds %d bytes", maxEventBytes)
+			}
+			if fin {
+				return msg, nil
+			}
+		default:
+			return nil, fmt.Errorf("unknown opcode %#x", op)
+		}
+	}
+}
+
+func (c *wsConn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var hdr [2]byte
+	if _, err := io.ReadFull(c.r, hdr[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = hdr[0]&0x80 != 0
+	op = hdr[0] & 0x0f
+	masked := hdr[1]&0x80 != 0
+
+	n := uint64(hdr[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > maxEventBytes {
+		return false, 0, nil, fmt.Errorf("frame exceeds %d bytes", maxEventBytes)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.r, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, op, payload, nil
+}
+
+// writeFrame sends a control frame. Client frames must be masked.
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	if len(payload) > 125 {
+		payload = payload[:125]
+	}
+	frame := []byte{0x80 | op, 0x80 | byte(len(payload)), 0, 0, 0, 0}
+	if _, err := rand.Read(frame[2:6]); err != nil {
+		return err
+	}
+	for i, b := range payload {
+		frame = append(frame, b^frame[2+i%4])
+	}
+	_, err := c.w.Write(frame)
+	return err
+}
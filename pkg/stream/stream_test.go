@@ -0,0 +1,302 @@
+package stream
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jcadam/burrow/pkg/config"
+	"github.com/jcadam/burrow/pkg/privacy"
+)
+
+func newService(endpoint string, window, maxEvents int) *StreamService {
+	return NewStreamService(config.ServiceConfig{
+		Name:      "live",
+		Type:      "stream",
+		Endpoint:  endpoint,
+		Window:    window,
+		MaxEvents: maxEvents,
+	}, nil, "")
+}
+
+func decode(t *testing.T, data []byte) StreamResult {
+	t.Helper()
+	var r StreamResult
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("decoding result: %v\n%s", err, data)
+	}
+	return r
+}
+
+func TestExecuteSSEStopsAtMaxEvents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(": keepalive\n\n"))
+		w.Write([]byte("event: quake\nid: 1\ndata: {\"mag\": 4.1}\n\n"))
+		w.Write([]byte("data: first line\ndata: second line\n\n"))
+		w.Write([]byte("data: never read\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	result, err := newService(srv.URL, 5, 2).Execute(context.Background(), "events", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("unexpected error: %s", result.Error)
+	}
+	r := decode(t, result.Data)
+	if r.EventCount != 2 || r.Ended != "max_events" {
+		t.Fatalf("got %d events, ended %q", r.EventCount, r.Ended)
+	}
+	if r.Events[0].Event != "quake" || r.Events[0].ID != "1" || string(r.Events[0].Data) != `{"mag":4.1}` {
+		t.Errorf("event 0 = %+v", r.Events[0])
+	}
+	if string(r.Events[1].Data) != `"first line\nsecond line"` {
+		t.Errorf("event 1 data = %s", r.Events[1].Data)
+	}
+}
+
+func TestExecuteSSEStopsAtWindow(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("data: 1\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	svc := newService(srv.URL, 0, 0)
+	svc.window = 100 * time.Millisecond
+	result, err := svc.Execute(context.Background(), "events", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if r := decode(t, result.Data); r.EventCount != 1 || r.Ended != "window" {
+		t.Errorf("got %d events, ended %q", r.EventCount, r.Ended)
+	}
+}
+
+func TestExecuteSSEClosedWithoutEventsIsEmpty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	result, err := newService(srv.URL, 5, 0).Execute(context.Background(), "events", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !result.Empty || result.Error != "" {
+		t.Errorf("expected empty result, got empty=%v error=%q", result.Empty, result.Error)
+	}
+	if r := decode(t, result.Data); r.Ended != "closed" {
+		t.Errorf("ended = %q, want closed", r.Ended)
+	}
+}
+
+func TestExecuteHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	result, err := newService(srv.URL, 5, 0).Execute(context.Background(), "events", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !strings.HasPrefix(result.Error, "HTTP 403") {
+		t.Errorf("expected HTTP 403 error, got %q", result.Error)
+	}
+}
+
+func TestExecuteHTTPErrorBinaryBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte{0x89, 'P', 'N', 'G', 0, 0, 0xff})
+	}))
+	defer srv.Close()
+
+	result, err := newService(srv.URL, 5, 0).Execute(context.Background(), "events", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Error != "HTTP 502: (binary error body omitted)" {
+		t.Errorf("error = %q", result.Error)
+	}
+}
+
+func TestExecuteToolAuthOverridesService(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer events-token" {
+			t.Errorf("Authorization = %q", got)
+		}
+		if got := r.URL.Query().Get("api_key"); got != "" {
+			t.Errorf("service auth leaked: api_key=%q", got)
+		}
+	}))
+	defer srv.Close()
+
+	svc := NewStreamService(config.ServiceConfig{
+		Name:     "live",
+		Type:     "stream",
+		Endpoint: srv.URL,
+		Auth:     config.AuthConfig{Method: "api_key", Key: "service-key"},
+		Tools: []config.ToolConfig{{
+			Name: "events",
+			Auth: &config.AuthConfig{Method: "bearer", Token: "events-token"},
+		}},
+	}, nil, "")
+	if _, err := svc.Execute(context.Background(), "events", nil); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+}
+
+func TestExecuteSSEKeepsAcceptWhenMinimizing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept"); got != "text/event-stream" {
+			t.Errorf("Accept = %q, want text/event-stream", got)
+		}
+	}))
+	defer srv.Close()
+
+	svc := NewStreamService(config.ServiceConfig{Name: "live", Type: "stream", Endpoint: srv.URL},
+		&privacy.Config{MinimizeRequests: true}, "")
+	if _, err := svc.Execute(context.Background(), "events", nil); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+}
+
+func TestExecuteUnknownTool(t *testing.T) {
+	if _, err := newService("https://example.com", 0, 0).Execute(context.Background(), "feed", nil); err == nil {
+		t.Error("expected error for unknown tool")
+	}
+}
+
+// serverFrame encodes an unmasked server-to-client frame.
+func serverFrame(fin bool, op byte, payload []byte) []byte {
+	b0 := op
+	if fin {
+		b0 |= 0x80
+	}
+	frame := []byte{b0}
+	switch {
+	case len(payload) < 126:
+		frame = append(frame, byte(len(payload)))
+	default:
+		frame = append(frame, 126, 0, 0)
+		binary.BigEndian.PutUint16(frame[2:], uint16(len(payload)))
+	}
+	return append(frame, payload...)
+}
+
+// newWebsocketServer completes the handshake, writes frames, and records
+// the first frame the client sends back.
+func newWebsocketServer(t *testing.T, frames [][]byte, reply chan<- []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" {
+			t.Errorf("Upgrade = %q", r.Header.Get("Upgrade"))
+		}
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("hijack: %v", err)
+			return
+		}
+		defer conn.Close()
+		buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		buf.WriteString("Sec-WebSocket-Accept: " + acceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		for _, f := range frames {
+			buf.Write(f)
+		}
+		buf.Flush()
+
+		var hdr [2]byte
+		if _, err := buf.Read(hdr[:1]); err == nil {
+			buf.Read(hdr[1:])
+			if hdr[1]&0x80 == 0 {
+				t.Error("client frame not masked")
+			}
+			body := make([]byte, 4+hdr[1]&0x7f)
+			readFull(buf.Reader, body)
+			for i := range body[4:] {
+				body[4+i] ^= body[i%4]
+			}
+			reply <- append([]byte{hdr[0]}, body[4:]...)
+		}
+	}))
+}
+
+func readFull(r *bufio.Reader, b []byte) {
+	for n := 0; n < len(b); {
+		m, err := r.Read(b[n:])
+		if err != nil {
+			return
+		}
+		n += m
+	}
+}
+
+func TestExecuteWebsocket(t *testing.T) {
+	reply := make(chan []byte, 1)
+	srv := newWebsocketServer(t, [][]byte{
+		serverFrame(true, opText, []byte(`{"price": 101.5}`)),
+		serverFrame(false, opText, []byte("hello, ")),
+		serverFrame(true, opContinuation, []byte(strings.Repeat("x", 200))),
+		serverFrame(true, opPing, []byte("hb")),
+		serverFrame(true, opClose, nil),
+	}, reply)
+	defer srv.Close()
+
+	endpoint := "ws" + strings.TrimPrefix(srv.URL, "http")
+	result, err := newService(endpoint, 5, 0).Execute(context.Background(), "events", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Error != "" {
+		t.Fatalf("unexpected error: %s", result.Error)
+	}
+	r := decode(t, result.Data)
+	if r.EventCount != 2 || r.Ended != "closed" {
+		t.Fatalf("got %d events, ended %q", r.EventCount, r.Ended)
+	}
+	if string(r.Events[0].Data) != `{"price":101.5}` {
+		t.Errorf("message 0 = %s", r.Events[0].Data)
+	}
+	if want, _ := json.Marshal("hello, " + strings.Repeat("x", 200)); string(r.Events[1].Data) != string(want) {
+		t.Errorf("fragmented message = %s", r.Events[1].Data)
+	}
+
+	select {
+	case got := <-reply:
+		if got[0] != 0x80|opPong || string(got[1:]) != "hb" {
+			t.Errorf("expected pong echoing ping payload, got %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Error("no pong sent")
+	}
+}
+
+func TestExecuteWebsocketHandshakeRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	endpoint := "ws" + strings.TrimPrefix(srv.URL, "http")
+	result, err := newService(endpoint, 5, 0).Execute(context.Background(), "events", nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if !strings.HasPrefix(result.Error, "HTTP 400") {
+		t.Errorf("expected HTTP 400 error, got %q", result.Error)
+	}
+}
@@ -0,0 +1,239 @@
+package synthesis
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// anthropicVersion is the Messages API version sent with every request.
+const anthropicVersion = "2023-06-01"
+
+// defaultAnthropicMaxTokens is the reply limit used when max_tokens is not
+// configured. The Messages API requires one, unlike OpenAI-compatible APIs.
+const defaultAnthropicMaxTokens = 8192
+
+// AnthropicProvider implements Provider using Anthropic's Messages API.
+type AnthropicProvider struct {
+	endpoint  string
+	apiKey    string
+	model     string
+	genParams GenerationParams
+	client    *http.Client
+}
+
+// NewAnthropicProvider creates a provider for the Anthropic Messages API.
+// Default endpoint is https://api.anthropic.com if empty. A timeout of 0
+// uses the default (2 minutes).
+func NewAnthropicProvider(endpoint, apiKey, model string, timeoutSecs int) *AnthropicProvider {
+	if endpoint == "" {
+		endpoint = "https://api.anthropic.com"
+	}
+	endpoint = strings.TrimRight(endpoint, "/")
+	timeout := 2 * time.Minute
+	if timeoutSecs > 0 {
+		timeout = time.Duration(timeoutSecs) * time.Second
+	}
+	return &AnthropicProvider{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		model:    model,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{},
+		},
+	}
+}
+
+// SetGenerationParams configures optional generation parameters. The
+// Messages API has no sampling seed, so Seed is ignored.
+func (a *AnthropicProvider) SetGenerationParams(params GenerationParams) {
+	a.genParams = params
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature *float64           `json:"temperature,omitempty"`
+	TopP        *float64           `json:"top_p,omitempty"`
+	Stream      bool               `json:"stream,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResponse struct {
+	Content    []anthropicBlock `json:"content"`
+	StopReason string           `json:"stop_reason"`
+	Error      *anthropicError  `json:"error,omitempty"`
+	Usage      anthropicUsage   `json:"usage"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+type anthropicBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type anthropicError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// anthropicEvent is one server-sent event of a streamed reply. Only text
+// deltas, token counts, and errors matter here.
+type anthropicEvent struct {
+	Type  string          `json:"type"`
+	Delta anthropicBlock  `json:"delta"`
+	Error *anthropicError `json:"error,omitempty"`
+
+	// message_start carries the input token count; message_delta the
+	// output count so far.
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"`
+	Usage anthropicUsage `json:"usage"`
+}
+
+// Check confirms the API key sent in the Messages API's x-api-key header
+// is set.
+func (a *AnthropicProvider) Check(ctx context.Context) error {
+	return checkAPIKey(a.apiKey)
+}
+
+// Complete sends a Messages API request, with the system prompt in the
+// request's system field. Under WithTokens the reply is streamed to the
+// callback as it is generated.
+func (a *AnthropicProvider) Complete(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	tokens := tokensFrom(ctx)
+	maxTokens := a.genParams.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultAnthropicMaxTokens
+	}
+
+	reqBody := anthropicRequest{
+		Model:       a.model,
+		System:      systemPrompt,
+		Messages:    []anthropicMessage{{Role: "user", Content: userPrompt}},
+		MaxTokens:   maxTokens,
+		Temperature: a.genParams.Temperature,
+		TopP:        a.genParams.TopP,
+		Stream:      tokens != nil,
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", a.endpoint+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", a.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK && tokens != nil {
+		return readAnthropicStream(ctx, io.LimitReader(resp.Body, 10<<20), tokens)
+	}
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return "", fmt.Errorf("reading response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return "", fmt.Errorf("invalid API key")
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return "", fmt.Errorf("rate limited")
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errResp anthropicResponse
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error != nil {
+			return "", fmt.Errorf("API error (HTTP %d): %s", resp.StatusCode, errResp.Error.Message)
+		}
+		return "", fmt.Errorf("API error HTTP %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var result anthropicResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("parsing response: %w", err)
+	}
+
+	var reply strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			reply.WriteString(block.Text)
+		}
+	}
+	if reply.Len() == 0 {
+		return "", fmt.Errorf("no text in response (stop reason %q)", result.StopReason)
+	}
+	reportUsage(ctx, result.Usage.InputTokens, result.Usage.OutputTokens)
+	return reply.String(), nil
+}
+
+// readAnthropicStream reads a streamed Messages API reply sent as
+// server-sent events, passing each text delta to tokens and returning the
+// whole reply. Token counts are reported to ctx's UsageFunc at message_stop.
+func readAnthropicStream(ctx context.Context, r io.Reader, tokens TokenFunc) (string, error) {
+	var reply strings.Builder
+	var usage anthropicUsage
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue // "event:" lines and blank separators
+		}
+		var event anthropicEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			return "", fmt.Errorf("parsing streamed response: %w", err)
+		}
+		switch event.Type {
+		case "error":
+			if event.Error != nil {
+				return "", fmt.Errorf("API error: %s", event.Error.Message)
+			}
+			return "", fmt.Errorf("API error in streamed response")
+		case "content_block_delta":
+			if event.Delta.Type == "text_delta" && event.Delta.Text != "" {
+				tokens(event.Delta.Text)
+				reply.WriteString(event.Delta.Text)
+			}
+		case "message_start":
+			usage.InputTokens = event.Message.Usage.InputTokens
+		case "message_delta":
+			usage.OutputTokens = event.Usage.OutputTokens
+		case "message_stop":
+			reportUsage(ctx, usage.InputTokens, usage.OutputTokens)
+			return reply.String(), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("reading streamed response: %w", err)
+	}
+	return reply.String(), nil
+}
@@ -0,0 +1,115 @@
+package synthesis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAnthropicSuccess(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("expected /v1/messages, got %s", r.URL.Path)
+		}
+		if got := r.Header.Get("x-api-key"); got != "test-key" {
+			t.Errorf("x-api-key = %q", got)
+		}
+		if got := r.Header.Get("anthropic-version"); got != anthropicVersion {
+			t.Errorf("anthropic-version = %q", got)
+		}
+		var req anthropicRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.System != "Be brief." {
+			t.Errorf("system = %q", req.System)
+		}
+		if len(req.Messages) != 1 || req.Messages[0].Role != "user" || req.Messages[0].Content != "Generate report." {
+			t.Errorf("messages = %+v", req.Messages)
+		}
+		if req.MaxTokens != defaultAnthropicMaxTokens {
+			t.Errorf("max_tokens = %d, want the default %d", req.MaxTokens, defaultAnthropicMaxTokens)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"content": [{"type": "text", "text": "# Report\n"}, {"type": "text", "text": "Done."}], "stop_reason": "end_turn"}`))
+	}))
+	defer srv.Close()
+
+	p := NewAnthropicProvider(srv.URL, "test-key", "claude-sonnet-4-5", 0)
+	result, err := p.Complete(context.Background(), "Be brief.", "Generate report.")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if result != "# Report\nDone." {
+		t.Errorf("unexpected result: %q", result)
+	}
+}
+
+func TestAnthropicGenerationParams(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["max_tokens"] != float64(1000) || req["temperature"] != 0.2 {
+			t.Errorf("request = %v", req)
+		}
+		if _, ok := req["system"]; ok {
+			t.Error("empty system prompt sent")
+		}
+		w.Write([]byte(`{"content": [{"type": "text", "text": "ok"}]}`))
+	}))
+	defer srv.Close()
+
+	temp := 0.2
+	p := NewAnthropicProvider(srv.URL, "key", "model", 0)
+	p.SetGenerationParams(GenerationParams{Temperature: &temp, MaxTokens: 1000})
+	if _, err := p.Complete(context.Background(), "", "x"); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+}
+
+func TestAnthropicStreaming(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req anthropicRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			t.Error("expected a streaming request")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("event: message_start\ndata: {\"type\": \"message_start\"}\n\n" +
+			"event: ping\ndata: {\"type\": \"ping\"}\n\n" +
+			"event: content_block_delta\ndata: {\"type\": \"content_block_delta\", \"delta\": {\"type\": \"text_delta\", \"text\": \"# Rep\"}}\n\n" +
+			"event: content_block_delta\ndata: {\"type\": \"content_block_delta\", \"delta\": {\"type\": \"text_delta\", \"text\": \"ort\"}}\n\n" +
+			"event: message_stop\ndata: {\"type\": \"message_stop\"}\n\n"))
+	}))
+	defer srv.Close()
+
+	var streamed strings.Builder
+	ctx := WithTokens(context.Background(), func(text string) { streamed.WriteString(text) })
+	p := NewAnthropicProvider(srv.URL, "key", "model", 0)
+	result, err := p.Complete(ctx, "", "Generate report.")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if result != "# Report" || streamed.String() != "# Report" {
+		t.Errorf("result %q, streamed %q", result, streamed.String())
+	}
+}
+
+func TestAnthropicErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type": "error", "error": {"type": "invalid_request_error", "message": "max_tokens: too large"}}`))
+	}))
+	defer srv.Close()
+
+	p := NewAnthropicProvider(srv.URL, "key", "model", 0)
+	_, err := p.Complete(context.Background(), "", "x")
+	if err == nil || !strings.Contains(err.Error(), "max_tokens: too large") {
+		t.Errorf("expected API error message, got %v", err)
+	}
+
+	if err := NewAnthropicProvider("", "${ANTHROPIC_KEY}", "model", 0).Check(context.Background()); err == nil {
+		t.Error("Check accepted an unresolved api_key")
+	}
+}
@@ -0,0 +1,38 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrCallBudgetExceeded is returned by a call-limited provider once its
+// budget is spent.
+var ErrCallBudgetExceeded = errors.New("LLM call budget exceeded")
+
+// LimitCalls wraps p so that at most max Complete calls reach it. Later calls
+// fail with ErrCallBudgetExceeded without contacting the provider. The count
+// is shared by concurrent callers, so it bounds multi-stage synthesis too.
+func LimitCalls(p Provider, max int) Provider {
+	return &limitedProvider{inner: p, max: int64(max)}
+}
+
+type limitedProvider struct {
+	inner Provider
+	max   int64
+	used  atomic.Int64
+}
+
+// Check forwards to the wrapped provider's Check, if any; it doesn't count
+// against the budget.
+func (l *limitedProvider) Check(ctx context.Context) error {
+	return checkProvider(ctx, l.inner)
+}
+
+func (l *limitedProvider) Complete(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	if l.used.Add(1) > l.max {
+		return "", fmt.Errorf("%w (max %d)", ErrCallBudgetExceeded, l.max)
+	}
+	return l.inner.Complete(ctx, systemPrompt, userPrompt)
+}
@@ -0,0 +1,54 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+type countingProvider struct {
+	calls atomic.Int32
+}
+
+func (c *countingProvider) Complete(_ context.Context, _, _ string) (string, error) {
+	c.calls.Add(1)
+	return "# ok", nil
+}
+
+func TestLimitCalls(t *testing.T) {
+	inner := &countingProvider{}
+	p := LimitCalls(inner, 3)
+
+	var wg sync.WaitGroup
+	var failed atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := p.Complete(context.Background(), "", ""); err != nil {
+				if !errors.Is(err, ErrCallBudgetExceeded) {
+					t.Errorf("unexpected error: %v", err)
+				}
+				failed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := inner.calls.Load(); got != 3 {
+		t.Errorf("inner calls = %d, want 3", got)
+	}
+	if got := failed.Load(); got != 7 {
+		t.Errorf("failed calls = %d, want 7", got)
+	}
+}
+
+func TestLimitCallsFailsSynthesis(t *testing.T) {
+	synth := NewLLMSynthesizer(LimitCalls(&countingProvider{}, 0), false)
+	_, err := synth.Synthesize(context.Background(), "Report", "", nil)
+	if !errors.Is(err, ErrCallBudgetExceeded) {
+		t.Errorf("err = %v, want ErrCallBudgetExceeded", err)
+	}
+}
@@ -81,19 +81,7 @@ func splitJSONObjectWithArray(data string, maxWords int) []string {
 		return nil
 	}
 
-	// Find the largest array field
-	var largestKey string
-	var largestArray []json.RawMessage
-	for key, val := range obj {
-		var arr []json.RawMessage
-		if err := json.Unmarshal(val, &arr); err != nil {
-			continue
-		}
-		if len(arr) > len(largestArray) {
-			largestKey = key
-			largestArray = arr
-		}
-	}
+	largestKey, largestArray := largestArrayField(obj)
 
 	if len(largestArray) <= 1 {
 		return nil
@@ -137,6 +125,98 @@ func splitJSONObjectWithArray(data string, maxWords int) []string {
 	return chunks
 }
 
+// truncateJSON shortens a JSON array, or the largest array field of a JSON
+// object, by dropping elements from the end until the data fits in about
+// maxWords words. Other fields and the kept elements stay whole, so the
+// result is valid JSON. It returns how many of the array's elements were
+// kept, and false when data isn't such JSON, not even one element fits, or
+// dropping elements wouldn't help.
+func truncateJSON(data string, maxWords int) (truncated string, kept, total int, ok bool) {
+	trimmed := strings.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return "", 0, 0, false
+	}
+
+	switch trimmed[0] {
+	case '[':
+		var elements []json.RawMessage
+		if err := json.Unmarshal([]byte(trimmed), &elements); err != nil {
+			return "", 0, 0, false
+		}
+		kept = keepRecords(elements, maxWords)
+		if kept == 0 || kept == len(elements) {
+			return "", 0, 0, false
+		}
+		return "[" + joinRaw(elements[:kept]) + "]", kept, len(elements), true
+
+	case '{':
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(trimmed), &obj); err != nil {
+			return "", 0, 0, false
+		}
+		key, elements := largestArrayField(obj)
+		if len(elements) == 0 {
+			return "", 0, 0, false
+		}
+		// The other fields come along whole; the array gets what's left.
+		rest := 0
+		for k, v := range obj {
+			if k != key {
+				rest += countWords(string(v)) + 1
+			}
+		}
+		kept = keepRecords(elements, maxWords-rest)
+		if kept == 0 || kept == len(elements) {
+			return "", 0, 0, false
+		}
+		obj[key] = json.RawMessage("[" + joinRaw(elements[:kept]) + "]")
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return "", 0, 0, false
+		}
+		return string(b), kept, len(elements), true
+	}
+	return "", 0, 0, false
+}
+
+// keepRecords returns how many leading elements fit in maxWords words.
+func keepRecords(elements []json.RawMessage, maxWords int) int {
+	words := 0
+	for i, el := range elements {
+		words += countWords(string(el))
+		if words > maxWords {
+			return i
+		}
+	}
+	return len(elements)
+}
+
+func joinRaw(elements []json.RawMessage) string {
+	parts := make([]string, len(elements))
+	for i, el := range elements {
+		parts[i] = string(el)
+	}
+	return strings.Join(parts, ",")
+}
+
+// largestArrayField returns the key and elements of obj's largest
+// array-valued field, or an empty key when it has none.
+func largestArrayField(obj map[string]json.RawMessage) (string, []json.RawMessage) {
+	var largestKey string
+	var largestArray []json.RawMessage
+	for key, val := range obj {
+		var arr []json.RawMessage
+		if err := json.Unmarshal(val, &arr); err != nil {
+			continue
+		}
+		if len(arr) > len(largestArray) {
+			largestKey = key
+			largestArray = arr
+		}
+	}
+	return largestKey, largestArray
+}
+
 // splitLineBased splits on double-newline paragraph boundaries.
 // Returns nil if the data has no paragraph breaks.
 func splitLineBased(data string, maxWords int) []string {
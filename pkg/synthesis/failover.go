@@ -0,0 +1,108 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+// FallbackFunc is told when a provider in a failover chain fails and the
+// next one is about to be tried.
+type FallbackFunc func(failed string, err error, next string)
+
+type fallbackKey struct{}
+
+// WithFallback returns a context that reports failovers to fn. A nil fn
+// reports nothing.
+func WithFallback(ctx context.Context, fn FallbackFunc) context.Context {
+	return context.WithValue(ctx, fallbackKey{}, fn)
+}
+
+func fallbackFrom(ctx context.Context) FallbackFunc {
+	fn, _ := ctx.Value(fallbackKey{}).(FallbackFunc)
+	return fn
+}
+
+// FailoverSynthesizer tries a chain of synthesizers in order, each built
+// for one provider, and returns the first report that succeeds. A
+// cancelled run or an exhausted call budget stops the chain rather than
+// moving on.
+type FailoverSynthesizer struct {
+	names []string
+	chain []Synthesizer
+}
+
+// NewFailoverSynthesizer creates a failover chain. names[i] is the provider
+// behind chain[i], used when reporting a failover.
+func NewFailoverSynthesizer(names []string, chain []Synthesizer) *FailoverSynthesizer {
+	return &FailoverSynthesizer{names: names, chain: chain}
+}
+
+// Synthesize runs each synthesizer in turn until one succeeds. When all
+// fail, the error names every provider tried.
+func (f *FailoverSynthesizer) Synthesize(ctx context.Context, title string, systemPrompt string, results []*services.Result) (string, error) {
+	var errs []error
+	for i, s := range f.chain {
+		markdown, err := s.Synthesize(ctx, title, systemPrompt, results)
+		if err == nil {
+			return markdown, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", f.names[i], err))
+		if i+1 < len(f.chain) && !f.failover(ctx, i, err) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("all LLM providers failed: %w", errors.Join(errs...))
+}
+
+// Summarize condenses a report with the first synthesizer in the chain that
+// can and succeeds. Failovers here aren't reported; the TL;DR is a small
+// addition to a report that already records its own.
+func (f *FailoverSynthesizer) Summarize(ctx context.Context, report string) (string, error) {
+	err := errors.New("no LLM provider can summarize")
+	for _, s := range f.chain {
+		sum, ok := s.(Summarizer)
+		if !ok {
+			continue
+		}
+		var text string
+		if text, err = sum.Summarize(ctx, report); err == nil || ctx.Err() != nil {
+			return text, err
+		}
+	}
+	return "", err
+}
+
+// Check passes when any provider in the chain is ready, since the chain can
+// still produce a report; otherwise it returns the primary's error.
+func (f *FailoverSynthesizer) Check(ctx context.Context) error {
+	var first error
+	for _, s := range f.chain {
+		c, ok := s.(Checker)
+		if !ok {
+			return nil
+		}
+		err := c.Check(ctx)
+		if err == nil {
+			return nil
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// failover reports whether the chain should move on from synthesizer i to
+// the next after err, telling the context's FallbackFunc when it does.
+func (f *FailoverSynthesizer) failover(ctx context.Context, i int, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrCallBudgetExceeded) {
+		return false
+	}
+	if fn := fallbackFrom(ctx); fn != nil {
+		fn(f.names[i], err, f.names[i+1])
+	}
+	return true
+}
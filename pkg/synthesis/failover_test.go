@@ -0,0 +1,93 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+type stubSynthesizer struct {
+	report string
+	err    error
+	calls  int
+}
+
+func (s *stubSynthesizer) Synthesize(_ context.Context, _, _ string, _ []*services.Result) (string, error) {
+	s.calls++
+	return s.report, s.err
+}
+
+func TestFailoverSynthesizerFallsBack(t *testing.T) {
+	primary := &stubSynthesizer{err: errors.New("connection refused")}
+	backup := &stubSynthesizer{report: "# From backup"}
+	f := NewFailoverSynthesizer([]string{"local/llama3", "cloud/gpt"}, []Synthesizer{primary, backup})
+
+	var failed, next string
+	ctx := WithFallback(context.Background(), func(f string, err error, n string) {
+		failed, next = f, n
+	})
+	got, err := f.Synthesize(ctx, "Report", "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "# From backup" {
+		t.Errorf("report = %q, want the backup's", got)
+	}
+	if failed != "local/llama3" || next != "cloud/gpt" {
+		t.Errorf("fallback reported %q -> %q", failed, next)
+	}
+}
+
+func TestFailoverSynthesizerPrimarySucceeds(t *testing.T) {
+	primary := &stubSynthesizer{report: "# Primary"}
+	backup := &stubSynthesizer{report: "# Backup"}
+	f := NewFailoverSynthesizer([]string{"a", "b"}, []Synthesizer{primary, backup})
+
+	ctx := WithFallback(context.Background(), func(string, error, string) {
+		t.Error("no fallback expected")
+	})
+	if got, err := f.Synthesize(ctx, "Report", "", nil); err != nil || got != "# Primary" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if backup.calls != 0 {
+		t.Errorf("backup called %d times, want 0", backup.calls)
+	}
+}
+
+func TestFailoverSynthesizerAllFail(t *testing.T) {
+	f := NewFailoverSynthesizer([]string{"a", "b"}, []Synthesizer{
+		&stubSynthesizer{err: errors.New("timeout")},
+		&stubSynthesizer{err: errors.New("401 unauthorized")},
+	})
+	_, err := f.Synthesize(context.Background(), "Report", "", nil)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"a: timeout", "b: 401 unauthorized"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
+
+func TestFailoverSynthesizerStops(t *testing.T) {
+	budget := &stubSynthesizer{err: ErrCallBudgetExceeded}
+	backup := &stubSynthesizer{report: "# Backup"}
+	f := NewFailoverSynthesizer([]string{"a", "b"}, []Synthesizer{budget, backup})
+	if _, err := f.Synthesize(context.Background(), "Report", "", nil); !errors.Is(err, ErrCallBudgetExceeded) {
+		t.Errorf("err = %v, want ErrCallBudgetExceeded", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f = NewFailoverSynthesizer([]string{"a", "b"}, []Synthesizer{&stubSynthesizer{err: context.Canceled}, backup})
+	if _, err := f.Synthesize(ctx, "Report", "", nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if backup.calls != 0 {
+		t.Errorf("backup called %d times, want 0", backup.calls)
+	}
+}
@@ -0,0 +1,166 @@
+package synthesis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+// defaultMaxHeadlines caps a headlines digest so it fits on one screen.
+const defaultMaxHeadlines = 20
+
+// HeadlinesInstruction asks an LLM for a headlines-only digest in place of a
+// full report (report style: headlines).
+const HeadlinesInstruction = "Report style: headlines only. Produce just a bulleted list of the most important items " +
+	"across all sources — at most 20 — most important first. Each bullet is one line: the item's headline as a " +
+	"markdown link to its exact URL from the data, then \" — \" and the source's label. No prose, no analysis, " +
+	"no section headings or summaries; the only heading is the report title."
+
+// Headline is one item in a headlines digest.
+type Headline struct {
+	Title  string
+	URL    string
+	Source string
+}
+
+// HeadlinesSynthesizer builds a headlines-only digest without an LLM: a
+// bulleted list of linked items pulled from each source's JSON, taken in
+// turn from each source so no single feed crowds out the rest.
+type HeadlinesSynthesizer struct {
+	max int
+}
+
+// NewHeadlinesSynthesizer creates a deterministic headlines synthesizer.
+func NewHeadlinesSynthesizer() *HeadlinesSynthesizer {
+	return &HeadlinesSynthesizer{max: defaultMaxHeadlines}
+}
+
+// Synthesize renders the digest. Sources that failed, returned nothing, or
+// had no linked items are listed by name after the headlines.
+func (h *HeadlinesSynthesizer) Synthesize(_ context.Context, title string, _ string, results []*services.Result) (string, error) {
+	var b strings.Builder
+	b.WriteString("# " + title + "\n\n")
+
+	perSource := make([][]Headline, len(results))
+	var failed, none []string
+	for i, r := range results {
+		label := resultLabel(r)
+		switch {
+		case r.Error != "":
+			failed = append(failed, label)
+		case r.Empty:
+			none = append(none, label)
+		default:
+			perSource[i] = ExtractHeadlines(r.Data, label)
+			if len(perSource[i]) == 0 {
+				none = append(none, label)
+			}
+		}
+	}
+
+	headlines := interleave(perSource, h.max)
+	if len(headlines) == 0 {
+		b.WriteString("No headlines found.\n")
+	}
+	for _, hl := range headlines {
+		fmt.Fprintf(&b, "- [%s](%s) — %s\n", hl.Title, hl.URL, hl.Source)
+	}
+
+	if len(none) > 0 {
+		fmt.Fprintf(&b, "\n*No headlines from: %s*\n", strings.Join(none, ", "))
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(&b, "\n*Failed: %s*\n", strings.Join(failed, ", "))
+	}
+	return b.String(), nil
+}
+
+func resultLabel(r *services.Result) string {
+	if r.ContextLabel != "" {
+		return r.ContextLabel
+	}
+	return r.Service + " — " + r.Tool
+}
+
+// interleave takes items round-robin from each source, in source order,
+// until max are taken or every source is exhausted.
+func interleave(perSource [][]Headline, max int) []Headline {
+	var out []Headline
+	for depth := 0; len(out) < max; depth++ {
+		took := false
+		for _, items := range perSource {
+			if depth < len(items) && len(out) < max {
+				out = append(out, items[depth])
+				took = true
+			}
+		}
+		if !took {
+			break
+		}
+	}
+	return out
+}
+
+// Keys recognized as an item's headline and link, in order of preference.
+var (
+	titleKeys = []string{"title", "headline", "name"}
+	linkKeys  = []string{"link", "url", "href"}
+)
+
+// ExtractHeadlines returns the linked items in a JSON document, in document
+// order: every object with a headline field (title, headline, or name) and
+// an http(s) link field (link, url, or href). Objects within a matched item
+// are not searched. Non-JSON data yields none.
+func ExtractHeadlines(data []byte, source string) []Headline {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil
+	}
+	var out []Headline
+	collectHeadlines(v, source, &out)
+	return out
+}
+
+func collectHeadlines(v any, source string, out *[]Headline) {
+	switch v := v.(type) {
+	case []any:
+		for _, elem := range v {
+			collectHeadlines(elem, source, out)
+		}
+	case map[string]any:
+		title, link := firstString(v, titleKeys), firstString(v, linkKeys)
+		if title != "" && (strings.HasPrefix(link, "https://") || strings.HasPrefix(link, "http://")) {
+			*out = append(*out, Headline{Title: oneLine(title), URL: link, Source: source})
+			return
+		}
+		// Map order is random; sort keys so the digest is stable.
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectHeadlines(v[k], source, out)
+		}
+	}
+}
+
+func firstString(obj map[string]any, keys []string) string {
+	for _, k := range keys {
+		if s, ok := obj[k].(string); ok && strings.TrimSpace(s) != "" {
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
+
+// oneLine collapses whitespace and escapes brackets so a title stays a
+// single, well-formed markdown link label.
+func oneLine(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(s)
+}
@@ -0,0 +1,68 @@
+package synthesis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+func TestExtractHeadlines(t *testing.T) {
+	data := []byte(`{
+		"feed": {"title": "HN", "link": "https://news.ycombinator.com"},
+		"items": [
+			{"title": "First [draft]", "link": "https://example.com/1", "author": {"name": "a", "url": "https://example.com/a"}},
+			{"title": "No link"},
+			{"headline": "  Second\n  story ", "url": "http://example.com/2"},
+			{"name": "Relative", "href": "/3"}
+		]
+	}`)
+	got := ExtractHeadlines(data, "HN")
+	want := []Headline{
+		{Title: "HN", URL: "https://news.ycombinator.com", Source: "HN"},
+		{Title: `First \[draft\]`, URL: "https://example.com/1", Source: "HN"},
+		{Title: "Second story", URL: "http://example.com/2", Source: "HN"},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ExtractHeadlines =\n%v\nwant\n%v", got, want)
+	}
+	if got := ExtractHeadlines([]byte("<rss/>"), "x"); got != nil {
+		t.Errorf("non-JSON data should yield no headlines, got %v", got)
+	}
+}
+
+func TestHeadlinesSynthesizerInterleavesSources(t *testing.T) {
+	items := func(prefix string, n int) []byte {
+		var parts []string
+		for i := 1; i <= n; i++ {
+			parts = append(parts, fmt.Sprintf(`{"title": "%s %d", "link": "https://%s.example/%d"}`, prefix, i, prefix, i))
+		}
+		return []byte("[" + strings.Join(parts, ",") + "]")
+	}
+	results := []*services.Result{
+		{Service: "a", Tool: "t", ContextLabel: "Alpha", Data: items("a", 30)},
+		{Service: "b", Tool: "t", Data: items("b", 2)},
+		{Service: "c", Tool: "t", ContextLabel: "Down", Error: "HTTP 500"},
+		{Service: "d", Tool: "t", ContextLabel: "Quiet", Data: []byte(`{"count": 0}`)},
+	}
+
+	md, err := NewHeadlinesSynthesizer().Synthesize(context.Background(), "Morning Glance", "", results)
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	lines := strings.Split(md, "\n")
+	if lines[0] != "# Morning Glance" {
+		t.Errorf("title line = %q", lines[0])
+	}
+	if lines[2] != "- [a 1](https://a.example/1) — Alpha" || lines[3] != "- [b 1](https://b.example/1) — b — t" {
+		t.Errorf("expected sources interleaved, got:\n%s", md)
+	}
+	if n := strings.Count(md, "\n- ["); n != defaultMaxHeadlines {
+		t.Errorf("expected %d headlines, got %d", defaultMaxHeadlines, n)
+	}
+	if !strings.Contains(md, "*No headlines from: Quiet*") || !strings.Contains(md, "*Failed: Down*") {
+		t.Errorf("expected notes for quiet and failed sources:\n%s", md)
+	}
+}
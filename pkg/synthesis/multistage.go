@@ -3,6 +3,7 @@ package synthesis
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -11,18 +12,20 @@ import (
 
 // MultiStageConfig controls when and how multi-stage synthesis is used.
 type MultiStageConfig struct {
-	Strategy        string // auto | single | multi-stage
-	SummaryMaxWords int    // target words per stage 1 summary (default: 500)
-	ThresholdBytes  int    // auto-trigger threshold (default: 16384)
-	MaxSourceWords  int    // max words per source before chunking (default: 10000)
-	Concurrency     int    // max concurrent stage 1 LLM calls (default: 1)
-	ContextWindow   int    // model context window in tokens; used to derive MaxSourceWords when 0
+	Strategy         string // auto | single | multi-stage
+	SummaryMaxWords  int    // target words per stage 1 summary (default: 500)
+	ThresholdBytes   int    // auto-trigger threshold (default: 16384)
+	MaxSourceWords   int    // max words per source before chunking (default: 10000)
+	Concurrency      int    // max concurrent stage 1 LLM calls (default: 1)
+	ContextWindow    int    // model context window in tokens; used to derive MaxSourceWords when 0
+	TruncationMarker string // marks where fallback raw data was cut (default: "[... truncated ...]")
 }
 
 const (
 	defaultSummaryMaxWords  = 500
 	defaultThresholdBytes   = 16384
 	defaultMaxSourceWords   = 10000
+	defaultTruncationMarker = "[... truncated ...]"
 )
 
 // stage1SystemPrompt is the system prompt for per-source summarization calls.
@@ -86,6 +89,14 @@ func (c MultiStageConfig) maxSourceWords() int {
 	return defaultMaxSourceWords
 }
 
+// truncationMarker returns the configured truncation marker or the default.
+func (c MultiStageConfig) truncationMarker() string {
+	if c.TruncationMarker != "" {
+		return c.TruncationMarker
+	}
+	return defaultTruncationMarker
+}
+
 // concurrency returns the configured stage 1 concurrency or the default (1).
 func (c MultiStageConfig) concurrency() int {
 	if c.Concurrency > 0 {
@@ -114,6 +125,7 @@ func (l *LLMSynthesizer) shouldMultiStage(results []*services.Result) bool {
 // sourceSummary holds the result of a stage 1 summarization call.
 type sourceSummary struct {
 	label   string
+	tags    []string
 	summary string
 	err     error
 }
@@ -147,18 +159,30 @@ func (l *LLMSynthesizer) summarizeChunk(ctx context.Context, label, data, priori
 	return sourceSummary{label: label, summary: truncateSummary(summary, l.multiStage.summaryMaxWords()*2)}
 }
 
-// summarizeSource summarizes one source, chunking if the data exceeds maxSourceWords.
-func (l *LLMSynthesizer) summarizeSource(ctx context.Context, idx int, r *services.Result, priorities string) sourceSummary {
+// summarizeSource summarizes one source, chunking if the data exceeds
+// maxSourceWords. Stripped names the results whose service names are hidden;
+// as in single-stage synthesis, they are removed from every source, so a
+// public source that mentions a private one doesn't reveal it.
+func (l *LLMSynthesizer) summarizeSource(ctx context.Context, idx int, r *services.Result, stripped []*services.Result, priorities string) sourceSummary {
 	label := r.Service + " — " + r.Tool
 	if r.ContextLabel != "" {
 		label = r.ContextLabel
 	}
-	if l.stripAttribution {
+	if l.strips(r) {
 		label = fmt.Sprintf("Source %d", idx+1)
+	} else if l.stripAttribution {
+		label = stripServiceNames(label, stripped)
 	}
 
 	if r.Error != "" {
-		return sourceSummary{label: label, summary: "Error: " + r.Error}
+		errMsg := r.Error
+		if l.stripAttribution {
+			errMsg = stripServiceNames(errMsg, stripped)
+		}
+		return sourceSummary{label: label, summary: "Error: " + errMsg}
+	}
+	if r.Empty {
+		return sourceSummary{label: label, summary: noResultsNote}
 	}
 	if len(r.Data) == 0 {
 		return sourceSummary{label: label, summary: "(no data)"}
@@ -168,8 +192,9 @@ func (l *LLMSynthesizer) summarizeSource(ctx context.Context, idx int, r *servic
 	if l.preprocess {
 		data = PreprocessData(data)
 	}
+	data = formatNote(r.Note) + formatHeaders(r.Headers) + data
 	if l.stripAttribution {
-		data = stripServiceNames(data, []*services.Result{r})
+		data = stripServiceNames(data, stripped)
 	}
 
 	// If data fits within maxSourceWords, single LLM call
@@ -194,22 +219,40 @@ func (l *LLMSynthesizer) summarizeSource(ctx context.Context, idx int, r *servic
 	return sourceSummary{label: label, summary: truncateSummary(merged, l.multiStage.summaryMaxWords()*2)}
 }
 
-// runStage1 executes all stage 1 calls concurrently, bounded by a semaphore.
-// Concurrency defaults to 1 (sequential) to avoid overwhelming local LLM servers.
+// runStage1 executes all stage 1 calls on a pool of workers that take
+// sources in order, so sequential runs summarize them in prompt order.
+// Concurrency defaults to 1 (sequential) to avoid overwhelming local LLM
+// servers. Progress goes to the context's ProgressFunc as each finishes.
 func (l *LLMSynthesizer) runStage1(ctx context.Context, results []*services.Result, priorities string) []sourceSummary {
 	summaries := make([]sourceSummary, len(results))
+	stripped := l.strippedResults(results)
+	progress := progressFrom(ctx)
+	var mu sync.Mutex
+	done := 0
 	var wg sync.WaitGroup
-	sem := make(chan struct{}, l.multiStage.concurrency())
 
-	for i, r := range results {
+	next := make(chan int)
+	workers := min(l.multiStage.concurrency(), len(results))
+	for w := 0; w < workers; w++ {
 		wg.Add(1)
-		go func(idx int, r *services.Result) {
+		go func() {
 			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
-			summaries[idx] = l.summarizeSource(ctx, idx, r, priorities)
-		}(i, r)
+			for idx := range next {
+				r := results[idx]
+				summaries[idx] = l.summarizeSource(ctx, idx, r, stripped, priorities)
+				summaries[idx].tags = r.Tags
+
+				mu.Lock()
+				done++
+				progress(done, len(results))
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range results {
+		next <- i
 	}
+	close(next)
 
 	wg.Wait()
 	return summaries
@@ -219,22 +262,30 @@ func (l *LLMSynthesizer) runStage1(ctx context.Context, results []*services.Resu
 func (l *LLMSynthesizer) synthesizeMultiStage(ctx context.Context, title string, systemPrompt string, results []*services.Result) (string, error) {
 	priorities := extractPriorities(systemPrompt)
 
-	// Stage 1: per-source summarization (parallel)
-	summaries := l.runStage1(ctx, results, priorities)
+	// Stage 1: per-source summarization (parallel). Only the report streams.
+	summaries := l.runStage1(WithTokens(ctx, nil), results, priorities)
 
 	// For failed summaries, fall back to truncated raw data
+	stripped := l.strippedResults(results)
 	for i, s := range summaries {
 		if s.err != nil && i < len(results) {
-			raw := truncateRawFallback(string(results[i].Data), l.multiStage.summaryMaxWords()*3)
+			data := string(results[i].Data)
+			if l.stripAttribution {
+				data = stripServiceNames(data, stripped)
+			}
+			raw := truncateRawFallback(data, l.multiStage.summaryMaxWords()*3, l.multiStage.truncationMarker())
 			summaries[i] = sourceSummary{
 				label:   s.label,
+				tags:    s.tags,
 				summary: raw,
 			}
 		}
 	}
 
-	// Bound summaries so stage 2 prompt fits within context window.
+	// Bound summaries so stage 2 prompt fits within context window, then
+	// drop the least relevant sources if truncation alone wasn't enough.
 	summaries = l.boundStage2Summaries(summaries)
+	summaries, omitted := l.capStage2Summaries(summaries, systemPrompt)
 
 	// Stage 2: assembly
 	userPrompt := l.assembleStage2Prompt(title, summaries)
@@ -250,11 +301,21 @@ func (l *LLMSynthesizer) synthesizeMultiStage(ctx context.Context, title string,
 		fullSystem += staticDocumentInstruction
 	}
 
-	result, err := l.provider.Complete(ctx, fullSystem, userPrompt)
+	report, err := l.completeReport(ctx, title, fullSystem, userPrompt)
 	if err != nil {
 		return "", err
 	}
-	return postProcess(result), nil
+	return report + omittedSourcesNote(results, omitted), nil
+}
+
+// stage2BudgetBytes returns the bytes of summary text the stage 2 prompt may
+// hold: 60% of the context window at ~4 bytes/token, leaving the rest for
+// the system prompt and output. Zero means unbounded.
+func (c MultiStageConfig) stage2BudgetBytes() int {
+	if c.ContextWindow <= 0 {
+		return 0
+	}
+	return int(float64(c.ContextWindow) * 0.6 * 4)
 }
 
 // boundStage2Summaries truncates summaries so the stage 2 prompt fits within
@@ -263,17 +324,12 @@ func (l *LLMSynthesizer) synthesizeMultiStage(ctx context.Context, title string,
 // When total summary text exceeds the budget, each summary is proportionally
 // truncated to fit.
 func (l *LLMSynthesizer) boundStage2Summaries(summaries []sourceSummary) []sourceSummary {
-	if l.multiStage.ContextWindow <= 0 {
+	budgetBytes := l.multiStage.stage2BudgetBytes()
+	if budgetBytes == 0 {
 		return summaries
 	}
 
-	// 60% of context for summaries (rest for system prompt + generation)
-	budgetBytes := int(float64(l.multiStage.ContextWindow) * 0.6 * 4)
-
-	totalBytes := 0
-	for _, s := range summaries {
-		totalBytes += len(s.summary)
-	}
+	totalBytes := summaryBytes(summaries)
 
 	if totalBytes <= budgetBytes {
 		return summaries
@@ -289,12 +345,99 @@ func (l *LLMSynthesizer) boundStage2Summaries(summaries []sourceSummary) []sourc
 		}
 		bounded[i] = sourceSummary{
 			label:   s.label,
+			tags:    s.tags,
 			summary: truncateSummary(s.summary, maxWords),
 		}
 	}
 	return bounded
 }
 
+// capStage2Summaries drops whole sources when summaries still exceed the
+// stage 2 budget after truncation, which happens when there are too many
+// sources for each to keep even a minimal summary. Sources that failed or
+// returned nothing go first, then those with the least keyword overlap with
+// the system prompt; ties drop later sources first. At least one source is
+// always kept. It returns the kept summaries in their original order and
+// the indices of those omitted, ascending.
+func (l *LLMSynthesizer) capStage2Summaries(summaries []sourceSummary, systemPrompt string) ([]sourceSummary, []int) {
+	budgetBytes := l.multiStage.stage2BudgetBytes()
+	totalBytes := summaryBytes(summaries)
+	if budgetBytes == 0 || totalBytes <= budgetBytes {
+		return summaries, nil
+	}
+
+	keywords := priorityKeywords(systemPrompt)
+	scores := make([]int, len(summaries))
+	for i, s := range summaries {
+		switch {
+		case s.summary == noResultsNote || s.summary == "(no data)" || strings.HasPrefix(s.summary, "Error: "):
+			scores[i] = -1
+		default:
+			scores[i] = textOverlap(keywords, s.label+" "+s.summary)
+		}
+	}
+
+	// Least relevant first; among equals, the later source first.
+	order := make([]int, len(summaries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		if scores[order[a]] != scores[order[b]] {
+			return scores[order[a]] < scores[order[b]]
+		}
+		return order[a] > order[b]
+	})
+
+	dropped := make(map[int]bool)
+	for _, idx := range order {
+		if totalBytes <= budgetBytes || len(dropped) == len(summaries)-1 {
+			break
+		}
+		dropped[idx] = true
+		totalBytes -= len(summaries[idx].summary)
+	}
+
+	var kept []sourceSummary
+	var omitted []int
+	for i, s := range summaries {
+		if dropped[i] {
+			omitted = append(omitted, i)
+		} else {
+			kept = append(kept, s)
+		}
+	}
+	return kept, omitted
+}
+
+func summaryBytes(summaries []sourceSummary) int {
+	total := 0
+	for _, s := range summaries {
+		total += len(s.summary)
+	}
+	return total
+}
+
+// omittedSourcesNote renders the report section listing sources dropped for
+// space, by their routine labels, or "" when none were. It is appended after
+// synthesis, so the labels never reach the LLM.
+func omittedSourcesNote(results []*services.Result, omitted []int) string {
+	if len(omitted) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\n## Omitted Sources\n\n")
+	b.WriteString("These sources did not fit in the model's context window and are not reflected in this report:\n\n")
+	for _, i := range omitted {
+		label := results[i].Service + " — " + results[i].Tool
+		if results[i].ContextLabel != "" {
+			label = results[i].ContextLabel
+		}
+		b.WriteString("- " + label + "\n")
+	}
+	return b.String()
+}
+
 // assembleStage2Prompt builds the user prompt for the final assembly call.
 func (l *LLMSynthesizer) assembleStage2Prompt(title string, summaries []sourceSummary) string {
 	var b strings.Builder
@@ -306,10 +449,20 @@ func (l *LLMSynthesizer) assembleStage2Prompt(title string, summaries []sourceSu
 		b.WriteString("### ")
 		b.WriteString(s.label)
 		b.WriteString("\n")
+		b.WriteString(formatTags(s.tags))
 		b.WriteString(s.summary)
 		b.WriteString("\n\n")
 	}
 
+	for _, s := range summaries {
+		if len(s.tags) > 0 {
+			b.WriteString("\n---\n")
+			b.WriteString(tagsInstruction)
+			b.WriteString("\n")
+			break
+		}
+	}
+
 	if l.localModel {
 		b.WriteString(localInstructions)
 	} else {
@@ -352,11 +505,16 @@ func truncateSummary(text string, maxWords int) string {
 	return strings.Join(words[:maxWords], " ") + "..."
 }
 
-// truncateRawFallback truncates raw data for use as a fallback when stage 1 fails.
-func truncateRawFallback(data string, maxWords int) string {
+// truncateRawFallback truncates raw data for use as a fallback when stage 1
+// fails, appending marker where it was cut. JSON keeps whole array elements
+// (see truncateJSON); anything else is cut by word count.
+func truncateRawFallback(data string, maxWords int, marker string) string {
 	words := strings.Fields(data)
 	if len(words) <= maxWords {
 		return data
 	}
-	return strings.Join(words[:maxWords], " ") + "\n[... truncated ...]"
+	if truncated, kept, total, ok := truncateJSON(data, maxWords); ok {
+		return fmt.Sprintf("%s\n%s (%d of %d items omitted)", truncated, marker, total-kept, total)
+	}
+	return strings.Join(words[:maxWords], " ") + "\n" + marker
 }
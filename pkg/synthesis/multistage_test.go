@@ -2,7 +2,9 @@ package synthesis
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	gosync "sync"
 	"sync/atomic"
@@ -129,7 +131,7 @@ func TestTruncateSummaryLong(t *testing.T) {
 
 func TestTruncateRawFallbackShort(t *testing.T) {
 	input := "short data"
-	got := truncateRawFallback(input, 100)
+	got := truncateRawFallback(input, 100, defaultTruncationMarker)
 	if got != input {
 		t.Errorf("expected unchanged, got %q", got)
 	}
@@ -137,7 +139,7 @@ func TestTruncateRawFallbackShort(t *testing.T) {
 
 func TestTruncateRawFallbackLong(t *testing.T) {
 	input := "one two three four five six"
-	got := truncateRawFallback(input, 3)
+	got := truncateRawFallback(input, 3, defaultTruncationMarker)
 	if !strings.HasPrefix(got, "one two three") {
 		t.Errorf("expected truncated prefix, got %q", got)
 	}
@@ -146,6 +148,51 @@ func TestTruncateRawFallbackLong(t *testing.T) {
 	}
 }
 
+func TestTruncateRawFallbackJSONArray(t *testing.T) {
+	input := `[{"title": "one two"}, {"title": "three four"}, {"title": "five six"}]`
+	got := truncateRawFallback(input, 5, "[cut]")
+
+	body, marker, _ := strings.Cut(got, "\n")
+	var kept []map[string]string
+	if err := json.Unmarshal([]byte(body), &kept); err != nil {
+		t.Fatalf("truncated JSON should stay valid: %v\n%s", err, got)
+	}
+	if len(kept) != 1 || kept[0]["title"] != "one two" {
+		t.Errorf("expected the first whole element, got %v", kept)
+	}
+	if marker != "[cut] (2 of 3 items omitted)" {
+		t.Errorf("marker = %q", marker)
+	}
+}
+
+func TestTruncateRawFallbackJSONObject(t *testing.T) {
+	input := `{"total": 3, "results": [{"title": "one two"}, {"title": "three four"}, {"title": "five six"}]}`
+	got := truncateRawFallback(input, 8, defaultTruncationMarker)
+
+	body, marker, _ := strings.Cut(got, "\n")
+	var kept struct {
+		Total   int                 `json:"total"`
+		Results []map[string]string `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(body), &kept); err != nil {
+		t.Fatalf("truncated JSON should stay valid: %v\n%s", err, got)
+	}
+	if kept.Total != 3 || len(kept.Results) != 2 {
+		t.Errorf("expected total kept and two results, got %+v", kept)
+	}
+	if marker != "[... truncated ...] (1 of 3 items omitted)" {
+		t.Errorf("marker = %q", marker)
+	}
+}
+
+func TestTruncateRawFallbackJSONElementTooLarge(t *testing.T) {
+	input := `[{"text": "one two three four five six"}, {"text": "seven"}]`
+	got := truncateRawFallback(input, 3, defaultTruncationMarker)
+	if !strings.HasPrefix(got, `[{"text": "one two`) || !strings.HasSuffix(got, "\n[... truncated ...]") {
+		t.Errorf("expected word-based fallback when no element fits, got %q", got)
+	}
+}
+
 // --- recordingProvider captures all LLM calls ---
 
 type recordingProvider struct {
@@ -313,6 +360,79 @@ func TestMultiStageRespectsCustomConcurrency(t *testing.T) {
 	}
 }
 
+func TestMultiStageStage1InOrderWithProgress(t *testing.T) {
+	provider := &recordingProvider{response: "Summarized."}
+	synth := NewLLMSynthesizer(provider, false)
+	synth.SetMultiStage(MultiStageConfig{Strategy: "multi-stage"})
+
+	results := make([]*services.Result, 6)
+	for i := range results {
+		results[i] = &services.Result{Service: "svc", Tool: fmt.Sprintf("tool-%d", i), Data: []byte("data")}
+	}
+
+	var progress []string
+	ctx := WithProgress(context.Background(), func(done, total int) {
+		progress = append(progress, fmt.Sprintf("%d/%d", done, total))
+	})
+	if _, err := synth.Synthesize(ctx, "Report", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+
+	if got := strings.Join(progress, " "); got != "1/6 2/6 3/6 4/6 5/6 6/6" {
+		t.Errorf("progress = %q", got)
+	}
+	// Sequential stage 1 summarizes sources in prompt order.
+	calls := provider.getCalls()
+	for i := range results {
+		if !strings.Contains(calls[i].user, fmt.Sprintf("tool-%d", i)) {
+			t.Fatalf("stage 1 call %d is not source %d:\n%s", i, i, calls[i].user)
+		}
+	}
+
+	// No callback is fine too.
+	if _, err := synth.Synthesize(WithProgress(context.Background(), nil), "Report", "", results); err != nil {
+		t.Fatalf("Synthesize with nil progress: %v", err)
+	}
+}
+
+// streamCheckProvider records, per call, whether the report would stream.
+type streamCheckProvider struct {
+	mu        gosync.Mutex
+	streaming []bool
+}
+
+func (p *streamCheckProvider) Complete(ctx context.Context, system, _ string) (string, error) {
+	p.mu.Lock()
+	p.streaming = append(p.streaming, tokensFrom(ctx) != nil)
+	p.mu.Unlock()
+	if fn := tokensFrom(ctx); fn != nil {
+		fn("# Streamed")
+	}
+	return "# Streamed\n\nBody.", nil
+}
+
+func TestMultiStageStreamsOnlyTheReport(t *testing.T) {
+	provider := &streamCheckProvider{}
+	synth := NewLLMSynthesizer(provider, false)
+	synth.SetMultiStage(MultiStageConfig{Strategy: "multi-stage"})
+
+	results := []*services.Result{
+		{Service: "a", Tool: "x", Data: []byte("data")},
+		{Service: "b", Tool: "x", Data: []byte("data")},
+	}
+	var streamed strings.Builder
+	ctx := WithTokens(context.Background(), func(text string) { streamed.WriteString(text) })
+	if _, err := synth.Synthesize(ctx, "Report", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if want := []bool{false, false, true}; fmt.Sprint(provider.streaming) != fmt.Sprint(want) {
+		t.Errorf("streaming per call = %v, want %v", provider.streaming, want)
+	}
+	if streamed.String() != "# Streamed" {
+		t.Errorf("streamed = %q", streamed.String())
+	}
+}
+
 // --- Error fallback tests ---
 
 // selectiveFailProvider fails on the first stage 1 call only.
@@ -424,6 +544,80 @@ func TestMultiStageStripsAttribution(t *testing.T) {
 	}
 }
 
+func TestMultiStageStripsPrivateServicesOnly(t *testing.T) {
+	provider := &recordingProvider{response: "Summarized data."}
+	synth := NewLLMSynthesizer(provider, true)
+	synth.SetPrivateServices([]string{"sam-gov"})
+	synth.SetMultiStage(MultiStageConfig{Strategy: "multi-stage"})
+
+	results := []*services.Result{
+		{Service: "nws", Tool: "forecast", Data: []byte(`data from nws`), ContextLabel: "NWS Forecast"},
+		{Service: "sam-gov", Tool: "search", Data: []byte(`data from sam-gov`)},
+	}
+
+	if _, err := synth.Synthesize(context.Background(), "Brief", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+
+	calls := provider.getCalls()
+	for i, c := range calls {
+		if strings.Contains(c.user, "sam-gov") {
+			t.Errorf("call %d: attribution leak — sam-gov appears in prompt", i)
+		}
+	}
+	stage2 := calls[len(calls)-1].user
+	if !strings.Contains(stage2, "### NWS Forecast") || !strings.Contains(stage2, "### Source 2") {
+		t.Errorf("expected public label kept and private label stripped:\n%s", stage2)
+	}
+}
+
+func TestMultiStageStripsPrivateNamesFromPublicSources(t *testing.T) {
+	provider := &recordingProvider{response: "Summarized data."}
+	synth := NewLLMSynthesizer(provider, true)
+	synth.SetPrivateServices([]string{"sam-gov"})
+	synth.SetMultiStage(MultiStageConfig{Strategy: "multi-stage"})
+
+	results := []*services.Result{
+		{Service: "nws", Tool: "forecast", Data: []byte(`nws data citing sam-gov`), ContextLabel: "NWS vs sam-gov"},
+		{Service: "edgar", Tool: "search", Error: "sam-gov mirror unreachable"},
+		{Service: "sam-gov", Tool: "search", Data: []byte(`data from sam-gov`)},
+	}
+
+	if _, err := synth.Synthesize(context.Background(), "Brief", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+
+	calls := provider.getCalls()
+	for i, c := range calls {
+		if strings.Contains(c.user, "sam-gov") {
+			t.Errorf("call %d: attribution leak — sam-gov appears in prompt:\n%s", i, c.user)
+		}
+	}
+	if stage2 := calls[len(calls)-1].user; !strings.Contains(stage2, "### NWS vs [service]") || !strings.Contains(stage2, "### edgar — search") {
+		t.Errorf("expected public labels kept with only the private name stripped:\n%s", stage2)
+	}
+}
+
+func TestMultiStageStripsFallbackRawData(t *testing.T) {
+	provider := &recordingProvider{response: "Report.", failStage1: true}
+	synth := NewLLMSynthesizer(provider, true)
+	synth.SetPrivateServices([]string{"sam-gov"})
+	synth.SetMultiStage(MultiStageConfig{Strategy: "multi-stage"})
+
+	results := []*services.Result{
+		{Service: "nws", Tool: "forecast", Data: []byte(`nws data citing sam-gov`)},
+		{Service: "sam-gov", Tool: "search", Data: []byte(`data from sam-gov`)},
+	}
+
+	if _, err := synth.Synthesize(context.Background(), "Brief", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	calls := provider.getCalls()
+	if stage2 := calls[len(calls)-1].user; strings.Contains(stage2, "sam-gov") || !strings.Contains(stage2, "[service]") {
+		t.Errorf("attribution leak in the raw fallback:\n%s", stage2)
+	}
+}
+
 // --- End-to-end test ---
 
 // e2eProvider returns different responses for stage 1 vs stage 2.
@@ -499,6 +693,23 @@ func TestAssembleStage2Prompt(t *testing.T) {
 	}
 }
 
+func TestAssembleStage2PromptIncludesTags(t *testing.T) {
+	synth := NewLLMSynthesizer(&fakeProvider{}, false)
+
+	summaries := []sourceSummary{
+		{label: "Alerts", tags: []string{"critical"}, summary: "Storm warning."},
+		{label: "News", summary: "Quiet day."},
+	}
+
+	prompt := synth.assembleStage2Prompt("Test Report", summaries)
+	if !strings.Contains(prompt, "### Alerts\nTags: critical\nStorm warning.") {
+		t.Errorf("expected tags under the summary heading, got:\n%s", prompt)
+	}
+	if strings.Count(prompt, tagsInstruction) != 1 {
+		t.Errorf("expected the tags instruction once, got:\n%s", prompt)
+	}
+}
+
 // --- summarizeSource with ContextLabel ---
 
 func TestSummarizeSourceUsesContextLabel(t *testing.T) {
@@ -513,7 +724,7 @@ func TestSummarizeSourceUsesContextLabel(t *testing.T) {
 		ContextLabel: "NWS 7-Day Forecast — Anchorage",
 	}
 
-	s := synth.summarizeSource(context.Background(), 0, r, "priorities")
+	s := synth.summarizeSource(context.Background(), 0, r, nil, "priorities")
 	if s.label != "NWS 7-Day Forecast — Anchorage" {
 		t.Errorf("expected context label, got %q", s.label)
 	}
@@ -530,7 +741,7 @@ func TestSummarizeSourceFallbackLabel(t *testing.T) {
 		Data:    []byte("forecast data"),
 	}
 
-	s := synth.summarizeSource(context.Background(), 0, r, "")
+	s := synth.summarizeSource(context.Background(), 0, r, nil, "")
 	if s.label != "nws — forecast" {
 		t.Errorf("expected service-tool label, got %q", s.label)
 	}
@@ -546,7 +757,7 @@ func TestSummarizeSourceError(t *testing.T) {
 		Error:   "connection refused",
 	}
 
-	s := synth.summarizeSource(context.Background(), 0, r, "")
+	s := synth.summarizeSource(context.Background(), 0, r, nil, "")
 	if !strings.Contains(s.summary, "connection refused") {
 		t.Error("expected error message in summary")
 	}
@@ -561,7 +772,7 @@ func TestSummarizeSourceEmptyData(t *testing.T) {
 		Tool:    "t",
 	}
 
-	s := synth.summarizeSource(context.Background(), 0, r, "")
+	s := synth.summarizeSource(context.Background(), 0, r, nil, "")
 	if s.summary != "(no data)" {
 		t.Errorf("expected (no data), got %q", s.summary)
 	}
@@ -746,6 +957,95 @@ func TestBoundStage2SummariesNoContextWindow(t *testing.T) {
 	}
 }
 
+func TestCapStage2SummariesDropsLeastRelevant(t *testing.T) {
+	synth := NewLLMSynthesizer(&fakeProvider{}, false)
+	// 100 tokens → budget = 240 bytes
+	synth.SetMultiStage(MultiStageConfig{ContextWindow: 100})
+
+	pad := strings.Repeat("x", 110)
+	summaries := []sourceSummary{
+		{label: "A", summary: "earthquake swarm near the ridge " + pad},
+		{label: "B", summary: "local bake sale results " + pad},
+		{label: "C", summary: "Error: connection timed out " + pad},
+	}
+
+	kept, omitted := synth.capStage2Summaries(summaries, "Track earthquake activity.")
+	if len(kept) != 1 || kept[0].label != "A" {
+		t.Fatalf("expected only the relevant source kept, got %+v", kept)
+	}
+	if len(omitted) != 2 || omitted[0] != 1 || omitted[1] != 2 {
+		t.Errorf("omitted = %v, want [1 2]", omitted)
+	}
+}
+
+func TestCapStage2SummariesWithinBudget(t *testing.T) {
+	synth := NewLLMSynthesizer(&fakeProvider{}, false)
+	synth.SetMultiStage(MultiStageConfig{ContextWindow: 100})
+
+	summaries := []sourceSummary{
+		{label: "A", summary: "Short."},
+		{label: "B", summary: "Also short."},
+	}
+	kept, omitted := synth.capStage2Summaries(summaries, "")
+	if len(kept) != 2 || omitted != nil {
+		t.Errorf("expected nothing dropped, got kept=%d omitted=%v", len(kept), omitted)
+	}
+}
+
+func TestCapStage2SummariesKeepsOne(t *testing.T) {
+	synth := NewLLMSynthesizer(&fakeProvider{}, false)
+	synth.SetMultiStage(MultiStageConfig{ContextWindow: 10})
+
+	long := strings.Repeat("word ", 100)
+	summaries := []sourceSummary{
+		{label: "A", summary: long},
+		{label: "B", summary: long},
+	}
+	kept, omitted := synth.capStage2Summaries(summaries, "")
+	if len(kept) != 1 || kept[0].label != "A" {
+		t.Errorf("expected the first source kept on a tie, got %+v", kept)
+	}
+	if len(omitted) != 1 || omitted[0] != 1 {
+		t.Errorf("omitted = %v, want [1]", omitted)
+	}
+}
+
+func TestMultiStageNotesOmittedSources(t *testing.T) {
+	provider := &recordingProvider{}
+	synth := NewLLMSynthesizer(provider, true)
+	// 20 tokens → budget = 48 bytes: room for one stage 1 summary
+	synth.SetMultiStage(MultiStageConfig{Strategy: "multi-stage", ContextWindow: 20})
+
+	results := []*services.Result{
+		{Service: "usgs", Tool: "quakes", Data: []byte("a"), ContextLabel: "Quakes"},
+		{Service: "nws", Tool: "alerts", Data: []byte("b"), ContextLabel: "Alerts"},
+		{Service: "news", Tool: "search", Data: []byte("c")},
+	}
+
+	report, err := synth.Synthesize(context.Background(), "Brief", "", results)
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if !strings.Contains(report, "## Omitted Sources") {
+		t.Fatalf("expected omitted sources note, got %q", report)
+	}
+	if !strings.Contains(report, "- Alerts\n") || !strings.Contains(report, "- news — search\n") {
+		t.Errorf("expected dropped sources listed by label, got %q", report)
+	}
+	if strings.Contains(report, "- Quakes") {
+		t.Error("kept source listed as omitted")
+	}
+
+	calls := provider.getCalls()
+	stage2 := calls[len(calls)-1].user
+	if strings.Contains(stage2, "### Source 2") || strings.Contains(stage2, "### Source 3") {
+		t.Errorf("dropped sources reached the stage 2 prompt: %q", stage2)
+	}
+	if !strings.Contains(stage2, "### Source 1") {
+		t.Error("kept source missing from stage 2 prompt")
+	}
+}
+
 // --- Chunked summarization test ---
 
 func TestSummarizeSourceChunksLargeData(t *testing.T) {
@@ -770,7 +1070,7 @@ func TestSummarizeSourceChunksLargeData(t *testing.T) {
 		Data:    []byte(bigData),
 	}
 
-	s := synth.summarizeSource(context.Background(), 0, r, "priorities")
+	s := synth.summarizeSource(context.Background(), 0, r, nil, "priorities")
 	if s.err != nil {
 		t.Fatalf("summarizeSource: %v", s.err)
 	}
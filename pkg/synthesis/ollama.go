@@ -68,6 +68,12 @@ type ollamaMessage struct {
 
 type ollamaResponse struct {
 	Message ollamaMessage `json:"message"`
+	Done    bool          `json:"done"`
+	Error   string        `json:"error,omitempty"`
+
+	// Token counts, sent with the final response.
+	PromptEvalCount int `json:"prompt_eval_count,omitempty"`
+	EvalCount       int `json:"eval_count,omitempty"`
 }
 
 // Model returns the model name configured for this provider.
@@ -75,8 +81,47 @@ func (o *OllamaProvider) Model() string {
 	return o.model
 }
 
-// Complete sends a chat completion request to Ollama.
+// checkTimeout bounds a provider readiness check.
+const checkTimeout = 5 * time.Second
+
+// Check confirms Ollama is reachable and has the configured model pulled.
+func (o *OllamaProvider) Check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.endpoint+"/api/tags", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("Ollama is not reachable at %s (is ollama serve running?)", o.endpoint)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Ollama at %s returned HTTP %d", o.endpoint, resp.StatusCode)
+	}
+
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&tags); err != nil {
+		// An unexpected listing doesn't prove the model is missing.
+		return nil
+	}
+	for _, m := range tags.Models {
+		if m.Name == o.model || m.Name == o.model+":latest" {
+			return nil
+		}
+	}
+	return fmt.Errorf("model %q is not available in Ollama at %s (run ollama pull %s)", o.model, o.endpoint, o.model)
+}
+
+// Complete sends a chat completion request to Ollama. Under WithTokens the
+// reply is streamed to the callback as it is generated.
 func (o *OllamaProvider) Complete(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	tokens := tokensFrom(ctx)
 	messages := []ollamaMessage{
 		{Role: "user", Content: userPrompt},
 	}
@@ -87,7 +132,7 @@ func (o *OllamaProvider) Complete(ctx context.Context, systemPrompt, userPrompt
 	ollamaReq := ollamaRequest{
 		Model:    o.model,
 		Messages: messages,
-		Stream:   false,
+		Stream:   tokens != nil,
 	}
 
 	// Build options map with context window and generation params.
@@ -104,6 +149,9 @@ func (o *OllamaProvider) Complete(ctx context.Context, systemPrompt, userPrompt
 	if o.genParams.MaxTokens > 0 {
 		opts["num_predict"] = o.genParams.MaxTokens
 	}
+	if o.genParams.Seed != nil {
+		opts["seed"] = *o.genParams.Seed
+	}
 	if len(opts) > 0 {
 		ollamaReq.Options = opts
 	}
@@ -125,6 +173,10 @@ func (o *OllamaProvider) Complete(ctx context.Context, systemPrompt, userPrompt
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusOK && tokens != nil {
+		return readOllamaStream(ctx, io.LimitReader(resp.Body, 10<<20), tokens)
+	}
+
 	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
 	if err != nil {
 		return "", fmt.Errorf("reading response: %w", err)
@@ -142,5 +194,34 @@ func (o *OllamaProvider) Complete(ctx context.Context, systemPrompt, userPrompt
 		return "", fmt.Errorf("parsing response: %w", err)
 	}
 
+	reportUsage(ctx, result.PromptEvalCount, result.EvalCount)
 	return result.Message.Content, nil
 }
+
+// readOllamaStream reads a streamed chat reply, one JSON object per line,
+// passing each piece of content to tokens and returning the whole reply.
+// The final object's token counts are reported to ctx's UsageFunc.
+func readOllamaStream(ctx context.Context, r io.Reader, tokens TokenFunc) (string, error) {
+	var reply strings.Builder
+	dec := json.NewDecoder(r)
+	for {
+		var chunk ollamaResponse
+		if err := dec.Decode(&chunk); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", fmt.Errorf("reading streamed response: %w", err)
+		}
+		if chunk.Error != "" {
+			return "", fmt.Errorf("Ollama: %s", chunk.Error)
+		}
+		if chunk.Message.Content != "" {
+			tokens(chunk.Message.Content)
+			reply.WriteString(chunk.Message.Content)
+		}
+		if chunk.Done {
+			reportUsage(ctx, chunk.PromptEvalCount, chunk.EvalCount)
+			break
+		}
+	}
+	return reply.String(), nil
+}
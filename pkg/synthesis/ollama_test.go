@@ -33,6 +33,35 @@ func TestOllamaSuccess(t *testing.T) {
 	}
 }
 
+func TestOllamaStreaming(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			t.Error("expected a streaming request")
+		}
+		w.Write([]byte(`{"message": {"content": "# Rep"}, "done": false}
+{"message": {"content": "ort\nBody."}, "done": false}
+{"message": {"content": ""}, "done": true}
+`))
+	}))
+	defer srv.Close()
+
+	var streamed []string
+	ctx := WithTokens(context.Background(), func(text string) { streamed = append(streamed, text) })
+	p := NewOllamaProvider(srv.URL, "qwen2.5:14b")
+	result, err := p.Complete(ctx, "", "Generate report.")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if result != "# Report\nBody." {
+		t.Errorf("unexpected result: %q", result)
+	}
+	if strings.Join(streamed, "|") != "# Rep|ort\nBody." {
+		t.Errorf("streamed = %q", streamed)
+	}
+}
+
 func TestOllamaModelNotFound(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
@@ -61,6 +90,32 @@ func TestOllamaConnectionError(t *testing.T) {
 	}
 }
 
+func TestOllamaCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			t.Errorf("expected /api/tags, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"models": [{"name": "qwen2.5:14b"}, {"name": "llama3:latest"}]}`))
+	}))
+	defer srv.Close()
+
+	for _, model := range []string{"qwen2.5:14b", "llama3"} {
+		if err := NewOllamaProvider(srv.URL, model).Check(context.Background()); err != nil {
+			t.Errorf("Check(%s): %v", model, err)
+		}
+	}
+
+	err := NewOllamaProvider(srv.URL, "mistral").Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "ollama pull mistral") {
+		t.Errorf("expected pull guidance for a missing model, got %v", err)
+	}
+
+	err = NewOllamaProvider("http://127.0.0.1:1", "qwen2.5:14b").Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "not reachable") {
+		t.Errorf("expected unreachable error, got %v", err)
+	}
+}
+
 func TestOllamaContextCancellation(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Never respond — let the context cancel
@@ -90,11 +145,13 @@ func TestOllamaGenerationParams(t *testing.T) {
 
 	temp := 0.3
 	topP := 0.9
+	seed := 7
 	p := NewOllamaProviderWithTimeout(srv.URL, "test-model", 0, 32768)
 	p.SetGenerationParams(GenerationParams{
 		Temperature: &temp,
 		TopP:        &topP,
 		MaxTokens:   4096,
+		Seed:        &seed,
 	})
 
 	_, err := p.Complete(context.Background(), "system", "user")
@@ -117,6 +174,9 @@ func TestOllamaGenerationParams(t *testing.T) {
 	if v, ok := capturedBody.Options["num_predict"]; !ok || int(v.(float64)) != 4096 {
 		t.Errorf("expected num_predict=4096, got %v", v)
 	}
+	if v, ok := capturedBody.Options["seed"]; !ok || int(v.(float64)) != 7 {
+		t.Errorf("expected seed=7, got %v", v)
+	}
 }
 
 func TestOllamaNoGenerationParams(t *testing.T) {
@@ -1,6 +1,7 @@
 package synthesis
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -18,6 +19,8 @@ type OpenRouterProvider struct {
 	model     string
 	genParams GenerationParams
 	client    *http.Client
+
+	optionalKey bool // generic OpenAI-compatible servers may take no key
 }
 
 // NewOpenRouterProvider creates a provider for OpenRouter or any OpenAI-compatible endpoint.
@@ -48,6 +51,16 @@ func NewOpenRouterProviderWithTimeout(endpoint, apiKey, model string, timeoutSec
 	}
 }
 
+// NewOpenAIProvider creates a provider for a generic OpenAI-compatible
+// server such as vLLM, LM Studio, or Groq. Unlike OpenRouter there is no
+// default endpoint, and the API key may be empty for local servers that
+// take none. A timeout of 0 uses the default (2 minutes).
+func NewOpenAIProvider(endpoint, apiKey, model string, timeoutSecs int) *OpenRouterProvider {
+	p := NewOpenRouterProviderWithTimeout(endpoint, apiKey, model, timeoutSecs)
+	p.optionalKey = true
+	return p
+}
+
 // SetGenerationParams configures optional generation parameters.
 func (o *OpenRouterProvider) SetGenerationParams(params GenerationParams) {
 	o.genParams = params
@@ -60,6 +73,7 @@ type openAIRequest struct {
 	Temperature *float64        `json:"temperature,omitempty"`
 	TopP        *float64        `json:"top_p,omitempty"`
 	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Seed        *int            `json:"seed,omitempty"`
 }
 
 type openAIMessage struct {
@@ -70,18 +84,32 @@ type openAIMessage struct {
 type openAIResponse struct {
 	Choices []openAIChoice `json:"choices"`
 	Error   *openAIError   `json:"error,omitempty"`
+	Usage   *openAIUsage   `json:"usage,omitempty"` // in a streamed reply, only on the last chunk, if at all
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
 }
 
 type openAIChoice struct {
 	Message openAIMessage `json:"message"`
+	Delta   openAIMessage `json:"delta"` // streamed responses
 }
 
 type openAIError struct {
 	Message string `json:"message"`
 }
 
+// Check confirms the API key sent as the Authorization bearer token is set.
+func (o *OpenRouterProvider) Check(ctx context.Context) error {
+	return checkAPIKey(o.apiKey)
+}
+
 // Complete sends a chat completion request using the OpenAI-compatible API.
+// Under WithTokens the reply is streamed to the callback as it is generated.
 func (o *OpenRouterProvider) Complete(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	tokens := tokensFrom(ctx)
 	messages := []openAIMessage{
 		{Role: "user", Content: userPrompt},
 	}
@@ -95,6 +123,8 @@ func (o *OpenRouterProvider) Complete(ctx context.Context, systemPrompt, userPro
 		Temperature: o.genParams.Temperature,
 		TopP:        o.genParams.TopP,
 		MaxTokens:   o.genParams.MaxTokens,
+		Seed:        o.genParams.Seed,
+		Stream:      tokens != nil,
 	}
 
 	body, err := json.Marshal(reqBody)
@@ -107,7 +137,9 @@ func (o *OpenRouterProvider) Complete(ctx context.Context, systemPrompt, userPro
 		return "", fmt.Errorf("creating request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	if o.apiKey != "" || !o.optionalKey {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
 
 	resp, err := o.client.Do(req)
 	if err != nil {
@@ -115,6 +147,10 @@ func (o *OpenRouterProvider) Complete(ctx context.Context, systemPrompt, userPro
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusOK && tokens != nil {
+		return readOpenAIStream(ctx, io.LimitReader(resp.Body, 10<<20), tokens)
+	}
+
 	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
 	if err != nil {
 		return "", fmt.Errorf("reading response: %w", err)
@@ -144,5 +180,45 @@ func (o *OpenRouterProvider) Complete(ctx context.Context, systemPrompt, userPro
 		return "", fmt.Errorf("no choices in response")
 	}
 
+	if result.Usage != nil {
+		reportUsage(ctx, result.Usage.PromptTokens, result.Usage.CompletionTokens)
+	}
 	return result.Choices[0].Message.Content, nil
 }
+
+// readOpenAIStream reads a streamed chat reply sent as server-sent events,
+// passing each content delta to tokens and returning the whole reply.
+// Token counts, when the server sends them, are reported to ctx's UsageFunc.
+func readOpenAIStream(ctx context.Context, r io.Reader, tokens TokenFunc) (string, error) {
+	var reply strings.Builder
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue // blank separators and ": keep-alive" comments
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+		var chunk openAIResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", fmt.Errorf("parsing streamed response: %w", err)
+		}
+		if chunk.Error != nil {
+			return "", fmt.Errorf("API error: %s", chunk.Error.Message)
+		}
+		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			tokens(chunk.Choices[0].Delta.Content)
+			reply.WriteString(chunk.Choices[0].Delta.Content)
+		}
+		if chunk.Usage != nil {
+			reportUsage(ctx, chunk.Usage.PromptTokens, chunk.Usage.CompletionTokens)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("reading streamed response: %w", err)
+	}
+	return reply.String(), nil
+}
@@ -34,6 +34,33 @@ func TestOpenRouterSuccess(t *testing.T) {
 	}
 }
 
+func TestOpenRouterStreaming(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			t.Error("expected a streaming request")
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(": OPENROUTER PROCESSING\n\n" +
+			"data: {\"choices\": [{\"delta\": {\"content\": \"# Rep\"}}]}\n\n" +
+			"data: {\"choices\": [{\"delta\": {\"content\": \"ort\"}}]}\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	var streamed strings.Builder
+	ctx := WithTokens(context.Background(), func(text string) { streamed.WriteString(text) })
+	p := NewOpenRouterProvider(srv.URL, "key", "model")
+	result, err := p.Complete(ctx, "", "Generate report.")
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if result != "# Report" || streamed.String() != "# Report" {
+		t.Errorf("result %q, streamed %q", result, streamed.String())
+	}
+}
+
 func TestOpenRouterAuthFailure(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -51,6 +78,16 @@ func TestOpenRouterAuthFailure(t *testing.T) {
 	}
 }
 
+func TestOpenRouterCheck(t *testing.T) {
+	if err := NewOpenRouterProvider("http://unused", "sk-key", "model").Check(context.Background()); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+	err := NewOpenRouterProvider("http://unused", "${OPENROUTER_KEY}", "model").Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "${OPENROUTER_KEY} is unresolved") {
+		t.Errorf("expected unresolved key error, got %v", err)
+	}
+}
+
 func TestOpenRouterRateLimit(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusTooManyRequests)
@@ -97,11 +134,13 @@ func TestOpenRouterGenerationParams(t *testing.T) {
 
 	temp := 0.3
 	topP := 0.9
+	seed := 7
 	p := NewOpenRouterProviderWithTimeout(srv.URL, "key", "model", 0)
 	p.SetGenerationParams(GenerationParams{
 		Temperature: &temp,
 		TopP:        &topP,
 		MaxTokens:   4096,
+		Seed:        &seed,
 	})
 
 	_, err := p.Complete(context.Background(), "system", "user")
@@ -118,6 +157,9 @@ func TestOpenRouterGenerationParams(t *testing.T) {
 	if v, ok := capturedBody["max_tokens"]; !ok || int(v.(float64)) != 4096 {
 		t.Errorf("expected max_tokens=4096, got %v", v)
 	}
+	if v, ok := capturedBody["seed"]; !ok || int(v.(float64)) != 7 {
+		t.Errorf("expected seed=7, got %v", v)
+	}
 }
 
 func TestOpenRouterNoGenerationParams(t *testing.T) {
@@ -147,3 +189,24 @@ func TestOpenRouterNoGenerationParams(t *testing.T) {
 		t.Error("expected max_tokens absent when not set")
 	}
 }
+
+func TestOpenAIProviderWithoutKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Authorization sent without a key: %q", auth)
+		}
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "ok"}}]}`))
+	}))
+	defer srv.Close()
+
+	p := NewOpenAIProvider(srv.URL+"/v1/", "", "qwen2.5-7b-instruct", 0)
+	if err := p.Check(context.Background()); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+	if result, err := p.Complete(context.Background(), "", "x"); err != nil || result != "ok" {
+		t.Errorf("Complete = %q, %v", result, err)
+	}
+}
@@ -0,0 +1,97 @@
+package synthesis
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+// Source orders for prompt construction. They control where each source's
+// data sits in the LLM prompt, not the order of the report's sections.
+const (
+	SourceOrderRoutine   = "routine"   // as declared in the routine (default)
+	SourceOrderRelevance = "relevance" // most keyword overlap with the system prompt first
+	SourceOrderSize      = "size"      // most data first
+)
+
+// stopwords are common words ignored when matching priorities.
+var stopwords = map[string]bool{
+	"about": true, "after": true, "also": true, "and": true, "any": true, "are": true,
+	"been": true, "before": true, "being": true, "but": true, "each": true, "focus": true,
+	"for": true, "from": true, "have": true, "include": true, "into": true, "more": true,
+	"most": true, "only": true, "other": true, "over": true, "report": true, "should": true,
+	"some": true, "such": true, "than": true, "that": true, "the": true, "their": true,
+	"them": true, "then": true, "there": true, "these": true, "they": true, "this": true,
+	"those": true, "what": true, "when": true, "where": true, "which": true, "while": true,
+	"will": true, "with": true, "would": true, "your": true,
+}
+
+// orderSources returns results reordered for the prompt. Sources with an
+// error or no results go last in every non-routine order; ties keep
+// routine order.
+func orderSources(results []*services.Result, order, systemPrompt string) []*services.Result {
+	var score func(r *services.Result) int
+	switch order {
+	case SourceOrderRelevance:
+		keywords := priorityKeywords(systemPrompt)
+		if len(keywords) == 0 {
+			return results
+		}
+		score = func(r *services.Result) int { return keywordOverlap(keywords, r) }
+	case SourceOrderSize:
+		score = func(r *services.Result) int { return len(r.Data) }
+	default:
+		return results
+	}
+
+	scores := make(map[*services.Result]int, len(results))
+	for _, r := range results {
+		if r.Error != "" || r.Empty {
+			scores[r] = -1
+		} else {
+			scores[r] = score(r)
+		}
+	}
+	ordered := make([]*services.Result, len(results))
+	copy(ordered, results)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return scores[ordered[i]] > scores[ordered[j]]
+	})
+	return ordered
+}
+
+// priorityKeywords extracts the distinct lowercase words of four or more
+// letters from the system prompt, minus stopwords.
+func priorityKeywords(systemPrompt string) []string {
+	seen := make(map[string]bool)
+	var keywords []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(systemPrompt), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(w)) < 4 || stopwords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		keywords = append(keywords, w)
+	}
+	return keywords
+}
+
+// keywordOverlap counts the keywords that appear in a source's label or data.
+func keywordOverlap(keywords []string, r *services.Result) int {
+	return textOverlap(keywords, r.ContextLabel+" "+string(r.Data))
+}
+
+// textOverlap counts the keywords that appear in text, ignoring case.
+func textOverlap(keywords []string, text string) int {
+	text = strings.ToLower(text)
+	n := 0
+	for _, k := range keywords {
+		if strings.Contains(text, k) {
+			n++
+		}
+	}
+	return n
+}
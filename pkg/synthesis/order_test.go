@@ -0,0 +1,76 @@
+package synthesis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+func labels(results []*services.Result) string {
+	var names []string
+	for _, r := range results {
+		names = append(names, r.ContextLabel)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestOrderSourcesRelevance(t *testing.T) {
+	results := []*services.Result{
+		{ContextLabel: "weather", Data: []byte(`{"forecast": "rain"}`)},
+		{ContextLabel: "failed", Error: "HTTP 500"},
+		{ContextLabel: "filings", Data: []byte(`{"company": "Acme", "filing": "10-K", "sanctions": []}`)},
+		{ContextLabel: "news", Data: []byte(`{"headline": "Acme under sanctions review"}`)},
+	}
+	system := "Focus on sanctions and company filings for Acme."
+
+	got := labels(orderSources(results, SourceOrderRelevance, system))
+	if got != "filings,news,weather,failed" {
+		t.Errorf("relevance order = %s", got)
+	}
+	if labels(results) != "weather,failed,filings,news" {
+		t.Error("orderSources must not reorder its input")
+	}
+}
+
+func TestOrderSourcesSize(t *testing.T) {
+	results := []*services.Result{
+		{ContextLabel: "small", Data: []byte("a")},
+		{ContextLabel: "empty", Empty: true},
+		{ContextLabel: "large", Data: []byte("aaaaaaaa")},
+	}
+	if got := labels(orderSources(results, SourceOrderSize, "")); got != "large,small,empty" {
+		t.Errorf("size order = %s", got)
+	}
+}
+
+func TestOrderSourcesRoutineDefault(t *testing.T) {
+	results := []*services.Result{
+		{ContextLabel: "b", Data: []byte("x")},
+		{ContextLabel: "a", Data: []byte("xxxx")},
+	}
+	for _, order := range []string{"", SourceOrderRoutine} {
+		if got := labels(orderSources(results, order, "anything")); got != "b,a" {
+			t.Errorf("order %q = %s, want routine order", order, got)
+		}
+	}
+}
+
+func TestSynthesizeAppliesSourceOrder(t *testing.T) {
+	provider := &scriptedProvider{responses: []string{"# Report\n\nDone."}}
+	synth := NewLLMSynthesizer(provider, false)
+	synth.SetSourceOrder(SourceOrderSize)
+
+	results := []*services.Result{
+		{Service: "a", Tool: "t", ContextLabel: "Small Source", Data: []byte("x")},
+		{Service: "b", Tool: "t", ContextLabel: "Large Source", Data: []byte(strings.Repeat("y", 100))},
+	}
+	if _, err := synth.Synthesize(context.Background(), "Report", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	prompt := provider.prompts[0]
+	if strings.Index(prompt, "Large Source") > strings.Index(prompt, "Small Source") {
+		t.Error("larger source should come first in the prompt")
+	}
+}
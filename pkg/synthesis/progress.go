@@ -0,0 +1,94 @@
+package synthesis
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// ProgressFunc is told how many of a multi-stage run's stage 1 summaries
+// are done, after each one finishes. Calls are serialized, with done
+// counting up to total.
+type ProgressFunc func(done, total int)
+
+type progressKey struct{}
+
+// WithProgress returns a context that reports stage 1 progress to fn. The
+// context travels through wrapping synthesizers and providers, so callers
+// need not know which synthesizer they hold. A nil fn reports nothing.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// TokenFunc receives a report's text as the model generates it, a few
+// tokens at a time.
+type TokenFunc func(text string)
+
+type tokensKey struct{}
+
+// WithTokens returns a context under which the final report is streamed to
+// fn as it is generated, by providers that can stream. Stage 1 summaries are
+// not streamed, and the text is the model's raw output, before the cleanup
+// Synthesize applies. A nil fn turns streaming off.
+func WithTokens(ctx context.Context, fn TokenFunc) context.Context {
+	return context.WithValue(ctx, tokensKey{}, fn)
+}
+
+// CompleteStream is Complete with the reply passed to fn as it is
+// generated, for callers that show a model's output live rather than a
+// spinner. Providers that can't stream pass the whole reply to fn once,
+// when it arrives. A nil fn makes it a plain Complete.
+func CompleteStream(ctx context.Context, p Provider, systemPrompt, userPrompt string, fn TokenFunc) (string, error) {
+	if fn == nil {
+		return p.Complete(ctx, systemPrompt, userPrompt)
+	}
+	streamed := false
+	reply, err := p.Complete(WithTokens(ctx, func(text string) {
+		streamed = true
+		fn(text)
+	}), systemPrompt, userPrompt)
+	if err == nil && !streamed && reply != "" {
+		fn(reply)
+	}
+	return reply, err
+}
+
+// StreamBuffer collects a reply as it streams in, for a UI that redraws on
+// its own schedule. Add is a TokenFunc; Tail is safe to call meanwhile.
+type StreamBuffer struct {
+	mu   sync.Mutex
+	text strings.Builder
+}
+
+// Add appends streamed text.
+func (b *StreamBuffer) Add(text string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.text.WriteString(text)
+}
+
+// Tail returns the last n runes received, on one line, for a status line.
+func (b *StreamBuffer) Tail(n int) string {
+	b.mu.Lock()
+	text := b.text.String()
+	b.mu.Unlock()
+	text = strings.Join(strings.Fields(text), " ")
+	if r := []rune(text); len(r) > n {
+		text = "…" + string(r[len(r)-n+1:])
+	}
+	return text
+}
+
+// tokensFrom returns ctx's token callback, or nil when nothing streams.
+func tokensFrom(ctx context.Context) TokenFunc {
+	fn, _ := ctx.Value(tokensKey{}).(TokenFunc)
+	return fn
+}
+
+// progressFrom returns ctx's progress callback, or a no-op.
+func progressFrom(ctx context.Context) ProgressFunc {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		return fn
+	}
+	return func(int, int) {}
+}
@@ -0,0 +1,44 @@
+package synthesis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompleteStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"choices\": [{\"delta\": {\"content\": \"To: Ann\\n\"}}]}\n\n" +
+			"data: {\"choices\": [{\"delta\": {\"content\": \"Hello\"}}]}\n\n" +
+			"data: [DONE]\n\n"))
+	}))
+	defer srv.Close()
+
+	var buf StreamBuffer
+	reply, err := CompleteStream(context.Background(), NewOpenAIProvider(srv.URL, "", "model", 0), "", "x", buf.Add)
+	if err != nil {
+		t.Fatalf("CompleteStream: %v", err)
+	}
+	if reply != "To: Ann\nHello" || buf.Tail(100) != "To: Ann Hello" {
+		t.Errorf("reply %q, streamed %q", reply, buf.Tail(100))
+	}
+	if got := buf.Tail(5); got != "…ello" {
+		t.Errorf("Tail(5) = %q", got)
+	}
+}
+
+func TestCompleteStreamWithoutStreamingProvider(t *testing.T) {
+	var chunks []string
+	reply, err := CompleteStream(context.Background(), &fakeProvider{response: "# Draft"}, "", "x", func(text string) {
+		chunks = append(chunks, text)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply != "# Draft" || strings.Join(chunks, "|") != "# Draft" {
+		t.Errorf("reply %q, chunks %q; want the whole reply once", reply, chunks)
+	}
+}
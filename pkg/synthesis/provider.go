@@ -1,7 +1,9 @@
 package synthesis
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
 	"github.com/jcadam/burrow/pkg/config"
 )
@@ -11,7 +13,32 @@ import (
 type GenerationParams struct {
 	Temperature *float64
 	TopP        *float64
-	MaxTokens   int // 0 = model default
+	MaxTokens   int  // 0 = model default
+	Seed        *int // fixed sampling seed, where the provider supports one
+}
+
+// Checker is implemented by providers that can confirm, cheaply and before a
+// run commits to collecting data, that they are configured and reachable.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// checkProvider runs p's Check when it has one.
+func checkProvider(ctx context.Context, p Provider) error {
+	if c, ok := p.(Checker); ok {
+		return c.Check(ctx)
+	}
+	return nil
+}
+
+// checkAPIKey is the Check for hosted providers that authenticate with an
+// API key. It makes no request: a key that is still an unresolved ${VAR}
+// reference means the variable isn't exported.
+func checkAPIKey(key string) error {
+	if strings.HasPrefix(key, "$") {
+		return fmt.Errorf("api_key %s is unresolved (is the variable set?)", key)
+	}
+	return nil
 }
 
 // NewProvider creates an LLM provider from config. Returns (nil, nil) for
@@ -21,6 +48,7 @@ func NewProvider(cfg config.ProviderConfig) (Provider, error) {
 		Temperature: cfg.Temperature,
 		TopP:        cfg.TopP,
 		MaxTokens:   cfg.MaxTokens,
+		Seed:        cfg.Seed,
 	}
 
 	switch cfg.Type {
@@ -43,6 +71,28 @@ func NewProvider(cfg config.ProviderConfig) (Provider, error) {
 		p.SetGenerationParams(params)
 		return p, nil
 
+	case "openai":
+		if cfg.Endpoint == "" {
+			return nil, fmt.Errorf("openai provider %q requires an endpoint", cfg.Name)
+		}
+		if cfg.Model == "" {
+			return nil, fmt.Errorf("openai provider %q requires a model", cfg.Name)
+		}
+		p := NewOpenAIProvider(cfg.Endpoint, cfg.APIKey, cfg.Model, cfg.Timeout)
+		p.SetGenerationParams(params)
+		return p, nil
+
+	case "anthropic":
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("anthropic provider %q requires an api_key", cfg.Name)
+		}
+		if cfg.Model == "" {
+			return nil, fmt.Errorf("anthropic provider %q requires a model", cfg.Name)
+		}
+		p := NewAnthropicProvider(cfg.Endpoint, cfg.APIKey, cfg.Model, cfg.Timeout)
+		p.SetGenerationParams(params)
+		return p, nil
+
 	case "passthrough", "":
 		return nil, nil
 
@@ -73,6 +73,46 @@ func TestNewProviderOpenRouterNoModel(t *testing.T) {
 	}
 }
 
+func TestNewProviderOpenAI(t *testing.T) {
+	p, err := NewProvider(config.ProviderConfig{
+		Name:     "local/vllm",
+		Type:     "openai",
+		Endpoint: "http://localhost:8000/v1",
+		Model:    "qwen2.5-7b-instruct",
+	})
+	if err != nil {
+		t.Fatalf("NewProvider without api_key: %v", err)
+	}
+	if _, ok := p.(*OpenRouterProvider); !ok {
+		t.Errorf("expected *OpenRouterProvider, got %T", p)
+	}
+	if _, err := NewProvider(config.ProviderConfig{Name: "local/vllm", Type: "openai", Model: "m"}); err == nil {
+		t.Error("expected error for missing endpoint")
+	}
+}
+
+func TestNewProviderAnthropic(t *testing.T) {
+	p, err := NewProvider(config.ProviderConfig{
+		Name:   "remote/claude",
+		Type:   "anthropic",
+		APIKey: "sk-ant-test",
+		Model:  "claude-sonnet-4-5",
+	})
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	if _, ok := p.(*AnthropicProvider); !ok {
+		t.Errorf("expected *AnthropicProvider, got %T", p)
+	}
+
+	if _, err := NewProvider(config.ProviderConfig{Name: "remote/claude", Type: "anthropic", Model: "m"}); err == nil {
+		t.Error("expected error for missing api_key")
+	}
+	if _, err := NewProvider(config.ProviderConfig{Name: "remote/claude", Type: "anthropic", APIKey: "k"}); err == nil {
+		t.Error("expected error for missing model")
+	}
+}
+
 func TestNewProviderPassthrough(t *testing.T) {
 	p, err := NewProvider(config.ProviderConfig{
 		Name: "none",
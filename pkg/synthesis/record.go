@@ -0,0 +1,115 @@
+package synthesis
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// PromptCall is one completion seen by a PromptRecorder.
+type PromptCall struct {
+	System   string
+	User     string
+	Response string
+	Err      error
+}
+
+// PromptRecorder wraps a Provider and keeps every prompt sent through it and
+// the reply, so a run's exact LLM input can be saved and compared.
+type PromptRecorder struct {
+	inner Provider
+	mu    sync.Mutex
+	calls []PromptCall
+}
+
+// RecordPrompts wraps p in a PromptRecorder.
+func RecordPrompts(p Provider) *PromptRecorder {
+	return &PromptRecorder{inner: p}
+}
+
+// Complete forwards to the wrapped provider and records the exchange.
+func (r *PromptRecorder) Complete(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	resp, err := r.inner.Complete(ctx, systemPrompt, userPrompt)
+	r.mu.Lock()
+	r.calls = append(r.calls, PromptCall{System: systemPrompt, User: userPrompt, Response: resp, Err: err})
+	r.mu.Unlock()
+	return resp, err
+}
+
+// Check forwards to the wrapped provider's Check, if any. It isn't recorded.
+func (r *PromptRecorder) Check(ctx context.Context) error {
+	return checkProvider(ctx, r.inner)
+}
+
+// Calls returns the recorded exchanges in the order they completed.
+func (r *PromptRecorder) Calls() []PromptCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]PromptCall(nil), r.calls...)
+}
+
+// WriteMarkdown writes the recorded exchanges as markdown, one section per
+// call, with prompts and responses in fenced blocks so files from two runs
+// diff cleanly.
+func (r *PromptRecorder) WriteMarkdown(w io.Writer) error {
+	for i, c := range r.Calls() {
+		reply := c.Response
+		if c.Err != nil {
+			reply = "error: " + c.Err.Error()
+		}
+		if _, err := fmt.Fprintf(w, "## Call %d\n\n### System\n\n%s\n\n### User\n\n%s\n\n### Response\n\n%s\n\n",
+			i+1, fence(c.System), fence(c.User), fence(reply)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WritePrompts writes the recorded prompts as WriteMarkdown does, without
+// the responses.
+func (r *PromptRecorder) WritePrompts(w io.Writer) error {
+	for i, c := range r.Calls() {
+		if _, err := fmt.Fprintf(w, "## Call %d\n\n### System\n\n%s\n\n### User\n\n%s\n\n",
+			i+1, fence(c.System), fence(c.User)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// previewResponse stands in for every model reply in a prompt preview. It is
+// a valid report so no retry is triggered, and reads as a placeholder where
+// it feeds a later stage's prompt.
+const previewResponse = "# Preview\n\n(placeholder: no LLM call was made)"
+
+// PreviewProvider is a Provider that never calls a model. Wrapped in a
+// PromptRecorder, it shows the exact prompts synthesis would send.
+type PreviewProvider struct{}
+
+// Complete returns a placeholder reply.
+func (PreviewProvider) Complete(context.Context, string, string) (string, error) {
+	return previewResponse, nil
+}
+
+// fence wraps text in a code fence longer than any backtick run inside it.
+func fence(text string) string {
+	longest, run := 0, 0
+	for _, c := range text {
+		if c == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	n := 3
+	if longest >= n {
+		n = longest + 1
+	}
+	marks := strings.Repeat("`", n)
+	return marks + "\n" + text + "\n" + marks
+}
@@ -0,0 +1,95 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+type echoProvider struct{ err error }
+
+func (e *echoProvider) Complete(_ context.Context, _, userPrompt string) (string, error) {
+	if e.err != nil {
+		return "", e.err
+	}
+	return "re: " + userPrompt, nil
+}
+
+func TestPromptRecorder(t *testing.T) {
+	rec := RecordPrompts(&echoProvider{})
+	rec.Complete(context.Background(), "be brief", "first")
+	rec.Complete(context.Background(), "be brief", "second")
+
+	calls := rec.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(calls))
+	}
+	if calls[1].System != "be brief" || calls[1].User != "second" || calls[1].Response != "re: second" {
+		t.Errorf("unexpected call record: %+v", calls[1])
+	}
+
+	var b strings.Builder
+	if err := rec.WriteMarkdown(&b); err != nil {
+		t.Fatalf("WriteMarkdown: %v", err)
+	}
+	out := b.String()
+	for _, want := range []string{"## Call 1", "## Call 2", "### System\n\n```\nbe brief\n```", "```\nre: first\n```"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPromptRecorderError(t *testing.T) {
+	rec := RecordPrompts(&echoProvider{err: errors.New("offline")})
+	if _, err := rec.Complete(context.Background(), "", "hi"); err == nil {
+		t.Fatal("expected the provider error to pass through")
+	}
+	var b strings.Builder
+	rec.WriteMarkdown(&b)
+	if !strings.Contains(b.String(), "error: offline") {
+		t.Errorf("expected error in recorded response:\n%s", b.String())
+	}
+}
+
+func TestPreviewProviderRecordsStagePrompts(t *testing.T) {
+	rec := RecordPrompts(PreviewProvider{})
+	synth := NewLLMSynthesizer(rec, false)
+	synth.SetMultiStage(MultiStageConfig{Strategy: "multi-stage", Concurrency: 1})
+
+	results := []*services.Result{
+		{Service: "a", Tool: "t", Data: []byte("alpha data")},
+		{Service: "b", Tool: "t", Data: []byte("beta data")},
+	}
+	if _, err := synth.Synthesize(context.Background(), "Report", "be brief", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+
+	// Two stage 1 prompts and one stage 2 prompt; the placeholder replies
+	// pass validation, so nothing is retried.
+	calls := rec.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 prompts, got %d", len(calls))
+	}
+	if !strings.Contains(calls[0].User+calls[1].User, "alpha data") {
+		t.Error("stage 1 prompts missing source data")
+	}
+
+	var b strings.Builder
+	if err := rec.WritePrompts(&b); err != nil {
+		t.Fatalf("WritePrompts: %v", err)
+	}
+	if out := b.String(); !strings.Contains(out, "## Call 3") || strings.Contains(out, "### Response") {
+		t.Errorf("unexpected prompts output:\n%s", out)
+	}
+}
+
+func TestFenceLongerThanContent(t *testing.T) {
+	got := fence("```yaml\nx: 1\n```")
+	if !strings.HasPrefix(got, "````\n") || !strings.HasSuffix(got, "\n````") {
+		t.Errorf("fence should outgrow inner backticks, got %q", got)
+	}
+}
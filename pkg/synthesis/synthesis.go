@@ -43,17 +43,21 @@ func (p *PassthroughSynthesizer) Synthesize(_ context.Context, title string, _ s
 	b.WriteString("*\n\n")
 
 	successCount := 0
+	emptyCount := 0
 	errorCount := 0
 	for _, r := range results {
-		if r.Error != "" {
+		switch {
+		case r.Error != "":
 			errorCount++
-		} else {
+		case r.Empty:
+			emptyCount++
+		default:
 			successCount++
 		}
 	}
 
-	b.WriteString(fmt.Sprintf("**Sources queried:** %d | **Successful:** %d | **Errors:** %d\n\n",
-		len(results), successCount, errorCount))
+	b.WriteString(fmt.Sprintf("**Sources queried:** %d | **Successful:** %d | **No results:** %d | **Errors:** %d\n\n",
+		len(results), successCount, emptyCount, errorCount))
 	b.WriteString("---\n\n")
 
 	for _, r := range results {
@@ -72,7 +76,14 @@ func (p *PassthroughSynthesizer) Synthesize(_ context.Context, title string, _ s
 			}
 			continue
 		}
+		if r.Empty {
+			b.WriteString("> No results returned.\n\n")
+			continue
+		}
 
+		if r.Note != "" {
+			b.WriteString("*" + r.Note + "*\n\n")
+		}
 		b.WriteString("```\n")
 		b.WriteString(string(r.Data))
 		b.WriteString("\n```\n\n")
@@ -96,10 +107,17 @@ const (
 		"Every news item, paper, or article with a URL must have a clickable link in the report. " +
 		"Never break a URL across lines."
 
+	// noResultsNote stands in for the data of a source that returned no items.
+	noResultsNote = "No results: this source returned no items. Say so briefly; do not invent content for it."
+
 	missingDataInstruction = "When source data has missing or incomplete fields, always analyze what IS present. " +
 		"Never skip a section or declare \"none included\" because some records lack a field. " +
 		"Present the available data, note any limitations briefly in parentheses, and move on."
 
+	// tagsInstruction explains the Tags line under a source's heading.
+	tagsInstruction = "Some sources carry Tags: labels the user assigned to guide the report's structure. " +
+		"Keep sources that share a tag together, and lead with sources tagged critical, urgent, or important."
+
 	// Compact variants for local models — fewer tokens, same rules.
 	localStaticDocumentInstruction = "You write factual report documents. " +
 		"Start immediately with content. No chat, no reasoning, no greetings, no closings."
@@ -116,16 +134,59 @@ const (
 type LLMSynthesizer struct {
 	provider         Provider
 	stripAttribution bool
+	privateServices  map[string]bool // when non-nil, only these services are stripped
 	localModel       bool
 	preprocess       bool
 	multiStage       MultiStageConfig
+	retries          int
+	sourceOrder      string
 }
 
 // NewLLMSynthesizer creates a synthesizer backed by an LLM provider.
 // When stripAttribution is true, service names are replaced with generic labels
 // before sending data to the provider (required for remote LLMs per spec).
 func NewLLMSynthesizer(provider Provider, stripAttribution bool) *LLMSynthesizer {
-	return &LLMSynthesizer{provider: provider, stripAttribution: stripAttribution}
+	return &LLMSynthesizer{provider: provider, stripAttribution: stripAttribution, retries: defaultRetries}
+}
+
+// Check confirms the provider is configured and reachable, for providers
+// that can tell (see Checker).
+func (s *LLMSynthesizer) Check(ctx context.Context) error {
+	return checkProvider(ctx, s.provider)
+}
+
+// SetPrivateServices limits attribution stripping to the named services;
+// the rest keep their labels, which gives the LLM better context. With no
+// names (the default) every service is stripped. It has no effect unless
+// stripping is enabled.
+func (l *LLMSynthesizer) SetPrivateServices(names []string) {
+	if len(names) == 0 {
+		l.privateServices = nil
+		return
+	}
+	l.privateServices = make(map[string]bool, len(names))
+	for _, n := range names {
+		l.privateServices[n] = true
+	}
+}
+
+// strips reports whether r's attribution is hidden from the provider.
+func (l *LLMSynthesizer) strips(r *services.Result) bool {
+	return l.stripAttribution && (l.privateServices == nil || l.privateServices[r.Service])
+}
+
+// strippedResults returns the results whose service names are hidden.
+func (l *LLMSynthesizer) strippedResults(results []*services.Result) []*services.Result {
+	if l.privateServices == nil {
+		return results
+	}
+	var stripped []*services.Result
+	for _, r := range results {
+		if l.strips(r) {
+			stripped = append(stripped, r)
+		}
+	}
+	return stripped
 }
 
 // SetLocalModel enables compact prompt variants optimized for smaller local models.
@@ -139,6 +200,23 @@ func (l *LLMSynthesizer) SetPreprocess(enabled bool) {
 	l.preprocess = enabled
 }
 
+// SetRetries sets how many times empty or refused output is regenerated
+// before Synthesize returns an error. Zero disables retries.
+func (l *LLMSynthesizer) SetRetries(n int) {
+	if n < 0 {
+		n = 0
+	}
+	l.retries = n
+}
+
+// SetSourceOrder sets how source data is ordered in the prompt: routine
+// order (the default), relevance, or size. Putting the most relevant data
+// first helps small-context models focus. Report section order is separate
+// and unaffected.
+func (l *LLMSynthesizer) SetSourceOrder(order string) {
+	l.sourceOrder = order
+}
+
 // SetMultiStage configures multi-stage synthesis behavior.
 func (l *LLMSynthesizer) SetMultiStage(cfg MultiStageConfig) {
 	l.multiStage = cfg
@@ -147,6 +225,7 @@ func (l *LLMSynthesizer) SetMultiStage(cfg MultiStageConfig) {
 // Synthesize sends collected results through the LLM for synthesis.
 // It routes to single-stage or multi-stage based on configuration and data size.
 func (l *LLMSynthesizer) Synthesize(ctx context.Context, title string, systemPrompt string, results []*services.Result) (string, error) {
+	results = orderSources(results, l.sourceOrder, systemPrompt)
 	if l.shouldMultiStage(results) {
 		return l.synthesizeMultiStage(ctx, title, systemPrompt, results)
 	}
@@ -172,33 +251,39 @@ func (l *LLMSynthesizer) synthesizeSingle(ctx context.Context, title string, sys
 	userPrompt.WriteString(title)
 	userPrompt.WriteString("\n\nSource data:\n\n")
 
+	stripped := l.strippedResults(results)
 	for i, r := range results {
 		label := r.Service + " — " + r.Tool
 		if r.ContextLabel != "" {
 			label = r.ContextLabel
 		}
-		if l.stripAttribution {
+		if l.strips(r) {
 			label = fmt.Sprintf("Source %d", i+1)
 		}
 
 		userPrompt.WriteString("### ")
 		userPrompt.WriteString(label)
 		userPrompt.WriteString("\n")
+		userPrompt.WriteString(formatTags(r.Tags))
 		if r.Error != "" {
 			errMsg := r.Error
 			if l.stripAttribution {
-				errMsg = stripServiceNames(errMsg, results)
+				errMsg = stripServiceNames(errMsg, stripped)
 			}
 			userPrompt.WriteString("Error: ")
 			userPrompt.WriteString(errMsg)
 			userPrompt.WriteString("\n")
+		} else if r.Empty {
+			userPrompt.WriteString(noResultsNote)
+			userPrompt.WriteString("\n")
 		} else {
 			data := string(r.Data)
 			if l.preprocess {
 				data = PreprocessData(data)
 			}
+			data = formatNote(r.Note) + formatHeaders(r.Headers) + data
 			if l.stripAttribution {
-				data = stripServiceNames(data, results)
+				data = stripServiceNames(data, stripped)
 			}
 			userPrompt.WriteString(data)
 			userPrompt.WriteString("\n")
@@ -206,6 +291,12 @@ func (l *LLMSynthesizer) synthesizeSingle(ctx context.Context, title string, sys
 		userPrompt.WriteString("\n")
 	}
 
+	if hasTags(results) {
+		userPrompt.WriteString("\n---\n")
+		userPrompt.WriteString(tagsInstruction)
+		userPrompt.WriteString("\n")
+	}
+
 	if l.localModel {
 		userPrompt.WriteString(localInstructions)
 	} else {
@@ -220,11 +311,54 @@ func (l *LLMSynthesizer) synthesizeSingle(ctx context.Context, title string, sys
 		userPrompt.WriteString("\n---\nBegin with report content immediately. No preamble, no reasoning, no conversational closing.\n")
 	}
 
-	result, err := l.provider.Complete(ctx, fullSystem, userPrompt.String())
-	if err != nil {
-		return "", err
+	return l.completeReport(ctx, title, fullSystem, userPrompt.String())
+}
+
+// formatNote renders a result's note as a line before its data, or "".
+func formatNote(note string) string {
+	if note == "" {
+		return ""
+	}
+	return "Note: " + note + "\n\n"
+}
+
+// formatHeaders renders captured response headers as a short preamble to a
+// source's data, sorted by name. Returns "" when there are none.
+func formatHeaders(headers map[string]string) string {
+	if len(headers) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("Response headers:\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %s: %s\n", name, headers[name])
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// formatTags renders a source's tags as a line under its heading, or ""
+// when it has none.
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return "Tags: " + strings.Join(tags, ", ") + "\n"
+}
+
+func hasTags(results []*services.Result) bool {
+	for _, r := range results {
+		if len(r.Tags) > 0 {
+			return true
+		}
 	}
-	return postProcess(result), nil
+	return false
 }
 
 // brokenURLPattern matches markdown link URLs that contain newlines: ](url\nrest)
@@ -99,6 +99,28 @@ func TestPassthroughSynthesizeEmpty(t *testing.T) {
 	}
 }
 
+func TestPassthroughSynthesizeNoResults(t *testing.T) {
+	synth := NewPassthroughSynthesizer()
+	results := []*services.Result{
+		{Service: "quiet-api", Tool: "search", Data: []byte(`{"results": []}`), Empty: true},
+		{Service: "good-api", Tool: "search", Data: []byte(`{"ok": true}`)},
+	}
+
+	md, err := synth.Synthesize(context.Background(), "Quiet Day", "", results)
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if !strings.Contains(md, "**Successful:** 1 | **No results:** 1 | **Errors:** 0") {
+		t.Errorf("expected no-results count in summary, got:\n%s", md)
+	}
+	if !strings.Contains(md, "> No results returned.") {
+		t.Error("expected no-results note for empty source")
+	}
+	if strings.Contains(md, `{"results": []}`) {
+		t.Error("empty source data should not be rendered")
+	}
+}
+
 // --- trimConversationalClosing unit tests ---
 
 func TestTrimClosingBasic(t *testing.T) {
@@ -228,6 +250,88 @@ func TestLLMSynthesizerTrimsClosing(t *testing.T) {
 	}
 }
 
+func TestLLMSynthesizerIncludesCapturedHeaders(t *testing.T) {
+	provider := &fakeProvider{}
+	synth := NewLLMSynthesizer(provider, false)
+
+	results := []*services.Result{
+		{Service: "api", Tool: "search", Data: []byte(`[]`), Headers: map[string]string{
+			"X-Total-Count":         "42",
+			"X-Ratelimit-Remaining": "3",
+		}},
+	}
+	if _, err := synth.Synthesize(context.Background(), "Brief", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	want := "Response headers:\n  X-Ratelimit-Remaining: 3\n  X-Total-Count: 42\n\n[]"
+	if !strings.Contains(provider.lastUser, want) {
+		t.Errorf("expected sorted headers before data, got:\n%s", provider.lastUser)
+	}
+}
+
+func TestLLMSynthesizerIncludesNote(t *testing.T) {
+	provider := &fakeProvider{}
+	synth := NewLLMSynthesizer(provider, false)
+
+	results := []*services.Result{
+		{Service: "api", Tool: "search", Data: []byte(`[1,2]`), Note: "Showing top 2 of 9 items."},
+	}
+	if _, err := synth.Synthesize(context.Background(), "Brief", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if !strings.Contains(provider.lastUser, "Note: Showing top 2 of 9 items.\n\n[1,2]") {
+		t.Errorf("expected the note before the data, got:\n%s", provider.lastUser)
+	}
+}
+
+func TestLLMSynthesizerIncludesSourceTags(t *testing.T) {
+	provider := &fakeProvider{}
+	synth := NewLLMSynthesizer(provider, false)
+
+	results := []*services.Result{
+		{Service: "nws", Tool: "alerts", ContextLabel: "Alerts", Data: []byte(`[]`), Tags: []string{"weather", "critical"}},
+		{Service: "api", Tool: "search", ContextLabel: "Search", Data: []byte(`[]`)},
+	}
+	if _, err := synth.Synthesize(context.Background(), "Brief", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if !strings.Contains(provider.lastUser, "### Alerts\nTags: weather, critical\n[]") {
+		t.Errorf("expected tags under the source heading, got:\n%s", provider.lastUser)
+	}
+	if !strings.Contains(provider.lastUser, "### Search\n[]") {
+		t.Errorf("untagged source should have no tags line, got:\n%s", provider.lastUser)
+	}
+	if !strings.Contains(provider.lastUser, tagsInstruction) {
+		t.Error("expected tags instruction when a source is tagged")
+	}
+
+	results[0].Tags = nil
+	if _, err := synth.Synthesize(context.Background(), "Brief", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if strings.Contains(provider.lastUser, tagsInstruction) {
+		t.Error("tags instruction should be omitted when no source is tagged")
+	}
+}
+
+func TestLLMSynthesizerMarksNoResults(t *testing.T) {
+	provider := &fakeProvider{}
+	synth := NewLLMSynthesizer(provider, false)
+
+	results := []*services.Result{
+		{Service: "api", Tool: "search", ContextLabel: "Contracts", Data: []byte(`{"results": []}`), Empty: true},
+	}
+	if _, err := synth.Synthesize(context.Background(), "Brief", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if !strings.Contains(provider.lastUser, "### Contracts\n"+noResultsNote) {
+		t.Errorf("expected no-results note in place of data, got:\n%s", provider.lastUser)
+	}
+	if strings.Contains(provider.lastUser, `{"results": []}`) {
+		t.Error("empty source data should not be sent to the LLM")
+	}
+}
+
 func TestLLMSynthesizerStripAttribution(t *testing.T) {
 	provider := &fakeProvider{}
 	synth := NewLLMSynthesizer(provider, true)
@@ -258,6 +362,47 @@ func TestLLMSynthesizerStripAttribution(t *testing.T) {
 	}
 }
 
+func TestLLMSynthesizerStripPrivateServicesOnly(t *testing.T) {
+	provider := &fakeProvider{}
+	synth := NewLLMSynthesizer(provider, true)
+	synth.SetPrivateServices([]string{"edgar"})
+
+	results := []*services.Result{
+		{Service: "nws", Tool: "forecast", Data: []byte(`forecast data`), ContextLabel: "NWS Forecast"},
+		{Service: "edgar", Tool: "company_filings", Data: []byte(`filings from edgar`), ContextLabel: "Watched Filings"},
+	}
+
+	if _, err := synth.Synthesize(context.Background(), "Brief", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+
+	if !strings.Contains(provider.lastUser, "### NWS Forecast") {
+		t.Error("expected public service to keep its label")
+	}
+	if strings.Contains(provider.lastUser, "edgar") || strings.Contains(provider.lastUser, "Watched Filings") {
+		t.Error("attribution leak: private service identifiable in LLM prompt")
+	}
+	if !strings.Contains(provider.lastUser, "### Source 2") {
+		t.Error("expected generic label for the private service")
+	}
+}
+
+func TestLLMSynthesizerPrivateServicesWithoutStripping(t *testing.T) {
+	provider := &fakeProvider{}
+	synth := NewLLMSynthesizer(provider, false)
+	synth.SetPrivateServices([]string{"edgar"})
+
+	results := []*services.Result{
+		{Service: "edgar", Tool: "company_filings", Data: []byte(`data`), ContextLabel: "Watched Filings"},
+	}
+	if _, err := synth.Synthesize(context.Background(), "Brief", "", results); err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if !strings.Contains(provider.lastUser, "### Watched Filings") {
+		t.Error("private flag should not strip when stripping is disabled")
+	}
+}
+
 func TestLLMSynthesizerStripErrorAttribution(t *testing.T) {
 	provider := &fakeProvider{}
 	synth := NewLLMSynthesizer(provider, true)
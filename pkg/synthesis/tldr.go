@@ -0,0 +1,55 @@
+package synthesis
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Summarizer is implemented by synthesizers that can condense a finished
+// report into a short executive summary.
+type Summarizer interface {
+	Summarize(ctx context.Context, report string) (string, error)
+}
+
+// maxSummaryBullets caps the TL;DR, however many bullets the model writes.
+const maxSummaryBullets = 5
+
+// summarySystemPrompt asks for the TL;DR of a finished report.
+const summarySystemPrompt = "You write the TL;DR for a report. Reply with 3 to 5 markdown bullet points, " +
+	"one line each, covering the most important findings and anything that needs action. " +
+	"Use only facts stated in the report. No heading, no introduction, no closing remarks."
+
+// Summarize asks the provider for a 3–5 bullet TL;DR of report and returns
+// it as a markdown list. It makes one call, which is not streamed; output
+// without bullets is an error.
+func (l *LLMSynthesizer) Summarize(ctx context.Context, report string) (string, error) {
+	text, err := l.provider.Complete(WithTokens(ctx, nil), summarySystemPrompt, report)
+	if err != nil {
+		return "", err
+	}
+	bullets := summaryBullets(postProcess(text))
+	if len(bullets) == 0 {
+		return "", fmt.Errorf("summary has no bullet points")
+	}
+	return "- " + strings.Join(bullets, "\n- ") + "\n", nil
+}
+
+// listItemPattern matches a bulleted or numbered markdown list item.
+var listItemPattern = regexp.MustCompile(`^\s*(?:[-*]|\d{1,2}[.)])\s+(\S.*)$`)
+
+// summaryBullets returns the text of the markdown list items in text, in
+// order and at most maxSummaryBullets of them.
+func summaryBullets(text string) []string {
+	var bullets []string
+	for _, line := range strings.Split(text, "\n") {
+		if m := listItemPattern.FindStringSubmatch(line); m != nil {
+			bullets = append(bullets, strings.TrimSpace(m[1]))
+			if len(bullets) == maxSummaryBullets {
+				break
+			}
+		}
+	}
+	return bullets
+}
@@ -0,0 +1,39 @@
+package synthesis
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	provider := &recordingProvider{response: "Here is the TL;DR:\n\n- Two contracts awarded\n- Budget vote moved to Friday\n* Storm expected Tuesday\n"}
+	synth := NewLLMSynthesizer(provider, false)
+
+	got, err := synth.Summarize(context.Background(), "# Brief\n\nBody.")
+	if err != nil {
+		t.Fatalf("Summarize: %v", err)
+	}
+	want := "- Two contracts awarded\n- Budget vote moved to Friday\n- Storm expected Tuesday\n"
+	if got != want {
+		t.Errorf("Summarize = %q, want %q", got, want)
+	}
+	if calls := provider.getCalls(); len(calls) != 1 || calls[0].user != "# Brief\n\nBody." {
+		t.Errorf("calls = %+v, want one call with the report", calls)
+	}
+}
+
+func TestSummarizeNoBullets(t *testing.T) {
+	synth := NewLLMSynthesizer(&recordingProvider{response: "Nothing much happened."}, false)
+	if _, err := synth.Summarize(context.Background(), "# Brief"); err == nil {
+		t.Error("expected an error for a summary without bullets")
+	}
+}
+
+func TestSummaryBullets(t *testing.T) {
+	text := "1. One\n2) Two\n- Three\n\n10. Four\n- Five\n- Six\n"
+	got := summaryBullets(text)
+	if strings.Join(got, "|") != "One|Two|Three|Four|Five" {
+		t.Errorf("summaryBullets = %q", got)
+	}
+}
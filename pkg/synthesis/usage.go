@@ -0,0 +1,80 @@
+package synthesis
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// UsageFunc receives the token counts of one completed LLM call, with the
+// provider that made it and the call's estimated cost in US dollars (0 when
+// the provider has no pricing). Counts are 0 when the provider's reply
+// didn't include them. Calls may come from concurrent stage 1 summaries.
+type UsageFunc func(provider string, promptTokens, completionTokens int, cost float64)
+
+type usageKey struct{}
+
+// WithUsage returns a context under which every completed LLM call's usage
+// is passed to fn. Only calls through a provider wrapped by MeterUsage are
+// attributed to a provider by name. A nil fn records nothing.
+func WithUsage(ctx context.Context, fn UsageFunc) context.Context {
+	return context.WithValue(ctx, usageKey{}, fn)
+}
+
+func usageFrom(ctx context.Context) UsageFunc {
+	fn, _ := ctx.Value(usageKey{}).(UsageFunc)
+	return fn
+}
+
+// reportUsage passes a call's token counts, as the provider's reply gave
+// them, to the context's UsageFunc.
+func reportUsage(ctx context.Context, promptTokens, completionTokens int) {
+	if fn := usageFrom(ctx); fn != nil {
+		fn("", promptTokens, completionTokens, 0)
+	}
+}
+
+// Pricing is what a provider charges, in US dollars per million tokens.
+type Pricing struct {
+	Prompt     float64
+	Completion float64
+}
+
+// Cost estimates the price of a call from its token counts.
+func (p Pricing) Cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*p.Prompt + float64(completionTokens)*p.Completion) / 1e6
+}
+
+// MeterUsage wraps p so the usage of its calls is reported under name and
+// priced at price. A successful call whose reply carried no token counts
+// is still reported, with zero tokens, so it counts as a call.
+func MeterUsage(p Provider, name string, price Pricing) Provider {
+	return &meteredProvider{inner: p, name: name, price: price}
+}
+
+type meteredProvider struct {
+	inner Provider
+	name  string
+	price Pricing
+}
+
+// Check forwards to the wrapped provider's Check, if any.
+func (m *meteredProvider) Check(ctx context.Context) error {
+	return checkProvider(ctx, m.inner)
+}
+
+func (m *meteredProvider) Complete(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	fn := usageFrom(ctx)
+	if fn == nil {
+		return m.inner.Complete(ctx, systemPrompt, userPrompt)
+	}
+	var reported atomic.Bool
+	ctx = WithUsage(ctx, func(_ string, prompt, completion int, _ float64) {
+		reported.Store(true)
+		fn(m.name, prompt, completion, m.price.Cost(prompt, completion))
+	})
+	reply, err := m.inner.Complete(ctx, systemPrompt, userPrompt)
+	if err == nil && !reported.Load() {
+		fn(m.name, 0, 0, 0)
+	}
+	return reply, err
+}
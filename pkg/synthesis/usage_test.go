@@ -0,0 +1,99 @@
+package synthesis
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// usageCall is one call seen by a UsageFunc.
+type usageCall struct {
+	provider           string
+	prompt, completion int
+	cost               float64
+}
+
+func collectUsage(calls *[]usageCall) context.Context {
+	return WithUsage(context.Background(), func(provider string, prompt, completion int, cost float64) {
+		*calls = append(*calls, usageCall{provider, prompt, completion, cost})
+	})
+}
+
+func TestMeterUsage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"choices": [{"message": {"content": "ok"}}], "usage": {"prompt_tokens": 1000, "completion_tokens": 200}}`))
+	}))
+	defer srv.Close()
+
+	var calls []usageCall
+	p := MeterUsage(NewOpenRouterProvider(srv.URL, "key", "model"), "cloud/gpt", Pricing{Prompt: 3, Completion: 15})
+	if _, err := p.Complete(collectUsage(&calls), "", "hi"); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("got %d usage calls, want 1", len(calls))
+	}
+	c := calls[0]
+	if c.provider != "cloud/gpt" || c.prompt != 1000 || c.completion != 200 {
+		t.Errorf("usage = %+v", c)
+	}
+	if want := 0.006; math.Abs(c.cost-want) > 1e-9 {
+		t.Errorf("cost = %v, want %v", c.cost, want)
+	}
+}
+
+func TestMeterUsageWithoutCounts(t *testing.T) {
+	var calls []usageCall
+	p := MeterUsage(PreviewProvider{}, "local/llama3", Pricing{})
+	if _, err := p.Complete(collectUsage(&calls), "", "hi"); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if len(calls) != 1 || calls[0].provider != "local/llama3" || calls[0].prompt != 0 {
+		t.Errorf("usage = %+v, want one uncounted call", calls)
+	}
+}
+
+func TestProvidersReportUsage(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		stream bool
+		newP   func(url string) Provider
+	}{
+		{"ollama", `{"message": {"content": "ok"}, "done": true, "prompt_eval_count": 40, "eval_count": 7}`, false,
+			func(url string) Provider { return NewOllamaProvider(url, "m") }},
+		{"ollama stream", "{\"message\": {\"content\": \"ok\"}}\n{\"done\": true, \"prompt_eval_count\": 40, \"eval_count\": 7}\n", true,
+			func(url string) Provider { return NewOllamaProvider(url, "m") }},
+		{"openai stream", "data: {\"choices\": [{\"delta\": {\"content\": \"ok\"}}]}\n\ndata: {\"choices\": [], \"usage\": {\"prompt_tokens\": 40, \"completion_tokens\": 7}}\n\ndata: [DONE]\n\n", true,
+			func(url string) Provider { return NewOpenAIProvider(url, "", "m", 0) }},
+		{"anthropic", `{"content": [{"type": "text", "text": "ok"}], "usage": {"input_tokens": 40, "output_tokens": 7}}`, false,
+			func(url string) Provider { return NewAnthropicProvider(url, "key", "m", 0) }},
+		{"anthropic stream", "data: {\"type\": \"message_start\", \"message\": {\"usage\": {\"input_tokens\": 40, \"output_tokens\": 1}}}\n\n" +
+			"data: {\"type\": \"content_block_delta\", \"delta\": {\"type\": \"text_delta\", \"text\": \"ok\"}}\n\n" +
+			"data: {\"type\": \"message_delta\", \"usage\": {\"output_tokens\": 7}}\n\n" +
+			"data: {\"type\": \"message_stop\"}\n\n", true,
+			func(url string) Provider { return NewAnthropicProvider(url, "key", "m", 0) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			var calls []usageCall
+			ctx := collectUsage(&calls)
+			if tt.stream {
+				ctx = WithTokens(ctx, func(string) {})
+			}
+			if _, err := tt.newP(srv.URL).Complete(ctx, "", "hi"); err != nil {
+				t.Fatalf("Complete: %v", err)
+			}
+			if len(calls) != 1 || calls[0].prompt != 40 || calls[0].completion != 7 {
+				t.Errorf("usage = %+v, want 40 prompt and 7 completion tokens", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// defaultRetries is how many times a rejected report is regenerated before
+// synthesis gives up.
+const defaultRetries = 1
+
+// reinforcedInstruction is appended to the user prompt when a previous
+// attempt produced unusable output.
+const reinforcedInstruction = "\n\n---\nIMPORTANT: Your previous response was not a usable report. " +
+	"Respond with the complete markdown report now. " +
+	"Do not refuse, apologize, or leave the response empty."
+
+// refusalPrefixes are lowercase openings that mark a response as a refusal
+// rather than a report.
+var refusalPrefixes = []string{
+	"i'm sorry",
+	"i am sorry",
+	"sorry, ",
+	"i cannot",
+	"i can't",
+	"i can not",
+	"i'm unable",
+	"i am unable",
+	"i'm not able",
+	"i am not able",
+	"as an ai",
+}
+
+// validateReport rejects synthesis output that is empty or opens with a
+// refusal. Anything else is a usable report, whatever its structure.
+func validateReport(text string) error {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return errors.New("empty output")
+	}
+
+	first := strings.ToLower(strings.SplitN(trimmed, "\n", 2)[0])
+	for _, p := range refusalPrefixes {
+		if strings.HasPrefix(first, p) {
+			return errors.New("output is a refusal")
+		}
+	}
+	return nil
+}
+
+// ensureHeading prepends "# title" to a report with no markdown heading, so
+// the saved report still carries its title.
+func ensureHeading(text, title string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			return text
+		}
+	}
+	return "# " + title + "\n\n" + text
+}
+
+// completeReport makes the final report-producing LLM call, post-processes
+// the output, and validates it. Rejected output is retried with a reinforced
+// prompt up to the configured number of times; provider errors are returned
+// immediately. Accepted output without a heading gets the title as one.
+func (l *LLMSynthesizer) completeReport(ctx context.Context, title, systemPrompt, userPrompt string) (string, error) {
+	var lastErr error
+	for attempt := 0; attempt <= l.retries; attempt++ {
+		prompt := userPrompt
+		if attempt > 0 {
+			prompt += reinforcedInstruction
+			if fn := tokensFrom(ctx); fn != nil {
+				fn("\n\n[output rejected; regenerating]\n\n")
+			}
+		}
+		result, err := l.provider.Complete(ctx, systemPrompt, prompt)
+		if err != nil {
+			return "", err
+		}
+		result = postProcess(result)
+		if lastErr = validateReport(result); lastErr == nil {
+			return ensureHeading(result, title), nil
+		}
+	}
+	return "", fmt.Errorf("synthesis output rejected after %d attempt(s): %w", l.retries+1, lastErr)
+}
@@ -0,0 +1,132 @@
+package synthesis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jcadam/burrow/pkg/services"
+)
+
+// scriptedProvider returns its responses in order, repeating the last one.
+type scriptedProvider struct {
+	responses []string
+	prompts   []string
+}
+
+func (s *scriptedProvider) Complete(_ context.Context, _, user string) (string, error) {
+	s.prompts = append(s.prompts, user)
+	i := len(s.prompts) - 1
+	if i >= len(s.responses) {
+		i = len(s.responses) - 1
+	}
+	return s.responses[i], nil
+}
+
+func TestValidateReport(t *testing.T) {
+	tests := []struct {
+		text string
+		ok   bool
+	}{
+		{"# Brief\n\nContent.", true},
+		{"Intro line\n\n## Section\nContent.", true},
+		{"", false},
+		{"  \n\n ", false},
+		{"Just some prose without structure.", true},
+		{"I'm sorry, but I can't help with that.\n# Report", false},
+		{"As an AI, I cannot browse.", false},
+	}
+	for _, tt := range tests {
+		err := validateReport(tt.text)
+		if (err == nil) != tt.ok {
+			t.Errorf("validateReport(%q) = %v, want ok=%v", tt.text, err, tt.ok)
+		}
+	}
+}
+
+func TestLLMSynthesizerRetriesEmptyOutput(t *testing.T) {
+	provider := &scriptedProvider{responses: []string{"", "# Brief\n\nRecovered.\n"}}
+	synth := NewLLMSynthesizer(provider, false)
+
+	results := []*services.Result{{Service: "api", Tool: "fetch", Data: []byte(`{"a": 1}`)}}
+	got, err := synth.Synthesize(context.Background(), "Brief", "", results)
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if !strings.Contains(got, "Recovered.") {
+		t.Errorf("expected retried output, got %q", got)
+	}
+	if len(provider.prompts) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(provider.prompts))
+	}
+	if strings.Contains(provider.prompts[0], reinforcedInstruction) {
+		t.Error("first attempt should use the original prompt")
+	}
+	if !strings.HasSuffix(provider.prompts[1], reinforcedInstruction) {
+		t.Error("retry should reinforce the prompt")
+	}
+}
+
+func TestLLMSynthesizerPersistentEmptyOutputFails(t *testing.T) {
+	provider := &scriptedProvider{responses: []string{""}}
+	synth := NewLLMSynthesizer(provider, false)
+	synth.SetRetries(2)
+
+	results := []*services.Result{{Service: "api", Tool: "fetch", Data: []byte(`{"a": 1}`)}}
+	_, err := synth.Synthesize(context.Background(), "Brief", "", results)
+	if err == nil {
+		t.Fatal("expected error after persistent empty output")
+	}
+	if !strings.Contains(err.Error(), "empty output") {
+		t.Errorf("expected empty output error, got: %v", err)
+	}
+	if len(provider.prompts) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(provider.prompts))
+	}
+}
+
+func TestLLMSynthesizerRetriesDisabled(t *testing.T) {
+	provider := &scriptedProvider{responses: []string{"I'm sorry, I can't help with that."}}
+	synth := NewLLMSynthesizer(provider, false)
+	synth.SetRetries(0)
+
+	results := []*services.Result{{Service: "api", Tool: "fetch", Data: []byte(`{"a": 1}`)}}
+	if _, err := synth.Synthesize(context.Background(), "Brief", "", results); err == nil {
+		t.Fatal("expected error for a refusal")
+	}
+	if len(provider.prompts) != 1 {
+		t.Errorf("expected a single attempt, got %d", len(provider.prompts))
+	}
+}
+
+func TestLLMSynthesizerPrependsMissingHeading(t *testing.T) {
+	provider := &scriptedProvider{responses: []string{"Markets were quiet today.\n\n- Nothing filed.\n"}}
+	synth := NewLLMSynthesizer(provider, false)
+
+	results := []*services.Result{{Service: "api", Tool: "fetch", Data: []byte(`{"a": 1}`)}}
+	got, err := synth.Synthesize(context.Background(), "Morning Brief", "", results)
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if !strings.HasPrefix(got, "# Morning Brief\n\nMarkets were quiet today.") {
+		t.Errorf("expected title heading prepended, got %q", got)
+	}
+	if len(provider.prompts) != 1 {
+		t.Errorf("a report without a heading should not be retried, got %d calls", len(provider.prompts))
+	}
+}
+
+func TestMultiStageRetriesMalformedAssembly(t *testing.T) {
+	provider := &scriptedProvider{responses: []string{"Stage 1 summary.", "I'm sorry, I can't do that.", "# Brief\n\nDone.\n"}}
+	synth := NewLLMSynthesizer(provider, false)
+	synth.SetMultiStage(MultiStageConfig{Strategy: "multi-stage"})
+
+	results := []*services.Result{{Service: "api", Tool: "fetch", Data: []byte(`{"a": 1}`)}}
+	got, err := synth.Synthesize(context.Background(), "Brief", "", results)
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if !strings.Contains(got, "Done.") {
+		t.Errorf("expected retried stage 2 output, got %q", got)
+	}
+}
@@ -0,0 +1,166 @@
+// Package values persists small per-routine key/value pairs between runs.
+// Routines stash values (e.g. a result count) at the end of a run and read
+// them back in templates on the next run via {{lastValue "key"}}, enabling
+// lightweight change detection without full report comparison.
+//
+// Values are stored as plain JSON alongside the scheduler state.
+package values
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Store persists values to a JSON file keyed by routine name, then value key.
+type Store struct {
+	path string
+	mu   sync.Mutex // serializes load→modify→save
+}
+
+// NewStore creates a Store backed by the JSON file at path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load returns the stored values for a routine. Returns an empty map if the
+// file or routine doesn't exist.
+func (s *Store) Load(routine string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readAll()
+	if err != nil {
+		return nil, err
+	}
+	vals := all[routine]
+	if vals == nil {
+		vals = make(map[string]string)
+	}
+	return vals, nil
+}
+
+// Update merges vals into the routine's stored values and writes the file
+// atomically via temp+rename.
+func (s *Store) Update(routine string, vals map[string]string) error {
+	if len(vals) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := s.readAll()
+	if err != nil {
+		return err
+	}
+	if all[routine] == nil {
+		all[routine] = make(map[string]string)
+	}
+	for k, v := range vals {
+		all[routine][k] = v
+	}
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling values: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating values directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "values-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("renaming values file: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) readAll() (map[string]map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]map[string]string), nil
+		}
+		return nil, fmt.Errorf("reading values file: %w", err)
+	}
+	var all map[string]map[string]string
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("parsing values file: %w", err)
+	}
+	if all == nil {
+		all = make(map[string]map[string]string)
+	}
+	return all, nil
+}
+
+// Extract reads a value from JSON data using a dot-separated path
+// (e.g. "totalRecords" or "results.0.title"). Numeric segments index into
+// arrays. Scalars are returned as strings; arrays and objects return their
+// length, so "results" yields the number of results. Returns false if the
+// data isn't JSON or the path doesn't exist.
+func Extract(data []byte, path string) (string, bool) {
+	var current interface{}
+	if err := json.Unmarshal(data, &current); err != nil {
+		return "", false
+	}
+
+	if path != "" {
+		for _, part := range strings.Split(path, ".") {
+			switch v := current.(type) {
+			case map[string]interface{}:
+				next, ok := v[part]
+				if !ok {
+					return "", false
+				}
+				current = next
+			case []interface{}:
+				idx, err := strconv.Atoi(part)
+				if err != nil || idx < 0 || idx >= len(v) {
+					return "", false
+				}
+				current = v[idx]
+			default:
+				return "", false
+			}
+		}
+	}
+
+	switch v := current.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case []interface{}:
+		return strconv.Itoa(len(v)), true
+	case map[string]interface{}:
+		return strconv.Itoa(len(v)), true
+	default:
+		return fmt.Sprintf("%v", v), true
+	}
+}
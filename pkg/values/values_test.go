@@ -0,0 +1,81 @@
+package values
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.json")
+	s := NewStore(path)
+
+	if err := s.Update("brief", map[string]string{"contract_count": "12"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Update("other", map[string]string{"x": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Update("brief", map[string]string{"top": "Alpha"}); err != nil {
+		t.Fatal(err)
+	}
+
+	vals, err := NewStore(path).Load("brief")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vals["contract_count"] != "12" || vals["top"] != "Alpha" {
+		t.Errorf("brief values = %v", vals)
+	}
+	if _, ok := vals["x"]; ok {
+		t.Error("values leaked across routines")
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, e := range entries {
+		if filepath.Ext(e.Name()) == ".tmp" {
+			t.Errorf("temp file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestStoreLoadMissing(t *testing.T) {
+	s := NewStore(filepath.Join(t.TempDir(), "missing.json"))
+	vals, err := s.Load("brief")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vals) != 0 {
+		t.Errorf("expected empty values, got %v", vals)
+	}
+}
+
+func TestExtract(t *testing.T) {
+	data := []byte(`{"totalRecords": 42, "ok": true, "results": [{"title": "A"}, {"title": "B"}], "meta": {"source": "x"}}`)
+
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"totalRecords", "42", true},
+		{"ok", "true", true},
+		{"results", "2", true},
+		{"results.1.title", "B", true},
+		{"meta.source", "x", true},
+		{"meta", "1", true},
+		{"missing", "", false},
+		{"results.5.title", "", false},
+		{"totalRecords.deeper", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Extract(data, tt.path)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("Extract(%q) = (%q, %v), want (%q, %v)", tt.path, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	if _, ok := Extract([]byte("not json"), "x"); ok {
+		t.Error("expected failure for non-JSON data")
+	}
+}